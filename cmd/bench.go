@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/opd-ai/go-jf-org/internal/scanner"
+	"github.com/opd-ai/go-jf-org/internal/util"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+var (
+	benchEnrich     bool
+	benchCPUProfile string
+	benchMemProfile string
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [directory]",
+	Short: "Measure scan, parse and enrich performance on a directory",
+	Long: `Bench runs the scan and parse phases (and the enrich phase with --enrich)
+over a directory and reports per-phase throughput, p50/p95 per-file latency
+and API cache hit rates. Use it to tune settings for a machine or NAS.
+
+Bench is read-only: it never moves, renames or writes media files.
+
+Examples:
+  go-jf-org bench /media/unsorted
+  go-jf-org bench /media/unsorted --enrich
+  go-jf-org bench /media/unsorted --cpuprofile cpu.pprof --memprofile mem.pprof`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBench,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.Flags().BoolVar(&benchEnrich, "enrich", false, "also benchmark metadata enrichment (makes API requests)")
+	benchCmd.Flags().StringVar(&benchCPUProfile, "cpuprofile", "", "write a pprof CPU profile to this file")
+	benchCmd.Flags().StringVar(&benchMemProfile, "memprofile", "", "write a pprof heap profile to this file")
+	addExtensionFlags(benchCmd)
+	addMaxDepthFlag(benchCmd)
+	addIOWorkersFlag(benchCmd)
+}
+
+// benchPhase is the measured result of one benchmark phase
+type benchPhase struct {
+	Name     string
+	Files    int
+	Duration time.Duration
+	// Latencies holds per-file durations; empty for whole-tree phases like scan
+	Latencies []time.Duration
+}
+
+// FilesPerSecond returns the phase throughput
+func (p benchPhase) FilesPerSecond() float64 {
+	if p.Duration <= 0 {
+		return 0
+	}
+	return float64(p.Files) / p.Duration.Seconds()
+}
+
+// benchCache is the hit/miss count of one API cache
+type benchCache struct {
+	Name   string
+	Hits   int64
+	Misses int64
+}
+
+// parsedFile is a scanned file's detected type and parsed metadata
+type parsedFile struct {
+	mediaType types.MediaType
+	metadata  *types.Metadata
+}
+
+// benchReport is the complete result of a benchmark run
+type benchReport struct {
+	Path   string
+	Phases []benchPhase
+	Caches []benchCache
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	absPath, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	s, err := createScanner()
+	if err != nil {
+		return err
+	}
+
+	if benchCPUProfile != "" {
+		f, err := os.Create(benchCPUProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	var enrichers *enricherSet
+	if benchEnrich {
+		set, err := setupEnrichers()
+		if err != nil {
+			return err
+		}
+		enrichers = &set
+	}
+
+	report, err := runBenchmark(absPath, s, enrichers)
+	if err != nil {
+		return err
+	}
+
+	if benchMemProfile != "" {
+		if err := writeHeapProfile(benchMemProfile); err != nil {
+			return err
+		}
+	}
+
+	printBenchReport(os.Stdout, report)
+	return nil
+}
+
+// runBenchmark scans path, parses every file found and optionally enriches
+// it, timing each phase. Nothing on disk is modified.
+func runBenchmark(path string, s *scanner.Scanner, enrichers *enricherSet) (*benchReport, error) {
+	stats := util.NewStatistics()
+	report := &benchReport{Path: path}
+
+	scanTimer := stats.NewTimer("scan")
+	result, err := s.Scan(path)
+	scanTimer.Stop()
+	if err != nil {
+		return nil, fmt.Errorf("scan failed: %w", err)
+	}
+	report.Phases = append(report.Phases, benchPhase{
+		Name:     "scan",
+		Files:    len(result.Files),
+		Duration: stats.GetTiming("scan"),
+	})
+
+	parse := benchPhase{Name: "parse"}
+	parsed := make([]parsedFile, 0, len(result.Files))
+	for _, file := range result.Files {
+		start := time.Now()
+		mediaType := s.GetMediaType(file)
+		metadata, err := s.GetMetadata(file)
+		elapsed := time.Since(start)
+
+		stats.AddTiming("parse", elapsed)
+		parse.Latencies = append(parse.Latencies, elapsed)
+		if err != nil {
+			log.Debug().Err(err).Str("file", file).Msg("Failed to parse metadata")
+			stats.Increment("parse_errors")
+			continue
+		}
+		parse.Files++
+		parsed = append(parsed, parsedFile{mediaType: mediaType, metadata: metadata})
+	}
+	parse.Duration = stats.GetTiming("parse")
+	report.Phases = append(report.Phases, parse)
+
+	if enrichers == nil {
+		return report, nil
+	}
+
+	enrich := benchPhase{Name: "enrich"}
+	for _, f := range parsed {
+		if f.metadata == nil {
+			continue
+		}
+		start := time.Now()
+		ok, err := enrichers.enrich(context.Background(), f.mediaType, f.metadata)
+		elapsed := time.Since(start)
+		if !ok {
+			continue
+		}
+
+		stats.AddTiming("enrich", elapsed)
+		enrich.Files++
+		enrich.Latencies = append(enrich.Latencies, elapsed)
+		if err != nil {
+			log.Debug().Err(err).Msg("Failed to enrich metadata")
+		}
+	}
+	enrich.Duration = stats.GetTiming("enrich")
+	report.Phases = append(report.Phases, enrich)
+
+	names := make([]string, 0, len(enrichers.cacheStats))
+	for name := range enrichers.cacheStats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hits, misses := enrichers.cacheStats[name]()
+		report.Caches = append(report.Caches, benchCache{Name: name, Hits: hits, Misses: misses})
+	}
+
+	return report, nil
+}
+
+// writeHeapProfile writes a pprof heap profile after forcing a GC so the
+// profile reflects live memory
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create memory profile: %w", err)
+	}
+	defer f.Close()
+
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("failed to write memory profile: %w", err)
+	}
+	return nil
+}
+
+// printBenchReport writes the benchmark results as a table
+func printBenchReport(out io.Writer, report *benchReport) {
+	fmt.Fprintf(out, "Benchmark: %s\n", report.Path)
+	fmt.Fprintln(out, "==========")
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PHASE\tFILES\tDURATION\tFILES/SEC\tP50\tP95")
+	for _, p := range report.Phases {
+		p50, p95 := "-", "-"
+		if len(p.Latencies) > 0 {
+			p50 = util.Percentile(p.Latencies, 50).Round(time.Microsecond).String()
+			p95 = util.Percentile(p.Latencies, 95).Round(time.Microsecond).String()
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%.1f\t%s\t%s\n", p.Name, p.Files, p.Duration.Round(time.Microsecond), p.FilesPerSecond(), p50, p95)
+	}
+	w.Flush()
+
+	if len(report.Caches) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "API cache:")
+		for _, c := range report.Caches {
+			rate := 0.0
+			if total := c.Hits + c.Misses; total > 0 {
+				rate = float64(c.Hits) / float64(total) * 100
+			}
+			fmt.Fprintf(out, "  %s: %d hits, %d misses (%.1f%% hit rate)\n", c.Name, c.Hits, c.Misses, rate)
+		}
+	}
+}
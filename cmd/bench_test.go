@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/config"
+)
+
+func TestRunBenchmark_TimingOutput(t *testing.T) {
+	oldCfg := cfg
+	cfg = config.DefaultConfig()
+	defer func() { cfg = oldCfg }()
+
+	tmpDir := t.TempDir()
+	names := []string{"The.Matrix.1999.1080p.mkv", "Breaking.Bad.S01E01.720p.mkv", "Inception.2010.mp4"}
+	for _, name := range names {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Truncate(path, minFileSize); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err := createScanner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := runBenchmark(tmpDir, s, nil)
+	if err != nil {
+		t.Fatalf("runBenchmark() error = %v", err)
+	}
+
+	if len(report.Phases) != 2 {
+		t.Fatalf("phases = %d, want scan and parse", len(report.Phases))
+	}
+	parse := report.Phases[1]
+	if parse.Files != len(names) || len(parse.Latencies) != len(names) {
+		t.Errorf("parse phase files = %d, latencies = %d, want %d", parse.Files, len(parse.Latencies), len(names))
+	}
+
+	var out bytes.Buffer
+	printBenchReport(&out, report)
+	for _, want := range []string{"PHASE", "FILES/SEC", "P50", "P95", "scan", "parse"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// Bench is read-only: every file is still where it was
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(tmpDir, name)); err != nil {
+			t.Errorf("bench modified %s: %v", name, err)
+		}
+	}
+}
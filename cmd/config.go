@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+
+	"github.com/opd-ai/go-jf-org/internal/config"
 )
 
 var configCmd = &cobra.Command{
@@ -25,7 +28,8 @@ var configInitCmd = &cobra.Command{
 
 This command will:
   - Create the ~/.go-jf-org directory if it doesn't exist
-  - Generate a config.yaml file with sensible defaults
+  - Generate a config.yaml listing every setting with its default value
+    and an explanatory comment
   - Create cache and transaction log directories
 
 If a configuration file already exists, it will not be overwritten
@@ -33,13 +37,23 @@ unless the --force flag is used.`,
 	RunE: runConfigInit,
 }
 
+// initCmd is a top-level shortcut for "config init" so new users find it
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create default configuration file (same as 'config init')",
+	Long:  configInitCmd.Long,
+	RunE:  runConfigInit,
+}
+
 var configInitForce bool
 
 func init() {
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(initCmd)
 	configCmd.AddCommand(configInitCmd)
 
 	configInitCmd.Flags().BoolVarP(&configInitForce, "force", "f", false, "overwrite existing configuration file")
+	initCmd.Flags().BoolVarP(&configInitForce, "force", "f", false, "overwrite existing configuration file")
 }
 
 func runConfigInit(cmd *cobra.Command, args []string) error {
@@ -78,41 +92,14 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create transaction directory: %w", err)
 	}
 
-	// Default configuration content
-	configContent := `# go-jf-org Configuration File
-# Automatically generated by 'go-jf-org config init'
-
-# Source directories to scan for media files
-sources:
-  - /media/unsorted
-
-# Destination directories for organized media
-destinations:
-  movies: /media/jellyfin/movies
-  tv: /media/jellyfin/tv
-  music: /media/jellyfin/music
-  books: /media/jellyfin/books
-
-# API keys for metadata enrichment (optional - uses free tier if not provided)
-api_keys:
-  tmdb: ""  # Get from https://www.themoviedb.org/settings/api
-
-# Organization options
-organize:
-  create_nfo: true           # Generate Jellyfin-compatible NFO files
-  download_artwork: true     # Download posters, fanart, and album covers
-  normalize_names: true      # Normalize file and directory names
-
-# Safety options
-safety:
-  dry_run: false                    # Preview mode - don't actually move files
-  transaction_log: true             # Log all operations for rollback support
-  conflict_resolution: skip         # How to handle conflicts: skip | rename | interactive
-`
-
-	// Write configuration file
+	// Write a fully commented configuration with every default
+	var buf bytes.Buffer
+	if err := config.WriteTemplate(&buf, config.DefaultConfig()); err != nil {
+		return err
+	}
+
 	log.Info().Str("file", configFile).Msg("Writing configuration file")
-	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+	if err := os.WriteFile(configFile, buf.Bytes(), 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/config"
 )
 
 func TestConfigInit(t *testing.T) {
@@ -71,6 +73,11 @@ func TestConfigInit(t *testing.T) {
 						t.Errorf("Config file missing section: %s", section)
 					}
 				}
+
+				// The generated file must load cleanly
+				if _, err := config.Load(configFile); err != nil {
+					t.Errorf("generated config failed to load: %v", err)
+				}
 			},
 		},
 		{
@@ -146,3 +153,16 @@ func TestConfigInit(t *testing.T) {
 		})
 	}
 }
+
+func TestInitCommandRegistered(t *testing.T) {
+	found, _, err := rootCmd.Find([]string{"init"})
+	if err != nil {
+		t.Fatalf("rootCmd.Find(init) error = %v", err)
+	}
+	if found != initCmd {
+		t.Errorf("init resolved to %q, want the top-level init command", found.Name())
+	}
+	if found.Flags().Lookup("force") == nil {
+		t.Error("init command is missing --force")
+	}
+}
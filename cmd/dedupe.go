@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/opd-ai/go-jf-org/internal/safety"
+	"github.com/opd-ai/go-jf-org/internal/util"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// dedupeCmd finds byte-identical files in a library and can hardlink them
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe [library-root]",
+	Short: "Find byte-identical files in a library and hardlink them",
+	Long: `Dedupe walks a library, groups files by size and then by SHA-256, and
+lists every set of byte-identical files, such as the same movie filed under
+two collections. Files that are already hardlinks of each other count once.
+
+With --hardlink each copy is replaced by a hardlink to the first file of its
+set, so they share one inode and the space is freed. Only files on the same
+filesystem are linked. The changes are recorded as a transaction; rolling it
+back gives every linked path its own copy again.
+
+Examples:
+  # Report identical files and the space they waste
+  go-jf-org dedupe /media/jellyfin
+
+  # Replace the copies with hardlinks
+  go-jf-org dedupe /media/jellyfin --hardlink`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDedupe,
+}
+
+var dedupeHardlink bool
+
+func init() {
+	rootCmd.AddCommand(dedupeCmd)
+
+	dedupeCmd.Flags().BoolVar(&dedupeHardlink, "hardlink", false, "Replace identical copies with hardlinks to one file (recorded for rollback)")
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	root, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil {
+		return fmt.Errorf("cannot access path: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", root)
+	}
+
+	sets, err := safety.FindIdenticalFiles(root)
+	if err != nil {
+		return err
+	}
+	if len(sets) == 0 {
+		fmt.Println("No identical files found")
+		return nil
+	}
+
+	var reclaimable int64
+	for _, set := range sets {
+		fmt.Printf("%s each, %d copies:\n", util.FormatBytes(set.Size), len(set.Paths))
+		for _, p := range set.Paths {
+			fmt.Printf("  %s\n", p)
+		}
+		reclaimable += set.Reclaimable()
+	}
+	fmt.Printf("\n%d set(s) of identical files, %s reclaimable\n", len(sets), util.FormatBytes(reclaimable))
+
+	if !dedupeHardlink {
+		fmt.Println("Run again with --hardlink to replace the copies with hardlinks")
+		return nil
+	}
+
+	logDir, err := safety.GetDefaultLogDir()
+	if err != nil {
+		return fmt.Errorf("failed to get transaction log directory: %w", err)
+	}
+	tm, err := safety.NewTransactionManager(logDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize transaction manager: %w", err)
+	}
+	if err := setCommitBatch(tm); err != nil {
+		return err
+	}
+
+	txnID, ops, err := tm.Hardlink(sets)
+	if err != nil {
+		return fmt.Errorf("failed to hardlink files: %w", err)
+	}
+
+	failed := 0
+	var saved int64
+	sizes := make(map[string]int64)
+	for _, set := range sets {
+		for _, p := range set.Paths {
+			sizes[p] = set.Size
+		}
+	}
+	for _, op := range ops {
+		if op.Status == types.OperationStatusFailed {
+			failed++
+			fmt.Printf("✗ %s\n    Error: %v\n", op.Destination, op.Error)
+			continue
+		}
+		saved += sizes[op.Destination]
+	}
+
+	fmt.Printf("\nLinked %d file(s), %d failed, %s freed\n", len(ops)-failed, failed, util.FormatBytes(saved))
+	if len(ops) > 0 {
+		fmt.Printf("To undo, run: go-jf-org rollback %s\n", txnID)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d file(s) could not be hardlinked", failed)
+	}
+	return nil
+}
@@ -2,12 +2,30 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/opd-ai/go-jf-org/internal/artwork"
+	"github.com/opd-ai/go-jf-org/internal/config"
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+	"github.com/opd-ai/go-jf-org/internal/jellyfin"
+	"github.com/opd-ai/go-jf-org/internal/notify"
+	"github.com/opd-ai/go-jf-org/internal/organizer"
+	"github.com/opd-ai/go-jf-org/internal/safety"
 	"github.com/opd-ai/go-jf-org/internal/scanner"
+	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
@@ -32,6 +50,23 @@ func getDestinationRoot(mediaType string, dest string) (string, error) {
 	return "", fmt.Errorf("destination directory required (use --dest or configure in config file)")
 }
 
+// configuredDestinations returns the config's library root for each media
+// type that has one, used to route a mixed folder file by file
+func configuredDestinations() map[types.MediaType]string {
+	destinations := make(map[types.MediaType]string)
+	for mediaType, dest := range map[types.MediaType]string{
+		types.MediaTypeMovie: cfg.Destinations.Movies,
+		types.MediaTypeTV:    cfg.Destinations.TV,
+		types.MediaTypeMusic: cfg.Destinations.Music,
+		types.MediaTypeBook:  cfg.Destinations.Books,
+	} {
+		if dest != "" {
+			destinations[mediaType] = dest
+		}
+	}
+	return destinations
+}
+
 // parseMediaTypeFilter converts a string media type to a MediaType enum
 func parseMediaTypeFilter(mediaType string) (types.MediaType, error) {
 	if mediaType == "" {
@@ -52,17 +87,810 @@ func parseMediaTypeFilter(mediaType string) (types.MediaType, error) {
 	}
 }
 
+// resolveMovieLayout determines the movie layout from the --flatten flag or config
+func resolveMovieLayout(flatten bool) (jellyfin.MovieLayout, error) {
+	if flatten {
+		return jellyfin.MovieLayoutFlat, nil
+	}
+
+	switch layout := jellyfin.MovieLayout(cfg.Organize.MovieLayout); layout {
+	case "", jellyfin.MovieLayoutFolder:
+		return jellyfin.MovieLayoutFolder, nil
+	case jellyfin.MovieLayoutFlat:
+		return jellyfin.MovieLayoutFlat, nil
+	default:
+		return "", fmt.Errorf("invalid movie layout: %s (must be folder or flat)", layout)
+	}
+}
+
+// resolveMiniSeriesLayout validates the configured mini-series layout
+func resolveMiniSeriesLayout() (jellyfin.MiniSeriesLayout, error) {
+	switch layout := jellyfin.MiniSeriesLayout(cfg.Organize.MiniSeriesLayout); layout {
+	case "", jellyfin.MiniSeriesLayoutSeason:
+		return jellyfin.MiniSeriesLayoutSeason, nil
+	case jellyfin.MiniSeriesLayoutShow:
+		return jellyfin.MiniSeriesLayoutShow, nil
+	default:
+		return "", fmt.Errorf("invalid miniseries layout: %s (must be season or show)", layout)
+	}
+}
+
+// resolveDestStructure determines the library layout from the
+// --dest-structure flag or config
+func resolveDestStructure(flag string) (jellyfin.DestStructure, error) {
+	value := flag
+	if value == "" {
+		value = cfg.Organize.DestStructure
+	}
+	switch structure := jellyfin.DestStructure(value); structure {
+	case "", jellyfin.StructureNested:
+		return jellyfin.StructureNested, nil
+	case jellyfin.StructureFlatByType:
+		return structure, nil
+	default:
+		return "", fmt.Errorf("invalid dest structure: %s (must be nested or flat-by-type)", structure)
+	}
+}
+
+// resolveMusicLayout validates the configured music layout
+func resolveMusicLayout() (jellyfin.MusicLayout, error) {
+	switch layout := jellyfin.MusicLayout(cfg.Organize.MusicLayout); layout {
+	case "", jellyfin.MusicLayoutArtist:
+		return jellyfin.MusicLayoutArtist, nil
+	case jellyfin.MusicLayoutDecadeArtist:
+		return jellyfin.MusicLayoutDecadeArtist, nil
+	default:
+		return "", fmt.Errorf("invalid music layout: %s (must be artist or decade-artist)", layout)
+	}
+}
+
+// parseErrorPolicy validates the --on-error value
+func parseErrorPolicy(value string) (organizer.ErrorPolicy, error) {
+	switch policy := organizer.ErrorPolicy(value); policy {
+	case "", organizer.ErrorPolicyContinue:
+		return organizer.ErrorPolicyContinue, nil
+	case organizer.ErrorPolicyStop, organizer.ErrorPolicyRollback:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid --on-error policy: %s (must be continue, stop or rollback)", value)
+	}
+}
+
+// resolveSidecarNFOPolicy validates the configured policy for NFOs moved by
+// --copy-sidecar-nfo
+func resolveSidecarNFOPolicy() (organizer.SidecarNFOPolicy, error) {
+	switch policy := organizer.SidecarNFOPolicy(cfg.Organize.SidecarNFOPolicy); policy {
+	case "", organizer.SidecarNFOMerge:
+		return organizer.SidecarNFOMerge, nil
+	case organizer.SidecarNFOReplace:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid sidecar_nfo_policy: %s (must be merge or replace)", policy)
+	}
+}
+
+// resolveNFOTypes returns the media types that get NFOs: organize.create_nfo_for
+// minus any --no-nfo-for-type exclusions
+func resolveNFOTypes(excluded []string) ([]types.MediaType, error) {
+	skip := make(map[types.MediaType]bool, len(excluded))
+	for _, name := range excluded {
+		mediaType, err := parseMediaTypeFilter(strings.TrimSpace(name))
+		if err != nil || mediaType == types.MediaTypeUnknown {
+			return nil, fmt.Errorf("invalid --no-nfo-for-type: %q (must be movie, tv, music or book)", name)
+		}
+		skip[mediaType] = true
+	}
+
+	enabled := make([]types.MediaType, 0, len(cfg.Organize.CreateNFOFor))
+	for _, name := range cfg.Organize.CreateNFOFor {
+		mediaType, err := parseMediaTypeFilter(strings.TrimSpace(name))
+		if err != nil || mediaType == types.MediaTypeUnknown {
+			return nil, fmt.Errorf("invalid create_nfo_for entry: %q (must be movie, tv, music or book)", name)
+		}
+		if !skip[mediaType] {
+			enabled = append(enabled, mediaType)
+		}
+	}
+	return enabled, nil
+}
+
+// resolveExtrasLayout validates the configured placement of suffixed movie extras
+func resolveExtrasLayout() (jellyfin.ExtrasLayout, error) {
+	switch layout := jellyfin.ExtrasLayout(cfg.Organize.ExtrasLayout); layout {
+	case "", jellyfin.ExtrasLayoutSuffix:
+		return jellyfin.ExtrasLayoutSuffix, nil
+	case jellyfin.ExtrasLayoutFolder:
+		return layout, nil
+	default:
+		return "", fmt.Errorf("invalid extras_layout: %s (must be suffix or folder)", layout)
+	}
+}
+
+// resolveShardTypes returns the media types sharded into first-letter
+// buckets: those given to --group-by-first-letter, or else
+// organize.group_by_first_letter
+func resolveShardTypes(flag []string) ([]types.MediaType, error) {
+	names := cfg.Organize.GroupByFirstLetter
+	if len(flag) > 0 {
+		names = flag
+	}
+
+	shards := make([]types.MediaType, 0, len(names))
+	for _, name := range names {
+		mediaType, err := parseMediaTypeFilter(strings.TrimSpace(name))
+		if err != nil || mediaType == types.MediaTypeUnknown {
+			return nil, fmt.Errorf("invalid group_by_first_letter entry: %q (must be movie, tv, music or book)", name)
+		}
+		shards = append(shards, mediaType)
+	}
+	return shards, nil
+}
+
+// resolveLooseTrackLayout validates the configured layout for tracks without an album
+func resolveLooseTrackLayout() (jellyfin.LooseTrackLayout, error) {
+	switch layout := jellyfin.LooseTrackLayout(cfg.Organize.LooseTrackLayout); layout {
+	case "", jellyfin.LooseTrackUnknownAlbum:
+		return jellyfin.LooseTrackUnknownAlbum, nil
+	case jellyfin.LooseTrackSingles, jellyfin.LooseTrackCompilations:
+		return layout, nil
+	default:
+		return "", fmt.Errorf("invalid loose_track_layout: %s (must be unknown-album, singles or compilations)", layout)
+	}
+}
+
+// resolveUnmappedMode validates the configured handling of characters that
+// ASCII folding cannot transliterate
+func resolveUnmappedMode() (jellyfin.UnmappedMode, error) {
+	switch mode := jellyfin.UnmappedMode(cfg.Naming.ASCIIFoldUnmapped); mode {
+	case "", jellyfin.UnmappedKeep:
+		return jellyfin.UnmappedKeep, nil
+	case jellyfin.UnmappedStrip:
+		return jellyfin.UnmappedStrip, nil
+	default:
+		return "", fmt.Errorf("invalid ascii_fold_unmapped: %s (must be keep or strip)", mode)
+	}
+}
+
+// resolveUnknownYearPolicy validates the configured handling of movies,
+// albums and books without a year
+func resolveUnknownYearPolicy() (jellyfin.UnknownYearPolicy, error) {
+	switch policy := jellyfin.UnknownYearPolicy(cfg.Naming.UnknownYear); policy {
+	case "", jellyfin.UnknownYearOmit:
+		return jellyfin.UnknownYearOmit, nil
+	case jellyfin.UnknownYearPlaceholder, jellyfin.UnknownYearQuarantine:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid unknown_year: %s (must be omit, placeholder or quarantine)", policy)
+	}
+}
+
+// resolveFilenameStyle validates the configured separator and case of
+// generated filenames
+func resolveFilenameStyle() (jellyfin.FilenameStyle, error) {
+	var style jellyfin.FilenameStyle
+	switch sep := jellyfin.Separator(cfg.Naming.Separator); sep {
+	case "", jellyfin.SeparatorSpaceDash:
+		style.Separator = jellyfin.SeparatorSpaceDash
+	case jellyfin.SeparatorDot, jellyfin.SeparatorUnderscore:
+		style.Separator = sep
+	default:
+		return style, fmt.Errorf("invalid naming separator: %s (must be space-dash, dot or underscore)", sep)
+	}
+	switch nameCase := jellyfin.NameCase(cfg.Naming.Case); nameCase {
+	case "", jellyfin.CasePreserve:
+		style.Case = jellyfin.CasePreserve
+	case jellyfin.CaseTitle, jellyfin.CaseLower:
+		style.Case = nameCase
+	default:
+		return style, fmt.Errorf("invalid naming case: %s (must be preserve, title or lower)", nameCase)
+	}
+	return style, nil
+}
+
+// resolveArtworkFormat validates the configured artwork storage format
+func resolveArtworkFormat() (artwork.Format, error) {
+	switch format := artwork.Format(cfg.Artwork.Format); format {
+	case "", artwork.FormatJPEG:
+		return artwork.FormatJPEG, nil
+	case artwork.FormatOriginal:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid artwork format: %s (must be jpeg or original)", format)
+	}
+}
+
+// resolveArtworkSources validates the configured movie poster source order.
+// fanart.tv and OMDb sources without an API key are dropped with a warning.
+func resolveArtworkSources() ([]artwork.Source, artwork.SourceKeys, error) {
+	keys := artwork.SourceKeys{
+		Fanart: cfg.APIKeys.Fanart,
+		OMDb:   cfg.APIKeys.OMDb,
+	}
+
+	var sources []artwork.Source
+	for _, name := range cfg.Artwork.Sources {
+		source := artwork.Source(strings.ToLower(strings.TrimSpace(name)))
+		switch source {
+		case artwork.SourceTMDB:
+		case artwork.SourceFanart:
+			if keys.Fanart == "" {
+				log.Warn().Msg("artwork.sources lists fanart but api_keys.fanart is not set; skipping it")
+				continue
+			}
+		case artwork.SourceOMDb:
+			if keys.OMDb == "" {
+				log.Warn().Msg("artwork.sources lists omdb but api_keys.omdb is not set; skipping it")
+				continue
+			}
+		default:
+			return nil, keys, fmt.Errorf("invalid artwork source: %s (must be tmdb, fanart or omdb)", name)
+		}
+		sources = append(sources, source)
+	}
+	return sources, keys, nil
+}
+
+// resolveHashAlgorithm validates the configured integrity hash algorithm
+func resolveHashAlgorithm() (safety.HashAlgorithm, error) {
+	algorithm, err := safety.ParseHashAlgorithm(cfg.Integrity.Algorithm)
+	if err != nil {
+		return "", fmt.Errorf("invalid integrity.algorithm: %w", err)
+	}
+	return algorithm, nil
+}
+
+// resolveCopyBufferSize returns the configured cross-filesystem copy buffer size
+func resolveCopyBufferSize() (int, error) {
+	size, err := config.ParseSize(cfg.Performance.CopyBufferSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid copy_buffer_size: %w", err)
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("invalid copy_buffer_size: %s (must be greater than zero)", cfg.Performance.CopyBufferSize)
+	}
+	return int(size), nil
+}
+
+// resolveThrottle returns the configured operation and byte rate limits
+func resolveThrottle() (int, int64, error) {
+	if cfg.Performance.MaxOpsPerSec < 0 {
+		return 0, 0, fmt.Errorf("invalid max_ops_per_sec: %d (must be zero or more)", cfg.Performance.MaxOpsPerSec)
+	}
+	bytesPerSec, err := config.ParseSize(cfg.Performance.MaxBytesPerSec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid max_bytes_per_sec: %w", err)
+	}
+	return cfg.Performance.MaxOpsPerSec, bytesPerSec, nil
+}
+
+// resolveEnrichTimeout returns the configured per-file enrichment timeout
+func resolveEnrichTimeout() (time.Duration, error) {
+	timeout, err := time.ParseDuration(cfg.Enrich.PerItemTimeout)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid per_item_timeout: %q (must be a duration such as 60s)", cfg.Enrich.PerItemTimeout)
+	}
+	return timeout, nil
+}
+
+// startStatsFlusher keeps partial statistics in path (or
+// performance.stats_file) during a run, so a run that dies still leaves
+// them. The returned stop writes the final report, once stats is finished;
+// it does nothing when no file is set.
+func startStatsFlusher(stats *util.Statistics, path string) (func(), error) {
+	if path == "" {
+		path = cfg.Performance.StatsFile
+	}
+	if path == "" {
+		return func() {}, nil
+	}
+	interval, err := time.ParseDuration(cfg.Performance.StatsFlushInterval)
+	if err != nil || interval < 0 {
+		return nil, fmt.Errorf("invalid stats_flush_interval: %q (must be a duration such as 30s)", cfg.Performance.StatsFlushInterval)
+	}
+	if cfg.Performance.StatsFlushEvery < 0 {
+		return nil, fmt.Errorf("invalid stats_flush_every: %d (must be zero or more)", cfg.Performance.StatsFlushEvery)
+	}
+
+	flusher := stats.StartFlusher(path, interval, cfg.Performance.StatsFlushEvery)
+	return func() {
+		if err := flusher.Stop(); err != nil {
+			log.Warn().Err(err).Str("file", path).Msg("Failed to write statistics file")
+		}
+	}, nil
+}
+
+// setCommitBatch applies safety.transaction_batch_size and
+// safety.transaction_batch_interval to tm
+func setCommitBatch(tm *safety.TransactionManager) error {
+	interval, err := time.ParseDuration(cfg.Safety.TransactionBatchInterval)
+	if err != nil || interval < 0 {
+		return fmt.Errorf("invalid transaction_batch_interval: %q (must be a duration such as 500ms)", cfg.Safety.TransactionBatchInterval)
+	}
+	if cfg.Safety.TransactionBatchSize < 0 {
+		return fmt.Errorf("invalid transaction_batch_size: %d (must be zero or more)", cfg.Safety.TransactionBatchSize)
+	}
+	tm.SetCommitBatch(cfg.Safety.TransactionBatchSize, interval)
+	return nil
+}
+
+// hookRun describes a finished organize run to --after-hook
+type hookRun struct {
+	TransactionID string
+	Organized     int
+	Failed        int
+	Skipped       int
+	DestRoots     []string
+}
+
+// runAfterHook runs command through the shell (sh -c, or cmd /C on
+// Windows) with the run described in GO_JF_ORG_* environment variables:
+// TRANSACTION_ID (empty with --no-transaction), ORGANIZED, FAILED, SKIPPED,
+// DEST_ROOT (the first destination root) and DEST_ROOTS (all of them,
+// joined like PATH). A failing hook is returned as an error for the caller
+// to report; the organization itself stands.
+func runAfterHook(command string, run hookRun, stdout, stderr io.Writer) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	destRoot := ""
+	if len(run.DestRoots) > 0 {
+		destRoot = run.DestRoots[0]
+	}
+
+	hook := exec.Command(shell, flag, command)
+	hook.Stdout = stdout
+	hook.Stderr = stderr
+	hook.Env = append(os.Environ(),
+		"GO_JF_ORG_TRANSACTION_ID="+run.TransactionID,
+		"GO_JF_ORG_ORGANIZED="+strconv.Itoa(run.Organized),
+		"GO_JF_ORG_FAILED="+strconv.Itoa(run.Failed),
+		"GO_JF_ORG_SKIPPED="+strconv.Itoa(run.Skipped),
+		"GO_JF_ORG_DEST_ROOT="+destRoot,
+		"GO_JF_ORG_DEST_ROOTS="+strings.Join(run.DestRoots, string(os.PathListSeparator)),
+	)
+	if err := hook.Run(); err != nil {
+		return fmt.Errorf("after hook %q failed: %w", command, err)
+	}
+	return nil
+}
+
+// runAfterHookOnSuccess runs command as runAfterHook does, but only after a
+// real run in which no file failed, like --clean-sources; ran reports
+// whether the hook was started
+func runAfterHookOnSuccess(command string, dryRun bool, run hookRun, stdout, stderr io.Writer) (ran bool, err error) {
+	if command == "" || dryRun || run.Failed > 0 {
+		return false, nil
+	}
+	return true, runAfterHook(command, run, stdout, stderr)
+}
+
+// sendRunNotification sends a finished run to the targets in
+// notifications.*, as a success or, when err is set or files failed, a
+// failure; notifications.on_success and on_failure choose which are sent.
+// Nothing is sent with --offline.
+func sendRunNotification(run *notify.Summary, err error, elapsed time.Duration) {
+	settings := cfg.Notifications
+	notifiers := notify.New(notify.Config{
+		WebhookURL:        settings.WebhookURL,
+		DiscordWebhookURL: settings.DiscordWebhookURL,
+		NtfyURL:           settings.NtfyURL,
+		NtfyToken:         settings.NtfyToken,
+	})
+	if len(notifiers) == 0 {
+		return
+	}
+	if offline {
+		log.Debug().Int("targets", len(notifiers)).Msg("Offline mode: skipping run notification")
+		return
+	}
+
+	run.DurationMS = elapsed.Milliseconds()
+	if err != nil {
+		run.Error = err.Error()
+	}
+	run.Success = err == nil && run.Failed == 0
+	if (run.Success && !settings.OnSuccess) || (!run.Success && !settings.OnFailure) {
+		return
+	}
+	notify.Send(context.Background(), notifiers, *run)
+}
+
+// copyProgressReporter returns a callback that shows a progress bar while a
+// file is copied across filesystems, in MiB
+func copyProgressReporter() safety.CopyProgressFunc {
+	var (
+		tracker *util.ProgressTracker
+		current string
+		shown   int
+	)
+	return func(path string, written, total int64) {
+		if path != current {
+			current, shown = path, 0
+			tracker = util.NewProgressTracker(int(total>>20), "Copying "+filepath.Base(path)+" (MiB)")
+		}
+		if mib := int(written >> 20); mib > shown {
+			tracker.Add(mib - shown)
+			shown = mib
+		}
+		if written >= total {
+			tracker.Finish()
+			current = ""
+		}
+	}
+}
+
+// resolveSampleFilter returns the configured suspected-sample size threshold
+// and whether suspected samples should be skipped rather than warned about
+func resolveSampleFilter() (int64, bool, error) {
+	maxSize := int64(0)
+	if cfg.Filters.SampleMaxSize != "" {
+		size, err := config.ParseSize(cfg.Filters.SampleMaxSize)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid sample_max_size: %w", err)
+		}
+		maxSize = size
+	}
+
+	switch cfg.Filters.SampleAction {
+	case "", "warn":
+		return maxSize, false, nil
+	case "skip":
+		return maxSize, true, nil
+	default:
+		return 0, false, fmt.Errorf("invalid sample_action: %s (must be warn or skip)", cfg.Filters.SampleAction)
+	}
+}
+
+// resolveCleanup validates the source cleanup mode and returns it with the
+// trash directory, defaulting to ~/.go-jf-org/trash
+func resolveCleanup() (safety.CleanupMode, string, error) {
+	var mode safety.CleanupMode
+	switch m := safety.CleanupMode(cfg.Cleanup.Mode); m {
+	case "", safety.CleanupTrash:
+		mode = safety.CleanupTrash
+	case safety.CleanupRemove:
+		mode = m
+	default:
+		return "", "", fmt.Errorf("invalid cleanup mode: %s (must be trash or remove)", m)
+	}
+
+	trashDir := cfg.Cleanup.TrashDir
+	if trashDir == "" {
+		dir, err := safety.GetDefaultTrashDir()
+		if err != nil {
+			return "", "", err
+		}
+		trashDir = dir
+	}
+	return mode, trashDir, nil
+}
+
+// sortArticles returns the configured articles to move to the end of folder
+// names, or nil when article sorting is disabled
+func sortArticles() []string {
+	if !cfg.Naming.SortArticles {
+		return nil
+	}
+	return cfg.Naming.Articles
+}
+
 // Minimum file size for scanning (10MB)
 const minFileSize = 10 * 1024 * 1024
 
+// Extension overrides from --video-ext/--audio-ext/--book-ext; when set they
+// replace the corresponding config list for this invocation
+var (
+	videoExtFlags []string
+	audioExtFlags []string
+	bookExtFlags  []string
+)
+
+// addExtensionFlags registers the extension override flags on a command
+func addExtensionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&videoExtFlags, "video-ext", nil, "video extensions to process instead of config (repeatable, e.g. --video-ext mkv)")
+	cmd.Flags().StringSliceVar(&audioExtFlags, "audio-ext", nil, "audio extensions to process instead of config (repeatable)")
+	cmd.Flags().StringSliceVar(&bookExtFlags, "book-ext", nil, "book extensions to process instead of config (repeatable)")
+}
+
+// Directory depth limit from --max-depth (-1 = unlimited)
+var scanMaxDepth int
+
+// addMaxDepthFlag registers the --max-depth flag on a command
+func addMaxDepthFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&scanMaxDepth, "max-depth", -1, "only descend this many directories below the source (0 = direct children only, -1 = unlimited)")
+}
+
+// Whether --check-readable probes each file's head and tail
+var checkReadable bool
+
+// addCheckReadableFlag registers the --check-readable flag on a command
+func addCheckReadableFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&checkReadable, "check-readable", false, "read the first and last few KB of each file and skip unreadable or truncated ones")
+}
+
+// printSkippedFiles reports files the scan left out as broken
+func printSkippedFiles(skipped []scanner.SkippedFile) {
+	if len(skipped) == 0 {
+		return
+	}
+	fmt.Printf("⚠ Skipped %d broken file(s):\n", len(skipped))
+	for _, f := range skipped {
+		fmt.Printf("  %s: %s\n", f.Path, f.Reason)
+	}
+}
+
+// printArchives reports archive sets the scan found but did not organize
+func printArchives(sets []scanner.ArchiveSet) {
+	if len(sets) == 0 {
+		return
+	}
+	fmt.Printf("⚠ Skipped %d archive set(s); extract them first or organize with --extract:\n", len(sets))
+	for _, warning := range archiveWarnings(sets) {
+		fmt.Printf("  %s\n", warning)
+	}
+}
+
+// archiveWarnings describes archive sets left unextracted, one per warning
+func archiveWarnings(sets []scanner.ArchiveSet) []string {
+	warnings := make([]string, 0, len(sets))
+	for _, set := range sets {
+		warnings = append(warnings, set.Warning())
+	}
+	return warnings
+}
+
+// extractArchives extracts each archive set next to its volumes with
+// command (archives.extract_command), returning how many were extracted and
+// the sets that could not be
+func extractArchives(sets []scanner.ArchiveSet, command []string) (int, []scanner.ArchiveSet) {
+	extracted := 0
+	var failed []scanner.ArchiveSet
+	for _, set := range sets {
+		if fsys.IsSFTPURL(set.Dir) {
+			log.Warn().Str("archive", set.First()).Msg("Archives on sftp:// sources cannot be extracted; extract them on the server")
+			failed = append(failed, set)
+			continue
+		}
+		if err := set.Extract(command); err != nil {
+			log.Warn().Err(err).Str("archive", set.First()).Msg("Failed to extract archive")
+			failed = append(failed, set)
+			continue
+		}
+		log.Info().Str("archive", set.First()).Int("parts", len(set.Parts)).Msg("Extracted archive")
+		extracted++
+	}
+	return extracted, failed
+}
+
+// skippedWarnings describes broken files the scan left out, one per warning
+func skippedWarnings(skipped []scanner.SkippedFile) []string {
+	warnings := make([]string, 0, len(skipped))
+	for _, f := range skipped {
+		warnings = append(warnings, fmt.Sprintf("skipped %s: %s", f.Path, f.Reason))
+	}
+	return warnings
+}
+
+// Worker pool overrides from --workers-io and --workers-net (0 = use config)
+var (
+	ioWorkersFlag  int
+	netWorkersFlag int
+)
+
+// addIOWorkersFlag registers the --workers-io flag on a command
+func addIOWorkersFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&ioWorkersFlag, "workers-io", 0, "concurrent file system operations (0 = performance.io_workers)")
+}
+
+// addNetWorkersFlag registers the --workers-net flag on a command
+func addNetWorkersFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&netWorkersFlag, "workers-net", 0, "concurrent API/artwork requests (0 = performance.net_workers)")
+}
+
+// resolveWorkers returns the file system and network worker counts, each
+// taken from its flag when set and otherwise from config
+func resolveWorkers() (ioWorkers, netWorkers int) {
+	ioWorkers, netWorkers = cfg.Performance.IOWorkers, cfg.Performance.NetWorkers
+	if ioWorkersFlag > 0 {
+		ioWorkers = ioWorkersFlag
+	}
+	if netWorkersFlag > 0 {
+		netWorkers = netWorkersFlag
+	}
+	return ioWorkers, netWorkers
+}
+
+// Minimum enrichment match confidence from --min-confidence (-1 = use config)
+var minConfidenceFlag float64
+
+// addMinConfidenceFlag registers the --min-confidence flag on a command
+func addMinConfidenceFlag(cmd *cobra.Command) {
+	cmd.Flags().Float64Var(&minConfidenceFlag, "min-confidence", -1, "keep parsed metadata when a TMDB match scores below this (0.0-1.0; default enrich.min_confidence)")
+}
+
+// resolveMinConfidence returns the --min-confidence value when given,
+// otherwise the configured threshold
+func resolveMinConfidence() (float64, error) {
+	if minConfidenceFlag < 0 {
+		return cfg.Enrich.MinConfidence, nil
+	}
+	if minConfidenceFlag > 1 {
+		return 0, fmt.Errorf("invalid --min-confidence %v: must be between 0.0 and 1.0", minConfidenceFlag)
+	}
+	return minConfidenceFlag, nil
+}
+
+// resolveExtensions returns the dot-normalized override list when given,
+// otherwise the configured list
+func resolveExtensions(overrides, configured []string) ([]string, error) {
+	if len(overrides) == 0 {
+		return configured, nil
+	}
+
+	exts := make([]string, 0, len(overrides))
+	for _, ext := range overrides {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if len(ext) < 2 || strings.ContainsAny(ext[1:], "./\\ \t*?") {
+			return nil, fmt.Errorf("invalid extension: %q", ext)
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
+
+// scannerExtensions returns the video, audio and book extension lists from
+// config, replaced by any command-line overrides
+func scannerExtensions() (video, audio, book []string, err error) {
+	if video, err = resolveExtensions(videoExtFlags, cfg.Filters.VideoExtensions); err != nil {
+		return nil, nil, nil, fmt.Errorf("--video-ext: %w", err)
+	}
+	if audio, err = resolveExtensions(audioExtFlags, cfg.Filters.AudioExtensions); err != nil {
+		return nil, nil, nil, fmt.Errorf("--audio-ext: %w", err)
+	}
+	if book, err = resolveExtensions(bookExtFlags, cfg.Filters.BookExtensions); err != nil {
+		return nil, nil, nil, fmt.Errorf("--book-ext: %w", err)
+	}
+	return video, audio, book, nil
+}
+
 // createScanner creates a new scanner with configuration from cfg
-func createScanner() *scanner.Scanner {
-	return scanner.NewScanner(
-		cfg.Filters.VideoExtensions,
-		cfg.Filters.AudioExtensions,
-		cfg.Filters.BookExtensions,
-		minFileSize,
-	)
+func createScanner() (*scanner.Scanner, error) {
+	video, audio, book, err := scannerExtensions()
+	if err != nil {
+		return nil, err
+	}
+	s := scanner.NewScanner(video, audio, book, minFileSize)
+	configureScanner(s)
+	return s, nil
+}
+
+// absSources resolves the source directory arguments to absolute paths,
+// dropping repeats. sftp:// sources are checked and kept as URLs.
+func absSources(args []string) ([]string, error) {
+	sources := make([]string, 0, len(args))
+	seen := make(map[string]bool, len(args))
+	for _, arg := range args {
+		var abs string
+		var err error
+		if fsys.IsSFTPURL(arg) {
+			u, err := fsys.ParseSFTPURL(arg)
+			if err != nil {
+				return nil, err
+			}
+			abs = u.String()
+		} else if abs, err = filepath.Abs(arg); err != nil {
+			return nil, fmt.Errorf("failed to resolve path %s: %w", arg, err)
+		}
+		if !seen[abs] {
+			seen[abs] = true
+			sources = append(sources, abs)
+		}
+	}
+	return sources, nil
+}
+
+// scanSources scans each source directory and merges the results. A file
+// found under more than one source, such as a directory and one of its
+// subdirectories, is listed once. Files of sftp:// sources are listed as
+// sftp:// URLs.
+func scanSources(s *scanner.Scanner, sources []string) (*scanner.ScanResult, error) {
+	merged := &scanner.ScanResult{}
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		var result *scanner.ScanResult
+		var err error
+		if fsys.IsSFTPURL(source) {
+			result, err = scanRemote(s, source)
+		} else {
+			result, err = s.Scan(source)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		for _, file := range result.Files {
+			if !seen[file] {
+				seen[file] = true
+				merged.Files = append(merged.Files, file)
+			}
+		}
+		for _, skipped := range result.Skipped {
+			if !seen[skipped.Path] {
+				seen[skipped.Path] = true
+				merged.Skipped = append(merged.Skipped, skipped)
+			}
+		}
+		for _, set := range result.Archives {
+			if !seen[set.First()] {
+				seen[set.First()] = true
+				merged.Archives = append(merged.Archives, set)
+			}
+		}
+		merged.Errors = append(merged.Errors, result.Errors...)
+	}
+	return merged, nil
+}
+
+// checkDestRoots enforces --dest-must-exist: every destination root must
+// already be a directory, so a mistyped path like /mnt/meda fails instead of
+// growing a new library. An empty root is as likely a wrong or unmounted
+// path, so organizing into one is confirmed on reader unless assumeYes; a
+// nil reader (no terminal to prompt on) requires assumeYes.
+func checkDestRoots(roots []string, assumeYes bool, reader io.Reader) error {
+	var empty []string
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("destination %s does not exist (--dest-must-exist); create it first or check the path for typos", root)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot access destination %s: %w", root, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("destination %s is not a directory", root)
+		}
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			return fmt.Errorf("cannot read destination %s: %w", root, err)
+		}
+		if len(entries) == 0 {
+			empty = append(empty, root)
+		}
+	}
+
+	if len(empty) == 0 || assumeYes {
+		return nil
+	}
+	if reader == nil {
+		return fmt.Errorf("destination %s is empty; pass --yes to organize into it", empty[0])
+	}
+
+	bufReader := bufio.NewReader(reader)
+	for _, root := range empty {
+		fmt.Printf("⚠ Destination %s is empty. Organize into it? [y/N]: ", root)
+		input, _ := bufReader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "y", "yes":
+		default:
+			return fmt.Errorf("organizing into empty destination %s not confirmed", root)
+		}
+	}
+	return nil
+}
+
+// configureScanner applies the scanner settings shared by every command
+func configureScanner(s *scanner.Scanner) {
+	s.SetTrustedReleaseGroups(cfg.Organize.TrustedReleaseGroups)
+	s.SetReadMediaInfo(cfg.Organize.ReadMediaInfo)
+	s.SetMaxDepth(scanMaxDepth)
+	s.SetExcludePatterns(cfg.Filters.Exclude)
+	s.SetCheckReadable(checkReadable)
+	ioWorkers, _ := resolveWorkers()
+	s.SetNumWorkers(ioWorkers)
 }
 
 // promptConflictResolution prompts the user for how to handle a conflict
@@ -101,3 +929,59 @@ func promptConflictResolutionWithReader(sourcePath, destPath string, reader io.R
 		return "skip"
 	}
 }
+
+// libraryRoots returns every destination root of the run
+func libraryRoots(destRoot string, destinations map[types.MediaType]string) []string {
+	if destRoot != "" {
+		return []string{destRoot}
+	}
+	roots := make([]string, 0, len(destinations))
+	for _, dest := range destinations {
+		roots = append(roots, dest)
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// markableSources returns the local source folders an .ignore marker can go
+// in: not sftp:// sources or manifest files, and not a folder holding a
+// library root, which Jellyfin would then skip as well
+func markableSources(sources, roots []string) []string {
+	var dirs []string
+	for _, source := range sources {
+		if fsys.IsSFTPURL(source) {
+			continue
+		}
+		if info, err := os.Stat(source); err != nil || !info.IsDir() {
+			continue
+		}
+		holdsRoot := false
+		for _, root := range roots {
+			rel, err := filepath.Rel(source, root)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				holdsRoot = true
+			}
+		}
+		if holdsRoot {
+			log.Warn().Str("source", source).Msg("Not marking a source that holds a library root with .ignore")
+			continue
+		}
+		dirs = append(dirs, source)
+	}
+	return dirs
+}
+
+// writeIgnoreMarkers writes an .ignore marker into each of dirs; failures
+// are warnings, since the organization itself does not depend on them
+func writeIgnoreMarkers(dirs []string) {
+	for _, dir := range dirs {
+		written, err := safety.WriteIgnoreMarker(dir)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to write .ignore marker")
+			continue
+		}
+		if written {
+			log.Debug().Str("dir", dir).Msg("Wrote .ignore marker")
+		}
+	}
+}
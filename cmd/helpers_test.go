@@ -0,0 +1,502 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/go-jf-org/internal/config"
+	"github.com/opd-ai/go-jf-org/internal/notify"
+	"github.com/opd-ai/go-jf-org/internal/organizer"
+	"github.com/opd-ai/go-jf-org/internal/safety"
+	"github.com/opd-ai/go-jf-org/internal/scanner"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestResolveExtensions(t *testing.T) {
+	configured := []string{".mkv", ".mp4"}
+
+	tests := []struct {
+		name      string
+		overrides []string
+		want      []string
+		wantErr   bool
+	}{
+		{"no override uses config", nil, configured, false},
+		{"dot added and lowercased", []string{"MKV"}, []string{".mkv"}, false},
+		{"dotted values kept", []string{".avi", "webm"}, []string{".avi", ".webm"}, false},
+		{"whitespace trimmed", []string{" mkv "}, []string{".mkv"}, false},
+		{"empty rejected", []string{""}, nil, true},
+		{"bare dot rejected", []string{"."}, nil, true},
+		{"path separator rejected", []string{"a/b"}, nil, true},
+		{"multi-dot rejected", []string{"tar.gz"}, nil, true},
+		{"glob rejected", []string{"*.mkv"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveExtensions(tt.overrides, configured)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveExtensions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveExtensions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateScanner_ExtensionFlags(t *testing.T) {
+	oldCfg := cfg
+	cfg = config.DefaultConfig()
+	defer func() {
+		cfg = oldCfg
+		videoExtFlags, audioExtFlags, bookExtFlags = nil, nil, nil
+	}()
+
+	tmpDir := t.TempDir()
+	for _, name := range []string{"Movie.2020.mkv", "Other.2021.mp4", "track.flac", "book.epub"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Truncate(path, minFileSize); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanNames := func(t *testing.T) []string {
+		t.Helper()
+		s, err := createScanner()
+		if err != nil {
+			t.Fatalf("createScanner() error = %v", err)
+		}
+		result, err := s.Scan(tmpDir)
+		if err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		names := make([]string, 0, len(result.Files))
+		for _, f := range result.Files {
+			names = append(names, filepath.Base(f))
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// Config lists apply when no flags are given
+	want := []string{"Movie.2020.mkv", "Other.2021.mp4", "book.epub", "track.flac"}
+	if got := scanNames(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("without flags scanned %v, want %v", got, want)
+	}
+
+	// --video-ext replaces only the video list
+	videoExtFlags = []string{"mkv"}
+	want = []string{"Movie.2020.mkv", "book.epub", "track.flac"}
+	if got := scanNames(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("with --video-ext mkv scanned %v, want %v", got, want)
+	}
+
+	// Invalid values surface as an error
+	bookExtFlags = []string{"e/pub"}
+	if _, err := createScanner(); err == nil {
+		t.Error("createScanner() with invalid --book-ext should fail")
+	}
+}
+
+func TestResolveWorkers(t *testing.T) {
+	oldCfg := cfg
+	cfg = config.DefaultConfig()
+	cfg.Performance.IOWorkers = 16
+	cfg.Performance.NetWorkers = 2
+	defer func() {
+		cfg = oldCfg
+		ioWorkersFlag, netWorkersFlag = 0, 0
+	}()
+
+	tests := []struct {
+		name            string
+		ioFlag, netFlag int
+		wantIO, wantNet int
+	}{
+		{"config", 0, 0, 16, 2},
+		{"io flag only", 32, 0, 32, 2},
+		{"net flag only", 0, 1, 16, 1},
+		{"both flags", 8, 3, 8, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ioWorkersFlag, netWorkersFlag = tt.ioFlag, tt.netFlag
+			gotIO, gotNet := resolveWorkers()
+			if gotIO != tt.wantIO || gotNet != tt.wantNet {
+				t.Errorf("resolveWorkers() = (%d, %d), want (%d, %d)", gotIO, gotNet, tt.wantIO, tt.wantNet)
+			}
+		})
+	}
+}
+
+func TestResolveMinConfidence(t *testing.T) {
+	oldCfg := cfg
+	cfg = config.DefaultConfig()
+	cfg.Enrich.MinConfidence = 0.6
+	defer func() {
+		cfg = oldCfg
+		minConfidenceFlag = -1
+	}()
+
+	tests := []struct {
+		name    string
+		flag    float64
+		want    float64
+		wantErr bool
+	}{
+		{"config", -1, 0.6, false},
+		{"flag overrides", 0.85, 0.85, false},
+		{"flag disables", 0, 0, false},
+		{"out of range", 1.2, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minConfidenceFlag = tt.flag
+			got, err := resolveMinConfidence()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveMinConfidence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("resolveMinConfidence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveNFOTypes(t *testing.T) {
+	oldCfg := cfg
+	cfg = config.DefaultConfig()
+	cfg.Organize.CreateNFOFor = []string{"movie", "tv", "music"}
+	defer func() { cfg = oldCfg }()
+
+	tests := []struct {
+		name     string
+		excluded []string
+		want     []types.MediaType
+		wantErr  bool
+	}{
+		{"config", nil, []types.MediaType{types.MediaTypeMovie, types.MediaTypeTV, types.MediaTypeMusic}, false},
+		{"flag excludes", []string{"music"}, []types.MediaType{types.MediaTypeMovie, types.MediaTypeTV}, false},
+		{"everything excluded", []string{"movie", "tv", "music"}, []types.MediaType{}, false},
+		{"invalid type", []string{"podcast"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveNFOTypes(tt.excluded)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveNFOTypes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveNFOTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanSources_OrganizeTwoSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	downloads := filepath.Join(tmpDir, "downloads")
+	torrents := filepath.Join(tmpDir, "torrents")
+	files := []string{
+		filepath.Join(downloads, "The.Matrix.1999.1080p.mkv"),
+		filepath.Join(downloads, "movies", "Inception.2010.720p.mkv"),
+		filepath.Join(torrents, "Heat.1995.mkv"),
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, []byte("video"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// downloads/movies overlaps downloads; repeated arguments collapse too
+	sources, err := absSources([]string{downloads, torrents, filepath.Join(downloads, "movies"), torrents})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 3 {
+		t.Fatalf("absSources() = %v, want 3 distinct sources", sources)
+	}
+
+	s := scanner.NewScanner([]string{".mkv"}, nil, nil, 0)
+	result, err := scanSources(s, sources)
+	if err != nil {
+		t.Fatalf("scanSources() error = %v", err)
+	}
+	got := append([]string(nil), result.Files...)
+	sort.Strings(got)
+	want := append([]string(nil), files...)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("scanSources() files = %v, want %v", got, want)
+	}
+
+	tm, err := safety.NewTransactionManager(filepath.Join(tmpDir, "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(tmpDir, "library")
+	org := organizer.NewOrganizerWithTransactions(false, tm)
+	plans, err := org.PlanOrganization(result.Files, dest, types.MediaTypeUnknown)
+	if err != nil {
+		t.Fatalf("PlanOrganization() error = %v", err)
+	}
+	txnID, ops, err := org.ExecuteWithTransaction(plans, "skip")
+	if err != nil {
+		t.Fatalf("ExecuteWithTransaction() error = %v", err)
+	}
+	if len(ops) != len(files) {
+		t.Errorf("got %d operations, want %d", len(ops), len(files))
+	}
+	for _, name := range []string{"The Matrix (1999)", "Inception (2010)", "Heat (1995)"} {
+		if _, err := os.Stat(filepath.Join(dest, name, name+".mkv")); err != nil {
+			t.Errorf("%s not organized: %v", name, err)
+		}
+	}
+
+	ids, err := tm.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != txnID {
+		t.Errorf("transactions = %v, want the single run %s", ids, txnID)
+	}
+}
+
+func TestCheckDestRoots(t *testing.T) {
+	tmpDir := t.TempDir()
+	library := filepath.Join(tmpDir, "media")
+	empty := filepath.Join(tmpDir, "empty")
+	file := filepath.Join(tmpDir, "file")
+	if err := os.MkdirAll(filepath.Join(library, "Movies"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(empty, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		roots     []string
+		assumeYes bool
+		input     string
+		noTTY     bool
+		wantErr   bool
+	}{
+		{"existing library", []string{library}, false, "", false, false},
+		{"typo rejected", []string{filepath.Join(tmpDir, "meda")}, true, "", false, true},
+		{"any missing root rejected", []string{library, filepath.Join(tmpDir, "music")}, true, "", false, true},
+		{"file rejected", []string{file}, true, "", false, true},
+		{"empty confirmed", []string{empty}, false, "y\n", false, false},
+		{"empty declined", []string{empty}, false, "\n", false, true},
+		{"empty with --yes", []string{empty}, true, "", false, false},
+		{"empty without terminal", []string{empty}, false, "", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reader io.Reader = strings.NewReader(tt.input)
+			if tt.noTTY {
+				reader = nil
+			}
+			err := checkDestRoots(tt.roots, tt.assumeYes, reader)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkDestRoots() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Nothing is created for a rejected root
+	if _, err := os.Stat(filepath.Join(tmpDir, "meda")); !os.IsNotExist(err) {
+		t.Errorf("rejected destination was created: %v", err)
+	}
+}
+
+func TestRunAfterHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook script uses sh")
+	}
+
+	out := filepath.Join(t.TempDir(), "env.txt")
+	run := hookRun{
+		TransactionID: "txn-123",
+		Organized:     3,
+		Failed:        1,
+		Skipped:       2,
+		DestRoots:     []string{"/media/Movies", "/media/TV"},
+	}
+	command := `env | grep -E '^GO_JF_ORG_(TRANSACTION_ID|ORGANIZED|FAILED|SKIPPED|DEST_ROOTS?)=' | sort > "` + out + `"`
+	if err := runAfterHook(command, run, io.Discard, io.Discard); err != nil {
+		t.Fatalf("runAfterHook() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{
+		"GO_JF_ORG_DEST_ROOT=/media/Movies",
+		"GO_JF_ORG_DEST_ROOTS=/media/Movies:/media/TV",
+		"GO_JF_ORG_FAILED=1",
+		"GO_JF_ORG_ORGANIZED=3",
+		"GO_JF_ORG_SKIPPED=2",
+		"GO_JF_ORG_TRANSACTION_ID=txn-123",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hook environment = %q, want %q", got, want)
+	}
+
+	if err := runAfterHook("exit 3", run, io.Discard, io.Discard); err == nil {
+		t.Error("a non-zero hook exit should be reported")
+	}
+}
+
+func TestRunAfterHookOnSuccess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook script uses sh")
+	}
+
+	tests := []struct {
+		name    string
+		dryRun  bool
+		failed  int
+		wantRan bool
+	}{
+		{name: "clean run", wantRan: true},
+		{name: "failed run", failed: 2, wantRan: false},
+		{name: "dry run", dryRun: true, wantRan: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marker := filepath.Join(t.TempDir(), "ran")
+			run := hookRun{Organized: 3, Failed: tt.failed}
+			ran, err := runAfterHookOnSuccess(`touch "`+marker+`"`, tt.dryRun, run, io.Discard, io.Discard)
+			if err != nil {
+				t.Fatalf("runAfterHookOnSuccess() error = %v", err)
+			}
+			_, statErr := os.Stat(marker)
+			if ran != tt.wantRan || (statErr == nil) != tt.wantRan {
+				t.Errorf("hook ran = %v (marker stat err %v), want %v", ran, statErr, tt.wantRan)
+			}
+		})
+	}
+}
+
+func TestSendRunNotification_Offline(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	oldCfg, oldOffline := cfg, offline
+	cfg = config.DefaultConfig()
+	cfg.Notifications.WebhookURL = server.URL
+	defer func() { cfg, offline = oldCfg, oldOffline }()
+
+	offline = true
+	sendRunNotification(&notify.Summary{Command: "organize"}, nil, time.Second)
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("offline run sent %d notifications, want none", n)
+	}
+
+	offline = false
+	sendRunNotification(&notify.Summary{Command: "organize"}, nil, time.Second)
+	if n := requests.Load(); n != 1 {
+		t.Errorf("online run sent %d notifications, want 1", n)
+	}
+}
+
+func TestScanSources_SkipsArchiveSets(t *testing.T) {
+	tmpDir := t.TempDir()
+	release := filepath.Join(tmpDir, "Movie.2020.1080p")
+	if err := os.MkdirAll(release, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		part := filepath.Join(release, "movie.part"+strconv.Itoa(i)+".rar")
+		if err := os.WriteFile(part, []byte("volume"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Without --extract the volumes are neither organized nor dropped silently
+	s := scanner.NewScanner([]string{".mkv"}, nil, nil, 0)
+	result, err := scanSources(s, []string{tmpDir, release})
+	if err != nil {
+		t.Fatalf("scanSources() error = %v", err)
+	}
+	if len(result.Files) != 0 {
+		t.Errorf("Files = %v, want no archive volume treated as media", result.Files)
+	}
+	if len(result.Archives) != 1 {
+		t.Fatalf("Archives = %+v, want the set once across overlapping sources", result.Archives)
+	}
+
+	warnings := archiveWarnings(result.Archives)
+	want := filepath.Join(release, "movie.part1.rar") + ": 3-part rar archive, needs extraction"
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("archiveWarnings() = %q, want %q", warnings, want)
+	}
+}
+
+func TestMarkableSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	incoming := filepath.Join(tmpDir, "media", "incoming")
+	library := filepath.Join(tmpDir, "media", "library")
+	downloads := filepath.Join(tmpDir, "downloads")
+	manifest := filepath.Join(tmpDir, "fixes.csv")
+	for _, dir := range []string{incoming, library, downloads} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(manifest, nil, 0644)
+
+	sources := []string{incoming, downloads, filepath.Join(tmpDir, "media"), manifest, "sftp://seedbox/complete"}
+	roots := libraryRoots("", map[types.MediaType]string{
+		types.MediaTypeMovie: filepath.Join(library, "movies"),
+		types.MediaTypeTV:    filepath.Join(library, "tv"),
+	})
+	got := markableSources(sources, roots)
+	// The media folder holds the library, which Jellyfin would skip too
+	if want := []string{incoming, downloads}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("markableSources() = %v, want %v", got, want)
+	}
+	if got := markableSources([]string{library}, []string{library}); len(got) != 0 {
+		t.Errorf("markableSources() marked the library root itself: %v", got)
+	}
+
+	staging := safety.StagingDir(filepath.Join(library, "movies"), time.Now())
+	writeIgnoreMarkers(append([]string{staging}, got...))
+	for _, dir := range []string{incoming, downloads, staging} {
+		if _, err := os.Stat(filepath.Join(dir, safety.IgnoreMarker)); err != nil {
+			t.Errorf("no marker in %s: %v", dir, err)
+		}
+	}
+	for _, dir := range []string{filepath.Join(tmpDir, "media"), library, filepath.Join(library, "movies")} {
+		if _, err := os.Stat(filepath.Join(dir, safety.IgnoreMarker)); !os.IsNotExist(err) {
+			t.Errorf("unexpected marker in %s, stat err = %v", dir, err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/organizer"
+	"github.com/opd-ai/go-jf-org/internal/scanner"
+	"github.com/opd-ai/go-jf-org/internal/util"
+	"github.com/opd-ai/go-jf-org/internal/verifier"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/")
+
+// checkGolden compares v, encoded as indented JSON, with testdata/<name>.
+// A mismatch means the machine-readable output contract changed: if that is
+// deliberate, bump util.JSONSchemaVersion when needed, update
+// docs/json-output.md and rerun with -update.
+func checkGolden(t *testing.T, name string, v interface{}) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("json.MarshalIndent() error = %v", err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s output changed:\n got: %s\nwant: %s", name, got, want)
+	}
+}
+
+func TestPreviewJSON_Golden(t *testing.T) {
+	plans := []organizer.Plan{
+		{
+			SourcePath:      "/src/The.Matrix.1999.1080p.BluRay.x264-SPARKS.mkv",
+			DestinationPath: "/dest/movies/The Matrix (1999)/The Matrix (1999).mkv",
+			MediaType:       types.MediaTypeMovie,
+			Metadata: &types.Metadata{
+				Title:        "The Matrix",
+				Year:         1999,
+				Quality:      "1080p",
+				Source:       "BluRay",
+				Codec:        "x264",
+				ReleaseGroup: "SPARKS",
+			},
+			Subtitles: []string{"/dest/movies/The Matrix (1999)/The Matrix (1999).en.srt"},
+		},
+		{
+			SourcePath:      "/src/Show.S01E02.mkv",
+			DestinationPath: "/dest/tv/Show/Season 01/Show - S01E02.mkv",
+			MediaType:       types.MediaTypeTV,
+			Metadata: &types.Metadata{
+				Title:      "Show",
+				TVMetadata: &types.TVMetadata{ShowTitle: "Show", Season: 1, Episode: 2},
+			},
+			Conflict:       true,
+			ConflictReason: "destination file already exists",
+			Warnings:       []string{"suspected sample (12 MB)"},
+		},
+	}
+
+	checkGolden(t, "preview.golden", buildPreviewReport("/src", "/dest", types.MediaTypeUnknown, plans))
+}
+
+func TestVerifyJSON_Golden(t *testing.T) {
+	result := &verifier.Result{
+		Path:         "/media/movies",
+		CheckedDirs:  3,
+		ErrorCount:   1,
+		WarningCount: 1,
+		MediaCounts:  map[types.MediaType]int{types.MediaTypeMovie: 2},
+		ItemCounts:   map[types.MediaType]int{types.MediaTypeMovie: 2},
+		Violations: []verifier.Violation{
+			{
+				Severity:   verifier.SeverityError,
+				Path:       "/media/movies/matrix",
+				Message:    "Movie directory should be named 'Title (Year)'",
+				Suggestion: "Rename to 'The Matrix (1999)'",
+				MediaType:  types.MediaTypeMovie,
+			},
+			{
+				Severity:  verifier.SeverityWarning,
+				Path:      "/media/movies/The Matrix (1999)",
+				Message:   "Missing movie.nfo",
+				MediaType: types.MediaTypeMovie,
+			},
+		},
+	}
+
+	checkGolden(t, "verify.golden", buildVerifyReport(result))
+	checkGolden(t, "verify_empty.golden", buildVerifyReport(&verifier.Result{Path: "/media/movies"}))
+}
+
+func TestDuplicatesJSON_Golden(t *testing.T) {
+	groups := []scanner.DuplicateGroup{
+		{
+			Key:       "The Matrix (1999)",
+			MediaType: types.MediaTypeMovie,
+			Files: []scanner.DuplicateFile{
+				{Path: "/src/The.Matrix.1999.1080p.BluRay.x264-SPARKS.mkv", Size: 8589934592, Quality: "1080P", ReleaseGroup: "SPARKS", Best: true},
+				{Path: "/src/The.Matrix.1999.720p.mkv", Size: 4294967296, Quality: "720P"},
+			},
+		},
+	}
+
+	checkGolden(t, "duplicates.golden", buildDuplicateReport("/src", groups))
+	checkGolden(t, "duplicates_empty.golden", buildDuplicateReport("/src", nil))
+}
+
+func TestJSONOutputs_SchemaVersion(t *testing.T) {
+	reports := map[string]interface{}{
+		"preview":    buildPreviewReport("/src", "/dest", types.MediaTypeUnknown, nil),
+		"verify":     buildVerifyReport(&verifier.Result{}),
+		"duplicates": buildDuplicateReport("/src", nil),
+	}
+
+	for name, report := range reports {
+		data, err := json.Marshal(report)
+		if err != nil {
+			t.Fatalf("%s: json.Marshal() error = %v", name, err)
+		}
+		var decoded struct {
+			SchemaVersion *int `json:"schema_version"`
+		}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("%s: json.Unmarshal() error = %v", name, err)
+		}
+		if decoded.SchemaVersion == nil || *decoded.SchemaVersion != util.JSONSchemaVersion {
+			t.Errorf("%s: schema_version missing or wrong in %s", name, data)
+		}
+	}
+}
@@ -2,34 +2,71 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/opd-ai/go-jf-org/internal/artwork"
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+	"github.com/opd-ai/go-jf-org/internal/notify"
 	"github.com/opd-ai/go-jf-org/internal/organizer"
+	"github.com/opd-ai/go-jf-org/internal/output"
 	"github.com/opd-ai/go-jf-org/internal/safety"
 	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
-var (
-	organizeDest             string
-	organizeMediaType        string
-	organizeConflictStrategy string
-	organizeDryRun           bool
-	organizeNoTransaction    bool
-	organizeCreateNFO        bool
-	organizeJSONOutput       bool
-	organizeInteractive      bool
-	organizeDownloadArtwork  bool
-	organizeArtworkSize      string
-)
+// organizeOptions are the settings of one organize run, from the
+// command's flags or from an API request
+type organizeOptions struct {
+	dest             string
+	mediaType        string
+	conflictStrategy string
+	dryRun           bool
+	noTransaction    bool
+	createNFO        bool
+	clobberNFO       bool
+	sidecarNFO       bool
+	noNFOForType     []string
+	shardTypes       []string
+	jsonOutput       bool
+	interactive      bool
+	downloadArtwork  bool
+	artworkSize      string
+	flatten          bool
+	folderNames      bool
+	probeDuration    bool
+	cleanSources     bool
+	ignoreMarkers    bool
+	extract          bool
+	destStructure    string
+	collisionLog     string
+	hash             bool
+	renameOnly       bool
+	stage            bool
+	onError          string
+	enrich           bool
+	destMustExist    bool
+	strictValidation bool
+	validationReport string
+	yes              bool
+	statsFile        string
+	afterHook        string
+	manifest         string
+	limit            int
+}
+
+// organizeFlags holds the organize command's flags
+var organizeFlags organizeOptions
 
 var organizeCmd = &cobra.Command{
-	Use:   "organize [directory]",
+	Use:   "organize [directory...]",
 	Short: "Organize media files into Jellyfin-compatible structure",
 	Long: `Organize scans the specified directory and moves media files into a
 Jellyfin-compatible directory structure with proper naming conventions.
@@ -41,54 +78,188 @@ The organize command:
   - Renames files according to Jellyfin conventions
   - Handles conflicts based on specified strategy
 
+Several source directories can be given; they are scanned together and
+organized in one run with a single transaction. A file reached through
+overlapping paths is organized once.
+
+A source may be on an SFTP server, given as sftp://user@host/path. Its media
+files are downloaded under remote.download_dir and organized from there;
+the files on the server are left as they are.
+
+Without --dest or --type, each file is moved to the configured destination
+for its own media type, so a folder holding a movie, its soundtrack and a
+book is split across the movie, music and book libraries.
+
 Safety features:
   - Files are moved, never deleted
   - Conflict resolution strategies available
   - Dry-run mode for testing (--dry-run)
-  - Validation before operations`,
-	Args: cobra.ExactArgs(1),
+  - Validation before operations
+  - Failure policy (--on-error continue|stop|rollback)
+
+With --manifest, a CSV or JSON file lists each source file with its type,
+title, year, season and episode, and organize uses that instead of scanning
+directories and parsing filenames.`,
+	Args: organizeArgs,
 	RunE: runOrganize,
 }
 
 func init() {
 	rootCmd.AddCommand(organizeCmd)
 
-	organizeCmd.Flags().StringVarP(&organizeDest, "dest", "d", "", "destination root directory (default from config)")
-	organizeCmd.Flags().StringVarP(&organizeMediaType, "type", "t", "", "filter by media type (movie, tv, music, book)")
-	organizeCmd.Flags().StringVar(&organizeConflictStrategy, "conflict", "skip", "conflict resolution strategy (skip, rename, interactive)")
-	organizeCmd.Flags().BoolVar(&organizeDryRun, "dry-run", false, "preview changes without executing")
-	organizeCmd.Flags().BoolVar(&organizeNoTransaction, "no-transaction", false, "disable transaction logging (not recommended)")
-	organizeCmd.Flags().BoolVar(&organizeCreateNFO, "create-nfo", false, "create Jellyfin-compatible NFO metadata files")
-	organizeCmd.Flags().BoolVar(&organizeDownloadArtwork, "download-artwork", false, "download poster and cover artwork for media")
-	organizeCmd.Flags().StringVar(&organizeArtworkSize, "artwork-size", "medium", "artwork size preference (small, medium, large, original)")
-	organizeCmd.Flags().BoolVar(&organizeJSONOutput, "json", false, "output statistics in JSON format")
-	organizeCmd.Flags().BoolVar(&organizeInteractive, "interactive", false, "prompt for decisions on conflicts (sets conflict strategy to interactive)")
+	organizeCmd.Flags().StringVarP(&organizeFlags.dest, "dest", "d", "", "destination root directory for all files (default: per-type destinations from config)")
+	organizeCmd.Flags().StringVarP(&organizeFlags.mediaType, "type", "t", "", "filter by media type (movie, tv, music, book)")
+	organizeCmd.Flags().StringVar(&organizeFlags.conflictStrategy, "conflict", "skip", "conflict resolution strategy (skip, rename, interactive)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.dryRun, "dry-run", false, "preview changes without executing")
+	organizeCmd.Flags().BoolVar(&organizeFlags.noTransaction, "no-transaction", false, "disable transaction logging (not recommended)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.createNFO, "create-nfo", false, "create Jellyfin-compatible NFO metadata files")
+	organizeCmd.Flags().BoolVar(&organizeFlags.clobberNFO, "clobber-nfo", false, "overwrite existing NFO files instead of keeping them (use with --create-nfo)")
+	organizeCmd.Flags().StringSliceVar(&organizeFlags.noNFOForType, "no-nfo-for-type", nil, "media types to skip NFOs for with --create-nfo (repeatable, e.g. --no-nfo-for-type music)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.sidecarNFO, "copy-sidecar-nfo", false, "move a video's existing <name>.nfo along with it, merged with or replacing the generated NFO (organize.sidecar_nfo_policy)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.enrich, "enrich", false, "look up metadata from TMDB, MusicBrainz and OpenLibrary before naming (with --offline, from cache only)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.downloadArtwork, "download-artwork", false, "download poster and cover artwork for media")
+	organizeCmd.Flags().StringVar(&organizeFlags.artworkSize, "artwork-size", "medium", "artwork size preference (small, medium, large, original)")
+	organizeCmd.Flags().StringSliceVar(&organizeFlags.shardTypes, "group-by-first-letter", nil, "put folders in first-letter buckets (Movies/M/The Matrix (1999)/); alone for every type, or =movie,music for some (default organize.group_by_first_letter)")
+	organizeCmd.Flags().Lookup("group-by-first-letter").NoOptDefVal = "movie,tv,music,book"
+	organizeCmd.Flags().BoolVar(&organizeFlags.probeDuration, "probe-duration", false, "read the running time of videos whose name could be a movie or a TV episode to decide which (default organize.probe_duration)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.folderNames, "prefer-folder-name", false, "take music artist and album from Artist/Album/ or \"Artist - Album\"/ folders instead of filenames (default organize.prefer_folder_name)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.flatten, "flatten", false, "place movies directly in the destination root without per-movie folders")
+	organizeCmd.Flags().StringVar(&organizeFlags.destStructure, "dest-structure", "", "library layout: nested (Jellyfin folders) or flat-by-type (every file directly in its library root); default from config")
+	organizeCmd.Flags().StringVar(&organizeFlags.collisionLog, "collision-log", "", "write a JSON log of every conflict and its resolution to this file")
+	organizeCmd.Flags().BoolVar(&organizeFlags.hash, "hash", false, "record a hash of each moved file in the transaction, using integrity.algorithm (see 'transactions verify')")
+	organizeCmd.Flags().BoolVar(&organizeFlags.renameOnly, "rename-only", false, "rename files in place to Jellyfin conventions without moving them to a destination root")
+	organizeCmd.Flags().StringVar(&organizeFlags.onError, "on-error", "continue", "after a failed operation: continue, stop (keep completed operations) or rollback (undo the whole run)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.stage, "stage", false, "write output under <dest>/.staging-<timestamp>/ for review (merge with 'transactions merge-staging')")
+	organizeCmd.Flags().BoolVar(&organizeFlags.destMustExist, "dest-must-exist", false, "fail unless each destination root already exists, and confirm empty ones (default safety.dest_must_exist)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.strictValidation, "strict-validation", false, "abort the whole run if any planned file fails validation, instead of organizing the valid ones (default safety.strict_validation)")
+	organizeCmd.Flags().StringVar(&organizeFlags.validationReport, "validation-report", "", "write a JSON report of the files that failed validation to this file")
+	organizeCmd.Flags().BoolVarP(&organizeFlags.yes, "yes", "y", false, "organize into an empty destination without asking (with --dest-must-exist)")
+	organizeCmd.Flags().StringVar(&organizeFlags.manifest, "manifest", "", "organize the files listed in this CSV or JSON manifest with its metadata instead of scanning directories")
+	organizeCmd.Flags().IntVar(&organizeFlags.limit, "limit", 0, "organize at most this many valid files, taken in source path order; the rest are left for a later run (0 = no limit)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.extract, "extract", false, "extract RAR and 7z archive sets (Movie.part01.rar ...) next to their volumes with archives.extract_command before organizing (default archives.extract)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.cleanSources, "clean-sources", false, "after a run with no failures, trash files matching cleanup.junk_patterns from the folders files were moved out of and remove those left empty (default cleanup.after_organize)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.ignoreMarkers, "ignore-markers", false, "write Jellyfin .ignore markers into the source folders and --stage directories before organizing (default markers.ignore_sources and markers.ignore_staging)")
+	organizeCmd.Flags().StringVar(&organizeFlags.afterHook, "after-hook", "", "shell command to run after a run with no failures, with GO_JF_ORG_* variables describing the run (default organize.after_hook)")
+	organizeCmd.Flags().StringVar(&organizeFlags.statsFile, "stats-file", "", "keep partial run statistics in this JSON file while organizing (default performance.stats_file)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.jsonOutput, "json", false, "output statistics in JSON format (same as --output json)")
+	organizeCmd.Flags().BoolVar(&organizeFlags.interactive, "interactive", false, "prompt for decisions on conflicts (sets conflict strategy to interactive)")
+	addExtensionFlags(organizeCmd)
+	addMaxDepthFlag(organizeCmd)
+	addIOWorkersFlag(organizeCmd)
+	addCheckReadableFlag(organizeCmd)
+	addMinConfidenceFlag(organizeCmd)
+}
+
+// organizeArgs requires directories unless a manifest lists the files
+func organizeArgs(cmd *cobra.Command, args []string) error {
+	if organizeFlags.manifest != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--manifest cannot be combined with directory arguments")
+		}
+		return nil
+	}
+	return cobra.MinimumNArgs(1)(cmd, args)
 }
 
+// runOrganize organizes the given directories, then notifies the
+// configured targets about any run that got as far as starting
 func runOrganize(cmd *cobra.Command, args []string) error {
-	scanPath := args[0]
+	run := &notify.Summary{Command: "organize"}
+	started := time.Now()
+	err := organize(organizeFlags, args, run)
+	if run.Sources != nil && !organizeFlags.dryRun {
+		sendRunNotification(run, err, time.Since(started))
+	}
+	return err
+}
 
-	// Make path absolute
-	absPath, err := filepath.Abs(scanPath)
+// organize does the work of runOrganize with opts, recording the outcome
+// in run
+func organize(opts organizeOptions, args []string, run *notify.Summary) error {
+	format, err := resolveOutputFormat(opts.jsonOutput)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return err
 	}
+	structured := format != output.FormatText
 
-	// Determine destination root
-	destRoot, err := getDestinationRoot(organizeMediaType, organizeDest)
+	// A manifest stands in for the source directories
+	var manifest []organizer.ManifestEntry
+	if opts.manifest != "" {
+		if manifest, err = organizer.LoadManifest(opts.manifest); err != nil {
+			return err
+		}
+		args = []string{opts.manifest}
+	}
+	sources, err := absSources(args)
 	if err != nil {
 		return err
 	}
 
+	// Determine destination root (files stay under the source in rename-only
+	// mode). Without --dest or --type, each file goes to the configured
+	// destination for its own type and destRoot stays empty.
+	destRoot := sources[0]
+	var destinations map[types.MediaType]string
+	if opts.renameOnly {
+		if opts.dest != "" {
+			return fmt.Errorf("--rename-only cannot be combined with --dest")
+		}
+		if opts.stage {
+			return fmt.Errorf("--rename-only cannot be combined with --stage")
+		}
+		for _, source := range sources {
+			if fsys.IsSFTPURL(source) {
+				return fmt.Errorf("--rename-only cannot be used with sftp:// sources")
+			}
+		}
+	} else if opts.dest == "" && opts.mediaType == "" {
+		destRoot = ""
+		destinations = configuredDestinations()
+		if len(destinations) == 0 {
+			return fmt.Errorf("destination directory required (use --dest or configure in config file)")
+		}
+	} else {
+		destRoot, err = getDestinationRoot(opts.mediaType, opts.dest)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Catch mistyped destinations before anything is scanned or created
+	if (opts.destMustExist || cfg.Safety.DestMustExist) && !opts.renameOnly {
+		roots := libraryRoots(destRoot, destinations)
+		var reader io.Reader = os.Stdin
+		if structured {
+			reader = nil
+		}
+		if err := checkDestRoots(roots, opts.yes || opts.dryRun, reader); err != nil {
+			return err
+		}
+	}
+
+	// Route all output into a timestamped staging directory for review
+	if opts.stage {
+		if opts.noTransaction {
+			return fmt.Errorf("--stage requires transaction logging to merge staged files (remove --no-transaction)")
+		}
+		now := time.Now()
+		if destRoot != "" {
+			destRoot = safety.StagingDir(destRoot, now)
+		}
+		for mediaType, dest := range destinations {
+			destinations[mediaType] = safety.StagingDir(dest, now)
+		}
+	}
+
 	// Parse media type filter
-	mediaTypeFilter, err := parseMediaTypeFilter(organizeMediaType)
+	mediaTypeFilter, err := parseMediaTypeFilter(opts.mediaType)
 	if err != nil {
 		return err
 	}
 
 	// Handle interactive flag
-	if organizeInteractive {
-		organizeConflictStrategy = "interactive"
+	if opts.interactive {
+		opts.conflictStrategy = "interactive"
 	}
 
 	// Validate conflict strategy
@@ -97,101 +268,322 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 		"rename":      true,
 		"interactive": true,
 	}
-	if !validStrategies[organizeConflictStrategy] {
-		return fmt.Errorf("invalid conflict strategy: %s (must be skip, rename, or interactive)", organizeConflictStrategy)
+	if !validStrategies[opts.conflictStrategy] {
+		return fmt.Errorf("invalid conflict strategy: %s (must be skip, rename, or interactive)", opts.conflictStrategy)
+	}
+
+	if opts.limit < 0 {
+		return fmt.Errorf("invalid --limit: %d (must be 0 or more)", opts.limit)
 	}
 
+	errorPolicy, err := parseErrorPolicy(opts.onError)
+	if err != nil {
+		return err
+	}
+	if errorPolicy == organizer.ErrorPolicyRollback && opts.noTransaction {
+		return fmt.Errorf("--on-error rollback requires transaction logging (remove --no-transaction)")
+	}
+
+	cleanupMode, trashDir, err := resolveCleanup()
+	if err != nil {
+		return err
+	}
+	cleanSources := (opts.cleanSources || cfg.Cleanup.AfterOrganize) && !opts.renameOnly
+	// Renaming in place would mark the library itself
+	markSources := (opts.ignoreMarkers || cfg.Markers.IgnoreSources) && !opts.renameOnly
+	markStaging := (opts.ignoreMarkers || cfg.Markers.IgnoreStaging) && opts.stage
+
 	// Interactive mode requires TTY
-	if organizeConflictStrategy == "interactive" {
-		if organizeJSONOutput {
-			return fmt.Errorf("interactive mode cannot be used with --json output")
+	if opts.conflictStrategy == "interactive" {
+		if structured {
+			return fmt.Errorf("interactive mode cannot be used with %s output", format)
 		}
-		if organizeDryRun {
+		if opts.dryRun {
 			fmt.Println("⚠️  Note: Interactive mode in dry-run will simulate prompts without user input")
 			fmt.Println()
 		}
 	}
 
-	if organizeDryRun && !organizeJSONOutput {
+	if opts.dryRun && !structured {
 		fmt.Println("⚠ DRY-RUN MODE: No files will be moved")
 		fmt.Println()
 	}
 
+	logDest := destRoot
+	if logDest == "" {
+		logDest = "per-type destinations"
+	}
 	log.Info().
-		Str("path", absPath).
-		Str("dest", destRoot).
-		Bool("dry_run", organizeDryRun).
+		Strs("paths", sources).
+		Str("dest", logDest).
+		Bool("dry_run", opts.dryRun).
 		Msg("Starting organization")
+	run.Sources = sources
 
 	// Create statistics tracker
 	stats := util.NewStatistics()
+	stopStats, err := startStatsFlusher(stats, opts.statsFile)
+	if err != nil {
+		return err
+	}
+	defer stopStats()
 
 	// Create scanner
-	s := createScanner()
-
-	// Scan for files with progress
-	if !organizeJSONOutput {
-		fmt.Printf("Scanning %s...\n", absPath)
-	}
-	scanSpinner := util.NewSpinner("Scanning for media files")
-	if !organizeJSONOutput {
-		scanSpinner.Start()
+	s, err := createScanner()
+	if err != nil {
+		return err
 	}
 
-	scanTimer := stats.NewTimer("scan")
-	result, err := s.Scan(absPath)
-	scanTimer.Stop()
+	var files []string
+	if manifest != nil {
+		files = organizer.ManifestSources(manifest)
+		stats.Add("files_scanned", len(files))
+	} else {
+		// Scan for files with progress
+		if !structured {
+			fmt.Printf("Scanning %s...\n", strings.Join(sources, ", "))
+		}
+		scanSpinner := util.NewSpinner("Scanning for media files")
+		if !structured {
+			scanSpinner.Start()
+		}
 
-	if !organizeJSONOutput {
-		scanSpinner.Stop()
-	}
+		scanTimer := stats.NewTimer("scan")
+		result, err := scanSources(s, sources)
+		scanTimer.Stop()
 
-	if err != nil {
-		return fmt.Errorf("scan failed: %w", err)
-	}
+		if !structured {
+			scanSpinner.Stop()
+		}
 
-	stats.Add("files_scanned", len(result.Files))
+		if err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
 
-	if len(result.Files) == 0 {
-		fmt.Println("No media files found to organize.")
-		return nil
+		// Archive volumes are never organized; extract the sets and scan
+		// again to pick up what they held, or report them
+		archives := result.Archives
+		if len(archives) > 0 && (opts.extract || cfg.Archives.Extract) {
+			if opts.dryRun {
+				fmt.Printf("Would extract %d archive set(s) before organizing\n", len(archives))
+			} else {
+				var extracted int
+				extracted, archives = extractArchives(archives, cfg.Archives.ExtractCommand)
+				stats.Add("archives_extracted", extracted)
+				if !structured {
+					fmt.Printf("✓ Extracted %d archive set(s)\n", extracted)
+				}
+				if extracted > 0 {
+					if result, err = scanSources(s, sources); err != nil {
+						return fmt.Errorf("scan failed: %w", err)
+					}
+				}
+			}
+		}
+		files = result.Files
+
+		stats.Add("files_scanned", len(result.Files))
+		stats.Add("files_broken", len(result.Skipped))
+		stats.Add("archives_skipped", len(archives))
+		if !structured {
+			printSkippedFiles(result.Skipped)
+			printArchives(archives)
+		}
+
+		if len(result.Files) == 0 {
+			fmt.Println("No media files found to organize.")
+			return nil
+		}
+
+		// Files on sftp:// sources are downloaded and organized from the
+		// local copies; the originals stay on the server
+		localFiles, remote := dropRemoteFiles(files)
+		if remote > 0 && opts.dryRun {
+			files = localFiles
+			fmt.Printf("Would download %d file(s) from sftp:// sources before organizing\n", remote)
+		} else if remote > 0 {
+			downloadDir, err := resolveDownloadDir()
+			if err != nil {
+				return err
+			}
+			downloads, err := fetchRemoteFiles(files, downloadDir)
+			if err != nil {
+				return err
+			}
+			files = downloads.Files
+			stats.Add("files_downloaded", downloads.Downloaded)
+			if !structured && downloads.Downloaded > 0 {
+				fmt.Printf("✓ Downloaded %d file(s) to %s\n", downloads.Downloaded, downloadDir)
+			}
+			for _, err := range downloads.Errors {
+				fmt.Printf("⚠ Warning: download failed: %v\n", err)
+			}
+		}
 	}
 
-	fmt.Printf("Found %d media files\n\n", len(result.Files))
+	fmt.Printf("Found %d media files\n\n", len(files))
 
 	// Create organizer with transaction support
 	var org *organizer.Organizer
 	var tm *safety.TransactionManager
 
-	if !organizeNoTransaction && !organizeDryRun {
+	if !opts.noTransaction && !opts.dryRun {
 		logDir, err := safety.GetDefaultLogDir()
 		if err != nil {
 			log.Warn().Err(err).Msg("Failed to get transaction log directory, proceeding without transactions")
-			org = organizer.NewOrganizer(organizeDryRun)
+			org = organizer.NewOrganizer(opts.dryRun)
 		} else {
 			tm, err = safety.NewTransactionManager(logDir)
 			if err != nil {
 				log.Warn().Err(err).Msg("Failed to initialize transaction manager, proceeding without transactions")
-				org = organizer.NewOrganizer(organizeDryRun)
+				org = organizer.NewOrganizer(opts.dryRun)
 			} else {
-				org = organizer.NewOrganizerWithTransactions(organizeDryRun, tm)
+				if err := setCommitBatch(tm); err != nil {
+					return err
+				}
+				org = organizer.NewOrganizerWithTransactions(opts.dryRun, tm)
 			}
 		}
 	} else {
-		org = organizer.NewOrganizer(organizeDryRun)
+		org = organizer.NewOrganizer(opts.dryRun)
 	}
 
 	// Configure NFO generation
-	org.SetCreateNFO(organizeCreateNFO)
+	org.SetCreateNFO(opts.createNFO)
+	org.SetClobberNFO(opts.clobberNFO)
+	org.SetRecordOriginalFilename(cfg.NFO.RecordOriginalFilename)
+	nfoTypes, err := resolveNFOTypes(opts.noNFOForType)
+	if err != nil {
+		return err
+	}
+	org.SetNFOTypes(nfoTypes)
+	sidecarPolicy, err := resolveSidecarNFOPolicy()
+	if err != nil {
+		return err
+	}
+	org.SetSidecarNFO(opts.sidecarNFO, sidecarPolicy)
+
+	// Configure movie layout
+	movieLayout, err := resolveMovieLayout(opts.flatten)
+	if err != nil {
+		return err
+	}
+	org.SetMovieLayout(movieLayout)
+
+	miniSeriesLayout, err := resolveMiniSeriesLayout()
+	if err != nil {
+		return err
+	}
+	org.SetMiniSeriesLayout(miniSeriesLayout)
+
+	destStructure, err := resolveDestStructure(opts.destStructure)
+	if err != nil {
+		return err
+	}
+	org.SetDestStructure(destStructure)
+
+	musicLayout, err := resolveMusicLayout()
+	if err != nil {
+		return err
+	}
+	org.SetMusicLayout(musicLayout)
+
+	looseTrackLayout, err := resolveLooseTrackLayout()
+	if err != nil {
+		return err
+	}
+	org.SetLooseTrackLayout(looseTrackLayout)
+
+	extrasLayout, err := resolveExtrasLayout()
+	if err != nil {
+		return err
+	}
+	org.SetExtrasLayout(extrasLayout)
+	org.SetSortArticles(sortArticles())
+
+	shardTypes, err := resolveShardTypes(opts.shardTypes)
+	if err != nil {
+		return err
+	}
+	org.SetFirstLetterShards(shardTypes, cfg.Naming.Articles)
+	org.SetTitleCase(cfg.Organize.NormalizeNames, cfg.Naming.SmallWords, cfg.Naming.Acronyms)
+	org.SetEpisodeTitleFallback(cfg.Naming.EpisodeTitleFallback)
+
+	unmappedMode, err := resolveUnmappedMode()
+	if err != nil {
+		return err
+	}
+	org.SetASCIIFold(cfg.Naming.ASCIIFold, unmappedMode)
+	org.SetIDTokens(cfg.Naming.IDTokens)
+
+	unknownYear, err := resolveUnknownYearPolicy()
+	if err != nil {
+		return err
+	}
+	org.SetUnknownYearPolicy(unknownYear, cfg.Naming.UnknownYearPlaceholder)
+
+	filenameStyle, err := resolveFilenameStyle()
+	if err != nil {
+		return err
+	}
+	org.SetFilenameStyle(filenameStyle)
+	org.SetExtrasDirs(cfg.Organize.ExtrasDirs)
+	org.SetReadMediaInfo(cfg.Organize.ReadMediaInfo)
+	org.SetPreferFolderNames(opts.folderNames || cfg.Organize.PreferFolderName)
+	org.SetProbeDuration(opts.probeDuration || cfg.Organize.ProbeDuration)
+
+	sampleMaxSize, skipSamples, err := resolveSampleFilter()
+	if err != nil {
+		return err
+	}
+	org.SetSampleFilter(sampleMaxSize, skipSamples)
+	org.SetErrorPolicy(errorPolicy)
+	hashAlgorithm, err := resolveHashAlgorithm()
+	if err != nil {
+		return err
+	}
+	org.SetHashFiles(opts.hash)
+	org.SetBackupBeforeMove(cfg.Safety.BackupBeforeMove)
+	org.SetHashAlgorithm(hashAlgorithm)
+	org.SetRenameOnly(opts.renameOnly)
+	org.SetCollisionLimit(cfg.Safety.CollisionLimit, cfg.Safety.CollisionHashFallback)
+	org.SetDestinations(destinations)
+
+	copyBufferSize, err := resolveCopyBufferSize()
+	if err != nil {
+		return err
+	}
+	var copyProgress safety.CopyProgressFunc
+	if !structured && !quiet {
+		copyProgress = copyProgressReporter()
+	}
+	org.SetCopyBuffer(copyBufferSize, copyProgress)
+
+	opsPerSec, bytesPerSec, err := resolveThrottle()
+	if err != nil {
+		return err
+	}
+	org.SetThrottle(opsPerSec, bytesPerSec)
 
-	if organizeCreateNFO {
+	if opts.createNFO {
 		log.Info().Msg("NFO file generation enabled")
 	}
 
+	var enrichers enricherSet
+	if opts.enrich {
+		if enrichers, err = setupEnrichers(); err != nil {
+			return err
+		}
+		org.SetEnricher(enrichers.enrichFunc())
+		org.SetEnrichTimeout(enrichers.timeout)
+	}
+
 	// Configure artwork downloads
-	if organizeDownloadArtwork {
+	if opts.downloadArtwork && offline {
+		log.Warn().Msg("Offline mode: skipping artwork downloads")
+	} else if opts.downloadArtwork {
 		var artworkSize artwork.ImageSize
-		switch organizeArtworkSize {
+		switch opts.artworkSize {
 		case "small":
 			artworkSize = artwork.SizeSmall
 		case "medium":
@@ -204,16 +596,50 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 			artworkSize = artwork.SizeMedium
 		}
 		org.SetDownloadArtwork(true, artworkSize)
-		log.Info().Str("size", organizeArtworkSize).Msg("Artwork download enabled")
+		org.SetTMDBImageBase(cfg.Artwork.TMDBImageBase)
+		org.SetGenerateThumbnails(cfg.Artwork.GenerateThumbnails)
+		org.SetActorImages(cfg.Artwork.ActorImages)
+
+		artworkFormat, err := resolveArtworkFormat()
+		if err != nil {
+			return err
+		}
+		org.SetArtworkFormat(artworkFormat)
+
+		posterSources, sourceKeys, err := resolveArtworkSources()
+		if err != nil {
+			return err
+		}
+		org.SetPosterSources(posterSources, sourceKeys)
+		log.Info().Str("size", opts.artworkSize).Msg("Artwork download enabled")
 	}
 
 	// Plan organization
 	fmt.Println("Planning organization...")
-	plans, err := org.PlanOrganization(result.Files, destRoot, mediaTypeFilter)
+	var plans []organizer.Plan
+	if manifest != nil {
+		plans, err = org.PlanManifest(manifest, destRoot, mediaTypeFilter)
+	} else {
+		plans, err = org.PlanOrganization(files, destRoot, mediaTypeFilter)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to plan organization: %w", err)
 	}
 
+	if n := org.AlreadyOrganized(); n > 0 {
+		fmt.Printf("%d files already organized, skipping\n", n)
+	}
+	if n := org.Unrouted(); n > 0 {
+		fmt.Printf("⚠ %d files skipped: no destination configured for their media type (use --dest or set destinations in the config file)\n", n)
+	}
+	if n := org.Yearless(); n > 0 {
+		fmt.Printf("⚠ %d files left in place: no year detected (naming.unknown_year is quarantine)\n", n)
+	}
+	if n := org.EnrichTimeouts(); n > 0 {
+		fmt.Printf("⚠ %d files planned from parsed metadata: enrichment timed out (enrich.per_item_timeout)\n", n)
+		stats.Add("enrichment_timeouts", n)
+	}
+
 	if len(plans) == 0 {
 		fmt.Println("No files match the criteria for organization.")
 		return nil
@@ -223,12 +649,49 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 
 	// Validate plans
 	validationErrors := org.ValidatePlan(plans)
+	if opts.validationReport != "" {
+		if err := organizer.WriteValidationReport(opts.validationReport, validationErrors); err != nil {
+			log.Error().Err(err).Str("path", opts.validationReport).Msg("Failed to write validation report")
+		} else {
+			fmt.Printf("Validation report written to: %s (%d errors)\n", opts.validationReport, len(validationErrors))
+		}
+	}
+	strictValidation := opts.strictValidation || cfg.Safety.StrictValidation
 	if len(validationErrors) > 0 {
 		fmt.Printf("⚠ Warning: %d validation errors found:\n", len(validationErrors))
 		for _, err := range validationErrors {
 			fmt.Printf("  - %v\n", err)
 		}
-		fmt.Println("\nProceeding with valid files only...")
+		if !strictValidation {
+			fmt.Println("\nProceeding with valid files only...")
+		}
+	}
+	if plans, err = applyValidation(plans, validationErrors, strictValidation); err != nil {
+		return err
+	}
+	if len(plans) == 0 {
+		fmt.Println("No valid files to organize.")
+		return nil
+	}
+
+	var remaining int
+	if plans, remaining = limitPlans(plans, opts.limit); remaining > 0 {
+		fmt.Printf("Limited to %d files (--limit); %d left for a later run\n", len(plans), remaining)
+		stats.Add("files_remaining", remaining)
+	}
+
+	for _, plan := range plans {
+		for _, warning := range plan.Warnings {
+			fmt.Printf("⚠ Warning: %s: %s\n", filepath.Base(plan.SourcePath), warning)
+		}
+	}
+
+	// Detect read-only or unwritable destinations once, before any file is touched
+	if err := org.PreflightDestinations(plans); err != nil {
+		if !opts.dryRun {
+			return fmt.Errorf("destination check failed: %w", err)
+		}
+		fmt.Printf("⚠ Warning: %v\n", err)
 	}
 
 	// Count by type and conflicts
@@ -270,16 +733,32 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Books: %d\n", bookCount)
 	}
 
+	if destRoot == "" {
+		fmt.Println("\nDestinations:")
+		for _, mediaType := range usedMediaTypes(plans) {
+			fmt.Printf("  %-6s → %s\n", mediaType, destinations[mediaType])
+		}
+	}
+	if mixes := organizer.MixedFolders(plans); len(mixes) > 0 {
+		fmt.Printf("\nMixed folders: %d (files are routed by type)\n", len(mixes))
+		for _, mix := range mixes {
+			fmt.Printf("  %s: %s\n", mix.Dir, formatTypeCounts(mix.Counts))
+		}
+	}
+
 	if conflictCount > 0 {
-		fmt.Printf("\n⚠ Conflicts: %d (strategy: %s)\n", conflictCount, organizeConflictStrategy)
+		fmt.Printf("\n⚠ Conflicts: %d (strategy: %s)\n", conflictCount, opts.conflictStrategy)
 	}
-	if !organizeJSONOutput {
+	if collisions := countPlanCollisions(plans); collisions > 0 {
+		fmt.Printf("⚠ Plan collisions: %d file(s) want a destination an earlier file already claimed\n", collisions)
+	}
+	if !structured {
 		fmt.Println()
 	}
 
 	// Execute organization with progress tracking
-	if !organizeJSONOutput {
-		if organizeDryRun {
+	if !structured {
+		if opts.dryRun {
 			fmt.Println("Simulating file operations...")
 		} else {
 			fmt.Println("Organizing files...")
@@ -287,8 +766,21 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 	}
 
 	// Handle interactive conflict resolution
-	if organizeConflictStrategy == "interactive" && !organizeDryRun {
-		plans = handleInteractiveConflicts(plans)
+	if opts.conflictStrategy == "interactive" && !opts.dryRun {
+		plans = resolveInteractiveConflicts(plans, org.RecordCollision)
+	}
+
+	// Keep Jellyfin out of the sources and staging while files move
+	var markedSources []string
+	if !opts.dryRun && (markSources || markStaging) {
+		var staging []string
+		if markStaging {
+			staging = planRoots(destRoot, destinations, plans)
+		}
+		if markSources {
+			markedSources = markableSources(sources, libraryRoots(destRoot, destinations))
+		}
+		writeIgnoreMarkers(append(staging, markedSources...))
 	}
 
 	var ops []types.Operation
@@ -296,14 +788,15 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 
 	// Use the actual conflict strategy for execution
 	// If interactive, conflicts have been resolved, so use "skip" for any remaining
-	execStrategy := organizeConflictStrategy
-	if organizeConflictStrategy == "interactive" {
+	execStrategy := opts.conflictStrategy
+	if opts.conflictStrategy == "interactive" {
 		execStrategy = "skip" // Interactive conflicts already resolved
 	}
 
 	execTimer := stats.NewTimer("execution")
 	if tm != nil {
 		txnID, ops, err = org.ExecuteWithTransaction(plans, execStrategy)
+		run.TransactionID = txnID
 		if err != nil {
 			execTimer.Stop()
 			return fmt.Errorf("organization failed: %w", err)
@@ -317,6 +810,15 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 	}
 	execTimer.Stop()
 
+	// Write collision audit trail if requested
+	if opts.collisionLog != "" {
+		if err := organizer.WriteCollisionLog(opts.collisionLog, org.Collisions()); err != nil {
+			log.Error().Err(err).Str("path", opts.collisionLog).Msg("Failed to write collision log")
+		} else if !structured {
+			fmt.Printf("Collision log written to: %s (%d entries)\n", opts.collisionLog, len(org.Collisions()))
+		}
+	}
+
 	// Count results and update statistics
 	successCount := 0
 	failedCount := 0
@@ -346,12 +848,24 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 	stats.Add("files_skipped", skippedCount)
 	stats.AddSize("total_bytes", totalBytes)
 
+	run.Organized, run.Failed, run.Skipped = successCount, failedCount, skippedCount
+	run.DestRoots = planRoots(destRoot, destinations, plans)
+	for _, op := range ops {
+		if op.Status == types.OperationStatusFailed {
+			failure := notify.Failure{Source: op.Source}
+			if op.Error != nil {
+				failure.Error = op.Error.Error()
+			}
+			run.Failures = append(run.Failures, failure)
+		}
+	}
+
 	// Display results
-	if !organizeJSONOutput {
+	if !structured {
 		fmt.Println()
 		fmt.Println("Results:")
 		fmt.Println("========")
-		if organizeDryRun {
+		if opts.dryRun {
 			fmt.Printf("Would organize: %d files\n", successCount)
 		} else {
 			fmt.Printf("✓ Successfully organized: %d files\n", successCount)
@@ -362,6 +876,9 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 		if skippedCount > 0 {
 			fmt.Printf("⊘ Skipped: %d files\n", skippedCount)
 		}
+		if remaining > 0 {
+			fmt.Printf("⏭ Remaining: %d files (--limit %d)\n", remaining, opts.limit)
+		}
 	}
 
 	// Display failures if any
@@ -376,45 +893,193 @@ func runOrganize(cmd *cobra.Command, args []string) error {
 	}
 
 	// Display transaction ID if available
-	if txnID != "" && !organizeJSONOutput {
+	if txnID != "" && !structured {
 		fmt.Printf("\nTransaction ID: %s\n", txnID)
-		fmt.Printf("To rollback this operation, run: go-jf-org rollback %s\n", txnID)
+		if fallback := tm.FallbackPath(); fallback != "" {
+			fmt.Printf("⚠ The transaction log directory was unavailable; the log was saved to:\n  %s\n", fallback)
+			fmt.Printf("Copy it into %s before running: go-jf-org rollback %s\n", tm.LogDir(), txnID)
+		} else {
+			fmt.Printf("To rollback this operation, run: go-jf-org rollback %s\n", txnID)
+		}
 	}
 
 	// Success message
-	if successCount > 0 && !organizeDryRun && !organizeJSONOutput {
-		fmt.Printf("\n✓ Organization complete! Files are now in:\n")
-		fmt.Printf("  %s\n", destRoot)
+	if successCount > 0 && !opts.dryRun && !structured {
+		if opts.stage {
+			fmt.Printf("\n✓ Organization staged for review in:\n")
+			for _, root := range planRoots(destRoot, destinations, plans) {
+				fmt.Printf("  %s\n", root)
+			}
+			if txnID != "" {
+				fmt.Printf("To merge the staged files into the library, run: go-jf-org transactions merge-staging %s\n", txnID)
+			}
+		} else {
+			fmt.Printf("\n✓ Organization complete! Files are now in:\n")
+			for _, root := range planRoots(destRoot, destinations, plans) {
+				fmt.Printf("  %s\n", root)
+			}
+		}
 	}
 
-	if organizeDryRun && !organizeJSONOutput {
+	if opts.dryRun && !structured {
 		fmt.Println("\nTo execute this organization, run the same command without --dry-run")
 	}
 
+	// Tidy the folders files were moved out of, only when nothing failed
+	if cleanSources && !opts.dryRun && successCount > 0 && failedCount == 0 {
+		cleanup := safety.CleanSources(movedFromDirs(ops), sources, cfg.Cleanup.JunkPatterns, cleanupMode, trashDir, time.Now())
+		for _, err := range cleanup.Errors {
+			log.Warn().Err(err).Msg("Source cleanup incomplete")
+		}
+		if !structured {
+			printCleanup(cleanup, cleanupMode, trashDir)
+		}
+		if cfg.Markers.RemoveWhenEmpty {
+			for _, dir := range markedSources {
+				if _, err := safety.RemoveIgnoreMarkerIfAlone(dir); err != nil {
+					log.Warn().Err(err).Msg("Failed to remove source marker")
+				}
+			}
+		}
+	}
+
+	// Run the user's hook after a run with no failures; its failure is
+	// reported but nothing is undone
+	afterHook := opts.afterHook
+	if afterHook == "" {
+		afterHook = cfg.Organize.AfterHook
+	}
+	hookOut := io.Writer(os.Stdout)
+	if structured {
+		hookOut = os.Stderr // keep structured output parseable
+	}
+	hook := hookRun{
+		TransactionID: txnID,
+		Organized:     successCount,
+		Failed:        failedCount,
+		Skipped:       skippedCount,
+		DestRoots:     planRoots(destRoot, destinations, plans),
+	}
+	if ran, err := runAfterHookOnSuccess(afterHook, opts.dryRun, hook, hookOut, os.Stderr); err != nil {
+		log.Warn().Err(err).Msg("After hook failed; the organization was kept")
+		if !structured {
+			fmt.Printf("⚠ %v (the organization was kept)\n", err)
+		}
+	} else if !ran && afterHook != "" && !opts.dryRun {
+		log.Warn().Int("failed", failedCount).Msg("Skipped after hook because files failed")
+		if !structured {
+			fmt.Printf("⚠ After hook skipped: %d files failed\n", failedCount)
+		}
+	}
+
 	// Finalize and display statistics
+	enrichers.recordAPIUsage(stats)
 	stats.Finish()
 
-	if organizeJSONOutput {
-		jsonStr, err := stats.ToJSON()
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to generate JSON statistics")
-		} else {
-			fmt.Fprintln(os.Stdout, jsonStr)
+	if structured {
+		return printResult(format, stats.Report())
+	}
+
+	// Show summary statistics
+	fmt.Println()
+	fmt.Printf("Completed in %s\n", util.FormatDuration(stats.Duration))
+	if totalBytes > 0 {
+		fmt.Printf("Total data processed: %s\n", util.FormatBytes(totalBytes))
+	}
+	enrichers.printAPIUsage(stats)
+
+	return nil
+}
+
+// applyValidation decides what runs after ValidatePlan: with strict set
+// any validation error aborts the run, otherwise the plans that failed are
+// dropped and the rest go ahead
+func applyValidation(plans []organizer.Plan, validationErrors []error, strict bool) ([]organizer.Plan, error) {
+	if len(validationErrors) == 0 {
+		return plans, nil
+	}
+	if strict {
+		return nil, fmt.Errorf("%d planned files failed validation; nothing was organized (--strict-validation)", len(validationErrors))
+	}
+	return organizer.ExcludeInvalid(plans, validationErrors), nil
+}
+
+// limitPlans keeps the first limit plans by source path, so repeated runs
+// work through a backlog in the same order, and returns how many were left
+// out. Kept plans stay in plan order, which decides plan collisions. A
+// limit of 0 keeps everything.
+func limitPlans(plans []organizer.Plan, limit int) ([]organizer.Plan, int) {
+	if limit <= 0 || len(plans) <= limit {
+		return plans, 0
+	}
+
+	sources := make([]string, len(plans))
+	for i, plan := range plans {
+		sources[i] = plan.SourcePath
+	}
+	sort.Strings(sources)
+	selected := make(map[string]bool, limit)
+	for _, source := range sources[:limit] {
+		selected[source] = true
+	}
+
+	kept := make([]organizer.Plan, 0, limit)
+	for _, plan := range plans {
+		if selected[plan.SourcePath] {
+			kept = append(kept, plan)
 		}
-	} else {
-		// Show summary statistics
-		fmt.Println()
-		fmt.Printf("Completed in %s\n", util.FormatDuration(stats.Duration))
-		if totalBytes > 0 {
-			fmt.Printf("Total data processed: %s\n", util.FormatBytes(totalBytes))
+	}
+	return kept, len(plans) - len(kept)
+}
+
+// movedFromDirs returns the directories completed moves took files out of
+func movedFromDirs(ops []types.Operation) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, op := range ops {
+		if op.Type != types.OperationMove || op.Status != types.OperationStatusCompleted {
+			continue
+		}
+		if dir := filepath.Dir(op.Source); !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
 		}
 	}
+	return dirs
+}
 
-	return nil
+// printCleanup summarizes the source cleanup
+func printCleanup(cleanup safety.CleanupResult, mode safety.CleanupMode, trashDir string) {
+	if len(cleanup.Junk) == 0 && len(cleanup.RemovedDirs) == 0 && len(cleanup.Errors) == 0 {
+		return
+	}
+	fmt.Println()
+	if len(cleanup.Junk) > 0 {
+		if mode == safety.CleanupRemove {
+			fmt.Printf("✓ Removed %d junk file(s) from source folders\n", len(cleanup.Junk))
+		} else {
+			fmt.Printf("✓ Moved %d junk file(s) from source folders to %s\n", len(cleanup.Junk), trashDir)
+		}
+	}
+	if len(cleanup.RemovedDirs) > 0 {
+		fmt.Printf("✓ Removed %d empty source folder(s)\n", len(cleanup.RemovedDirs))
+	}
+	if len(cleanup.Errors) > 0 {
+		fmt.Printf("⚠ %d junk file(s) or folder(s) could not be cleaned (see log)\n", len(cleanup.Errors))
+	}
 }
 
 // handleInteractiveConflicts processes plans with conflicts and prompts user for resolution
 func handleInteractiveConflicts(plans []organizer.Plan) []organizer.Plan {
+	return resolveInteractiveConflicts(plans, nil)
+}
+
+// resolveInteractiveConflicts prompts for each conflict and reports every
+// resolution to record (if non-nil) for the collision log
+func resolveInteractiveConflicts(plans []organizer.Plan, record func(organizer.Collision)) []organizer.Plan {
+	if record == nil {
+		record = func(organizer.Collision) {}
+	}
 	skipAll := false
 	result := make([]organizer.Plan, 0, len(plans))
 
@@ -425,9 +1090,18 @@ func handleInteractiveConflicts(plans []organizer.Plan) []organizer.Plan {
 			continue
 		}
 
+		collision := organizer.Collision{
+			Source:              plan.SourcePath,
+			IntendedDestination: plan.DestinationPath,
+			Kind:                plan.CollisionKind(),
+			Strategy:            "interactive",
+			Resolution:          organizer.CollisionSkipped,
+		}
+
 		// Skip remaining conflicts if user chose "skip all"
 		if skipAll {
 			log.Info().Str("file", plan.SourcePath).Msg("Skipping due to 'skip all' choice")
+			record(collision)
 			continue
 		}
 
@@ -444,18 +1118,25 @@ func handleInteractiveConflicts(plans []organizer.Plan) []organizer.Plan {
 			// Don't add to result - file will be skipped
 		case "rename":
 			// Add suffix to destination
-			newPath, err := findAvailableName(plan.DestinationPath)
+			newPath, err := availableName(plan.DestinationPath, plan.SourcePath)
 			if err != nil {
 				log.Error().Err(err).Str("file", plan.SourcePath).Msg("Failed to find available name, skipping")
+				collision.Resolution = organizer.CollisionFailed
+				collision.Error = err.Error()
+				record(collision)
 				continue
 			}
 			plan.DestinationPath = newPath
 			plan.Conflict = false // Conflict resolved
 			log.Info().Str("file", plan.SourcePath).Str("new_dest", plan.DestinationPath).Msg("User chose to rename")
+			collision.Resolution = organizer.CollisionRenamed
+			collision.FinalDestination = newPath
 			result = append(result, plan)
 		default:
 			log.Warn().Str("file", plan.SourcePath).Str("choice", choice).Msg("Unknown choice, skipping")
 		}
+
+		record(collision)
 	}
 
 	return result
@@ -463,15 +1144,74 @@ func handleInteractiveConflicts(plans []organizer.Plan) []organizer.Plan {
 
 // findAvailableName finds an available filename by adding -1, -2, etc suffix
 func findAvailableName(path string) (string, error) {
-	ext := filepath.Ext(path)
-	base := path[:len(path)-len(ext)]
+	return availableName(path, "")
+}
+
+// availableName finds a free destination for source using the configured
+// collision limit and hash fallback
+func availableName(path, source string) (string, error) {
+	limit, hashFallback := organizer.DefaultCollisionLimit, false
+	if cfg != nil {
+		limit, hashFallback = cfg.Safety.CollisionLimit, cfg.Safety.CollisionHashFallback
+	}
+	return organizer.AvailableName(path, source, limit, hashFallback)
+}
+
+// mediaTypeOrder is the order media types are listed in summaries
+var mediaTypeOrder = []types.MediaType{
+	types.MediaTypeMovie,
+	types.MediaTypeTV,
+	types.MediaTypeMusic,
+	types.MediaTypeBook,
+}
 
-	for i := 1; i < 1000; i++ {
-		newPath := fmt.Sprintf("%s-%d%s", base, i, ext)
-		if _, err := os.Stat(newPath); os.IsNotExist(err) {
-			return newPath, nil
+// countPlanCollisions counts plans flagged because an earlier plan claimed
+// their destination
+func countPlanCollisions(plans []organizer.Plan) int {
+	count := 0
+	for _, plan := range plans {
+		if plan.ConflictKind == organizer.CollisionPlanCollision {
+			count++
 		}
 	}
+	return count
+}
 
-	return "", fmt.Errorf("could not find available filename after 1000 attempts")
+// usedMediaTypes returns the media types present in plans, in summary order
+func usedMediaTypes(plans []organizer.Plan) []types.MediaType {
+	present := make(map[types.MediaType]bool)
+	for _, plan := range plans {
+		present[plan.MediaType] = true
+	}
+	var used []types.MediaType
+	for _, mediaType := range mediaTypeOrder {
+		if present[mediaType] {
+			used = append(used, mediaType)
+		}
+	}
+	return used
+}
+
+// formatTypeCounts renders per-type counts as "1 movie, 2 music, 1 book"
+func formatTypeCounts(counts map[types.MediaType]int) string {
+	var parts []string
+	for _, mediaType := range mediaTypeOrder {
+		if n := counts[mediaType]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, mediaType))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// planRoots returns the library roots files were organized into: destRoot
+// when one was given, otherwise the destination of each type in plans
+func planRoots(destRoot string, destinations map[types.MediaType]string, plans []organizer.Plan) []string {
+	if destRoot != "" {
+		return []string{destRoot}
+	}
+	var roots []string
+	for _, mediaType := range usedMediaTypes(plans) {
+		roots = append(roots, destinations[mediaType])
+	}
+	return roots
 }
@@ -239,3 +239,94 @@ func TestPromptConflictResolution(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyValidation(t *testing.T) {
+	tmpDir := t.TempDir()
+	valid := filepath.Join(tmpDir, "Valid.2020.mkv")
+	if err := os.WriteFile(valid, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plans := []organizer.Plan{
+		{SourcePath: valid, DestinationPath: filepath.Join(tmpDir, "dest", "Valid (2020).mkv")},
+		{SourcePath: filepath.Join(tmpDir, "Gone.2020.mkv"), DestinationPath: filepath.Join(tmpDir, "dest", "Gone (2020).mkv")},
+	}
+	errs := organizer.NewOrganizer(false).ValidatePlan(plans)
+
+	t.Run("strict aborts", func(t *testing.T) {
+		kept, err := applyValidation(plans, errs, true)
+		if err == nil || !strings.Contains(err.Error(), "1 planned files failed validation") {
+			t.Errorf("applyValidation() error = %v, want the run aborted", err)
+		}
+		if len(kept) != 0 {
+			t.Errorf("applyValidation() kept %d plans, want none", len(kept))
+		}
+	})
+
+	t.Run("lenient continues with valid plans", func(t *testing.T) {
+		kept, err := applyValidation(plans, errs, false)
+		if err != nil {
+			t.Fatalf("applyValidation() error = %v", err)
+		}
+		if len(kept) != 1 || kept[0].SourcePath != valid {
+			t.Errorf("applyValidation() = %v, want only %s", kept, valid)
+		}
+	})
+
+	t.Run("strict passes a clean plan", func(t *testing.T) {
+		kept, err := applyValidation(plans[:1], nil, true)
+		if err != nil || len(kept) != 1 {
+			t.Errorf("applyValidation() = %v, %v; want the plan kept", kept, err)
+		}
+	})
+}
+
+func TestLimitPlans(t *testing.T) {
+	srcDir := t.TempDir()
+	destDir := t.TempDir()
+	var files []string
+	for _, name := range []string{"Gamma.2022.mkv", "Alpha.2020.mkv", "Delta.2023.mkv", "Beta.2021.mkv"} {
+		path := filepath.Join(srcDir, name)
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+
+	org := organizer.NewOrganizer(false)
+	plans, err := org.PlanOrganization(files, destDir, "")
+	if err != nil || len(plans) != 4 {
+		t.Fatalf("PlanOrganization() = %d plans, %v; want 4", len(plans), err)
+	}
+
+	kept, remaining := limitPlans(plans, 2)
+	if len(kept) != 2 || remaining != 2 {
+		t.Fatalf("limitPlans() = %d plans, %d remaining; want 2 and 2", len(kept), remaining)
+	}
+
+	ops, err := org.Execute(kept, "skip")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(ops) != 2 {
+		t.Errorf("Execute() ran %d operations, want 2", len(ops))
+	}
+
+	// The first files by source path are organized, the rest left in place
+	for _, name := range []string{"Alpha.2020.mkv", "Beta.2021.mkv"} {
+		if _, err := os.Stat(filepath.Join(srcDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s was not organized", name)
+		}
+	}
+	for _, name := range []string{"Gamma.2022.mkv", "Delta.2023.mkv"} {
+		if _, err := os.Stat(filepath.Join(srcDir, name)); err != nil {
+			t.Errorf("%s should be left for a later run: %v", name, err)
+		}
+	}
+
+	if kept, remaining := limitPlans(plans, 0); len(kept) != 4 || remaining != 0 {
+		t.Errorf("limitPlans(0) = %d plans, %d remaining; want all plans", len(kept), remaining)
+	}
+	if kept, remaining := limitPlans(plans, 10); len(kept) != 4 || remaining != 0 {
+		t.Errorf("limitPlans(10) = %d plans, %d remaining; want all plans", len(kept), remaining)
+	}
+}
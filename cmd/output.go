@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/internal/output"
+)
+
+// resolveOutputFormat returns the result format from --output; a command's
+// own --json flag, kept for compatibility, selects JSON
+func resolveOutputFormat(jsonFlag bool) (output.Format, error) {
+	if jsonFlag {
+		return output.FormatJSON, nil
+	}
+	return output.ParseFormat(outputFlag)
+}
+
+// printResult writes a command result to stdout in the given format
+func printResult(format output.Format, r output.Result) error {
+	if err := output.New(format).Format(os.Stdout, r); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	return nil
+}
+
+// Rows lists one row per planned file
+func (r previewReport) Rows() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.Files))
+	for _, f := range r.Files {
+		conflict := ""
+		if f.Conflict {
+			conflict = f.ConflictReason
+		}
+		rows = append(rows, []string{string(f.MediaType), f.Source, f.Destination, conflict, strings.Join(f.Warnings, "; ")})
+	}
+	return []string{"type", "source", "destination", "conflict", "warnings"}, rows
+}
+
+// Rows lists one row per violation
+func (r verifyReport) Rows() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.Violations))
+	for _, v := range r.Violations {
+		rows = append(rows, []string{string(v.Severity), string(v.MediaType), v.Path, v.Message, v.Suggestion})
+	}
+	return []string{"severity", "type", "path", "message", "suggestion"}, rows
+}
+
+// Rows lists one row per duplicate file, the best copy of each group first
+func (r duplicateReport) Rows() ([]string, [][]string) {
+	var rows [][]string
+	for _, g := range r.Groups {
+		for _, f := range g.Files {
+			best := ""
+			if f.Best {
+				best = "yes"
+			}
+			rows = append(rows, []string{g.Key, f.Path, strconv.FormatInt(f.Size, 10), f.Quality, f.ReleaseGroup, best})
+		}
+	}
+	return []string{"group", "path", "size", "quality", "release_group", "best"}, rows
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
+	"github.com/opd-ai/go-jf-org/internal/jellyfin"
 	"github.com/opd-ai/go-jf-org/internal/organizer"
+	"github.com/opd-ai/go-jf-org/internal/output"
+	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
@@ -16,8 +19,73 @@ var (
 	previewMediaType        string
 	previewConflictStrategy string
 	previewCreateNFO        bool
+	previewFlatten          bool
+	previewFolderNames      bool
+	previewProbeDuration    bool
+	previewDestStructure    string
+	previewJSONOutput       bool
+	previewRenameOnly       bool
+	previewShardTypes       []string
+	previewEnrich           bool
 )
 
+// previewReport is the machine-readable form of an organization preview
+type previewReport struct {
+	SchemaVersion int    `json:"schema_version"`
+	Source        string `json:"source"`
+	Destination   string `json:"destination"`
+	// Destinations holds the per-type roots used when Destination is empty
+	Destinations     map[types.MediaType]string `json:"destinations,omitempty"`
+	Filter           string                     `json:"filter,omitempty"`
+	ConflictStrategy string                     `json:"conflict_strategy"`
+	Summary          previewSummary             `json:"summary"`
+	Warnings         []string                   `json:"warnings,omitempty"`
+	Files            []previewFile              `json:"files"`
+}
+
+// previewSummary counts planned files by media type
+type previewSummary struct {
+	Total     int `json:"total"`
+	Movies    int `json:"movies"`
+	TV        int `json:"tv"`
+	Music     int `json:"music"`
+	Books     int `json:"books"`
+	Conflicts int `json:"conflicts"`
+}
+
+// previewFile describes what will happen to a single file
+type previewFile struct {
+	Source         string          `json:"source"`
+	Destination    string          `json:"destination"`
+	MediaType      types.MediaType `json:"media_type"`
+	Metadata       previewMetadata `json:"metadata"`
+	Conflict       bool            `json:"conflict,omitempty"`
+	ConflictReason string          `json:"conflict_reason,omitempty"`
+	ConflictKind   string          `json:"conflict_kind,omitempty"`
+	Subtitles      []string        `json:"subtitles,omitempty"`
+	Extras         []string        `json:"extras,omitempty"`
+	Warnings       []string        `json:"warnings,omitempty"`
+}
+
+// previewMetadata holds the parsed metadata relevant to naming
+type previewMetadata struct {
+	Title        string `json:"title,omitempty"`
+	Year         int    `json:"year,omitempty"`
+	Quality      string `json:"quality,omitempty"`
+	Source       string `json:"source,omitempty"`
+	Codec        string `json:"codec,omitempty"`
+	ReleaseGroup string `json:"release_group,omitempty"`
+	Season       int    `json:"season,omitempty"`
+	Episode      int    `json:"episode,omitempty"`
+	EpisodeEnd   int    `json:"episode_end,omitempty"`
+	AirDate      string `json:"air_date,omitempty"`
+	EpisodeTitle string `json:"episode_title,omitempty"`
+	Artist       string `json:"artist,omitempty"`
+	Album        string `json:"album,omitempty"`
+	Track        int    `json:"track,omitempty"`
+	Author       string `json:"author,omitempty"`
+}
+
 var previewCmd = &cobra.Command{
 	Use:   "preview [directory]",
 	Short: "Preview file organization without making changes",
@@ -25,7 +93,9 @@ var previewCmd = &cobra.Command{
 without actually performing any operations. This is useful for verifying
 the organization plan before executing it.
 
-The preview command is equivalent to running organize with --dry-run flag.`,
+For each file the preview lists the detected media type, the parsed metadata
+and the target path. Use --json for machine-readable output. No transaction
+is created and nothing is written.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runPreview,
 }
@@ -37,21 +107,55 @@ func init() {
 	previewCmd.Flags().StringVarP(&previewMediaType, "type", "t", "", "filter by media type (movie, tv, music, book)")
 	previewCmd.Flags().StringVar(&previewConflictStrategy, "conflict", "skip", "conflict resolution strategy (skip, rename, interactive)")
 	previewCmd.Flags().BoolVar(&previewCreateNFO, "create-nfo", false, "preview NFO file creation")
+	previewCmd.Flags().BoolVar(&previewProbeDuration, "probe-duration", false, "read the running time of videos whose name could be a movie or a TV episode to decide which (default organize.probe_duration)")
+	previewCmd.Flags().BoolVar(&previewFolderNames, "prefer-folder-name", false, "take music artist and album from Artist/Album/ or \"Artist - Album\"/ folders instead of filenames (default organize.prefer_folder_name)")
+	previewCmd.Flags().BoolVar(&previewFlatten, "flatten", false, "place movies directly in the destination root without per-movie folders")
+	previewCmd.Flags().StringVar(&previewDestStructure, "dest-structure", "", "library layout: nested (Jellyfin folders) or flat-by-type (every file directly in its library root); default from config")
+	previewCmd.Flags().StringSliceVar(&previewShardTypes, "group-by-first-letter", nil, "preview folders in first-letter buckets (Movies/M/The Matrix (1999)/); alone for every type, or =movie,music for some (default organize.group_by_first_letter)")
+	previewCmd.Flags().Lookup("group-by-first-letter").NoOptDefVal = "movie,tv,music,book"
+	previewCmd.Flags().BoolVar(&previewRenameOnly, "rename-only", false, "preview renaming files in place without moving them to a destination root")
+	previewCmd.Flags().BoolVar(&previewEnrich, "enrich", false, "look up metadata from external APIs before naming, as 'organize --enrich' does")
+	previewCmd.Flags().BoolVar(&previewJSONOutput, "json", false, "output the plan in JSON format (same as --output json)")
+	addExtensionFlags(previewCmd)
+	addMaxDepthFlag(previewCmd)
+	addIOWorkersFlag(previewCmd)
+	addCheckReadableFlag(previewCmd)
+	addMinConfidenceFlag(previewCmd)
 }
 
 func runPreview(cmd *cobra.Command, args []string) error {
 	scanPath := args[0]
 
+	format, err := resolveOutputFormat(previewJSONOutput)
+	if err != nil {
+		return err
+	}
+
 	// Make path absolute
 	absPath, err := filepath.Abs(scanPath)
 	if err != nil {
 		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 
-	// Determine destination root
-	destRoot, err := getDestinationRoot(previewMediaType, previewDest)
-	if err != nil {
-		return err
+	// Determine destination root (files stay under the source in rename-only
+	// mode, and go to per-type destinations without --dest or --type)
+	destRoot := absPath
+	var destinations map[types.MediaType]string
+	if previewRenameOnly {
+		if previewDest != "" {
+			return fmt.Errorf("--rename-only cannot be combined with --dest")
+		}
+	} else if previewDest == "" && previewMediaType == "" {
+		destRoot = ""
+		destinations = configuredDestinations()
+		if len(destinations) == 0 {
+			return fmt.Errorf("destination directory required (use --dest or configure in config file)")
+		}
+	} else {
+		destRoot, err = getDestinationRoot(previewMediaType, previewDest)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Parse media type filter
@@ -63,7 +167,10 @@ func runPreview(cmd *cobra.Command, args []string) error {
 	log.Info().Str("path", absPath).Str("dest", destRoot).Msg("Starting preview")
 
 	// Create scanner
-	s := createScanner()
+	s, err := createScanner()
+	if err != nil {
+		return err
+	}
 
 	// Scan for files
 	result, err := s.Scan(absPath)
@@ -72,6 +179,14 @@ func runPreview(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(result.Files) == 0 {
+		if format != output.FormatText {
+			report := buildPreviewReport(absPath, destRoot, mediaTypeFilter, nil)
+			report.Warnings = append(report.Warnings, skippedWarnings(result.Skipped)...)
+			report.Warnings = append(report.Warnings, archiveWarnings(result.Archives)...)
+			return printPreviewReport(format, report)
+		}
+		printSkippedFiles(result.Skipped)
+		printArchives(result.Archives)
 		fmt.Println("No media files found to organize.")
 		return nil
 	}
@@ -80,23 +195,139 @@ func runPreview(cmd *cobra.Command, args []string) error {
 	org := organizer.NewOrganizer(true)
 	org.SetCreateNFO(previewCreateNFO)
 
+	movieLayout, err := resolveMovieLayout(previewFlatten)
+	if err != nil {
+		return err
+	}
+	org.SetMovieLayout(movieLayout)
+
+	miniSeriesLayout, err := resolveMiniSeriesLayout()
+	if err != nil {
+		return err
+	}
+	org.SetMiniSeriesLayout(miniSeriesLayout)
+
+	destStructure, err := resolveDestStructure(previewDestStructure)
+	if err != nil {
+		return err
+	}
+	org.SetDestStructure(destStructure)
+
+	musicLayout, err := resolveMusicLayout()
+	if err != nil {
+		return err
+	}
+	org.SetMusicLayout(musicLayout)
+
+	looseTrackLayout, err := resolveLooseTrackLayout()
+	if err != nil {
+		return err
+	}
+	org.SetLooseTrackLayout(looseTrackLayout)
+
+	extrasLayout, err := resolveExtrasLayout()
+	if err != nil {
+		return err
+	}
+	org.SetExtrasLayout(extrasLayout)
+	org.SetSortArticles(sortArticles())
+
+	shardTypes, err := resolveShardTypes(previewShardTypes)
+	if err != nil {
+		return err
+	}
+	org.SetFirstLetterShards(shardTypes, cfg.Naming.Articles)
+	org.SetTitleCase(cfg.Organize.NormalizeNames, cfg.Naming.SmallWords, cfg.Naming.Acronyms)
+	org.SetRenameOnly(previewRenameOnly)
+	org.SetEpisodeTitleFallback(cfg.Naming.EpisodeTitleFallback)
+
+	unmappedMode, err := resolveUnmappedMode()
+	if err != nil {
+		return err
+	}
+	org.SetASCIIFold(cfg.Naming.ASCIIFold, unmappedMode)
+	org.SetIDTokens(cfg.Naming.IDTokens)
+
+	unknownYear, err := resolveUnknownYearPolicy()
+	if err != nil {
+		return err
+	}
+	org.SetUnknownYearPolicy(unknownYear, cfg.Naming.UnknownYearPlaceholder)
+
+	filenameStyle, err := resolveFilenameStyle()
+	if err != nil {
+		return err
+	}
+	org.SetFilenameStyle(filenameStyle)
+	org.SetExtrasDirs(cfg.Organize.ExtrasDirs)
+	org.SetReadMediaInfo(cfg.Organize.ReadMediaInfo)
+	org.SetPreferFolderNames(previewFolderNames || cfg.Organize.PreferFolderName)
+	org.SetProbeDuration(previewProbeDuration || cfg.Organize.ProbeDuration)
+
+	if previewEnrich {
+		enrichers, err := setupEnrichers()
+		if err != nil {
+			return err
+		}
+		org.SetEnricher(enrichers.enrichFunc())
+		org.SetEnrichTimeout(enrichers.timeout)
+	}
+
+	sampleMaxSize, skipSamples, err := resolveSampleFilter()
+	if err != nil {
+		return err
+	}
+	org.SetSampleFilter(sampleMaxSize, skipSamples)
+	org.SetDestinations(destinations)
+
 	// Plan organization
 	plans, err := org.PlanOrganization(result.Files, destRoot, mediaTypeFilter)
 	if err != nil {
 		return fmt.Errorf("failed to plan organization: %w", err)
 	}
 
+	report := buildPreviewReport(absPath, destRoot, mediaTypeFilter, plans)
+	report.Warnings = append(report.Warnings, skippedWarnings(result.Skipped)...)
+	report.Warnings = append(report.Warnings, archiveWarnings(result.Archives)...)
+	if destRoot == "" {
+		report.Destinations = make(map[types.MediaType]string)
+		for _, mediaType := range usedMediaTypes(plans) {
+			report.Destinations[mediaType] = destinations[mediaType]
+		}
+	}
+	if n := org.Unrouted(); n > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d files have no destination configured for their media type", n))
+	}
+	if n := org.Yearless(); n > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d files have no year and will be left in place", n))
+	}
+	if n := org.EnrichTimeouts(); n > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d files timed out enriching and use parsed metadata", n))
+	}
+
+	// Validate plans
+	for _, err := range org.ValidatePlan(plans) {
+		report.Warnings = append(report.Warnings, err.Error())
+	}
+
+	// Warn early if the destination cannot be written to
+	if err := org.PreflightDestinations(plans); err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%v (organize would abort)", err))
+	}
+
+	if format != output.FormatText {
+		return printPreviewReport(format, report)
+	}
+
 	if len(plans) == 0 {
 		fmt.Println("No files match the criteria for organization.")
 		return nil
 	}
 
-	// Validate plans
-	validationErrors := org.ValidatePlan(plans)
-	if len(validationErrors) > 0 {
-		fmt.Printf("\n⚠ Warning: %d validation errors found:\n", len(validationErrors))
-		for _, err := range validationErrors {
-			fmt.Printf("  - %v\n", err)
+	if len(report.Warnings) > 0 {
+		fmt.Printf("\n⚠ Warning: %d issues found:\n", len(report.Warnings))
+		for _, warning := range report.Warnings {
+			fmt.Printf("  - %s\n", warning)
 		}
 		fmt.Println()
 	}
@@ -105,84 +336,218 @@ func runPreview(cmd *cobra.Command, args []string) error {
 	fmt.Printf("\nOrganization Preview\n")
 	fmt.Printf("====================\n")
 	fmt.Printf("Source: %s\n", absPath)
-	fmt.Printf("Destination: %s\n", destRoot)
-	if mediaTypeFilter != types.MediaTypeUnknown {
-		fmt.Printf("Filter: %s only\n", mediaTypeFilter)
+	if destRoot != "" {
+		fmt.Printf("Destination: %s\n", destRoot)
+	} else {
+		fmt.Println("Destinations:")
+		for _, mediaType := range usedMediaTypes(plans) {
+			fmt.Printf("  %-6s → %s\n", mediaType, destinations[mediaType])
+		}
+	}
+	if report.Filter != "" {
+		fmt.Printf("Filter: %s only\n", report.Filter)
 	}
 	fmt.Printf("Conflict Strategy: %s\n", previewConflictStrategy)
-	fmt.Printf("\nFiles to organize: %d\n\n", len(plans))
+	fmt.Printf("\nFiles to organize: %d\n\n", report.Summary.Total)
 
-	// Group by media type for display
-	movieCount := 0
-	tvCount := 0
-	musicCount := 0
-	bookCount := 0
-	conflictCount := 0
+	// Display summary
+	if report.Summary.Movies > 0 {
+		fmt.Printf("Movies: %d\n", report.Summary.Movies)
+	}
+	if report.Summary.TV > 0 {
+		fmt.Printf("TV Shows: %d\n", report.Summary.TV)
+	}
+	if report.Summary.Music > 0 {
+		fmt.Printf("Music: %d\n", report.Summary.Music)
+	}
+	if report.Summary.Books > 0 {
+		fmt.Printf("Books: %d\n", report.Summary.Books)
+	}
 
-	for _, plan := range plans {
-		switch plan.MediaType {
-		case types.MediaTypeMovie:
-			movieCount++
-		case types.MediaTypeTV:
-			tvCount++
-		case types.MediaTypeMusic:
-			musicCount++
-		case types.MediaTypeBook:
-			bookCount++
-		}
-		if plan.Conflict {
-			conflictCount++
+	if mixes := organizer.MixedFolders(plans); len(mixes) > 0 {
+		fmt.Printf("\nMixed folders: %d (files are routed by type)\n", len(mixes))
+		for _, mix := range mixes {
+			fmt.Printf("  %s: %s\n", mix.Dir, formatTypeCounts(mix.Counts))
 		}
 	}
 
-	// Display summary
-	if movieCount > 0 {
-		fmt.Printf("Movies: %d\n", movieCount)
-	}
-	if tvCount > 0 {
-		fmt.Printf("TV Shows: %d\n", tvCount)
-	}
-	if musicCount > 0 {
-		fmt.Printf("Music: %d\n", musicCount)
-	}
-	if bookCount > 0 {
-		fmt.Printf("Books: %d\n", bookCount)
-	}
-
-	if conflictCount > 0 {
-		fmt.Printf("\n⚠ Conflicts detected: %d files\n", conflictCount)
-	}
-
-	// Display detailed plan if verbose
-	if verbose {
-		fmt.Println("\nDetailed Plan:")
-		fmt.Println("==============")
-		for i, plan := range plans {
-			fmt.Printf("\n%d. [%s] %s\n", i+1, plan.MediaType, filepath.Base(plan.SourcePath))
-			fmt.Printf("   From: %s\n", plan.SourcePath)
-			fmt.Printf("   To:   %s\n", plan.DestinationPath)
-			if plan.Conflict {
-				fmt.Printf("   ⚠ CONFLICT: %s\n", plan.ConflictReason)
-				if previewConflictStrategy == "rename" {
-					fmt.Printf("   → Will be renamed with suffix\n")
-				} else {
-					fmt.Printf("   → Will be skipped\n")
-				}
+	if report.Summary.Conflicts > 0 {
+		fmt.Printf("\n⚠ Conflicts detected: %d files\n", report.Summary.Conflicts)
+	}
+
+	fmt.Println("\nPlan:")
+	fmt.Println("=====")
+	for i, file := range report.Files {
+		fmt.Printf("\n%d. [%s] %s\n", i+1, file.MediaType, filepath.Base(file.Source))
+		if details := formatPreviewMetadata(file.Metadata); details != "" {
+			fmt.Printf("   Parsed: %s\n", details)
+		}
+		if verbose {
+			fmt.Printf("   From: %s\n", file.Source)
+		}
+		fmt.Printf("   To:   %s\n", file.Destination)
+		if file.Conflict {
+			fmt.Printf("   ⚠ CONFLICT: %s\n", file.ConflictReason)
+			if previewConflictStrategy == "rename" {
+				fmt.Printf("   → Will be renamed with suffix\n")
+			} else {
+				fmt.Printf("   → Will be skipped\n")
 			}
 		}
-	} else {
-		fmt.Println("\nUse -v/--verbose to see detailed file-by-file plan")
+		for _, warning := range file.Warnings {
+			fmt.Printf("   ⚠ %s\n", warning)
+		}
 	}
 
 	fmt.Printf("\nTo execute this plan, run:\n")
 	cmdArgs := fmt.Sprintf("  go-jf-org organize %s --dest %s", absPath, destRoot)
+	if destRoot == "" {
+		cmdArgs = fmt.Sprintf("  go-jf-org organize %s", absPath)
+	}
+	if previewRenameOnly {
+		cmdArgs = fmt.Sprintf("  go-jf-org organize %s --rename-only", absPath)
+	}
 	if previewMediaType != "" {
 		cmdArgs += fmt.Sprintf(" --type %s", previewMediaType)
 	}
 	if previewConflictStrategy != "skip" {
 		cmdArgs += fmt.Sprintf(" --conflict %s", previewConflictStrategy)
 	}
+	if previewFlatten {
+		cmdArgs += " --flatten"
+	}
+	if previewDestStructure != "" {
+		cmdArgs += " --dest-structure " + previewDestStructure
+	}
+	if previewEnrich {
+		cmdArgs += " --enrich"
+	}
+	if offline {
+		cmdArgs += " --offline"
+	}
 	fmt.Println(cmdArgs)
 
 	return nil
 }
+
+// buildPreviewReport converts organization plans into a preview report
+func buildPreviewReport(source, dest string, filter types.MediaType, plans []organizer.Plan) previewReport {
+	report := previewReport{
+		SchemaVersion:    util.JSONSchemaVersion,
+		Source:           source,
+		Destination:      dest,
+		ConflictStrategy: previewConflictStrategy,
+		Files:            make([]previewFile, 0, len(plans)),
+	}
+	if filter != types.MediaTypeUnknown {
+		report.Filter = string(filter)
+	}
+
+	for _, plan := range plans {
+		switch plan.MediaType {
+		case types.MediaTypeMovie:
+			report.Summary.Movies++
+		case types.MediaTypeTV:
+			report.Summary.TV++
+		case types.MediaTypeMusic:
+			report.Summary.Music++
+		case types.MediaTypeBook:
+			report.Summary.Books++
+		}
+		if plan.Conflict {
+			report.Summary.Conflicts++
+		}
+
+		report.Files = append(report.Files, previewFile{
+			Source:         plan.SourcePath,
+			Destination:    plan.DestinationPath,
+			MediaType:      plan.MediaType,
+			Metadata:       newPreviewMetadata(plan.Metadata),
+			Conflict:       plan.Conflict,
+			ConflictReason: plan.ConflictReason,
+			ConflictKind:   plan.ConflictKind,
+			Subtitles:      plan.Subtitles,
+			Extras:         plan.Extras,
+			Warnings:       plan.Warnings,
+		})
+	}
+	report.Summary.Total = len(report.Files)
+
+	return report
+}
+
+// newPreviewMetadata flattens parsed metadata into its preview form
+func newPreviewMetadata(metadata *types.Metadata) previewMetadata {
+	if metadata == nil {
+		return previewMetadata{}
+	}
+
+	pm := previewMetadata{
+		Title:        metadata.Title,
+		Year:         metadata.Year,
+		Quality:      metadata.Quality,
+		Source:       metadata.Source,
+		Codec:        metadata.Codec,
+		ReleaseGroup: metadata.ReleaseGroup,
+	}
+	if tv := metadata.TVMetadata; tv != nil {
+		if tv.ShowTitle != "" {
+			pm.Title = tv.ShowTitle
+		}
+		pm.Season = tv.Season
+		pm.Episode = tv.Episode
+		pm.EpisodeEnd = tv.EpisodeEnd
+		pm.AirDate = tv.AirDate
+		pm.EpisodeTitle = tv.EpisodeTitle
+	}
+	if music := metadata.MusicMetadata; music != nil {
+		pm.Artist = music.Artist
+		pm.Album = music.Album
+		pm.Track = music.TrackNumber
+	}
+	if book := metadata.BookMetadata; book != nil {
+		pm.Author = book.Author
+	}
+
+	return pm
+}
+
+// formatPreviewMetadata renders parsed metadata as a compact one-line summary
+func formatPreviewMetadata(pm previewMetadata) string {
+	details := pm.Title
+	if pm.Year > 0 {
+		details += fmt.Sprintf(" (%d)", pm.Year)
+	}
+	if pm.Episode == 0 && pm.AirDate != "" {
+		details += " " + pm.AirDate
+	} else if pm.Season > 0 || pm.Episode > 0 {
+		details += " " + jellyfin.EpisodeCode(&types.TVMetadata{Season: pm.Season, Episode: pm.Episode, EpisodeEnd: pm.EpisodeEnd})
+	}
+	if pm.EpisodeTitle != "" {
+		details += " - " + pm.EpisodeTitle
+	}
+	if pm.Artist != "" {
+		details += " by " + pm.Artist
+	}
+	if pm.Author != "" {
+		details += " by " + pm.Author
+	}
+	if pm.Album != "" {
+		details += fmt.Sprintf(" [%s]", pm.Album)
+	}
+	if pm.Quality != "" {
+		details += fmt.Sprintf(" [%s]", pm.Quality)
+	}
+	return details
+}
+
+// printPreviewReport writes the preview report to stdout in a structured format
+func printPreviewReport(format output.Format, report previewReport) error {
+	// Tables only hold files, so their warnings go to the log
+	if format == output.FormatTable {
+		for _, warning := range report.Warnings {
+			log.Warn().Msg(warning)
+		}
+	}
+	return printResult(format, report)
+}
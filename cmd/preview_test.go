@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/organizer"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestBuildPreviewReport_JSON(t *testing.T) {
+	plans := []organizer.Plan{
+		{
+			SourcePath:      "/src/The.Matrix.1999.1080p.mkv",
+			DestinationPath: "/dest/movies/The Matrix (1999)/The Matrix (1999).mkv",
+			MediaType:       types.MediaTypeMovie,
+			Metadata:        &types.Metadata{Title: "The Matrix", Year: 1999, Quality: "1080p"},
+		},
+		{
+			SourcePath:      "/src/Show.S01E02.mkv",
+			DestinationPath: "/dest/tv/Show/Season 01/Show - S01E02.mkv",
+			MediaType:       types.MediaTypeTV,
+			Metadata: &types.Metadata{
+				Title:      "Show",
+				TVMetadata: &types.TVMetadata{ShowTitle: "Show", Season: 1, Episode: 2},
+			},
+			Conflict:       true,
+			ConflictReason: "destination file already exists",
+		},
+	}
+
+	report := buildPreviewReport("/src", "/dest", types.MediaTypeUnknown, plans)
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	for _, key := range []string{"source", "destination", "conflict_strategy", "summary", "files"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("missing top-level key %q in %s", key, data)
+		}
+	}
+	if _, ok := decoded["filter"]; ok {
+		t.Errorf("filter should be omitted when no filter is set")
+	}
+
+	summary := decoded["summary"].(map[string]interface{})
+	wantSummary := map[string]float64{"total": 2, "movies": 1, "tv": 1, "music": 0, "books": 0, "conflicts": 1}
+	for key, want := range wantSummary {
+		if got := summary[key]; got != want {
+			t.Errorf("summary[%q] = %v, want %v", key, got, want)
+		}
+	}
+
+	files := decoded["files"].([]interface{})
+	if len(files) != 2 {
+		t.Fatalf("files length = %d, want 2", len(files))
+	}
+
+	movie := files[0].(map[string]interface{})
+	if movie["media_type"] != "movie" {
+		t.Errorf("files[0].media_type = %v, want movie", movie["media_type"])
+	}
+	if movie["destination"] != plans[0].DestinationPath {
+		t.Errorf("files[0].destination = %v, want %s", movie["destination"], plans[0].DestinationPath)
+	}
+	if _, ok := movie["conflict"]; ok {
+		t.Errorf("files[0].conflict should be omitted when false")
+	}
+	movieMeta := movie["metadata"].(map[string]interface{})
+	if movieMeta["title"] != "The Matrix" || movieMeta["year"] != float64(1999) || movieMeta["quality"] != "1080p" {
+		t.Errorf("files[0].metadata = %v", movieMeta)
+	}
+
+	episode := files[1].(map[string]interface{})
+	if episode["conflict"] != true || episode["conflict_reason"] != "destination file already exists" {
+		t.Errorf("files[1] conflict fields = %v, %v", episode["conflict"], episode["conflict_reason"])
+	}
+	episodeMeta := episode["metadata"].(map[string]interface{})
+	if episodeMeta["season"] != float64(1) || episodeMeta["episode"] != float64(2) {
+		t.Errorf("files[1].metadata = %v", episodeMeta)
+	}
+}
+
+func TestBuildPreviewReport_EmptyPlan(t *testing.T) {
+	report := buildPreviewReport("/src", "/dest", types.MediaTypeMovie, nil)
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded struct {
+		Filter string        `json:"filter"`
+		Files  []interface{} `json:"files"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.Files == nil {
+		t.Errorf("files should encode as an empty array, got %s", data)
+	}
+	if decoded.Filter != "movie" {
+		t.Errorf("filter = %q, want movie", decoded.Filter)
+	}
+}
+
+func TestFormatPreviewMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		pm   previewMetadata
+		want string
+	}{
+		{"movie", previewMetadata{Title: "The Matrix", Year: 1999, Quality: "1080p"}, "The Matrix (1999) [1080p]"},
+		{"episode", previewMetadata{Title: "Show", Season: 1, Episode: 2, EpisodeTitle: "Pilot"}, "Show S01E02 - Pilot"},
+		{"music", previewMetadata{Title: "Time", Artist: "Pink Floyd", Album: "The Dark Side of the Moon"}, "Time by Pink Floyd [The Dark Side of the Moon]"},
+		{"empty", previewMetadata{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatPreviewMetadata(tt.pm); got != tt.want {
+				t.Errorf("formatPreviewMetadata() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+	"github.com/opd-ai/go-jf-org/internal/scanner"
+)
+
+// sftpPasswordEnv holds the password for sftp:// sources that do not log in
+// with a key
+const sftpPasswordEnv = "GO_JF_ORG_SFTP_PASSWORD"
+
+// remoteFS is a connected remote source
+type remoteFS interface {
+	fsys.ReadFS
+	io.Closer
+}
+
+// dialRemote connects to an sftp:// source's server; replaceable in tests
+var dialRemote = func(u *fsys.SFTPURL) (remoteFS, error) {
+	return fsys.DialSFTP(u, fsys.SFTPOptions{
+		IdentityFile:          cfg.Remote.IdentityFile,
+		KnownHosts:            cfg.Remote.KnownHosts,
+		InsecureIgnoreHostKey: cfg.Remote.InsecureIgnoreHostKey,
+		Password:              os.Getenv(sftpPasswordEnv),
+	})
+}
+
+// scanRemote scans an sftp:// source through s, returning every path in
+// the result as an sftp:// URL
+func scanRemote(s *scanner.Scanner, source string) (*scanner.ScanResult, error) {
+	u, err := fsys.ParseSFTPURL(source)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialRemote(u)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	s.SetFileSystem(conn)
+	defer s.SetFileSystem(fsys.OSFileSystem{})
+	result, err := s.Scan(filepath.FromSlash(u.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, file := range result.Files {
+		result.Files[i] = u.WithPath(file)
+	}
+	for i := range result.Skipped {
+		result.Skipped[i].Path = u.WithPath(result.Skipped[i].Path)
+	}
+	for i, set := range result.Archives {
+		result.Archives[i].Dir = u.WithPath(set.Dir)
+		for j, part := range set.Parts {
+			set.Parts[j] = u.WithPath(part)
+		}
+	}
+	return result, nil
+}
+
+// remoteDownloads is what fetchRemoteFiles did
+type remoteDownloads struct {
+	// Files are the scanned files with remote ones replaced by their local copies
+	Files []string
+	// Downloaded counts files copied, companions included; files already
+	// downloaded by an earlier run are not counted
+	Downloaded int
+	// Errors are the remote files that could not be downloaded, which are
+	// left out of Files
+	Errors []error
+}
+
+// fetchRemoteFiles downloads the sftp:// files among files into
+// downloadDir/<host>/<remote path>, with the subtitles, NFOs and artwork
+// beside them that share their name, so organize can place them like local
+// files. The remote files are left as they are.
+func fetchRemoteFiles(files []string, downloadDir string) (*remoteDownloads, error) {
+	result := &remoteDownloads{Files: make([]string, 0, len(files))}
+	conns := make(map[string]remoteFS)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	listed := make(map[string][]os.DirEntry)
+
+	for _, file := range files {
+		if !fsys.IsSFTPURL(file) {
+			result.Files = append(result.Files, file)
+			continue
+		}
+		u, err := fsys.ParseSFTPURL(file)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+
+		server := u.WithPath("/")
+		conn := conns[server]
+		if conn == nil {
+			if conn, err = dialRemote(u); err != nil {
+				return nil, err
+			}
+			conns[server] = conn
+		}
+
+		remote := filepath.FromSlash(u.Path)
+		local := remoteDownloadPath(downloadDir, u)
+		fetched, err := fsys.Fetch(conn, remote, local)
+		if err != nil {
+			log.Warn().Err(err).Str("file", file).Msg("Failed to download remote file")
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		if fetched {
+			result.Downloaded++
+		}
+		result.Files = append(result.Files, local)
+
+		// Companions organize would move along with a local file
+		dir := filepath.Dir(remote)
+		entries, ok := listed[server+dir]
+		if !ok {
+			entries, _ = conn.ReadDir(dir)
+			listed[server+dir] = entries
+		}
+		stem := strings.TrimSuffix(filepath.Base(remote), filepath.Ext(remote))
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || name == filepath.Base(remote) || !isCompanionName(name, stem) {
+				continue
+			}
+			companion := *u
+			companion.Path = path.Join(path.Dir(u.Path), name)
+			if fetched, err := fsys.Fetch(conn, filepath.Join(dir, name), remoteDownloadPath(downloadDir, &companion)); err != nil {
+				log.Warn().Err(err).Str("file", companion.String()).Msg("Failed to download companion file")
+			} else if fetched {
+				result.Downloaded++
+			}
+		}
+	}
+	return result, nil
+}
+
+// isCompanionName reports whether name belongs with a media file named
+// stem: "Movie.en.srt", "Movie.nfo", "Movie-poster.jpg"
+func isCompanionName(name, stem string) bool {
+	return strings.HasPrefix(name, stem+".") || strings.HasPrefix(name, stem+"-")
+}
+
+// remoteDownloadPath is where a remote file is downloaded to, keeping its
+// folders so folder names still inform the metadata
+func remoteDownloadPath(downloadDir string, u *fsys.SFTPURL) string {
+	host := strings.NewReplacer(":", "_", "[", "", "]", "").Replace(u.Host)
+	return filepath.Join(downloadDir, host, filepath.FromSlash(u.Path))
+}
+
+// dropRemoteFiles returns files without the sftp:// ones and how many
+// were left out, for a dry run that downloads nothing
+func dropRemoteFiles(files []string) ([]string, int) {
+	local := make([]string, 0, len(files))
+	for _, file := range files {
+		if !fsys.IsSFTPURL(file) {
+			local = append(local, file)
+		}
+	}
+	return local, len(files) - len(local)
+}
+
+// resolveDownloadDir returns remote.download_dir, defaulting to
+// ~/.go-jf-org/downloads
+func resolveDownloadDir() (string, error) {
+	if cfg.Remote.DownloadDir != "" {
+		return cfg.Remote.DownloadDir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".go-jf-org", "downloads"), nil
+}
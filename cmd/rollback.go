@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"text/tabwriter"
 	"time"
@@ -30,7 +31,10 @@ Examples:
   go-jf-org rollback --list
 
   # Show details of a transaction
-  go-jf-org rollback abc123def456 --show`,
+  go-jf-org rollback abc123def456 --show
+
+  # Preview the reverse operations without performing them
+  go-jf-org rollback abc123def456 --dry-run`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runRollback,
 }
@@ -38,6 +42,7 @@ Examples:
 var (
 	listTransactions bool
 	showTransaction  bool
+	rollbackDryRun   bool
 )
 
 func init() {
@@ -45,6 +50,7 @@ func init() {
 
 	rollbackCmd.Flags().BoolVarP(&listTransactions, "list", "l", false, "List all transactions")
 	rollbackCmd.Flags().BoolVarP(&showTransaction, "show", "s", false, "Show transaction details without rolling back")
+	rollbackCmd.Flags().BoolVar(&rollbackDryRun, "dry-run", false, "Preview each reverse operation and any that cannot be performed, without changing files")
 }
 
 func runRollback(cmd *cobra.Command, args []string) error {
@@ -76,6 +82,11 @@ func runRollback(cmd *cobra.Command, args []string) error {
 		return showTransactionDetails(tm, txnID)
 	}
 
+	// Preview rollback
+	if rollbackDryRun {
+		return previewRollback(os.Stdout, tm, txnID)
+	}
+
 	// Perform rollback
 	return performRollback(tm, txnID)
 }
@@ -156,6 +167,47 @@ func showTransactionDetails(tm *safety.TransactionManager, txnID string) error {
 	return nil
 }
 
+// previewRollback prints the steps a rollback of txnID would take, flagging
+// those that cannot be performed, without touching any files
+func previewRollback(out io.Writer, tm *safety.TransactionManager, txnID string) error {
+	txn, steps, err := tm.PlanRollback(txnID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "[DRY-RUN] Rollback of transaction: %s\n", txn.ID)
+	fmt.Fprintf(out, "Status:      %s\n", txn.Status)
+	fmt.Fprintf(out, "Operations:  %d\n\n", len(steps))
+
+	if len(steps) == 0 {
+		fmt.Fprintln(out, "Nothing to roll back")
+		return nil
+	}
+
+	warnings := 0
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tFROM\tTO")
+	fmt.Fprintln(w, "------\t----\t--")
+	for _, step := range steps {
+		to := step.To()
+		if to == "" {
+			to = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", step.Action, step.From(), to)
+		if step.Warning != "" {
+			fmt.Fprintf(w, "  ⚠ %s\t\t\n", step.Warning)
+			warnings++
+		}
+	}
+	w.Flush()
+
+	if warnings > 0 {
+		fmt.Fprintf(out, "\n%d of %d operation(s) have warnings\n", warnings, len(steps))
+	}
+	fmt.Fprintf(out, "\nRun 'rollback %s' without --dry-run to perform the rollback\n", txn.ID)
+	return nil
+}
+
 func performRollback(tm *safety.TransactionManager, txnID string) error {
 	// Load and show transaction info
 	txn, err := tm.Load(txnID)
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/safety"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestPreviewRollback_MovesNothing(t *testing.T) {
+	tmpDir := t.TempDir()
+	tm, err := safety.NewTransactionManager(filepath.Join(tmpDir, "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := filepath.Join(tmpDir, "unsorted", "The.Matrix.1999.mkv")
+	dest := filepath.Join(tmpDir, "movies", "The Matrix (1999)", "The Matrix (1999).mkv")
+	nfo := filepath.Join(tmpDir, "movies", "The Matrix (1999)", "movie.nfo")
+	for _, path := range []string{dest, nfo} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txn, _ := tm.Begin()
+	tm.AddOperation(txn, types.Operation{Type: types.OperationMove, Source: source, Destination: dest, Status: types.OperationStatusCompleted})
+	tm.AddOperation(txn, types.Operation{Type: types.OperationMove, Source: source + ".srt", Destination: dest + ".srt", Status: types.OperationStatusCompleted})
+	tm.AddOperation(txn, types.Operation{Type: types.OperationCreateFile, Destination: nfo, Status: types.OperationStatusCompleted})
+	tm.Complete(txn)
+
+	var out bytes.Buffer
+	if err := previewRollback(&out, tm, txn.ID); err != nil {
+		t.Fatalf("previewRollback() error = %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"[DRY-RUN] Rollback of transaction: " + txn.ID,
+		"delete", nfo,
+		"move", dest, source,
+		"destination file no longer exists",
+		"1 of 3 operation(s) have warnings",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview output missing %q:\n%s", want, got)
+		}
+	}
+
+	for _, path := range []string{dest, nfo} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should not be touched by a dry run: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("%s should not be restored by a dry run", source)
+	}
+	if loaded, _ := tm.Load(txn.ID); loaded.Status != safety.TransactionStatusCompleted {
+		t.Errorf("transaction status = %s after a dry run, want %s", loaded.Status, safety.TransactionStatusCompleted)
+	}
+}
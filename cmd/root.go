@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 	"time"
 
@@ -11,9 +12,16 @@ import (
 )
 
 var (
-	cfgFile string
-	cfg     *config.Config
-	verbose bool
+	cfgFile   string
+	profile   string
+	cfg       *config.Config
+	verbose   bool
+	verbosity int
+	quiet     bool
+	// outputFlag is the global --output format (text, json or table)
+	outputFlag string
+	// offline keeps API clients to cached responses and skips downloads
+	offline bool
 )
 
 // rootCmd represents the base command
@@ -25,23 +33,26 @@ into a clean, Jellyfin-compatible directory structure.
 
 It extracts metadata from filenames and files, enriches it with external APIs,
 and safely moves files without ever deleting anything.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Set up logging
 		zerolog.TimeFieldFormat = time.RFC3339
-		if verbose {
-			zerolog.SetGlobalLevel(zerolog.DebugLevel)
-		} else {
-			zerolog.SetGlobalLevel(zerolog.InfoLevel)
-		}
+		verbose = verbosity > 0 && !quiet
+		zerolog.SetGlobalLevel(logLevel(verbosity, quiet))
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 
 		// Load configuration
 		var err error
-		cfg, err = config.Load(cfgFile)
+		cfg, err = config.LoadProfile(cfgFile, profile)
 		if err != nil {
+			// Falling back to defaults would send a profile's files to the
+			// base destinations, so a bad profile name is fatal
+			if errors.Is(err, config.ErrUnknownProfile) {
+				return err
+			}
 			log.Warn().Err(err).Msg("Failed to load config, using defaults")
 			cfg = config.DefaultConfig()
 		}
+		return nil
 	},
 }
 
@@ -52,5 +63,28 @@ func Execute() error {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.go-jf-org/config.yaml)")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "apply a named profile from the config's profiles section")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "verbose output (repeat for more: -v info, -vv debug, -vvv trace)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only log errors")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "text", "result format: text, json or table")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "never make network requests: API lookups use cached responses only (see 'scan --warm-cache'), artwork is not downloaded and run notifications are not sent")
+}
+
+// logLevel maps the -v count and --quiet flag to a zerolog level:
+// none → warn, -v → info, -vv → debug, -vvv (or more) → trace; --quiet → error
+func logLevel(verbosity int, quiet bool) zerolog.Level {
+	if quiet {
+		return zerolog.ErrorLevel
+	}
+
+	switch {
+	case verbosity <= 0:
+		return zerolog.WarnLevel
+	case verbosity == 1:
+		return zerolog.InfoLevel
+	case verbosity == 2:
+		return zerolog.DebugLevel
+	default:
+		return zerolog.TraceLevel
+	}
 }
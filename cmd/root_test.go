@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		verbosity int
+		quiet     bool
+		want      zerolog.Level
+	}{
+		{"default", 0, false, zerolog.WarnLevel},
+		{"-v", 1, false, zerolog.InfoLevel},
+		{"-vv", 2, false, zerolog.DebugLevel},
+		{"-vvv", 3, false, zerolog.TraceLevel},
+		{"more than -vvv", 5, false, zerolog.TraceLevel},
+		{"quiet", 0, true, zerolog.ErrorLevel},
+		{"quiet overrides verbose", 2, true, zerolog.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logLevel(tt.verbosity, tt.quiet); got != tt.want {
+				t.Errorf("logLevel(%d, %v) = %s, want %s", tt.verbosity, tt.quiet, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerboseFlagCounts(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("verbose")
+	if flag == nil {
+		t.Fatal("verbose flag not registered")
+	}
+	defer func() { verbosity = 0 }()
+
+	for i := 0; i < 3; i++ {
+		if err := flag.Value.Set("+1"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	if verbosity != 3 {
+		t.Errorf("verbosity = %d after three -v, want 3", verbosity)
+	}
+}
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -13,14 +15,22 @@ import (
 	"github.com/opd-ai/go-jf-org/internal/api/openlibrary"
 	"github.com/opd-ai/go-jf-org/internal/api/tmdb"
 	"github.com/opd-ai/go-jf-org/internal/config"
+	"github.com/opd-ai/go-jf-org/internal/genre"
+	"github.com/opd-ai/go-jf-org/internal/jellyfin"
+	"github.com/opd-ai/go-jf-org/internal/metadata"
+	"github.com/opd-ai/go-jf-org/internal/organizer"
+	"github.com/opd-ai/go-jf-org/internal/output"
 	"github.com/opd-ai/go-jf-org/internal/scanner"
 	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
 var (
-	enrichScan bool
-	jsonOutput bool
+	enrichScan     bool
+	jsonOutput     bool
+	scanDuplicates bool
+	scanWarmCache  bool
+	scanStatsFile  string
 )
 
 var scanCmd = &cobra.Command{
@@ -29,8 +39,14 @@ var scanCmd = &cobra.Command{
 	Long: `Scan scans the specified directory (and subdirectories) for media files.
 
 It identifies video, audio, and book files based on their extensions
-and reports what it finds. Use --enrich to fetch metadata from external APIs
-(TMDB for movies/TV, MusicBrainz for music, OpenLibrary for books).`,
+and reports what it finds. The directory may be on an SFTP server, given as
+sftp://user@host/path. Use --enrich to fetch metadata from external APIs
+(TMDB for movies/TV, MusicBrainz for music, OpenLibrary for books).
+Use --duplicates to report movies and episodes that exist more than once.
+
+Use --warm-cache to run every enrichment lookup and store the responses in
+the on-disk API caches without printing results, so a later
+'organize --enrich --offline' works without a connection.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runScan,
 }
@@ -38,22 +54,40 @@ and reports what it finds. Use --enrich to fetch metadata from external APIs
 func init() {
 	rootCmd.AddCommand(scanCmd)
 	scanCmd.Flags().BoolVar(&enrichScan, "enrich", false, "Enrich metadata using external APIs (TMDB, MusicBrainz, OpenLibrary)")
-	scanCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output statistics in JSON format")
+	scanCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output statistics in JSON format (same as --output json)")
+	scanCmd.Flags().BoolVar(&scanDuplicates, "duplicates", false, "Report duplicate movies/episodes (informational only)")
+	scanCmd.Flags().StringVar(&scanStatsFile, "stats-file", "", "keep partial run statistics in this JSON file while scanning (default performance.stats_file)")
+	scanCmd.Flags().BoolVar(&scanWarmCache, "warm-cache", false, "Run all enrichment lookups to fill the API caches for a later --offline run")
+	addExtensionFlags(scanCmd)
+	addMaxDepthFlag(scanCmd)
+	addIOWorkersFlag(scanCmd)
+	addCheckReadableFlag(scanCmd)
+	addNetWorkersFlag(scanCmd)
+	addMinConfidenceFlag(scanCmd)
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
-	scanPath := args[0]
+	// Make path absolute; an sftp:// source stays a URL
+	sources, err := absSources(args)
+	if err != nil {
+		return err
+	}
+	absPath := sources[0]
 
-	// Make path absolute
-	absPath, err := filepath.Abs(scanPath)
+	format, err := resolveOutputFormat(jsonOutput)
 	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+		return err
 	}
 
 	log.Info().Str("path", absPath).Msg("Starting scan")
 
 	// Create statistics tracker
 	stats := util.NewStatistics()
+	stopStats, err := startStatsFlusher(stats, scanStatsFile)
+	if err != nil {
+		return err
+	}
+	defer stopStats()
 
 	// Create scanner with configuration
 	minSize := int64(10 * 1024 * 1024) // 10MB default
@@ -66,60 +100,32 @@ func runScan(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	s := scanner.NewScanner(
-		cfg.Filters.VideoExtensions,
-		cfg.Filters.AudioExtensions,
-		cfg.Filters.BookExtensions,
-		minSize,
-	)
-
-	// Set up enrichers if requested
-	var tmdbEnricher *tmdb.Enricher
-	var mbEnricher *musicbrainz.Enricher
-	var olEnricher *openlibrary.Enricher
-
-	if enrichScan {
-		// Set up TMDB enricher for movies and TV shows
-		if cfg.APIKeys.TMDB == "" {
-			log.Warn().Msg("TMDB API key not configured, skipping movie/TV enrichment. Set api_keys.tmdb in config.")
-		} else {
-			client, err := tmdb.NewClient(tmdb.Config{
-				APIKey: cfg.APIKeys.TMDB,
-			})
-			if err != nil {
-				log.Warn().Err(err).Msg("Failed to create TMDB client, skipping movie/TV enrichment")
-			} else {
-				tmdbEnricher = tmdb.NewEnricher(client)
-				log.Info().Msg("TMDB enrichment enabled for movies and TV shows")
-			}
-		}
+	videoExts, audioExts, bookExts, err := scannerExtensions()
+	if err != nil {
+		return err
+	}
+	s := scanner.NewScanner(videoExts, audioExts, bookExts, minSize)
+	configureScanner(s)
 
-		// Set up MusicBrainz enricher for music
-		mbClient, err := musicbrainz.NewClient(musicbrainz.Config{})
-		if err != nil {
-			log.Warn().Err(err).Msg("Failed to create MusicBrainz client, skipping music enrichment")
-		} else {
-			mbEnricher = musicbrainz.NewEnricher(mbClient)
-			log.Info().Msg("MusicBrainz enrichment enabled for music")
-		}
+	if scanWarmCache && offline {
+		return fmt.Errorf("--warm-cache needs network access and cannot be used with --offline")
+	}
 
-		// Set up OpenLibrary enricher for books
-		olClient, err := openlibrary.NewClient(openlibrary.Config{})
-		if err != nil {
-			log.Warn().Err(err).Msg("Failed to create OpenLibrary client, skipping book enrichment")
-		} else {
-			olEnricher = openlibrary.NewEnricher(olClient)
-			log.Info().Msg("OpenLibrary enrichment enabled for books")
+	// Set up enrichers if requested
+	var enrichers enricherSet
+	if enrichScan || scanWarmCache {
+		if enrichers, err = setupEnrichers(); err != nil {
+			return err
 		}
 	}
 
 	// Perform scan with progress tracking
-	if !jsonOutput {
+	if format == output.FormatText {
 		fmt.Printf("Scanning %s...\n", absPath)
 	}
 
 	scanTimer := stats.NewTimer("scan")
-	result, err := s.Scan(absPath)
+	result, err := scanSources(s, sources)
 	scanTimer.Stop()
 
 	if err != nil {
@@ -129,6 +135,13 @@ func runScan(cmd *cobra.Command, args []string) error {
 	stats.Add("files_found", len(result.Files))
 	stats.Add("errors", len(result.Errors))
 
+	if scanDuplicates {
+		return printDuplicates(format, absPath, s.FindDuplicates(result.Files))
+	}
+	if scanWarmCache {
+		return warmCaches(s, absPath, result.Files, enrichers, stats)
+	}
+
 	// Display results
 	fmt.Println()
 	fmt.Printf("Scan Results for: %s\n", absPath)
@@ -138,6 +151,8 @@ func runScan(cmd *cobra.Command, args []string) error {
 	if len(result.Errors) > 0 {
 		fmt.Printf("Errors encountered: %d\n", len(result.Errors))
 	}
+	printSkippedFiles(result.Skipped)
+	printArchives(result.Archives)
 
 	fmt.Println()
 
@@ -165,17 +180,19 @@ func runScan(cmd *cobra.Command, args []string) error {
 
 	// List all files if verbose
 	if verbose {
-		// Set up progress tracking for metadata enrichment
-		var progress *util.ProgressTracker
-		if enrichScan && !jsonOutput {
-			progress = util.NewProgressTracker(len(result.Files), "Enriching metadata")
-			defer progress.Finish()
+		// Parse every file first so enrichment can run concurrently
+		entries := make([]scanEntry, len(result.Files))
+		for i, file := range result.Files {
+			entries[i].mediaType = s.GetMediaType(file)
+			entries[i].metadata, entries[i].err = s.GetMetadata(file)
+		}
+		if enrichScan {
+			enrichScanEntries(entries, result.Files, enrichers, stats, format == output.FormatText)
 		}
 
 		fmt.Println("Files found:")
-		for _, file := range result.Files {
-			mediaType := s.GetMediaType(file)
-			metadata, err := s.GetMetadata(file)
+		for i, file := range result.Files {
+			mediaType, metadata, err := entries[i].mediaType, entries[i].metadata, entries[i].err
 
 			stats.Increment("files_processed")
 
@@ -184,61 +201,6 @@ func runScan(cmd *cobra.Command, args []string) error {
 				continue
 			}
 
-			// Enrich metadata if enrichers are available
-			if metadata != nil {
-				switch mediaType {
-				case types.MediaTypeMovie:
-					if tmdbEnricher != nil {
-						enrichTimer := stats.NewTimer("enrichment")
-						if err := tmdbEnricher.EnrichMovie(metadata); err != nil {
-							log.Debug().Err(err).Str("file", file).Msg("Failed to enrich movie metadata")
-							stats.Increment("enrichment_failures")
-						} else {
-							stats.Increment("enrichment_success")
-						}
-						enrichTimer.Stop()
-					}
-				case types.MediaTypeTV:
-					if tmdbEnricher != nil {
-						enrichTimer := stats.NewTimer("enrichment")
-						if err := tmdbEnricher.EnrichTVShow(metadata); err != nil {
-							log.Debug().Err(err).Str("file", file).Msg("Failed to enrich TV metadata")
-							stats.Increment("enrichment_failures")
-						} else {
-							stats.Increment("enrichment_success")
-						}
-						enrichTimer.Stop()
-					}
-				case types.MediaTypeMusic:
-					if mbEnricher != nil {
-						enrichTimer := stats.NewTimer("enrichment")
-						if err := mbEnricher.EnrichMusic(metadata); err != nil {
-							log.Debug().Err(err).Str("file", file).Msg("Failed to enrich music metadata")
-							stats.Increment("enrichment_failures")
-						} else {
-							stats.Increment("enrichment_success")
-						}
-						enrichTimer.Stop()
-					}
-				case types.MediaTypeBook:
-					if olEnricher != nil {
-						enrichTimer := stats.NewTimer("enrichment")
-						if err := olEnricher.EnrichBook(metadata); err != nil {
-							log.Debug().Err(err).Str("file", file).Msg("Failed to enrich book metadata")
-							stats.Increment("enrichment_failures")
-						} else {
-							stats.Increment("enrichment_success")
-						}
-						enrichTimer.Stop()
-					}
-				}
-			}
-
-			// Update progress if tracking
-			if progress != nil {
-				progress.Increment()
-			}
-
 			// Display based on media type
 			switch mediaType {
 			case types.MediaTypeMovie:
@@ -281,8 +243,10 @@ func runScan(cmd *cobra.Command, args []string) error {
 					if metadata.TVMetadata.ShowTitle != "" {
 						fmt.Printf("          Show: %s  ", metadata.TVMetadata.ShowTitle)
 					}
-					if metadata.TVMetadata.Season > 0 || metadata.TVMetadata.Episode > 0 {
-						fmt.Printf("S%02dE%02d", metadata.TVMetadata.Season, metadata.TVMetadata.Episode)
+					if metadata.TVMetadata.Episode == 0 && metadata.TVMetadata.AirDate != "" {
+						fmt.Printf("%s", metadata.TVMetadata.AirDate)
+					} else if metadata.TVMetadata.Season > 0 || metadata.TVMetadata.Episode > 0 {
+						fmt.Printf("%s", jellyfin.EpisodeCode(metadata.TVMetadata))
 					}
 					if metadata.TVMetadata.EpisodeTitle != "" {
 						fmt.Printf("  %s", metadata.TVMetadata.EpisodeTitle)
@@ -364,32 +328,100 @@ func runScan(cmd *cobra.Command, args []string) error {
 	}
 
 	// Finalize and display statistics
+	enrichers.recordAPIUsage(stats)
 	stats.Finish()
 
-	if jsonOutput {
-		// Output JSON statistics
-		jsonStr, err := stats.ToJSON()
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to generate JSON statistics")
-		} else {
-			fmt.Fprintln(os.Stdout, jsonStr)
-		}
-	} else if !verbose {
+	if format != output.FormatText {
+		return printResult(format, stats.Report())
+	}
+	if !verbose {
 		// Show summary statistics for non-verbose mode
 		fmt.Println()
 		fmt.Printf("Scan completed in %s\n", util.FormatDuration(stats.Duration))
 		if enrichScan {
 			enrichSuccess := stats.Get("enrichment_success")
 			enrichFailed := stats.Get("enrichment_failures")
-			if enrichSuccess > 0 || enrichFailed > 0 {
-				fmt.Printf("Enrichment: %d successful, %d failed\n", enrichSuccess, enrichFailed)
+			enrichTimeouts := stats.Get("enrichment_timeouts")
+			if enrichSuccess > 0 || enrichFailed > 0 || enrichTimeouts > 0 {
+				fmt.Printf("Enrichment: %d successful, %d failed, %d timed out\n", enrichSuccess, enrichFailed, enrichTimeouts)
 			}
+			enrichers.printAPIUsage(stats)
 		}
 	}
 
 	return nil
 }
 
+// duplicateReport is the JSON form of a duplicate report
+type duplicateReport struct {
+	SchemaVersion int                      `json:"schema_version"`
+	Path          string                   `json:"path"`
+	Groups        []scanner.DuplicateGroup `json:"groups"`
+}
+
+// buildDuplicateReport wraps duplicate groups in a versioned report
+func buildDuplicateReport(absPath string, groups []scanner.DuplicateGroup) duplicateReport {
+	if groups == nil {
+		groups = []scanner.DuplicateGroup{}
+	}
+	return duplicateReport{
+		SchemaVersion: util.JSONSchemaVersion,
+		Path:          absPath,
+		Groups:        groups,
+	}
+}
+
+// printDuplicates outputs a duplicate report in the given format
+func printDuplicates(format output.Format, absPath string, groups []scanner.DuplicateGroup) error {
+	if format != output.FormatText {
+		return printResult(format, buildDuplicateReport(absPath, groups))
+	}
+
+	fmt.Println()
+	fmt.Printf("Duplicate Report for: %s\n", absPath)
+	fmt.Println("=====================================")
+
+	if len(groups) == 0 {
+		fmt.Println("No duplicates found.")
+		return nil
+	}
+
+	fmt.Printf("Duplicate groups found: %d\n\n", len(groups))
+	for _, group := range groups {
+		fmt.Printf("[%s] %s (%d copies)\n", group.MediaType, group.Key, len(group.Files))
+		for _, f := range group.Files {
+			if f.Best {
+				fmt.Printf("  %s (preferred)\n", f.Path)
+			} else {
+				fmt.Printf("  %s\n", f.Path)
+			}
+			details := fmt.Sprintf("Size: %s", util.FormatBytes(f.Size))
+			if f.Quality != "" {
+				details += "  Quality: " + f.Quality
+			}
+			if f.Source != "" {
+				details += "  Source: " + f.Source
+			}
+			if f.Codec != "" {
+				details += "  Codec: " + f.Codec
+			}
+			if f.Proper {
+				details += "  PROPER"
+			}
+			if f.Repack {
+				details += "  REPACK"
+			}
+			if f.ReleaseGroup != "" {
+				details += "  Group: " + f.ReleaseGroup
+			}
+			fmt.Printf("    %s\n", details)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
 // truncate truncates a string to maxLen characters, adding "..." if truncated
 func truncate(s string, maxLen int) string {
 	if maxLen < 3 {
@@ -400,3 +432,235 @@ func truncate(s string, maxLen int) string {
 	}
 	return s[:maxLen-3] + "..."
 }
+
+// warmCaches enriches every scanned file only to fill the API caches,
+// showing nothing but progress and a summary
+func warmCaches(s *scanner.Scanner, absPath string, files []string, enrichers enricherSet, stats *util.Statistics) error {
+	entries := make([]scanEntry, len(files))
+	for i, file := range files {
+		entries[i].mediaType = s.GetMediaType(file)
+		entries[i].metadata, entries[i].err = s.GetMetadata(file)
+	}
+	enrichScanEntries(entries, files, enrichers, stats, !quiet)
+	enrichers.recordAPIUsage(stats)
+	stats.Finish()
+
+	fmt.Printf("\nWarmed API caches for %d file(s) in %s: %d looked up, %d failed\n",
+		len(files), util.FormatDuration(stats.Duration), stats.Get("enrichment_success"), stats.Get("enrichment_failures"))
+	fmt.Printf("Organize offline with: go-jf-org organize %s --enrich --offline\n", absPath)
+	return nil
+}
+
+// scanEntry is a scanned file's parsed metadata
+type scanEntry struct {
+	mediaType types.MediaType
+	metadata  *types.Metadata
+	err       error
+}
+
+// enrichScanEntries enriches the parsed entries using up to the resolved
+// number of network workers, giving up on a file after the set's timeout,
+// recording successes, failures and timeouts in stats and showing a
+// progress bar when showProgress is set
+func enrichScanEntries(entries []scanEntry, files []string, enrichers enricherSet, stats *util.Statistics, showProgress bool) {
+	_, netWorkers := resolveWorkers()
+
+	metadataList := make([]*types.Metadata, 0, len(entries))
+	mediaTypes := make(map[*types.Metadata]types.MediaType, len(entries))
+	paths := make(map[*types.Metadata]string, len(entries))
+	for i, e := range entries {
+		if e.err != nil || e.metadata == nil {
+			continue
+		}
+		metadataList = append(metadataList, e.metadata)
+		mediaTypes[e.metadata] = e.mediaType
+		paths[e.metadata] = files[i]
+	}
+
+	var progress *util.ProgressTracker
+	if showProgress {
+		progress = util.NewProgressTracker(len(metadataList), "Enriching metadata")
+	}
+
+	enricher := util.NewConcurrentEnricher(netWorkers)
+	enricher.EnrichWithProgress(context.Background(), metadataList, func(ctx context.Context, meta *types.Metadata) error {
+		enrichTimer := stats.NewTimer("enrichment")
+		defer enrichTimer.Stop()
+
+		// Reading the duration lets MusicBrainz match tracks without numbers
+		if mediaTypes[meta] == types.MediaTypeMusic && enrichers.musicbrainz != nil {
+			metadata.ApplyAudioDuration(meta, paths[meta])
+		}
+		var ok bool
+		err := util.EnrichWithTimeout(ctx, enrichers.timeout, meta, func(ctx context.Context, meta *types.Metadata) error {
+			var err error
+			ok, err = enrichers.enrich(ctx, mediaTypes[meta], meta)
+			return err
+		})
+		if errors.Is(err, util.ErrEnrichTimeout) {
+			log.Warn().Err(err).Str("file", paths[meta]).Msg("Enrichment timed out, using parsed metadata")
+			stats.Increment("enrichment_timeouts")
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		if err != nil {
+			log.Debug().Err(err).Str("file", paths[meta]).Msgf("Failed to enrich %s metadata", mediaTypes[meta])
+			stats.Increment("enrichment_failures")
+			return err
+		}
+		stats.Increment("enrichment_success")
+		return nil
+	}, progress)
+}
+
+// enricherSet holds the metadata enrichers for each media type; a nil
+// enricher means that API is unavailable
+type enricherSet struct {
+	tmdb        *tmdb.Enricher
+	musicbrainz *musicbrainz.Enricher
+	openlibrary *openlibrary.Enricher
+	// cacheStats reports cache hits and misses per API client created
+	cacheStats map[string]func() (hits, misses int64)
+	// rateStats reports rate limiter usage per rate-limited API client
+	rateStats map[string]func() (requests, waits int64, waited time.Duration)
+	// timeout bounds the lookups for one file (enrich.per_item_timeout)
+	timeout time.Duration
+}
+
+// setupEnrichers creates the TMDB, MusicBrainz and OpenLibrary enrichers,
+// logging and skipping any API that cannot be used. It fails only on an
+// invalid --min-confidence or enrich.per_item_timeout.
+func setupEnrichers() (enricherSet, error) {
+	set := enricherSet{
+		cacheStats: make(map[string]func() (int64, int64)),
+		rateStats:  make(map[string]func() (int64, int64, time.Duration)),
+	}
+
+	minConfidence, err := resolveMinConfidence()
+	if err != nil {
+		return set, err
+	}
+	if set.timeout, err = resolveEnrichTimeout(); err != nil {
+		return set, err
+	}
+
+	// Set up TMDB enricher for movies and TV shows
+	if cfg.APIKeys.TMDB == "" {
+		log.Warn().Msg("TMDB API key not configured, skipping movie/TV enrichment. Set api_keys.tmdb in config.")
+	} else {
+		client, err := tmdb.NewClient(tmdb.Config{
+			APIKey:  cfg.APIKeys.TMDB,
+			Offline: offline,
+		})
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to create TMDB client, skipping movie/TV enrichment")
+		} else {
+			set.tmdb = tmdb.NewEnricher(client)
+			set.tmdb.SetGenreMapper(genre.NewMapper(cfg.Genres.Mapping, cfg.Genres.Allowlist))
+			set.tmdb.SetImageBaseURL(cfg.Artwork.TMDBImageBase)
+			set.tmdb.SetRegion(cfg.Enrich.Region)
+			set.tmdb.SetMinConfidence(minConfidence)
+			if cfg.Artwork.ActorThumbs || cfg.Artwork.ActorImages {
+				set.tmdb.SetActorLimit(cfg.Artwork.ActorLimit)
+			}
+			set.cacheStats["tmdb"] = client.CacheStats
+			set.rateStats["tmdb"] = client.RateLimitStats
+			log.Info().Msg("TMDB enrichment enabled for movies and TV shows")
+		}
+	}
+
+	// Set up MusicBrainz enricher for music
+	mbClient, err := musicbrainz.NewClient(musicbrainz.Config{Offline: offline})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to create MusicBrainz client, skipping music enrichment")
+	} else {
+		set.musicbrainz = musicbrainz.NewEnricher(mbClient)
+		set.cacheStats["musicbrainz"] = mbClient.CacheStats
+		set.rateStats["musicbrainz"] = mbClient.RateLimitStats
+		log.Info().Msg("MusicBrainz enrichment enabled for music")
+	}
+
+	// Set up OpenLibrary enricher for books
+	olClient, err := openlibrary.NewClient(openlibrary.Config{Offline: offline})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to create OpenLibrary client, skipping book enrichment")
+	} else {
+		set.openlibrary = openlibrary.NewEnricher(olClient)
+		set.cacheStats["openlibrary"] = olClient.CacheStats
+		log.Info().Msg("OpenLibrary enrichment enabled for books")
+	}
+
+	if offline {
+		log.Info().Msg("Offline mode: enrichment uses cached API responses only")
+	}
+	return set, nil
+}
+
+// enrich runs the enricher for mediaType on metadata, cancelling its API
+// requests once ctx is done. ok is false when no enricher is available for
+// that type.
+func (e enricherSet) enrich(ctx context.Context, mediaType types.MediaType, metadata *types.Metadata) (ok bool, err error) {
+	switch mediaType {
+	case types.MediaTypeMovie:
+		if e.tmdb != nil {
+			return true, e.tmdb.EnrichMovie(ctx, metadata)
+		}
+	case types.MediaTypeTV:
+		if e.tmdb != nil {
+			return true, e.tmdb.EnrichTVShow(ctx, metadata)
+		}
+	case types.MediaTypeMusic:
+		if e.musicbrainz != nil {
+			return true, e.musicbrainz.EnrichMusic(ctx, metadata)
+		}
+	case types.MediaTypeBook:
+		if e.openlibrary != nil {
+			return true, e.openlibrary.EnrichBook(ctx, metadata)
+		}
+	}
+	return false, nil
+}
+
+// recordAPIUsage adds each rate-limited API's requests, rate limit waits and
+// time spent waiting to stats as <api>_api_requests, <api>_rate_limit_waits
+// and the <api>_rate_limit_wait timing, so users can tune
+// performance.api_rate_limit. APIs that made no requests are left out.
+func (e enricherSet) recordAPIUsage(stats *util.Statistics) {
+	for name, rateStats := range e.rateStats {
+		requests, waits, waited := rateStats()
+		if requests == 0 && waits == 0 {
+			continue
+		}
+		stats.Add(name+"_api_requests", int(requests))
+		stats.Add(name+"_rate_limit_waits", int(waits))
+		stats.AddTiming(name+"_rate_limit_wait", waited)
+	}
+}
+
+// printAPIUsage prints the usage recordAPIUsage added to stats
+func (e enricherSet) printAPIUsage(stats *util.Statistics) {
+	names := make([]string, 0, len(e.rateStats))
+	for name := range e.rateStats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		requests := stats.Get(name + "_api_requests")
+		if requests == 0 {
+			continue
+		}
+		fmt.Printf("%s API: %d requests, %d rate limit waits (%s waiting)\n",
+			name, requests, stats.Get(name+"_rate_limit_waits"), util.FormatDuration(stats.GetTiming(name+"_rate_limit_wait")))
+	}
+}
+
+// enrichFunc adapts the set for organizer.SetEnricher; media types without
+// an enricher are left as parsed
+func (e enricherSet) enrichFunc() organizer.EnrichFunc {
+	return func(ctx context.Context, mediaType types.MediaType, metadata *types.Metadata) error {
+		_, err := e.enrich(ctx, mediaType, metadata)
+		return err
+	}
+}
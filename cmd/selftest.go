@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/opd-ai/go-jf-org/internal/jellyfin"
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check that generated NFO files round-trip and use Jellyfin element names",
+	Long: `Selftest generates every NFO type (movie, tvshow, episode, season, album,
+book) from sample metadata containing characters that need XML escaping,
+parses each file back and checks that all fields survive unchanged and that
+only element names Jellyfin reads are used.
+
+Run this when Jellyfin does not pick up NFO files written by go-jf-org.`,
+	Args: cobra.NoArgs,
+	RunE: runSelftest,
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+}
+
+func runSelftest(cmd *cobra.Command, args []string) error {
+	results := jellyfin.RunNFOSelfTest()
+
+	fmt.Println("NFO Self-Test")
+	fmt.Println("=============")
+
+	failed := 0
+	for _, r := range results {
+		if r.Passed() {
+			fmt.Printf("✓ %s\n", r.Name)
+			continue
+		}
+		failed++
+		fmt.Printf("✗ %s\n", r.Name)
+		for _, problem := range r.Problems {
+			fmt.Printf("    - %s\n", problem)
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d NFO checks failed", failed, len(results))
+	}
+	fmt.Printf("All %d NFO checks passed\n", len(results))
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/opd-ai/go-jf-org/internal/notify"
+	"github.com/opd-ai/go-jf-org/internal/safety"
+	"github.com/opd-ai/go-jf-org/internal/server"
+)
+
+var (
+	serveAddr  string
+	serveToken string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a small HTTP API for run status, transactions and rollback",
+	Long: `Serve starts an HTTP API for home dashboards and automation:
+
+  GET  /status          the organize run in progress and the last finished run
+  GET  /transactions    every transaction with its status and operation count
+  POST /organize        start an organize run (JSON body, see below)
+  POST /rollback/{id}   roll back a transaction
+
+The organize body takes "paths" (required), "dest", "type", "conflict"
+(skip or rename), "dry_run", "create_nfo" and "hash"; everything else comes
+from the config file as for the organize command. One organize run or
+rollback is served at a time; another request for either while one is
+running is refused with 409 Conflict.
+
+When a token is set (--token or server.token), every request must send
+"Authorization: Bearer <token>". Without one the API is open, so keep it on
+localhost.
+
+Examples:
+  go-jf-org serve --addr 127.0.0.1:8080 --token s3cret
+  curl -H "Authorization: Bearer s3cret" http://127.0.0.1:8080/status
+  curl -H "Authorization: Bearer s3cret" -d '{"paths":["/media/unsorted"]}' \
+    http://127.0.0.1:8080/organize`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "", "address to listen on (default server.addr)")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "API token clients must send as a bearer token (default server.token)")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	addr := serveAddr
+	if addr == "" {
+		addr = cfg.Server.Addr
+	}
+	token := serveToken
+	if token == "" {
+		token = cfg.Server.Token
+	}
+
+	logDir, err := safety.GetDefaultLogDir()
+	if err != nil {
+		return fmt.Errorf("failed to get transaction log directory: %w", err)
+	}
+	tm, err := safety.NewTransactionManager(logDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize transaction manager: %w", err)
+	}
+
+	if token == "" {
+		log.Warn().Str("addr", addr).Msg("No API token set; the API is open to anyone who can reach it")
+	}
+	log.Info().Str("addr", addr).Msg("Serving API")
+	fmt.Printf("Serving API on http://%s\n", addr)
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           server.New(tm, serveOrganize, token).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		// A rollback is answered once it has finished
+		WriteTimeout: 10 * time.Minute,
+	}
+	return srv.ListenAndServe()
+}
+
+// serveOrganize runs organize with the options of an API request. Anything
+// the request does not set has the organize command's default, so no flag
+// or earlier request carries over.
+func serveOrganize(req server.OrganizeRequest, run *notify.Summary) error {
+	opts := organizeOptions{
+		dest:             req.Dest,
+		mediaType:        req.Type,
+		conflictStrategy: req.Conflict,
+		dryRun:           req.DryRun,
+		createNFO:        req.CreateNFO,
+		hash:             req.Hash,
+		artworkSize:      "medium",
+		onError:          "continue",
+		yes:              true, // nobody is at the terminal to confirm
+	}
+	if opts.conflictStrategy == "" {
+		opts.conflictStrategy = "skip"
+	}
+
+	started := time.Now()
+	err := organize(opts, req.Paths, run)
+	if run.Sources != nil && !opts.dryRun {
+		sendRunNotification(run, err, time.Since(started))
+	}
+	return err
+}
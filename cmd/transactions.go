@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/opd-ai/go-jf-org/internal/organizer"
+	"github.com/opd-ai/go-jf-org/internal/safety"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// transactionsCmd groups transaction log maintenance commands
+var transactionsCmd = &cobra.Command{
+	Use:   "transactions",
+	Short: "Inspect, verify, merge and transfer transaction logs",
+	Long: `Transactions provides tools for working with the transaction logs written
+by the organize command.`,
+}
+
+// transactionsVerifyCmd verifies file hashes recorded in a transaction
+var transactionsVerifyCmd = &cobra.Command{
+	Use:   "verify [transaction-id]",
+	Short: "Verify organized files against hashes recorded in a transaction",
+	Long: `Verify re-hashes every file recorded with a hash in the transaction
+(organize --hash) and reports files that were modified, corrupted or removed.
+Each file is re-hashed with the algorithm recorded for it (integrity.algorithm
+at organize time), so changing the setting later does not break verification.
+
+Examples:
+  # Organize with hashes, then verify later
+  go-jf-org organize /media/unsorted --hash
+  go-jf-org transactions verify abc123def456`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTransactionsVerify,
+}
+
+// transactionsMergeStagingCmd moves staged files into the library
+var transactionsMergeStagingCmd = &cobra.Command{
+	Use:   "merge-staging [transaction-id]",
+	Short: "Move files staged by 'organize --stage' into the library",
+	Long: `Merge-staging moves every file a staged organize run wrote under
+<dest>/.staging-<timestamp>/ to the same path without the staging directory,
+then removes the emptied staging directories. Files whose library path is
+already occupied are left staged.
+
+The merge is recorded as a new transaction, so it can be rolled back.
+
+Examples:
+  # Stage, review, then merge
+  go-jf-org organize /media/unsorted --stage
+  go-jf-org transactions merge-staging abc123def456`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTransactionsMergeStaging,
+}
+
+// transactionsExportCmd bundles a transaction for archival or transfer
+var transactionsExportCmd = &cobra.Command{
+	Use:   "export [transaction-id] [file.tar.gz]",
+	Short: "Bundle a transaction log and its backups into a tarball",
+	Long: `Export writes the transaction log, any backup files kept for it and a
+manifest of their SHA-256 checksums to a gzipped tarball. Backups are the
+NFOs replaced by --clobber-nfo and, with safety.backup_before_move, a copy
+of every moved file. Import the bundle on another machine to roll the
+transaction back there.
+
+Examples:
+  go-jf-org transactions export abc123def456 abc123def456.tar.gz`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTransactionsExport,
+}
+
+// transactionsImportCmd restores an exported transaction bundle
+var transactionsImportCmd = &cobra.Command{
+	Use:   "import [file.tar.gz]",
+	Short: "Restore a transaction bundle made by 'transactions export'",
+	Long: `Import checks every file in a bundle against its manifest, then installs
+the transaction log and backups into the transaction log directory. A bundle
+that fails the check, or whose transaction already exists, is rejected
+without changing anything.
+
+Examples:
+  go-jf-org transactions import abc123def456.tar.gz
+  go-jf-org rollback abc123def456`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTransactionsImport,
+}
+
+// transactionsRetryArtworkCmd repeats artwork downloads that failed
+var transactionsRetryArtworkCmd = &cobra.Command{
+	Use:   "retry-artwork [transaction-id]",
+	Short: "Retry the artwork downloads that failed during an organize run",
+	Long: `Retry-artwork repeats the artwork downloads that failed while organizing
+with --download-artwork, such as posters lost to a flaky connection, without
+moving any files again. Artwork that the source simply does not have is not
+retried.
+
+Artwork downloaded now is added to the transaction, so rolling it back
+removes it too. Downloads that fail again stay listed for the next retry.
+
+Examples:
+  go-jf-org organize /media/unsorted --download-artwork
+  go-jf-org transactions retry-artwork abc123def456`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTransactionsRetryArtwork,
+}
+
+func init() {
+	rootCmd.AddCommand(transactionsCmd)
+	transactionsCmd.AddCommand(transactionsVerifyCmd)
+	transactionsCmd.AddCommand(transactionsMergeStagingCmd)
+	transactionsCmd.AddCommand(transactionsExportCmd)
+	transactionsCmd.AddCommand(transactionsImportCmd)
+	transactionsCmd.AddCommand(transactionsRetryArtworkCmd)
+}
+
+func runTransactionsVerify(cmd *cobra.Command, args []string) error {
+	logDir, err := safety.GetDefaultLogDir()
+	if err != nil {
+		return fmt.Errorf("failed to get transaction log directory: %w", err)
+	}
+
+	tm, err := safety.NewTransactionManager(logDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize transaction manager: %w", err)
+	}
+
+	txnID := args[0]
+	results, err := tm.Verify(txnID)
+	if err != nil {
+		return fmt.Errorf("failed to verify transaction: %w", err)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("Transaction %s has no recorded hashes (organize with --hash to record them)\n", txnID)
+		return nil
+	}
+
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tFILE")
+	fmt.Fprintln(w, "------\t----")
+	for _, r := range results {
+		if r.Status != safety.VerifyStatusOK {
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\n", r.Status, r.Operation.Destination)
+	}
+	w.Flush()
+
+	fmt.Printf("\nVerified %d file(s): %d ok, %d failed\n", len(results), len(results)-failed, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d file(s) failed verification", failed)
+	}
+
+	fmt.Println("✓ All files match their recorded hashes")
+	return nil
+}
+
+func runTransactionsMergeStaging(cmd *cobra.Command, args []string) error {
+	logDir, err := safety.GetDefaultLogDir()
+	if err != nil {
+		return fmt.Errorf("failed to get transaction log directory: %w", err)
+	}
+
+	tm, err := safety.NewTransactionManager(logDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize transaction manager: %w", err)
+	}
+
+	mergeID, ops, err := tm.MergeStaging(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to merge staging: %w", err)
+	}
+
+	if len(ops) == 0 {
+		fmt.Printf("Transaction %s has no staged files\n", args[0])
+		return nil
+	}
+
+	failed := 0
+	for _, op := range ops {
+		if op.Status == types.OperationStatusFailed {
+			failed++
+			fmt.Printf("✗ %s\n    Error: %v\n", op.Source, op.Error)
+		}
+	}
+
+	fmt.Printf("Merged %d file(s), %d left staged\n", len(ops)-failed, failed)
+	fmt.Printf("To undo the merge, run: go-jf-org rollback %s\n", mergeID)
+
+	if failed > 0 {
+		return fmt.Errorf("%d staged file(s) could not be merged", failed)
+	}
+	return nil
+}
+
+func runTransactionsExport(cmd *cobra.Command, args []string) error {
+	logDir, err := safety.GetDefaultLogDir()
+	if err != nil {
+		return fmt.Errorf("failed to get transaction log directory: %w", err)
+	}
+
+	tm, err := safety.NewTransactionManager(logDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize transaction manager: %w", err)
+	}
+
+	txnID, path := args[0], args[1]
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	if err := tm.Export(txnID, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to export transaction: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Printf("✓ Exported transaction %s to %s\n", txnID, path)
+	return nil
+}
+
+func runTransactionsImport(cmd *cobra.Command, args []string) error {
+	logDir, err := safety.GetDefaultLogDir()
+	if err != nil {
+		return fmt.Errorf("failed to get transaction log directory: %w", err)
+	}
+
+	tm, err := safety.NewTransactionManager(logDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize transaction manager: %w", err)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer f.Close()
+
+	txnID, err := tm.Import(f)
+	if err != nil {
+		return fmt.Errorf("failed to import transaction: %w", err)
+	}
+
+	fmt.Printf("✓ Imported transaction %s\n", txnID)
+	fmt.Printf("To rollback this operation, run: go-jf-org rollback %s\n", txnID)
+	return nil
+}
+
+func runTransactionsRetryArtwork(cmd *cobra.Command, args []string) error {
+	if offline {
+		return fmt.Errorf("cannot retry artwork downloads in offline mode")
+	}
+
+	logDir, err := safety.GetDefaultLogDir()
+	if err != nil {
+		return fmt.Errorf("failed to get transaction log directory: %w", err)
+	}
+
+	tm, err := safety.NewTransactionManager(logDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize transaction manager: %w", err)
+	}
+
+	return retryArtwork(tm, args[0])
+}
+
+// retryArtwork retries a transaction's failed artwork downloads and records
+// the outcome in its log
+func retryArtwork(tm *safety.TransactionManager, txnID string) error {
+	txn, err := tm.Load(txnID)
+	if err != nil {
+		return fmt.Errorf("failed to load transaction: %w", err)
+	}
+	if txn.Status == safety.TransactionStatusRolledBack {
+		return fmt.Errorf("transaction %s has been rolled back", txnID)
+	}
+	if len(txn.ArtworkRetries) == 0 {
+		fmt.Printf("Transaction %s has no failed artwork downloads\n", txnID)
+		return nil
+	}
+
+	artworkFormat, err := resolveArtworkFormat()
+	if err != nil {
+		return err
+	}
+	org := organizer.NewOrganizer(false)
+	org.SetTMDBImageBase(cfg.Artwork.TMDBImageBase)
+	org.SetArtworkFormat(artworkFormat)
+
+	downloaded, failing := org.RetryArtwork(context.Background(), txn.ArtworkRetries)
+	if err := tm.RecordArtworkRetries(txn, downloaded, failing); err != nil {
+		return fmt.Errorf("failed to update transaction: %w", err)
+	}
+
+	for _, op := range downloaded {
+		fmt.Printf("✓ %s\n", op.Destination)
+	}
+	for _, retry := range failing {
+		fmt.Printf("✗ %s\n    Error: %s\n", retry.Destination, retry.Error)
+	}
+	fmt.Printf("Downloaded %d artwork file(s), %d still failing\n", len(downloaded), len(failing))
+
+	if len(failing) > 0 {
+		return fmt.Errorf("%d artwork download(s) still failing; run 'go-jf-org transactions retry-artwork %s' again later", len(failing), txnID)
+	}
+	return nil
+}
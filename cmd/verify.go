@@ -1,15 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
+	"github.com/opd-ai/go-jf-org/internal/output"
+	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/internal/verifier"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
@@ -18,6 +18,9 @@ var (
 	verifyStrict     bool
 	verifyMediaType  string
 	verifyJSONOutput bool
+	verifyRecursive  bool
+	verifyContainers bool
+	verifyComplete   bool
 )
 
 var verifyCmd = &cobra.Command{
@@ -33,6 +36,11 @@ It validates:
 
 Use --strict to fail on any violations (exit code 1).
 Use --type to verify only specific media types.
+Use --recursive to verify a whole library root (Movies/, TV/, Music/, Books/).
+Use --check-containers to read video headers and flag extensions that lie
+about the container (e.g. an MKV named .mp4).
+Use --check-completeness to warn about episodes missing from TV seasons and
+season directories missing between the first and last present.
 Use --json for machine-readable output.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runVerify,
@@ -42,12 +50,20 @@ func init() {
 	rootCmd.AddCommand(verifyCmd)
 	verifyCmd.Flags().BoolVar(&verifyStrict, "strict", false, "Fail with exit code 1 if errors are found")
 	verifyCmd.Flags().StringVar(&verifyMediaType, "type", "", "Verify specific media type (movie, tv, music, book)")
-	verifyCmd.Flags().BoolVar(&verifyJSONOutput, "json", false, "Output results as JSON")
+	verifyCmd.Flags().BoolVar(&verifyJSONOutput, "json", false, "Output results as JSON (same as --output json)")
+	verifyCmd.Flags().BoolVar(&verifyContainers, "check-containers", false, "Read video file headers and warn when the container does not match the extension")
+	verifyCmd.Flags().BoolVar(&verifyComplete, "check-completeness", false, "Warn about missing episodes within TV seasons and missing season directories")
+	verifyCmd.Flags().BoolVar(&verifyRecursive, "recursive", false, "Verify a library root, inferring the media type of every item in each section")
 }
 
 func runVerify(cmd *cobra.Command, args []string) error {
 	verifyPath := args[0]
 
+	format, err := resolveOutputFormat(verifyJSONOutput)
+	if err != nil {
+		return err
+	}
+
 	// Make path absolute
 	absPath, err := filepath.Abs(verifyPath)
 	if err != nil {
@@ -56,6 +72,10 @@ func runVerify(cmd *cobra.Command, args []string) error {
 
 	log.Info().Str("path", absPath).Msg("Starting verification")
 
+	if verifyRecursive && verifyMediaType != "" {
+		return fmt.Errorf("--recursive infers media types and cannot be combined with --type")
+	}
+
 	// Parse media type if specified
 	var mediaType types.MediaType
 	if verifyMediaType != "" {
@@ -72,42 +92,70 @@ func runVerify(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	filenameStyle, err := resolveFilenameStyle()
+	if err != nil {
+		return err
+	}
+
 	// Create verifier and run verification
 	v := verifier.NewVerifier()
-	result, err := v.VerifyPath(absPath, mediaType)
+	v.SetExtrasDirs(cfg.Organize.ExtrasDirs)
+	v.SetFilenameStyle(filenameStyle)
+	v.SetCheckContainers(verifyContainers)
+	v.SetCheckCompleteness(verifyComplete)
+	var result *verifier.Result
+	if verifyRecursive {
+		result, err = v.VerifyLibrary(absPath)
+	} else {
+		result, err = v.VerifyPath(absPath, mediaType)
+	}
 	if err != nil {
 		return fmt.Errorf("verification failed: %w", err)
 	}
 
 	// Output results
-	if verifyJSONOutput {
-		return outputJSON(result)
+	if format != output.FormatText {
+		return printResult(format, buildVerifyReport(result))
 	}
 
 	return outputHuman(result, verifyStrict)
 }
 
-// outputJSON outputs results in JSON format
-func outputJSON(result *verifier.Result) error {
-	output := struct {
-		Path         string                  `json:"path"`
-		CheckedDirs  int                     `json:"checked_directories"`
-		ErrorCount   int                     `json:"error_count"`
-		WarningCount int                     `json:"warning_count"`
-		MediaCounts  map[types.MediaType]int `json:"media_counts"`
-		Violations   []verifier.Violation    `json:"violations"`
-	}{
-		Path:         result.Path,
-		CheckedDirs:  result.CheckedDirs,
-		ErrorCount:   result.ErrorCount,
-		WarningCount: result.WarningCount,
-		MediaCounts:  result.MediaCounts,
-		Violations:   result.Violations,
-	}
-
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(output)
+// verifyReport is the JSON form of a verification result
+type verifyReport struct {
+	SchemaVersion int                     `json:"schema_version"`
+	Path          string                  `json:"path"`
+	CheckedDirs   int                     `json:"checked_directories"`
+	ErrorCount    int                     `json:"error_count"`
+	WarningCount  int                     `json:"warning_count"`
+	MediaCounts   map[types.MediaType]int `json:"media_counts"`
+	ItemCounts    map[types.MediaType]int `json:"item_counts"`
+	Violations    []verifier.Violation    `json:"violations"`
+}
+
+// buildVerifyReport converts a verification result into its JSON report,
+// encoding empty counts and violations as {} and [] rather than null
+func buildVerifyReport(result *verifier.Result) verifyReport {
+	report := verifyReport{
+		SchemaVersion: util.JSONSchemaVersion,
+		Path:          result.Path,
+		CheckedDirs:   result.CheckedDirs,
+		ErrorCount:    result.ErrorCount,
+		WarningCount:  result.WarningCount,
+		MediaCounts:   result.MediaCounts,
+		ItemCounts:    result.ItemCounts,
+		Violations:    result.Violations,
+	}
+	if report.MediaCounts == nil {
+		report.MediaCounts = map[types.MediaType]int{}
+	}
+	if report.ItemCounts == nil {
+		report.ItemCounts = map[types.MediaType]int{}
+	}
+	if report.Violations == nil {
+		report.Violations = []verifier.Violation{}
+	}
+	return report
 }
 
 // outputHuman outputs results in human-readable format
@@ -120,6 +168,16 @@ func outputHuman(result *verifier.Result, strict bool) error {
 	fmt.Printf("Warnings:            %d\n", result.WarningCount)
 	fmt.Println()
 
+	if len(result.ItemCounts) > 0 {
+		fmt.Println("Items verified by media type:")
+		for _, mediaType := range []types.MediaType{types.MediaTypeMovie, types.MediaTypeTV, types.MediaTypeMusic, types.MediaTypeBook} {
+			if count := result.ItemCounts[mediaType]; count > 0 {
+				fmt.Printf("  %s: %d (%d issue(s))\n", mediaType, count, result.MediaCounts[mediaType])
+			}
+		}
+		fmt.Println()
+	}
+
 	// Display media type breakdown if available
 	if len(result.MediaCounts) > 0 {
 		fmt.Println("Issues by media type:")
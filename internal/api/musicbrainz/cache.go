@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -15,6 +16,9 @@ import (
 // Cache manages local caching of MusicBrainz API responses
 type Cache struct {
 	dir string
+	// hits and misses count Get lookups for performance reporting
+	hits   atomic.Int64
+	misses atomic.Int64
 }
 
 // NewCache creates a new cache instance
@@ -43,12 +47,14 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		// Cache miss - file doesn't exist or can't be read
+		c.misses.Add(1)
 		return nil, false
 	}
 
 	var cached CachedResponse
 	if err := json.Unmarshal(data, &cached); err != nil {
 		log.Debug().Err(err).Str("file", filename).Msg("Failed to unmarshal cached response")
+		c.misses.Add(1)
 		return nil, false
 	}
 
@@ -60,13 +66,20 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 		if err := os.Remove(filename); err != nil {
 			log.Warn().Err(err).Str("file", filename).Msg("Failed to remove expired cache file")
 		}
+		c.misses.Add(1)
 		return nil, false
 	}
 
 	log.Debug().Str("key", key).Msg("Cache hit")
+	c.hits.Add(1)
 	return cached.Data, true
 }
 
+// Stats returns the number of cache hits and misses since the cache was created
+func (c *Cache) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
 // Set stores a response in the cache with the specified TTL
 func (c *Cache) Set(key string, data interface{}, ttl int) error {
 	cached := CachedResponse{
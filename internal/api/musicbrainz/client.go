@@ -1,7 +1,9 @@
 package musicbrainz
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -26,6 +28,9 @@ const (
 	UserAgent = "go-jf-org/1.0 (https://github.com/opd-ai/go-jf-org)"
 )
 
+// ErrOffline is returned in offline mode for a request that is not cached
+var ErrOffline = errors.New("offline and no cached MusicBrainz response")
+
 // Client represents a MusicBrainz API client
 type Client struct {
 	httpClient  *http.Client
@@ -33,6 +38,7 @@ type Client struct {
 	cache       *Cache
 	baseURL     string
 	userAgent   string
+	offline     bool
 }
 
 // Config holds configuration for the MusicBrainz client
@@ -40,6 +46,8 @@ type Config struct {
 	CacheDir  string
 	Timeout   time.Duration
 	UserAgent string
+	// Offline serves responses from the cache only and never makes requests
+	Offline bool
 }
 
 // NewClient creates a new MusicBrainz API client
@@ -65,11 +73,12 @@ func NewClient(config Config) (*Client, error) {
 		cache:       cache,
 		baseURL:     BaseURL,
 		userAgent:   config.UserAgent,
+		offline:     config.Offline,
 	}, nil
 }
 
 // get performs a GET request to the MusicBrainz API with rate limiting and caching
-func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
 	// Add format parameter for JSON response
 	if params == nil {
 		params = url.Values{}
@@ -88,14 +97,19 @@ func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
 			return jsonData, nil
 		}
 	}
+	if c.offline {
+		return nil, fmt.Errorf("%w: %s", ErrOffline, endpoint)
+	}
 
 	// Rate limiting - wait for token
 	log.Debug().Str("endpoint", endpoint).Msg("Waiting for rate limiter")
-	c.rateLimiter.Wait()
+	if err := c.rateLimiter.WaitContext(ctx); err != nil {
+		return nil, err
+	}
 
 	// Make HTTP request
 	log.Debug().Str("endpoint", endpoint).Msg("Making MusicBrainz API request")
-	req, err := http.NewRequest("GET", apiURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -137,7 +151,7 @@ func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
 }
 
 // SearchRelease searches for releases (albums) by title and artist
-func (c *Client) SearchRelease(title string, artist string) (*SearchReleaseResponse, error) {
+func (c *Client) SearchRelease(ctx context.Context, title string, artist string) (*SearchReleaseResponse, error) {
 	params := url.Values{}
 
 	// Build Lucene query
@@ -159,7 +173,7 @@ func (c *Client) SearchRelease(title string, artist string) (*SearchReleaseRespo
 	params.Set("query", query)
 	params.Set("limit", "5") // Limit results
 
-	body, err := c.get("/release", params)
+	body, err := c.get(ctx, "/release", params)
 	if err != nil {
 		return nil, err
 	}
@@ -173,12 +187,12 @@ func (c *Client) SearchRelease(title string, artist string) (*SearchReleaseRespo
 }
 
 // GetReleaseDetails retrieves detailed information about a specific release
-func (c *Client) GetReleaseDetails(releaseID string) (*ReleaseDetails, error) {
+func (c *Client) GetReleaseDetails(ctx context.Context, releaseID string) (*ReleaseDetails, error) {
 	params := url.Values{}
 	params.Set("inc", "artists+labels+recordings") // Include related data
 
 	endpoint := fmt.Sprintf("/release/%s", releaseID)
-	body, err := c.get(endpoint, params)
+	body, err := c.get(ctx, endpoint, params)
 	if err != nil {
 		return nil, err
 	}
@@ -192,7 +206,7 @@ func (c *Client) GetReleaseDetails(releaseID string) (*ReleaseDetails, error) {
 }
 
 // SearchArtist searches for artists by name
-func (c *Client) SearchArtist(name string) (*SearchArtistResponse, error) {
+func (c *Client) SearchArtist(ctx context.Context, name string) (*SearchArtistResponse, error) {
 	if name == "" {
 		return nil, fmt.Errorf("artist name is required")
 	}
@@ -201,7 +215,7 @@ func (c *Client) SearchArtist(name string) (*SearchArtistResponse, error) {
 	params.Set("query", fmt.Sprintf("artist:\"%s\"", name))
 	params.Set("limit", "5")
 
-	body, err := c.get("/artist", params)
+	body, err := c.get(ctx, "/artist", params)
 	if err != nil {
 		return nil, err
 	}
@@ -215,12 +229,12 @@ func (c *Client) SearchArtist(name string) (*SearchArtistResponse, error) {
 }
 
 // GetArtistDetails retrieves detailed information about a specific artist
-func (c *Client) GetArtistDetails(artistID string) (*ArtistDetails, error) {
+func (c *Client) GetArtistDetails(ctx context.Context, artistID string) (*ArtistDetails, error) {
 	params := url.Values{}
 	params.Set("inc", "aliases") // Include aliases
 
 	endpoint := fmt.Sprintf("/artist/%s", artistID)
-	body, err := c.get(endpoint, params)
+	body, err := c.get(ctx, endpoint, params)
 	if err != nil {
 		return nil, err
 	}
@@ -232,3 +246,14 @@ func (c *Client) GetArtistDetails(artistID string) (*ArtistDetails, error) {
 
 	return &details, nil
 }
+
+// CacheStats returns the number of cache hits and misses for this client
+func (c *Client) CacheStats() (hits, misses int64) {
+	return c.cache.Stats()
+}
+
+// RateLimitStats returns the requests this client's rate limiter let
+// through, how often it held one back, and the total time spent waiting
+func (c *Client) RateLimitStats() (requests, waits int64, waited time.Duration) {
+	return c.rateLimiter.Stats()
+}
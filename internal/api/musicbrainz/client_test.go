@@ -1,6 +1,7 @@
 package musicbrainz
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -120,7 +121,7 @@ func TestSearchRelease(t *testing.T) {
 	client.baseURL = server.URL
 
 	// Test search
-	result, err := client.SearchRelease("Dark Side of the Moon", "Pink Floyd")
+	result, err := client.SearchRelease(context.Background(), "Dark Side of the Moon", "Pink Floyd")
 	if err != nil {
 		t.Errorf("SearchRelease() error = %v", err)
 		return
@@ -193,7 +194,7 @@ func TestGetReleaseDetails(t *testing.T) {
 	client.baseURL = server.URL
 
 	// Test get details
-	details, err := client.GetReleaseDetails("test-release-id")
+	details, err := client.GetReleaseDetails(context.Background(), "test-release-id")
 	if err != nil {
 		t.Errorf("GetReleaseDetails() error = %v", err)
 		return
@@ -325,6 +326,11 @@ func TestRateLimiter(t *testing.T) {
 		if elapsed < 900*time.Millisecond {
 			t.Errorf("Wait() elapsed = %v, want >= 900ms", elapsed)
 		}
+
+		requests, waits, waited := rl.Stats()
+		if requests != 2 || waits != 1 || waited < 900*time.Millisecond {
+			t.Errorf("Stats() = %d, %d, %v; want 2 requests, 1 wait of >= 900ms", requests, waits, waited)
+		}
 	})
 }
 
@@ -1,6 +1,7 @@
 package musicbrainz
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -14,6 +15,8 @@ const (
 	MinMusicYear = 1900
 	// MaxMusicYear is the latest valid year (future releases)
 	MaxMusicYear = 2100
+	// TrackDurationTolerance is the maximum difference in seconds for duration-based track matching
+	TrackDurationTolerance = 3
 )
 
 // Enricher enriches metadata using MusicBrainz API
@@ -27,7 +30,7 @@ func NewEnricher(client *Client) *Enricher {
 }
 
 // EnrichMusic enriches music metadata with MusicBrainz data
-func (e *Enricher) EnrichMusic(metadata *types.Metadata) error {
+func (e *Enricher) EnrichMusic(ctx context.Context, metadata *types.Metadata) error {
 	if metadata == nil {
 		return fmt.Errorf("metadata is nil")
 	}
@@ -54,7 +57,7 @@ func (e *Enricher) EnrichMusic(metadata *types.Metadata) error {
 		Msg("Enriching music metadata")
 
 	// Search for release
-	searchResp, err := e.client.SearchRelease(album, artist)
+	searchResp, err := e.client.SearchRelease(ctx, album, artist)
 	if err != nil {
 		return fmt.Errorf("failed to search release: %w", err)
 	}
@@ -71,7 +74,7 @@ func (e *Enricher) EnrichMusic(metadata *types.Metadata) error {
 	release := searchResp.Releases[0]
 
 	// Get detailed information
-	details, err := e.client.GetReleaseDetails(release.ID)
+	details, err := e.client.GetReleaseDetails(ctx, release.ID)
 	if err != nil {
 		log.Warn().Err(err).Str("id", release.ID).Msg("Failed to get release details")
 		// Use search result data only
@@ -79,6 +82,9 @@ func (e *Enricher) EnrichMusic(metadata *types.Metadata) error {
 		return nil
 	}
 
+	// Match the file to a track before release details fill any remaining gaps
+	e.applyTrackListing(metadata, details)
+
 	// Apply enriched metadata
 	e.applyReleaseDetails(metadata, details)
 
@@ -168,6 +174,107 @@ func (e *Enricher) applyReleaseDetails(metadata *types.Metadata, details *Releas
 		Msg("Applied MusicBrainz metadata")
 }
 
+// applyTrackListing matches the file to a track in the release's media by
+// track number (and disc, if known) or, failing that, by duration, and sets
+// the title, track number and disc number from MusicBrainz
+func (e *Enricher) applyTrackListing(metadata *types.Metadata, details *ReleaseDetails) {
+	track, disc := e.matchTrack(metadata.MusicMetadata, details.Media)
+	if track == nil {
+		return
+	}
+
+	title := track.Title
+	if title == "" {
+		title = track.Recording.Title
+	}
+	if title != "" {
+		metadata.Title = title
+	}
+	metadata.MusicMetadata.TrackNumber = track.Position
+	if disc > 0 {
+		metadata.MusicMetadata.DiscNumber = disc
+	}
+
+	log.Debug().
+		Str("title", metadata.Title).
+		Int("track", track.Position).
+		Int("disc", disc).
+		Msg("Matched MusicBrainz track")
+}
+
+// matchTrack finds the track matching the music metadata, returning the track and its disc position.
+// A track number matches on the known disc, if any; when it matches on several
+// discs, the duration picks between them.
+func (e *Enricher) matchTrack(music *types.MusicMetadata, media []Media) (*Track, int) {
+	// Match by track number (restricted to the known disc, if any)
+	if music.TrackNumber > 0 {
+		var candidates []trackRef
+		for i := range media {
+			if music.DiscNumber > 0 && media[i].Position != music.DiscNumber {
+				continue
+			}
+			for j := range media[i].Tracks {
+				if media[i].Tracks[j].Position == music.TrackNumber {
+					candidates = append(candidates, trackRef{&media[i].Tracks[j], media[i].Position})
+				}
+			}
+		}
+		if len(candidates) > 1 && music.Duration > 0 {
+			if best := closestTrack(music.Duration, candidates); best.track != nil {
+				return best.track, best.disc
+			}
+		}
+		if len(candidates) > 0 {
+			return candidates[0].track, candidates[0].disc
+		}
+	}
+
+	// Fall back to the closest track by duration
+	if music.Duration > 0 {
+		var all []trackRef
+		for i := range media {
+			for j := range media[i].Tracks {
+				all = append(all, trackRef{&media[i].Tracks[j], media[i].Position})
+			}
+		}
+		if best := closestTrack(music.Duration, all); best.track != nil {
+			return best.track, best.disc
+		}
+	}
+
+	return nil, 0
+}
+
+// trackRef is a release track with the position of its disc
+type trackRef struct {
+	track *Track
+	disc  int
+}
+
+// closestTrack returns the candidate whose length is nearest to duration
+// (in seconds), or a zero trackRef if none is within TrackDurationTolerance
+func closestTrack(duration int, candidates []trackRef) trackRef {
+	var best trackRef
+	bestDiff := TrackDurationTolerance + 1
+	for _, c := range candidates {
+		length := c.track.Length
+		if length == 0 {
+			length = c.track.Recording.Length
+		}
+		if length == 0 {
+			continue
+		}
+		diff := duration - (length+500)/1000
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff < bestDiff {
+			best, bestDiff = c, diff
+		}
+	}
+	return best
+}
+
 // extractYear extracts year from date string (YYYY-MM-DD or YYYY)
 func (e *Enricher) extractYear(dateStr string) int {
 	if dateStr == "" {
@@ -0,0 +1,143 @@
+package musicbrainz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// newTrackListingServer returns a mock MusicBrainz server with a two-disc release
+func newTrackListingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if strings.HasPrefix(r.URL.Path, "/release/") {
+			json.NewEncoder(w).Encode(ReleaseDetails{
+				ID:           "release-id",
+				Title:        "Test Album",
+				Date:         "1999-05-01",
+				ArtistCredit: []ArtistCredit{{Name: "Test Artist", Artist: Artist{ID: "artist-id", Name: "Test Artist"}}},
+				Media: []Media{
+					{
+						Position: 1,
+						Tracks: []Track{
+							{Position: 1, Title: "Opening", Length: 180000},
+							{Position: 2, Title: "Second Song", Length: 241000},
+						},
+					},
+					{
+						Position: 2,
+						Tracks: []Track{
+							{Position: 1, Title: "Disc Two Opener", Length: 300000},
+							{Position: 2, Recording: Recording{Title: "Recording Title", Length: 95000}},
+						},
+					},
+				},
+			})
+			return
+		}
+
+		json.NewEncoder(w).Encode(SearchReleaseResponse{
+			Count:    1,
+			Releases: []Release{{ID: "release-id", Title: "Test Album"}},
+		})
+	}))
+}
+
+func TestEnrichMusic_TrackListing(t *testing.T) {
+	server := newTrackListingServer(t)
+	defer server.Close()
+
+	client, err := NewClient(Config{CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+	enricher := NewEnricher(client)
+
+	tests := []struct {
+		name      string
+		title     string
+		music     types.MusicMetadata
+		wantTitle string
+		wantTrack int
+		wantDisc  int
+	}{
+		{
+			name:      "match by track number",
+			title:     "track02",
+			music:     types.MusicMetadata{Album: "Test Album", TrackNumber: 2},
+			wantTitle: "Second Song",
+			wantTrack: 2,
+			wantDisc:  1,
+		},
+		{
+			name:      "match by track and disc number",
+			title:     "track01",
+			music:     types.MusicMetadata{Album: "Test Album", TrackNumber: 1, DiscNumber: 2},
+			wantTitle: "Disc Two Opener",
+			wantTrack: 1,
+			wantDisc:  2,
+		},
+		{
+			name:      "ambiguous track number resolved by duration",
+			title:     "track01",
+			music:     types.MusicMetadata{Album: "Test Album", TrackNumber: 1, Duration: 299},
+			wantTitle: "Disc Two Opener",
+			wantTrack: 1,
+			wantDisc:  2,
+		},
+		{
+			name:      "ambiguous track number without close duration keeps first disc",
+			title:     "track01",
+			music:     types.MusicMetadata{Album: "Test Album", TrackNumber: 1, Duration: 30},
+			wantTitle: "Opening",
+			wantTrack: 1,
+			wantDisc:  1,
+		},
+		{
+			name:      "match by duration uses recording title",
+			title:     "unknown",
+			music:     types.MusicMetadata{Album: "Test Album", Duration: 96},
+			wantTitle: "Recording Title",
+			wantTrack: 2,
+			wantDisc:  2,
+		},
+		{
+			name:      "no match outside duration tolerance",
+			title:     "unknown",
+			music:     types.MusicMetadata{Album: "Test Album", Duration: 500},
+			wantTitle: "unknown",
+			wantTrack: 0,
+			wantDisc:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			music := tt.music
+			metadata := &types.Metadata{Title: tt.title, MusicMetadata: &music}
+
+			if err := enricher.EnrichMusic(context.Background(), metadata); err != nil {
+				t.Fatalf("EnrichMusic() error = %v", err)
+			}
+
+			if metadata.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", metadata.Title, tt.wantTitle)
+			}
+			if metadata.MusicMetadata.TrackNumber != tt.wantTrack {
+				t.Errorf("TrackNumber = %d, want %d", metadata.MusicMetadata.TrackNumber, tt.wantTrack)
+			}
+			if metadata.MusicMetadata.DiscNumber != tt.wantDisc {
+				t.Errorf("DiscNumber = %d, want %d", metadata.MusicMetadata.DiscNumber, tt.wantDisc)
+			}
+		})
+	}
+}
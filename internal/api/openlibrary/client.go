@@ -1,7 +1,9 @@
 package openlibrary
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -27,12 +29,16 @@ const (
 	UserAgent = "go-jf-org/1.0 (https://github.com/opd-ai/go-jf-org)"
 )
 
+// ErrOffline is returned in offline mode for a request that is not cached
+var ErrOffline = errors.New("offline and no cached OpenLibrary response")
+
 // Client represents an OpenLibrary API client
 type Client struct {
 	httpClient *http.Client
 	cache      *Cache
 	baseURL    string
 	userAgent  string
+	offline    bool
 }
 
 // Config holds configuration for the OpenLibrary client
@@ -40,6 +46,8 @@ type Config struct {
 	CacheDir  string
 	Timeout   time.Duration
 	UserAgent string
+	// Offline serves responses from the cache only and never makes requests
+	Offline bool
 }
 
 // NewClient creates a new OpenLibrary API client
@@ -64,11 +72,12 @@ func NewClient(config Config) (*Client, error) {
 		cache:     cache,
 		baseURL:   BaseURL,
 		userAgent: config.UserAgent,
+		offline:   config.Offline,
 	}, nil
 }
 
 // get performs a GET request to the OpenLibrary API with caching
-func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
 	// Construct URL
 	apiURL := fmt.Sprintf("%s%s", c.baseURL, endpoint)
 	if params != nil && len(params) > 0 {
@@ -84,10 +93,13 @@ func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
 			return jsonData, nil
 		}
 	}
+	if c.offline {
+		return nil, fmt.Errorf("%w: %s", ErrOffline, endpoint)
+	}
 
 	// Make HTTP request
 	log.Debug().Str("endpoint", endpoint).Msg("Making OpenLibrary API request")
-	req, err := http.NewRequest("GET", apiURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -128,7 +140,7 @@ func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
 }
 
 // Search searches for books by title and/or author
-func (c *Client) Search(title string, author string) (*SearchResponse, error) {
+func (c *Client) Search(ctx context.Context, title string, author string) (*SearchResponse, error) {
 	if title == "" && author == "" {
 		return nil, fmt.Errorf("title or author is required")
 	}
@@ -146,7 +158,7 @@ func (c *Client) Search(title string, author string) (*SearchResponse, error) {
 	params.Set("q", strings.Join(queryParts, " "))
 	params.Set("limit", "5")
 
-	body, err := c.get("/search.json", params)
+	body, err := c.get(ctx, "/search.json", params)
 	if err != nil {
 		return nil, err
 	}
@@ -160,13 +172,13 @@ func (c *Client) Search(title string, author string) (*SearchResponse, error) {
 }
 
 // GetBookByISBN retrieves book information by ISBN
-func (c *Client) GetBookByISBN(isbn string) (*ISBNResponse, error) {
+func (c *Client) GetBookByISBN(ctx context.Context, isbn string) (*ISBNResponse, error) {
 	if isbn == "" {
 		return nil, fmt.Errorf("ISBN is required")
 	}
 
 	endpoint := fmt.Sprintf("/isbn/%s.json", isbn)
-	body, err := c.get(endpoint, nil)
+	body, err := c.get(ctx, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -180,7 +192,7 @@ func (c *Client) GetBookByISBN(isbn string) (*ISBNResponse, error) {
 }
 
 // GetBookDetails retrieves detailed information about a specific book
-func (c *Client) GetBookDetails(bookKey string) (*BookDetails, error) {
+func (c *Client) GetBookDetails(ctx context.Context, bookKey string) (*BookDetails, error) {
 	if bookKey == "" {
 		return nil, fmt.Errorf("book key is required")
 	}
@@ -191,7 +203,7 @@ func (c *Client) GetBookDetails(bookKey string) (*BookDetails, error) {
 	}
 
 	endpoint := fmt.Sprintf("%s.json", bookKey)
-	body, err := c.get(endpoint, nil)
+	body, err := c.get(ctx, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -205,7 +217,7 @@ func (c *Client) GetBookDetails(bookKey string) (*BookDetails, error) {
 }
 
 // GetWorkDetails retrieves detailed information about a work
-func (c *Client) GetWorkDetails(workKey string) (*WorkDetails, error) {
+func (c *Client) GetWorkDetails(ctx context.Context, workKey string) (*WorkDetails, error) {
 	if workKey == "" {
 		return nil, fmt.Errorf("work key is required")
 	}
@@ -216,7 +228,7 @@ func (c *Client) GetWorkDetails(workKey string) (*WorkDetails, error) {
 	}
 
 	endpoint := fmt.Sprintf("%s.json", workKey)
-	body, err := c.get(endpoint, nil)
+	body, err := c.get(ctx, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +242,7 @@ func (c *Client) GetWorkDetails(workKey string) (*WorkDetails, error) {
 }
 
 // GetAuthorDetails retrieves detailed information about an author
-func (c *Client) GetAuthorDetails(authorKey string) (*AuthorDetails, error) {
+func (c *Client) GetAuthorDetails(ctx context.Context, authorKey string) (*AuthorDetails, error) {
 	if authorKey == "" {
 		return nil, fmt.Errorf("author key is required")
 	}
@@ -241,7 +253,7 @@ func (c *Client) GetAuthorDetails(authorKey string) (*AuthorDetails, error) {
 	}
 
 	endpoint := fmt.Sprintf("%s.json", authorKey)
-	body, err := c.get(endpoint, nil)
+	body, err := c.get(ctx, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -267,3 +279,8 @@ func (c *Client) GetCoverURL(coverID int, size string) string {
 
 	return fmt.Sprintf("https://covers.openlibrary.org/b/id/%d-%s.jpg", coverID, size)
 }
+
+// CacheStats returns the number of cache hits and misses for this client
+func (c *Client) CacheStats() (hits, misses int64) {
+	return c.cache.Stats()
+}
@@ -1,6 +1,7 @@
 package openlibrary
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -108,7 +109,7 @@ func TestSearch(t *testing.T) {
 	client.baseURL = server.URL
 
 	// Test search
-	result, err := client.Search("The Great Gatsby", "F. Scott Fitzgerald")
+	result, err := client.Search(context.Background(), "The Great Gatsby", "F. Scott Fitzgerald")
 	if err != nil {
 		t.Errorf("Search() error = %v", err)
 		return
@@ -164,7 +165,7 @@ func TestGetBookByISBN(t *testing.T) {
 	client.baseURL = server.URL
 
 	// Test get by ISBN
-	book, err := client.GetBookByISBN("9780743273565")
+	book, err := client.GetBookByISBN(context.Background(), "9780743273565")
 	if err != nil {
 		t.Errorf("GetBookByISBN() error = %v", err)
 		return
@@ -312,13 +313,13 @@ func TestSearchErrors(t *testing.T) {
 	}
 
 	// Test with empty title and author
-	_, err = client.Search("", "")
+	_, err = client.Search(context.Background(), "", "")
 	if err == nil {
 		t.Error("Search() with empty params should return error")
 	}
 
 	// Test with empty ISBN
-	_, err = client.GetBookByISBN("")
+	_, err = client.GetBookByISBN(context.Background(), "")
 	if err == nil {
 		t.Error("GetBookByISBN() with empty ISBN should return error")
 	}
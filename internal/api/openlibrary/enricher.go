@@ -1,10 +1,12 @@
 package openlibrary
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/opd-ai/go-jf-org/internal/isbn"
 	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 	"github.com/rs/zerolog/log"
@@ -28,7 +30,7 @@ func NewEnricher(client *Client) *Enricher {
 }
 
 // EnrichBook enriches book metadata with OpenLibrary data
-func (e *Enricher) EnrichBook(metadata *types.Metadata) error {
+func (e *Enricher) EnrichBook(ctx context.Context, metadata *types.Metadata) error {
 	if metadata == nil {
 		return fmt.Errorf("metadata is nil")
 	}
@@ -38,9 +40,20 @@ func (e *Enricher) EnrichBook(metadata *types.Metadata) error {
 		metadata.BookMetadata = &types.BookMetadata{}
 	}
 
+	// Normalize ISBN before lookup; invalid ISBNs are dropped so the search can fill a valid one
+	if metadata.BookMetadata.ISBN != "" {
+		normalized, err := isbn.Normalize(metadata.BookMetadata.ISBN)
+		if err != nil {
+			log.Warn().Err(err).Str("isbn", metadata.BookMetadata.ISBN).Msg("Invalid ISBN, skipping ISBN lookup")
+			metadata.BookMetadata.ISBN = ""
+		} else {
+			metadata.BookMetadata.ISBN = normalized
+		}
+	}
+
 	// Try ISBN lookup first if available
 	if metadata.BookMetadata.ISBN != "" {
-		if isbnErr := e.enrichByISBN(metadata); isbnErr == nil {
+		if isbnErr := e.enrichByISBN(ctx, metadata); isbnErr == nil {
 			return nil
 		} else {
 			log.Debug().Err(isbnErr).Msg("ISBN lookup failed, falling back to search")
@@ -61,7 +74,7 @@ func (e *Enricher) EnrichBook(metadata *types.Metadata) error {
 		Msg("Enriching book metadata")
 
 	// Search for book
-	searchResp, err := e.client.Search(title, author)
+	searchResp, err := e.client.Search(ctx, title, author)
 	if err != nil {
 		return fmt.Errorf("failed to search book: %w", err)
 	}
@@ -82,7 +95,7 @@ func (e *Enricher) EnrichBook(metadata *types.Metadata) error {
 
 	// Try to get more details if we have a key
 	if book.Key != "" {
-		details, err := e.client.GetBookDetails(book.Key)
+		details, err := e.client.GetBookDetails(ctx, book.Key)
 		if err != nil {
 			log.Debug().Err(err).Str("key", book.Key).Msg("Failed to get book details")
 		} else {
@@ -100,11 +113,11 @@ func (e *Enricher) EnrichBook(metadata *types.Metadata) error {
 }
 
 // enrichByISBN enriches metadata using ISBN lookup
-func (e *Enricher) enrichByISBN(metadata *types.Metadata) error {
-	isbn := metadata.BookMetadata.ISBN
-	log.Debug().Str("isbn", isbn).Msg("Looking up book by ISBN")
+func (e *Enricher) enrichByISBN(ctx context.Context, metadata *types.Metadata) error {
+	bookISBN := metadata.BookMetadata.ISBN
+	log.Debug().Str("isbn", bookISBN).Msg("Looking up book by ISBN")
 
-	response, err := e.client.GetBookByISBN(isbn)
+	response, err := e.client.GetBookByISBN(ctx, bookISBN)
 	if err != nil {
 		return err
 	}
@@ -117,7 +130,7 @@ func (e *Enricher) enrichByISBN(metadata *types.Metadata) error {
 	// Set author from authors reference
 	if metadata.BookMetadata.Author == "" && len(response.Authors) > 0 {
 		// Get author details
-		authorDetails, err := e.client.GetAuthorDetails(response.Authors[0].Key)
+		authorDetails, err := e.client.GetAuthorDetails(ctx, response.Authors[0].Key)
 		if err == nil {
 			metadata.BookMetadata.Author = authorDetails.Name
 		}
@@ -138,13 +151,13 @@ func (e *Enricher) enrichByISBN(metadata *types.Metadata) error {
 
 	// Set description from work if available
 	if metadata.BookMetadata.Description == "" && len(response.Works) > 0 {
-		workDetails, err := e.client.GetWorkDetails(response.Works[0].Key)
+		workDetails, err := e.client.GetWorkDetails(ctx, response.Works[0].Key)
 		if err == nil {
 			metadata.BookMetadata.Description = e.extractDescription(workDetails.Description)
 		}
 	}
 
-	log.Info().Str("isbn", isbn).Msg("Book metadata enriched by ISBN")
+	log.Info().Str("isbn", bookISBN).Msg("Book metadata enriched by ISBN")
 	return nil
 }
 
@@ -165,18 +178,9 @@ func (e *Enricher) applyBookSearchResult(metadata *types.Metadata, book *BookDoc
 		metadata.Year = book.FirstPublishYear
 	}
 
-	// Set ISBN
+	// Set ISBN (first valid candidate, normalized to ISBN-13)
 	if metadata.BookMetadata.ISBN == "" && len(book.ISBN) > 0 {
-		// Prefer ISBN-13, fall back to ISBN-10
-		for _, isbn := range book.ISBN {
-			if len(isbn) == 13 {
-				metadata.BookMetadata.ISBN = isbn
-				break
-			}
-		}
-		if metadata.BookMetadata.ISBN == "" {
-			metadata.BookMetadata.ISBN = book.ISBN[0]
-		}
+		metadata.BookMetadata.ISBN = firstValidISBN(book.ISBN)
 	}
 
 	// Set publisher
@@ -222,11 +226,7 @@ func (e *Enricher) applyBookDetails(metadata *types.Metadata, details *BookDetai
 
 	// Set ISBN (prefer ISBN-13)
 	if metadata.BookMetadata.ISBN == "" {
-		if len(details.ISBN13) > 0 {
-			metadata.BookMetadata.ISBN = details.ISBN13[0]
-		} else if len(details.ISBN10) > 0 {
-			metadata.BookMetadata.ISBN = details.ISBN10[0]
-		}
+		metadata.BookMetadata.ISBN = firstValidISBN(append(details.ISBN13, details.ISBN10...))
 	}
 
 	log.Debug().
@@ -241,6 +241,20 @@ func (e *Enricher) applyBookDetails(metadata *types.Metadata, details *BookDetai
 		Msg("Applied OpenLibrary book details")
 }
 
+// firstValidISBN returns the first candidate that normalizes to a valid ISBN-13,
+// or an empty string if none are valid
+func firstValidISBN(candidates []string) string {
+	for _, candidate := range candidates {
+		normalized, err := isbn.Normalize(candidate)
+		if err != nil {
+			log.Debug().Err(err).Str("isbn", candidate).Msg("Ignoring invalid ISBN from OpenLibrary")
+			continue
+		}
+		return normalized
+	}
+	return ""
+}
+
 // extractDescription extracts description string from interface{} (can be string or object)
 func (e *Enricher) extractDescription(desc interface{}) string {
 	if desc == nil {
@@ -1,11 +1,14 @@
 package tmdb
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -23,6 +26,9 @@ const (
 	CacheTTLNotFound = 3600  // 1 hour
 )
 
+// ErrOffline is returned in offline mode for a request that is not cached
+var ErrOffline = errors.New("offline and no cached TMDB response")
+
 // Client represents a TMDB API client
 type Client struct {
 	apiKey      string
@@ -30,6 +36,8 @@ type Client struct {
 	rateLimiter *RateLimiter
 	cache       *Cache
 	baseURL     string
+	region      string
+	offline     bool
 }
 
 // Config holds configuration for the TMDB client
@@ -37,6 +45,8 @@ type Config struct {
 	APIKey   string
 	CacheDir string
 	Timeout  time.Duration
+	// Offline serves responses from the cache only and never makes requests
+	Offline bool
 }
 
 // NewClient creates a new TMDB API client
@@ -62,11 +72,18 @@ func NewClient(config Config) (*Client, error) {
 		rateLimiter: NewTMDBRateLimiter(),
 		cache:       cache,
 		baseURL:     BaseURL,
+		offline:     config.Offline,
 	}, nil
 }
 
+// SetRegion sets the ISO 3166-1 country sent with movie searches so results
+// favor that country's releases; empty sends none
+func (c *Client) SetRegion(region string) {
+	c.region = strings.ToUpper(region)
+}
+
 // get performs a GET request to the TMDB API with rate limiting and caching
-func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
 	// Add API key to parameters
 	if params == nil {
 		params = url.Values{}
@@ -85,14 +102,23 @@ func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
 			return jsonData, nil
 		}
 	}
+	if c.offline {
+		return nil, fmt.Errorf("%w: %s", ErrOffline, endpoint)
+	}
 
 	// Rate limiting - wait for token
 	log.Debug().Str("endpoint", endpoint).Msg("Waiting for rate limiter")
-	c.rateLimiter.Wait()
+	if err := c.rateLimiter.WaitContext(ctx); err != nil {
+		return nil, err
+	}
 
 	// Make HTTP request
 	log.Debug().Str("endpoint", endpoint).Msg("Making TMDB API request")
-	resp, err := c.httpClient.Get(apiURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
@@ -125,14 +151,17 @@ func (c *Client) get(endpoint string, params url.Values) ([]byte, error) {
 }
 
 // SearchMovie searches for movies by title and optional year
-func (c *Client) SearchMovie(title string, year int) (*SearchMovieResponse, error) {
+func (c *Client) SearchMovie(ctx context.Context, title string, year int) (*SearchMovieResponse, error) {
 	params := url.Values{}
 	params.Set("query", title)
 	if year > 0 {
 		params.Set("year", fmt.Sprintf("%d", year))
 	}
+	if c.region != "" {
+		params.Set("region", c.region)
+	}
 
-	body, err := c.get("/search/movie", params)
+	body, err := c.get(ctx, "/search/movie", params)
 	if err != nil {
 		return nil, err
 	}
@@ -152,10 +181,12 @@ func (c *Client) SearchMovie(title string, year int) (*SearchMovieResponse, erro
 }
 
 // GetMovieDetails retrieves detailed information for a movie by ID
-func (c *Client) GetMovieDetails(movieID int) (*MovieDetails, error) {
+func (c *Client) GetMovieDetails(ctx context.Context, movieID int) (*MovieDetails, error) {
 	endpoint := fmt.Sprintf("/movie/%d", movieID)
+	params := url.Values{}
+	params.Set("append_to_response", "release_dates")
 
-	body, err := c.get(endpoint, nil)
+	body, err := c.get(ctx, endpoint, params)
 	if err != nil {
 		return nil, err
 	}
@@ -174,14 +205,14 @@ func (c *Client) GetMovieDetails(movieID int) (*MovieDetails, error) {
 }
 
 // SearchTV searches for TV shows by name and optional year
-func (c *Client) SearchTV(name string, year int) (*SearchTVResponse, error) {
+func (c *Client) SearchTV(ctx context.Context, name string, year int) (*SearchTVResponse, error) {
 	params := url.Values{}
 	params.Set("query", name)
 	if year > 0 {
 		params.Set("first_air_date_year", fmt.Sprintf("%d", year))
 	}
 
-	body, err := c.get("/search/tv", params)
+	body, err := c.get(ctx, "/search/tv", params)
 	if err != nil {
 		return nil, err
 	}
@@ -201,10 +232,12 @@ func (c *Client) SearchTV(name string, year int) (*SearchTVResponse, error) {
 }
 
 // GetTVDetails retrieves detailed information for a TV show by ID
-func (c *Client) GetTVDetails(tvID int) (*TVDetails, error) {
+func (c *Client) GetTVDetails(ctx context.Context, tvID int) (*TVDetails, error) {
 	endpoint := fmt.Sprintf("/tv/%d", tvID)
+	params := url.Values{}
+	params.Set("append_to_response", "content_ratings")
 
-	body, err := c.get(endpoint, nil)
+	body, err := c.get(ctx, endpoint, params)
 	if err != nil {
 		return nil, err
 	}
@@ -222,6 +255,90 @@ func (c *Client) GetTVDetails(tvID int) (*TVDetails, error) {
 	return &result, nil
 }
 
+// GetMovieCredits retrieves the cast of a movie by ID
+func (c *Client) GetMovieCredits(ctx context.Context, movieID int) (*Credits, error) {
+	return c.getCredits(ctx, fmt.Sprintf("/movie/%d/credits", movieID))
+}
+
+// GetTVCredits retrieves the cast of a TV show by ID
+func (c *Client) GetTVCredits(ctx context.Context, tvID int) (*Credits, error) {
+	return c.getCredits(ctx, fmt.Sprintf("/tv/%d/credits", tvID))
+}
+
+// getCredits fetches and parses a credits endpoint
+func (c *Client) getCredits(ctx context.Context, endpoint string) (*Credits, error) {
+	body, err := c.get(ctx, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Credits
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse credits response: %w", err)
+	}
+
+	log.Info().
+		Str("endpoint", endpoint).
+		Int("cast", len(result.Cast)).
+		Msg("Credits retrieved")
+
+	return &result, nil
+}
+
+// GetMovieAlternativeTitles retrieves a movie's localized and alternate titles
+func (c *Client) GetMovieAlternativeTitles(ctx context.Context, movieID int) (*AlternativeTitlesResponse, error) {
+	return c.getAlternativeTitles(ctx, fmt.Sprintf("/movie/%d/alternative_titles", movieID))
+}
+
+// GetTVAlternativeTitles retrieves a TV show's localized and alternate titles
+func (c *Client) GetTVAlternativeTitles(ctx context.Context, tvID int) (*AlternativeTitlesResponse, error) {
+	return c.getAlternativeTitles(ctx, fmt.Sprintf("/tv/%d/alternative_titles", tvID))
+}
+
+// getAlternativeTitles fetches and parses an alternative titles endpoint
+func (c *Client) getAlternativeTitles(ctx context.Context, endpoint string) (*AlternativeTitlesResponse, error) {
+	body, err := c.get(ctx, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result AlternativeTitlesResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse alternative titles response: %w", err)
+	}
+
+	log.Info().
+		Str("endpoint", endpoint).
+		Int("titles", len(result.All())).
+		Msg("Alternative titles retrieved")
+
+	return &result, nil
+}
+
+// FindByIMDBID looks up movies and TV shows by IMDb ID (e.g. "tt0133093")
+func (c *Client) FindByIMDBID(ctx context.Context, imdbID string) (*FindResponse, error) {
+	params := url.Values{}
+	params.Set("external_source", "imdb_id")
+
+	body, err := c.get(ctx, "/find/"+url.PathEscape(imdbID), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result FindResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse find response: %w", err)
+	}
+
+	log.Info().
+		Str("imdb_id", imdbID).
+		Int("movies", len(result.MovieResults)).
+		Int("tv", len(result.TVResults)).
+		Msg("IMDb ID lookup completed")
+
+	return &result, nil
+}
+
 // ClearCache clears all cached TMDB responses
 func (c *Client) ClearCache() error {
 	return c.cache.Clear()
@@ -231,3 +348,14 @@ func (c *Client) ClearCache() error {
 func (c *Client) GetCacheSize() (int, error) {
 	return c.cache.Size()
 }
+
+// CacheStats returns the number of cache hits and misses for this client
+func (c *Client) CacheStats() (hits, misses int64) {
+	return c.cache.Stats()
+}
+
+// RateLimitStats returns the requests this client's rate limiter let
+// through, how often it held one back, and the total time spent waiting
+func (c *Client) RateLimitStats() (requests, waits int64, waited time.Duration) {
+	return c.rateLimiter.Stats()
+}
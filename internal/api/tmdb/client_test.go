@@ -1,10 +1,13 @@
 package tmdb
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -127,7 +130,7 @@ func TestSearchMovie(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := client.SearchMovie(tt.title, tt.year)
+			result, err := client.SearchMovie(context.Background(), tt.title, tt.year)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SearchMovie() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -181,7 +184,7 @@ func TestSearchTV(t *testing.T) {
 	}
 	client.baseURL = server.URL
 
-	result, err := client.SearchTV("Breaking Bad", 2008)
+	result, err := client.SearchTV(context.Background(), "Breaking Bad", 2008)
 	if err != nil {
 		t.Fatalf("SearchTV() error = %v", err)
 	}
@@ -229,7 +232,7 @@ func TestGetMovieDetails(t *testing.T) {
 	}
 	client.baseURL = server.URL
 
-	details, err := client.GetMovieDetails(603)
+	details, err := client.GetMovieDetails(context.Background(), 603)
 	if err != nil {
 		t.Fatalf("GetMovieDetails() error = %v", err)
 	}
@@ -247,6 +250,52 @@ func TestGetMovieDetails(t *testing.T) {
 	}
 }
 
+func TestClient_OfflineMakesNoRequests(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SearchMovieResponse{
+			Page:    1,
+			Results: []MovieResult{{ID: 603, Title: "The Matrix", ReleaseDate: "1999-03-31"}},
+		})
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	newClient := func(offline bool) *Client {
+		client, err := NewClient(Config{APIKey: "test-key", CacheDir: cacheDir, Offline: offline})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		client.baseURL = server.URL
+		return client
+	}
+
+	// A miss fails without touching the network
+	if _, err := newClient(true).SearchMovie(context.Background(), "The Matrix", 1999); !errors.Is(err, ErrOffline) {
+		t.Fatalf("offline SearchMovie() error = %v, want ErrOffline", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("offline client made %d HTTP requests, want 0", n)
+	}
+
+	// Warm the cache online, then the offline client is served from it
+	if _, err := newClient(false).SearchMovie(context.Background(), "The Matrix", 1999); err != nil {
+		t.Fatalf("online SearchMovie() error = %v", err)
+	}
+	result, err := newClient(true).SearchMovie(context.Background(), "The Matrix", 1999)
+	if err != nil {
+		t.Fatalf("offline SearchMovie() after warming error = %v", err)
+	}
+	if len(result.Results) != 1 || result.Results[0].ID != 603 {
+		t.Errorf("offline SearchMovie() = %+v", result.Results)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("HTTP requests = %d, want only the warming request", n)
+	}
+}
+
 func TestCache(t *testing.T) {
 	tmpDir := t.TempDir()
 	cache, err := NewCache(tmpDir)
@@ -334,6 +383,25 @@ func TestCache(t *testing.T) {
 	})
 }
 
+func TestCache_Stats(t *testing.T) {
+	cache, err := NewCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+
+	cache.Get("missing")
+	if err := cache.Set("key", "value", 60); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	cache.Get("key")
+	cache.Get("key")
+
+	hits, misses := cache.Stats()
+	if hits != 2 || misses != 1 {
+		t.Errorf("Stats() = %d hits, %d misses; want 2, 1", hits, misses)
+	}
+}
+
 func TestRateLimiter(t *testing.T) {
 	t.Run("basic allow", func(t *testing.T) {
 		rl := NewRateLimiter(10, 10, 1*time.Second)
@@ -385,6 +453,32 @@ func TestRateLimiter(t *testing.T) {
 		}
 	})
 
+	t.Run("stats after burst", func(t *testing.T) {
+		rl := NewRateLimiter(3, 3, 50*time.Millisecond)
+
+		// A burst of 5 Allows gets 3 through and denies 2
+		for i := 0; i < 5; i++ {
+			rl.Allow()
+		}
+		requests, waits, waited := rl.Stats()
+		if requests != 3 || waits != 2 || waited != 0 {
+			t.Errorf("Stats() after Allow burst = %d, %d, %v; want 3, 2, 0", requests, waits, waited)
+		}
+
+		// The bucket is empty, so the first Wait blocks and the next two
+		// use the refilled tokens
+		for i := 0; i < 3; i++ {
+			rl.Wait()
+		}
+		requests, waits, waited = rl.Stats()
+		if requests != 6 || waits != 3 {
+			t.Errorf("Stats() after Wait burst = %d requests, %d waits; want 6, 3", requests, waits)
+		}
+		if waited <= 0 {
+			t.Errorf("Stats() waited = %v, want time spent blocked", waited)
+		}
+	})
+
 	t.Run("TMDB rate limiter", func(t *testing.T) {
 		rl := NewTMDBRateLimiter()
 
@@ -415,12 +509,59 @@ func TestAPIErrorHandling(t *testing.T) {
 	})
 	client.baseURL = server.URL
 
-	_, err := client.SearchMovie("Test", 2000)
+	_, err := client.SearchMovie(context.Background(), "Test", 2000)
 	if err == nil {
 		t.Error("SearchMovie() expected error, got nil")
 	}
 }
 
+func TestClient_ContextCancelsRequest(t *testing.T) {
+	var cancelled atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			cancelled.Store(true)
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-key", CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	client.baseURL = server.URL
+
+	t.Run("in-flight request", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := client.SearchMovie(ctx, "Slow", 0)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("SearchMovie() error = %v, want DeadlineExceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("SearchMovie() returned after %s, want it cancelled at the deadline", elapsed)
+		}
+		server.Close() // waits for the handler to see the cancellation
+		if !cancelled.Load() {
+			t.Error("server did not see the request cancelled")
+		}
+	})
+
+	t.Run("rate limiter wait", func(t *testing.T) {
+		client.rateLimiter = NewRateLimiter(1, 1, time.Hour)
+		client.rateLimiter.Wait()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := client.SearchMovie(ctx, "Limited", 0); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("SearchMovie() error = %v, want DeadlineExceeded while waiting for a token", err)
+		}
+	})
+}
+
 func TestCacheWithRealDirectory(t *testing.T) {
 	tmpCacheDir := t.TempDir()
 
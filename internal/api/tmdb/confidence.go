@@ -0,0 +1,90 @@
+package tmdb
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MatchConfidence scores how likely a search result is the file that was
+// searched for, between 0 and 1. It multiplies three factors:
+//
+//   - title similarity: the best edit-distance ratio between query and any
+//     of titles (primary, original, matched alternate), ignoring case and
+//     punctuation
+//   - year match: 1 for the same year, 0.8 one year off (regional release
+//     dates differ), 0.4 further off and 0.9 when either year is unknown
+//   - popularity: 0.7 for a result nobody has rated, rising to 1 at a TMDB
+//     popularity of about 100, so obscure near-matches need a closer title
+func MatchConfidence(query string, year int, date string, popularity float64, titles ...string) float64 {
+	similarity := 0.0
+	for _, title := range titles {
+		if title == "" {
+			continue
+		}
+		if s := titleSimilarity(query, title); s > similarity {
+			similarity = s
+		}
+	}
+
+	return similarity * yearFactor(year, date) * popularityFactor(popularity)
+}
+
+// titleSimilarity returns 1 minus the edit distance between the titles'
+// keys divided by the longer key's length
+func titleSimilarity(a, b string) float64 {
+	ka, kb := []rune(titleKey(a)), []rune(titleKey(b))
+	longest := len(ka)
+	if len(kb) > longest {
+		longest = len(kb)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ka, kb))/float64(longest)
+}
+
+// levenshtein returns the edit distance between two rune slices
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// yearFactor compares the parsed year with a TMDB "YYYY-MM-DD" date
+func yearFactor(year int, date string) float64 {
+	resultYear, err := strconv.Atoi(strings.SplitN(date, "-", 2)[0])
+	if year <= 0 || err != nil || resultYear <= 0 {
+		return 0.9
+	}
+	switch diff := year - resultYear; {
+	case diff == 0:
+		return 1
+	case diff == 1 || diff == -1:
+		return 0.8
+	default:
+		return 0.4
+	}
+}
+
+// popularityFactor maps TMDB popularity onto 0.7-1 logarithmically
+func popularityFactor(popularity float64) float64 {
+	if popularity <= 0 {
+		return 0.7
+	}
+	return 0.7 + 0.3*math.Min(1, math.Log10(1+popularity)/2)
+}
@@ -1,51 +1,236 @@
 package tmdb
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 
+	"github.com/opd-ai/go-jf-org/internal/genre"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 	"github.com/rs/zerolog/log"
 )
 
+// DefaultImageBaseURL is the base URL used for poster and backdrop URLs
+const DefaultImageBaseURL = "https://image.tmdb.org/t/p/"
+
 // Enricher enriches metadata using TMDB API
 type Enricher struct {
-	client *Client
+	client        *Client
+	genreMapper   *genre.Mapper
+	imageBaseURL  string
+	actorLimit    int
+	region        string
+	minConfidence float64
 }
 
 // NewEnricher creates a new metadata enricher
 func NewEnricher(client *Client) *Enricher {
-	return &Enricher{client: client}
+	return &Enricher{client: client, imageBaseURL: DefaultImageBaseURL}
+}
+
+// SetImageBaseURL overrides the image base URL used for poster and backdrop
+// URLs (e.g. a mirror or proxy). An empty value restores the default.
+func (e *Enricher) SetImageBaseURL(base string) {
+	if base == "" {
+		base = DefaultImageBaseURL
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	e.imageBaseURL = base
+}
+
+// imageURL builds a full image URL for the given size and TMDB image path
+func (e *Enricher) imageURL(size, path string) string {
+	return e.imageBaseURL + size + path
+}
+
+// SetGenreMapper sets the mapper used to normalize genres after enrichment
+func (e *Enricher) SetGenreMapper(mapper *genre.Mapper) {
+	e.genreMapper = mapper
+}
+
+// SetActorLimit enables fetching credits after the details request, keeping
+// the top limit billed actors with their profile image URLs. This costs one
+// extra API request per movie or show; 0 (the default) disables it.
+func (e *Enricher) SetActorLimit(limit int) {
+	e.actorLimit = limit
+}
+
+// SetRegion sets the ISO 3166-1 country (e.g. "US", "GB") whose
+// certification is used for ratings and which movie searches favor. An empty
+// region leaves certifications unset.
+func (e *Enricher) SetRegion(region string) {
+	e.region = strings.ToUpper(region)
+	e.client.SetRegion(e.region)
+}
+
+// SetMinConfidence sets the MatchConfidence a search result needs before it
+// is applied; below it the filename-parsed metadata is left as is. 0 accepts
+// every result.
+func (e *Enricher) SetMinConfidence(min float64) {
+	e.minConfidence = min
+}
+
+// confident reports whether a search result's confidence meets the
+// threshold, logging the rejection when it does not
+func (e *Enricher) confident(query, match string, confidence float64) bool {
+	if confidence >= e.minConfidence {
+		return true
+	}
+	log.Warn().
+		Str("query", query).
+		Str("match", match).
+		Float64("confidence", confidence).
+		Float64("min_confidence", e.minConfidence).
+		Msg("TMDB match below minimum confidence, keeping parsed metadata")
+	return false
+}
+
+// movieCertification picks the region's certification from a movie's
+// releases, preferring the theatrical release
+func (e *Enricher) movieCertification(releases *ReleaseDatesResponse) string {
+	if e.region == "" || releases == nil {
+		return ""
+	}
+	for _, country := range releases.Results {
+		if !strings.EqualFold(country.ISO31661, e.region) {
+			continue
+		}
+		cert := ""
+		for _, rd := range country.ReleaseDates {
+			if rd.Certification == "" {
+				continue
+			}
+			if rd.Type == 3 {
+				return rd.Certification
+			}
+			if cert == "" {
+				cert = rd.Certification
+			}
+		}
+		return cert
+	}
+	return ""
+}
+
+// tvCertification picks the region's rating from a show's content ratings
+func (e *Enricher) tvCertification(ratings *ContentRatingsResponse) string {
+	if e.region == "" || ratings == nil {
+		return ""
+	}
+	for _, r := range ratings.Results {
+		if strings.EqualFold(r.ISO31661, e.region) {
+			return r.Rating
+		}
+	}
+	return ""
+}
+
+// actors fetches credits with fetch and converts the top billed cast,
+// returning nil when credits are disabled or unavailable
+func (e *Enricher) actors(ctx context.Context, id int, fetch func(context.Context, int) (*Credits, error)) []types.Person {
+	if e.actorLimit <= 0 || id <= 0 {
+		return nil
+	}
+
+	credits, err := fetch(ctx, id)
+	if err != nil {
+		log.Warn().Err(err).Int("id", id).Msg("Failed to get credits")
+		return nil
+	}
+
+	cast := credits.Cast
+	sort.SliceStable(cast, func(i, j int) bool { return cast[i].Order < cast[j].Order })
+	if len(cast) > e.actorLimit {
+		cast = cast[:e.actorLimit]
+	}
+
+	actors := make([]types.Person, 0, len(cast))
+	for _, member := range cast {
+		person := types.Person{Name: member.Name, Role: member.Character}
+		if member.ProfilePath != "" {
+			person.Thumb = e.imageURL("w185", member.ProfilePath)
+		}
+		actors = append(actors, person)
+	}
+	return actors
 }
 
 // EnrichMovie enriches movie metadata with TMDB data
-func (e *Enricher) EnrichMovie(metadata *types.Metadata) error {
+func (e *Enricher) EnrichMovie(ctx context.Context, metadata *types.Metadata) error {
 	if metadata == nil {
 		return fmt.Errorf("metadata is nil")
 	}
 
-	if metadata.Title == "" {
-		return fmt.Errorf("title is required for enrichment")
-	}
-
 	// Ensure MovieMetadata exists
 	if metadata.MovieMetadata == nil {
 		metadata.MovieMetadata = &types.MovieMetadata{}
 	}
 
+	// An ID embedded in the filename identifies the movie exactly
+	id, err := e.movieIDFromTokens(ctx, metadata.MovieMetadata)
+	if err != nil {
+		return err
+	}
+	if id > 0 {
+		details, err := e.client.GetMovieDetails(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get movie details for TMDB ID %d: %w", id, err)
+		}
+		e.applyMovieDetails(ctx, metadata, details)
+		log.Info().
+			Str("title", metadata.Title).
+			Int("tmdb_id", details.ID).
+			Msg("Movie metadata enriched by ID")
+		return nil
+	}
+
+	if metadata.Title == "" {
+		return fmt.Errorf("title is required for enrichment")
+	}
+
 	log.Debug().
 		Str("title", metadata.Title).
 		Int("year", metadata.Year).
 		Msg("Enriching movie metadata")
 
 	// Search for movie
-	searchResp, err := e.client.SearchMovie(metadata.Title, metadata.Year)
+	searchResp, err := e.client.SearchMovie(ctx, metadata.Title, metadata.Year)
 	if err != nil {
 		return fmt.Errorf("failed to search movie: %w", err)
 	}
 
-	if len(searchResp.Results) == 0 {
+	// Without the year the search may surface candidates that list this
+	// title among their alternative titles
+	candidates := searchResp.Results
+	if len(candidates) == 0 && metadata.Year > 0 {
+		if resp, err := e.client.SearchMovie(ctx, metadata.Title, 0); err == nil {
+			candidates = resp.Results
+		}
+	}
+
+	// Use the first result unless its title differs and another candidate
+	// lists the searched title as an alternative
+	idx, alternate := -1, ""
+	if len(candidates) > 0 {
+		if titleMatches(metadata.Title, candidates[0].Title, candidates[0].OriginalTitle) {
+			idx = 0
+		} else {
+			ids := make([]int, len(candidates))
+			for i, c := range candidates {
+				ids[i] = c.ID
+			}
+			idx, alternate = alternateMatch(ctx, metadata.Title, ids, e.client.GetMovieAlternativeTitles)
+		}
+		if idx < 0 && len(searchResp.Results) > 0 {
+			idx = 0
+		}
+	}
+	if idx < 0 {
 		log.Warn().
 			Str("title", metadata.Title).
 			Int("year", metadata.Year).
@@ -53,20 +238,38 @@ func (e *Enricher) EnrichMovie(metadata *types.Metadata) error {
 		return nil // Not an error, just no results
 	}
 
-	// Use first result (best match)
-	movie := searchResp.Results[0]
+	movie := candidates[idx]
+	confidence := MatchConfidence(metadata.Title, metadata.Year, movie.ReleaseDate, movie.Popularity, movie.Title, movie.OriginalTitle, alternate)
+	if !e.confident(metadata.Title, movie.Title, confidence) {
+		return nil
+	}
 
 	// Get detailed information
-	details, err := e.client.GetMovieDetails(movie.ID)
+	details, err := e.client.GetMovieDetails(ctx, movie.ID)
 	if err != nil {
 		log.Warn().Err(err).Int("id", movie.ID).Msg("Failed to get movie details")
 		// Use search result data only
 		e.applyMovieSearchResult(metadata, &movie)
+		if alternate != "" {
+			metadata.Title = movie.Title
+			metadata.MovieMetadata.Tags = appendTag(metadata.MovieMetadata.Tags, alternate)
+		}
 		return nil
 	}
 
 	// Apply enriched metadata
-	e.applyMovieDetails(metadata, details)
+	e.applyMovieDetails(ctx, metadata, details)
+
+	// A file named after an alternate title is organized under the
+	// canonical one, keeping the alternate as a tag
+	if alternate != "" {
+		metadata.Title = details.Title
+		metadata.MovieMetadata.Tags = appendTag(metadata.MovieMetadata.Tags, alternate)
+		log.Info().
+			Str("alternate", alternate).
+			Str("title", details.Title).
+			Msg("Movie matched by alternative title")
+	}
 
 	log.Info().
 		Str("title", metadata.Title).
@@ -78,7 +281,7 @@ func (e *Enricher) EnrichMovie(metadata *types.Metadata) error {
 }
 
 // EnrichTVShow enriches TV show metadata with TMDB data
-func (e *Enricher) EnrichTVShow(metadata *types.Metadata) error {
+func (e *Enricher) EnrichTVShow(ctx context.Context, metadata *types.Metadata) error {
 	if metadata == nil {
 		return fmt.Errorf("metadata is nil")
 	}
@@ -88,6 +291,24 @@ func (e *Enricher) EnrichTVShow(metadata *types.Metadata) error {
 		metadata.TVMetadata = &types.TVMetadata{}
 	}
 
+	// An ID embedded in the filename identifies the show exactly
+	id, err := e.tvIDFromTokens(ctx, metadata.TVMetadata)
+	if err != nil {
+		return err
+	}
+	if id > 0 {
+		details, err := e.client.GetTVDetails(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get TV details for TMDB ID %d: %w", id, err)
+		}
+		e.applyTVDetails(ctx, metadata, details)
+		log.Info().
+			Str("show", metadata.TVMetadata.ShowTitle).
+			Int("tmdb_id", details.ID).
+			Msg("TV show metadata enriched by ID")
+		return nil
+	}
+
 	if metadata.TVMetadata.ShowTitle == "" && metadata.Title == "" {
 		return fmt.Errorf("show name is required for enrichment")
 	}
@@ -109,31 +330,75 @@ func (e *Enricher) EnrichTVShow(metadata *types.Metadata) error {
 	}
 
 	// Search for TV show
-	searchResp, err := e.client.SearchTV(showName, year)
+	searchResp, err := e.client.SearchTV(ctx, showName, year)
 	if err != nil {
 		return fmt.Errorf("failed to search TV show: %w", err)
 	}
 
-	if len(searchResp.Results) == 0 {
+	candidates := searchResp.Results
+	if len(candidates) == 0 && year > 0 {
+		if resp, err := e.client.SearchTV(ctx, showName, 0); err == nil {
+			candidates = resp.Results
+		}
+	}
+
+	// Use the first result unless its name differs and another candidate
+	// lists the searched name as an alternative
+	idx, alternate := -1, ""
+	if len(candidates) > 0 {
+		if titleMatches(showName, candidates[0].Name, candidates[0].OriginalName) {
+			idx = 0
+		} else {
+			ids := make([]int, len(candidates))
+			for i, c := range candidates {
+				ids[i] = c.ID
+			}
+			idx, alternate = alternateMatch(ctx, showName, ids, e.client.GetTVAlternativeTitles)
+		}
+		if idx < 0 && len(searchResp.Results) > 0 {
+			idx = 0
+		}
+	}
+	if idx < 0 {
 		log.Warn().
 			Str("show", showName).
 			Msg("No TMDB results found for TV show")
 		return nil
 	}
 
-	// Use first result
-	show := searchResp.Results[0]
+	show := candidates[idx]
+	confidence := MatchConfidence(showName, year, show.FirstAirDate, show.Popularity, show.Name, show.OriginalName, alternate)
+	if !e.confident(showName, show.Name, confidence) {
+		return nil
+	}
 
 	// Get detailed information
-	details, err := e.client.GetTVDetails(show.ID)
+	details, err := e.client.GetTVDetails(ctx, show.ID)
 	if err != nil {
 		log.Warn().Err(err).Int("id", show.ID).Msg("Failed to get TV details")
 		e.applyTVSearchResult(metadata, &show)
+		if alternate != "" {
+			metadata.TVMetadata.ShowTitle = show.Name
+			metadata.Title = show.Name
+			metadata.TVMetadata.Tags = appendTag(metadata.TVMetadata.Tags, alternate)
+		}
 		return nil
 	}
 
 	// Apply enriched metadata
-	e.applyTVDetails(metadata, details)
+	e.applyTVDetails(ctx, metadata, details)
+
+	// A file named after an alternate title is organized under the
+	// canonical one, keeping the alternate as a tag
+	if alternate != "" {
+		metadata.TVMetadata.ShowTitle = details.Name
+		metadata.Title = details.Name
+		metadata.TVMetadata.Tags = appendTag(metadata.TVMetadata.Tags, alternate)
+		log.Info().
+			Str("alternate", alternate).
+			Str("show", details.Name).
+			Msg("TV show matched by alternative title")
+	}
 
 	log.Info().
 		Str("show", showName).
@@ -143,138 +408,250 @@ func (e *Enricher) EnrichTVShow(metadata *types.Metadata) error {
 	return nil
 }
 
-// applyMovieSearchResult applies data from search result to metadata
-func (e *Enricher) applyMovieSearchResult(metadata *types.Metadata, movie *MovieResult) {
-	metadata.MovieMetadata.Plot = movie.Overview
-	metadata.MovieMetadata.Rating = movie.VoteAverage
-
-	// Extract year from release date if not already set
-	if metadata.Year == 0 && movie.ReleaseDate != "" {
-		parts := strings.Split(movie.ReleaseDate, "-")
-		if len(parts) > 0 {
-			year, err := strconv.Atoi(parts[0])
-			if err == nil && year > 0 {
-				metadata.Year = year
+// movieIDFromTokens returns the TMDB ID given by a {tmdb-...} filename token,
+// resolving an {imdb-...} token through TMDB's find endpoint. It returns 0
+// when the filename carried no ID, so the caller falls back to search.
+func (e *Enricher) movieIDFromTokens(ctx context.Context, movie *types.MovieMetadata) (int, error) {
+	if movie.TMDBID > 0 {
+		return movie.TMDBID, nil
+	}
+	if movie.IMDBID == "" {
+		return 0, nil
+	}
+
+	found, err := e.client.FindByIMDBID(ctx, movie.IMDBID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up IMDb ID %s: %w", movie.IMDBID, err)
+	}
+	if len(found.MovieResults) == 0 {
+		log.Warn().Str("imdb_id", movie.IMDBID).Msg("No TMDB movie found for IMDb ID, falling back to search")
+		return 0, nil
+	}
+	return found.MovieResults[0].ID, nil
+}
+
+// tvIDFromTokens is the TV equivalent of movieIDFromTokens
+func (e *Enricher) tvIDFromTokens(ctx context.Context, tv *types.TVMetadata) (int, error) {
+	if tv.TMDBID > 0 {
+		return tv.TMDBID, nil
+	}
+	if tv.IMDBID == "" {
+		return 0, nil
+	}
+
+	found, err := e.client.FindByIMDBID(ctx, tv.IMDBID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up IMDb ID %s: %w", tv.IMDBID, err)
+	}
+	if len(found.TVResults) == 0 {
+		log.Warn().Str("imdb_id", tv.IMDBID).Msg("No TMDB show found for IMDb ID, falling back to search")
+		return 0, nil
+	}
+	return found.TVResults[0].ID, nil
+}
+
+// maxAlternateCandidates bounds how many search results have their
+// alternative titles fetched when the first result's title does not match
+const maxAlternateCandidates = 3
+
+// alternateMatch fetches the alternative titles of the leading candidate IDs
+// and returns the index of the first candidate listing title, along with the
+// title as TMDB lists it. It returns -1 when none match.
+func alternateMatch(ctx context.Context, title string, ids []int, fetch func(context.Context, int) (*AlternativeTitlesResponse, error)) (int, string) {
+	for i, id := range ids {
+		if i >= maxAlternateCandidates {
+			break
+		}
+		alts, err := fetch(ctx, id)
+		if err != nil {
+			log.Debug().Err(err).Int("id", id).Msg("Failed to get alternative titles")
+			continue
+		}
+		for _, alt := range alts.All() {
+			if titleMatches(title, alt.Title) {
+				return i, alt.Title
 			}
 		}
 	}
+	return -1, ""
+}
 
-	// Store TMDB ID for reference
-	metadata.MovieMetadata.TMDBID = movie.ID
+// titleMatches reports whether title equals any of candidates, ignoring
+// case, punctuation and spacing
+func titleMatches(title string, candidates ...string) bool {
+	key := titleKey(title)
+	if key == "" {
+		return false
+	}
+	for _, c := range candidates {
+		if titleKey(c) == key {
+			return true
+		}
+	}
+	return false
+}
 
-	// Build poster URL if available
+// titleKey reduces a title to its lowercase letters and digits
+func titleKey(title string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, title)
+}
+
+// appendTag adds tag unless already present
+func appendTag(tags []string, tag string) []string {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
+
+// applyMovieSearchResult applies data from search result to metadata
+func (e *Enricher) applyMovieSearchResult(metadata *types.Metadata, movie *MovieResult) {
+	enriched := &types.Metadata{
+		MovieMetadata: &types.MovieMetadata{
+			Plot:   movie.Overview,
+			Rating: movie.VoteAverage,
+			TMDBID: movie.ID,
+		},
+	}
+	if metadata.Year == 0 {
+		enriched.Year = releaseYear(movie.ReleaseDate)
+	}
 	if movie.PosterPath != "" {
-		metadata.MovieMetadata.PosterURL = fmt.Sprintf("https://image.tmdb.org/t/p/w500%s", movie.PosterPath)
+		enriched.MovieMetadata.PosterURL = e.imageURL("w500", movie.PosterPath)
 	}
+	metadata.Merge(enriched, types.SourceEnrichment)
 }
 
-// applyMovieDetails applies detailed movie data to metadata
-func (e *Enricher) applyMovieDetails(metadata *types.Metadata, details *MovieDetails) {
-	// Title (prefer original if set, otherwise use TMDB title)
+// applyMovieDetails applies detailed movie data to metadata. The parsed
+// title and year identify the file and are only filled when missing; the
+// rest is merged with enrichment precedence, so NFO values are kept.
+func (e *Enricher) applyMovieDetails(ctx context.Context, metadata *types.Metadata, details *MovieDetails) {
+	enriched := &types.Metadata{
+		MovieMetadata: &types.MovieMetadata{
+			Plot:          details.Overview,
+			Rating:        details.VoteAverage,
+			TMDBID:        details.ID,
+			IMDBID:        details.IMDBID,
+			Runtime:       details.Runtime,
+			Tagline:       details.Tagline,
+			Certification: e.movieCertification(details.ReleaseDates),
+		},
+	}
 	if metadata.Title == "" {
-		metadata.Title = details.Title
+		enriched.Title = details.Title
 	}
-
-	metadata.MovieMetadata.Plot = details.Overview
-	metadata.MovieMetadata.Rating = details.VoteAverage
-	metadata.MovieMetadata.TMDBID = details.ID
-	metadata.MovieMetadata.IMDBID = details.IMDBID
-	metadata.MovieMetadata.Runtime = details.Runtime
-
-	// Extract year from release date
-	if details.ReleaseDate != "" {
-		parts := strings.Split(details.ReleaseDate, "-")
-		if len(parts) > 0 {
-			year, err := strconv.Atoi(parts[0])
-			if err == nil && year > 0 && metadata.Year == 0 {
-				metadata.Year = year
-			}
-		}
+	if metadata.Year == 0 {
+		enriched.Year = releaseYear(details.ReleaseDate)
 	}
 
-	// Genres
+	movie := enriched.MovieMetadata
 	if len(details.Genres) > 0 {
-		metadata.MovieMetadata.Genres = make([]string, len(details.Genres))
-		for i, genre := range details.Genres {
-			metadata.MovieMetadata.Genres[i] = genre.Name
+		genres := make([]string, len(details.Genres))
+		for i, g := range details.Genres {
+			genres[i] = g.Name
 		}
+		movie.Genres = e.genreMapper.Apply(genres)
 	}
-
-	// Poster URL
 	if details.PosterPath != "" {
-		metadata.MovieMetadata.PosterURL = fmt.Sprintf("https://image.tmdb.org/t/p/w500%s", details.PosterPath)
+		movie.PosterURL = e.imageURL("w500", details.PosterPath)
 	}
-
-	// Backdrop URL
 	if details.BackdropPath != "" {
-		metadata.MovieMetadata.BackdropURL = fmt.Sprintf("https://image.tmdb.org/t/p/w1280%s", details.BackdropPath)
+		movie.BackdropURL = e.imageURL("w1280", details.BackdropPath)
+	}
+	if actors := e.actors(ctx, details.ID, e.client.GetMovieCredits); len(actors) > 0 {
+		movie.Actors = actors
+		movie.Cast = make([]string, len(actors))
+		for i, actor := range actors {
+			movie.Cast[i] = actor.Name
+		}
 	}
 
-	metadata.MovieMetadata.Tagline = details.Tagline
+	metadata.Merge(enriched, types.SourceEnrichment)
 }
 
 // applyTVSearchResult applies data from TV search result to metadata
 func (e *Enricher) applyTVSearchResult(metadata *types.Metadata, show *TVResult) {
-	metadata.TVMetadata.Plot = show.Overview
-	metadata.TVMetadata.Rating = show.VoteAverage
-	metadata.TVMetadata.TMDBID = show.ID
-
-	// Extract year from first air date
-	if show.FirstAirDate != "" {
-		parts := strings.Split(show.FirstAirDate, "-")
-		if len(parts) > 0 {
-			year, err := strconv.Atoi(parts[0])
-			if err == nil && year > 0 && metadata.Year == 0 {
-				metadata.Year = year
-			}
-		}
+	enriched := &types.Metadata{
+		TVMetadata: &types.TVMetadata{
+			Plot:          show.Overview,
+			Rating:        show.VoteAverage,
+			TMDBID:        show.ID,
+			OriginalTitle: originalTitle(show.Name, show.OriginalName),
+		},
+	}
+	if metadata.Year == 0 {
+		enriched.Year = releaseYear(show.FirstAirDate)
 	}
-
-	// Poster URL
 	if show.PosterPath != "" {
-		metadata.TVMetadata.PosterURL = fmt.Sprintf("https://image.tmdb.org/t/p/w500%s", show.PosterPath)
+		enriched.TVMetadata.PosterURL = e.imageURL("w500", show.PosterPath)
 	}
+	metadata.Merge(enriched, types.SourceEnrichment)
 }
 
-// applyTVDetails applies detailed TV show data to metadata
-func (e *Enricher) applyTVDetails(metadata *types.Metadata, details *TVDetails) {
-	if metadata.TVMetadata.ShowTitle == "" {
-		metadata.TVMetadata.ShowTitle = details.Name
-		metadata.Title = details.Name
+// originalTitle returns a show's original-language name, or "" when it is
+// the same as the name it is listed under
+func originalTitle(name, original string) string {
+	if original == name {
+		return ""
 	}
+	return original
+}
 
-	metadata.TVMetadata.Plot = details.Overview
-	metadata.TVMetadata.Rating = details.VoteAverage
-	metadata.TVMetadata.TMDBID = details.ID
-
-	// Extract year from first air date
-	if details.FirstAirDate != "" {
-		parts := strings.Split(details.FirstAirDate, "-")
-		if len(parts) > 0 {
-			year, err := strconv.Atoi(parts[0])
-			if err == nil && year > 0 && metadata.Year == 0 {
-				metadata.Year = year
-			}
-		}
+// applyTVDetails applies detailed TV show data to metadata. The parsed
+// show title and year identify the file and are only filled when missing;
+// the rest is merged with enrichment precedence, so NFO values are kept.
+func (e *Enricher) applyTVDetails(ctx context.Context, metadata *types.Metadata, details *TVDetails) {
+	enriched := &types.Metadata{
+		TVMetadata: &types.TVMetadata{
+			Plot:          details.Overview,
+			Rating:        details.VoteAverage,
+			TMDBID:        details.ID,
+			OriginalTitle: originalTitle(details.Name, details.OriginalName),
+			Tagline:       details.Tagline,
+			Certification: e.tvCertification(details.ContentRatings),
+		},
+	}
+	if metadata.TVMetadata.ShowTitle == "" {
+		enriched.TVMetadata.ShowTitle = details.Name
+		enriched.Title = details.Name
+	}
+	if metadata.Year == 0 {
+		enriched.Year = releaseYear(details.FirstAirDate)
 	}
 
-	// Genres
+	show := enriched.TVMetadata
 	if len(details.Genres) > 0 {
-		metadata.TVMetadata.Genres = make([]string, len(details.Genres))
-		for i, genre := range details.Genres {
-			metadata.TVMetadata.Genres[i] = genre.Name
+		genres := make([]string, len(details.Genres))
+		for i, g := range details.Genres {
+			genres[i] = g.Name
 		}
+		show.Genres = e.genreMapper.Apply(genres)
 	}
-
-	// Poster URL
 	if details.PosterPath != "" {
-		metadata.TVMetadata.PosterURL = fmt.Sprintf("https://image.tmdb.org/t/p/w500%s", details.PosterPath)
+		show.PosterURL = e.imageURL("w500", details.PosterPath)
 	}
-
-	// Backdrop URL
 	if details.BackdropPath != "" {
-		metadata.TVMetadata.BackdropURL = fmt.Sprintf("https://image.tmdb.org/t/p/w1280%s", details.BackdropPath)
+		show.BackdropURL = e.imageURL("w1280", details.BackdropPath)
 	}
+	if actors := e.actors(ctx, details.ID, e.client.GetTVCredits); len(actors) > 0 {
+		show.Actors = actors
+	}
+
+	metadata.Merge(enriched, types.SourceEnrichment)
+}
 
-	metadata.TVMetadata.Tagline = details.Tagline
+// releaseYear returns the year of a TMDB "YYYY-MM-DD" date, or 0
+func releaseYear(date string) int {
+	year, err := strconv.Atoi(strings.SplitN(date, "-", 2)[0])
+	if err != nil || year <= 0 {
+		return 0
+	}
+	return year
 }
@@ -0,0 +1,488 @@
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/genre"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestEnricher_GenreMapping(t *testing.T) {
+	e := NewEnricher(nil)
+	e.SetGenreMapper(genre.NewMapper(
+		map[string]string{"sci-fi": "Science Fiction"},
+		[]string{"Science Fiction", "Drama"},
+	))
+
+	metadata := &types.Metadata{MovieMetadata: &types.MovieMetadata{}}
+	e.applyMovieDetails(context.Background(), metadata, &MovieDetails{
+		Genres: []Genre{{Name: "Sci-Fi"}, {Name: "Drama"}, {Name: "Documentary"}},
+	})
+
+	want := []string{"Science Fiction", "Drama"}
+	if !reflect.DeepEqual(metadata.MovieMetadata.Genres, want) {
+		t.Errorf("movie genres = %v, want %v", metadata.MovieMetadata.Genres, want)
+	}
+
+	tvMetadata := &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "Show"}}
+	e.applyTVDetails(context.Background(), tvMetadata, &TVDetails{
+		Genres: []Genre{{Name: "Sci-Fi"}, {Name: "Reality"}},
+	})
+
+	want = []string{"Science Fiction"}
+	if !reflect.DeepEqual(tvMetadata.TVMetadata.Genres, want) {
+		t.Errorf("tv genres = %v, want %v", tvMetadata.TVMetadata.Genres, want)
+	}
+}
+
+func TestEnricher_TVOriginalTitle(t *testing.T) {
+	e := NewEnricher(nil)
+
+	metadata := &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "Money Heist"}}
+	e.applyTVDetails(context.Background(), metadata, &TVDetails{ID: 71446, Name: "Money Heist", OriginalName: "La casa de papel"})
+	if metadata.TVMetadata.OriginalTitle != "La casa de papel" {
+		t.Errorf("OriginalTitle = %q, want %q", metadata.TVMetadata.OriginalTitle, "La casa de papel")
+	}
+
+	metadata = &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "The Expanse"}}
+	e.applyTVDetails(context.Background(), metadata, &TVDetails{ID: 63639, Name: "The Expanse", OriginalName: "The Expanse"})
+	if metadata.TVMetadata.OriginalTitle != "" {
+		t.Errorf("OriginalTitle = %q, want empty when it matches the name", metadata.TVMetadata.OriginalTitle)
+	}
+}
+
+func TestEnricher_CustomImageBase(t *testing.T) {
+	e := NewEnricher(nil)
+	e.SetImageBaseURL("https://images.example.com/tmdb")
+
+	metadata := &types.Metadata{MovieMetadata: &types.MovieMetadata{}}
+	e.applyMovieDetails(context.Background(), metadata, &MovieDetails{
+		PosterPath:   "/poster.jpg",
+		BackdropPath: "/backdrop.jpg",
+	})
+
+	if want := "https://images.example.com/tmdb/w500/poster.jpg"; metadata.MovieMetadata.PosterURL != want {
+		t.Errorf("PosterURL = %s, want %s", metadata.MovieMetadata.PosterURL, want)
+	}
+	if want := "https://images.example.com/tmdb/w1280/backdrop.jpg"; metadata.MovieMetadata.BackdropURL != want {
+		t.Errorf("BackdropURL = %s, want %s", metadata.MovieMetadata.BackdropURL, want)
+	}
+
+	tvMetadata := &types.Metadata{TVMetadata: &types.TVMetadata{}}
+	NewEnricher(nil).applyTVDetails(context.Background(), tvMetadata, &TVDetails{PosterPath: "/show.jpg"})
+	if want := "https://image.tmdb.org/t/p/w500/show.jpg"; tvMetadata.TVMetadata.PosterURL != want {
+		t.Errorf("default PosterURL = %s, want %s", tvMetadata.TVMetadata.PosterURL, want)
+	}
+}
+
+// newIDTestEnricher returns an enricher backed by a fake TMDB server that
+// records every requested path
+func newIDTestEnricher(t *testing.T) (*Enricher, *[]string) {
+	t.Helper()
+
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/movie/603":
+			json.NewEncoder(w).Encode(MovieDetails{ID: 603, Title: "The Matrix", IMDBID: "tt0133093"})
+		case r.URL.Path == "/tv/1396":
+			json.NewEncoder(w).Encode(TVDetails{ID: 1396, Name: "Breaking Bad"})
+		case r.URL.Path == "/movie/603/credits":
+			json.NewEncoder(w).Encode(Credits{ID: 603, Cast: []CastMember{
+				{Name: "Carrie-Anne Moss", Character: "Trinity", ProfilePath: "/moss.jpg", Order: 2},
+				{Name: "Keanu Reeves", Character: "Neo", ProfilePath: "/reeves.jpg", Order: 0},
+				{Name: "Laurence Fishburne", Character: "Morpheus", Order: 1},
+			}})
+		case r.URL.Path == "/tv/1396/credits":
+			json.NewEncoder(w).Encode(Credits{ID: 1396, Cast: []CastMember{
+				{Name: "Bryan Cranston", Character: "Walter White", ProfilePath: "/cranston.jpg"},
+			}})
+		case r.URL.Path == "/find/tt0133093":
+			json.NewEncoder(w).Encode(FindResponse{MovieResults: []MovieResult{{ID: 603}}})
+		case strings.HasPrefix(r.URL.Path, "/search/"):
+			json.NewEncoder(w).Encode(SearchMovieResponse{})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(Config{APIKey: "test-key", CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	return NewEnricher(client), &paths
+}
+
+func TestEnricher_IDTokenSkipsSearch(t *testing.T) {
+	tests := []struct {
+		name      string
+		metadata  *types.Metadata
+		tv        bool
+		wantPaths []string
+		wantID    int
+	}{
+		{
+			name:      "movie tmdb id",
+			metadata:  &types.Metadata{Title: "Wrong Title", MovieMetadata: &types.MovieMetadata{TMDBID: 603}},
+			wantPaths: []string{"/movie/603"},
+			wantID:    603,
+		},
+		{
+			name:      "movie imdb id",
+			metadata:  &types.Metadata{Title: "Wrong Title", MovieMetadata: &types.MovieMetadata{IMDBID: "tt0133093"}},
+			wantPaths: []string{"/find/tt0133093", "/movie/603"},
+			wantID:    603,
+		},
+		{
+			name:      "tv tmdb id",
+			metadata:  &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "BB", TMDBID: 1396}},
+			tv:        true,
+			wantPaths: []string{"/tv/1396"},
+			wantID:    1396,
+		},
+		{
+			name:      "no id searches",
+			metadata:  &types.Metadata{Title: "The Matrix", MovieMetadata: &types.MovieMetadata{}},
+			wantPaths: []string{"/search/movie"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, paths := newIDTestEnricher(t)
+
+			var err error
+			var gotID int
+			if tt.tv {
+				err = e.EnrichTVShow(context.Background(), tt.metadata)
+				gotID = tt.metadata.TVMetadata.TMDBID
+			} else {
+				err = e.EnrichMovie(context.Background(), tt.metadata)
+				gotID = tt.metadata.MovieMetadata.TMDBID
+			}
+			if err != nil {
+				t.Fatalf("enrich error = %v", err)
+			}
+
+			if !reflect.DeepEqual(*paths, tt.wantPaths) {
+				t.Errorf("requested paths = %v, want %v", *paths, tt.wantPaths)
+			}
+			if gotID != tt.wantID {
+				t.Errorf("TMDBID = %d, want %d", gotID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestEnricher_ActorLimit(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		e, paths := newIDTestEnricher(t)
+		metadata := &types.Metadata{MovieMetadata: &types.MovieMetadata{TMDBID: 603}}
+		if err := e.EnrichMovie(context.Background(), metadata); err != nil {
+			t.Fatalf("EnrichMovie() error = %v", err)
+		}
+		if len(*paths) != 1 || len(metadata.MovieMetadata.Actors) != 0 {
+			t.Errorf("credits fetched without an actor limit: paths=%v actors=%v", *paths, metadata.MovieMetadata.Actors)
+		}
+	})
+
+	t.Run("movie keeps top billed actors", func(t *testing.T) {
+		e, _ := newIDTestEnricher(t)
+		e.SetActorLimit(2)
+		metadata := &types.Metadata{MovieMetadata: &types.MovieMetadata{TMDBID: 603}}
+		if err := e.EnrichMovie(context.Background(), metadata); err != nil {
+			t.Fatalf("EnrichMovie() error = %v", err)
+		}
+
+		want := []types.Person{
+			{Name: "Keanu Reeves", Role: "Neo", Thumb: DefaultImageBaseURL + "w185/reeves.jpg"},
+			{Name: "Laurence Fishburne", Role: "Morpheus"},
+		}
+		if !reflect.DeepEqual(metadata.MovieMetadata.Actors, want) {
+			t.Errorf("Actors = %+v, want %+v", metadata.MovieMetadata.Actors, want)
+		}
+		if !reflect.DeepEqual(metadata.MovieMetadata.Cast, []string{"Keanu Reeves", "Laurence Fishburne"}) {
+			t.Errorf("Cast = %v", metadata.MovieMetadata.Cast)
+		}
+	})
+
+	t.Run("tv show", func(t *testing.T) {
+		e, _ := newIDTestEnricher(t)
+		e.SetActorLimit(10)
+		metadata := &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "BB", TMDBID: 1396}}
+		if err := e.EnrichTVShow(context.Background(), metadata); err != nil {
+			t.Fatalf("EnrichTVShow() error = %v", err)
+		}
+		if len(metadata.TVMetadata.Actors) != 1 || metadata.TVMetadata.Actors[0].Thumb != DefaultImageBaseURL+"w185/cranston.jpg" {
+			t.Errorf("Actors = %+v", metadata.TVMetadata.Actors)
+		}
+	})
+}
+
+func TestEnricher_RegionCertification(t *testing.T) {
+	var searchRegion, detailsAppend, tvAppend string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search/movie":
+			searchRegion = r.URL.Query().Get("region")
+			json.NewEncoder(w).Encode(SearchMovieResponse{Results: []MovieResult{{ID: 27205}}})
+		case "/movie/27205":
+			detailsAppend = r.URL.Query().Get("append_to_response")
+			json.NewEncoder(w).Encode(MovieDetails{ID: 27205, Title: "Inception", ReleaseDates: &ReleaseDatesResponse{
+				Results: []CountryReleases{
+					{ISO31661: "US", ReleaseDates: []ReleaseDate{{Certification: "PG-13", Type: 3}}},
+					{ISO31661: "GB", ReleaseDates: []ReleaseDate{
+						{Certification: "", Type: 1},
+						{Certification: "12", Type: 5},
+						{Certification: "12A", Type: 3},
+					}},
+					{ISO31661: "DE", ReleaseDates: []ReleaseDate{{Certification: "12", Type: 3}}},
+				},
+			}})
+		case "/tv/1396":
+			tvAppend = r.URL.Query().Get("append_to_response")
+			json.NewEncoder(w).Encode(TVDetails{ID: 1396, Name: "Breaking Bad", ContentRatings: &ContentRatingsResponse{
+				Results: []ContentRating{{ISO31661: "US", Rating: "TV-MA"}, {ISO31661: "GB", Rating: "18"}},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-key", CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+	e := NewEnricher(client)
+	e.SetRegion("gb")
+
+	movie := &types.Metadata{Title: "Inception", Year: 2010}
+	if err := e.EnrichMovie(context.Background(), movie); err != nil {
+		t.Fatalf("EnrichMovie() error = %v", err)
+	}
+	if searchRegion != "GB" {
+		t.Errorf("search region param = %q, want GB", searchRegion)
+	}
+	if detailsAppend != "release_dates" {
+		t.Errorf("details append_to_response = %q, want release_dates", detailsAppend)
+	}
+	if got := movie.MovieMetadata.Certification; got != "12A" {
+		t.Errorf("movie certification = %q, want the GB theatrical 12A", got)
+	}
+
+	show := &types.Metadata{TVMetadata: &types.TVMetadata{TMDBID: 1396}}
+	if err := e.EnrichTVShow(context.Background(), show); err != nil {
+		t.Fatalf("EnrichTVShow() error = %v", err)
+	}
+	if tvAppend != "content_ratings" {
+		t.Errorf("tv append_to_response = %q, want content_ratings", tvAppend)
+	}
+	if got := show.TVMetadata.Certification; got != "18" {
+		t.Errorf("tv certification = %q, want 18", got)
+	}
+}
+
+func TestEnricher_MovieCertificationFallback(t *testing.T) {
+	releases := &ReleaseDatesResponse{Results: []CountryReleases{
+		{ISO31661: "US", ReleaseDates: []ReleaseDate{{Certification: "", Type: 3}, {Certification: "R", Type: 4}}},
+	}}
+
+	tests := []struct {
+		region string
+		want   string
+	}{
+		{"US", "R"}, // no theatrical certification, first non-empty wins
+		{"FR", ""},  // region missing
+		{"", ""},    // no region configured
+	}
+	for _, tt := range tests {
+		e := &Enricher{region: tt.region}
+		if got := e.movieCertification(releases); got != tt.want {
+			t.Errorf("movieCertification() region %q = %q, want %q", tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestEnricher_AlternativeTitleMatch(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search/movie":
+			switch {
+			case r.URL.Query().Get("query") == "The Matrix":
+				json.NewEncoder(w).Encode(SearchMovieResponse{Results: []MovieResult{{ID: 603, Title: "The Matrix"}}})
+			case r.URL.Query().Get("year") != "":
+				// The primary search under the German title finds nothing
+				json.NewEncoder(w).Encode(SearchMovieResponse{})
+			default:
+				json.NewEncoder(w).Encode(SearchMovieResponse{Results: []MovieResult{
+					{ID: 1, Title: "Pate"},
+					{ID: 238, Title: "The Godfather"},
+				}})
+			}
+		case "/movie/1/alternative_titles":
+			json.NewEncoder(w).Encode(AlternativeTitlesResponse{ID: 1})
+		case "/movie/238/alternative_titles":
+			json.NewEncoder(w).Encode(AlternativeTitlesResponse{ID: 238, Titles: []AlternativeTitle{
+				{ISO31661: "IT", Title: "Il padrino"},
+				{ISO31661: "DE", Title: "Der Pate"},
+			}})
+		case "/movie/238":
+			json.NewEncoder(w).Encode(MovieDetails{ID: 238, Title: "The Godfather", ReleaseDate: "1972-03-14"})
+		case "/movie/603":
+			json.NewEncoder(w).Encode(MovieDetails{ID: 603, Title: "The Matrix"})
+		case "/search/tv":
+			// A low-confidence result whose primary name differs
+			json.NewEncoder(w).Encode(SearchTVResponse{Results: []TVResult{{ID: 71446, Name: "Money Heist"}}})
+		case "/tv/71446/alternative_titles":
+			json.NewEncoder(w).Encode(AlternativeTitlesResponse{ID: 71446, Results: []AlternativeTitle{
+				{ISO31661: "ES", Title: "La casa de papel"},
+			}})
+		case "/tv/71446":
+			json.NewEncoder(w).Encode(TVDetails{ID: 71446, Name: "Money Heist"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-key", CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+	e := NewEnricher(client)
+
+	movie := &types.Metadata{Title: "Der Pate", Year: 1972}
+	if err := e.EnrichMovie(context.Background(), movie); err != nil {
+		t.Fatalf("EnrichMovie() error = %v", err)
+	}
+	if movie.Title != "The Godfather" || movie.MovieMetadata.TMDBID != 238 {
+		t.Errorf("movie = %q (TMDB %d), want the canonical The Godfather (238)", movie.Title, movie.MovieMetadata.TMDBID)
+	}
+	if !reflect.DeepEqual(movie.MovieMetadata.Tags, []string{"Der Pate"}) {
+		t.Errorf("movie tags = %v, want the alternate title preserved", movie.MovieMetadata.Tags)
+	}
+
+	show := &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "La Casa de Papel", Season: 1, Episode: 1}}
+	if err := e.EnrichTVShow(context.Background(), show); err != nil {
+		t.Fatalf("EnrichTVShow() error = %v", err)
+	}
+	if show.TVMetadata.ShowTitle != "Money Heist" || show.Title != "Money Heist" {
+		t.Errorf("show = %q, want the canonical Money Heist", show.TVMetadata.ShowTitle)
+	}
+	if !reflect.DeepEqual(show.TVMetadata.Tags, []string{"La casa de papel"}) {
+		t.Errorf("show tags = %v, want the alternate title preserved", show.TVMetadata.Tags)
+	}
+
+	// A primary title match needs no alternative titles lookup
+	paths = nil
+	matrix := &types.Metadata{Title: "The Matrix"}
+	if err := e.EnrichMovie(context.Background(), matrix); err != nil {
+		t.Fatalf("EnrichMovie() error = %v", err)
+	}
+	for _, p := range paths {
+		if strings.HasSuffix(p, "/alternative_titles") {
+			t.Errorf("unexpected alternative titles request %s for a matching title", p)
+		}
+	}
+	if len(matrix.MovieMetadata.Tags) != 0 {
+		t.Errorf("matrix tags = %v, want none", matrix.MovieMetadata.Tags)
+	}
+}
+
+func TestMatchConfidence(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		year       int
+		date       string
+		popularity float64
+		titles     []string
+		want       float64
+	}{
+		{"exact popular match", "The Matrix", 1999, "1999-03-30", 100, []string{"The Matrix"}, 1},
+		{"punctuation and case ignored", "the.matrix", 1999, "1999-03-30", 100, []string{"The Matrix!"}, 1},
+		{"original title counts", "Amelie", 2001, "2001-04-25", 100, []string{"Le Fabuleux Destin d'Amélie Poulain", "Amelie"}, 1},
+		{"year off by one", "The Matrix", 1999, "1998-03-30", 100, []string{"The Matrix"}, 0.8},
+		{"wrong year", "The Matrix", 2021, "1999-03-30", 100, []string{"The Matrix"}, 0.4},
+		{"unknown year", "The Matrix", 0, "1999-03-30", 100, []string{"The Matrix"}, 0.9},
+		{"obscure result", "The Matrix", 1999, "1999-03-30", 0, []string{"The Matrix"}, 0.7},
+		{"one letter off in six", "Amelie", 2001, "2001-04-25", 100, []string{"Amélie"}, 5.0 / 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchConfidence(tt.query, tt.year, tt.date, tt.popularity, tt.titles...)
+			if got < tt.want-0.01 || got > tt.want+0.01 {
+				t.Errorf("MatchConfidence() = %.3f, want %.3f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnricher_MinConfidence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search/movie":
+			// A borderline match: right year, popular, but the title is
+			// one letter off (confidence ~0.83)
+			json.NewEncoder(w).Encode(SearchMovieResponse{Results: []MovieResult{
+				{ID: 194, Title: "Amélie", ReleaseDate: "2001-04-25", Popularity: 100},
+			}})
+		case "/movie/194/alternative_titles":
+			json.NewEncoder(w).Encode(AlternativeTitlesResponse{ID: 194})
+		case "/movie/194":
+			json.NewEncoder(w).Encode(MovieDetails{ID: 194, Title: "Amélie", Overview: "A shy waitress"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-key", CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	tests := []struct {
+		min      float64
+		accepted bool
+	}{
+		{0, true},
+		{0.8, true},
+		{0.9, false},
+	}
+	for _, tt := range tests {
+		e := NewEnricher(client)
+		e.SetMinConfidence(tt.min)
+
+		movie := &types.Metadata{Title: "Amelie", Year: 2001}
+		if err := e.EnrichMovie(context.Background(), movie); err != nil {
+			t.Fatalf("EnrichMovie() error = %v", err)
+		}
+		if got := movie.MovieMetadata.TMDBID == 194; got != tt.accepted {
+			t.Errorf("min confidence %.1f: match applied = %v, want %v", tt.min, got, tt.accepted)
+		}
+		if !tt.accepted && (movie.Title != "Amelie" || movie.Year != 2001 || movie.MovieMetadata.Plot != "") {
+			t.Errorf("min confidence %.1f: rejected match changed parsed metadata: %+v %+v", tt.min, movie, movie.MovieMetadata)
+		}
+	}
+}
@@ -46,6 +46,29 @@ type MovieDetails struct {
 	Genres           []Genre `json:"genres"`
 	IMDBID           string  `json:"imdb_id"`
 	OriginalLanguage string  `json:"original_language"`
+	// ReleaseDates holds per-country releases and certifications, appended
+	// to the details response
+	ReleaseDates *ReleaseDatesResponse `json:"release_dates,omitempty"`
+}
+
+// ReleaseDatesResponse represents the TMDB movie release dates API response
+type ReleaseDatesResponse struct {
+	Results []CountryReleases `json:"results"`
+}
+
+// CountryReleases lists a movie's releases in one country
+type CountryReleases struct {
+	ISO31661     string        `json:"iso_3166_1"`
+	ReleaseDates []ReleaseDate `json:"release_dates"`
+}
+
+// ReleaseDate is a single release with its certification
+type ReleaseDate struct {
+	Certification string `json:"certification"`
+	ReleaseDate   string `json:"release_date"`
+	// Type is TMDB's release type: 1 premiere, 2 limited, 3 theatrical,
+	// 4 digital, 5 physical, 6 TV
+	Type int `json:"type"`
 }
 
 // SearchTVResponse represents the TMDB TV search API response
@@ -92,6 +115,62 @@ type TVDetails struct {
 	NumberOfEpisodes int      `json:"number_of_episodes"`
 	Seasons          []Season `json:"seasons"`
 	OriginalLanguage string   `json:"original_language"`
+	// ContentRatings holds per-country ratings, appended to the details response
+	ContentRatings *ContentRatingsResponse `json:"content_ratings,omitempty"`
+}
+
+// ContentRatingsResponse represents the TMDB TV content ratings API response
+type ContentRatingsResponse struct {
+	Results []ContentRating `json:"results"`
+}
+
+// ContentRating is a TV show's rating in one country
+type ContentRating struct {
+	ISO31661 string `json:"iso_3166_1"`
+	Rating   string `json:"rating"`
+}
+
+// AlternativeTitlesResponse represents the TMDB alternative titles API
+// response. Movies list them under "titles", TV shows under "results".
+type AlternativeTitlesResponse struct {
+	ID      int                `json:"id"`
+	Titles  []AlternativeTitle `json:"titles"`
+	Results []AlternativeTitle `json:"results"`
+}
+
+// AlternativeTitle is a localized or alternate title in one country
+type AlternativeTitle struct {
+	ISO31661 string `json:"iso_3166_1"`
+	Title    string `json:"title"`
+	Type     string `json:"type"`
+}
+
+// All returns the alternative titles regardless of which field held them
+func (r *AlternativeTitlesResponse) All() []AlternativeTitle {
+	all := make([]AlternativeTitle, 0, len(r.Titles)+len(r.Results))
+	all = append(all, r.Titles...)
+	return append(all, r.Results...)
+}
+
+// FindResponse represents the TMDB find-by-external-ID API response
+type FindResponse struct {
+	MovieResults []MovieResult `json:"movie_results"`
+	TVResults    []TVResult    `json:"tv_results"`
+}
+
+// Credits represents the TMDB movie or TV credits API response
+type Credits struct {
+	ID   int          `json:"id"`
+	Cast []CastMember `json:"cast"`
+}
+
+// CastMember represents a single billed cast member
+type CastMember struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Character   string `json:"character"`
+	ProfilePath string `json:"profile_path"`
+	Order       int    `json:"order"`
 }
 
 // Genre represents a movie or TV genre
@@ -1,6 +1,7 @@
 package tmdb
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -14,6 +15,12 @@ type RateLimiter struct {
 	interval   time.Duration // refill interval
 	mu         sync.Mutex
 	lastRefill time.Time
+
+	// requests, waits and waited count tokens taken, denied Allows and
+	// blocked Waits, and time spent blocked, for Stats
+	requests int64
+	waits    int64
+	waited   time.Duration
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -44,22 +51,36 @@ func (rl *RateLimiter) Allow() bool {
 
 	if rl.tokens > 0 {
 		rl.tokens--
+		rl.requests++
 		return true
 	}
+	rl.waits++
 	return false
 }
 
 // Wait blocks until a token is available, then consumes it
 // Calculates optimal wait time instead of busy-waiting
 func (rl *RateLimiter) Wait() {
+	_ = rl.WaitContext(context.Background())
+}
+
+// WaitContext is Wait that gives up with ctx's error once ctx is done,
+// without consuming a token
+func (rl *RateLimiter) WaitContext(ctx context.Context) error {
+	var blocked time.Duration
 	for {
 		rl.mu.Lock()
 		rl.refillTokens()
 
 		if rl.tokens > 0 {
 			rl.tokens--
+			rl.requests++
+			if blocked > 0 {
+				rl.waits++
+				rl.waited += blocked
+			}
 			rl.mu.Unlock()
-			return
+			return nil
 		}
 
 		// Calculate time until next refill while holding the lock
@@ -68,11 +89,18 @@ func (rl *RateLimiter) Wait() {
 		rl.mu.Unlock()
 
 		// Wait for next refill or minimum time
-		if timeUntilRefill > 0 {
-			time.Sleep(timeUntilRefill)
-		} else {
-			time.Sleep(100 * time.Millisecond)
+		if timeUntilRefill <= 0 {
+			timeUntilRefill = 100 * time.Millisecond
+		}
+		start := time.Now()
+		timer := time.NewTimer(timeUntilRefill)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
 		}
+		blocked += time.Since(start)
 	}
 }
 
@@ -100,6 +128,15 @@ func (rl *RateLimiter) Available() int {
 	return rl.tokens
 }
 
+// Stats returns the requests let through, how often a request was held
+// back (a denied Allow or a Wait that blocked), and the total time Wait
+// spent blocked
+func (rl *RateLimiter) Stats() (requests, waits int64, waited time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.requests, rl.waits, rl.waited
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
@@ -0,0 +1,104 @@
+package artwork
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+	"github.com/rs/zerolog/log"
+)
+
+// Source names an artwork provider in a poster source chain
+type Source string
+
+const (
+	// SourceTMDB uses the poster path returned by TMDB enrichment
+	SourceTMDB Source = "tmdb"
+	// SourceFanart looks the movie up on fanart.tv by TMDB or IMDb ID
+	SourceFanart Source = "fanart"
+	// SourceOMDb looks the movie up on OMDb by IMDb ID
+	SourceOMDb Source = "omdb"
+)
+
+// SourceKeys holds the API keys of poster sources that need one
+type SourceKeys struct {
+	Fanart string
+	OMDb   string
+}
+
+// PosterSource fetches a movie poster from one artwork provider
+type PosterSource interface {
+	// Name identifies the source in logs
+	Name() Source
+	// FetchMoviePoster saves the movie's poster at destPath and returns the
+	// image URL it came from. It returns ErrNotFound when the provider has
+	// no poster for the movie.
+	FetchMoviePoster(ctx context.Context, movie *types.MovieMetadata, destPath string) (string, error)
+}
+
+// PosterChain tries poster sources in order until one yields an image
+type PosterChain struct {
+	sources []PosterSource
+}
+
+// NewPosterChain creates a chain trying sources in the given order
+func NewPosterChain(sources ...PosterSource) *PosterChain {
+	return &PosterChain{sources: sources}
+}
+
+// NewMoviePosterChain builds a chain from configured source names. TMDB
+// downloads go through tmdb so its size and image base apply; fanart.tv and
+// OMDb sources without an API key are left out.
+func NewMoviePosterChain(names []Source, tmdb *TMDBDownloader, config Config, keys SourceKeys) *PosterChain {
+	var sources []PosterSource
+	for _, name := range names {
+		switch name {
+		case SourceTMDB:
+			sources = append(sources, tmdb)
+		case SourceFanart:
+			if keys.Fanart != "" {
+				sources = append(sources, NewFanartDownloader(config, keys.Fanart))
+			}
+		case SourceOMDb:
+			if keys.OMDb != "" {
+				sources = append(sources, NewOMDbDownloader(config, keys.OMDb))
+			}
+		}
+	}
+	return NewPosterChain(sources...)
+}
+
+// Len returns the number of sources in the chain
+func (c *PosterChain) Len() int {
+	return len(c.sources)
+}
+
+// FetchMoviePoster tries each source in turn and returns the name of the
+// one that supplied the poster and its image URL. A source failing with an
+// error is logged and skipped like one without a poster; if no source
+// yields an image, the last such error is returned, or ErrNotFound when
+// every source simply had none.
+func (c *PosterChain) FetchMoviePoster(ctx context.Context, movie *types.MovieMetadata, destPath string) (Source, string, error) {
+	var lastErr error
+	for _, source := range c.sources {
+		imageURL, err := source.FetchMoviePoster(ctx, movie, destPath)
+		if err == nil {
+			return source.Name(), imageURL, nil
+		}
+		if ctx.Err() != nil {
+			return "", "", ctx.Err()
+		}
+		if errors.Is(err, ErrNotFound) {
+			log.Debug().Str("source", string(source.Name())).Msg("No poster from source, trying next")
+			continue
+		}
+		log.Warn().Err(err).Str("source", string(source.Name())).Msg("Poster source failed, trying next")
+		lastErr = fmt.Errorf("%s: %w", source.Name(), err)
+	}
+
+	if lastErr != nil {
+		return "", "", lastErr
+	}
+	return "", "", ErrNotFound
+}
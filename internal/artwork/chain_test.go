@@ -0,0 +1,120 @@
+package artwork
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestPosterChain_FallsBackToNextSource(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fanart/movies/603":
+			// fanart.tv knows the movie but has no poster for it
+			w.Write([]byte(`{"name":"The Matrix","moviebackground":[{"url":"x"}]}`))
+		case "/omdb/":
+			if r.URL.Query().Get("i") != "tt0133093" {
+				t.Errorf("OMDb lookup id = %q, want tt0133093", r.URL.Query().Get("i"))
+			}
+			w.Write([]byte(`{"Response":"True","Poster":"` + server.URL + `/images/matrix.jpg"}`))
+		case "/images/matrix.jpg":
+			w.Write([]byte("poster data"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.RetryDelay = time.Millisecond
+
+	tmdb := NewTMDBDownloader(config, SizeMedium)
+	fanart := NewFanartDownloader(config, "fanart-key")
+	fanart.SetBaseURL(server.URL + "/fanart")
+	omdb := NewOMDbDownloader(config, "omdb-key")
+	omdb.SetBaseURL(server.URL + "/omdb")
+
+	// TMDB found no poster, fanart.tv has none, OMDb has one
+	movie := &types.MovieMetadata{TMDBID: 603, IMDBID: "tt0133093"}
+	destPath := filepath.Join(t.TempDir(), "poster.jpg")
+
+	chain := NewPosterChain(tmdb, fanart, omdb)
+	source, imageURL, err := chain.FetchMoviePoster(context.Background(), movie, destPath)
+	if err != nil {
+		t.Fatalf("FetchMoviePoster() error = %v", err)
+	}
+	if source != SourceOMDb {
+		t.Errorf("source = %q, want %q", source, SourceOMDb)
+	}
+	if imageURL != server.URL+"/images/matrix.jpg" {
+		t.Errorf("imageURL = %q, want the OMDb poster", imageURL)
+	}
+	if !FileExists(destPath) {
+		t.Error("expected the poster to be downloaded")
+	}
+}
+
+func TestPosterChain_NotFoundVersusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fanart/movies/603":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.RetryDelay = time.Millisecond
+	movie := &types.MovieMetadata{TMDBID: 603, IMDBID: "tt0133093"}
+	destPath := filepath.Join(t.TempDir(), "poster.jpg")
+
+	fanart := NewFanartDownloader(config, "fanart-key")
+	fanart.SetBaseURL(server.URL + "/fanart")
+	_, _, err := NewPosterChain(NewTMDBDownloader(config, SizeMedium), fanart).FetchMoviePoster(context.Background(), movie, destPath)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("FetchMoviePoster() error = %v, want ErrNotFound when no source has a poster", err)
+	}
+
+	omdb := NewOMDbDownloader(config, "omdb-key")
+	omdb.SetBaseURL(server.URL + "/omdb")
+	_, _, err = NewPosterChain(fanart, omdb).FetchMoviePoster(context.Background(), movie, destPath)
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("FetchMoviePoster() error = %v, want the OMDb failure", err)
+	}
+}
+
+func TestNewMoviePosterChain_SkipsSourcesWithoutKeys(t *testing.T) {
+	config := DefaultConfig()
+	tmdb := NewTMDBDownloader(config, SizeMedium)
+	names := []Source{SourceTMDB, SourceFanart, SourceOMDb}
+
+	if got := NewMoviePosterChain(names, tmdb, config, SourceKeys{}).Len(); got != 1 {
+		t.Errorf("Len() without keys = %d, want 1", got)
+	}
+	if got := NewMoviePosterChain(names, tmdb, config, SourceKeys{Fanart: "a", OMDb: "b"}).Len(); got != 3 {
+		t.Errorf("Len() with keys = %d, want 3", got)
+	}
+}
+
+func TestSelectFanartPoster(t *testing.T) {
+	images := []fanartImage{
+		{URL: "de", Lang: "de", Likes: "9"},
+		{URL: "en-low", Lang: "en", Likes: "1"},
+		{URL: "en-high", Lang: "en", Likes: "5"},
+	}
+	if got := selectFanartPoster(images); got != "en-high" {
+		t.Errorf("selectFanartPoster() = %q, want en-high", got)
+	}
+	if got := selectFanartPoster(images[:1]); got != "de" {
+		t.Errorf("selectFanartPoster() = %q, want de when no English poster exists", got)
+	}
+}
@@ -2,6 +2,7 @@ package artwork
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -23,12 +24,19 @@ const (
 	DefaultRetryDelay = 1 * time.Second
 )
 
+// ErrNotFound reports that a source has no image for the requested media, as
+// opposed to a failed request; a source chain moves on to its next source
+var ErrNotFound = errors.New("artwork not found")
+
 // Config holds configuration for artwork downloaders
 type Config struct {
 	Timeout    time.Duration
 	MaxRetries int
 	RetryDelay time.Duration
 	Force      bool // Force re-download even if file exists
+	// Format decides whether PNG and GIF artwork is converted to JPEG or
+	// kept as served; empty means FormatJPEG
+	Format Format
 }
 
 // DefaultConfig returns default configuration
@@ -38,6 +46,7 @@ func DefaultConfig() Config {
 		MaxRetries: DefaultMaxRetries,
 		RetryDelay: DefaultRetryDelay,
 		Force:      false,
+		Format:     FormatJPEG,
 	}
 }
 
@@ -58,6 +67,9 @@ func NewBaseDownloader(config Config) *BaseDownloader {
 	if config.RetryDelay == 0 {
 		config.RetryDelay = DefaultRetryDelay
 	}
+	if config.Format == "" {
+		config.Format = FormatJPEG
+	}
 
 	return &BaseDownloader{
 		httpClient: &http.Client{
@@ -68,14 +80,16 @@ func NewBaseDownloader(config Config) *BaseDownloader {
 }
 
 // DownloadImage downloads an image from the given URL to the destination path
-// It handles retries, checks if file exists, and validates the downloaded image
+// It handles retries, checks if file exists, and validates the downloaded image.
+// The image's real format is detected from its magic bytes: PNG and GIF are
+// converted to JPEG under FormatJPEG, and otherwise a non-JPEG image is saved
+// with its own extension in place of destPath's (see SavedImage).
 func (d *BaseDownloader) DownloadImage(ctx context.Context, imageURL, destPath string) error {
 	// Check if file already exists and skip if not forcing
 	if !d.config.Force {
-		if info, err := os.Stat(destPath); err == nil && info.Size() > 0 {
+		if existing := SavedImage(destPath); existing != "" {
 			log.Debug().
-				Str("path", destPath).
-				Int64("size", info.Size()).
+				Str("path", existing).
 				Msg("Artwork already exists, skipping download")
 			return nil
 		}
@@ -114,6 +128,10 @@ func (d *BaseDownloader) DownloadImage(ctx context.Context, imageURL, destPath s
 			return nil
 		}
 
+		if errors.Is(err, ErrNotFound) {
+			return err
+		}
+
 		lastErr = err
 		log.Warn().
 			Err(err).
@@ -140,6 +158,11 @@ func (d *BaseDownloader) downloadOnce(ctx context.Context, imageURL, destPath st
 	}
 	defer resp.Body.Close()
 
+	// A missing image will not appear on retry
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, imageURL)
+	}
+
 	// Check for successful response
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
@@ -169,19 +192,54 @@ func (d *BaseDownloader) downloadOnce(ctx context.Context, imageURL, destPath st
 		return fmt.Errorf("downloaded file is empty")
 	}
 
+	savePath, err := d.storeImage(tmpPath, destPath)
+	if err != nil {
+		return err
+	}
+
 	// Move temp file to final destination
-	if err := os.Rename(tmpPath, destPath); err != nil {
+	if err := os.Rename(tmpPath, savePath); err != nil {
 		return fmt.Errorf("failed to move file to destination: %w", err)
 	}
 
 	log.Debug().
-		Str("path", destPath).
+		Str("path", savePath).
 		Int64("size", written).
 		Msg("Image file written successfully")
 
 	return nil
 }
 
+// storeImage prepares a downloaded image at tmpPath for saving and returns
+// the path it should be saved at: converted to JPEG in place under
+// FormatJPEG, or destPath with the extension of the image's real format.
+// Data that is not a recognized image is saved at destPath unchanged.
+func (d *BaseDownloader) storeImage(tmpPath, destPath string) (string, error) {
+	head, err := readHead(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image data: %w", err)
+	}
+
+	ext := sniffImage(head)
+	switch {
+	case ext == "":
+		return destPath, nil
+	case d.config.Format == FormatJPEG && (ext == ".png" || ext == ".gif"):
+		converted := tmpPath + ".jpg"
+		defer os.Remove(converted)
+		if err := convertToJPEG(tmpPath, converted); err != nil {
+			return "", err
+		}
+		if err := os.Rename(converted, tmpPath); err != nil {
+			return "", fmt.Errorf("failed to replace converted image: %w", err)
+		}
+		return withImageExt(destPath, ".jpg"), nil
+	case d.config.Format == FormatJPEG && ext == ".webp":
+		log.Warn().Str("dest", destPath).Msg("WebP artwork cannot be converted to JPEG, keeping it as WebP")
+	}
+	return withImageExt(destPath, ext), nil
+}
+
 // FileExists checks if a file exists and has non-zero size
 func FileExists(path string) bool {
 	info, err := os.Stat(path)
@@ -1,7 +1,13 @@
 package artwork
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -134,6 +140,97 @@ func TestDownloadImage(t *testing.T) {
 	}
 }
 
+// pngServer serves a small PNG image
+func pngServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.NRGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buf.Bytes())
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadImage_ConvertsPNGToJPEG(t *testing.T) {
+	server := pngServer(t)
+	destPath := filepath.Join(t.TempDir(), "poster.jpg")
+
+	downloader := NewBaseDownloader(DefaultConfig())
+	if err := downloader.DownloadImage(context.Background(), server.URL, destPath); err != nil {
+		t.Fatalf("DownloadImage() error = %v", err)
+	}
+
+	f, err := os.Open(destPath)
+	if err != nil {
+		t.Fatalf("poster.jpg not written: %v", err)
+	}
+	defer f.Close()
+	img, err := jpeg.Decode(f)
+	if err != nil {
+		t.Fatalf("poster.jpg is not a JPEG: %v", err)
+	}
+	if img.Bounds().Dx() != 4 {
+		t.Errorf("converted width = %d, want 4", img.Bounds().Dx())
+	}
+	if FileExists(filepath.Join(filepath.Dir(destPath), "poster.png")) {
+		t.Error("poster.png written alongside the converted poster")
+	}
+}
+
+func TestDownloadImage_OriginalFormat(t *testing.T) {
+	server := pngServer(t)
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "poster.jpg")
+
+	config := DefaultConfig()
+	config.Format = FormatOriginal
+	downloader := NewBaseDownloader(config)
+	if err := downloader.DownloadImage(context.Background(), server.URL, destPath); err != nil {
+		t.Fatalf("DownloadImage() error = %v", err)
+	}
+
+	want := filepath.Join(dir, "poster.png")
+	if FileExists(destPath) {
+		t.Error("PNG data saved under a .jpg name")
+	}
+	if got := SavedImage(destPath); got != want {
+		t.Fatalf("SavedImage() = %q, want %q", got, want)
+	}
+	data, _ := os.ReadFile(want)
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("poster.png is not a PNG: %v", err)
+	}
+}
+
+func TestDownloadImage_WebPKeptAsWebP(t *testing.T) {
+	webp := append([]byte("RIFF\x00\x00\x00\x00WEBPVP8 "), make([]byte, 32)...)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(webp)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "poster.jpg")
+
+	downloader := NewBaseDownloader(DefaultConfig())
+	if err := downloader.DownloadImage(context.Background(), server.URL, destPath); err != nil {
+		t.Fatalf("DownloadImage() error = %v", err)
+	}
+	if got, want := SavedImage(destPath), filepath.Join(dir, "poster.webp"); got != want {
+		t.Errorf("SavedImage() = %q, want %q", got, want)
+	}
+
+	// The saved WebP counts as existing artwork, so it is not fetched again
+	server.Close()
+	if err := downloader.DownloadImage(context.Background(), server.URL, destPath); err != nil {
+		t.Errorf("second DownloadImage() error = %v, want skip", err)
+	}
+}
+
 func TestDownloadImageWithRetries(t *testing.T) {
 	attempts := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -170,6 +267,27 @@ func TestDownloadImageWithRetries(t *testing.T) {
 	}
 }
 
+func TestDownloadImageNotFoundIsNotRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.RetryDelay = time.Millisecond
+	downloader := NewBaseDownloader(config)
+
+	err := downloader.DownloadImage(context.Background(), server.URL, filepath.Join(t.TempDir(), "missing.jpg"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("DownloadImage() error = %v, want ErrNotFound", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
 func TestDownloadImageFailure(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,137 @@
+package artwork
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+	"github.com/rs/zerolog/log"
+)
+
+// FanartBaseURL is the base URL for the fanart.tv API
+const FanartBaseURL = "https://webservice.fanart.tv/v3"
+
+// FanartDownloader handles movie poster downloads from fanart.tv
+type FanartDownloader struct {
+	*BaseDownloader
+	apiKey  string
+	baseURL string
+}
+
+// fanartMovieResponse is the part of a fanart.tv movie response we use
+type fanartMovieResponse struct {
+	MoviePoster []fanartImage `json:"movieposter"`
+}
+
+// fanartImage is a single fanart.tv image
+type fanartImage struct {
+	URL   string `json:"url"`
+	Lang  string `json:"lang"`
+	Likes string `json:"likes"`
+}
+
+// NewFanartDownloader creates a new fanart.tv artwork downloader
+func NewFanartDownloader(config Config, apiKey string) *FanartDownloader {
+	return &FanartDownloader{
+		BaseDownloader: NewBaseDownloader(config),
+		apiKey:         apiKey,
+		baseURL:        FanartBaseURL,
+	}
+}
+
+// SetBaseURL overrides the fanart.tv API base URL; empty restores the default
+func (d *FanartDownloader) SetBaseURL(base string) {
+	if base == "" {
+		base = FanartBaseURL
+	}
+	d.baseURL = strings.TrimSuffix(base, "/")
+}
+
+// Name identifies fanart.tv in a poster source chain
+func (d *FanartDownloader) Name() Source {
+	return SourceFanart
+}
+
+// FetchMoviePoster looks the movie up by TMDB ID, or IMDb ID, and downloads
+// its best-liked English poster to destPath. It returns ErrNotFound when
+// the movie has no ID or fanart.tv has no poster for it.
+func (d *FanartDownloader) FetchMoviePoster(ctx context.Context, movie *types.MovieMetadata, destPath string) (string, error) {
+	if movie == nil {
+		return "", ErrNotFound
+	}
+	var id string
+	switch {
+	case movie.TMDBID > 0:
+		id = strconv.Itoa(movie.TMDBID)
+	case movie.IMDBID != "":
+		id = movie.IMDBID
+	default:
+		return "", ErrNotFound
+	}
+
+	apiURL := fmt.Sprintf("%s/movies/%s?api_key=%s", d.baseURL, url.PathEscape(id), url.QueryEscape(d.apiKey))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result fanartMovieResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	imageURL := selectFanartPoster(result.MoviePoster)
+	if imageURL == "" {
+		return "", ErrNotFound
+	}
+
+	log.Info().
+		Str("id", id).
+		Str("dest", destPath).
+		Msg("Downloading movie poster from fanart.tv")
+
+	if err := d.DownloadImage(ctx, imageURL, destPath); err != nil {
+		return "", err
+	}
+	return imageURL, nil
+}
+
+// selectFanartPoster picks the most-liked English poster, falling back to
+// textless ("00") and then any poster
+func selectFanartPoster(images []fanartImage) string {
+	for _, lang := range []string{"en", "00", ""} {
+		best, bestLikes := "", -1
+		for _, img := range images {
+			if img.URL == "" || (lang != "" && img.Lang != lang) {
+				continue
+			}
+			likes, _ := strconv.Atoi(img.Likes)
+			if likes > bestLikes {
+				best, bestLikes = img.URL, likes
+			}
+		}
+		if best != "" {
+			return best
+		}
+	}
+	return ""
+}
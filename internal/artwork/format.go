@@ -0,0 +1,123 @@
+package artwork
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register GIF decoding for JPEG conversion
+	"image/jpeg"
+	_ "image/png" // register PNG decoding for JPEG conversion
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format selects how downloaded artwork is stored
+type Format string
+
+const (
+	// FormatJPEG converts PNG and GIF artwork to JPEG so Jellyfin-facing
+	// names stay "poster.jpg"; WebP, which the standard library cannot
+	// decode, is kept as ".webp"
+	FormatJPEG Format = "jpeg"
+	// FormatOriginal keeps artwork as served, named with the extension of
+	// its actual format ("poster.png")
+	FormatOriginal Format = "original"
+)
+
+// jpegQuality is used when converting artwork to JPEG
+const jpegQuality = 90
+
+// imageExtensions are the extensions artwork may be saved under
+var imageExtensions = []string{".jpg", ".jpeg", ".png", ".webp", ".gif"}
+
+// sniffImage returns the extension for the image format of data, judged by
+// its magic bytes, or "" when it is not a recognized image
+func sniffImage(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ""
+	}
+}
+
+// withImageExt returns path with its extension replaced by ext, leaving
+// ".jpeg" alone for JPEG data
+func withImageExt(path, ext string) string {
+	current := strings.ToLower(filepath.Ext(path))
+	if current == ext || (ext == ".jpg" && current == ".jpeg") {
+		return path
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ext
+}
+
+// SavedImage returns the file artwork requested at destPath was saved as:
+// destPath itself or the same name with the extension of the format that
+// was actually served ("poster.jpg" may be "poster.webp"). It returns ""
+// when none exists.
+func SavedImage(destPath string) string {
+	if FileExists(destPath) {
+		return destPath
+	}
+	stem := strings.TrimSuffix(destPath, filepath.Ext(destPath))
+	for _, ext := range imageExtensions {
+		if p := stem + ext; FileExists(p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// readHead returns up to the first 512 bytes of a file, enough to sniff its type
+func readHead(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// convertToJPEG re-encodes the image at src as a JPEG written to dst.
+// Transparent areas are filled with white, since JPEG has no alpha channel.
+func convertToJPEG(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	img, _, err := image.Decode(in)
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err := jpeg.Encode(out, flat, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	return out.Close()
+}
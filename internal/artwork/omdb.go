@@ -0,0 +1,106 @@
+package artwork
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+	"github.com/rs/zerolog/log"
+)
+
+// OMDbBaseURL is the base URL for the OMDb API
+const OMDbBaseURL = "https://www.omdbapi.com/"
+
+// OMDbDownloader handles movie poster downloads from OMDb
+type OMDbDownloader struct {
+	*BaseDownloader
+	apiKey  string
+	baseURL string
+}
+
+// omdbResponse is the part of an OMDb title response we use
+type omdbResponse struct {
+	Poster   string `json:"Poster"`
+	Response string `json:"Response"`
+}
+
+// NewOMDbDownloader creates a new OMDb artwork downloader
+func NewOMDbDownloader(config Config, apiKey string) *OMDbDownloader {
+	return &OMDbDownloader{
+		BaseDownloader: NewBaseDownloader(config),
+		apiKey:         apiKey,
+		baseURL:        OMDbBaseURL,
+	}
+}
+
+// SetBaseURL overrides the OMDb API base URL; empty restores the default
+func (d *OMDbDownloader) SetBaseURL(base string) {
+	if base == "" {
+		base = OMDbBaseURL
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	d.baseURL = base
+}
+
+// Name identifies OMDb in a poster source chain
+func (d *OMDbDownloader) Name() Source {
+	return SourceOMDb
+}
+
+// FetchMoviePoster looks the movie up by IMDb ID and downloads its poster to
+// destPath. It returns ErrNotFound when the movie has no IMDb ID or OMDb
+// has no poster for it.
+func (d *OMDbDownloader) FetchMoviePoster(ctx context.Context, movie *types.MovieMetadata, destPath string) (string, error) {
+	if movie == nil || movie.IMDBID == "" {
+		return "", ErrNotFound
+	}
+
+	params := url.Values{}
+	params.Set("apikey", d.apiKey)
+	params.Set("i", movie.IMDBID)
+	apiURL := d.baseURL + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result omdbResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// OMDb answers unknown titles with Response "False" and missing
+	// posters with "N/A"
+	if ok, _ := strconv.ParseBool(result.Response); !ok || result.Poster == "" || result.Poster == "N/A" {
+		return "", ErrNotFound
+	}
+
+	log.Info().
+		Str("imdb_id", movie.IMDBID).
+		Str("dest", destPath).
+		Msg("Downloading movie poster from OMDb")
+
+	if err := d.DownloadImage(ctx, result.Poster, destPath); err != nil {
+		return "", err
+	}
+	return result.Poster, nil
+}
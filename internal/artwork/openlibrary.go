@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"path/filepath"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 )
@@ -18,6 +19,7 @@ const (
 type OpenLibraryDownloader struct {
 	*BaseDownloader
 	imageSize ImageSize
+	baseURL   string
 }
 
 // NewOpenLibraryDownloader creates a new OpenLibrary cover downloader
@@ -29,9 +31,19 @@ func NewOpenLibraryDownloader(config Config, size ImageSize) *OpenLibraryDownloa
 	return &OpenLibraryDownloader{
 		BaseDownloader: NewBaseDownloader(config),
 		imageSize:      size,
+		baseURL:        OpenLibraryCoversBaseURL,
 	}
 }
 
+// SetBaseURL overrides the OpenLibrary Covers API base URL (e.g. a mirror).
+// An empty value restores the default.
+func (d *OpenLibraryDownloader) SetBaseURL(base string) {
+	if base == "" {
+		base = OpenLibraryCoversBaseURL
+	}
+	d.baseURL = strings.TrimSuffix(base, "/")
+}
+
 // DownloadBookCoverByISBN downloads book cover by ISBN
 func (d *OpenLibraryDownloader) DownloadBookCoverByISBN(ctx context.Context, isbn, destDir string) error {
 	if isbn == "" {
@@ -40,7 +52,7 @@ func (d *OpenLibraryDownloader) DownloadBookCoverByISBN(ctx context.Context, isb
 	}
 
 	sizeStr := d.getSizeString()
-	imageURL := fmt.Sprintf("%s/isbn/%s-%s.jpg?default=false", OpenLibraryCoversBaseURL, isbn, sizeStr)
+	imageURL := fmt.Sprintf("%s/isbn/%s-%s.jpg?default=false", d.baseURL, isbn, sizeStr)
 	destPath := filepath.Join(destDir, "cover.jpg")
 
 	log.Info().
@@ -49,16 +61,10 @@ func (d *OpenLibraryDownloader) DownloadBookCoverByISBN(ctx context.Context, isb
 		Str("dest", destPath).
 		Msg("Downloading book cover")
 
-	// Try to download with ?default=false to get 404 if no cover exists
-	err := d.downloadWithFallback(ctx, imageURL, destPath)
-	if err != nil {
-		log.Warn().
-			Err(err).
-			Str("isbn", isbn).
-			Msg("Failed to download book cover")
-		return nil // Don't fail if cover is missing
+	// ?default=false makes a missing cover a 404, which is not an error
+	if err := d.downloadWithFallback(ctx, imageURL, destPath); err != nil {
+		return fmt.Errorf("failed to download book cover for ISBN %s: %w", isbn, err)
 	}
-
 	return nil
 }
 
@@ -70,7 +76,7 @@ func (d *OpenLibraryDownloader) DownloadBookCoverByOLID(ctx context.Context, oli
 	}
 
 	sizeStr := d.getSizeString()
-	imageURL := fmt.Sprintf("%s/olid/%s-%s.jpg?default=false", OpenLibraryCoversBaseURL, olid, sizeStr)
+	imageURL := fmt.Sprintf("%s/olid/%s-%s.jpg?default=false", d.baseURL, olid, sizeStr)
 	destPath := filepath.Join(destDir, "cover.jpg")
 
 	log.Info().
@@ -79,13 +85,8 @@ func (d *OpenLibraryDownloader) DownloadBookCoverByOLID(ctx context.Context, oli
 		Str("dest", destPath).
 		Msg("Downloading book cover")
 
-	err := d.downloadWithFallback(ctx, imageURL, destPath)
-	if err != nil {
-		log.Warn().
-			Err(err).
-			Str("olid", olid).
-			Msg("Failed to download book cover")
-		return nil // Don't fail if cover is missing
+	if err := d.downloadWithFallback(ctx, imageURL, destPath); err != nil {
+		return fmt.Errorf("failed to download book cover for %s: %w", olid, err)
 	}
 
 	return nil
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -50,28 +52,44 @@ func TestNewOpenLibraryDownloader(t *testing.T) {
 	}
 }
 
+// coversServer serves the OpenLibrary Covers API: a cover for 0385472579 and
+// OL7440033M, none for 0000000000 and OL1M, and an error for anything else
+func coversServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/isbn/0385472579-L.jpg", "/olid/OL7440033M-L.jpg":
+			w.Write([]byte("fake image data"))
+		case "/isbn/0000000000-L.jpg", "/olid/OL1M-L.jpg":
+			http.NotFound(w, r)
+		default:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
 func TestOpenLibraryDownloader_DownloadBookCoverByISBN(t *testing.T) {
 	tests := []struct {
 		name        string
 		isbn        string
+		expectCover bool
 		expectError bool
 	}{
-		{
-			name:        "Empty ISBN",
-			isbn:        "",
-			expectError: false,
-		},
-		{
-			name:        "Valid ISBN",
-			isbn:        "0385472579",
-			expectError: false,
-		},
+		{name: "Empty ISBN", isbn: ""},
+		{name: "Valid ISBN", isbn: "0385472579", expectCover: true},
+		{name: "No cover", isbn: "0000000000"},
+		{name: "Server error", isbn: "9780000000002", expectError: true},
 	}
 
+	server := coversServer(t)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			config := DefaultConfig()
+			config.MaxRetries = 1
 			downloader := NewOpenLibraryDownloader(config, SizeLarge)
+			downloader.SetBaseURL(server.URL + "/")
 			tempDir := t.TempDir()
 
 			err := downloader.DownloadBookCoverByISBN(context.Background(), tt.isbn, tempDir)
@@ -82,6 +100,9 @@ func TestOpenLibraryDownloader_DownloadBookCoverByISBN(t *testing.T) {
 			if !tt.expectError && err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
+			if _, err := os.Stat(filepath.Join(tempDir, "cover.jpg")); (err == nil) != tt.expectCover {
+				t.Errorf("cover.jpg written = %v, want %v", err == nil, tt.expectCover)
+			}
 		})
 	}
 }
@@ -92,22 +113,19 @@ func TestOpenLibraryDownloader_DownloadBookCoverByOLID(t *testing.T) {
 		olid        string
 		expectError bool
 	}{
-		{
-			name:        "Empty OLID",
-			olid:        "",
-			expectError: false,
-		},
-		{
-			name:        "Valid OLID",
-			olid:        "OL7440033M",
-			expectError: false,
-		},
+		{name: "Empty OLID", olid: ""},
+		{name: "Valid OLID", olid: "OL7440033M"},
+		{name: "No cover", olid: "OL1M"},
+		{name: "Server error", olid: "OL2M", expectError: true},
 	}
 
+	server := coversServer(t)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			config := DefaultConfig()
+			config.MaxRetries = 1
 			downloader := NewOpenLibraryDownloader(config, SizeLarge)
+			downloader.SetBaseURL(server.URL)
 			tempDir := t.TempDir()
 
 			err := downloader.DownloadBookCoverByOLID(context.Background(), tt.olid, tempDir)
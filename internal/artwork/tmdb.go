@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 
+	"github.com/opd-ai/go-jf-org/pkg/types"
 	"github.com/rs/zerolog/log"
 )
 
 const (
 	// TMDBImageBaseURL is the base URL for TMDB images
 	TMDBImageBaseURL = "https://image.tmdb.org/t/p/"
+
+	// ThumbnailSize is the size used for poster thumbnails
+	ThumbnailSize = SizeSmall
 )
 
 // ImageSize represents artwork image size preference
@@ -27,7 +32,8 @@ const (
 // TMDBDownloader handles artwork downloads from TMDB
 type TMDBDownloader struct {
 	*BaseDownloader
-	imageSize ImageSize
+	imageSize    ImageSize
+	imageBaseURL string
 }
 
 // NewTMDBDownloader creates a new TMDB artwork downloader
@@ -39,18 +45,36 @@ func NewTMDBDownloader(config Config, size ImageSize) *TMDBDownloader {
 	return &TMDBDownloader{
 		BaseDownloader: NewBaseDownloader(config),
 		imageSize:      size,
+		imageBaseURL:   TMDBImageBaseURL,
+	}
+}
+
+// SetImageBaseURL overrides the TMDB image base URL (e.g. a mirror or proxy).
+// An empty value restores the default.
+func (d *TMDBDownloader) SetImageBaseURL(base string) {
+	if base == "" {
+		base = TMDBImageBaseURL
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
 	}
+	d.imageBaseURL = base
 }
 
 // DownloadMoviePoster downloads a movie poster to the specified directory
 func (d *TMDBDownloader) DownloadMoviePoster(ctx context.Context, posterPath, destDir string) error {
+	return d.DownloadMoviePosterTo(ctx, posterPath, filepath.Join(destDir, "poster.jpg"))
+}
+
+// DownloadMoviePosterTo downloads a movie poster to an explicit file path
+// (used for flat layouts where artwork is named after the movie file)
+func (d *TMDBDownloader) DownloadMoviePosterTo(ctx context.Context, posterPath, destPath string) error {
 	if posterPath == "" {
 		log.Debug().Msg("No poster path available, skipping poster download")
 		return nil
 	}
 
 	imageURL := d.buildImageURL(posterPath, true)
-	destPath := filepath.Join(destDir, "poster.jpg")
 
 	log.Info().
 		Str("url", imageURL).
@@ -60,15 +84,61 @@ func (d *TMDBDownloader) DownloadMoviePoster(ctx context.Context, posterPath, de
 	return d.DownloadImage(ctx, imageURL, destPath)
 }
 
+// Name identifies TMDB in a poster source chain
+func (d *TMDBDownloader) Name() Source {
+	return SourceTMDB
+}
+
+// FetchMoviePoster downloads the poster TMDB enrichment found for the movie
+// to destPath, returning ErrNotFound when there is none
+func (d *TMDBDownloader) FetchMoviePoster(ctx context.Context, movie *types.MovieMetadata, destPath string) (string, error) {
+	if movie == nil || movie.PosterURL == "" {
+		return "", ErrNotFound
+	}
+	if err := d.DownloadMoviePosterTo(ctx, movie.PosterURL, destPath); err != nil {
+		return "", err
+	}
+	return d.buildImageURL(movie.PosterURL, true), nil
+}
+
+// DownloadPosterThumbnailTo downloads a small version of a poster (movie or
+// TV) to an explicit file path, built from the same TMDB image path
+func (d *TMDBDownloader) DownloadPosterThumbnailTo(ctx context.Context, posterPath, destPath string) error {
+	if posterPath == "" {
+		log.Debug().Msg("No poster path available, skipping thumbnail download")
+		return nil
+	}
+
+	imageURL := d.buildImageURLForSize(posterPath, ThumbnailSize, true)
+
+	log.Info().
+		Str("url", imageURL).
+		Str("dest", destPath).
+		Msg("Downloading poster thumbnail")
+
+	return d.DownloadImage(ctx, imageURL, destPath)
+}
+
+// ThumbnailPath returns the thumbnail file path for a poster path
+// ("poster.jpg" -> "poster-thumb.jpg")
+func ThumbnailPath(posterPath string) string {
+	ext := filepath.Ext(posterPath)
+	return strings.TrimSuffix(posterPath, ext) + "-thumb" + ext
+}
+
 // DownloadMovieBackdrop downloads a movie backdrop to the specified directory
 func (d *TMDBDownloader) DownloadMovieBackdrop(ctx context.Context, backdropPath, destDir string) error {
+	return d.DownloadMovieBackdropTo(ctx, backdropPath, filepath.Join(destDir, "backdrop.jpg"))
+}
+
+// DownloadMovieBackdropTo downloads a movie backdrop to an explicit file path
+func (d *TMDBDownloader) DownloadMovieBackdropTo(ctx context.Context, backdropPath, destPath string) error {
 	if backdropPath == "" {
 		log.Debug().Msg("No backdrop path available, skipping backdrop download")
 		return nil
 	}
 
 	imageURL := d.buildImageURL(backdropPath, false)
-	destPath := filepath.Join(destDir, "backdrop.jpg")
 
 	log.Info().
 		Str("url", imageURL).
@@ -159,15 +229,30 @@ func (d *TMDBDownloader) DownloadTVArtwork(ctx context.Context, posterPath, dest
 
 // buildImageURL constructs the full TMDB image URL
 func (d *TMDBDownloader) buildImageURL(path string, isPoster bool) string {
-	sizeStr := d.getSizeString(isPoster)
-	return fmt.Sprintf("%s%s%s", TMDBImageBaseURL, sizeStr, path)
+	return d.buildImageURLForSize(path, d.imageSize, isPoster)
+}
+
+// buildImageURLForSize constructs the full TMDB image URL at a specific size.
+// path may be a TMDB image path ("/abc.jpg") or a full TMDB image URL, in
+// which case only its final path segment is used.
+func (d *TMDBDownloader) buildImageURLForSize(path string, size ImageSize, isPoster bool) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		path = path[strings.LastIndex(path, "/"):]
+	}
+	sizeStr := sizeString(size, isPoster)
+	return fmt.Sprintf("%s%s%s", d.imageBaseURL, sizeStr, path)
 }
 
 // getSizeString returns the appropriate size string for TMDB API
 func (d *TMDBDownloader) getSizeString(isPoster bool) string {
+	return sizeString(d.imageSize, isPoster)
+}
+
+// sizeString maps an image size to the TMDB size segment
+func sizeString(size ImageSize, isPoster bool) string {
 	if isPoster {
 		// Poster sizes: w92, w154, w185, w342, w500, w780, original
-		switch d.imageSize {
+		switch size {
 		case SizeSmall:
 			return "w185"
 		case SizeMedium:
@@ -181,7 +266,7 @@ func (d *TMDBDownloader) getSizeString(isPoster bool) string {
 		}
 	} else {
 		// Backdrop sizes: w300, w780, w1280, original
-		switch d.imageSize {
+		switch size {
 		case SizeSmall:
 			return "w300"
 		case SizeMedium:
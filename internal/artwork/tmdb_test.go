@@ -347,3 +347,54 @@ func TestGetSizeString(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildImageURL_CustomBase(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		expected string
+	}{
+		{"custom base", "https://images.example.com/tmdb/", "https://images.example.com/tmdb/w500/poster.jpg"},
+		{"custom base without trailing slash", "http://proxy.local:8080/t/p", "http://proxy.local:8080/t/p/w500/poster.jpg"},
+		{"empty base keeps default", "", "https://image.tmdb.org/t/p/w500/poster.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downloader := NewTMDBDownloader(DefaultConfig(), SizeMedium)
+			downloader.SetImageBaseURL(tt.base)
+
+			if got := downloader.buildImageURL("/poster.jpg", true); got != tt.expected {
+				t.Errorf("buildImageURL() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestThumbnailPath(t *testing.T) {
+	tests := []struct {
+		posterPath string
+		want       string
+	}{
+		{"/media/movies/Movie (2020)/poster.jpg", "/media/movies/Movie (2020)/poster-thumb.jpg"},
+		{"/media/movies/Movie (2020)-poster.jpg", "/media/movies/Movie (2020)-poster-thumb.jpg"},
+	}
+
+	for _, tt := range tests {
+		if got := ThumbnailPath(tt.posterPath); got != tt.want {
+			t.Errorf("ThumbnailPath(%q) = %q, want %q", tt.posterPath, got, tt.want)
+		}
+	}
+}
+
+func TestBuildImageURLForSize_FromFullURL(t *testing.T) {
+	downloader := NewTMDBDownloader(DefaultConfig(), SizeLarge)
+
+	full := "https://image.tmdb.org/t/p/w500/abc.jpg"
+	if got, want := downloader.buildImageURL(full, true), "https://image.tmdb.org/t/p/w780/abc.jpg"; got != want {
+		t.Errorf("buildImageURL(full URL) = %s, want %s", got, want)
+	}
+	if got, want := downloader.buildImageURLForSize(full, ThumbnailSize, true), "https://image.tmdb.org/t/p/w185/abc.jpg"; got != want {
+		t.Errorf("buildImageURLForSize(full URL, thumbnail) = %s, want %s", got, want)
+	}
+}
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -21,14 +23,49 @@ type Config struct {
 	APIKeys APIKeys `yaml:"api_keys" mapstructure:"api_keys"`
 	// Organize settings
 	Organize OrganizeSettings `yaml:"organize" mapstructure:"organize"`
+	// Naming settings
+	Naming NamingSettings `yaml:"naming" mapstructure:"naming"`
 	// Safety settings
 	Safety SafetySettings `yaml:"safety" mapstructure:"safety"`
 	// Filters for file selection
 	Filters FilterSettings `yaml:"filters" mapstructure:"filters"`
 	// Performance settings
 	Performance PerformanceSettings `yaml:"performance" mapstructure:"performance"`
+	// Genres settings for normalizing provider genres
+	Genres GenreSettings `yaml:"genres" mapstructure:"genres"`
+	// Artwork settings
+	Artwork ArtworkSettings `yaml:"artwork" mapstructure:"artwork"`
+	// NFO settings for generated NFO contents
+	NFO NFOSettings `yaml:"nfo" mapstructure:"nfo"`
+	// Integrity settings for hashes recorded with organize --hash
+	Integrity IntegritySettings `yaml:"integrity" mapstructure:"integrity"`
+	// Cleanup settings for tidying source folders after organize
+	Cleanup CleanupSettings `yaml:"cleanup" mapstructure:"cleanup"`
+	// Markers settings for Jellyfin .ignore files in sources and staging
+	Markers MarkerSettings `yaml:"markers" mapstructure:"markers"`
+	// Archives settings for RAR and 7z releases found while organizing
+	Archives ArchiveSettings `yaml:"archives" mapstructure:"archives"`
+	// Remote settings for sftp:// sources
+	Remote RemoteSettings `yaml:"remote" mapstructure:"remote"`
+	// Enrich settings for metadata lookups
+	Enrich EnrichSettings `yaml:"enrich" mapstructure:"enrich"`
+	// Notifications settings for run summaries
+	Notifications NotificationSettings `yaml:"notifications" mapstructure:"notifications"`
+	// Server settings for the serve command's HTTP API
+	Server ServerSettings `yaml:"server" mapstructure:"server"`
+	// Profiles are named sets of overrides, e.g. for a kids' or anime
+	// library; the one selected with --profile is merged over the settings
+	// above at load time, and anything it leaves out is inherited
+	Profiles map[string]map[string]interface{} `yaml:"profiles" mapstructure:"profiles"`
 }
 
+// regionPattern matches an ISO 3166-1 alpha-2 country code
+var regionPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// ErrUnknownProfile is returned by LoadProfile when the requested profile is
+// not defined in the config file
+var ErrUnknownProfile = errors.New("unknown profile")
+
 // Destinations contains paths for different media types
 type Destinations struct {
 	Movies string `yaml:"movies" mapstructure:"movies"`
@@ -43,6 +80,9 @@ type APIKeys struct {
 	MusicBrainzApp string `yaml:"musicbrainz_app" mapstructure:"musicbrainz_app"`
 	LastFM         string `yaml:"lastfm" mapstructure:"lastfm"`
 	GoogleBooksAPI string `yaml:"google_books_api" mapstructure:"google_books_api"`
+	// Fanart and OMDb are needed only when listed in artwork.sources
+	Fanart string `yaml:"fanart" mapstructure:"fanart"`
+	OMDb   string `yaml:"omdb" mapstructure:"omdb"`
 }
 
 // OrganizeSettings contains settings for file organization
@@ -51,6 +91,93 @@ type OrganizeSettings struct {
 	DownloadArtwork     bool `yaml:"download_artwork" mapstructure:"download_artwork"`
 	NormalizeNames      bool `yaml:"normalize_names" mapstructure:"normalize_names"`
 	PreserveQualityTags bool `yaml:"preserve_quality_tags" mapstructure:"preserve_quality_tags"`
+	// MovieLayout is "folder" (Movie (Year)/Movie (Year).mkv) or "flat" (Movie (Year).mkv)
+	MovieLayout string `yaml:"movie_layout" mapstructure:"movie_layout"`
+	// MiniSeriesLayout places episodes numbered without a season ("E01",
+	// "Part 2") under "Show/Season 01/" ("season") or directly in "Show/" ("show")
+	MiniSeriesLayout string `yaml:"miniseries_layout" mapstructure:"miniseries_layout"`
+	// MusicLayout is "artist" (Artist/Album (Year)) or "decade-artist" (1970s/Artist/Album (Year))
+	MusicLayout string `yaml:"music_layout" mapstructure:"music_layout"`
+	// DestStructure is "nested" (Jellyfin's per-title folders) or
+	// "flat-by-type" (every file directly in its library, e.g. "TV/Show - S01E01 - Title.mkv")
+	DestStructure string `yaml:"dest_structure" mapstructure:"dest_structure"`
+	// LooseTrackLayout places tracks without an album under "Artist/Unknown Album"
+	// ("unknown-album"), "Artist/Singles" ("singles") or
+	// "Various Artists/Compilations" ("compilations")
+	LooseTrackLayout string `yaml:"loose_track_layout" mapstructure:"loose_track_layout"`
+	// TrustedReleaseGroups ranks scene groups, most trusted first; the best
+	// duplicate of equal quality is the one from the highest-ranked group
+	TrustedReleaseGroups []string `yaml:"trusted_release_groups" mapstructure:"trusted_release_groups"`
+	// ReadMediaInfo takes a video's resolution and codec from a companion
+	// MediaInfo report ("Movie.mediainfo" or "Movie.txt") when present,
+	// overriding the tags in its name
+	ReadMediaInfo bool `yaml:"read_mediainfo" mapstructure:"read_mediainfo"`
+	// PreferFolderName takes a music track's artist and album from its
+	// "Artist/Album/" or "Artist - Album/" folders instead of its filename
+	PreferFolderName bool `yaml:"prefer_folder_name" mapstructure:"prefer_folder_name"`
+	// ProbeDuration reads the running time of videos whose filename could
+	// be a movie or a TV episode and lets it decide: up to 50 minutes is an
+	// episode, 75 minutes or more a movie
+	ProbeDuration bool `yaml:"probe_duration" mapstructure:"probe_duration"`
+	// ExtrasDirs are movie subfolders (extras/, trailers/, ...) accepted by
+	// verify and moved along with a movie from its own folder
+	ExtrasDirs []string `yaml:"extras_dirs" mapstructure:"extras_dirs"`
+	// ExtrasLayout places movie extras named with a Jellyfin suffix
+	// ("Movie-trailer.mkv"): "suffix" keeps them beside the movie as
+	// "Movie (2020)-trailer.mkv", "folder" moves them into the matching
+	// extras folder ("trailers/")
+	ExtrasLayout string `yaml:"extras_layout" mapstructure:"extras_layout"`
+	// SidecarNFOPolicy decides how a source's own NFO moved by
+	// --copy-sidecar-nfo combines with the generated one: "merge" keeps its
+	// fields and adds generated ones it lacks, "replace" uses it as is
+	SidecarNFOPolicy string `yaml:"sidecar_nfo_policy" mapstructure:"sidecar_nfo_policy"`
+	// CreateNFOFor lists the media types (movie, tv, music, book) that get
+	// NFOs when NFO creation is on, e.g. [movie, tv] to leave music to its tags
+	CreateNFOFor []string `yaml:"create_nfo_for" mapstructure:"create_nfo_for"`
+	// GroupByFirstLetter lists the media types (movie, tv, music, book)
+	// whose folders go in first-letter buckets ("Movies/M/The Matrix
+	// (1999)/", "#" for names not starting with a letter); naming.articles
+	// are skipped when picking the letter
+	GroupByFirstLetter []string `yaml:"group_by_first_letter" mapstructure:"group_by_first_letter"`
+	// AfterHook is a shell command run after a successful organize, e.g. to
+	// chown the files or trigger a library scan; empty runs nothing
+	AfterHook string `yaml:"after_hook" mapstructure:"after_hook"`
+}
+
+// NamingSettings contains folder naming settings
+type NamingSettings struct {
+	// SortArticles moves leading articles to the end of folder names ("Matrix, The (1999)")
+	SortArticles bool `yaml:"sort_articles" mapstructure:"sort_articles"`
+	// Articles lists the leading articles to move (add e.g. "Der", "Le", "L'" for other languages)
+	Articles []string `yaml:"articles" mapstructure:"articles"`
+	// SmallWords stay lowercase (except first and last) and Acronyms are
+	// capitalized when organize.normalize_names title-cases an all-lowercase
+	// movie or show title ("the lord of the rings" -> "The Lord of the Rings")
+	SmallWords []string `yaml:"small_words" mapstructure:"small_words"`
+	Acronyms   []string `yaml:"acronyms" mapstructure:"acronyms"`
+	// EpisodeTitleFallback is used as the episode title when none is known,
+	// e.g. "Episode {episode}" (tokens: {show}, {season}, {episode}); empty omits it
+	EpisodeTitleFallback string `yaml:"episode_title_fallback" mapstructure:"episode_title_fallback"`
+	// ASCIIFold transliterates folder and file names to ASCII ("Amélie" → "Amelie");
+	// NFO titles keep the original Unicode
+	ASCIIFold bool `yaml:"ascii_fold" mapstructure:"ascii_fold"`
+	// ASCIIFoldUnmapped is "keep" or "strip" for characters with no ASCII
+	// equivalent (e.g. CJK) when ASCIIFold is on
+	ASCIIFoldUnmapped string `yaml:"ascii_fold_unmapped" mapstructure:"ascii_fold_unmapped"`
+	// IDTokens appends "[tmdbid-603]" (or "[imdbid-tt...]") to movie and show
+	// names when the ID is known, so Jellyfin matches them exactly
+	IDTokens bool `yaml:"id_tokens" mapstructure:"id_tokens"`
+	// UnknownYear is "omit", "placeholder" or "quarantine" for movies,
+	// albums and books without a year
+	UnknownYear string `yaml:"unknown_year" mapstructure:"unknown_year"`
+	// UnknownYearPlaceholder replaces the year when UnknownYear is
+	// "placeholder", e.g. "0000" or "Unknown"
+	UnknownYearPlaceholder string `yaml:"unknown_year_placeholder" mapstructure:"unknown_year_placeholder"`
+	// Separator is "space-dash" ("Show - S01E01 - Pilot.mkv"), "dot" or
+	// "underscore" for generated filenames; folders keep Jellyfin's style
+	Separator string `yaml:"separator" mapstructure:"separator"`
+	// Case is "preserve", "title" or "lower" for generated filenames
+	Case string `yaml:"case" mapstructure:"case"`
 }
 
 // SafetySettings contains safety-related settings
@@ -59,15 +186,42 @@ type SafetySettings struct {
 	TransactionLog     bool   `yaml:"transaction_log" mapstructure:"transaction_log"`
 	LogDirectory       string `yaml:"log_directory" mapstructure:"log_directory"`
 	ConflictResolution string `yaml:"conflict_resolution" mapstructure:"conflict_resolution"` // skip, rename, interactive
-	BackupBeforeMove   bool   `yaml:"backup_before_move" mapstructure:"backup_before_move"`
+	// BackupBeforeMove copies each file into <log_directory>/backups/<transaction>
+	// before moving it, so rollback can restore files lost after organizing
+	BackupBeforeMove bool `yaml:"backup_before_move" mapstructure:"backup_before_move"`
+	// CollisionLimit is how many numeric suffixes (-1, -2, ...) the rename strategy tries
+	CollisionLimit int `yaml:"collision_limit" mapstructure:"collision_limit"`
+	// CollisionHashFallback appends a short source hash instead of failing when the limit is hit
+	CollisionHashFallback bool `yaml:"collision_hash_fallback" mapstructure:"collision_hash_fallback"`
+	// DestMustExist makes organize refuse destination roots that do not exist
+	// yet and confirm empty ones, catching mistyped paths
+	DestMustExist bool `yaml:"dest_must_exist" mapstructure:"dest_must_exist"`
+	// StrictValidation makes organize abort the whole run when any planned
+	// file fails validation, instead of organizing the valid ones
+	StrictValidation bool `yaml:"strict_validation" mapstructure:"strict_validation"`
+	// TransactionBatchSize writes the transaction log after every that many
+	// operation updates instead of after each one; 1 writes every update
+	TransactionBatchSize int `yaml:"transaction_batch_size" mapstructure:"transaction_batch_size"`
+	// TransactionBatchInterval also writes it on the first update this long
+	// after the last write (e.g. "500ms"); "0" disables the timer. Completing
+	// or failing a run always writes the whole log.
+	TransactionBatchInterval string `yaml:"transaction_batch_interval" mapstructure:"transaction_batch_interval"`
 }
 
 // FilterSettings contains file filtering settings
 type FilterSettings struct {
-	MinFileSize     string   `yaml:"min_file_size" mapstructure:"min_file_size"`
+	MinFileSize string `yaml:"min_file_size" mapstructure:"min_file_size"`
+	// SampleMaxSize flags 1080p videos below this size as likely sample clips
+	// (scaled for other resolutions); "0" disables the size check
+	SampleMaxSize string `yaml:"sample_max_size" mapstructure:"sample_max_size"`
+	// SampleAction is "warn" (organize with a warning) or "skip" for suspected samples
+	SampleAction    string   `yaml:"sample_action" mapstructure:"sample_action"`
 	VideoExtensions []string `yaml:"video_extensions" mapstructure:"video_extensions"`
 	AudioExtensions []string `yaml:"audio_extensions" mapstructure:"audio_extensions"`
 	BookExtensions  []string `yaml:"book_extensions" mapstructure:"book_extensions"`
+	// Exclude lists gitignore-style patterns skipped by every scan; a
+	// .jf-ignore in the source root is applied after them
+	Exclude []string `yaml:"exclude" mapstructure:"exclude"`
 }
 
 // PerformanceSettings contains performance-related settings
@@ -75,6 +229,160 @@ type PerformanceSettings struct {
 	MaxConcurrentOps int    `yaml:"max_concurrent_operations" mapstructure:"max_concurrent_operations"`
 	APIRateLimit     int    `yaml:"api_rate_limit" mapstructure:"api_rate_limit"`
 	CacheTTL         string `yaml:"cache_ttl" mapstructure:"cache_ttl"`
+	// CopyBufferSize is the buffer used to stream files when a move crosses
+	// filesystems, bounding memory use for very large files
+	CopyBufferSize string `yaml:"copy_buffer_size" mapstructure:"copy_buffer_size"`
+	// IOWorkers bounds concurrent file system work such as stat calls while
+	// scanning; NetWorkers bounds concurrent API and artwork requests
+	IOWorkers  int `yaml:"io_workers" mapstructure:"io_workers"`
+	NetWorkers int `yaml:"net_workers" mapstructure:"net_workers"`
+	// MaxOpsPerSec and MaxBytesPerSec throttle file moves and cross-filesystem
+	// copies so slow disks stay responsive; zero is unlimited
+	MaxOpsPerSec   int    `yaml:"max_ops_per_sec" mapstructure:"max_ops_per_sec"`
+	MaxBytesPerSec string `yaml:"max_bytes_per_sec" mapstructure:"max_bytes_per_sec"`
+	// StatsFile, when set, receives partial run statistics every
+	// StatsFlushInterval and every StatsFlushEvery updates, so a run that
+	// dies early still leaves them; the final report replaces them
+	StatsFile          string `yaml:"stats_file" mapstructure:"stats_file"`
+	StatsFlushInterval string `yaml:"stats_flush_interval" mapstructure:"stats_flush_interval"`
+	StatsFlushEvery    int    `yaml:"stats_flush_every" mapstructure:"stats_flush_every"`
+}
+
+// GenreSettings contains genre normalization settings
+type GenreSettings struct {
+	// Mapping collapses provider genre names (case-insensitive) to a canonical name
+	Mapping map[string]string `yaml:"mapping" mapstructure:"mapping"`
+	// Allowlist, when non-empty, drops any genre not listed after mapping
+	Allowlist []string `yaml:"allowlist" mapstructure:"allowlist"`
+}
+
+// ArtworkSettings contains artwork download settings
+type ArtworkSettings struct {
+	// TMDBImageBase is the base URL for TMDB images; point it at a mirror or proxy if needed
+	TMDBImageBase string `yaml:"tmdb_image_base" mapstructure:"tmdb_image_base"`
+	// GenerateThumbnails also downloads a small poster-thumb.jpg next to each poster
+	GenerateThumbnails bool `yaml:"generate_thumbnails" mapstructure:"generate_thumbnails"`
+	// ActorThumbs fetches TMDB credits (one extra request per movie or show)
+	// and writes the top ActorLimit actors with <thumb> image URLs into NFOs
+	ActorThumbs bool `yaml:"actor_thumbs" mapstructure:"actor_thumbs"`
+	// ActorImages also downloads those actors' images into a ".actors" folder
+	ActorImages bool `yaml:"actor_images" mapstructure:"actor_images"`
+	// ActorLimit is how many billed actors to keep
+	ActorLimit int `yaml:"actor_limit" mapstructure:"actor_limit"`
+	// Format is "jpeg" to convert PNG and GIF artwork to JPEG, keeping names
+	// like poster.jpg, or "original" to save it as served (poster.png)
+	Format string `yaml:"format" mapstructure:"format"`
+	// Sources is the order movie posters are looked for in ("tmdb",
+	// "fanart", "omdb"); the next source is tried when one has no poster
+	Sources []string `yaml:"sources" mapstructure:"sources"`
+}
+
+// NFOSettings contains settings for generated NFO contents
+type NFOSettings struct {
+	// RecordOriginalFilename adds an <original_filename> element holding a
+	// file's name before organizing to movie and book NFOs
+	RecordOriginalFilename bool `yaml:"record_original_filename" mapstructure:"record_original_filename"`
+}
+
+// IntegritySettings contains settings for file integrity hashes
+type IntegritySettings struct {
+	// Algorithm hashes organized files: "sha256" (default), "blake3"
+	// (faster on large videos) or "crc32" (cheap change detection only).
+	// Each transaction records the algorithm it used
+	Algorithm string `yaml:"algorithm" mapstructure:"algorithm"`
+}
+
+// CleanupSettings contains how organize tidies the source folders it moved
+// files out of, once every file of the run was organized
+type CleanupSettings struct {
+	// AfterOrganize turns the cleanup on (organize --clean-sources)
+	AfterOrganize bool `yaml:"after_organize" mapstructure:"after_organize"`
+	// JunkPatterns are globs for leftover files to clean, matched
+	// case-insensitively against file names ("*.nfo", "RARBG.txt")
+	JunkPatterns []string `yaml:"junk_patterns" mapstructure:"junk_patterns"`
+	// Mode is "trash" (move junk under TrashDir) or "remove" (delete it)
+	Mode string `yaml:"mode" mapstructure:"mode"`
+	// TrashDir receives trashed junk; empty means ~/.go-jf-org/trash
+	TrashDir string `yaml:"trash_dir" mapstructure:"trash_dir"`
+}
+
+// MarkerSettings contains where organize writes the .ignore files Jellyfin
+// skips folders by, for libraries that overlap the folders being organized.
+// Only markers go-jf-org wrote are ever removed.
+type MarkerSettings struct {
+	// IgnoreSources writes .ignore into each local source folder before
+	// organizing, except a source holding a destination
+	IgnoreSources bool `yaml:"ignore_sources" mapstructure:"ignore_sources"`
+	// IgnoreStaging writes .ignore into --stage directories; it is removed
+	// when they are merged or rolled back
+	IgnoreStaging bool `yaml:"ignore_staging" mapstructure:"ignore_staging"`
+	// RemoveWhenEmpty removes a source's .ignore once source cleanup
+	// leaves nothing else in it
+	RemoveWhenEmpty bool `yaml:"remove_when_empty" mapstructure:"remove_when_empty"`
+}
+
+// RemoteSettings contains how scan and organize reach sftp:// sources. The
+// password, when keys are not used, comes from GO_JF_ORG_SFTP_PASSWORD.
+type RemoteSettings struct {
+	// DownloadDir receives files organize downloads before placing them,
+	// under <host>/<remote path>; empty means ~/.go-jf-org/downloads
+	DownloadDir string `yaml:"download_dir" mapstructure:"download_dir"`
+	// IdentityFile is an unencrypted private key; empty tries ssh-agent and
+	// ~/.ssh/id_ed25519, id_ecdsa and id_rsa
+	IdentityFile string `yaml:"identity_file" mapstructure:"identity_file"`
+	// KnownHosts verifies the server's host key; empty means ~/.ssh/known_hosts
+	KnownHosts string `yaml:"known_hosts" mapstructure:"known_hosts"`
+	// InsecureIgnoreHostKey accepts any host key (not recommended)
+	InsecureIgnoreHostKey bool `yaml:"insecure_ignore_host_key" mapstructure:"insecure_ignore_host_key"`
+}
+
+// ArchiveSettings contains how organize handles RAR and 7z archive sets
+// (Movie.part01.rar, ...), which are never organized as media themselves
+type ArchiveSettings struct {
+	// Extract unpacks archive sets next to their volumes before organizing
+	// (organize --extract); otherwise they are reported as needing extraction
+	Extract bool `yaml:"extract" mapstructure:"extract"`
+	// ExtractCommand is the extractor and its arguments; "{archive}" is the
+	// first volume and "{dir}" the folder it is in
+	ExtractCommand []string `yaml:"extract_command" mapstructure:"extract_command"`
+}
+
+// EnrichSettings contains metadata enrichment settings
+type EnrichSettings struct {
+	// Region is the ISO 3166-1 country (e.g. "US", "GB") whose certification
+	// fills the NFO <mpaa> field and whose releases TMDB searches favor
+	Region string `yaml:"region" mapstructure:"region"`
+	// MinConfidence is the match confidence (0-1, from title similarity,
+	// year match and popularity) a TMDB result needs to be applied; 0
+	// applies every result
+	MinConfidence float64 `yaml:"min_confidence" mapstructure:"min_confidence"`
+	// PerItemTimeout bounds the lookups for one file (e.g. "60s"); a file
+	// whose enrichment runs longer keeps its parsed metadata. "0" waits
+	// for every lookup
+	PerItemTimeout string `yaml:"per_item_timeout" mapstructure:"per_item_timeout"`
+}
+
+// NotificationSettings contains where organize sends a summary when it
+// finishes; each target is off while its URL is empty
+type NotificationSettings struct {
+	// OnSuccess and OnFailure choose which runs notify; a run fails when it
+	// errors or any file fails to move
+	OnSuccess bool `yaml:"on_success" mapstructure:"on_success"`
+	OnFailure bool `yaml:"on_failure" mapstructure:"on_failure"`
+	// WebhookURL receives the summary as a JSON POST
+	WebhookURL        string `yaml:"webhook_url" mapstructure:"webhook_url"`
+	DiscordWebhookURL string `yaml:"discord_webhook_url" mapstructure:"discord_webhook_url"`
+	// NtfyURL is a topic URL such as https://ntfy.sh/my-topic; NtfyToken
+	// is its optional access token
+	NtfyURL   string `yaml:"ntfy_url" mapstructure:"ntfy_url"`
+	NtfyToken string `yaml:"ntfy_token" mapstructure:"ntfy_token"`
+}
+
+// ServerSettings contains where the serve command listens and the token
+// its clients must send; the API is open while Token is empty
+type ServerSettings struct {
+	Addr  string `yaml:"addr" mapstructure:"addr"`
+	Token string `yaml:"token" mapstructure:"token"`
 }
 
 // DefaultConfig returns the default configuration
@@ -94,20 +402,53 @@ func DefaultConfig() *Config {
 			MusicBrainzApp: "go-jf-org/1.0",
 		},
 		Organize: OrganizeSettings{
-			CreateNFO:           true,
-			DownloadArtwork:     true,
-			NormalizeNames:      true,
-			PreserveQualityTags: true,
+			CreateNFO:            true,
+			DownloadArtwork:      true,
+			NormalizeNames:       true,
+			PreserveQualityTags:  true,
+			MovieLayout:          "folder",
+			MiniSeriesLayout:     "season",
+			MusicLayout:          "artist",
+			DestStructure:        "nested",
+			LooseTrackLayout:     "unknown-album",
+			TrustedReleaseGroups: []string{},
+			ExtrasDirs: []string{
+				"extras", "trailers", "extrafanart", "behind the scenes", "deleted scenes",
+				"featurettes", "interviews", "scenes", "shorts", "clips", "other",
+				"backdrops", "theme-music",
+			},
+			ExtrasLayout:       "suffix",
+			SidecarNFOPolicy:   "merge",
+			CreateNFOFor:       []string{"movie", "tv", "music", "book"},
+			GroupByFirstLetter: []string{},
+		},
+		Naming: NamingSettings{
+			SortArticles:           false,
+			Articles:               []string{"The", "A", "An"},
+			SmallWords:             []string{"a", "an", "and", "as", "at", "but", "by", "for", "from", "in", "into", "nor", "of", "on", "or", "the", "to", "vs", "with"},
+			Acronyms:               []string{"USA", "UK", "FBI", "CIA", "NSA", "NASA", "NYC", "CSI", "NCIS", "SWAT", "UFO", "TV", "DC"},
+			ASCIIFold:              false,
+			ASCIIFoldUnmapped:      "keep",
+			UnknownYear:            "omit",
+			UnknownYearPlaceholder: "0000",
+			Separator:              "space-dash",
+			Case:                   "preserve",
 		},
 		Safety: SafetySettings{
-			DryRun:             false,
-			TransactionLog:     true,
-			LogDirectory:       filepath.Join(configDir, "logs"),
-			ConflictResolution: "skip",
-			BackupBeforeMove:   false,
+			DryRun:                   false,
+			TransactionLog:           true,
+			LogDirectory:             filepath.Join(configDir, "logs"),
+			ConflictResolution:       "skip",
+			BackupBeforeMove:         false,
+			CollisionLimit:           1000,
+			TransactionBatchSize:     1,
+			TransactionBatchInterval: "0",
 		},
 		Filters: FilterSettings{
-			MinFileSize: "10MB",
+			MinFileSize:   "10MB",
+			SampleMaxSize: "200MB",
+			SampleAction:  "warn",
+			Exclude:       []string{},
 			VideoExtensions: []string{
 				".mkv", ".mp4", ".avi", ".m4v", ".ts", ".webm",
 				".mov", ".wmv", ".flv", ".mpg", ".mpeg",
@@ -124,12 +465,55 @@ func DefaultConfig() *Config {
 			MaxConcurrentOps: 4,
 			APIRateLimit:     40,
 			CacheTTL:         "24h",
+			CopyBufferSize:   "4MB",
+			IOWorkers:        4,
+			NetWorkers:       4,
+			MaxBytesPerSec:   "0",
+			// StatsFile is empty: no incremental statistics by default
+			StatsFlushInterval: "30s",
+			StatsFlushEvery:    100,
+		},
+		Artwork: ArtworkSettings{
+			TMDBImageBase: "https://image.tmdb.org/t/p/",
+			ActorLimit:    10,
+			Format:        "jpeg",
+			Sources:       []string{"tmdb"},
+		},
+		Integrity: IntegritySettings{
+			Algorithm: "sha256",
+		},
+		Cleanup: CleanupSettings{
+			JunkPatterns: []string{"*.nfo", "*.txt", "*.jpg", "*.url", "*.sfv"},
+			Mode:         "trash",
+		},
+		Markers: MarkerSettings{
+			RemoveWhenEmpty: true,
+		},
+		Archives: ArchiveSettings{
+			ExtractCommand: []string{"7z", "x", "-aos", "-o{dir}", "{archive}"},
+		},
+		Enrich: EnrichSettings{
+			Region:         "US",
+			PerItemTimeout: "60s",
+		},
+		Notifications: NotificationSettings{
+			OnSuccess: true,
+			OnFailure: true,
+		},
+		Server: ServerSettings{
+			Addr: "127.0.0.1:8080",
 		},
 	}
 }
 
 // Load loads configuration from file and environment variables
 func Load(cfgFile string) (*Config, error) {
+	return LoadProfile(cfgFile, "")
+}
+
+// LoadProfile loads configuration like Load, then merges the named profile
+// from the "profiles" section over it. An empty name loads the base config.
+func LoadProfile(cfgFile, profile string) (*Config, error) {
 	// Set defaults
 	setDefaults()
 
@@ -166,6 +550,12 @@ func Load(cfgFile string) (*Config, error) {
 		}
 	}
 
+	if profile != "" {
+		if err := mergeProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+
 	// Unmarshal into Config struct
 	var cfg Config
 	if err := viper.Unmarshal(&cfg); err != nil {
@@ -174,6 +564,12 @@ func Load(cfgFile string) (*Config, error) {
 
 	// Apply defaults for empty slices (viper doesn't unmarshal defaults for slices properly)
 	defaults := DefaultConfig()
+	if cfg.Filters.SampleMaxSize == "" {
+		cfg.Filters.SampleMaxSize = defaults.Filters.SampleMaxSize
+	}
+	if cfg.Filters.SampleAction == "" {
+		cfg.Filters.SampleAction = defaults.Filters.SampleAction
+	}
 	if len(cfg.Filters.VideoExtensions) == 0 {
 		cfg.Filters.VideoExtensions = defaults.Filters.VideoExtensions
 	}
@@ -198,6 +594,57 @@ func Load(cfgFile string) (*Config, error) {
 	if cfg.APIKeys.MusicBrainzApp == "" {
 		cfg.APIKeys.MusicBrainzApp = defaults.APIKeys.MusicBrainzApp
 	}
+	if len(cfg.Naming.Articles) == 0 {
+		cfg.Naming.Articles = defaults.Naming.Articles
+	}
+	if len(cfg.Naming.SmallWords) == 0 {
+		cfg.Naming.SmallWords = defaults.Naming.SmallWords
+	}
+	if len(cfg.Naming.Acronyms) == 0 {
+		cfg.Naming.Acronyms = defaults.Naming.Acronyms
+	}
+	if cfg.Naming.ASCIIFoldUnmapped == "" {
+		cfg.Naming.ASCIIFoldUnmapped = defaults.Naming.ASCIIFoldUnmapped
+	}
+	if cfg.Naming.UnknownYear == "" {
+		cfg.Naming.UnknownYear = defaults.Naming.UnknownYear
+	}
+	if cfg.Naming.UnknownYearPlaceholder == "" {
+		cfg.Naming.UnknownYearPlaceholder = defaults.Naming.UnknownYearPlaceholder
+	}
+	if cfg.Naming.Separator == "" {
+		cfg.Naming.Separator = defaults.Naming.Separator
+	}
+	if cfg.Naming.Case == "" {
+		cfg.Naming.Case = defaults.Naming.Case
+	}
+	if cfg.Organize.MovieLayout == "" {
+		cfg.Organize.MovieLayout = defaults.Organize.MovieLayout
+	}
+	if cfg.Organize.MiniSeriesLayout == "" {
+		cfg.Organize.MiniSeriesLayout = defaults.Organize.MiniSeriesLayout
+	}
+	if cfg.Organize.MusicLayout == "" {
+		cfg.Organize.MusicLayout = defaults.Organize.MusicLayout
+	}
+	if cfg.Organize.DestStructure == "" {
+		cfg.Organize.DestStructure = defaults.Organize.DestStructure
+	}
+	if len(cfg.Organize.ExtrasDirs) == 0 {
+		cfg.Organize.ExtrasDirs = defaults.Organize.ExtrasDirs
+	}
+	if cfg.Organize.LooseTrackLayout == "" {
+		cfg.Organize.LooseTrackLayout = defaults.Organize.LooseTrackLayout
+	}
+	if cfg.Organize.SidecarNFOPolicy == "" {
+		cfg.Organize.SidecarNFOPolicy = defaults.Organize.SidecarNFOPolicy
+	}
+	if len(cfg.Organize.CreateNFOFor) == 0 {
+		cfg.Organize.CreateNFOFor = defaults.Organize.CreateNFOFor
+	}
+	if cfg.Safety.CollisionLimit <= 0 {
+		cfg.Safety.CollisionLimit = defaults.Safety.CollisionLimit
+	}
 	if cfg.Performance.CacheTTL == "" {
 		cfg.Performance.CacheTTL = defaults.Performance.CacheTTL
 	}
@@ -207,10 +654,80 @@ func Load(cfgFile string) (*Config, error) {
 	if cfg.Performance.APIRateLimit == 0 {
 		cfg.Performance.APIRateLimit = defaults.Performance.APIRateLimit
 	}
+	if cfg.Performance.CopyBufferSize == "" {
+		cfg.Performance.CopyBufferSize = defaults.Performance.CopyBufferSize
+	}
+	if cfg.Performance.IOWorkers <= 0 {
+		cfg.Performance.IOWorkers = defaults.Performance.IOWorkers
+	}
+	if cfg.Performance.NetWorkers <= 0 {
+		cfg.Performance.NetWorkers = defaults.Performance.NetWorkers
+	}
+	if cfg.Performance.MaxBytesPerSec == "" {
+		cfg.Performance.MaxBytesPerSec = defaults.Performance.MaxBytesPerSec
+	}
+	if cfg.Safety.TransactionBatchInterval == "" {
+		cfg.Safety.TransactionBatchInterval = defaults.Safety.TransactionBatchInterval
+	}
+	if cfg.Performance.StatsFlushInterval == "" {
+		cfg.Performance.StatsFlushInterval = defaults.Performance.StatsFlushInterval
+	}
+	if cfg.Artwork.TMDBImageBase == "" {
+		cfg.Artwork.TMDBImageBase = defaults.Artwork.TMDBImageBase
+	}
+	if cfg.Artwork.ActorLimit <= 0 {
+		cfg.Artwork.ActorLimit = defaults.Artwork.ActorLimit
+	}
+	if cfg.Artwork.Format == "" {
+		cfg.Artwork.Format = defaults.Artwork.Format
+	}
+	if len(cfg.Artwork.Sources) == 0 {
+		cfg.Artwork.Sources = defaults.Artwork.Sources
+	}
+	if cfg.Integrity.Algorithm == "" {
+		cfg.Integrity.Algorithm = defaults.Integrity.Algorithm
+	}
+	if cfg.Enrich.Region == "" {
+		cfg.Enrich.Region = defaults.Enrich.Region
+	}
+	if cfg.Enrich.PerItemTimeout == "" {
+		cfg.Enrich.PerItemTimeout = defaults.Enrich.PerItemTimeout
+	}
+	cfg.Enrich.Region = strings.ToUpper(cfg.Enrich.Region)
+	if !regionPattern.MatchString(cfg.Enrich.Region) {
+		return nil, fmt.Errorf("invalid enrich.region %q: expected an ISO 3166-1 alpha-2 code such as US or GB", cfg.Enrich.Region)
+	}
+	if cfg.Enrich.MinConfidence < 0 || cfg.Enrich.MinConfidence > 1 {
+		return nil, fmt.Errorf("invalid enrich.min_confidence %v: must be between 0.0 and 1.0", cfg.Enrich.MinConfidence)
+	}
 
 	return &cfg, nil
 }
 
+// mergeProfile merges the settings of profiles.<name> over the loaded config.
+// Nested sections merge key by key, so a profile setting only
+// organize.movie_layout keeps every other organize setting from the base.
+func mergeProfile(name string) error {
+	profiles := viper.GetStringMap("profiles")
+	overrides, ok := profiles[strings.ToLower(name)].(map[string]interface{})
+	if !ok {
+		known := make([]string, 0, len(profiles))
+		for k := range profiles {
+			known = append(known, k)
+		}
+		sort.Strings(known)
+		if len(known) == 0 {
+			return fmt.Errorf("%w %q: no profiles are defined", ErrUnknownProfile, name)
+		}
+		return fmt.Errorf("%w %q (available: %s)", ErrUnknownProfile, name, strings.Join(known, ", "))
+	}
+
+	if err := viper.MergeConfigMap(overrides); err != nil {
+		return fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+	return nil
+}
+
 // setDefaults sets default values for viper
 func setDefaults() {
 	defaults := DefaultConfig()
@@ -219,23 +736,102 @@ func setDefaults() {
 	viper.SetDefault("organize.download_artwork", defaults.Organize.DownloadArtwork)
 	viper.SetDefault("organize.normalize_names", defaults.Organize.NormalizeNames)
 	viper.SetDefault("organize.preserve_quality_tags", defaults.Organize.PreserveQualityTags)
+	viper.SetDefault("organize.movie_layout", defaults.Organize.MovieLayout)
+	viper.SetDefault("organize.miniseries_layout", defaults.Organize.MiniSeriesLayout)
+	viper.SetDefault("organize.music_layout", defaults.Organize.MusicLayout)
+	viper.SetDefault("organize.dest_structure", defaults.Organize.DestStructure)
+	viper.SetDefault("organize.loose_track_layout", defaults.Organize.LooseTrackLayout)
+	viper.SetDefault("organize.trusted_release_groups", defaults.Organize.TrustedReleaseGroups)
+	viper.SetDefault("organize.read_mediainfo", defaults.Organize.ReadMediaInfo)
+	viper.SetDefault("organize.prefer_folder_name", defaults.Organize.PreferFolderName)
+	viper.SetDefault("organize.probe_duration", defaults.Organize.ProbeDuration)
+	viper.SetDefault("organize.extras_dirs", defaults.Organize.ExtrasDirs)
+	viper.SetDefault("organize.sidecar_nfo_policy", defaults.Organize.SidecarNFOPolicy)
+	viper.SetDefault("organize.extras_layout", defaults.Organize.ExtrasLayout)
+	viper.SetDefault("organize.create_nfo_for", defaults.Organize.CreateNFOFor)
+	viper.SetDefault("organize.group_by_first_letter", defaults.Organize.GroupByFirstLetter)
+	viper.SetDefault("organize.after_hook", defaults.Organize.AfterHook)
+
+	viper.SetDefault("naming.sort_articles", defaults.Naming.SortArticles)
+	viper.SetDefault("naming.articles", defaults.Naming.Articles)
+	viper.SetDefault("naming.small_words", defaults.Naming.SmallWords)
+	viper.SetDefault("naming.acronyms", defaults.Naming.Acronyms)
+	viper.SetDefault("naming.episode_title_fallback", defaults.Naming.EpisodeTitleFallback)
+	viper.SetDefault("naming.ascii_fold", defaults.Naming.ASCIIFold)
+	viper.SetDefault("naming.ascii_fold_unmapped", defaults.Naming.ASCIIFoldUnmapped)
+	viper.SetDefault("naming.id_tokens", defaults.Naming.IDTokens)
+	viper.SetDefault("naming.unknown_year", defaults.Naming.UnknownYear)
+	viper.SetDefault("naming.unknown_year_placeholder", defaults.Naming.UnknownYearPlaceholder)
+	viper.SetDefault("naming.separator", defaults.Naming.Separator)
+	viper.SetDefault("naming.case", defaults.Naming.Case)
 
 	viper.SetDefault("safety.dry_run", defaults.Safety.DryRun)
 	viper.SetDefault("safety.transaction_log", defaults.Safety.TransactionLog)
 	viper.SetDefault("safety.log_directory", defaults.Safety.LogDirectory)
 	viper.SetDefault("safety.conflict_resolution", defaults.Safety.ConflictResolution)
 	viper.SetDefault("safety.backup_before_move", defaults.Safety.BackupBeforeMove)
+	viper.SetDefault("safety.collision_limit", defaults.Safety.CollisionLimit)
+	viper.SetDefault("safety.collision_hash_fallback", defaults.Safety.CollisionHashFallback)
+	viper.SetDefault("safety.dest_must_exist", defaults.Safety.DestMustExist)
+	viper.SetDefault("safety.strict_validation", defaults.Safety.StrictValidation)
+	viper.SetDefault("safety.transaction_batch_size", defaults.Safety.TransactionBatchSize)
+	viper.SetDefault("safety.transaction_batch_interval", defaults.Safety.TransactionBatchInterval)
 
 	viper.SetDefault("filters.min_file_size", defaults.Filters.MinFileSize)
+	viper.SetDefault("filters.sample_max_size", defaults.Filters.SampleMaxSize)
+	viper.SetDefault("filters.sample_action", defaults.Filters.SampleAction)
 	viper.SetDefault("filters.video_extensions", defaults.Filters.VideoExtensions)
 	viper.SetDefault("filters.audio_extensions", defaults.Filters.AudioExtensions)
 	viper.SetDefault("filters.book_extensions", defaults.Filters.BookExtensions)
+	viper.SetDefault("filters.exclude", defaults.Filters.Exclude)
 
 	viper.SetDefault("performance.max_concurrent_operations", defaults.Performance.MaxConcurrentOps)
 	viper.SetDefault("performance.api_rate_limit", defaults.Performance.APIRateLimit)
 	viper.SetDefault("performance.cache_ttl", defaults.Performance.CacheTTL)
+	viper.SetDefault("performance.copy_buffer_size", defaults.Performance.CopyBufferSize)
+	viper.SetDefault("performance.io_workers", defaults.Performance.IOWorkers)
+	viper.SetDefault("performance.net_workers", defaults.Performance.NetWorkers)
+	viper.SetDefault("performance.max_ops_per_sec", defaults.Performance.MaxOpsPerSec)
+	viper.SetDefault("performance.max_bytes_per_sec", defaults.Performance.MaxBytesPerSec)
+	viper.SetDefault("performance.stats_file", defaults.Performance.StatsFile)
+	viper.SetDefault("performance.stats_flush_interval", defaults.Performance.StatsFlushInterval)
+	viper.SetDefault("performance.stats_flush_every", defaults.Performance.StatsFlushEvery)
 
 	viper.SetDefault("api_keys.musicbrainz_app", defaults.APIKeys.MusicBrainzApp)
+
+	viper.SetDefault("artwork.tmdb_image_base", defaults.Artwork.TMDBImageBase)
+	viper.SetDefault("artwork.generate_thumbnails", defaults.Artwork.GenerateThumbnails)
+	viper.SetDefault("artwork.actor_thumbs", defaults.Artwork.ActorThumbs)
+	viper.SetDefault("artwork.actor_images", defaults.Artwork.ActorImages)
+	viper.SetDefault("artwork.actor_limit", defaults.Artwork.ActorLimit)
+	viper.SetDefault("artwork.format", defaults.Artwork.Format)
+	viper.SetDefault("artwork.sources", defaults.Artwork.Sources)
+	viper.SetDefault("nfo.record_original_filename", defaults.NFO.RecordOriginalFilename)
+	viper.SetDefault("integrity.algorithm", defaults.Integrity.Algorithm)
+	viper.SetDefault("cleanup.after_organize", defaults.Cleanup.AfterOrganize)
+	viper.SetDefault("cleanup.junk_patterns", defaults.Cleanup.JunkPatterns)
+	viper.SetDefault("cleanup.mode", defaults.Cleanup.Mode)
+	viper.SetDefault("cleanup.trash_dir", defaults.Cleanup.TrashDir)
+	viper.SetDefault("markers.ignore_sources", defaults.Markers.IgnoreSources)
+	viper.SetDefault("markers.ignore_staging", defaults.Markers.IgnoreStaging)
+	viper.SetDefault("markers.remove_when_empty", defaults.Markers.RemoveWhenEmpty)
+	viper.SetDefault("archives.extract", defaults.Archives.Extract)
+	viper.SetDefault("archives.extract_command", defaults.Archives.ExtractCommand)
+	viper.SetDefault("remote.download_dir", defaults.Remote.DownloadDir)
+	viper.SetDefault("remote.identity_file", defaults.Remote.IdentityFile)
+	viper.SetDefault("remote.known_hosts", defaults.Remote.KnownHosts)
+	viper.SetDefault("remote.insecure_ignore_host_key", defaults.Remote.InsecureIgnoreHostKey)
+	viper.SetDefault("enrich.region", defaults.Enrich.Region)
+	viper.SetDefault("enrich.min_confidence", defaults.Enrich.MinConfidence)
+	viper.SetDefault("enrich.per_item_timeout", defaults.Enrich.PerItemTimeout)
+	viper.SetDefault("notifications.on_success", defaults.Notifications.OnSuccess)
+	viper.SetDefault("notifications.on_failure", defaults.Notifications.OnFailure)
+	viper.SetDefault("notifications.webhook_url", defaults.Notifications.WebhookURL)
+	viper.SetDefault("notifications.discord_webhook_url", defaults.Notifications.DiscordWebhookURL)
+	viper.SetDefault("notifications.ntfy_url", defaults.Notifications.NtfyURL)
+	viper.SetDefault("notifications.ntfy_token", defaults.Notifications.NtfyToken)
+	viper.SetDefault("server.addr", defaults.Server.Addr)
+	viper.SetDefault("server.token", defaults.Server.Token)
 }
 
 // ParseSize converts a size string (e.g., "10MB", "1GB") to bytes
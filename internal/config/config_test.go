@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -115,6 +117,117 @@ safety:
 	}
 }
 
+func TestLoadProfile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := []byte(`
+sources:
+  - /test/source
+
+destinations:
+  movies: /test/movies
+  tv: /test/tv
+
+organize:
+  create_nfo: false
+  movie_layout: flat
+
+naming:
+  sort_articles: true
+
+profiles:
+  anime:
+    destinations:
+      tv: /test/anime
+    naming:
+      id_tokens: true
+  kids:
+    organize:
+      movie_layout: folder
+    genres:
+      allowlist: [Animation, Family]
+`)
+	if err := os.WriteFile(configPath, configContent, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("profile overrides apply", func(t *testing.T) {
+		cfg, err := LoadProfile(configPath, "anime")
+		if err != nil {
+			t.Fatalf("LoadProfile failed: %v", err)
+		}
+		if cfg.Destinations.TV != "/test/anime" {
+			t.Errorf("Destinations.TV = %q, want /test/anime", cfg.Destinations.TV)
+		}
+		if !cfg.Naming.IDTokens {
+			t.Error("Naming.IDTokens should be enabled by the profile")
+		}
+	})
+
+	t.Run("unspecified fields inherit the base", func(t *testing.T) {
+		cfg, err := LoadProfile(configPath, "anime")
+		if err != nil {
+			t.Fatalf("LoadProfile failed: %v", err)
+		}
+		if cfg.Destinations.Movies != "/test/movies" {
+			t.Errorf("Destinations.Movies = %q, want /test/movies", cfg.Destinations.Movies)
+		}
+		if !cfg.Naming.SortArticles {
+			t.Error("Naming.SortArticles should be inherited from the base")
+		}
+		if cfg.Organize.CreateNFO || cfg.Organize.MovieLayout != "flat" {
+			t.Errorf("Organize = %+v, want base settings", cfg.Organize)
+		}
+		if len(cfg.Sources) != 1 || cfg.Sources[0] != "/test/source" {
+			t.Errorf("Sources = %v, want [/test/source]", cfg.Sources)
+		}
+		if len(cfg.Filters.VideoExtensions) == 0 {
+			t.Error("Default video extensions should still be applied")
+		}
+	})
+
+	t.Run("profile name is case-insensitive", func(t *testing.T) {
+		cfg, err := LoadProfile(configPath, "Kids")
+		if err != nil {
+			t.Fatalf("LoadProfile failed: %v", err)
+		}
+		if cfg.Organize.MovieLayout != "folder" {
+			t.Errorf("Organize.MovieLayout = %q, want folder", cfg.Organize.MovieLayout)
+		}
+		if cfg.Organize.CreateNFO {
+			t.Error("Organize.CreateNFO should be inherited from the base")
+		}
+		if len(cfg.Genres.Allowlist) != 2 {
+			t.Errorf("Genres.Allowlist = %v, want 2 entries", cfg.Genres.Allowlist)
+		}
+		if cfg.Destinations.TV != "/test/tv" {
+			t.Errorf("Destinations.TV = %q, another profile leaked in", cfg.Destinations.TV)
+		}
+	})
+
+	t.Run("no profile loads the base", func(t *testing.T) {
+		cfg, err := LoadProfile(configPath, "")
+		if err != nil {
+			t.Fatalf("LoadProfile failed: %v", err)
+		}
+		if cfg.Destinations.TV != "/test/tv" || cfg.Naming.IDTokens {
+			t.Errorf("base config was modified: tv=%q id_tokens=%v", cfg.Destinations.TV, cfg.Naming.IDTokens)
+		}
+		if len(cfg.Profiles) != 2 {
+			t.Errorf("Profiles = %v, want 2 entries", cfg.Profiles)
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := LoadProfile(configPath, "missing")
+		if !errors.Is(err, ErrUnknownProfile) {
+			t.Fatalf("LoadProfile error = %v, want ErrUnknownProfile", err)
+		}
+		if !strings.Contains(err.Error(), "anime, kids") {
+			t.Errorf("error %q should list the available profiles", err)
+		}
+	})
+}
+
 func TestLoad_InvalidYAML(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "config-test-*")
 	if err != nil {
@@ -138,6 +251,63 @@ this is not: valid: yaml: content
 	}
 }
 
+func TestLoad_EnrichRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"default", "sources: []\n", "US", false},
+		{"lowercase normalized", "enrich:\n  region: gb\n", "GB", false},
+		{"invalid", "enrich:\n  region: England\n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.Enrich.Region != tt.want {
+				t.Errorf("Enrich.Region = %q, want %q", cfg.Enrich.Region, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_EnrichMinConfidence(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    float64
+		wantErr bool
+	}{
+		{"default", "sources: []\n", 0, false},
+		{"set", "enrich:\n  min_confidence: 0.75\n", 0.75, false},
+		{"above one", "enrich:\n  min_confidence: 1.5\n", 0, true},
+		{"negative", "enrich:\n  min_confidence: -0.1\n", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.Enrich.MinConfidence != tt.want {
+				t.Errorf("Enrich.MinConfidence = %v, want %v", cfg.Enrich.MinConfidence, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseSize(t *testing.T) {
 	tests := []struct {
 		name     string
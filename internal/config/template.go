@@ -0,0 +1,308 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/template"
+)
+
+// configTemplate renders a Config as a fully commented config.yaml. Every
+// field of Config must appear here; TestWriteTemplate_CoversAllFields fails
+// when one is added without being documented.
+const configTemplate = `# go-jf-org Configuration File
+# Generated by 'go-jf-org init'; every setting is shown with its default value.
+# See docs/ for detailed documentation.
+
+# Source directories to scan for media files, e.g.
+#   sources:
+#     - /media/unsorted
+{{- if .Sources}}
+sources:
+{{- range .Sources}}
+  - {{q .}}
+{{- end}}
+{{- else}}
+sources: []
+{{- end}}
+
+# Destination directories for organized media
+destinations:
+  movies: {{q .Destinations.Movies}}
+  tv: {{q .Destinations.TV}}
+  music: {{q .Destinations.Music}}
+  books: {{q .Destinations.Books}}
+
+# API keys for external metadata services
+# TMDB is optional but recommended for better movie/TV metadata
+api_keys:
+  tmdb: {{q .APIKeys.TMDB}}  # Get a free API key at https://www.themoviedb.org/settings/api
+  musicbrainz_app: {{q .APIKeys.MusicBrainzApp}}  # User agent for MusicBrainz requests
+  lastfm: {{q .APIKeys.LastFM}}  # Optional, for music metadata
+  google_books_api: {{q .APIKeys.GoogleBooksAPI}}  # Optional, for book metadata
+  fanart: {{q .APIKeys.Fanart}}  # Optional, for fanart.tv posters (artwork.sources)
+  omdb: {{q .APIKeys.OMDb}}  # Optional, for OMDb posters (artwork.sources)
+
+# Organization settings
+organize:
+  create_nfo: {{.Organize.CreateNFO}}  # Generate NFO files for Jellyfin
+  download_artwork: {{.Organize.DownloadArtwork}}  # Download posters, fanart, covers
+  normalize_names: {{.Organize.NormalizeNames}}  # Clean and standardize names; all-lowercase titles are title-cased (naming.small_words, naming.acronyms)
+  preserve_quality_tags: {{.Organize.PreserveQualityTags}}  # Keep quality info (1080p, 4K, etc.)
+  movie_layout: {{q .Organize.MovieLayout}}  # folder: Movie (Year)/Movie (Year).mkv, flat: Movie (Year).mkv
+  miniseries_layout: {{q .Organize.MiniSeriesLayout}}  # Episodes without a season (Show.E01, Part 2): season: Show/Season 01/, show: directly in Show/
+  music_layout: {{q .Organize.MusicLayout}}  # artist: Artist/Album (Year)/, decade-artist: 1970s/Artist/Album (Year)/
+  dest_structure: {{q .Organize.DestStructure}}  # nested: Jellyfin per-title folders, flat-by-type: TV/Show - S01E01 - Title.mkv (no subfolders)
+  loose_track_layout: {{q .Organize.LooseTrackLayout}}  # Tracks without an album: unknown-album, singles (Artist/Singles/) or compilations (Various Artists/Compilations/)
+  # Release groups to prefer among equal-quality duplicates, most trusted first
+{{- if .Organize.TrustedReleaseGroups}}
+  trusted_release_groups:
+{{- range .Organize.TrustedReleaseGroups}}
+    - {{q .}}
+{{- end}}
+{{- else}}
+  trusted_release_groups: []
+{{- end}}
+  read_mediainfo: {{.Organize.ReadMediaInfo}}  # Take resolution and codec from a companion Movie.mediainfo or Movie.txt report over the filename
+  prefer_folder_name: {{.Organize.PreferFolderName}}  # Take music artist and album from Artist/Album/ or "Artist - Album"/ folders over the filename
+  probe_duration: {{.Organize.ProbeDuration}}  # Settle videos that could be a movie or an episode by running time (up to 50 min: TV, 75+ min: movie)
+  # Movie subfolders Jellyfin treats as extras; verify accepts them and organize moves them with the movie
+  extras_dirs:
+{{- range .Organize.ExtrasDirs}}
+    - {{q .}}
+{{- end}}
+  extras_layout: {{q .Organize.ExtrasLayout}}  # Suffixed extras (Movie-trailer.mkv): suffix (Movie (2020)-trailer.mkv beside the movie) or folder (trailers/)
+  sidecar_nfo_policy: {{q .Organize.SidecarNFOPolicy}}  # With --copy-sidecar-nfo: merge (keep its fields, add generated ones it lacks) or replace (use it as is)
+  # Media types that get NFOs when create_nfo is on (e.g. drop music to let Jellyfin read its tags)
+  create_nfo_for:
+{{- range .Organize.CreateNFOFor}}
+    - {{q .}}
+{{- end}}
+{{- if .Organize.GroupByFirstLetter}}
+  # Media types whose folders go in first-letter buckets (Movies/M/The Matrix (1999)/, # for non-letters)
+  group_by_first_letter:
+{{- range .Organize.GroupByFirstLetter}}
+    - {{q .}}
+{{- end}}
+{{- else}}
+  group_by_first_letter: []  # Media types whose folders go in first-letter buckets (Movies/M/The Matrix (1999)/, # for non-letters)
+{{- end}}
+  after_hook: {{q .Organize.AfterHook}}  # Shell command run after an organize with no failures, with GO_JF_ORG_* variables describing the run (or --after-hook)
+
+# Folder naming settings
+naming:
+  sort_articles: {{.Naming.SortArticles}}  # File "The Matrix" under "Matrix, The (1999)/" (filenames unchanged)
+  # Leading articles to move; add others per language (e.g. Der, Le, L')
+{{- if .Naming.Articles}}
+  articles:
+{{- range .Naming.Articles}}
+    - {{q .}}
+{{- end}}
+{{- else}}
+  articles: []
+{{- end}}
+  # With organize.normalize_names, all-lowercase movie and show titles are title-cased:
+  # small_words are kept lowercase unless first or last, acronyms written in capitals
+  small_words:
+{{- range .Naming.SmallWords}}
+    - {{q .}}
+{{- end}}
+  acronyms:
+{{- range .Naming.Acronyms}}
+    - {{q .}}
+{{- end}}
+  episode_title_fallback: {{q .Naming.EpisodeTitleFallback}}  # Placeholder when an episode has no title, e.g. "Episode {episode}"
+  ascii_fold: {{.Naming.ASCIIFold}}  # Transliterate names to ASCII ("Amélie" -> "Amelie"); NFO titles stay Unicode
+  ascii_fold_unmapped: {{q .Naming.ASCIIFoldUnmapped}}  # keep | strip characters with no ASCII equivalent (e.g. CJK) when folding
+  id_tokens: {{.Naming.IDTokens}}  # Append "[tmdbid-603]" to movie and show names when the ID is known
+  unknown_year: {{q .Naming.UnknownYear}}  # omit | placeholder | quarantine (leave in place) movies, albums and books with no year
+  unknown_year_placeholder: {{q .Naming.UnknownYearPlaceholder}}  # Year written by the placeholder policy, e.g. "0000" or "Unknown"
+  separator: {{q .Naming.Separator}}  # space-dash | dot | underscore between words and parts of filenames (folders unchanged)
+  case: {{q .Naming.Case}}  # preserve | title | lower case for filenames
+
+# Safety settings
+safety:
+  dry_run: {{.Safety.DryRun}}  # Preview mode - don't actually move files
+  transaction_log: {{.Safety.TransactionLog}}  # Log all operations for rollback
+  log_directory: {{q .Safety.LogDirectory}}  # Where to store transaction logs
+  conflict_resolution: {{q .Safety.ConflictResolution}}  # Options: skip, rename, interactive
+  backup_before_move: {{.Safety.BackupBeforeMove}}  # Copy each file into log_directory/backups before moving it (needs room for a second copy)
+  collision_limit: {{.Safety.CollisionLimit}}  # Numeric suffixes (-1, -2, ...) tried when renaming on conflict
+  collision_hash_fallback: {{.Safety.CollisionHashFallback}}  # When those run out, append a short source hash instead of failing
+  dest_must_exist: {{.Safety.DestMustExist}}  # Refuse destination roots that don't exist yet, confirm empty ones (catches typos)
+  strict_validation: {{.Safety.StrictValidation}}  # Abort the whole run if any planned file fails validation instead of skipping it
+  transaction_batch_size: {{.Safety.TransactionBatchSize}}  # Write the transaction log every N operation updates (1 = every update); faster for big runs, a crash loses at most one batch
+  transaction_batch_interval: {{q .Safety.TransactionBatchInterval}}  # Also write it once this long has passed since the last write, e.g. 500ms (0 = off)
+
+# File filters
+filters:
+  min_file_size: {{q .Filters.MinFileSize}}  # Ignore files smaller than this
+  sample_max_size: {{q .Filters.SampleMaxSize}}  # 1080p videos below this are suspected samples (scaled by resolution, 0 disables)
+  sample_action: {{q .Filters.SampleAction}}  # What to do with suspected samples: warn or skip
+  # Supported video file extensions
+  video_extensions:
+{{- range .Filters.VideoExtensions}}
+    - {{q .}}
+{{- end}}
+  # Supported audio file extensions
+  audio_extensions:
+{{- range .Filters.AudioExtensions}}
+    - {{q .}}
+{{- end}}
+  # Supported book file extensions
+  book_extensions:
+{{- range .Filters.BookExtensions}}
+    - {{q .}}
+{{- end}}
+  # Gitignore-style patterns to skip (a .jf-ignore in the source root is applied after these)
+{{- if .Filters.Exclude}}
+  exclude:
+{{- range .Filters.Exclude}}
+    - {{q .}}
+{{- end}}
+{{- else}}
+  exclude: []
+{{- end}}
+
+# Performance settings
+performance:
+  max_concurrent_operations: {{.Performance.MaxConcurrentOps}}  # Max parallel file operations
+  api_rate_limit: {{.Performance.APIRateLimit}}  # API requests per 10 seconds (TMDB limit)
+  cache_ttl: {{q .Performance.CacheTTL}}  # How long to cache API responses
+  copy_buffer_size: {{q .Performance.CopyBufferSize}}  # Buffer for copying files across filesystems (memory use stays bounded)
+  io_workers: {{.Performance.IOWorkers}}  # Concurrent file system operations (raise for network shares)
+  net_workers: {{.Performance.NetWorkers}}  # Concurrent API/artwork requests (keep low to respect rate limits)
+  max_ops_per_sec: {{.Performance.MaxOpsPerSec}}  # Max file moves per second (0 = unlimited; throttle for slow NAS disks)
+  max_bytes_per_sec: {{q .Performance.MaxBytesPerSec}}  # Max copy rate across filesystems, e.g. 20MB (0 = unlimited)
+  stats_file: {{q .Performance.StatsFile}}  # Write partial run statistics here during organize/scan (or --stats-file)
+  stats_flush_interval: {{q .Performance.StatsFlushInterval}}  # How often to rewrite stats_file (0 = only on stats_flush_every)
+  stats_flush_every: {{.Performance.StatsFlushEvery}}  # Also rewrite it after this many counter updates (0 = only on the interval)
+
+# Genre normalization (applied to movie/TV genres before NFO generation)
+genres:
+  # Collapse provider synonyms to a canonical name, e.g. "Sci-Fi": "Science Fiction"
+{{- if .Genres.Mapping}}
+  mapping:
+{{- range $from := sortedKeys .Genres.Mapping}}
+    {{q $from}}: {{q (index $.Genres.Mapping $from)}}
+{{- end}}
+{{- else}}
+  mapping: {}
+{{- end}}
+  # If non-empty, drop genres not in this list
+{{- if .Genres.Allowlist}}
+  allowlist:
+{{- range .Genres.Allowlist}}
+    - {{q .}}
+{{- end}}
+{{- else}}
+  allowlist: []
+{{- end}}
+
+# Artwork settings
+artwork:
+  tmdb_image_base: {{q .Artwork.TMDBImageBase}}  # Override to use a TMDB image mirror or proxy
+  generate_thumbnails: {{.Artwork.GenerateThumbnails}}  # Also download a small poster-thumb.jpg next to each poster
+  actor_thumbs: {{.Artwork.ActorThumbs}}  # Fetch TMDB credits (one extra request per title) and add actors with <thumb> URLs to NFOs
+  actor_images: {{.Artwork.ActorImages}}  # Also download actor images into a .actors/ folder (Kodi style); needs --download-artwork
+  actor_limit: {{.Artwork.ActorLimit}}  # Number of billed actors to keep
+  format: {{q .Artwork.Format}}  # jpeg converts PNG/GIF artwork so names stay poster.jpg; original keeps the served format (poster.png, poster.webp)
+  # Movie poster sources tried in order until one has a poster: tmdb, fanart (api_keys.fanart), omdb (api_keys.omdb)
+  sources:
+{{- range .Artwork.Sources}}
+    - {{q .}}
+{{- end}}
+
+# Generated NFO contents
+nfo:
+  record_original_filename: {{.NFO.RecordOriginalFilename}}  # Add <original_filename> with the pre-organize file name to movie and book NFOs
+
+# Hashes recorded by organize --hash and checked by transactions verify
+integrity:
+  algorithm: {{q .Integrity.Algorithm}}  # sha256, blake3 (faster on large videos) or crc32 (cheap change detection)
+
+# Tidying of the source folders organize moved files out of, after a run with no failures
+cleanup:
+  after_organize: {{.Cleanup.AfterOrganize}}  # Clean after every organize (or per run with --clean-sources)
+  # Leftover files to clean from those folders (globs, case-insensitive); other files are never touched
+  junk_patterns:
+{{- range .Cleanup.JunkPatterns}}
+    - {{q .}}
+{{- end}}
+  mode: {{q .Cleanup.Mode}}  # trash (move under trash_dir, keeping the full path) or remove (delete)
+  trash_dir: {{q .Cleanup.TrashDir}}  # Empty means ~/.go-jf-org/trash
+
+# Jellyfin .ignore files, so a library overlapping the sources skips files not organized yet
+markers:
+  ignore_sources: {{.Markers.IgnoreSources}}  # Write .ignore into each local source folder (or per run with --ignore-markers); skipped for a source holding a destination
+  ignore_staging: {{.Markers.IgnoreStaging}}  # Write .ignore into --stage directories; removed on merge-staging or rollback
+  remove_when_empty: {{.Markers.RemoveWhenEmpty}}  # Remove a source's .ignore once --clean-sources leaves it empty; user-written .ignore files are never removed
+
+# RAR and 7z releases (Movie.part01.rar ...); their volumes are never organized as media
+archives:
+  extract: {{.Archives.Extract}}  # Extract them before organizing (or per run with --extract); otherwise they are reported
+  # Extractor and arguments, run without a shell: {archive} is the first volume, {dir} its folder
+  extract_command:
+{{- range .Archives.ExtractCommand}}
+    - {{q .}}
+{{- end}}
+
+# sftp://user@host/path sources for scan and organize; the password, if keys are not used, comes from GO_JF_ORG_SFTP_PASSWORD
+remote:
+  download_dir: {{q .Remote.DownloadDir}}  # Where organize downloads remote files before placing them; empty means ~/.go-jf-org/downloads
+  identity_file: {{q .Remote.IdentityFile}}  # Unencrypted private key; empty tries ssh-agent, then ~/.ssh/id_ed25519, id_ecdsa and id_rsa
+  known_hosts: {{q .Remote.KnownHosts}}  # Empty means ~/.ssh/known_hosts; the server must be listed
+  insecure_ignore_host_key: {{.Remote.InsecureIgnoreHostKey}}  # Accept any host key (not recommended)
+
+# Metadata enrichment settings
+enrich:
+  region: {{q .Enrich.Region}}  # ISO 3166-1 country for certifications (<mpaa>) and TMDB search, e.g. US, GB, DE
+  min_confidence: {{.Enrich.MinConfidence}}  # Keep parsed metadata when a TMDB match scores below this (0.0-1.0, 0 = accept all)
+  per_item_timeout: {{q .Enrich.PerItemTimeout}}  # Give up on a file's lookups after this long and keep its parsed metadata (0 = wait)
+
+# Summaries sent when organize finishes (each target is off while its URL is empty)
+notifications:
+  on_success: {{.Notifications.OnSuccess}}  # Notify after a clean run
+  on_failure: {{.Notifications.OnFailure}}  # Notify when the run errors or any file fails, with the transaction ID for rollback
+  webhook_url: {{q .Notifications.WebhookURL}}  # POST the summary as JSON
+  discord_webhook_url: {{q .Notifications.DiscordWebhookURL}}  # Discord channel webhook
+  ntfy_url: {{q .Notifications.NtfyURL}}  # ntfy topic, e.g. https://ntfy.sh/my-media-topic
+  ntfy_token: {{q .Notifications.NtfyToken}}  # ntfy access token for protected topics
+
+# HTTP API started by 'go-jf-org serve'
+server:
+  addr: {{q .Server.Addr}}  # Listen address; keep it on localhost unless a token is set
+  token: {{q .Server.Token}}  # Clients send "Authorization: Bearer <token>"; empty leaves the API open
+
+# Named overrides selected with --profile; each is merged over the settings
+# above and inherits anything it leaves out, e.g.
+#   profiles:
+#     kids:
+#       destinations:
+#         movies: /media/kids/movies
+#       genres:
+#         allowlist: [Animation, Family]
+profiles: {}
+`
+
+var configTmpl = template.Must(template.New("config").Funcs(template.FuncMap{
+	"q": strconv.Quote,
+	"sortedKeys": func(m map[string]string) []string {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	},
+}).Parse(configTemplate))
+
+// WriteTemplate writes cfg as a commented YAML config file that Load can
+// read back. Pass DefaultConfig() to produce a starting config for new users.
+func WriteTemplate(w io.Writer, cfg *Config) error {
+	if err := configTmpl.Execute(w, cfg); err != nil {
+		return fmt.Errorf("failed to render config template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestWriteTemplate_RoundTrip(t *testing.T) {
+	defaults := DefaultConfig()
+
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, defaults); err != nil {
+		t.Fatalf("WriteTemplate() error = %v", err)
+	}
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load() of generated config error = %v", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"destinations", cfg.Destinations, defaults.Destinations},
+		{"api_keys", cfg.APIKeys, defaults.APIKeys},
+		{"organize", cfg.Organize, defaults.Organize},
+		{"naming", cfg.Naming, defaults.Naming},
+		{"safety", cfg.Safety, defaults.Safety},
+		{"filters", cfg.Filters, defaults.Filters},
+		{"performance", cfg.Performance, defaults.Performance},
+		{"artwork", cfg.Artwork, defaults.Artwork},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %+v, want %+v", c.name, c.got, c.want)
+		}
+	}
+	if len(cfg.Sources) != 0 || len(cfg.Genres.Mapping) != 0 || len(cfg.Genres.Allowlist) != 0 {
+		t.Errorf("empty defaults did not round-trip: sources=%v genres=%+v", cfg.Sources, cfg.Genres)
+	}
+}
+
+func TestWriteTemplate_Values(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Sources = []string{"/media/unsorted", "/downloads/it's here"}
+	cfg.Genres.Mapping = map[string]string{"Sci-Fi": "Science Fiction"}
+
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, cfg); err != nil {
+		t.Fatalf("WriteTemplate() error = %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(&buf); err != nil {
+		t.Fatalf("generated config is not valid YAML: %v", err)
+	}
+	if got := v.GetStringSlice("sources"); !reflect.DeepEqual(got, cfg.Sources) {
+		t.Errorf("sources = %v, want %v", got, cfg.Sources)
+	}
+	if got := v.GetStringMapString("genres.mapping")["sci-fi"]; got != "Science Fiction" {
+		t.Errorf("genres.mapping[sci-fi] = %q, want %q", got, "Science Fiction")
+	}
+}
+
+func TestWriteTemplate_CoversAllFields(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, DefaultConfig()); err != nil {
+		t.Fatalf("WriteTemplate() error = %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(&buf); err != nil {
+		t.Fatalf("generated config is not valid YAML: %v", err)
+	}
+
+	for _, key := range yamlKeys(reflect.TypeOf(Config{}), "") {
+		if !v.InConfig(key) {
+			t.Errorf("generated config is missing %q", key)
+		}
+	}
+}
+
+// yamlKeys returns the dotted yaml key of every leaf field in a config struct
+func yamlKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, yamlKeys(field.Type, key+".")...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
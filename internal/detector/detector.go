@@ -1,8 +1,12 @@
 package detector
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
 
 	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/pkg/types"
@@ -12,12 +16,32 @@ import (
 type Detector interface {
 	// Detect determines the media type based on the filename
 	Detect(filename string) types.MediaType
+	// DetectWithConfidence determines the media type and also returns a
+	// confidence between 0 and 1 and the heuristics that led to it
+	DetectWithConfidence(filename string) (types.MediaType, float64, []string)
 }
 
+// DurationProbe reads a video file's running time
+type DurationProbe func(path string) (time.Duration, error)
+
+const (
+	// AmbiguousConfidence is the score below which a video's type is
+	// checked against its running time, when a DurationProbe is set
+	AmbiguousConfidence = 0.75
+	// MaxEpisodeDuration is the longest running time taken as a TV episode
+	MaxEpisodeDuration = 50 * time.Minute
+	// MinMovieDuration is the shortest running time taken as a movie
+	MinMovieDuration = 75 * time.Minute
+	// minProbedDuration is the shortest running time that says anything;
+	// shorter clips are samples, trailers or shorts
+	minProbedDuration = 10 * time.Minute
+)
+
 // detector is the main implementation of Detector
 type detector struct {
 	movieDetector MovieDetector
 	tvDetector    TVDetector
+	probe         DurationProbe
 }
 
 // New creates a new Detector instance
@@ -28,8 +52,29 @@ func New() Detector {
 	}
 }
 
+// NewWithDurationProbe creates a Detector that settles ambiguous videos by
+// their running time: a filename scoring below AmbiguousConfidence is a TV
+// episode when the file runs up to MaxEpisodeDuration and a movie from
+// MinMovieDuration. Only ambiguous files are probed, so the filename still
+// decides for everything else. Detect must be given the file's full path.
+func NewWithDurationProbe(probe DurationProbe) Detector {
+	return &detector{
+		movieDetector: NewMovieDetector(),
+		tvDetector:    NewTVDetector(),
+		probe:         probe,
+	}
+}
+
 // Detect determines the media type based on filename patterns
 func (d *detector) Detect(filename string) types.MediaType {
+	mediaType, _, _ := d.DetectWithConfidence(filename)
+	return mediaType
+}
+
+// DetectWithConfidence determines the media type based on filename patterns
+// and reports how certain the match is. Low scores mark ambiguous files,
+// e.g. "Something.2020.mkv", which may be a movie or a dated TV episode.
+func (d *detector) DetectWithConfidence(filename string) (types.MediaType, float64, []string) {
 	// Get the base filename without path
 	base := filepath.Base(filename)
 	ext := strings.ToLower(filepath.Ext(base))
@@ -39,30 +84,71 @@ func (d *detector) Detect(filename string) types.MediaType {
 
 	// Check if it's a video file
 	if isVideoExtension(ext) {
-		// Try TV detector first (more specific patterns)
-		if d.tvDetector.IsTV(base) {
-			return types.MediaTypeTV
-		}
-		// Try movie detector
-		if d.movieDetector.IsMovie(base) {
-			return types.MediaTypeMovie
+		mediaType, confidence, reasons := d.detectVideo(base)
+		if d.probe != nil && confidence < AmbiguousConfidence {
+			return d.applyDuration(filename, mediaType, confidence, reasons)
 		}
-		// If no specific pattern matched, default to movie
-		// (most single video files are movies)
-		return types.MediaTypeMovie
+		return mediaType, confidence, reasons
 	}
 
 	// Audio files are music
 	if isAudioExtension(ext) {
-		return types.MediaTypeMusic
+		return types.MediaTypeMusic, 0.9, []string{"audio extension " + ext}
 	}
 
 	// Book extensions
 	if isBookExtension(ext) {
-		return types.MediaTypeBook
+		return types.MediaTypeBook, 0.9, []string{"book extension " + ext}
 	}
 
-	return types.MediaTypeUnknown
+	return types.MediaTypeUnknown, 0, []string{"unrecognized extension " + ext}
+}
+
+// detectVideo tells a movie from a TV episode by its filename
+func (d *detector) detectVideo(base string) (types.MediaType, float64, []string) {
+	// Try TV detector first (more specific patterns)
+	if confidence, reasons := d.tvDetector.Score(base); confidence > 0 {
+		return types.MediaTypeTV, confidence, reasons
+	}
+	// Try movie detector
+	if confidence, reasons := d.movieDetector.Score(base); confidence > 0 {
+		return types.MediaTypeMovie, confidence, reasons
+	}
+	// If no specific pattern matched, default to movie
+	// (most single video files are movies)
+	return types.MediaTypeMovie, 0.3, []string{"video file without movie or TV markers; assuming movie"}
+}
+
+// applyDuration breaks the tie for an ambiguous video with its running
+// time. A duration that agrees with the filename raises the confidence; one
+// that disagrees overrides it. Unreadable files and running times between
+// the episode and movie ranges keep the filename's verdict.
+func (d *detector) applyDuration(path string, mediaType types.MediaType, confidence float64, reasons []string) (types.MediaType, float64, []string) {
+	duration, err := d.probe(path)
+	if err != nil {
+		log.Debug().Err(err).Str("file", path).Msg("Could not read video duration")
+		return mediaType, confidence, reasons
+	}
+
+	var fits types.MediaType
+	var kind string
+	switch {
+	case duration < minProbedDuration:
+		return mediaType, confidence, reasons
+	case duration <= MaxEpisodeDuration:
+		fits, kind = types.MediaTypeTV, "TV episode"
+	case duration >= MinMovieDuration:
+		fits, kind = types.MediaTypeMovie, "movie"
+	default:
+		return mediaType, confidence, reasons
+	}
+
+	reason := fmt.Sprintf("running time %s fits a %s", duration.Round(time.Minute), kind)
+	if fits == mediaType {
+		return mediaType, max(confidence, 0.8), append(reasons, reason)
+	}
+	log.Debug().Str("file", path).Str("from", string(mediaType)).Str("to", string(fits)).Dur("duration", duration).Msg("Running time overrides ambiguous filename")
+	return fits, 0.7, append(reasons, reason)
 }
 
 // Video extensions
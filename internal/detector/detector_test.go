@@ -1,7 +1,10 @@
 package detector
 
 import (
+	"errors"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
@@ -232,11 +235,41 @@ func TestTVDetector_IsTV(t *testing.T) {
 			filename: "THE.SHOW.S02E15.mkv",
 			want:     true,
 		},
+		{
+			name:     "daily show dotted air date",
+			filename: "The.Daily.Show.2023.05.15.720p.mkv",
+			want:     true,
+		},
+		{
+			name:     "daily show dashed air date",
+			filename: "Late Night - 2021-11-03.mp4",
+			want:     true,
+		},
+		{
+			name:     "invalid date is not an air date",
+			filename: "Movie.2023.13.45.mkv",
+			want:     false,
+		},
 		{
 			name:     "movie with year should not match",
 			filename: "Movie.2023.mkv",
 			want:     false,
 		},
+		{
+			name:     "mini-series episode without season",
+			filename: "Chernobyl.E01.1080p.mkv",
+			want:     true,
+		},
+		{
+			name:     "mini-series part without season",
+			filename: "The Night Manager - Part 2.mkv",
+			want:     true,
+		},
+		{
+			name:     "movie sequel part with year should not match",
+			filename: "Harry.Potter.and.the.Deathly.Hallows.Part.2.2011.mkv",
+			want:     false,
+		},
 		{
 			name:     "no season/episode pattern",
 			filename: "randomfile.mkv",
@@ -289,3 +322,110 @@ func TestExtensionDetection(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectWithConfidence(t *testing.T) {
+	d := New()
+
+	tests := []struct {
+		filename       string
+		wantType       types.MediaType
+		wantConfidence float64
+	}{
+		{"The.Matrix.1999.1080p.BluRay.x264.mkv", types.MediaTypeMovie, 0.9},
+		{"Something.2020.mkv", types.MediaTypeMovie, 0.7},
+		{"randomfile.mkv", types.MediaTypeMovie, 0.3},
+		{"Breaking.Bad.S01E01.mkv", types.MediaTypeTV, 0.95},
+		{"The.Daily.Show.2023.05.15.mkv", types.MediaTypeTV, 0.8},
+		{"song.mp3", types.MediaTypeMusic, 0.9},
+		{"notes.txt", types.MediaTypeUnknown, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			gotType, gotConfidence, reasons := d.DetectWithConfidence(tt.filename)
+			if gotType != tt.wantType {
+				t.Errorf("type = %v, want %v", gotType, tt.wantType)
+			}
+			if gotConfidence != tt.wantConfidence {
+				t.Errorf("confidence = %v, want %v", gotConfidence, tt.wantConfidence)
+			}
+			if len(reasons) == 0 {
+				t.Error("expected at least one reason")
+			}
+			if got := d.Detect(tt.filename); got != gotType {
+				t.Errorf("Detect() = %v, want it to agree with DetectWithConfidence() %v", got, gotType)
+			}
+		})
+	}
+}
+
+func TestDetectWithConfidence_Ordering(t *testing.T) {
+	d := New()
+
+	_, clear, _ := d.DetectWithConfidence("The.Matrix.1999.1080p.BluRay.x264.mkv")
+	_, ambiguous, _ := d.DetectWithConfidence("Something.2020.mkv")
+	_, unknown, _ := d.DetectWithConfidence("randomfile.mkv")
+
+	if !(clear > ambiguous && ambiguous > unknown) {
+		t.Errorf("confidence ordering = clear %v, ambiguous %v, no markers %v; want strictly decreasing", clear, ambiguous, unknown)
+	}
+}
+
+func TestDetectWithConfidence_DurationProbe(t *testing.T) {
+	durations := map[string]time.Duration{
+		"Something.2020.mkv":      45 * time.Minute,
+		"randomfile.mkv":          22 * time.Minute,
+		"Some.Show.Part.2.mkv":    2 * time.Hour,
+		"Unclear.2019.mkv":        60 * time.Minute,
+		"Sample.2021.mkv":         5 * time.Minute,
+		"Breaking.Bad.S01E01.mkv": 2 * time.Hour,
+		"Feature.2018.mkv":        110 * time.Minute,
+	}
+	var probed []string
+	d := NewWithDurationProbe(func(path string) (time.Duration, error) {
+		probed = append(probed, filepath.Base(path))
+		duration, ok := durations[filepath.Base(path)]
+		if !ok {
+			return 0, errors.New("unreadable")
+		}
+		return duration, nil
+	})
+
+	tests := []struct {
+		filename       string
+		wantType       types.MediaType
+		wantConfidence float64
+	}{
+		// Running time overrides an ambiguous filename
+		{"/media/Something.2020.mkv", types.MediaTypeTV, 0.7},
+		{"/media/randomfile.mkv", types.MediaTypeTV, 0.7},
+		// Running time backs up an ambiguous filename
+		{"/media/Feature.2018.mkv", types.MediaTypeMovie, 0.8},
+		// Inconclusive running times and probe errors keep the filename verdict
+		{"/media/Unclear.2019.mkv", types.MediaTypeMovie, 0.7},
+		{"/media/Sample.2021.mkv", types.MediaTypeMovie, 0.7},
+		{"/media/Missing.2017.mkv", types.MediaTypeMovie, 0.7},
+		// Confident filenames are not probed
+		{"/media/Breaking.Bad.S01E01.mkv", types.MediaTypeTV, 0.95},
+	}
+
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.filename), func(t *testing.T) {
+			probed = nil
+			gotType, gotConfidence, reasons := d.DetectWithConfidence(tt.filename)
+			if gotType != tt.wantType {
+				t.Errorf("type = %v, want %v (reasons %v)", gotType, tt.wantType, reasons)
+			}
+			if gotConfidence != tt.wantConfidence {
+				t.Errorf("confidence = %v, want %v", gotConfidence, tt.wantConfidence)
+			}
+			if wantProbe := tt.wantConfidence != 0.95; (len(probed) > 0) != wantProbe {
+				t.Errorf("probed = %v, want probe %v", probed, wantProbe)
+			}
+		})
+	}
+
+	if got := New().Detect("/media/Something.2020.mkv"); got != types.MediaTypeMovie {
+		t.Errorf("New().Detect() = %v, want movie without a probe", got)
+	}
+}
@@ -10,6 +10,8 @@ import (
 // MovieDetector detects if a video file is a movie
 type MovieDetector interface {
 	IsMovie(filename string) bool
+	// Score returns a confidence between 0 and 1 and the heuristics that fired
+	Score(filename string) (float64, []string)
 }
 
 type movieDetector struct {
@@ -27,15 +29,25 @@ func NewMovieDetector() MovieDetector {
 
 // IsMovie returns true if the filename appears to be a movie
 func (m *movieDetector) IsMovie(filename string) bool {
+	confidence, _ := m.Score(filename)
+	return confidence > 0
+}
+
+// Score returns how confident the detector is that filename is a movie
+// (0 when no movie indicator matched) and the heuristics that fired
+func (m *movieDetector) Score(filename string) (float64, []string) {
 	// Remove extension for analysis
 	name := util.RemoveExtension(filename)
 	name = strings.ToLower(name)
 
-	// If it has a year pattern, it's likely a movie
+	var confidence float64
+	var reasons []string
+
+	// A year is a strong indicator for movies, though not definitive
+	// (dated TV episodes and shows like "Doctor Who 2005" also carry one)
 	if m.yearPattern.MatchString(name) {
-		// Make sure it doesn't have TV show patterns
-		// (year alone is not definitive, but it's a strong indicator for movies)
-		return true
+		confidence = 0.7
+		reasons = append(reasons, "release year")
 	}
 
 	// Check for common movie quality/source tags
@@ -51,10 +63,16 @@ func (m *movieDetector) IsMovie(filename string) bool {
 
 	for _, tag := range movieTags {
 		if strings.Contains(name, tag) {
-			return true
+			reasons = append(reasons, "quality/source tag: "+tag)
+			if confidence == 0 {
+				confidence = 0.5
+			} else {
+				confidence = 0.9
+			}
+			break
 		}
 	}
 
 	// If no specific indicators, we can't definitively say it's a movie
-	return false
+	return confidence, reasons
 }
@@ -8,6 +8,8 @@ import (
 // TVDetector detects if a video file is a TV show
 type TVDetector interface {
 	IsTV(filename string) bool
+	// Score returns a confidence between 0 and 1 and the heuristics that fired
+	Score(filename string) (float64, []string)
 }
 
 type tvDetector struct {
@@ -15,8 +17,14 @@ type tvDetector struct {
 	seasonEpisodePattern *regexp.Regexp
 	// Alternative pattern: 1x01, 01x01, etc.
 	altSeasonEpisodePattern *regexp.Regexp
-	// Episode pattern without season: E01, E1, etc. (less reliable)
+	// Episode pattern without season: E01, Ep1, etc. (mini-series)
 	episodeOnlyPattern *regexp.Regexp
+	// "Part 2" without season (mini-series, but also movie sequels)
+	partPattern *regexp.Regexp
+	// Release year, which marks a "Part" as a movie sequel
+	yearPattern *regexp.Regexp
+	// Daily show pattern: 2023.05.15, 2023-05-15
+	dailyPattern *regexp.Regexp
 }
 
 // NewTVDetector creates a new TVDetector
@@ -26,39 +34,52 @@ func NewTVDetector() TVDetector {
 		seasonEpisodePattern: regexp.MustCompile(`(?i)s\d{1,4}e\d{1,4}`),
 		// Match patterns like 1x01, 01x01 (more strict - needs digit before x)
 		altSeasonEpisodePattern: regexp.MustCompile(`(?i)\d{1,4}x\d{1,4}`),
-		// Match E01, E1, etc. (less reliable, used as secondary check)
-		episodeOnlyPattern: regexp.MustCompile(`(?i)\.e\d{1,4}[\.\s-]`),
+		// Match E01, Ep1, etc. standing alone between separators
+		episodeOnlyPattern: regexp.MustCompile(`(?i)[\._\s-]ep?\d{1,3}(?:[\._\s-]|$)`),
+		// Match Part 2, Part.02, Part2
+		partPattern: regexp.MustCompile(`(?i)[\._\s-]part[\._\s-]?\d{1,2}(?:[\._\s-]|$)`),
+		yearPattern: regexp.MustCompile(`[\[\(._\s](?:18[5-9]\d|19\d{2}|20\d{2}|21\d{2})(?:[\]\)._\s]|$)`),
+		// Match air dates like .2023.05.15. or -2023-05-15 (daily shows)
+		dailyPattern: regexp.MustCompile(`[\._\s-](?:19|20)\d{2}[.-](?:0[1-9]|1[0-2])[.-](?:0[1-9]|[12]\d|3[01])(?:[\._\s-]|$)`),
 	}
 }
 
 // IsTV returns true if the filename appears to be a TV show episode
 func (t *tvDetector) IsTV(filename string) bool {
+	confidence, _ := t.Score(filename)
+	return confidence > 0
+}
+
+// Score returns how confident the detector is that filename is a TV episode
+// (0 when no TV pattern matched) and the heuristics that fired
+func (t *tvDetector) Score(filename string) (float64, []string) {
 	name := strings.ToLower(filename)
 
 	// Check for standard season/episode pattern (S01E01)
 	if t.seasonEpisodePattern.MatchString(name) {
-		return true
+		return 0.95, []string{"season/episode marker (S01E01)"}
 	}
 
 	// Check for alternative pattern (1x01)
 	if t.altSeasonEpisodePattern.MatchString(name) {
-		return true
+		return 0.85, []string{"season/episode marker (1x01)"}
+	}
+
+	// Check for an air date (daily shows like talk shows and news)
+	if t.dailyPattern.MatchString(name) {
+		return 0.8, []string{"air date (YYYY.MM.DD)"}
 	}
 
-	// Check for episode-only pattern (less reliable)
-	// Only return true if we also find TV-related keywords
+	// Check for an episode without a season (mini-series like "Chernobyl.E01")
 	if t.episodeOnlyPattern.MatchString(name) {
-		// Additional validation - look for common TV show indicators
-		tvIndicators := []string{
-			"episode", "season", "series",
-			"hdtv", "pdtv", // TV-specific sources
-		}
-		for _, indicator := range tvIndicators {
-			if strings.Contains(name, indicator) {
-				return true
-			}
-		}
+		return 0.6, []string{"episode marker without season (E01)"}
+	}
+
+	// "Part 2" is a mini-series episode unless a year makes it a movie
+	// sequel ("Deathly.Hallows.Part.2.2011")
+	if t.partPattern.MatchString(name) && !t.yearPattern.MatchString(name) {
+		return 0.5, []string{"part marker without season or year (Part 2)"}
 	}
 
-	return false
+	return 0, nil
 }
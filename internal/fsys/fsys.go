@@ -0,0 +1,265 @@
+// Package fsys abstracts the file systems go-jf-org reads and writes, so
+// local directories and remote sources such as SFTP share the same scan
+// code, and the organizer can run against an in-memory tree in tests
+package fsys
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// File is an open file for reading
+type File interface {
+	io.Reader
+	io.ReaderAt
+	io.Closer
+}
+
+// ReadFS is the read access a scan needs. Paths are absolute and use the
+// local separator; implementations translate them as needed.
+type ReadFS interface {
+	Stat(name string) (fs.FileInfo, error)
+	// ReadDir lists a directory sorted by name
+	ReadDir(name string) ([]fs.DirEntry, error)
+	Open(name string) (File, error)
+}
+
+// FileSystem is the read and write access organizing needs. Errors for
+// missing files satisfy errors.Is(err, fs.ErrNotExist), as os's do.
+type FileSystem interface {
+	ReadFS
+	Rename(oldpath, newpath string) error
+	MkdirAll(path string, perm fs.FileMode) error
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// Create opens a file for writing, truncating one that exists. What is
+	// written is only guaranteed to be in place once Close returns nil.
+	Create(name string, perm fs.FileMode) (io.WriteCloser, error)
+	Remove(name string) error
+}
+
+// OSFileSystem is the local disk
+type OSFileSystem struct{}
+
+// Stat returns a local file's info
+func (OSFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// ReadDir lists a local directory sorted by name
+func (OSFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// Open opens a local file for reading
+func (OSFileSystem) Open(name string) (File, error) {
+	return os.Open(name)
+}
+
+// Rename moves a local file or directory
+func (OSFileSystem) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+// MkdirAll creates a local directory and its parents
+func (OSFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+// WriteFile writes a local file, replacing its contents
+func (OSFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// Create opens a local file for writing, truncating one that exists
+func (OSFileSystem) Create(name string, perm fs.FileMode) (io.WriteCloser, error) {
+	return os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+}
+
+// Remove deletes a local file or empty directory
+func (OSFileSystem) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// IsOS reports whether f is the local disk, which callers can then reach
+// with os calls the interface does not cover
+func IsOS(f ReadFS) bool {
+	_, ok := f.(OSFileSystem)
+	return ok
+}
+
+// WalkDir walks the tree at root like filepath.WalkDir, reading it through
+// fsys. The local disk is walked with filepath.WalkDir itself.
+func WalkDir(fsys ReadFS, root string, fn fs.WalkDirFunc) error {
+	if IsOS(fsys) {
+		return filepath.WalkDir(root, fn)
+	}
+
+	info, err := fsys.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDir(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+	if errors.Is(err, filepath.SkipDir) || errors.Is(err, filepath.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+// walkDir is filepath.WalkDir's recursion over fsys
+func walkDir(fsys ReadFS, path string, d fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, d, nil); err != nil || !d.IsDir() {
+		if errors.Is(err, filepath.SkipDir) && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		// Report the unreadable directory a second time, as WalkDir does
+		if err = fn(path, d, err); err != nil {
+			if errors.Is(err, filepath.SkipDir) {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		if err := walkDir(fsys, filepath.Join(path, entry.Name()), entry, fn); err != nil {
+			if errors.Is(err, filepath.SkipDir) {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile reads a whole file through fsys
+func ReadFile(fsys ReadFS, name string) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// Lstat is Stat that does not follow a final symlink on the local disk.
+// Other file systems are statted as they are.
+func Lstat(fsys ReadFS, name string) (fs.FileInfo, error) {
+	if IsOS(fsys) {
+		return os.Lstat(name)
+	}
+	return fsys.Stat(name)
+}
+
+// MkdirTemp creates a new directory in dir whose name starts with prefix,
+// like os.MkdirTemp, and returns its path
+func MkdirTemp(fsys FileSystem, dir, prefix string) (string, error) {
+	if IsOS(fsys) {
+		return os.MkdirTemp(dir, prefix)
+	}
+	for i := 0; i < 10000; i++ {
+		name := filepath.Join(dir, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10))
+		if _, err := fsys.Stat(name); !errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := fsys.MkdirAll(name, 0700); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	return "", &fs.PathError{Op: "mkdirtemp", Path: filepath.Join(dir, prefix+"*"), Err: fs.ErrExist}
+}
+
+// RemoveAll deletes path and everything inside it, like os.RemoveAll. A
+// missing path is not an error.
+func RemoveAll(fsys FileSystem, path string) error {
+	if IsOS(fsys) {
+		return os.RemoveAll(path)
+	}
+	var paths []string
+	err := WalkDir(fsys, path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		paths = append(paths, p)
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Walk order lists parents before children; remove children first
+	for i := len(paths) - 1; i >= 0; i-- {
+		if err := fsys.Remove(paths[i]); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortEntries orders directory entries by name, as os.ReadDir does
+func sortEntries(entries []fs.DirEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+}
+
+// Fetch copies name from fsys to local, creating local's directory. The copy
+// is written beside local and renamed into place, and takes the source's
+// modification time. A local file of the same size is taken to be an earlier
+// download and kept; fetched reports whether anything was copied.
+func Fetch(fsys ReadFS, name, local string) (fetched bool, err error) {
+	info, err := fsys.Stat(name)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat %s: %w", name, err)
+	}
+	if existing, err := os.Stat(local); err == nil && existing.Size() == info.Size() {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+		return false, fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	src, err := fsys.Open(name)
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer src.Close()
+
+	partial := local + ".part"
+	dst, err := os.Create(partial)
+	if err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", partial, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(partial)
+		return false, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(partial)
+		return false, fmt.Errorf("failed to write %s: %w", partial, err)
+	}
+	if err := os.Chtimes(partial, info.ModTime(), info.ModTime()); err != nil {
+		os.Remove(partial)
+		return false, fmt.Errorf("failed to set modification time of %s: %w", partial, err)
+	}
+	if err := os.Rename(partial, local); err != nil {
+		os.Remove(partial)
+		return false, fmt.Errorf("failed to move download into place: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,304 @@
+package fsys
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errNotDir = errors.New("not a directory")
+	errIsDir  = errors.New("is a directory")
+	// errNotEmpty matches fs.ErrExist, as ENOTEMPTY from the OS does
+	errNotEmpty = &notEmptyError{}
+)
+
+// notEmptyError reports removing or replacing a directory that has entries
+type notEmptyError struct{}
+
+func (*notEmptyError) Error() string        { return "directory not empty" }
+func (*notEmptyError) Is(target error) bool { return target == fs.ErrExist }
+
+// MemFS is a FileSystem held in memory, for running the organizer and the
+// transaction manager without touching the disk. Paths are cleaned before
+// use and the root directory always exists. It is safe for concurrent use.
+type MemFS struct {
+	mu    sync.RWMutex
+	nodes map[string]*memNode
+}
+
+// memNode is a file or directory in a MemFS
+type memNode struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// NewMemFS returns an empty MemFS
+func NewMemFS() *MemFS {
+	return &MemFS{nodes: make(map[string]*memNode)}
+}
+
+// isRoot reports whether a cleaned path is a root directory
+func isRoot(name string) bool {
+	return filepath.Dir(name) == name
+}
+
+// lookup returns the node at a cleaned path; the caller holds the lock
+func (m *MemFS) lookup(name string) (*memNode, bool) {
+	if isRoot(name) {
+		return &memNode{mode: fs.ModeDir | 0755}, true
+	}
+	node, ok := m.nodes[name]
+	return node, ok
+}
+
+// children lists the paths directly inside a cleaned directory path; the
+// caller holds the lock
+func (m *MemFS) children(dir string) []string {
+	var names []string
+	for name := range m.nodes {
+		if name != dir && filepath.Dir(name) == dir {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parentDir checks that a cleaned path's directory exists; the caller
+// holds the lock
+func (m *MemFS) parentDir(op, name string) error {
+	parent, ok := m.lookup(filepath.Dir(name))
+	if !ok {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if !parent.mode.IsDir() {
+		return &fs.PathError{Op: op, Path: name, Err: errNotDir}
+	}
+	return nil
+}
+
+// Stat returns a file's info
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	name = filepath.Clean(name)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, ok := m.lookup(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return memInfo{name: filepath.Base(name), node: *node}, nil
+}
+
+// ReadDir lists a directory sorted by name
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	name = filepath.Clean(name)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, ok := m.lookup(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	if !node.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+
+	children := m.children(name)
+	entries := make([]fs.DirEntry, len(children))
+	for i, child := range children {
+		entries[i] = fs.FileInfoToDirEntry(memInfo{name: filepath.Base(child), node: *m.nodes[child]})
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// Open opens a file for reading. Later writes to the file do not change
+// what an open file reads.
+func (m *MemFS) Open(name string) (File, error) {
+	name = filepath.Clean(name)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node, ok := m.lookup(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if node.mode.IsDir() {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errIsDir}
+	}
+	return memFile{bytes.NewReader(node.data)}, nil
+}
+
+// Rename moves a file or directory, with everything inside it. Like
+// os.Rename on Unix it replaces an existing file, or an empty directory
+// when moving a directory.
+func (m *MemFS) Rename(oldpath, newpath string) error {
+	oldpath, newpath = filepath.Clean(oldpath), filepath.Clean(newpath)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fail := func(err error) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+	node, ok := m.nodes[oldpath]
+	if !ok {
+		return fail(fs.ErrNotExist)
+	}
+	if oldpath == newpath {
+		return nil
+	}
+	if err := m.parentDir("rename", newpath); err != nil {
+		return fail(errors.Unwrap(err))
+	}
+	if strings.HasPrefix(newpath, oldpath+string(filepath.Separator)) {
+		return fail(fs.ErrInvalid)
+	}
+	if existing, ok := m.nodes[newpath]; ok {
+		switch {
+		case node.mode.IsDir() && !existing.mode.IsDir():
+			return fail(errNotDir)
+		case !node.mode.IsDir() && existing.mode.IsDir():
+			return fail(errIsDir)
+		case existing.mode.IsDir() && len(m.children(newpath)) > 0:
+			return fail(errNotEmpty)
+		}
+	}
+
+	prefix := oldpath + string(filepath.Separator)
+	moved := make(map[string]*memNode)
+	for name, n := range m.nodes {
+		if name == oldpath {
+			moved[newpath] = n
+		} else if strings.HasPrefix(name, prefix) {
+			moved[filepath.Join(newpath, strings.TrimPrefix(name, prefix))] = n
+		} else {
+			continue
+		}
+		delete(m.nodes, name)
+	}
+	for name, n := range moved {
+		m.nodes[name] = n
+	}
+	return nil
+}
+
+// MkdirAll creates a directory and any missing parents
+func (m *MemFS) MkdirAll(path string, perm fs.FileMode) error {
+	path = filepath.Clean(path)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var missing []string
+	for dir := path; ; dir = filepath.Dir(dir) {
+		node, ok := m.lookup(dir)
+		if ok {
+			if !node.mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: dir, Err: errNotDir}
+			}
+			break
+		}
+		missing = append(missing, dir)
+	}
+	now := time.Now()
+	for _, dir := range missing {
+		m.nodes[dir] = &memNode{mode: fs.ModeDir | perm.Perm(), modTime: now}
+	}
+	return nil
+}
+
+// WriteFile writes a file, replacing its contents and keeping the mode of
+// one that exists. The file's directory must exist.
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	name = filepath.Clean(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.parentDir("open", name); err != nil {
+		return err
+	}
+	mode := perm.Perm()
+	if existing, ok := m.lookup(name); ok {
+		if existing.mode.IsDir() {
+			return &fs.PathError{Op: "open", Path: name, Err: errIsDir}
+		}
+		mode = existing.mode
+	}
+	m.nodes[name] = &memNode{data: bytes.Clone(data), mode: mode, modTime: time.Now()}
+	return nil
+}
+
+// Create opens a file for writing. The contents are buffered and stored
+// when the file is closed; the file's directory must exist.
+func (m *MemFS) Create(name string, perm fs.FileMode) (io.WriteCloser, error) {
+	name = filepath.Clean(name)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := m.parentDir("open", name); err != nil {
+		return nil, err
+	}
+	if existing, ok := m.lookup(name); ok && existing.mode.IsDir() {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errIsDir}
+	}
+	return &memWriter{fs: m, name: name, perm: perm}, nil
+}
+
+// memWriter is a MemFS file open for writing
+type memWriter struct {
+	bytes.Buffer
+	fs   *MemFS
+	name string
+	perm fs.FileMode
+}
+
+func (w *memWriter) Close() error {
+	return w.fs.WriteFile(w.name, w.Bytes(), w.perm)
+}
+
+// Remove deletes a file or an empty directory
+func (m *MemFS) Remove(name string) error {
+	name = filepath.Clean(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, ok := m.nodes[name]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if node.mode.IsDir() && len(m.children(name)) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(m.nodes, name)
+	return nil
+}
+
+// memInfo describes a MemFS node
+type memInfo struct {
+	name string
+	node memNode
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return int64(len(i.node.data)) }
+func (i memInfo) Mode() fs.FileMode  { return i.node.mode }
+func (i memInfo) ModTime() time.Time { return i.node.modTime }
+func (i memInfo) IsDir() bool        { return i.node.mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
+
+// memFile is an open MemFS file
+type memFile struct {
+	*bytes.Reader
+}
+
+// Close does nothing; the file holds no resources
+func (memFile) Close() error {
+	return nil
+}
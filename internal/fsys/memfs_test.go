@@ -0,0 +1,132 @@
+package fsys
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMemFS(t *testing.T) {
+	m := NewMemFS()
+	root := string(filepath.Separator)
+	show := filepath.Join(root, "media", "Show")
+
+	if err := m.WriteFile(filepath.Join(show, "a.mkv"), []byte("a"), 0644); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("WriteFile() without a directory error = %v, want ErrNotExist", err)
+	}
+	if err := m.MkdirAll(filepath.Join(show, "Season 01"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"b.mkv", "a.mkv", filepath.Join("Season 01", "e1.mkv")} {
+		if err := m.WriteFile(filepath.Join(show, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := m.ReadDir(show)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := []string{"Season 01", "a.mkv", "b.mkv"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ReadDir() = %v, want %v", names, want)
+	}
+
+	// Renaming a directory carries its contents
+	moved := filepath.Join(root, "library", "Show")
+	if err := m.Rename(show, moved); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Rename() into a missing directory error = %v, want ErrNotExist", err)
+	}
+	if err := m.MkdirAll(filepath.Dir(moved), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Rename(show, moved); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ReadFile(m, filepath.Join(moved, "Season 01", "e1.mkv"))
+	if err != nil || string(data) != filepath.Join("Season 01", "e1.mkv") {
+		t.Errorf("ReadFile() after rename = %q, %v", data, err)
+	}
+	if _, err := m.Stat(show); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat() of the old path error = %v, want ErrNotExist", err)
+	}
+
+	if err := m.Remove(moved); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Remove() of a non-empty directory error = %v, want ErrExist", err)
+	}
+	if err := m.Remove(filepath.Join(moved, "a.mkv")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Open(filepath.Join(moved, "a.mkv")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open() of a removed file error = %v, want ErrNotExist", err)
+	}
+}
+
+func TestMemFSCreate(t *testing.T) {
+	m := NewMemFS()
+	name := filepath.Join(string(filepath.Separator), "media", "a.mkv")
+
+	if _, err := m.Create(name, 0644); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Create() without a directory error = %v, want ErrNotExist", err)
+	}
+	if err := m.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	w, err := m.Create(name, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range []string{"hello ", "world"} {
+		if _, err := w.Write([]byte(part)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := m.Stat(name); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat() before Close error = %v, want ErrNotExist", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ReadFile(m, name)
+	if err != nil || string(data) != "hello world" {
+		t.Errorf("ReadFile() after Close = %q, %v", data, err)
+	}
+}
+
+func TestMkdirTempRemoveAll(t *testing.T) {
+	m := NewMemFS()
+	root := string(filepath.Separator)
+
+	dir, err := MkdirTemp(m, root, ".import-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := MkdirTemp(m, root, ".import-")
+	if err != nil || other == dir {
+		t.Fatalf("MkdirTemp() = %q, %v; want a second, different directory", other, err)
+	}
+	if err := m.MkdirAll(filepath.Join(dir, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile(filepath.Join(dir, "a", "b", "f"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveAll(m, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Stat(dir); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat() after RemoveAll error = %v, want ErrNotExist", err)
+	}
+	if _, err := m.Stat(other); err != nil {
+		t.Errorf("RemoveAll() removed a sibling: %v", err)
+	}
+	if err := RemoveAll(m, dir); err != nil {
+		t.Errorf("RemoveAll() of a missing path error = %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package fsys
+
+import (
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTPScheme prefixes source paths on an SFTP server
+const SFTPScheme = "sftp://"
+
+// IsSFTPURL reports whether a source is an sftp:// URL
+func IsSFTPURL(source string) bool {
+	return strings.HasPrefix(strings.ToLower(source), SFTPScheme)
+}
+
+// SFTPURL is a parsed "sftp://user@host:port/path" source
+type SFTPURL struct {
+	// User logs in; empty means the local user name
+	User string
+	// Host is the server, with ":port" when not 22
+	Host string
+	// Path is the absolute remote path
+	Path string
+}
+
+// ParseSFTPURL parses an sftp:// source. Passwords are refused in the URL,
+// where they would end up in shell history and logs.
+func ParseSFTPURL(raw string) (*SFTPURL, error) {
+	if !IsSFTPURL(raw) {
+		return nil, fmt.Errorf("not an sftp:// URL: %s", raw)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sftp URL %s: %w", raw, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid sftp URL %s: missing host", raw)
+	}
+	if _, ok := u.User.Password(); ok {
+		return nil, fmt.Errorf("invalid sftp URL: passwords are not accepted in the URL, set GO_JF_ORG_SFTP_PASSWORD instead")
+	}
+
+	p := path.Clean("/" + u.Path)
+	return &SFTPURL{User: u.User.Username(), Host: u.Host, Path: p}, nil
+}
+
+// String formats the URL
+func (u *SFTPURL) String() string {
+	return u.WithPath(u.Path)
+}
+
+// WithPath formats the URL of another path on the same server
+func (u *SFTPURL) WithPath(p string) string {
+	host := u.Host
+	if u.User != "" {
+		host = u.User + "@" + host
+	}
+	return SFTPScheme + host + filepath.ToSlash(p)
+}
+
+// addr returns host:port, defaulting to port 22
+func (u *SFTPURL) addr() string {
+	if _, _, err := net.SplitHostPort(u.Host); err == nil {
+		return u.Host
+	}
+	return net.JoinHostPort(strings.Trim(u.Host, "[]"), "22")
+}
+
+// SFTPOptions controls how DialSFTP authenticates
+type SFTPOptions struct {
+	// IdentityFile is an unencrypted private key; empty tries
+	// ~/.ssh/id_ed25519, id_ecdsa and id_rsa. A running ssh-agent is
+	// always tried first.
+	IdentityFile string
+	// KnownHosts is the known_hosts file the server's key must be listed
+	// in; empty means ~/.ssh/known_hosts
+	KnownHosts string
+	// InsecureIgnoreHostKey accepts any host key
+	InsecureIgnoreHostKey bool
+	// Password is tried after keys when set
+	Password string
+	// Timeout limits connecting and logging in; 0 means 30s
+	Timeout time.Duration
+}
+
+// SFTP is a directory tree on an SFTP server
+type SFTP struct {
+	client *sftp.Client
+	// conn is the SSH connection DialSFTP opened, nil for NewSFTP
+	conn *ssh.Client
+}
+
+// NewSFTP reads through an existing SFTP client, which the caller closes
+func NewSFTP(client *sftp.Client) *SFTP {
+	return &SFTP{client: client}
+}
+
+// DialSFTP connects and logs in to the server in u
+func DialSFTP(u *SFTPURL, opts SFTPOptions) (*SFTP, error) {
+	username := u.User
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("no user in %s and cannot determine the local one: %w", u, err)
+		}
+		username = current.Username
+	}
+
+	hostKey, err := hostKeyCallback(opts)
+	if err != nil {
+		return nil, err
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	conn, err := ssh.Dial("tcp", u.addr(), &ssh.ClientConfig{
+		User:            username,
+		Auth:            authMethods(opts),
+		HostKeyCallback: hostKey,
+		Timeout:         timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", u.Host, err)
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start sftp on %s: %w", u.Host, err)
+	}
+	return &SFTP{client: client, conn: conn}, nil
+}
+
+// hostKeyCallback verifies server keys against known_hosts
+func hostKeyCallback(opts SFTPOptions) (ssh.HostKeyCallback, error) {
+	if opts.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	file := opts.KnownHosts
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find known_hosts: %w", err)
+		}
+		file = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	callback, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known hosts %s (connect once with ssh to add the server): %w", file, err)
+	}
+	return callback, nil
+}
+
+// authMethods lists the agent, key files and password to try, in order
+func authMethods(opts SFTPOptions) []ssh.AuthMethod {
+	var methods []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	keyFiles := []string{opts.IdentityFile}
+	if opts.IdentityFile == "" {
+		keyFiles = keyFiles[:0]
+		if home, err := os.UserHomeDir(); err == nil {
+			for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+				keyFiles = append(keyFiles, filepath.Join(home, ".ssh", name))
+			}
+		}
+	}
+	var signers []ssh.Signer
+	for _, file := range keyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if signer, err := ssh.ParsePrivateKey(data); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	if opts.Password != "" {
+		methods = append(methods, ssh.Password(opts.Password))
+	}
+	return methods
+}
+
+// Close ends the SFTP session and, when DialSFTP opened it, the connection
+func (s *SFTP) Close() error {
+	err := s.client.Close()
+	if s.conn != nil {
+		if cerr := s.conn.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Stat returns a remote file's info
+func (s *SFTP) Stat(name string) (fs.FileInfo, error) {
+	return s.client.Stat(filepath.ToSlash(name))
+}
+
+// ReadDir lists a remote directory sorted by name
+func (s *SFTP) ReadDir(name string) ([]fs.DirEntry, error) {
+	infos, err := s.client.ReadDir(filepath.ToSlash(name))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// Open opens a remote file for reading
+func (s *SFTP) Open(name string) (File, error) {
+	return s.client.Open(filepath.ToSlash(name))
+}
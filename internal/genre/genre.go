@@ -0,0 +1,72 @@
+// Package genre normalizes genre names returned by metadata providers
+package genre
+
+import "strings"
+
+// Mapper collapses genre synonyms to canonical names and optionally
+// restricts genres to an allowlist
+type Mapper struct {
+	mapping   map[string]string
+	allowlist map[string]bool
+}
+
+// NewMapper creates a genre mapper. Mapping keys are matched case-insensitively.
+// If allowlist is non-empty, genres not in it are dropped after mapping.
+func NewMapper(mapping map[string]string, allowlist []string) *Mapper {
+	m := &Mapper{
+		mapping: make(map[string]string, len(mapping)),
+	}
+
+	for from, to := range mapping {
+		m.mapping[normalizeKey(from)] = strings.TrimSpace(to)
+	}
+
+	if len(allowlist) > 0 {
+		m.allowlist = make(map[string]bool, len(allowlist))
+		for _, g := range allowlist {
+			m.allowlist[normalizeKey(g)] = true
+		}
+	}
+
+	return m
+}
+
+// Apply maps genres to their canonical names, drops genres outside the
+// allowlist (when set) and removes duplicates while preserving order
+func (m *Mapper) Apply(genres []string) []string {
+	if m == nil || len(genres) == 0 {
+		return genres
+	}
+
+	result := make([]string, 0, len(genres))
+	seen := make(map[string]bool, len(genres))
+
+	for _, g := range genres {
+		g = strings.TrimSpace(g)
+		if g == "" {
+			continue
+		}
+
+		if canonical, ok := m.mapping[normalizeKey(g)]; ok {
+			g = canonical
+		}
+
+		key := normalizeKey(g)
+		if g == "" || seen[key] {
+			continue
+		}
+		if m.allowlist != nil && !m.allowlist[key] {
+			continue
+		}
+
+		seen[key] = true
+		result = append(result, g)
+	}
+
+	return result
+}
+
+// normalizeKey returns the lookup key for a genre name
+func normalizeKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,72 @@
+package genre
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapper_Apply(t *testing.T) {
+	mapping := map[string]string{
+		"Sci-Fi":             "Science Fiction",
+		"sci-fi & fantasy":   "Science Fiction",
+		"Action & Adventure": "Action",
+	}
+
+	tests := []struct {
+		name      string
+		allowlist []string
+		input     []string
+		want      []string
+	}{
+		{
+			name:  "synonym mapped to canonical",
+			input: []string{"Sci-Fi", "Drama"},
+			want:  []string{"Science Fiction", "Drama"},
+		},
+		{
+			name:  "case-insensitive match",
+			input: []string{"SCI-FI"},
+			want:  []string{"Science Fiction"},
+		},
+		{
+			name:  "duplicates after mapping removed",
+			input: []string{"Sci-Fi", "Science Fiction", "Sci-Fi & Fantasy"},
+			want:  []string{"Science Fiction"},
+		},
+		{
+			name:  "unmapped genres kept without allowlist",
+			input: []string{"Documentary"},
+			want:  []string{"Documentary"},
+		},
+		{
+			name:      "unlisted genre dropped with allowlist",
+			allowlist: []string{"Science Fiction", "Drama"},
+			input:     []string{"Sci-Fi", "Drama", "Documentary"},
+			want:      []string{"Science Fiction", "Drama"},
+		},
+		{
+			name:      "all genres dropped",
+			allowlist: []string{"Comedy"},
+			input:     []string{"Drama"},
+			want:      []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMapper(mapping, tt.allowlist)
+			got := m.Apply(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper_NilSafe(t *testing.T) {
+	var m *Mapper
+	input := []string{"Sci-Fi"}
+	if got := m.Apply(input); !reflect.DeepEqual(got, input) {
+		t.Errorf("nil Mapper Apply() = %v, want %v", got, input)
+	}
+}
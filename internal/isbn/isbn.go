@@ -0,0 +1,93 @@
+package isbn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Normalize validates an ISBN-10 or ISBN-13 and returns it as a bare ISBN-13.
+// Hyphens and spaces are stripped, check digits are verified, and ISBN-10
+// values are converted to their 978-prefixed ISBN-13 equivalent.
+func Normalize(s string) (string, error) {
+	cleaned := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(s)))
+	cleaned = strings.TrimPrefix(cleaned, "ISBN")
+	cleaned = strings.TrimPrefix(cleaned, ":")
+
+	switch len(cleaned) {
+	case 10:
+		if !IsValidISBN10(cleaned) {
+			return "", fmt.Errorf("invalid ISBN-10 check digit: %s", s)
+		}
+		return ToISBN13(cleaned), nil
+	case 13:
+		if !IsValidISBN13(cleaned) {
+			return "", fmt.Errorf("invalid ISBN-13 check digit: %s", s)
+		}
+		return cleaned, nil
+	default:
+		return "", fmt.Errorf("invalid ISBN length (%d): %s", len(cleaned), s)
+	}
+}
+
+// IsValidISBN10 reports whether s is a bare ISBN-10 with a valid check digit
+func IsValidISBN10(s string) bool {
+	if len(s) != 10 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 10; i++ {
+		c := s[i]
+		var digit int
+		switch {
+		case c >= '0' && c <= '9':
+			digit = int(c - '0')
+		case c == 'X' && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+
+	return sum%11 == 0
+}
+
+// IsValidISBN13 reports whether s is a bare ISBN-13 with a valid check digit
+func IsValidISBN13(s string) bool {
+	if len(s) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 13; i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return sum%10 == 0
+}
+
+// ToISBN13 converts a valid bare ISBN-10 into its ISBN-13 form
+func ToISBN13(isbn10 string) string {
+	body := "978" + isbn10[:9]
+
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(body[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	check := (10 - sum%10) % 10
+	return fmt.Sprintf("%s%d", body, check)
+}
@@ -0,0 +1,75 @@
+package isbn
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "valid ISBN-13",
+			input: "9780743273565",
+			want:  "9780743273565",
+		},
+		{
+			name:  "valid ISBN-13 with hyphens",
+			input: "978-0-7432-7356-5",
+			want:  "9780743273565",
+		},
+		{
+			name:  "valid ISBN-10 converted to ISBN-13",
+			input: "0743273567",
+			want:  "9780743273565",
+		},
+		{
+			name:  "valid ISBN-10 with X check digit",
+			input: "0-8044-2957-X",
+			want:  "9780804429573",
+		},
+		{
+			name:  "lowercase x check digit and prefix",
+			input: "ISBN 080442957x",
+			want:  "9780804429573",
+		},
+		{
+			name:    "ISBN-10 with bad check digit",
+			input:   "0743273568",
+			wantErr: true,
+		},
+		{
+			name:    "ISBN-13 with bad check digit",
+			input:   "9780743273566",
+			wantErr: true,
+		},
+		{
+			name:    "wrong length",
+			input:   "12345",
+			wantErr: true,
+		},
+		{
+			name:    "non-digit characters",
+			input:   "97807432735AB",
+			wantErr: true,
+		},
+		{
+			name:    "empty string",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package jellyfin
+
+import "strings"
+
+// ActorsDir is the Kodi-style folder, next to a movie or show, holding actor
+// profile images that Jellyfin picks up for the cast list
+const ActorsDir = ".actors"
+
+// ActorImageName returns the image filename for an actor inside ActorsDir,
+// e.g. "Keanu Reeves" -> "Keanu_Reeves.jpg"; ext defaults to ".jpg"
+func ActorImageName(name, ext string) string {
+	if ext == "" {
+		ext = ".jpg"
+	}
+	return strings.ReplaceAll(SanitizeFilename(name), " ", "_") + ext
+}
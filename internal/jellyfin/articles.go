@@ -0,0 +1,46 @@
+package jellyfin
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultSortArticles are the leading articles moved to the end of folder names
+// when article sorting is enabled
+var DefaultSortArticles = []string{"The", "A", "An"}
+
+// MoveArticleToEnd moves a leading article to the end of a title for
+// alphabetical sorting, e.g. "The Matrix" -> "Matrix, The".
+// Articles are matched case-insensitively. Articles ending in an apostrophe
+// (e.g. "L'") may be attached to the following word ("L'Avventura" -> "Avventura, L'").
+// Titles without a listed article, or consisting only of the article, are returned unchanged.
+func MoveArticleToEnd(title string, articles []string) string {
+	title = strings.TrimSpace(title)
+
+	for _, article := range articles {
+		article = strings.TrimSpace(article)
+		if article == "" || len(title) <= len(article) {
+			continue
+		}
+		if !strings.EqualFold(title[:len(article)], article) {
+			continue
+		}
+
+		rest := title[len(article):]
+		if !strings.HasSuffix(article, "'") {
+			// Article must be a whole word
+			if !unicode.IsSpace(rune(rest[0])) {
+				continue
+			}
+		}
+
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			continue
+		}
+
+		return rest + ", " + title[:len(article)]
+	}
+
+	return title
+}
@@ -0,0 +1,105 @@
+package jellyfin
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestMoveArticleToEnd(t *testing.T) {
+	articles := []string{"The", "A", "An", "L'"}
+
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"The Matrix", "Matrix, The"},
+		{"A Quiet Place", "Quiet Place, A"},
+		{"An American Werewolf in London", "American Werewolf in London, An"},
+		{"the office", "office, the"},
+		{"L'Avventura", "Avventura, L'"},
+		{"Inception", "Inception"},
+		{"Theory of Everything", "Theory of Everything"},
+		{"Alien", "Alien"},
+		{"The", "The"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := MoveArticleToEnd(tt.title, articles); got != tt.want {
+				t.Errorf("MoveArticleToEnd(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFullPath_SortArticles(t *testing.T) {
+	n := NewNaming()
+	n.SetSortArticles(DefaultSortArticles)
+
+	movie := &types.Metadata{Title: "The Matrix", Year: 1999}
+	got := n.BuildFullPath("/movies", types.MediaTypeMovie, movie, ".mkv")
+	want := filepath.Join("/movies", "Matrix, The (1999)", "The Matrix (1999).mkv")
+	if got != want {
+		t.Errorf("BuildFullPath() = %q, want %q", got, want)
+	}
+
+	plain := &types.Metadata{Title: "Inception", Year: 2010}
+	got = n.BuildFullPath("/movies", types.MediaTypeMovie, plain, ".mkv")
+	want = filepath.Join("/movies", "Inception (2010)", "Inception (2010).mkv")
+	if got != want {
+		t.Errorf("BuildFullPath() = %q, want %q", got, want)
+	}
+
+	show := &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "The Office", Season: 1, Episode: 2}}
+	got = n.BuildFullPath("/tv", types.MediaTypeTV, show, ".mkv")
+	want = filepath.Join("/tv", "Office, The", "Season 01", "The Office - S01E02.mkv")
+	if got != want {
+		t.Errorf("BuildFullPath() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateNFO_SortTitle(t *testing.T) {
+	g := NewNFOGenerator()
+
+	// Disabled by default
+	nfo, err := g.GenerateMovieNFO(&types.Metadata{Title: "The Matrix", Year: 1999})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(nfo, "<sorttitle>") {
+		t.Error("sorttitle should not be written when article sorting is disabled")
+	}
+
+	g.SetSortArticles(DefaultSortArticles)
+
+	nfo, err = g.GenerateMovieNFO(&types.Metadata{Title: "The Matrix", Year: 1999})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(nfo, "<title>The Matrix</title>") {
+		t.Error("NFO title should keep the article")
+	}
+	if !strings.Contains(nfo, "<sorttitle>Matrix, The</sorttitle>") {
+		t.Errorf("NFO should contain sorttitle, got:\n%s", nfo)
+	}
+
+	nfo, err = g.GenerateMovieNFO(&types.Metadata{Title: "Inception", Year: 2010})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(nfo, "<sorttitle>") {
+		t.Error("sorttitle should be omitted for titles without an article")
+	}
+
+	nfo, err = g.GenerateTVShowNFO(&types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "The Office"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(nfo, "<sorttitle>Office, The</sorttitle>") {
+		t.Errorf("tvshow NFO should contain sorttitle, got:\n%s", nfo)
+	}
+}
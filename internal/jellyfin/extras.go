@@ -0,0 +1,81 @@
+package jellyfin
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ExtrasLayout controls where movie extras named with a Jellyfin suffix
+// ("Movie-trailer.mkv") are placed
+type ExtrasLayout string
+
+const (
+	// ExtrasLayoutSuffix keeps extras beside the movie, named with the
+	// suffix: "Movie (2020)/Movie (2020)-trailer.mkv"
+	ExtrasLayoutSuffix ExtrasLayout = "suffix"
+	// ExtrasLayoutFolder moves extras into the matching extras folder:
+	// "Movie (2020)/trailers/Movie (2020)-trailer.mkv"
+	ExtrasLayoutFolder ExtrasLayout = "folder"
+)
+
+// extraTypeDirs maps Jellyfin extra suffixes to their extras folder
+var extraTypeDirs = map[string]string{
+	"trailer":         "trailers",
+	"behindthescenes": "behind the scenes",
+	"deleted":         "deleted scenes",
+	"deletedscene":    "deleted scenes",
+	"featurette":      "featurettes",
+	"interview":       "interviews",
+	"scene":           "scenes",
+	"short":           "shorts",
+	"clip":            "clips",
+	"other":           "other",
+	"extra":           "extras",
+}
+
+// ExtraTypeDir returns the extras folder for a Jellyfin extra suffix
+// ("trailer" -> "trailers"), or "extras" for an unknown suffix
+func ExtraTypeDir(extraType string) string {
+	if dir, ok := extraTypeDirs[strings.ToLower(extraType)]; ok {
+		return dir
+	}
+	return "extras"
+}
+
+// SetExtrasLayout sets where suffixed movie extras go (suffix or folder)
+func (n *Naming) SetExtrasLayout(layout ExtrasLayout) {
+	if layout == "" {
+		layout = ExtrasLayoutSuffix
+	}
+	n.extrasLayout = layout
+}
+
+// GetMovieExtraPath returns where an extra of the movie organized at
+// moviePath goes: "Movie (2020)-trailer.ext" beside it, or inside the
+// matching extras folder under the folder layout. Flat movie libraries
+// always use the suffix.
+func (n *Naming) GetMovieExtraPath(moviePath, extraType, ext string) string {
+	dir := filepath.Dir(moviePath)
+	if n.extrasLayout == ExtrasLayoutFolder && n.MovieLayout() != MovieLayoutFlat {
+		dir = filepath.Join(dir, ExtraTypeDir(extraType))
+	}
+	return filepath.Join(dir, movieStem(moviePath)+"-"+extraType+ext)
+}
+
+// DefaultExtrasDirs lists the movie subfolders Jellyfin recognizes for extras,
+// trailers and additional artwork
+var DefaultExtrasDirs = []string{
+	"extras", "trailers", "extrafanart", "behind the scenes", "deleted scenes",
+	"featurettes", "interviews", "scenes", "shorts", "clips", "other",
+	"backdrops", "theme-music",
+}
+
+// IsExtrasDir reports whether a folder name is one of dirs, ignoring case
+func IsExtrasDir(name string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.EqualFold(name, dir) {
+			return true
+		}
+	}
+	return false
+}
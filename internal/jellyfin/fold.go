@@ -0,0 +1,70 @@
+package jellyfin
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// UnmappedMode controls what ASCII folding does with characters that have no
+// ASCII equivalent, such as CJK, Cyrillic or Arabic script
+type UnmappedMode string
+
+const (
+	// UnmappedKeep leaves characters without an ASCII equivalent unchanged
+	UnmappedKeep UnmappedMode = "keep"
+	// UnmappedStrip removes characters without an ASCII equivalent
+	UnmappedStrip UnmappedMode = "strip"
+)
+
+// asciiLetters maps letters that do not decompose into an ASCII base letter
+var asciiLetters = map[rune]string{
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O",
+	'ł': "l", 'Ł': "L",
+	'đ': "d", 'Đ': "D",
+	'ð': "d", 'Ð': "D",
+	'þ': "th", 'Þ': "Th",
+	'ı': "i",
+	'‘': "'", '’': "'", '‚': "'",
+	'“': "'", '”': "'", '„': "'",
+	'–': "-", '—': "-",
+}
+
+// FoldASCII transliterates s to ASCII: accents are removed ("Amélie" →
+// "Amelie") and letters such as ß and æ are spelled out. Characters with no
+// ASCII equivalent are kept or removed according to mode. If removing them
+// would leave nothing, s is returned unchanged so a name never becomes empty.
+func FoldASCII(s string, mode UnmappedMode) string {
+	// Decompose (é → e + ◌́, ｆ → f) and drop the combining marks
+	decomposed, _, err := transform.String(transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range decomposed {
+		switch {
+		case r < unicode.MaxASCII:
+			b.WriteRune(r)
+		case asciiLetters[r] != "":
+			b.WriteString(asciiLetters[r])
+		case mode == UnmappedStrip:
+			// dropped
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	folded := strings.TrimSpace(spaceRegex.ReplaceAllString(b.String(), " "))
+	folded = strings.Trim(folded, ".")
+	if folded == "" {
+		return s
+	}
+	return folded
+}
@@ -0,0 +1,80 @@
+package jellyfin
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestFoldASCII(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		mode  UnmappedMode
+		want  string
+	}{
+		{"accents", "Amélie", UnmappedKeep, "Amelie"},
+		{"mixed accents", "Crème Brûlée à la Façon", UnmappedKeep, "Creme Brulee a la Facon"},
+		{"spelled out letters", "Straße Æon Œuvre Łódź", UnmappedKeep, "Strasse AEon OEuvre Lodz"},
+		{"nordic", "Søren Kierkegård", UnmappedKeep, "Soren Kierkegard"},
+		{"curly quotes and dashes", "Schindler’s List – Part 1", UnmappedKeep, "Schindler's List - Part 1"},
+		{"fullwidth", "ＡＢＣ", UnmappedKeep, "ABC"},
+		{"ascii unchanged", "The Matrix", UnmappedKeep, "The Matrix"},
+		{"cjk kept", "千と千尋の神隠し", UnmappedKeep, "千と千尋の神隠し"},
+		{"cjk stripped next to latin", "Spirited Away 千と千尋の神隠し", UnmappedStrip, "Spirited Away"},
+		{"cjk only never empties", "千と千尋の神隠し", UnmappedStrip, "千と千尋の神隠し"},
+		{"cyrillic stripped", "Brat Брат", UnmappedStrip, "Brat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FoldASCII(tt.input, tt.mode); got != tt.want {
+				t.Errorf("FoldASCII(%q, %s) = %q, want %q", tt.input, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFullPath_ASCIIFold(t *testing.T) {
+	metadata := &types.Metadata{
+		Title: "Amélie",
+		Year:  2001,
+		MovieMetadata: &types.MovieMetadata{
+			OriginalTitle: "Le Fabuleux Destin d'Amélie Poulain",
+		},
+	}
+
+	n := NewNaming()
+	if got, want := n.BuildFullPath("/media/movies", types.MediaTypeMovie, metadata, ".mkv"),
+		filepath.Join("/media/movies", "Amélie (2001)", "Amélie (2001).mkv"); got != want {
+		t.Errorf("default BuildFullPath() = %q, want Unicode preserved %q", got, want)
+	}
+
+	n.SetASCIIFold(true, UnmappedKeep)
+	if got, want := n.BuildFullPath("/media/movies", types.MediaTypeMovie, metadata, ".mkv"),
+		filepath.Join("/media/movies", "Amelie (2001)", "Amelie (2001).mkv"); got != want {
+		t.Errorf("folded BuildFullPath() = %q, want %q", got, want)
+	}
+
+	tv := &types.Metadata{TVMetadata: &types.TVMetadata{
+		ShowTitle:    "Señor Ávila",
+		Season:       1,
+		Episode:      2,
+		EpisodeTitle: "El Niño",
+	}}
+	if got, want := n.BuildFullPath("/media/tv", types.MediaTypeTV, tv, ".mkv"),
+		filepath.Join("/media/tv", "Senor Avila", "Season 01", "Senor Avila - S01E02 - El Nino.mkv"); got != want {
+		t.Errorf("folded TV BuildFullPath() = %q, want %q", got, want)
+	}
+
+	// NFO content keeps the original Unicode title
+	nfo, err := NewNFOGenerator().GenerateMovieNFO(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(nfo, "<title>Amélie</title>") {
+		t.Errorf("NFO should keep the Unicode title, got:\n%s", nfo)
+	}
+}
@@ -0,0 +1,51 @@
+package jellyfin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// nfoElement is a top-level NFO element kept verbatim
+type nfoElement struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   string     `xml:",innerxml"`
+}
+
+// nfoDocument is an NFO parsed only down to its top-level elements
+type nfoDocument struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Elements []nfoElement `xml:",any"`
+}
+
+// MergeNFO combines an existing, possibly hand-curated NFO with a generated
+// one. Every element of existing is kept as written; generated elements are
+// added only when existing has no element of that name, so a curated
+// <genre> list replaces all generated genres rather than mixing with them.
+// Both documents must share a root element (e.g. <movie>).
+func MergeNFO(existing, generated string) (string, error) {
+	var base, extra nfoDocument
+	if err := xml.Unmarshal([]byte(existing), &base); err != nil {
+		return "", fmt.Errorf("failed to parse existing NFO: %w", err)
+	}
+	if err := xml.Unmarshal([]byte(generated), &extra); err != nil {
+		return "", fmt.Errorf("failed to parse generated NFO: %w", err)
+	}
+	if !strings.EqualFold(base.XMLName.Local, extra.XMLName.Local) {
+		return "", fmt.Errorf("cannot merge <%s> NFO into <%s> NFO", extra.XMLName.Local, base.XMLName.Local)
+	}
+
+	present := make(map[string]bool, len(base.Elements))
+	for _, el := range base.Elements {
+		present[strings.ToLower(el.XMLName.Local)] = true
+	}
+	for _, el := range extra.Elements {
+		if !present[strings.ToLower(el.XMLName.Local)] {
+			base.Elements = append(base.Elements, el)
+		}
+	}
+
+	return marshalNFO(base)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/opd-ai/go-jf-org/pkg/types"
@@ -12,84 +13,452 @@ import (
 // spaceRegex is compiled once for performance in SanitizeFilename
 var spaceRegex = regexp.MustCompile(`\s+`)
 
+// MovieLayout controls how movie files are placed under the destination root
+type MovieLayout string
+
+const (
+	// MovieLayoutFolder places each movie in its own "Movie Name (Year)/" folder
+	MovieLayoutFolder MovieLayout = "folder"
+	// MovieLayoutFlat places movie files directly in the destination root
+	MovieLayoutFlat MovieLayout = "flat"
+)
+
+// MiniSeriesLayout controls where episodes numbered without a season go
+type MiniSeriesLayout string
+
+const (
+	// MiniSeriesLayoutSeason places them under "Show/Season 01/" like any episode
+	MiniSeriesLayoutSeason MiniSeriesLayout = "season"
+	// MiniSeriesLayoutShow places them directly in "Show/", which Jellyfin
+	// reads as a show with a single season
+	MiniSeriesLayoutShow MiniSeriesLayout = "show"
+)
+
+// DestStructure selects how each media type's library is laid out
+type DestStructure string
+
+const (
+	// StructureNested is Jellyfin's usual layout of per-title folders
+	// ("Show/Season 01/", "Artist/Album (Year)/")
+	StructureNested DestStructure = "nested"
+	// StructureFlatByType puts every file of a type directly in its library
+	// root under a descriptive name ("TV/Show - S01E01 - Title.mkv")
+	StructureFlatByType DestStructure = "flat-by-type"
+)
+
+// MusicLayout controls how music is grouped under the destination root
+type MusicLayout string
+
+const (
+	// MusicLayoutArtist places music under "Artist/Album (Year)/"
+	MusicLayoutArtist MusicLayout = "artist"
+	// MusicLayoutDecadeArtist places music under "1970s/Artist/Album (Year)/"
+	MusicLayoutDecadeArtist MusicLayout = "decade-artist"
+)
+
+// LooseTrackLayout controls where music tracks without album metadata go
+type LooseTrackLayout string
+
+const (
+	// LooseTrackUnknownAlbum places album-less tracks under "Artist/Unknown Album/"
+	LooseTrackUnknownAlbum LooseTrackLayout = "unknown-album"
+	// LooseTrackSingles places album-less tracks under "Artist/Singles/"
+	LooseTrackSingles LooseTrackLayout = "singles"
+	// LooseTrackCompilations places album-less tracks under
+	// "Various Artists/Compilations/", named "Artist - Title.ext"
+	LooseTrackCompilations LooseTrackLayout = "compilations"
+)
+
+// UnknownDecade is the decade folder used for music without a year
+const UnknownDecade = "Unknown"
+
+// UnknownYearPolicy controls how movies, albums and books without a year
+// are named
+type UnknownYearPolicy string
+
+const (
+	// UnknownYearOmit leaves the "(Year)" suffix out: "Some Movie/"
+	UnknownYearOmit UnknownYearPolicy = "omit"
+	// UnknownYearPlaceholder writes a placeholder instead: "Some Movie (0000)/"
+	UnknownYearPlaceholder UnknownYearPolicy = "placeholder"
+	// UnknownYearQuarantine refuses to organize the file, leaving it in place
+	UnknownYearQuarantine UnknownYearPolicy = "quarantine"
+)
+
 // Naming provides Jellyfin-compatible naming conventions for media files
-type Naming struct{}
+type Naming struct {
+	movieLayout          MovieLayout
+	miniSeriesLayout     MiniSeriesLayout
+	musicLayout          MusicLayout
+	looseTrackLayout     LooseTrackLayout
+	sortArticles         []string
+	episodeTitleFallback string
+	asciiFold            bool
+	unmappedMode         UnmappedMode
+	idTokens             bool
+	yearPlaceholder      string
+	structure            DestStructure
+	shardTypes           map[types.MediaType]bool
+	shardArticles        []string
+	extrasLayout         ExtrasLayout
+	style                FilenameStyle
+}
 
 // NewNaming creates a new Naming instance
 func NewNaming() *Naming {
-	return &Naming{}
+	return &Naming{
+		movieLayout:      MovieLayoutFolder,
+		miniSeriesLayout: MiniSeriesLayoutSeason,
+		musicLayout:      MusicLayoutArtist,
+		looseTrackLayout: LooseTrackUnknownAlbum,
+		structure:        StructureNested,
+		extrasLayout:     ExtrasLayoutSuffix,
+	}
+}
+
+// SetMusicLayout sets the music layout (artist or decade-artist)
+func (n *Naming) SetMusicLayout(layout MusicLayout) {
+	if layout == "" {
+		layout = MusicLayoutArtist
+	}
+	n.musicLayout = layout
+}
+
+// SetLooseTrackLayout sets where tracks without album metadata are placed
+// (unknown-album, singles or compilations)
+func (n *Naming) SetLooseTrackLayout(layout LooseTrackLayout) {
+	if layout == "" {
+		layout = LooseTrackUnknownAlbum
+	}
+	n.looseTrackLayout = layout
+}
+
+// LooseTrackLayout returns the configured loose track layout
+func (n *Naming) LooseTrackLayout() LooseTrackLayout {
+	return n.looseTrackLayout
+}
+
+// IsLooseTrack reports whether a music track has no album and is therefore
+// placed according to the loose track layout
+func IsLooseTrack(metadata *types.Metadata) bool {
+	return metadata != nil && metadata.MusicMetadata != nil && strings.TrimSpace(metadata.MusicMetadata.Album) == ""
+}
+
+// GetMusicDecadeDir returns the decade folder for a year ("1970s"), or
+// "Unknown" when the year is not set
+func (n *Naming) GetMusicDecadeDir(year int) string {
+	if year <= 0 {
+		return UnknownDecade
+	}
+	return fmt.Sprintf("%ds", year/10*10)
+}
+
+// SetEpisodeTitleFallback sets the template used as the episode title when
+// none is known, e.g. "Episode {episode}". Supported tokens are {show},
+// {season} and {episode}. An empty template leaves the title off.
+func (n *Naming) SetEpisodeTitleFallback(template string) {
+	n.episodeTitleFallback = template
+}
+
+// fallbackEpisodeTitle expands the episode title fallback template
+func (n *Naming) fallbackEpisodeTitle(tv *types.TVMetadata) string {
+	if n.episodeTitleFallback == "" {
+		return ""
+	}
+	replacer := strings.NewReplacer(
+		"{show}", tv.ShowTitle,
+		"{season}", strconv.Itoa(tv.Season),
+		"{episode}", strconv.Itoa(tv.Episode),
+	)
+	return strings.TrimSpace(replacer.Replace(n.episodeTitleFallback))
+}
+
+// SetMovieLayout sets the movie layout (folder or flat)
+func (n *Naming) SetMovieLayout(layout MovieLayout) {
+	if layout == "" {
+		layout = MovieLayoutFolder
+	}
+	n.movieLayout = layout
+}
+
+// SetMiniSeriesLayout sets where mini-series episodes go (season or show)
+func (n *Naming) SetMiniSeriesLayout(layout MiniSeriesLayout) {
+	if layout == "" {
+		layout = MiniSeriesLayoutSeason
+	}
+	n.miniSeriesLayout = layout
+}
+
+// SetDestStructure sets the library layout (nested or flat-by-type)
+func (n *Naming) SetDestStructure(structure DestStructure) {
+	if structure == "" {
+		structure = StructureNested
+	}
+	n.structure = structure
+}
+
+// DestStructure returns the configured library layout
+func (n *Naming) DestStructure() DestStructure {
+	return n.structure
+}
+
+// SetSortArticles sets the leading articles moved to the end of movie and
+// show folder names ("Matrix, The (1999)"); nil or empty disables it
+func (n *Naming) SetSortArticles(articles []string) {
+	n.sortArticles = articles
+}
+
+// SetFirstLetterShards groups the top-level folders of the given media types
+// into first-letter buckets ("Movies/M/The Matrix (1999)/", "Music/B/The
+// Beatles/"), skipping articles when picking the letter; nil articles use
+// DefaultSortArticles. Flat layouts are not sharded.
+func (n *Naming) SetFirstLetterShards(mediaTypes []types.MediaType, articles []string) {
+	n.shardTypes = make(map[types.MediaType]bool, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		n.shardTypes[mediaType] = true
+	}
+	if articles == nil {
+		articles = DefaultSortArticles
+	}
+	n.shardArticles = articles
+}
+
+// shardRoot returns the directory a media type's top-level folder goes in:
+// its first-letter bucket under root when sharding is on for the type, or
+// root itself
+func (n *Naming) shardRoot(root string, mediaType types.MediaType, folder string) string {
+	if !n.shardTypes[mediaType] {
+		return root
+	}
+	return filepath.Join(root, FirstLetterBucket(folder, n.shardArticles))
+}
+
+// SetASCIIFold enables transliterating folder and file names to ASCII
+// ("Amélie" → "Amelie"); mode decides what happens to characters with no
+// ASCII equivalent. NFO titles are not affected.
+func (n *Naming) SetASCIIFold(enabled bool, mode UnmappedMode) {
+	if mode == "" {
+		mode = UnmappedKeep
+	}
+	n.asciiFold = enabled
+	n.unmappedMode = mode
+}
+
+// SetIDTokens enables appending a provider ID token ("[tmdbid-603]" or
+// "[imdbid-tt0133093]") to movie and show names when the ID is known, so
+// Jellyfin matches them exactly
+func (n *Naming) SetIDTokens(enabled bool) {
+	n.idTokens = enabled
+}
+
+// SetUnknownYearPlaceholder sets the text written in place of a missing year
+// in movie, album and book names ("0000" gives "Some Movie (0000)"); ""
+// omits the "(Year)" suffix
+func (n *Naming) SetUnknownYearPlaceholder(placeholder string) {
+	n.yearPlaceholder = SanitizeFilename(placeholder)
+}
+
+// SetFilenameStyle sets the separator and case of generated filenames
+// (naming.separator, naming.case); folders are unaffected
+func (n *Naming) SetFilenameStyle(style FilenameStyle) {
+	n.style = style
+}
+
+// FilenameStyle returns the configured filename style
+func (n *Naming) FilenameStyle() FilenameStyle {
+	return n.style
+}
+
+// styled applies the filename style to a stem and appends ext
+func (n *Naming) styled(stem, ext string) string {
+	return n.style.Apply(stem) + ext
+}
+
+// withYear appends " (Year)" to name, or the unknown-year placeholder when
+// the year is not known
+func (n *Naming) withYear(name string, year int) string {
+	switch {
+	case year > 0:
+		return fmt.Sprintf("%s (%d)", name, year)
+	case n.yearPlaceholder != "":
+		return fmt.Sprintf("%s (%s)", name, n.yearPlaceholder)
+	default:
+		return name
+	}
+}
+
+// idToken returns the " [tmdbid-...]" suffix for a known ID, preferring TMDB,
+// or "" when ID tokens are disabled or no ID is known
+func (n *Naming) idToken(tmdbID int, imdbID string) string {
+	switch {
+	case !n.idTokens:
+		return ""
+	case tmdbID > 0:
+		return fmt.Sprintf(" [tmdbid-%d]", tmdbID)
+	case imdbID != "":
+		return fmt.Sprintf(" [imdbid-%s]", SanitizeFilename(imdbID))
+	default:
+		return ""
+	}
+}
+
+// movieIDToken returns the ID token for a movie, if any
+func (n *Naming) movieIDToken(metadata *types.Metadata) string {
+	if metadata.MovieMetadata == nil {
+		return ""
+	}
+	return n.idToken(metadata.MovieMetadata.TMDBID, metadata.MovieMetadata.IMDBID)
+}
+
+// sanitize makes s safe for use in a path component, folding it to ASCII
+// when enabled
+func (n *Naming) sanitize(s string) string {
+	if n.asciiFold {
+		s = FoldASCII(s, n.unmappedMode)
+	}
+	return SanitizeFilename(s)
+}
+
+// sortFolderTitle applies article sorting to a sanitized folder title
+func (n *Naming) sortFolderTitle(title string) string {
+	if len(n.sortArticles) == 0 {
+		return title
+	}
+	return MoveArticleToEnd(title, n.sortArticles)
+}
+
+// MovieLayout returns the configured movie layout; the flat-by-type
+// structure always lays movies out flat
+func (n *Naming) MovieLayout() MovieLayout {
+	if n.structure == StructureFlatByType {
+		return MovieLayoutFlat
+	}
+	return n.movieLayout
 }
 
 // GetMovieName returns the Jellyfin-compatible filename for a movie
-// Format: "Movie Name (Year).ext"
+// Format: "Movie Name (Year).ext", or "Movie Name (Year) [tmdbid-603].ext"
+// with ID tokens enabled
 func (n *Naming) GetMovieName(metadata *types.Metadata, ext string) string {
 	if metadata == nil || metadata.Title == "" {
 		return ""
 	}
 
-	title := SanitizeFilename(metadata.Title)
+	title := n.sanitize(metadata.Title)
+	token := n.movieIDToken(metadata)
 
-	if metadata.Year > 0 {
-		return fmt.Sprintf("%s (%d)%s", title, metadata.Year, ext)
-	}
-
-	return fmt.Sprintf("%s%s", title, ext)
+	return n.styled(n.withYear(title, metadata.Year)+token, ext)
 }
 
 // GetMovieDir returns the Jellyfin-compatible directory name for a movie
-// Format: "Movie Name (Year)/"
+// Format: "Movie Name (Year)/", or "Movie Name (Year) [tmdbid-603]/" with ID
+// tokens enabled
 func (n *Naming) GetMovieDir(metadata *types.Metadata) string {
 	if metadata == nil || metadata.Title == "" {
 		return ""
 	}
 
-	title := SanitizeFilename(metadata.Title)
+	title := n.sortFolderTitle(n.sanitize(metadata.Title))
+	token := n.movieIDToken(metadata)
+
+	return n.withYear(title, metadata.Year) + token
+}
+
+// GetMovieNFOPath returns the NFO path for an organized movie file
+// Folder layout: "<dir>/movie.nfo", flat layout: "<dir>/Movie Name (Year).nfo"
+func (n *Naming) GetMovieNFOPath(moviePath string) string {
+	dir := filepath.Dir(moviePath)
+	if n.MovieLayout() == MovieLayoutFlat {
+		return filepath.Join(dir, movieStem(moviePath)+".nfo")
+	}
+	return filepath.Join(dir, "movie.nfo")
+}
 
-	if metadata.Year > 0 {
-		return fmt.Sprintf("%s (%d)", title, metadata.Year)
+// GetMovieArtworkPath returns the artwork path (kind is "poster", "backdrop", etc.)
+// for an organized movie file
+// Folder layout: "<dir>/poster.jpg", flat layout: "<dir>/Movie Name (Year)-poster.jpg"
+func (n *Naming) GetMovieArtworkPath(moviePath, kind string) string {
+	dir := filepath.Dir(moviePath)
+	if n.MovieLayout() == MovieLayoutFlat {
+		return filepath.Join(dir, fmt.Sprintf("%s-%s.jpg", movieStem(moviePath), kind))
 	}
+	return filepath.Join(dir, kind+".jpg")
+}
+
+// movieStem returns the movie filename without directory and extension
+func movieStem(moviePath string) string {
+	base := filepath.Base(moviePath)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
 
-	return title
+// EpisodeCode returns the season/episode marker of an episode: "S01E02", or
+// "S01E01-E03" when the file spans several episodes
+func EpisodeCode(tv *types.TVMetadata) string {
+	code := fmt.Sprintf("S%02dE%02d", tv.Season, tv.Episode)
+	if tv.EpisodeEnd > tv.Episode {
+		code += fmt.Sprintf("-E%02d", tv.EpisodeEnd)
+	}
+	return code
 }
 
 // GetTVShowName returns the Jellyfin-compatible filename for a TV episode
-// Format: "Show Name - S##E## - Episode Title.ext"
+// Format: "Show Name - S##E## - Episode Title.ext", or for daily shows
+// identified only by air date: "Show Name - YYYY-MM-DD - Episode Title.ext"
 func (n *Naming) GetTVShowName(metadata *types.Metadata, ext string) string {
 	if metadata == nil || metadata.TVMetadata == nil {
 		return ""
 	}
 
 	tv := metadata.TVMetadata
-	show := SanitizeFilename(tv.ShowTitle)
+	show := n.sanitize(tv.ShowTitle)
 
 	if show == "" {
 		return ""
 	}
 
-	// Base format: "Show Name - S##E##"
-	name := fmt.Sprintf("%s - S%02dE%02d", show, tv.Season, tv.Episode)
+	if isDailyEpisode(tv) {
+		name := fmt.Sprintf("%s - %s", show, tv.AirDate)
+		if tv.EpisodeTitle != "" {
+			name = fmt.Sprintf("%s - %s", name, n.sanitize(tv.EpisodeTitle))
+		}
+		return n.styled(name, ext)
+	}
 
-	// Add episode title if available
-	if tv.EpisodeTitle != "" {
-		episodeTitle := SanitizeFilename(tv.EpisodeTitle)
-		name = fmt.Sprintf("%s - %s", name, episodeTitle)
+	// Base format: "Show Name - S##E##" (or "S##E##-E##" for multi-episode files)
+	name := fmt.Sprintf("%s - %s", show, EpisodeCode(tv))
+
+	// Add episode title if available, otherwise the configured placeholder
+	episodeTitle := tv.EpisodeTitle
+	if episodeTitle == "" {
+		episodeTitle = n.fallbackEpisodeTitle(tv)
+	}
+	if episodeTitle != "" {
+		name = fmt.Sprintf("%s - %s", name, n.sanitize(episodeTitle))
 	}
 
-	return name + ext
+	return n.styled(name, ext)
+}
+
+// isDailyEpisode reports whether an episode is identified by its air date
+// rather than an episode number (daily shows such as talk shows and news)
+func isDailyEpisode(tv *types.TVMetadata) bool {
+	return tv.Episode == 0 && tv.AirDate != ""
 }
 
 // GetTVShowDir returns the Jellyfin-compatible show directory name
-// Format: "Show Name/"
+// Format: "Show Name/", or "Show Name [tmdbid-1396]/" with ID tokens enabled
 func (n *Naming) GetTVShowDir(metadata *types.Metadata) string {
 	if metadata == nil || metadata.TVMetadata == nil {
 		return ""
 	}
 
-	return SanitizeFilename(metadata.TVMetadata.ShowTitle)
+	tv := metadata.TVMetadata
+	return n.sortFolderTitle(n.sanitize(tv.ShowTitle)) + n.idToken(tv.TMDBID, tv.IMDBID)
 }
 
 // GetTVSeasonDir returns the Jellyfin-compatible season directory name
-// Format: "Season ##/" or "Specials/" for season 0
+// Format: "Season ##/" or "Specials/" for season 0; daily shows use the
+// air date's year as season, giving "Season 2023/"
 func (n *Naming) GetTVSeasonDir(season int) string {
 	if season == 0 {
 		return "Specials"
@@ -98,51 +467,63 @@ func (n *Naming) GetTVSeasonDir(season int) string {
 }
 
 // GetMusicDir returns the Jellyfin-compatible music directory structure
-// Format: "Artist Name/Album Name (Year)/"
+// Format: "Artist Name/Album Name (Year)/". Tracks without an album go to
+// "Artist Name/Unknown Album (Year)/", "Artist Name/Singles/" or
+// "Various Artists/Compilations/" depending on the loose track layout.
 func (n *Naming) GetMusicDir(metadata *types.Metadata) (artist, album string) {
 	if metadata == nil || metadata.MusicMetadata == nil {
 		return "", ""
 	}
 
 	music := metadata.MusicMetadata
-	artist = SanitizeFilename(music.Artist)
+	artist = n.sanitize(music.Artist)
 	if artist == "" {
 		artist = "Unknown Artist"
 	}
 
-	albumName := SanitizeFilename(music.Album)
-	if albumName == "" {
-		albumName = "Unknown Album"
+	if IsLooseTrack(metadata) {
+		switch n.looseTrackLayout {
+		case LooseTrackSingles:
+			return artist, "Singles"
+		case LooseTrackCompilations:
+			return "Various Artists", "Compilations"
+		}
 	}
 
-	if metadata.Year > 0 {
-		album = fmt.Sprintf("%s (%d)", albumName, metadata.Year)
-	} else {
-		album = albumName
+	albumName := n.sanitize(music.Album)
+	if albumName == "" {
+		albumName = "Unknown Album"
 	}
 
-	return artist, album
+	return artist, n.withYear(albumName, metadata.Year)
 }
 
 // GetMusicTrackName returns the Jellyfin-compatible track filename
-// Format: "## - Track Name.ext"
+// Format: "## - Track Name.ext"; loose tracks in the compilations bucket are
+// named "Artist - Track Name.ext" since the folder no longer names the artist
 func (n *Naming) GetMusicTrackName(metadata *types.Metadata, ext string) string {
 	if metadata == nil || metadata.MusicMetadata == nil {
 		return ""
 	}
 
 	music := metadata.MusicMetadata
-	title := SanitizeFilename(metadata.Title)
+	title := n.sanitize(metadata.Title)
 
 	if title == "" {
 		title = "Unknown Track"
 	}
 
+	if n.looseTrackLayout == LooseTrackCompilations && IsLooseTrack(metadata) {
+		if artist := n.sanitize(music.Artist); artist != "" {
+			title = artist + " - " + title
+		}
+	}
+
 	if music.TrackNumber > 0 {
-		return fmt.Sprintf("%02d - %s%s", music.TrackNumber, title, ext)
+		return n.styled(fmt.Sprintf("%02d - %s", music.TrackNumber, title), ext)
 	}
 
-	return title + ext
+	return n.styled(title, ext)
 }
 
 // GetBookDir returns the Jellyfin-compatible book directory structure
@@ -152,7 +533,7 @@ func (n *Naming) GetBookDir(metadata *types.Metadata) (author, book string) {
 		return "", ""
 	}
 
-	authorName := SanitizeFilename(metadata.BookMetadata.Author)
+	authorName := n.sanitize(metadata.BookMetadata.Author)
 	if authorName == "" {
 		authorName = "Unknown Author"
 	}
@@ -160,18 +541,12 @@ func (n *Naming) GetBookDir(metadata *types.Metadata) (author, book string) {
 	// Try to format as "Last, First" if possible
 	author = FormatAuthorName(authorName)
 
-	title := SanitizeFilename(metadata.Title)
+	title := n.sanitize(metadata.Title)
 	if title == "" {
 		title = "Unknown Book"
 	}
 
-	if metadata.Year > 0 {
-		book = fmt.Sprintf("%s (%d)", title, metadata.Year)
-	} else {
-		book = title
-	}
-
-	return author, book
+	return author, n.withYear(title, metadata.Year)
 }
 
 // GetBookName returns the Jellyfin-compatible book filename
@@ -181,12 +556,12 @@ func (n *Naming) GetBookName(metadata *types.Metadata, ext string) string {
 		return ""
 	}
 
-	title := SanitizeFilename(metadata.Title)
+	title := n.sanitize(metadata.Title)
 	if title == "" {
 		title = "Unknown Book"
 	}
 
-	return title + ext
+	return n.styled(title, ext)
 }
 
 // SanitizeFilename removes or replaces characters that are invalid in filenames
@@ -250,6 +625,10 @@ func FormatAuthorName(author string) string {
 
 // BuildFullPath constructs the full path for a media file based on its type and metadata
 func (n *Naming) BuildFullPath(destRoot string, mediaType types.MediaType, metadata *types.Metadata, ext string) string {
+	if n.structure == StructureFlatByType {
+		return n.BuildFlatPath(destRoot, mediaType, metadata, ext)
+	}
+
 	switch mediaType {
 	case types.MediaTypeMovie:
 		dir := n.GetMovieDir(metadata)
@@ -257,7 +636,14 @@ func (n *Naming) BuildFullPath(destRoot string, mediaType types.MediaType, metad
 		if dir == "" || filename == "" {
 			return ""
 		}
-		return filepath.Join(destRoot, dir, filename)
+		moviePath := filepath.Join(n.shardRoot(destRoot, mediaType, dir), dir, filename)
+		if n.MovieLayout() == MovieLayoutFlat {
+			moviePath = filepath.Join(destRoot, filename)
+		}
+		if metadata.MovieMetadata != nil && metadata.MovieMetadata.ExtraType != "" {
+			return n.GetMovieExtraPath(moviePath, metadata.MovieMetadata.ExtraType, ext)
+		}
+		return moviePath
 
 	case types.MediaTypeTV:
 		if metadata.TVMetadata == nil {
@@ -269,7 +655,11 @@ func (n *Naming) BuildFullPath(destRoot string, mediaType types.MediaType, metad
 		if showDir == "" || filename == "" {
 			return ""
 		}
-		return filepath.Join(destRoot, showDir, seasonDir, filename)
+		showRoot := n.shardRoot(destRoot, mediaType, showDir)
+		if metadata.TVMetadata.MiniSeries && n.miniSeriesLayout == MiniSeriesLayoutShow {
+			return filepath.Join(showRoot, showDir, filename)
+		}
+		return filepath.Join(showRoot, showDir, seasonDir, filename)
 
 	case types.MediaTypeMusic:
 		artistDir, albumDir := n.GetMusicDir(metadata)
@@ -277,7 +667,10 @@ func (n *Naming) BuildFullPath(destRoot string, mediaType types.MediaType, metad
 		if artistDir == "" || filename == "" {
 			return ""
 		}
-		return filepath.Join(destRoot, artistDir, albumDir, filename)
+		if n.musicLayout == MusicLayoutDecadeArtist {
+			destRoot = filepath.Join(destRoot, n.GetMusicDecadeDir(metadata.Year))
+		}
+		return filepath.Join(n.shardRoot(destRoot, mediaType, artistDir), artistDir, albumDir, filename)
 
 	case types.MediaTypeBook:
 		authorDir, bookDir := n.GetBookDir(metadata)
@@ -285,9 +678,78 @@ func (n *Naming) BuildFullPath(destRoot string, mediaType types.MediaType, metad
 		if authorDir == "" || filename == "" {
 			return ""
 		}
-		return filepath.Join(destRoot, authorDir, bookDir, filename)
+		return filepath.Join(n.shardRoot(destRoot, mediaType, authorDir), authorDir, bookDir, filename)
 
 	default:
 		return ""
 	}
 }
+
+// BuildFlatPath constructs the flat-by-type path for a media file: every
+// file sits directly in destRoot under a name that carries what the nested
+// folders would have said.
+//
+//	Movie: "Movie Name (Year).ext"
+//	TV:    "Show Name - S01E01 - Episode Title.ext"
+//	Music: "Artist - Album (Year) - 01 - Track.ext", or "Artist - Track.ext"
+//	       for tracks without an album
+//	Book:  "Author - Book Title (Year).ext"
+func (n *Naming) BuildFlatPath(destRoot string, mediaType types.MediaType, metadata *types.Metadata, ext string) string {
+	if metadata == nil {
+		return ""
+	}
+
+	var filename string
+	switch mediaType {
+	case types.MediaTypeMovie:
+		filename = n.GetMovieName(metadata, ext)
+		if filename != "" && metadata.MovieMetadata != nil && metadata.MovieMetadata.ExtraType != "" {
+			filename = strings.TrimSuffix(filename, ext) + "-" + metadata.MovieMetadata.ExtraType + ext
+		}
+
+	case types.MediaTypeTV:
+		filename = n.GetTVShowName(metadata, ext)
+
+	case types.MediaTypeMusic:
+		if metadata.MusicMetadata == nil {
+			return ""
+		}
+		artist := n.sanitize(metadata.MusicMetadata.Artist)
+		if artist == "" {
+			artist = "Unknown Artist"
+		}
+		title := n.sanitize(metadata.Title)
+		if title == "" {
+			title = "Unknown Track"
+		}
+		if IsLooseTrack(metadata) {
+			filename = n.styled(fmt.Sprintf("%s - %s", artist, title), ext)
+			break
+		}
+		_, album := n.GetMusicDir(metadata)
+		if track := metadata.MusicMetadata.TrackNumber; track > 0 {
+			filename = n.styled(fmt.Sprintf("%s - %s - %02d - %s", artist, album, track, title), ext)
+		} else {
+			filename = n.styled(fmt.Sprintf("%s - %s - %s", artist, album, title), ext)
+		}
+
+	case types.MediaTypeBook:
+		if metadata.BookMetadata == nil {
+			return ""
+		}
+		title := n.sanitize(metadata.Title)
+		if title == "" {
+			title = "Unknown Book"
+		}
+		stem := n.withYear(title, metadata.Year)
+		if author := n.sanitize(metadata.BookMetadata.Author); author != "" {
+			stem = author + " - " + stem
+		}
+		filename = n.styled(stem, ext)
+	}
+
+	if filename == "" {
+		return ""
+	}
+	return filepath.Join(destRoot, filename)
+}
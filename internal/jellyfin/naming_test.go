@@ -156,6 +156,44 @@ func TestGetTVShowName(t *testing.T) {
 			ext:  ".mkv",
 			want: "Doctor Who - S00E01 - Christmas Special.mkv",
 		},
+		{
+			name: "multi-episode file",
+			metadata: &types.Metadata{
+				TVMetadata: &types.TVMetadata{
+					ShowTitle:  "Show",
+					Season:     1,
+					Episode:    1,
+					EpisodeEnd: 3,
+				},
+			},
+			ext:  ".mkv",
+			want: "Show - S01E01-E03.mkv",
+		},
+		{
+			name: "daily show by air date",
+			metadata: &types.Metadata{
+				TVMetadata: &types.TVMetadata{
+					ShowTitle: "The Daily Show",
+					Season:    2023,
+					AirDate:   "2023-05-15",
+				},
+			},
+			ext:  ".mkv",
+			want: "The Daily Show - 2023-05-15.mkv",
+		},
+		{
+			name: "daily show with episode title",
+			metadata: &types.Metadata{
+				TVMetadata: &types.TVMetadata{
+					ShowTitle:    "The Daily Show",
+					Season:       2023,
+					AirDate:      "2023-05-15",
+					EpisodeTitle: "Guest Name",
+				},
+			},
+			ext:  ".mkv",
+			want: "The Daily Show - 2023-05-15 - Guest Name.mkv",
+		},
 		{
 			name:     "nil TV metadata",
 			metadata: &types.Metadata{},
@@ -174,6 +212,53 @@ func TestGetTVShowName(t *testing.T) {
 	}
 }
 
+func TestGetTVShowName_EpisodeTitleFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		tv       types.TVMetadata
+		want     string
+	}{
+		{
+			name:     "no fallback keeps current behavior",
+			template: "",
+			tv:       types.TVMetadata{ShowTitle: "Show", Season: 1, Episode: 1},
+			want:     "Show - S01E01.mkv",
+		},
+		{
+			name:     "fallback used when title missing",
+			template: "Episode {episode}",
+			tv:       types.TVMetadata{ShowTitle: "Show", Season: 1, Episode: 1},
+			want:     "Show - S01E01 - Episode 1.mkv",
+		},
+		{
+			name:     "fallback with all tokens",
+			template: "{show} {season}x{episode}",
+			tv:       types.TVMetadata{ShowTitle: "Show", Season: 2, Episode: 12},
+			want:     "Show - S02E12 - Show 2x12.mkv",
+		},
+		{
+			name:     "real title wins over fallback",
+			template: "Episode {episode}",
+			tv:       types.TVMetadata{ShowTitle: "Show", Season: 1, Episode: 1, EpisodeTitle: "Pilot"},
+			want:     "Show - S01E01 - Pilot.mkv",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNaming()
+			n.SetEpisodeTitleFallback(tt.template)
+
+			tv := tt.tv
+			got := n.GetTVShowName(&types.Metadata{TVMetadata: &tv}, ".mkv")
+			if got != tt.want {
+				t.Errorf("GetTVShowName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetTVSeasonDir(t *testing.T) {
 	n := NewNaming()
 
@@ -505,6 +590,20 @@ func TestBuildFullPath(t *testing.T) {
 			ext:  ".mkv",
 			want: filepath.Join("/media/tv", "Breaking Bad", "Season 01", "Breaking Bad - S01E01 - Pilot.mkv"),
 		},
+		{
+			name:      "daily tv show",
+			destRoot:  "/media/tv",
+			mediaType: types.MediaTypeTV,
+			metadata: &types.Metadata{
+				TVMetadata: &types.TVMetadata{
+					ShowTitle: "The Daily Show",
+					Season:    2023,
+					AirDate:   "2023-05-15",
+				},
+			},
+			ext:  ".mkv",
+			want: filepath.Join("/media/tv", "The Daily Show", "Season 2023", "The Daily Show - 2023-05-15.mkv"),
+		},
 		{
 			name:      "music",
 			destRoot:  "/media/music",
@@ -556,3 +655,486 @@ func TestBuildFullPath(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildFullPath_FlatMovieLayout(t *testing.T) {
+	n := NewNaming()
+	n.SetMovieLayout(MovieLayoutFlat)
+
+	metadata := &types.Metadata{
+		Title: "The Matrix",
+		Year:  1999,
+	}
+
+	got := n.BuildFullPath("/media/movies", types.MediaTypeMovie, metadata, ".mkv")
+	want := filepath.Join("/media/movies", "The Matrix (1999).mkv")
+	if got != want {
+		t.Errorf("BuildFullPath() = %q, want %q", got, want)
+	}
+
+	// Flat layout only affects movies
+	tvMetadata := &types.Metadata{
+		TVMetadata: &types.TVMetadata{
+			ShowTitle: "Breaking Bad",
+			Season:    1,
+			Episode:   1,
+		},
+	}
+	got = n.BuildFullPath("/media/tv", types.MediaTypeTV, tvMetadata, ".mkv")
+	want = filepath.Join("/media/tv", "Breaking Bad", "Season 01", "Breaking Bad - S01E01.mkv")
+	if got != want {
+		t.Errorf("BuildFullPath() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFullPath_MiniSeriesLayout(t *testing.T) {
+	metadata := &types.Metadata{
+		TVMetadata: &types.TVMetadata{
+			ShowTitle:  "Chernobyl",
+			Season:     1,
+			Episode:    1,
+			MiniSeries: true,
+		},
+	}
+
+	n := NewNaming()
+	got := n.BuildFullPath("/media/tv", types.MediaTypeTV, metadata, ".mkv")
+	want := filepath.Join("/media/tv", "Chernobyl", "Season 01", "Chernobyl - S01E01.mkv")
+	if got != want {
+		t.Errorf("season layout: BuildFullPath() = %q, want %q", got, want)
+	}
+
+	n.SetMiniSeriesLayout(MiniSeriesLayoutShow)
+	got = n.BuildFullPath("/media/tv", types.MediaTypeTV, metadata, ".mkv")
+	want = filepath.Join("/media/tv", "Chernobyl", "Chernobyl - S01E01.mkv")
+	if got != want {
+		t.Errorf("show layout: BuildFullPath() = %q, want %q", got, want)
+	}
+
+	// Regular episodes keep their season folder under the show layout
+	metadata.TVMetadata.MiniSeries = false
+	got = n.BuildFullPath("/media/tv", types.MediaTypeTV, metadata, ".mkv")
+	want = filepath.Join("/media/tv", "Chernobyl", "Season 01", "Chernobyl - S01E01.mkv")
+	if got != want {
+		t.Errorf("regular episode: BuildFullPath() = %q, want %q", got, want)
+	}
+}
+
+func TestFirstLetterBucket(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Avatar (2009)", "A"},
+		{"The Matrix (1999)", "M"},
+		{"Matrix, The (1999)", "M"},
+		{"an American Tail (1986)", "A"},
+		{"Élan (2020)", "E"},
+		{"2001 A Space Odyssey (1968)", NonAlphaBucket},
+		{"[REC] (2007)", NonAlphaBucket},
+		{"The", "T"},
+	}
+	for _, tt := range tests {
+		if got := FirstLetterBucket(tt.name, DefaultSortArticles); got != tt.want {
+			t.Errorf("FirstLetterBucket(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	for name, want := range map[string]bool{"A": true, "#": true, "Z": true, "a": false, "AB": false, "1": false, "": false} {
+		if got := IsFirstLetterBucket(name); got != want {
+			t.Errorf("IsFirstLetterBucket(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestBuildFullPath_FirstLetterShards(t *testing.T) {
+	movie := &types.Metadata{Title: "The Matrix", Year: 1999}
+	show := &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "Breaking Bad", Season: 1, Episode: 1}}
+	track := &types.Metadata{
+		Title:         "Come Together",
+		Year:          1969,
+		MusicMetadata: &types.MusicMetadata{Artist: "The Beatles", Album: "Abbey Road", TrackNumber: 1},
+	}
+	book := &types.Metadata{Title: "The Hobbit", Year: 1937, BookMetadata: &types.BookMetadata{Author: "J.R.R. Tolkien"}}
+
+	n := NewNaming()
+	n.SetFirstLetterShards([]types.MediaType{types.MediaTypeMovie, types.MediaTypeMusic, types.MediaTypeBook}, nil)
+
+	tests := []struct {
+		mediaType types.MediaType
+		metadata  *types.Metadata
+		ext       string
+		want      string
+	}{
+		{types.MediaTypeMovie, movie, ".mkv", filepath.Join("/media", "M", "The Matrix (1999)", "The Matrix (1999).mkv")},
+		// TV is not sharded here
+		{types.MediaTypeTV, show, ".mkv", filepath.Join("/media", "Breaking Bad", "Season 01", "Breaking Bad - S01E01.mkv")},
+		{types.MediaTypeMusic, track, ".flac", filepath.Join("/media", "B", "The Beatles", "Abbey Road (1969)", "01 - Come Together.flac")},
+		{types.MediaTypeBook, book, ".epub", filepath.Join("/media", "T", "Tolkien, J.R.R.", "The Hobbit (1937)", "The Hobbit.epub")},
+	}
+	for _, tt := range tests {
+		if got := n.BuildFullPath("/media", tt.mediaType, tt.metadata, tt.ext); got != tt.want {
+			t.Errorf("BuildFullPath(%s) = %q, want %q", tt.mediaType, got, tt.want)
+		}
+	}
+
+	// The bucket sits between the decade and the artist
+	n.SetMusicLayout(MusicLayoutDecadeArtist)
+	want := filepath.Join("/media", "1960s", "B", "The Beatles", "Abbey Road (1969)", "01 - Come Together.flac")
+	if got := n.BuildFullPath("/media", types.MediaTypeMusic, track, ".flac"); got != want {
+		t.Errorf("decade-artist: BuildFullPath() = %q, want %q", got, want)
+	}
+
+	// Flat movies stay in the root
+	n.SetMovieLayout(MovieLayoutFlat)
+	want = filepath.Join("/media", "The Matrix (1999).mkv")
+	if got := n.BuildFullPath("/media", types.MediaTypeMovie, movie, ".mkv"); got != want {
+		t.Errorf("flat: BuildFullPath() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFullPath_MovieExtra(t *testing.T) {
+	trailer := &types.Metadata{
+		Title:         "Movie",
+		Year:          2020,
+		MovieMetadata: &types.MovieMetadata{ExtraType: "trailer"},
+	}
+
+	n := NewNaming()
+	want := filepath.Join("/media", "Movie (2020)", "Movie (2020)-trailer.mkv")
+	if got := n.BuildFullPath("/media", types.MediaTypeMovie, trailer, ".mkv"); got != want {
+		t.Errorf("suffix layout: BuildFullPath() = %q, want %q", got, want)
+	}
+
+	n.SetExtrasLayout(ExtrasLayoutFolder)
+	want = filepath.Join("/media", "Movie (2020)", "trailers", "Movie (2020)-trailer.mkv")
+	if got := n.BuildFullPath("/media", types.MediaTypeMovie, trailer, ".mkv"); got != want {
+		t.Errorf("folder layout: BuildFullPath() = %q, want %q", got, want)
+	}
+
+	// Flat libraries have no folder to hold extras, so the suffix is kept
+	n.SetMovieLayout(MovieLayoutFlat)
+	want = filepath.Join("/media", "Movie (2020)-trailer.mkv")
+	if got := n.BuildFullPath("/media", types.MediaTypeMovie, trailer, ".mkv"); got != want {
+		t.Errorf("flat layout: BuildFullPath() = %q, want %q", got, want)
+	}
+
+	if got := ExtraTypeDir("behindthescenes"); got != "behind the scenes" {
+		t.Errorf("ExtraTypeDir(behindthescenes) = %q, want %q", got, "behind the scenes")
+	}
+}
+
+func TestBuildFullPath_IDTokens(t *testing.T) {
+	movie := &types.Metadata{
+		Title:         "The Matrix",
+		Year:          1999,
+		MovieMetadata: &types.MovieMetadata{TMDBID: 603, IMDBID: "tt0133093"},
+	}
+	imdbOnly := &types.Metadata{
+		Title:         "The Matrix",
+		Year:          1999,
+		MovieMetadata: &types.MovieMetadata{IMDBID: "tt0133093"},
+	}
+	show := &types.Metadata{
+		TVMetadata: &types.TVMetadata{ShowTitle: "Breaking Bad", Season: 1, Episode: 1, TMDBID: 1396},
+	}
+
+	tests := []struct {
+		name      string
+		enabled   bool
+		mediaType types.MediaType
+		metadata  *types.Metadata
+		want      string
+	}{
+		{"disabled", false, types.MediaTypeMovie, movie, filepath.Join("/media", "The Matrix (1999)", "The Matrix (1999).mkv")},
+		{"movie prefers tmdb", true, types.MediaTypeMovie, movie, filepath.Join("/media", "The Matrix (1999) [tmdbid-603]", "The Matrix (1999) [tmdbid-603].mkv")},
+		{"movie imdb only", true, types.MediaTypeMovie, imdbOnly, filepath.Join("/media", "The Matrix (1999) [imdbid-tt0133093]", "The Matrix (1999) [imdbid-tt0133093].mkv")},
+		{"show folder only", true, types.MediaTypeTV, show, filepath.Join("/media", "Breaking Bad [tmdbid-1396]", "Season 01", "Breaking Bad - S01E01.mkv")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNaming()
+			n.SetIDTokens(tt.enabled)
+			if got := n.BuildFullPath("/media", tt.mediaType, tt.metadata, ".mkv"); got != tt.want {
+				t.Errorf("BuildFullPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFullPath_UnknownYearPlaceholder(t *testing.T) {
+	movie := &types.Metadata{Title: "Some Movie", MovieMetadata: &types.MovieMetadata{}}
+	album := &types.Metadata{Title: "Track", MusicMetadata: &types.MusicMetadata{Artist: "Artist", Album: "Album", TrackNumber: 1}}
+	dated := &types.Metadata{Title: "Inception", Year: 2010, MovieMetadata: &types.MovieMetadata{}}
+
+	tests := []struct {
+		name        string
+		placeholder string
+		mediaType   types.MediaType
+		metadata    *types.Metadata
+		want        string
+	}{
+		{"omit", "", types.MediaTypeMovie, movie, filepath.Join("/media", "Some Movie", "Some Movie.mkv")},
+		{"zeros", "0000", types.MediaTypeMovie, movie, filepath.Join("/media", "Some Movie (0000)", "Some Movie (0000).mkv")},
+		{"word", "Unknown", types.MediaTypeMovie, movie, filepath.Join("/media", "Some Movie (Unknown)", "Some Movie (Unknown).mkv")},
+		{"album", "0000", types.MediaTypeMusic, album, filepath.Join("/media", "Artist", "Album (0000)", "01 - Track.mkv")},
+		{"known year unchanged", "0000", types.MediaTypeMovie, dated, filepath.Join("/media", "Inception (2010)", "Inception (2010).mkv")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNaming()
+			n.SetUnknownYearPlaceholder(tt.placeholder)
+			if got := n.BuildFullPath("/media", tt.mediaType, tt.metadata, ".mkv"); got != tt.want {
+				t.Errorf("BuildFullPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFullPath_FlatByType(t *testing.T) {
+	movie := &types.Metadata{Title: "The Matrix", Year: 1999, MovieMetadata: &types.MovieMetadata{}}
+	episode := &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "Breaking Bad", Season: 1, Episode: 1, EpisodeTitle: "Pilot"}}
+	track := &types.Metadata{Title: "Time", Year: 1973, MusicMetadata: &types.MusicMetadata{Artist: "Pink Floyd", Album: "The Dark Side of the Moon", TrackNumber: 4}}
+	single := &types.Metadata{Title: "Time", MusicMetadata: &types.MusicMetadata{Artist: "Pink Floyd"}}
+	book := &types.Metadata{Title: "Dune", Year: 1965, BookMetadata: &types.BookMetadata{Author: "Frank Herbert"}}
+
+	tests := []struct {
+		name      string
+		mediaType types.MediaType
+		metadata  *types.Metadata
+		ext       string
+		want      string
+	}{
+		{"movie", types.MediaTypeMovie, movie, ".mkv", filepath.Join("/media/Movies", "The Matrix (1999).mkv")},
+		{"tv", types.MediaTypeTV, episode, ".mkv", filepath.Join("/media/TV", "Breaking Bad - S01E01 - Pilot.mkv")},
+		{"music", types.MediaTypeMusic, track, ".flac", filepath.Join("/media/Music", "Pink Floyd - The Dark Side of the Moon (1973) - 04 - Time.flac")},
+		{"music without album", types.MediaTypeMusic, single, ".mp3", filepath.Join("/media/Music", "Pink Floyd - Time.mp3")},
+		{"book", types.MediaTypeBook, book, ".epub", filepath.Join("/media/Books", "Frank Herbert - Dune (1965).epub")},
+	}
+
+	roots := map[types.MediaType]string{
+		types.MediaTypeMovie: "/media/Movies",
+		types.MediaTypeTV:    "/media/TV",
+		types.MediaTypeMusic: "/media/Music",
+		types.MediaTypeBook:  "/media/Books",
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNaming()
+			n.SetDestStructure(StructureFlatByType)
+			if got := n.BuildFullPath(roots[tt.mediaType], tt.mediaType, tt.metadata, tt.ext); got != tt.want {
+				t.Errorf("BuildFullPath() = %q, want %q", got, tt.want)
+			}
+			if n.MovieLayout() != MovieLayoutFlat {
+				t.Errorf("MovieLayout() = %s, want flat under flat-by-type", n.MovieLayout())
+			}
+		})
+	}
+
+	// The default structure keeps Jellyfin's nested folders
+	n := NewNaming()
+	want := filepath.Join("/media/TV", "Breaking Bad", "Season 01", "Breaking Bad - S01E01 - Pilot.mkv")
+	if got := n.BuildFullPath("/media/TV", types.MediaTypeTV, episode, ".mkv"); got != want {
+		t.Errorf("nested BuildFullPath() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFullPath_DecadeMusicLayout(t *testing.T) {
+	n := NewNaming()
+	n.SetMusicLayout(MusicLayoutDecadeArtist)
+
+	tests := []struct {
+		name     string
+		metadata *types.Metadata
+		want     string
+	}{
+		{
+			name: "album from 1973",
+			metadata: &types.Metadata{
+				Title: "Time",
+				Year:  1973,
+				MusicMetadata: &types.MusicMetadata{
+					Artist:      "Pink Floyd",
+					Album:       "The Dark Side of the Moon",
+					TrackNumber: 4,
+				},
+			},
+			want: filepath.Join("/media/music", "1970s", "Pink Floyd", "The Dark Side of the Moon (1973)", "04 - Time.flac"),
+		},
+		{
+			name: "album without year",
+			metadata: &types.Metadata{
+				Title: "Song",
+				MusicMetadata: &types.MusicMetadata{
+					Artist:      "Some Artist",
+					Album:       "Some Album",
+					TrackNumber: 1,
+				},
+			},
+			want: filepath.Join("/media/music", "Unknown", "Some Artist", "Some Album", "01 - Song.flac"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := n.BuildFullPath("/media/music", types.MediaTypeMusic, tt.metadata, ".flac")
+			if got != tt.want {
+				t.Errorf("BuildFullPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFullPath_LooseTrackLayout(t *testing.T) {
+	single := &types.Metadata{
+		Title:         "Lonely Song",
+		Year:          2021,
+		MusicMetadata: &types.MusicMetadata{Artist: "Some Artist"},
+	}
+	albumTrack := &types.Metadata{
+		Title: "Time",
+		Year:  1973,
+		MusicMetadata: &types.MusicMetadata{
+			Artist:      "Pink Floyd",
+			Album:       "The Dark Side of the Moon",
+			TrackNumber: 4,
+		},
+	}
+	albumPath := filepath.Join("/media/music", "Pink Floyd", "The Dark Side of the Moon (1973)", "04 - Time.flac")
+
+	tests := []struct {
+		name     string
+		layout   LooseTrackLayout
+		metadata *types.Metadata
+		want     string
+	}{
+		{"default no album", "", single, filepath.Join("/media/music", "Some Artist", "Unknown Album (2021)", "Lonely Song.flac")},
+		{"singles no album", LooseTrackSingles, single, filepath.Join("/media/music", "Some Artist", "Singles", "Lonely Song.flac")},
+		{"compilations no album", LooseTrackCompilations, single, filepath.Join("/media/music", "Various Artists", "Compilations", "Some Artist - Lonely Song.flac")},
+		{"singles with album", LooseTrackSingles, albumTrack, albumPath},
+		{"compilations with album", LooseTrackCompilations, albumTrack, albumPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNaming()
+			n.SetLooseTrackLayout(tt.layout)
+			got := n.BuildFullPath("/media/music", types.MediaTypeMusic, tt.metadata, ".flac")
+			if got != tt.want {
+				t.Errorf("BuildFullPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMusicDecadeDir(t *testing.T) {
+	n := NewNaming()
+
+	tests := map[int]string{1973: "1970s", 1980: "1980s", 2009: "2000s", 0: "Unknown"}
+	for year, want := range tests {
+		if got := n.GetMusicDecadeDir(year); got != want {
+			t.Errorf("GetMusicDecadeDir(%d) = %q, want %q", year, got, want)
+		}
+	}
+}
+
+func TestMovieSidecarPaths(t *testing.T) {
+	moviePath := filepath.Join("/media/movies", "The Matrix (1999)", "The Matrix (1999).mkv")
+	flatPath := filepath.Join("/media/movies", "The Matrix (1999).mkv")
+
+	tests := []struct {
+		name      string
+		layout    MovieLayout
+		moviePath string
+		wantNFO   string
+		wantArt   string
+	}{
+		{
+			name:      "folder layout",
+			layout:    MovieLayoutFolder,
+			moviePath: moviePath,
+			wantNFO:   filepath.Join("/media/movies", "The Matrix (1999)", "movie.nfo"),
+			wantArt:   filepath.Join("/media/movies", "The Matrix (1999)", "poster.jpg"),
+		},
+		{
+			name:      "flat layout",
+			layout:    MovieLayoutFlat,
+			moviePath: flatPath,
+			wantNFO:   filepath.Join("/media/movies", "The Matrix (1999).nfo"),
+			wantArt:   filepath.Join("/media/movies", "The Matrix (1999)-poster.jpg"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNaming()
+			n.SetMovieLayout(tt.layout)
+
+			if got := n.GetMovieNFOPath(tt.moviePath); got != tt.wantNFO {
+				t.Errorf("GetMovieNFOPath() = %q, want %q", got, tt.wantNFO)
+			}
+			if got := n.GetMovieArtworkPath(tt.moviePath, "poster"); got != tt.wantArt {
+				t.Errorf("GetMovieArtworkPath() = %q, want %q", got, tt.wantArt)
+			}
+		})
+	}
+}
+
+func TestBuildFullPath_FilenameStyle(t *testing.T) {
+	movie := &types.Metadata{Title: "The Lord of the Rings", Year: 2001, MovieMetadata: &types.MovieMetadata{}}
+	episode := &types.Metadata{TVMetadata: &types.TVMetadata{ShowTitle: "Mr. Robot", Season: 1, Episode: 2, EpisodeTitle: "the hack"}}
+	track := &types.Metadata{Title: "One More Time", MusicMetadata: &types.MusicMetadata{Artist: "Daft Punk", Album: "Discovery", TrackNumber: 1}}
+	book := &types.Metadata{Title: "Dune", BookMetadata: &types.BookMetadata{Author: "Frank Herbert"}}
+
+	movieDir := filepath.Join("/media", "The Lord of the Rings (2001)")
+	seasonDir := filepath.Join("/media", "Mr. Robot", "Season 01")
+	albumDir := filepath.Join("/media", "Daft Punk", "Discovery")
+	bookDir := filepath.Join("/media", "Herbert, Frank", "Dune")
+
+	tests := []struct {
+		separator Separator
+		nameCase  NameCase
+		movie     string
+		episode   string
+		track     string
+		book      string
+	}{
+		{"", "", "The Lord of the Rings (2001).mkv", "Mr. Robot - S01E02 - the hack.mkv", "01 - One More Time.flac", "Dune.epub"},
+		{SeparatorSpaceDash, CasePreserve, "The Lord of the Rings (2001).mkv", "Mr. Robot - S01E02 - the hack.mkv", "01 - One More Time.flac", "Dune.epub"},
+		{SeparatorSpaceDash, CaseTitle, "The Lord Of The Rings (2001).mkv", "Mr. Robot - S01E02 - The Hack.mkv", "01 - One More Time.flac", "Dune.epub"},
+		{SeparatorSpaceDash, CaseLower, "the lord of the rings (2001).mkv", "mr. robot - s01e02 - the hack.mkv", "01 - one more time.flac", "dune.epub"},
+		{SeparatorDot, CasePreserve, "The.Lord.of.the.Rings.(2001).mkv", "Mr.Robot.S01E02.the.hack.mkv", "01.One.More.Time.flac", "Dune.epub"},
+		{SeparatorDot, CaseTitle, "The.Lord.Of.The.Rings.(2001).mkv", "Mr.Robot.S01E02.The.Hack.mkv", "01.One.More.Time.flac", "Dune.epub"},
+		{SeparatorDot, CaseLower, "the.lord.of.the.rings.(2001).mkv", "mr.robot.s01e02.the.hack.mkv", "01.one.more.time.flac", "dune.epub"},
+		{SeparatorUnderscore, CasePreserve, "The_Lord_of_the_Rings_(2001).mkv", "Mr._Robot_S01E02_the_hack.mkv", "01_One_More_Time.flac", "Dune.epub"},
+		{SeparatorUnderscore, CaseTitle, "The_Lord_Of_The_Rings_(2001).mkv", "Mr._Robot_S01E02_The_Hack.mkv", "01_One_More_Time.flac", "Dune.epub"},
+		{SeparatorUnderscore, CaseLower, "the_lord_of_the_rings_(2001).mkv", "mr._robot_s01e02_the_hack.mkv", "01_one_more_time.flac", "dune.epub"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.separator)+"/"+string(tt.nameCase), func(t *testing.T) {
+			n := NewNaming()
+			n.SetFilenameStyle(FilenameStyle{Separator: tt.separator, Case: tt.nameCase})
+
+			// Folders keep the Jellyfin style; only filenames change
+			checks := []struct {
+				mediaType types.MediaType
+				metadata  *types.Metadata
+				ext       string
+				want      string
+			}{
+				{types.MediaTypeMovie, movie, ".mkv", filepath.Join(movieDir, tt.movie)},
+				{types.MediaTypeTV, episode, ".mkv", filepath.Join(seasonDir, tt.episode)},
+				{types.MediaTypeMusic, track, ".flac", filepath.Join(albumDir, tt.track)},
+				{types.MediaTypeBook, book, ".epub", filepath.Join(bookDir, tt.book)},
+			}
+			for _, c := range checks {
+				if got := n.BuildFullPath("/media", c.mediaType, c.metadata, c.ext); got != c.want {
+					t.Errorf("BuildFullPath(%s) = %q, want %q", c.mediaType, got, c.want)
+				}
+			}
+		})
+	}
+}
@@ -8,17 +8,55 @@ import (
 )
 
 // NFOGenerator generates Kodi-compatible NFO files for Jellyfin
-type NFOGenerator struct{}
+type NFOGenerator struct {
+	sortArticles           []string
+	recordOriginalFilename bool
+}
 
 // NewNFOGenerator creates a new NFO generator
 func NewNFOGenerator() *NFOGenerator {
 	return &NFOGenerator{}
 }
 
+// SetSortArticles sets the leading articles used to derive <sorttitle>;
+// nil or empty disables sort titles
+func (g *NFOGenerator) SetSortArticles(articles []string) {
+	g.sortArticles = articles
+}
+
+// SetRecordOriginalFilename adds an <original_filename> element with the
+// file's pre-organization name to movie and book NFOs. Show, season and
+// album NFOs describe a folder of files and never carry it.
+func (g *NFOGenerator) SetRecordOriginalFilename(enabled bool) {
+	g.recordOriginalFilename = enabled
+}
+
+// originalFilename returns the name to record in an NFO, or "" when
+// recording is off
+func (g *NFOGenerator) originalFilename(metadata *types.Metadata) string {
+	if !g.recordOriginalFilename {
+		return ""
+	}
+	return metadata.OriginalFilename
+}
+
+// sortTitle returns the sort title for a title, or "" if it would be unchanged
+func (g *NFOGenerator) sortTitle(title string) string {
+	if len(g.sortArticles) == 0 {
+		return ""
+	}
+	sorted := MoveArticleToEnd(title, g.sortArticles)
+	if sorted == title {
+		return ""
+	}
+	return sorted
+}
+
 // MovieNFO represents the XML structure for a movie NFO file
 type MovieNFO struct {
 	XMLName       xml.Name `xml:"movie"`
 	Title         string   `xml:"title,omitempty"`
+	SortTitle     string   `xml:"sorttitle,omitempty"`
 	OriginalTitle string   `xml:"originaltitle,omitempty"`
 	Year          int      `xml:"year,omitempty"`
 	Plot          string   `xml:"plot,omitempty"`
@@ -29,21 +67,29 @@ type MovieNFO struct {
 	Studio        string   `xml:"studio,omitempty"`
 	Directors     []string `xml:"director,omitempty"`
 	Actors        []Actor  `xml:"actor,omitempty"`
+	Tags          []string `xml:"tag,omitempty"`
 	TMDBID        int      `xml:"tmdbid,omitempty"`
 	IMDBID        string   `xml:"imdbid,omitempty"`
+	// OriginalFilename is the file's name before organizing, for provenance
+	OriginalFilename string `xml:"original_filename,omitempty"`
 }
 
 // TVShowNFO represents the XML structure for a TV show NFO file
 type TVShowNFO struct {
-	XMLName   xml.Name `xml:"tvshow"`
-	Title     string   `xml:"title,omitempty"`
-	Plot      string   `xml:"plot,omitempty"`
-	Premiered string   `xml:"premiered,omitempty"`
-	Genres    []string `xml:"genre,omitempty"`
-	Studio    string   `xml:"studio,omitempty"`
-	Actors    []Actor  `xml:"actor,omitempty"`
-	TVDBID    int      `xml:"tvdbid,omitempty"`
-	TMDBID    int      `xml:"tmdbid,omitempty"`
+	XMLName       xml.Name `xml:"tvshow"`
+	Title         string   `xml:"title,omitempty"`
+	SortTitle     string   `xml:"sorttitle,omitempty"`
+	OriginalTitle string   `xml:"originaltitle,omitempty"`
+	Plot          string   `xml:"plot,omitempty"`
+	Premiered     string   `xml:"premiered,omitempty"`
+	MPAA          string   `xml:"mpaa,omitempty"`
+	Genres        []string `xml:"genre,omitempty"`
+	Studio        string   `xml:"studio,omitempty"`
+	Actors        []Actor  `xml:"actor,omitempty"`
+	Tags          []string `xml:"tag,omitempty"`
+	TVDBID        int      `xml:"tvdbid,omitempty"`
+	TMDBID        int      `xml:"tmdbid,omitempty"`
+	IMDBID        string   `xml:"imdbid,omitempty"`
 }
 
 // EpisodeNFO represents the XML structure for a TV episode NFO file
@@ -86,12 +132,30 @@ type BookNFO struct {
 	Series      string   `xml:"series,omitempty"`
 	SeriesIndex int      `xml:"seriesindex,omitempty"`
 	Description string   `xml:"description,omitempty"`
+	// OriginalFilename is the file's name before organizing, for provenance
+	OriginalFilename string `xml:"original_filename,omitempty"`
 }
 
 // Actor represents an actor in a movie or TV show
 type Actor struct {
-	Name string `xml:"name,omitempty"`
-	Role string `xml:"role,omitempty"`
+	Name  string `xml:"name,omitempty"`
+	Role  string `xml:"role,omitempty"`
+	Thumb string `xml:"thumb,omitempty"`
+}
+
+// nfoActors converts cast members to NFO actors, falling back to bare names
+// when no structured credits were fetched
+func nfoActors(people []types.Person, names []string) []Actor {
+	actors := make([]Actor, 0, len(people))
+	for _, person := range people {
+		actors = append(actors, Actor{Name: person.Name, Role: person.Role, Thumb: person.Thumb})
+	}
+	if len(actors) == 0 {
+		for _, name := range names {
+			actors = append(actors, Actor{Name: name})
+		}
+	}
+	return actors
 }
 
 // GenerateMovieNFO generates a movie.nfo XML file content
@@ -101,9 +165,11 @@ func (g *NFOGenerator) GenerateMovieNFO(metadata *types.Metadata) (string, error
 	}
 
 	nfo := MovieNFO{
-		Title:         metadata.Title,
-		OriginalTitle: metadata.Title, // Default to same as title
-		Year:          metadata.Year,
+		Title:            metadata.Title,
+		SortTitle:        g.sortTitle(metadata.Title),
+		OriginalTitle:    metadata.Title, // Default to same as title
+		Year:             metadata.Year,
+		OriginalFilename: g.originalFilename(metadata),
 	}
 
 	// Add movie-specific metadata if available
@@ -115,6 +181,7 @@ func (g *NFOGenerator) GenerateMovieNFO(metadata *types.Metadata) (string, error
 		}
 
 		nfo.Plot = mm.Plot
+		nfo.MPAA = mm.Certification
 		nfo.TMDBID = mm.TMDBID
 		nfo.IMDBID = mm.IMDBID
 
@@ -126,11 +193,8 @@ func (g *NFOGenerator) GenerateMovieNFO(metadata *types.Metadata) (string, error
 			nfo.Directors = append(nfo.Directors, director)
 		}
 
-		for _, cast := range mm.Cast {
-			nfo.Actors = append(nfo.Actors, Actor{
-				Name: cast,
-			})
-		}
+		nfo.Actors = nfoActors(mm.Actors, mm.Cast)
+		nfo.Tags = mm.Tags
 	}
 
 	return marshalNFO(nfo)
@@ -149,16 +213,24 @@ func (g *NFOGenerator) GenerateTVShowNFO(metadata *types.Metadata) (string, erro
 	tm := metadata.TVMetadata
 
 	nfo := TVShowNFO{
-		Title: tm.ShowTitle,
-		Plot:  tm.Plot,
+		Title:     tm.ShowTitle,
+		SortTitle: g.sortTitle(tm.ShowTitle),
+		Plot:      tm.Plot,
+	}
+	if tm.OriginalTitle != tm.ShowTitle {
+		nfo.OriginalTitle = tm.OriginalTitle
 	}
 
 	if tm.AirDate != "" {
 		nfo.Premiered = tm.AirDate
 	}
 
+	nfo.MPAA = tm.Certification
 	nfo.TMDBID = tm.TMDBID
 	nfo.TVDBID = tm.TVDBID
+	nfo.IMDBID = tm.IMDBID
+	nfo.Actors = nfoActors(tm.Actors, nil)
+	nfo.Tags = tm.Tags
 
 	return marshalNFO(nfo)
 }
@@ -243,8 +315,9 @@ func (g *NFOGenerator) GenerateBookNFO(metadata *types.Metadata) (string, error)
 	}
 
 	nfo := BookNFO{
-		Title: metadata.Title,
-		Year:  metadata.Year,
+		Title:            metadata.Title,
+		Year:             metadata.Year,
+		OriginalFilename: g.originalFilename(metadata),
 	}
 
 	// Add book-specific metadata if available
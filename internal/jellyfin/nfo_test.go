@@ -202,6 +202,39 @@ func TestGenerateTVShowNFO(t *testing.T) {
 	}
 }
 
+func TestGenerateTVShowNFO_SortAndOriginalTitle(t *testing.T) {
+	gen := NewNFOGenerator()
+	gen.SetSortArticles(DefaultSortArticles)
+
+	nfo, err := gen.GenerateTVShowNFO(&types.Metadata{
+		TVMetadata: &types.TVMetadata{ShowTitle: "The Expanse", OriginalTitle: "L'Étendue"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTVShowNFO() error = %v", err)
+	}
+	var got TVShowNFO
+	if err := xml.Unmarshal([]byte(nfo), &got); err != nil {
+		t.Fatalf("NFO should be valid XML: %v", err)
+	}
+	if got.SortTitle != "Expanse, The" {
+		t.Errorf("sorttitle = %q, want %q", got.SortTitle, "Expanse, The")
+	}
+	if got.OriginalTitle != "L'Étendue" {
+		t.Errorf("originaltitle = %q, want %q", got.OriginalTitle, "L'Étendue")
+	}
+
+	// Neither element is written without data for it
+	nfo, err = gen.GenerateTVShowNFO(&types.Metadata{
+		TVMetadata: &types.TVMetadata{ShowTitle: "Breaking Bad", OriginalTitle: "Breaking Bad"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTVShowNFO() error = %v", err)
+	}
+	if strings.Contains(nfo, "<sorttitle>") || strings.Contains(nfo, "<originaltitle>") {
+		t.Errorf("NFO should omit empty sorttitle/originaltitle:\n%s", nfo)
+	}
+}
+
 func TestGenerateEpisodeNFO(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -690,3 +723,223 @@ func TestGenerateBookNFO(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateNFO_ActorThumbs(t *testing.T) {
+	gen := NewNFOGenerator()
+
+	movie := &types.Metadata{
+		Title: "The Matrix",
+		Year:  1999,
+		MovieMetadata: &types.MovieMetadata{
+			Actors: []types.Person{
+				{Name: "Keanu Reeves", Role: "Neo", Thumb: "https://image.tmdb.org/t/p/w185/keanu.jpg"},
+				{Name: "Laurence Fishburne", Role: "Morpheus"},
+			},
+			Cast: []string{"Keanu Reeves", "Laurence Fishburne"},
+		},
+	}
+	nfo, err := gen.GenerateMovieNFO(movie)
+	if err != nil {
+		t.Fatalf("GenerateMovieNFO() error = %v", err)
+	}
+	if !strings.Contains(nfo, "<thumb>https://image.tmdb.org/t/p/w185/keanu.jpg</thumb>") {
+		t.Errorf("movie NFO missing actor thumb:\n%s", nfo)
+	}
+	if !strings.Contains(nfo, "<role>Morpheus</role>") {
+		t.Errorf("movie NFO missing actor role:\n%s", nfo)
+	}
+	if strings.Contains(nfo, "<mpaa>") {
+		t.Errorf("movie NFO without a certification should omit <mpaa>:\n%s", nfo)
+	}
+	if strings.Count(nfo, "<thumb>") != 1 {
+		t.Errorf("expected exactly one <thumb> element, got %d", strings.Count(nfo, "<thumb>"))
+	}
+
+	show := &types.Metadata{
+		Title: "Breaking Bad",
+		TVMetadata: &types.TVMetadata{
+			ShowTitle: "Breaking Bad",
+			Actors: []types.Person{
+				{Name: "Bryan Cranston", Role: "Walter White", Thumb: "https://image.tmdb.org/t/p/w185/bryan.jpg"},
+			},
+		},
+	}
+	nfo, err = gen.GenerateTVShowNFO(show)
+	if err != nil {
+		t.Fatalf("GenerateTVShowNFO() error = %v", err)
+	}
+	if !strings.Contains(nfo, "<thumb>https://image.tmdb.org/t/p/w185/bryan.jpg</thumb>") {
+		t.Errorf("tvshow NFO missing actor thumb:\n%s", nfo)
+	}
+
+	// Plain cast names carry no thumb.
+	plain := &types.Metadata{
+		Title:         "Inception",
+		MovieMetadata: &types.MovieMetadata{Cast: []string{"Leonardo DiCaprio"}},
+	}
+	nfo, err = gen.GenerateMovieNFO(plain)
+	if err != nil {
+		t.Fatalf("GenerateMovieNFO() error = %v", err)
+	}
+	if strings.Contains(nfo, "<thumb>") {
+		t.Errorf("movie NFO without profile paths should not contain <thumb>:\n%s", nfo)
+	}
+	if !strings.Contains(nfo, "<name>Leonardo DiCaprio</name>") {
+		t.Errorf("movie NFO missing cast name:\n%s", nfo)
+	}
+}
+
+func TestActorImageName(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  string
+		want string
+	}{
+		{"Keanu Reeves", ".jpg", "Keanu_Reeves.jpg"},
+		{"Carrie-Anne Moss", "", "Carrie-Anne_Moss.jpg"},
+		{"Bryan Cranston", ".png", "Bryan_Cranston.png"},
+	}
+	for _, tt := range tests {
+		if got := ActorImageName(tt.name, tt.ext); got != tt.want {
+			t.Errorf("ActorImageName(%q, %q) = %q, want %q", tt.name, tt.ext, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateNFO_Certification(t *testing.T) {
+	gen := NewNFOGenerator()
+
+	nfo, err := gen.GenerateMovieNFO(&types.Metadata{
+		Title:         "Inception",
+		MovieMetadata: &types.MovieMetadata{Certification: "12A"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateMovieNFO() error = %v", err)
+	}
+	if !strings.Contains(nfo, "<mpaa>12A</mpaa>") {
+		t.Errorf("movie NFO missing <mpaa>:\n%s", nfo)
+	}
+
+	nfo, err = gen.GenerateTVShowNFO(&types.Metadata{
+		TVMetadata: &types.TVMetadata{ShowTitle: "Breaking Bad", Certification: "TV-MA"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTVShowNFO() error = %v", err)
+	}
+	if !strings.Contains(nfo, "<mpaa>TV-MA</mpaa>") {
+		t.Errorf("tvshow NFO missing <mpaa>:\n%s", nfo)
+	}
+}
+
+func TestGenerateNFO_Tags(t *testing.T) {
+	gen := NewNFOGenerator()
+
+	nfo, err := gen.GenerateMovieNFO(&types.Metadata{
+		Title:         "The Godfather",
+		MovieMetadata: &types.MovieMetadata{Tags: []string{"Der Pate"}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateMovieNFO() error = %v", err)
+	}
+	if !strings.Contains(nfo, "<tag>Der Pate</tag>") {
+		t.Errorf("movie NFO missing <tag>:\n%s", nfo)
+	}
+
+	nfo, err = gen.GenerateTVShowNFO(&types.Metadata{
+		TVMetadata: &types.TVMetadata{ShowTitle: "Money Heist", Tags: []string{"La casa de papel"}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTVShowNFO() error = %v", err)
+	}
+	if !strings.Contains(nfo, "<tag>La casa de papel</tag>") {
+		t.Errorf("tvshow NFO missing <tag>:\n%s", nfo)
+	}
+}
+
+func TestGenerateNFO_OriginalFilename(t *testing.T) {
+	gen := NewNFOGenerator()
+	movie := &types.Metadata{
+		Title:            "The Matrix",
+		Year:             1999,
+		OriginalFilename: "The.Matrix.1999.1080p.BluRay.x264-GRP & Co.mkv",
+		MovieMetadata:    &types.MovieMetadata{},
+	}
+
+	// Omitted by default
+	nfo, err := gen.GenerateMovieNFO(movie)
+	if err != nil {
+		t.Fatalf("GenerateMovieNFO() error = %v", err)
+	}
+	if strings.Contains(nfo, "original_filename") {
+		t.Errorf("movie NFO has <original_filename> while disabled:\n%s", nfo)
+	}
+
+	gen.SetRecordOriginalFilename(true)
+	nfo, err = gen.GenerateMovieNFO(movie)
+	if err != nil {
+		t.Fatalf("GenerateMovieNFO() error = %v", err)
+	}
+	var got MovieNFO
+	if err := xml.Unmarshal([]byte(nfo), &got); err != nil {
+		t.Fatalf("NFO should be valid XML: %v\n%s", err, nfo)
+	}
+	if got.OriginalFilename != movie.OriginalFilename {
+		t.Errorf("original_filename = %q, want %q", got.OriginalFilename, movie.OriginalFilename)
+	}
+
+	nfo, err = gen.GenerateBookNFO(&types.Metadata{Title: "Dune", OriginalFilename: "frank_herbert-dune.epub"})
+	if err != nil {
+		t.Fatalf("GenerateBookNFO() error = %v", err)
+	}
+	if !strings.Contains(nfo, "<original_filename>frank_herbert-dune.epub</original_filename>") {
+		t.Errorf("book NFO missing <original_filename>:\n%s", nfo)
+	}
+}
+
+func TestMergeNFO(t *testing.T) {
+	existing := `<?xml version="1.0" encoding="UTF-8"?>
+<movie>
+    <title>Curated Title</title>
+    <genre>Cyberpunk</genre>
+    <actor><name>Keanu Reeves</name><role>Neo</role></actor>
+</movie>`
+	generated, err := NewNFOGenerator().GenerateMovieNFO(&types.Metadata{
+		Title: "The Matrix",
+		Year:  1999,
+		MovieMetadata: &types.MovieMetadata{
+			Genres: []string{"Action", "Science Fiction"},
+			TMDBID: 603,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := MergeNFO(existing, generated)
+	if err != nil {
+		t.Fatalf("MergeNFO() error = %v", err)
+	}
+	for _, want := range []string{
+		"<title>Curated Title</title>",
+		"<genre>Cyberpunk</genre>",
+		"<actor><name>Keanu Reeves</name><role>Neo</role></actor>",
+		"<year>1999</year>",
+		"<tmdbid>603</tmdbid>",
+	} {
+		if !strings.Contains(merged, want) {
+			t.Errorf("merged NFO missing %s:\n%s", want, merged)
+		}
+	}
+	for _, unwanted := range []string{"<title>The Matrix</title>", "<genre>Action</genre>"} {
+		if strings.Contains(merged, unwanted) {
+			t.Errorf("merged NFO should keep the curated field instead of %s:\n%s", unwanted, merged)
+		}
+	}
+
+	if _, err := MergeNFO("<episodedetails/>", generated); err == nil {
+		t.Error("MergeNFO() of different root elements should fail")
+	}
+	if _, err := MergeNFO("https://www.imdb.com/title/tt0133093/", generated); err == nil {
+		t.Error("MergeNFO() of a non-XML NFO should fail")
+	}
+}
@@ -0,0 +1,261 @@
+package jellyfin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// selfTestText exercises XML escaping: markup characters, quotes,
+// apostrophes, non-ASCII text and an embedded newline
+const selfTestText = `Tom & Jerry's <Uncut> "Director's" Cut — Ça été 日本語
+second line`
+
+// jellyfinElements lists the element names Jellyfin reads, per NFO root
+var jellyfinElements = map[string][]string{
+	"movie": {"title", "sorttitle", "originaltitle", "year", "plot", "tagline", "runtime",
+		"mpaa", "genre", "studio", "director", "actor", "name", "role", "tmdbid", "imdbid"},
+	"tvshow":         {"title", "sorttitle", "originaltitle", "plot", "premiered", "genre", "studio", "actor", "name", "role", "tvdbid", "tmdbid"},
+	"episodedetails": {"title", "season", "episode", "plot", "aired"},
+	"season":         {"seasonnumber"},
+	"album":          {"title", "artist", "albumartist", "year", "genre", "review", "musicbrainzalbumid", "musicbrainzreleasegroupid"},
+	"book":           {"title", "author", "year", "publisher", "isbn", "series", "seriesindex", "description"},
+}
+
+// SelfTestResult is the outcome of one NFO round-trip check
+type SelfTestResult struct {
+	// Name identifies the NFO type, e.g. "movie.nfo"
+	Name string
+	// Problems lists every discrepancy found; empty means the check passed
+	Problems []string
+}
+
+// Passed reports whether the check found no problems
+func (r SelfTestResult) Passed() bool {
+	return len(r.Problems) == 0
+}
+
+// RunNFOSelfTest generates each NFO type from sample metadata full of
+// characters that need escaping, parses it back and reports any field that
+// did not survive the round trip or any element Jellyfin would not recognize.
+func RunNFOSelfTest() []SelfTestResult {
+	g := NewNFOGenerator()
+	g.SetSortArticles(DefaultSortArticles)
+
+	title := "The " + selfTestText
+	genres := []string{"Action & Adventure", "Sci-Fi <Cult>"}
+
+	movie := &types.Metadata{
+		Title: title,
+		Year:  1999,
+		MovieMetadata: &types.MovieMetadata{
+			OriginalTitle: "L'" + selfTestText,
+			Plot:          selfTestText,
+			Director:      []string{"Lana & Lilly <W>"},
+			Cast:          []string{"Keanu \"Neo\" Reeves", "Carrie-Anne Moss"},
+			Genres:        genres,
+			TMDBID:        603,
+			IMDBID:        "tt0133093",
+		},
+	}
+	tv := &types.Metadata{
+		Title: title,
+		TVMetadata: &types.TVMetadata{
+			ShowTitle:     title,
+			OriginalTitle: "L'" + selfTestText,
+			Season:        2,
+			Episode:       13,
+			EpisodeTitle:  selfTestText,
+			Plot:          selfTestText,
+			AirDate:       "2008-01-20",
+			TMDBID:        1396,
+			TVDBID:        81189,
+		},
+	}
+	music := &types.Metadata{
+		Title: selfTestText,
+		Year:  1973,
+		MusicMetadata: &types.MusicMetadata{
+			Artist:         "Simon & Garfunkel",
+			AlbumArtist:    "Various <Artists>",
+			Genre:          "Rock & Roll",
+			MusicBrainzID:  "f5093c06-23e3-404f-aeaa-40f72885ee3a",
+			MusicBrainzRID: "a1c35a51-d102-4ce7-aca7-8b0a3a4b3b7d",
+		},
+	}
+	book := &types.Metadata{
+		Title: selfTestText,
+		Year:  1925,
+		BookMetadata: &types.BookMetadata{
+			Author:      "O'Brien & Sons",
+			Publisher:   "Scribner's <Classics>",
+			ISBN:        "9780306406157",
+			Series:      "\"Great\" Novels",
+			SeriesIndex: 3,
+			Description: selfTestText,
+		},
+	}
+
+	results := make([]SelfTestResult, 0, 6)
+
+	results = append(results, roundTrip("movie.nfo", func() (string, error) { return g.GenerateMovieNFO(movie) },
+		&MovieNFO{
+			Title:         title,
+			SortTitle:     MoveArticleToEnd(title, DefaultSortArticles),
+			OriginalTitle: movie.MovieMetadata.OriginalTitle,
+			Year:          movie.Year,
+			Plot:          selfTestText,
+			Genres:        genres,
+			Directors:     movie.MovieMetadata.Director,
+			Actors:        []Actor{{Name: movie.MovieMetadata.Cast[0]}, {Name: movie.MovieMetadata.Cast[1]}},
+			TMDBID:        603,
+			IMDBID:        "tt0133093",
+		}, &MovieNFO{}))
+
+	results = append(results, roundTrip("tvshow.nfo", func() (string, error) { return g.GenerateTVShowNFO(tv) },
+		&TVShowNFO{
+			Title:         title,
+			SortTitle:     MoveArticleToEnd(title, DefaultSortArticles),
+			OriginalTitle: tv.TVMetadata.OriginalTitle,
+			Plot:          selfTestText,
+			Premiered:     "2008-01-20",
+			TVDBID:        81189,
+			TMDBID:        1396,
+		}, &TVShowNFO{}))
+
+	results = append(results, roundTrip("episode.nfo", func() (string, error) { return g.GenerateEpisodeNFO(tv) },
+		&EpisodeNFO{
+			Title:   selfTestText,
+			Season:  2,
+			Episode: 13,
+			Plot:    selfTestText,
+			Aired:   "2008-01-20",
+		}, &EpisodeNFO{}))
+
+	results = append(results, roundTrip("season.nfo", func() (string, error) { return g.GenerateSeasonNFO(2) },
+		&SeasonNFO{SeasonNumber: 2}, &SeasonNFO{}))
+
+	results = append(results, roundTrip("album.nfo", func() (string, error) { return g.GenerateMusicAlbumNFO(music) },
+		&MusicAlbumNFO{
+			Title:                selfTestText,
+			Artist:               "Simon & Garfunkel",
+			AlbumArtist:          "Various <Artists>",
+			Year:                 1973,
+			Genre:                "Rock & Roll",
+			MusicBrainzID:        music.MusicMetadata.MusicBrainzID,
+			MusicBrainzReleaseID: music.MusicMetadata.MusicBrainzRID,
+		}, &MusicAlbumNFO{}))
+
+	results = append(results, roundTrip("book.nfo", func() (string, error) { return g.GenerateBookNFO(book) },
+		&BookNFO{
+			Title:       selfTestText,
+			Author:      "O'Brien & Sons",
+			Year:        1925,
+			Publisher:   "Scribner's <Classics>",
+			ISBN:        "9780306406157",
+			Series:      "\"Great\" Novels",
+			SeriesIndex: 3,
+			Description: selfTestText,
+		}, &BookNFO{}))
+
+	return results
+}
+
+// roundTrip generates an NFO, checks its element names and unmarshals it
+// into got, reporting every field that differs from want
+func roundTrip(name string, generate func() (string, error), want, got interface{}) SelfTestResult {
+	result := SelfTestResult{Name: name}
+
+	content, err := generate()
+	if err != nil {
+		result.Problems = append(result.Problems, fmt.Sprintf("generation failed: %v", err))
+		return result
+	}
+
+	result.Problems = append(result.Problems, CheckNFOElements(content)...)
+
+	if err := xml.Unmarshal([]byte(content), got); err != nil {
+		result.Problems = append(result.Problems, fmt.Sprintf("XML does not parse: %v", err))
+		return result
+	}
+
+	result.Problems = append(result.Problems, diffFields(want, got)...)
+	return result
+}
+
+// CheckNFOElements parses NFO content and reports a missing XML declaration,
+// an unknown root element, or elements Jellyfin does not read for that root
+func CheckNFOElements(content string) []string {
+	var problems []string
+
+	if !strings.HasPrefix(content, "<?xml") {
+		problems = append(problems, "missing XML declaration")
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	var root string
+	var allowed map[string]bool
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return append(problems, fmt.Sprintf("XML does not parse: %v", err))
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if root == "" {
+			root = start.Name.Local
+			names, known := jellyfinElements[root]
+			if !known {
+				return append(problems, fmt.Sprintf("unknown root element <%s>", root))
+			}
+			allowed = make(map[string]bool, len(names))
+			for _, n := range names {
+				allowed[n] = true
+			}
+			continue
+		}
+
+		if !allowed[start.Name.Local] {
+			problems = append(problems, fmt.Sprintf("element <%s> is not read by Jellyfin in <%s>", start.Name.Local, root))
+		}
+	}
+
+	if root == "" {
+		problems = append(problems, "no root element")
+	}
+	return problems
+}
+
+// diffFields compares two pointers to the same NFO struct type field by
+// field (ignoring XMLName) and describes each mismatch
+func diffFields(want, got interface{}) []string {
+	var problems []string
+
+	wv := reflect.ValueOf(want).Elem()
+	gv := reflect.ValueOf(got).Elem()
+	for i := 0; i < wv.NumField(); i++ {
+		field := wv.Type().Field(i)
+		if field.Name == "XMLName" {
+			continue
+		}
+		w := wv.Field(i).Interface()
+		g := gv.Field(i).Interface()
+		if !reflect.DeepEqual(w, g) {
+			problems = append(problems, fmt.Sprintf("%s did not round-trip: wrote %q, read %q", field.Name, fmt.Sprint(w), fmt.Sprint(g)))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,84 @@
+package jellyfin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunNFOSelfTest(t *testing.T) {
+	results := RunNFOSelfTest()
+
+	if len(results) != 6 {
+		t.Fatalf("RunNFOSelfTest() returned %d results, want 6", len(results))
+	}
+
+	for _, r := range results {
+		if !r.Passed() {
+			t.Errorf("%s failed self-test: %s", r.Name, strings.Join(r.Problems, "; "))
+		}
+	}
+}
+
+func TestCheckNFOElements(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantProblem string
+	}{
+		{
+			name:    "valid movie",
+			content: `<?xml version="1.0"?><movie><title>x</title><actor><name>a</name></actor></movie>`,
+		},
+		{
+			name:        "unknown element",
+			content:     `<?xml version="1.0"?><movie><title>x</title><moviename>x</moviename></movie>`,
+			wantProblem: "element <moviename> is not read by Jellyfin in <movie>",
+		},
+		{
+			name:        "unknown root",
+			content:     `<?xml version="1.0"?><film><title>x</title></film>`,
+			wantProblem: "unknown root element <film>",
+		},
+		{
+			name:        "missing declaration",
+			content:     `<season><seasonnumber>1</seasonnumber></season>`,
+			wantProblem: "missing XML declaration",
+		},
+		{
+			name:        "malformed XML",
+			content:     `<?xml version="1.0"?><movie><title>Tom & Jerry</title></movie>`,
+			wantProblem: "XML does not parse",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := CheckNFOElements(tt.content)
+			if tt.wantProblem == "" {
+				if len(problems) != 0 {
+					t.Errorf("CheckNFOElements() = %v, want no problems", problems)
+				}
+				return
+			}
+			found := false
+			for _, p := range problems {
+				if strings.Contains(p, tt.wantProblem) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("CheckNFOElements() = %v, want a problem containing %q", problems, tt.wantProblem)
+			}
+		})
+	}
+}
+
+func TestDiffFields(t *testing.T) {
+	want := &EpisodeNFO{Title: "A & B", Season: 1}
+	got := &EpisodeNFO{Title: "A &amp; B", Season: 1}
+
+	problems := diffFields(want, got)
+	if len(problems) != 1 || !strings.Contains(problems[0], "Title") {
+		t.Errorf("diffFields() = %v, want one Title mismatch", problems)
+	}
+}
@@ -0,0 +1,33 @@
+package jellyfin
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// NonAlphaBucket is the first-letter bucket for names that do not start
+// with a letter
+const NonAlphaBucket = "#"
+
+// FirstLetterBucket returns the bucket directory a folder name is sharded
+// into: its first letter, uppercased and folded to ASCII ("Élan" -> "E").
+// A leading article is skipped first, so "The Matrix (1999)" goes to "M".
+// Names starting with a digit or symbol go to NonAlphaBucket.
+func FirstLetterBucket(name string, articles []string) string {
+	name = MoveArticleToEnd(name, articles)
+	r, _ := utf8.DecodeRuneInString(FoldASCII(name, UnmappedKeep))
+	if !unicode.IsLetter(r) {
+		return NonAlphaBucket
+	}
+	return string(unicode.ToUpper(r))
+}
+
+// IsFirstLetterBucket reports whether a directory name is a first-letter
+// bucket: a single uppercase letter or NonAlphaBucket
+func IsFirstLetterBucket(name string) bool {
+	if name == NonAlphaBucket {
+		return true
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return size == len(name) && unicode.IsUpper(r)
+}
@@ -0,0 +1,87 @@
+package jellyfin
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Separator controls how words and name parts are joined in generated
+// filenames
+type Separator string
+
+const (
+	// SeparatorSpaceDash is Jellyfin's recommended style:
+	// "Show Name - S01E01 - Pilot.mkv"
+	SeparatorSpaceDash Separator = "space-dash"
+	// SeparatorDot joins everything with dots: "Show.Name.S01E01.Pilot.mkv"
+	SeparatorDot Separator = "dot"
+	// SeparatorUnderscore joins everything with underscores:
+	// "Show_Name_S01E01_Pilot.mkv"
+	SeparatorUnderscore Separator = "underscore"
+)
+
+// NameCase controls the letter case of generated filenames
+type NameCase string
+
+const (
+	// CasePreserve keeps titles as parsed or looked up
+	CasePreserve NameCase = "preserve"
+	// CaseTitle capitalizes the first letter of every word
+	CaseTitle NameCase = "title"
+	// CaseLower lowercases the whole name
+	CaseLower NameCase = "lower"
+)
+
+// FilenameStyle is the separator and case applied to generated media
+// filenames; folder names keep Jellyfin's style so libraries still match.
+// The zero value is space-dash and preserve.
+type FilenameStyle struct {
+	Separator Separator
+	Case      NameCase
+}
+
+// separatorRuns collapses what a title's own punctuation leaves doubled
+// once spaces become dots or underscores ("Mr. Robot" -> "Mr..Robot")
+var separatorRuns = map[Separator]*regexp.Regexp{
+	SeparatorDot:        regexp.MustCompile(`\.{2,}`),
+	SeparatorUnderscore: regexp.MustCompile(`_{2,}`),
+}
+
+// Apply restyles a filename stem (without extension) built in the default
+// "Name - Part - Part" style
+func (s FilenameStyle) Apply(stem string) string {
+	switch s.Case {
+	case CaseLower:
+		stem = strings.ToLower(stem)
+	case CaseTitle:
+		stem = titleWords(stem)
+	}
+
+	var sep string
+	switch s.Separator {
+	case SeparatorDot:
+		sep = "."
+	case SeparatorUnderscore:
+		sep = "_"
+	default:
+		return stem
+	}
+	stem = strings.ReplaceAll(stem, " - ", sep)
+	stem = spaceRegex.ReplaceAllString(stem, sep)
+	return separatorRuns[s.Separator].ReplaceAllString(stem, sep)
+}
+
+// titleWords uppercases the first letter of each space-separated word,
+// leaving the rest alone so "S01E01" and "III" survive
+func titleWords(s string) string {
+	words := strings.Split(s, " ")
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		if r != utf8.RuneError && unicode.IsLower(r) {
+			words[i] = string(unicode.ToUpper(r)) + word[size:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,96 @@
+package jellyfin
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// SubtitleExtensions lists external subtitle file extensions carried along with videos
+var SubtitleExtensions = []string{".srt", ".ass", ".ssa", ".sub", ".idx", ".vtt", ".sup"}
+
+// iso6392to6391 maps ISO 639-2 (bibliographic and terminologic) codes to ISO 639-1
+var iso6392to6391 = map[string]string{
+	"ara": "ar", "bul": "bg", "cat": "ca", "ces": "cs", "cze": "cs",
+	"chi": "zh", "zho": "zh", "dan": "da", "deu": "de", "ger": "de",
+	"ell": "el", "gre": "el", "eng": "en", "spa": "es", "est": "et",
+	"fas": "fa", "per": "fa", "fin": "fi", "fra": "fr", "fre": "fr",
+	"heb": "he", "hin": "hi", "hrv": "hr", "hun": "hu", "ind": "id",
+	"isl": "is", "ice": "is", "ita": "it", "jpn": "ja", "kor": "ko",
+	"lit": "lt", "lav": "lv", "msa": "ms", "may": "ms", "nld": "nl",
+	"dut": "nl", "nor": "no", "nob": "nb", "nno": "nn", "pol": "pl",
+	"por": "pt", "ron": "ro", "rum": "ro", "rus": "ru", "slk": "sk",
+	"slo": "sk", "slv": "sl", "srp": "sr", "swe": "sv", "tha": "th",
+	"tur": "tr", "ukr": "uk", "vie": "vi",
+}
+
+// iso6391 is the set of ISO 639-1 codes accepted as-is
+var iso6391 = func() map[string]bool {
+	codes := make(map[string]bool, len(iso6392to6391))
+	for _, code := range iso6392to6391 {
+		codes[code] = true
+	}
+	return codes
+}()
+
+// SubtitleInfo describes the language and flags of an external subtitle file
+type SubtitleInfo struct {
+	Language string // ISO 639-1 code, empty if unknown
+	Forced   bool
+	SDH      bool
+}
+
+// ParseSubtitleInfo extracts the language code and forced/sdh flags from a
+// subtitle filename. videoStem is the source video filename without extension;
+// when the subtitle name starts with it, only the remainder is inspected.
+func ParseSubtitleInfo(subtitleName, videoStem string) SubtitleInfo {
+	stem := strings.TrimSuffix(subtitleName, filepath.Ext(subtitleName))
+	if videoStem != "" && strings.HasPrefix(stem, videoStem) {
+		stem = stem[len(videoStem):]
+	}
+
+	var info SubtitleInfo
+	tokens := strings.FieldsFunc(stem, func(r rune) bool {
+		return r == '.' || r == '_' || r == ' '
+	})
+
+	// Tags trail the name, so walk backwards until an unrecognized token
+	for i := len(tokens) - 1; i >= 0; i-- {
+		token := strings.ToLower(tokens[i])
+		switch {
+		case token == "forced":
+			info.Forced = true
+		case token == "sdh" || token == "cc":
+			info.SDH = true
+		case info.Language == "" && normalizeLanguage(token) != "":
+			info.Language = normalizeLanguage(token)
+		default:
+			return info
+		}
+	}
+
+	return info
+}
+
+// normalizeLanguage returns the ISO 639-1 code for an ISO 639-1/639-2 code, or ""
+func normalizeLanguage(code string) string {
+	if iso6391[code] {
+		return code
+	}
+	return iso6392to6391[code]
+}
+
+// GetSubtitlePath returns the destination path for a subtitle belonging to an
+// organized video file, e.g. "Movie (2020).en.forced.srt"
+func (n *Naming) GetSubtitlePath(videoPath string, info SubtitleInfo, ext string) string {
+	name := movieStem(videoPath)
+	if info.Language != "" {
+		name += "." + info.Language
+	}
+	if info.Forced {
+		name += ".forced"
+	}
+	if info.SDH {
+		name += ".sdh"
+	}
+	return filepath.Join(filepath.Dir(videoPath), name+strings.ToLower(ext))
+}
@@ -0,0 +1,57 @@
+package jellyfin
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSubtitleInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		subtitle  string
+		videoStem string
+		want      SubtitleInfo
+	}{
+		{"iso 639-2 forced", "Movie.eng.forced.srt", "Movie", SubtitleInfo{Language: "en", Forced: true}},
+		{"iso 639-1", "Movie.de.srt", "Movie", SubtitleInfo{Language: "de"}},
+		{"sdh flag", "Movie.2020.1080p.fre.sdh.srt", "Movie.2020.1080p", SubtitleInfo{Language: "fr", SDH: true}},
+		{"no language", "Movie.srt", "Movie", SubtitleInfo{}},
+		{"unknown language", "Movie.xyz.srt", "Movie", SubtitleInfo{}},
+		{"title word not mistaken for language", "It.srt", "It", SubtitleInfo{}},
+		{"without video stem", "Some.Film.spa.srt", "", SubtitleInfo{Language: "es"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSubtitleInfo(tt.subtitle, tt.videoStem); got != tt.want {
+				t.Errorf("ParseSubtitleInfo(%q, %q) = %+v, want %+v", tt.subtitle, tt.videoStem, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSubtitlePath(t *testing.T) {
+	n := NewNaming()
+	videoPath := filepath.Join("movies", "Movie (2020)", "Movie (2020).mkv")
+
+	tests := []struct {
+		name     string
+		subtitle string
+		want     string
+	}{
+		{"forced english", "Movie.eng.forced.srt", "Movie (2020).en.forced.srt"},
+		{"sdh french", "Movie.fre.sdh.ass", "Movie (2020).fr.sdh.ass"},
+		{"unknown language", "Movie.SRT", "Movie (2020).srt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := ParseSubtitleInfo(tt.subtitle, "Movie")
+			got := n.GetSubtitlePath(videoPath, info, filepath.Ext(tt.subtitle))
+			want := filepath.Join("movies", "Movie (2020)", tt.want)
+			if got != want {
+				t.Errorf("GetSubtitlePath() = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// errUnsupportedAudio is returned for a format whose duration cannot be read
+var errUnsupportedAudio = errors.New("unsupported audio format")
+
+// ApplyAudioDuration reads a music file's duration from its container
+// headers into metadata, unless it is already known, so enrichment can match
+// the file to a release's track by length when its track number is missing
+// or ambiguous. Failures are only logged; the file is matched without it.
+func ApplyAudioDuration(metadata *types.Metadata, path string) {
+	if metadata.MusicMetadata == nil || metadata.MusicMetadata.Duration > 0 {
+		return
+	}
+	seconds, err := ReadAudioDuration(path)
+	if err != nil {
+		log.Debug().Err(err).Str("file", path).Msg("Could not read audio duration")
+		return
+	}
+	metadata.MusicMetadata.Duration = seconds
+}
+
+// ReadAudioDuration returns the length in whole seconds of a FLAC, WAV, MP3,
+// M4A/MP4 or Ogg (Vorbis, Opus) file, read from its headers without decoding
+// any audio
+func ReadAudioDuration(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+
+	var seconds float64
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".flac":
+		seconds, err = flacDuration(f, size)
+	case ".wav":
+		seconds, err = wavDuration(f, size)
+	case ".mp3":
+		seconds, err = mp3Duration(f, size)
+	case ".m4a", ".m4b", ".mp4", ".aac", ".alac":
+		seconds, err = mp4Duration(f, 0, size)
+	case ".ogg", ".oga", ".opus":
+		seconds, err = oggDuration(f, size)
+	default:
+		err = errUnsupportedAudio
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return int(seconds + 0.5), nil
+}
+
+// id3v2Size returns the length of an ID3v2 tag at the start of r, or 0
+func id3v2Size(r io.ReaderAt) int64 {
+	header := make([]byte, 10)
+	if _, err := r.ReadAt(header, 0); err != nil || string(header[:3]) != "ID3" {
+		return 0
+	}
+	// The size is syncsafe: 7 bits per byte
+	size := int64(header[6])<<21 | int64(header[7])<<14 | int64(header[8])<<7 | int64(header[9])
+	size += 10
+	if header[5]&0x10 != 0 {
+		size += 10 // footer
+	}
+	return size
+}
+
+// flacDuration reads the total samples and sample rate from STREAMINFO
+func flacDuration(r io.ReaderAt, size int64) (float64, error) {
+	start := id3v2Size(r)
+	buf := make([]byte, 4+4+34)
+	if _, err := r.ReadAt(buf, start); err != nil {
+		return 0, err
+	}
+	if string(buf[:4]) != "fLaC" || buf[4]&0x7f != 0 {
+		return 0, errors.New("missing FLAC STREAMINFO")
+	}
+	// Sample rate (20 bits), channels (3), bits per sample (5), total samples (36)
+	packed := binary.BigEndian.Uint64(buf[8+10 : 8+18])
+	rate := packed >> 44
+	samples := packed & (1<<36 - 1)
+	if rate == 0 || samples == 0 {
+		return 0, errors.New("FLAC stream length unknown")
+	}
+	return float64(samples) / float64(rate), nil
+}
+
+// wavDuration divides the data chunk size by the fmt chunk's byte rate
+func wavDuration(r io.ReaderAt, size int64) (float64, error) {
+	header := make([]byte, 12)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return 0, err
+	}
+	if string(header[:4]) != "RIFF" || string(header[8:]) != "WAVE" {
+		return 0, errors.New("not a RIFF WAVE file")
+	}
+
+	var byteRate uint32
+	chunk := make([]byte, 8)
+	for offset := int64(12); offset+8 <= size; {
+		if _, err := r.ReadAt(chunk, offset); err != nil {
+			return 0, err
+		}
+		id, length := string(chunk[:4]), int64(binary.LittleEndian.Uint32(chunk[4:]))
+		switch id {
+		case "fmt ":
+			format := make([]byte, 12)
+			if _, err := r.ReadAt(format, offset+8); err != nil {
+				return 0, err
+			}
+			byteRate = binary.LittleEndian.Uint32(format[8:])
+		case "data":
+			if byteRate == 0 {
+				return 0, errors.New("WAV data before fmt chunk")
+			}
+			// A streamed WAV may leave the size unset; use what is there
+			if length == 0 || offset+8+length > size {
+				length = size - offset - 8
+			}
+			return float64(length) / float64(byteRate), nil
+		}
+		offset += 8 + length + length%2
+	}
+	return 0, errors.New("no WAV data chunk")
+}
+
+// MPEG audio tables for Layer III, indexed by version (MPEG-1, or MPEG-2
+// and 2.5) and the header's bitrate and sample rate fields
+var (
+	mp3Bitrates = [2][16]int{
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	}
+	mp3SampleRates = map[byte][3]int{
+		3: {44100, 48000, 32000}, // MPEG-1
+		2: {22050, 24000, 16000}, // MPEG-2
+		0: {11025, 12000, 8000},  // MPEG-2.5
+	}
+)
+
+// mp3Duration uses the frame count from a Xing/Info or VBRI header when
+// present (VBR files), otherwise the first frame's bitrate (CBR files)
+func mp3Duration(r io.ReaderAt, size int64) (float64, error) {
+	start := id3v2Size(r)
+	buf := make([]byte, 64*1024)
+	n, err := r.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	buf = buf[:n]
+
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] != 0xff || buf[i+1]&0xe0 != 0xe0 {
+			continue
+		}
+		version := (buf[i+1] >> 3) & 3
+		layer := (buf[i+1] >> 1) & 3
+		bitrateIndex := buf[i+2] >> 4
+		rateIndex := (buf[i+2] >> 2) & 3
+		rates, ok := mp3SampleRates[version]
+		if !ok || layer != 1 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+			continue
+		}
+
+		mpeg1 := version == 3
+		mono := buf[i+3]>>6 == 3
+		table, samplesPerFrame, sideInfo := 1, 576, 17
+		if mpeg1 {
+			table, samplesPerFrame, sideInfo = 0, 1152, 32
+			if mono {
+				sideInfo = 17
+			}
+		} else if mono {
+			sideInfo = 9
+		}
+		sampleRate := rates[rateIndex]
+
+		if frames := mp3FrameCount(buf[i:], sideInfo); frames > 0 {
+			return float64(frames) * float64(samplesPerFrame) / float64(sampleRate), nil
+		}
+
+		audio := size - start - int64(i)
+		tag := make([]byte, 3)
+		if _, err := r.ReadAt(tag, size-128); err == nil && string(tag) == "TAG" {
+			audio -= 128
+		}
+		bitrate := mp3Bitrates[table][bitrateIndex] * 1000
+		return float64(audio) * 8 / float64(bitrate), nil
+	}
+	return 0, errors.New("no MPEG audio frame found")
+}
+
+// mp3FrameCount returns the frame count from a Xing/Info header after the
+// first frame's side information, or a VBRI header 32 bytes in; 0 if neither
+func mp3FrameCount(frame []byte, sideInfo int) uint32 {
+	if at := 4 + sideInfo; len(frame) >= at+12 {
+		tag := string(frame[at : at+4])
+		if (tag == "Xing" || tag == "Info") && binary.BigEndian.Uint32(frame[at+4:])&1 != 0 {
+			return binary.BigEndian.Uint32(frame[at+8:])
+		}
+	}
+	if at := 4 + 32; len(frame) >= at+18 && string(frame[at:at+4]) == "VBRI" {
+		return binary.BigEndian.Uint32(frame[at+14:])
+	}
+	return 0
+}
+
+// mp4Duration finds moov/mvhd among the atoms in [offset, end) and divides
+// its duration by its timescale
+func mp4Duration(r io.ReaderAt, offset, end int64) (float64, error) {
+	header := make([]byte, 16)
+	for offset+8 <= end {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return 0, err
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		kind := string(header[4:8])
+		body := offset + 8
+		switch length {
+		case 0:
+			length = end - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return 0, err
+			}
+			length = int64(binary.BigEndian.Uint64(header[8:16]))
+			body += 8
+		}
+		if length < body-offset {
+			return 0, errors.New("corrupt MP4 atom")
+		}
+
+		switch kind {
+		case "moov":
+			return mp4Duration(r, body, offset+length)
+		case "mvhd":
+			mvhd := make([]byte, 32)
+			if _, err := r.ReadAt(mvhd, body); err != nil && err != io.EOF {
+				return 0, err
+			}
+			var timescale uint32
+			var duration uint64
+			if mvhd[0] == 1 {
+				timescale = binary.BigEndian.Uint32(mvhd[20:])
+				duration = binary.BigEndian.Uint64(mvhd[24:])
+			} else {
+				timescale = binary.BigEndian.Uint32(mvhd[12:])
+				duration = uint64(binary.BigEndian.Uint32(mvhd[16:]))
+			}
+			if timescale == 0 {
+				return 0, errors.New("MP4 timescale is zero")
+			}
+			return float64(duration) / float64(timescale), nil
+		}
+		offset += length
+	}
+	return 0, errors.New("no MP4 movie header")
+}
+
+// oggDuration divides the last page's granule position by the stream's
+// sample rate, taken from the Vorbis or Opus identification header
+func oggDuration(r io.ReaderAt, size int64) (float64, error) {
+	first := make([]byte, 27+255+64)
+	n, err := r.ReadAt(first, 0)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	first = first[:n]
+	if len(first) < 28 || string(first[:4]) != "OggS" {
+		return 0, errors.New("not an Ogg stream")
+	}
+	packet := first[27+int(first[26]):]
+
+	var rate, preSkip float64
+	switch {
+	case len(packet) >= 16 && string(packet[:7]) == "\x01vorbis":
+		rate = float64(binary.LittleEndian.Uint32(packet[12:]))
+	case len(packet) >= 12 && string(packet[:8]) == "OpusHead":
+		// Opus granules always count 48kHz samples
+		rate = 48000
+		preSkip = float64(binary.LittleEndian.Uint16(packet[10:]))
+	default:
+		return 0, errors.New("unknown Ogg codec")
+	}
+	if rate == 0 {
+		return 0, errors.New("Ogg sample rate is zero")
+	}
+
+	tailSize := int64(64 * 1024)
+	if tailSize > size {
+		tailSize = size
+	}
+	tail := make([]byte, tailSize)
+	if _, err := r.ReadAt(tail, size-tailSize); err != nil && err != io.EOF {
+		return 0, err
+	}
+	last := bytes.LastIndex(tail, []byte("OggS"))
+	if last < 0 || last+14 > len(tail) {
+		return 0, errors.New("no final Ogg page")
+	}
+	granule := float64(int64(binary.LittleEndian.Uint64(tail[last+6:])))
+	if granule <= preSkip {
+		return 0, errors.New("Ogg stream length unknown")
+	}
+	return (granule - preSkip) / rate, nil
+}
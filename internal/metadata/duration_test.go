@@ -0,0 +1,154 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// flacHeader builds a FLAC stream marker and STREAMINFO block
+func flacHeader(rate, samples uint64) []byte {
+	var b bytes.Buffer
+	b.WriteString("fLaC")
+	b.Write([]byte{0x80, 0, 0, 34}) // last block, STREAMINFO, 34 bytes
+	info := make([]byte, 34)
+	binary.BigEndian.PutUint64(info[10:], rate<<44|1<<41|15<<36|samples)
+	b.Write(info)
+	return b.Bytes()
+}
+
+// wavFile builds a PCM WAV with the given byte rate and data length
+func wavFile(byteRate uint32, dataLen int) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(4+8+16+8+dataLen))
+	b.WriteString("WAVEfmt ")
+	binary.Write(&b, binary.LittleEndian, uint32(16))
+	fmtChunk := make([]byte, 16)
+	binary.LittleEndian.PutUint32(fmtChunk[8:], byteRate)
+	b.Write(fmtChunk)
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(dataLen))
+	b.Write(make([]byte, dataLen))
+	return b.Bytes()
+}
+
+// mp3File builds an ID3v2 tag and one MPEG-1 Layer III stereo 44.1kHz
+// frame at the given bitrate index, with a Xing header when frames > 0,
+// padded to size bytes
+func mp3File(bitrateIndex byte, frames uint32, size int) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{'I', 'D', '3', 3, 0, 0, 0, 0, 0, 10})
+	b.Write(make([]byte, 10))
+	b.Write([]byte{0xff, 0xfb, bitrateIndex << 4, 0x00})
+	b.Write(make([]byte, 32))
+	if frames > 0 {
+		b.WriteString("Xing")
+		binary.Write(&b, binary.BigEndian, uint32(1))
+		binary.Write(&b, binary.BigEndian, frames)
+	}
+	b.Write(make([]byte, size-b.Len()))
+	return b.Bytes()
+}
+
+// mp4File builds ftyp and moov/mvhd atoms
+func mp4File(timescale, duration uint32) []byte {
+	mvhd := make([]byte, 8+100)
+	binary.BigEndian.PutUint32(mvhd, uint32(len(mvhd)))
+	copy(mvhd[4:], "mvhd")
+	binary.BigEndian.PutUint32(mvhd[8+12:], timescale)
+	binary.BigEndian.PutUint32(mvhd[8+16:], duration)
+
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint32(16))
+	b.WriteString("ftypM4A \x00\x00\x00\x00")
+	binary.Write(&b, binary.BigEndian, uint32(8+len(mvhd)))
+	b.WriteString("moov")
+	b.Write(mvhd)
+	return b.Bytes()
+}
+
+// oggPage builds an Ogg page holding one packet
+func oggPage(granule uint64, packet []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("OggS")
+	b.Write([]byte{0, 0})
+	binary.Write(&b, binary.LittleEndian, granule)
+	b.Write(make([]byte, 12)) // serial, sequence, checksum
+	b.Write([]byte{1, byte(len(packet))})
+	b.Write(packet)
+	return b.Bytes()
+}
+
+func TestReadAudioDuration(t *testing.T) {
+	vorbisHead := append([]byte("\x01vorbis"), make([]byte, 23)...)
+	binary.LittleEndian.PutUint32(vorbisHead[12:], 44100)
+	opusHead := append([]byte("OpusHead"), make([]byte, 11)...)
+	binary.LittleEndian.PutUint16(opusHead[10:], 312)
+
+	tests := []struct {
+		name string
+		file string
+		data []byte
+		want int
+	}{
+		{"flac", "track.flac", flacHeader(44100, 44100*241), 241},
+		{"wav", "track.wav", wavFile(4000, 4000*95), 95},
+		{"mp3 with Xing header", "track.mp3", mp3File(9, 1000, 2048), 26},        // 1000*1152/44100
+		{"mp3 constant bitrate", "track.mp3", mp3File(9, 0, 20+128*1000/8*3), 3}, // 128kbps for 3s
+		{"m4a", "track.m4a", mp4File(1000, 180400), 180},
+		{"ogg vorbis", "track.ogg", append(oggPage(0, vorbisHead), oggPage(44100*200, []byte{0})...), 200},
+		{"opus", "track.opus", append(oggPage(0, opusHead), oggPage(48000*60+312, []byte{0})...), 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadAudioDuration(path)
+			if err != nil {
+				t.Fatalf("ReadAudioDuration() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ReadAudioDuration() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unsupported or corrupt", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{"track.wma", "track.flac"} {
+			path := filepath.Join(dir, name)
+			os.WriteFile(path, []byte("not audio at all"), 0644)
+			if _, err := ReadAudioDuration(path); err == nil {
+				t.Errorf("ReadAudioDuration(%s) expected an error", name)
+			}
+		}
+	})
+}
+
+func TestApplyAudioDuration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.wav")
+	if err := os.WriteFile(path, wavFile(1000, 1000*42), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &types.Metadata{MusicMetadata: &types.MusicMetadata{}}
+	ApplyAudioDuration(meta, path)
+	if meta.MusicMetadata.Duration != 42 {
+		t.Errorf("Duration = %d, want 42", meta.MusicMetadata.Duration)
+	}
+
+	// A known duration is kept
+	meta.MusicMetadata.Duration = 7
+	ApplyAudioDuration(meta, path)
+	if meta.MusicMetadata.Duration != 7 {
+		t.Errorf("Duration = %d, want existing 7", meta.MusicMetadata.Duration)
+	}
+}
@@ -0,0 +1,22 @@
+package metadata
+
+import (
+	"regexp"
+	"strings"
+)
+
+// extraSuffixPattern matches a Jellyfin extra suffix ending a movie filename
+// (without extension): "Movie (2020)-trailer", "Movie-behindthescenes"
+var extraSuffixPattern = regexp.MustCompile(`(?i)^(.+?)\s*-(trailer|behindthescenes|deletedscene|deleted|featurette|interview|scene|short|clip|other|extra)$`)
+
+// ParseExtraSuffix splits a Jellyfin extra suffix off a movie filename
+// without extension. extraType is the lowercase suffix ("trailer",
+// "behindthescenes", ...), or "" when name is not an extra, in which case
+// base is name unchanged.
+func ParseExtraSuffix(name string) (base, extraType string) {
+	m := extraSuffixPattern.FindStringSubmatch(name)
+	if m == nil {
+		return name, ""
+	}
+	return m[1], strings.ToLower(m[2])
+}
@@ -0,0 +1,128 @@
+package metadata
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// curatedFolderPattern matches a hand-named "Title (Year)" movie folder.
+// Dots and brackets are excluded so scene release folders
+// ("The.Matrix.1999.1080p") do not count as curated.
+var curatedFolderPattern = regexp.MustCompile(`^([^.\[\]{}()]+?) \((18[5-9]\d|19\d{2}|20\d{2}|21\d{2})\)$`)
+
+// ApplyParentTitle prefers a curated "Title (Year)" parent folder over the
+// filename for a movie's title and year, as in
+// "The Matrix (1999)/the.matrix.1999.1080p.mkv": folders are usually named
+// by hand while filenames come from release groups. The folder is only
+// trusted when it plausibly names the same movie, i.e. one title contains
+// the other once case and punctuation are ignored and the years are at most
+// one apart (or the filename has none), so a "Star Wars (1977)" collection
+// folder does not rename the sequels inside it. Provider ID tokens in the
+// folder name fill IDs the filename lacks.
+func ApplyParentTitle(metadata *types.Metadata, path string) {
+	if metadata.MovieMetadata == nil {
+		return
+	}
+
+	dir, tmdbID, imdbID := extractProviderIDs(filepath.Base(filepath.Dir(path)))
+	m := curatedFolderPattern.FindStringSubmatch(dir)
+	if m == nil {
+		return
+	}
+	title := strings.TrimSpace(m[1])
+	year, _ := strconv.Atoi(m[2])
+	if !sameMovie(metadata.Title, metadata.Year, title, year) {
+		return
+	}
+
+	metadata.Title = title
+	metadata.Year = year
+	if metadata.MovieMetadata.TMDBID == 0 {
+		metadata.MovieMetadata.TMDBID = tmdbID
+	}
+	if metadata.MovieMetadata.IMDBID == "" {
+		metadata.MovieMetadata.IMDBID = imdbID
+	}
+}
+
+// sameMovie reports whether a filename's title and year could name the same
+// movie as a folder's
+func sameMovie(fileTitle string, fileYear int, folderTitle string, folderYear int) bool {
+	if fileYear > 0 && (fileYear-folderYear > 1 || folderYear-fileYear > 1) {
+		return false
+	}
+	fileKey, folderKey := titleKey(fileTitle), titleKey(folderTitle)
+	return strings.Contains(folderKey, fileKey) || strings.Contains(fileKey, folderKey)
+}
+
+// titleKey lowercases a title and drops everything but letters and digits
+func titleKey(title string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, title)
+}
+
+// discFolderPattern matches a disc subfolder inside an album ("CD1", "Disc 2")
+var discFolderPattern = regexp.MustCompile(`(?i)^(?:cd|dis[ck])\s*(\d{1,2})$`)
+
+// albumYearPattern matches an album folder ending in its year:
+// "Abbey Road (1969)" or "Abbey Road [1969]"
+var albumYearPattern = regexp.MustCompile(`^(.+?)\s*[(\[](\d{4})[)\]]$`)
+
+// ApplyMusicFolders takes a track's artist and album from the folders above
+// it, which are usually better curated than track filenames: an
+// "Artist - Album" folder names both, otherwise the grandparent folder is the
+// artist and the parent the album ("Artist/Album (1973)/01 - Track.mp3"). A
+// disc folder ("CD1", "Disc 2") between album and track sets DiscNumber and
+// is skipped. A year in the album folder fills a missing Year. Tracks not at
+// least two folders deep keep their filename metadata.
+func ApplyMusicFolders(metadata *types.Metadata, path string) {
+	if metadata.MusicMetadata == nil {
+		return
+	}
+	music := metadata.MusicMetadata
+
+	dir := filepath.Dir(path)
+	disc := 0
+	if m := discFolderPattern.FindStringSubmatch(filepath.Base(dir)); m != nil {
+		disc, _ = strconv.Atoi(m[1])
+		dir = filepath.Dir(dir)
+	}
+
+	parent := filepath.Dir(dir)
+	if parent == dir {
+		return
+	}
+	artist, album := splitCreatorTitle(filepath.Base(dir))
+	if artist == "" {
+		if filepath.Dir(parent) == parent {
+			return
+		}
+		artist = filepath.Base(parent)
+	}
+	year := 0
+	if m := albumYearPattern.FindStringSubmatch(album); m != nil {
+		album = strings.TrimSpace(m[1])
+		year, _ = strconv.Atoi(m[2])
+	}
+	if artist == "" || album == "" {
+		return
+	}
+
+	music.Artist = artist
+	music.Album = album
+	if disc > 0 && music.DiscNumber == 0 {
+		music.DiscNumber = disc
+	}
+	if metadata.Year == 0 {
+		metadata.Year = year
+	}
+}
@@ -0,0 +1,38 @@
+package metadata
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// providerIDPattern matches provider ID tokens embedded in filenames, in the
+// forms Jellyfin itself recognizes: {tmdb-12345}, [tmdbid-12345],
+// {imdb-tt0133093} and [imdbid-tt0133093]
+var providerIDPattern = regexp.MustCompile(`(?i)[\[\{]\s*(tmdb|imdb)(?:id)?[-=]\s*(tt\d+|\d+)\s*[\]\}]`)
+
+// extractProviderIDs removes provider ID tokens from name and returns the
+// remaining name with the TMDB and IMDb IDs found (0 and "" if absent).
+// IMDb IDs are normalized to lowercase "tt" form; a non-numeric TMDB ID or an
+// IMDb ID without the "tt" prefix is ignored but still removed from the name.
+func extractProviderIDs(name string) (string, int, string) {
+	var tmdbID int
+	var imdbID string
+
+	for _, m := range providerIDPattern.FindAllStringSubmatch(name, -1) {
+		value := strings.ToLower(m[2])
+		switch strings.ToLower(m[1]) {
+		case "tmdb":
+			if id, err := strconv.Atoi(value); err == nil && id > 0 {
+				tmdbID = id
+			}
+		case "imdb":
+			if strings.HasPrefix(value, "tt") {
+				imdbID = value
+			}
+		}
+	}
+
+	stripped := strings.TrimSpace(providerIDPattern.ReplaceAllString(name, " "))
+	return stripped, tmdbID, imdbID
+}
@@ -0,0 +1,177 @@
+package metadata
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// mediaInfoExtensions are the companion report names tried next to a video,
+// as "<video name><ext>"
+var mediaInfoExtensions = []string{".mediainfo", ".txt"}
+
+// MediaInfo is the video track described by a MediaInfo text report
+type MediaInfo struct {
+	Width  int
+	Height int
+	// Codec uses the filename tag spelling: "x264", "h264", "h265", ...
+	Codec string
+}
+
+// Quality returns the resolution tag for the frame size as the filename
+// parser spells it ("2160P", "1080P", ...), or "" when the size is unknown.
+// Width is checked as well as height so cropped widescreen frames
+// (1920x800) rank with their full-height release.
+func (m MediaInfo) Quality() string {
+	switch {
+	case m.Width >= 7600 || m.Height >= 4300:
+		return "8K"
+	case m.Width >= 3800 || m.Height >= 2100:
+		return "2160P"
+	case m.Width >= 1900 || m.Height >= 1060:
+		return "1080P"
+	case m.Width >= 1260 || m.Height >= 700:
+		return "720P"
+	case m.Height >= 470:
+		return "480P"
+	default:
+		return ""
+	}
+}
+
+// ParseMediaInfo reads the first video section of a report in MediaInfo's
+// default text format:
+//
+//	Video
+//	Format                                   : HEVC
+//	Width                                    : 3 840 pixels
+//	Height                                   : 2 160 pixels
+//	Writing library                          : x265 - 3.5
+//
+// A report without a video section yields a zero MediaInfo.
+func ParseMediaInfo(r io.Reader) (MediaInfo, error) {
+	var info MediaInfo
+	var format, library string
+	inVideo, seenVideo := false, false
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			// Section header: "General", "Video", "Video #1", "Audio #2", ...
+			if inVideo {
+				break
+			}
+			inVideo = !seenVideo && (line == "Video" || strings.HasPrefix(line, "Video #"))
+			seenVideo = seenVideo || inVideo
+			continue
+		}
+		if !inVideo {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Width":
+			info.Width = pixels(value)
+		case "Height":
+			info.Height = pixels(value)
+		case "Format":
+			format = value
+		case "Writing library":
+			library = value
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return MediaInfo{}, err
+	}
+
+	info.Codec = mediaInfoCodec(format, library)
+	return info, nil
+}
+
+// pixels parses a MediaInfo dimension such as "1 920 pixels"
+func pixels(value string) int {
+	value = strings.TrimSpace(strings.TrimSuffix(value, "pixels"))
+	n, _ := strconv.Atoi(strings.ReplaceAll(value, " ", ""))
+	return n
+}
+
+// mediaInfoCodec maps a video format to the codec tag used in filenames,
+// preferring the encoder named in the writing library (x264, x265, XviD)
+func mediaInfoCodec(format, library string) string {
+	lib := strings.ToLower(library)
+	for _, encoder := range []string{"x264", "x265", "xvid"} {
+		if strings.Contains(lib, encoder) {
+			return encoder
+		}
+	}
+
+	switch strings.ToUpper(format) {
+	case "":
+		return ""
+	case "AVC":
+		return "h264"
+	case "HEVC":
+		return "h265"
+	default:
+		return strings.ToLower(format)
+	}
+}
+
+// FindMediaInfo returns the companion report for a video ("Movie.mediainfo"
+// or "Movie.txt" beside "Movie.mkv"), or "" when there is none
+func FindMediaInfo(path string) string {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, ext := range mediaInfoExtensions {
+		candidate := base + ext
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// ApplyMediaInfo overrides Quality and Codec with the values in a video's
+// companion MediaInfo report, which are measured rather than guessed from
+// the release name. Only movie and TV metadata is touched, and a report
+// without a video section (e.g. an unrelated .txt) changes nothing.
+func ApplyMediaInfo(metadata *types.Metadata, path string) {
+	if metadata.MovieMetadata == nil && metadata.TVMetadata == nil {
+		return
+	}
+	report := FindMediaInfo(path)
+	if report == "" {
+		return
+	}
+
+	f, err := os.Open(report)
+	if err != nil {
+		log.Debug().Err(err).Str("file", report).Msg("Could not open MediaInfo report")
+		return
+	}
+	defer f.Close()
+
+	info, err := ParseMediaInfo(f)
+	if err != nil {
+		log.Debug().Err(err).Str("file", report).Msg("Could not read MediaInfo report")
+		return
+	}
+	if quality := info.Quality(); quality != "" {
+		metadata.Quality = quality
+	}
+	if info.Codec != "" {
+		metadata.Codec = info.Codec
+	}
+}
@@ -0,0 +1,102 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+const hevcReport = `General
+Complete name                            : Movie.mkv
+Format                                   : Matroska
+File size                                : 14.2 GiB
+
+Video
+ID                                       : 1
+Format                                   : HEVC
+Format/Info                              : High Efficiency Video Coding
+Width                                    : 3 840 pixels
+Height                                   : 1 600 pixels
+Display aspect ratio                     : 2.40:1
+Writing library                          : x265 3.5+1-f0c1022b6:[Linux][GCC 10.2.1][64 bit] 10bit
+
+Audio
+Format                                   : E-AC-3
+`
+
+func TestParseMediaInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		report      string
+		wantWidth   int
+		wantHeight  int
+		wantCodec   string
+		wantQuality string
+	}{
+		{"hevc with encoder", hevcReport, 3840, 1600, "x265", "2160P"},
+		{
+			"avc without encoder",
+			"Video\nFormat : AVC\nWidth : 1 280 pixels\nHeight : 720 pixels\n",
+			1280, 720, "h264", "720P",
+		},
+		{
+			"cropped widescreen",
+			"General\nFormat : MPEG-4\n\nVideo #1\nFormat : HEVC\nWidth : 1 920 pixels\nHeight : 800 pixels\n\nVideo #2\nWidth : 640 pixels\n",
+			1920, 800, "h265", "1080P",
+		},
+		{
+			"xvid",
+			"Video\nFormat : MPEG-4 Visual\nWidth : 720 pixels\nHeight : 480 pixels\nWriting library : XviD 64\n",
+			720, 480, "xvid", "480P",
+		},
+		{"no video section", "General\nFormat : Matroska\n\nAudio\nFormat : FLAC\n", 0, 0, "", ""},
+		{"unrelated text", "Thanks for downloading!\nVisit us again.\n", 0, 0, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMediaInfo(strings.NewReader(tt.report))
+			if err != nil {
+				t.Fatalf("ParseMediaInfo() error = %v", err)
+			}
+			if got.Width != tt.wantWidth || got.Height != tt.wantHeight {
+				t.Errorf("size = %dx%d, want %dx%d", got.Width, got.Height, tt.wantWidth, tt.wantHeight)
+			}
+			if got.Codec != tt.wantCodec {
+				t.Errorf("Codec = %q, want %q", got.Codec, tt.wantCodec)
+			}
+			if q := got.Quality(); q != tt.wantQuality {
+				t.Errorf("Quality() = %q, want %q", q, tt.wantQuality)
+			}
+		})
+	}
+}
+
+func TestApplyMediaInfo(t *testing.T) {
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Movie.2020.720p.x264.mkv")
+	report := filepath.Join(dir, "Movie.2020.720p.x264.mediainfo")
+	if err := os.WriteFile(report, []byte(hevcReport), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &types.Metadata{Quality: "720P", Codec: "x264", MovieMetadata: &types.MovieMetadata{}}
+	ApplyMediaInfo(meta, video)
+	if meta.Quality != "2160P" || meta.Codec != "x265" {
+		t.Errorf("got %s %s, want the report's 2160P x265 over the filename tags", meta.Quality, meta.Codec)
+	}
+
+	// A .txt without a video section leaves the filename tags alone
+	other := filepath.Join(dir, "Other.2020.1080p.mkv")
+	if err := os.WriteFile(filepath.Join(dir, "Other.2020.1080p.txt"), []byte("Thanks for downloading!\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta = &types.Metadata{Quality: "1080P", Codec: "x264", MovieMetadata: &types.MovieMetadata{}}
+	ApplyMediaInfo(meta, other)
+	if meta.Quality != "1080P" || meta.Codec != "x264" {
+		t.Errorf("got %s %s, want the filename tags kept", meta.Quality, meta.Codec)
+	}
+}
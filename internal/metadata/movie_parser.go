@@ -33,9 +33,9 @@ func NewMovieParser() MovieParser {
 		// Capture title (non-greedy) and year
 		// Supports years 1850-2199 (extended to cover 21st century beyond 2100)
 		titleYearPattern: regexp.MustCompile(`^(.+?)[\[\(._\s]+(18[5-9]\d|19\d{2}|20\d{2}|21\d{2})[\]\)._\s]*`),
-		qualityPattern:   regexp.MustCompile(`(?i)(4K|8K|2160p|1080p|720p|480p|UHD|HD)`),
-		sourcePattern:    regexp.MustCompile(`(?i)(BluRay|Blu-Ray|BRRip|BDRip|WEB-DL|WEBRip|WEBDL|DVDRip|DVD-Rip|HDTV|PDTV|HDRip)`),
-		codecPattern:     regexp.MustCompile(`(?i)(x264|x265|h264|h265|HEVC|AVC|XviD)`),
+		qualityPattern:   qualityTagPattern,
+		sourcePattern:    sourceTagPattern,
+		codecPattern:     codecTagPattern,
 		yearPattern:      regexp.MustCompile(`[\[\(._\s](18[5-9]\d|19\d{2}|20\d{2}|21\d{2})[\]\)._\s]`),
 	}
 }
@@ -46,8 +46,14 @@ func (m *movieParser) Parse(filename string) (*types.Metadata, error) {
 		MovieMetadata: &types.MovieMetadata{},
 	}
 
-	// Remove extension
-	name := util.RemoveExtension(filename)
+	// Remove extension and any {tmdb-...}/{imdb-...} tokens
+	name, tmdbID, imdbID := extractProviderIDs(util.RemoveExtension(filename))
+	metadata.MovieMetadata.TMDBID = tmdbID
+	metadata.MovieMetadata.IMDBID = imdbID
+
+	// A "-trailer"-style suffix marks an extra of the movie rather than the
+	// movie itself; the rest of the name still identifies the movie
+	name, metadata.MovieMetadata.ExtraType = ParseExtraSuffix(name)
 
 	// Extract title and year
 	matches := m.titleYearPattern.FindStringSubmatch(name)
@@ -92,5 +98,8 @@ func (m *movieParser) Parse(filename string) (*types.Metadata, error) {
 		metadata.Codec = strings.ToLower(codecMatch)
 	}
 
+	parseReleaseFlags(name, metadata)
+	parseReleaseGroup(name, metadata)
+
 	return metadata, nil
 }
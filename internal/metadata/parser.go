@@ -1,6 +1,10 @@
 package metadata
 
 import (
+	"strconv"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
@@ -31,8 +35,44 @@ func (p *parser) Parse(filename string, mediaType types.MediaType) (*types.Metad
 		return p.movieParser.Parse(filename)
 	case types.MediaTypeTV:
 		return p.tvParser.Parse(filename)
+	case types.MediaTypeMusic:
+		return parseMusicFilename(filename), nil
+	case types.MediaTypeBook:
+		return parseBookFilename(filename), nil
 	default:
-		// For music and books, we'll implement later
 		return &types.Metadata{}, nil
 	}
 }
+
+// splitCreatorTitle splits a "Creator - Title" name at its first " - ".
+// creator is empty when there is no separator.
+func splitCreatorTitle(name string) (creator, title string) {
+	if i := strings.Index(name, " - "); i >= 0 {
+		return strings.TrimSpace(name[:i]), strings.TrimSpace(name[i+3:])
+	}
+	return "", strings.TrimSpace(name)
+}
+
+// parseMusicFilename reads "Artist - Title", "NN - Title" and
+// "Artist - NN - Title" track names; tags, when read later, take precedence
+func parseMusicFilename(filename string) *types.Metadata {
+	artist, title := splitCreatorTitle(util.RemoveExtension(filename))
+	music := &types.MusicMetadata{}
+	if n, err := strconv.Atoi(artist); err == nil && n > 0 {
+		music.TrackNumber = n
+		artist = ""
+	} else if track, rest := splitCreatorTitle(title); track != "" {
+		if n, err := strconv.Atoi(track); err == nil && n > 0 {
+			music.TrackNumber = n
+			title = rest
+		}
+	}
+	music.Artist = artist
+	return &types.Metadata{Title: util.CleanTitle(title), MusicMetadata: music}
+}
+
+// parseBookFilename reads "Author - Title" book names
+func parseBookFilename(filename string) *types.Metadata {
+	author, title := splitCreatorTitle(util.RemoveExtension(filename))
+	return &types.Metadata{Title: util.CleanTitle(title), BookMetadata: &types.BookMetadata{Author: author}}
+}
@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/opd-ai/go-jf-org/pkg/types"
@@ -137,6 +138,41 @@ func TestMovieParser_Parse(t *testing.T) {
 	}
 }
 
+func TestMovieParser_Parse_ExtraSuffix(t *testing.T) {
+	tests := []struct {
+		filename      string
+		wantTitle     string
+		wantYear      int
+		wantExtraType string
+	}{
+		{"Movie (2020)-trailer.mkv", "Movie", 2020, "trailer"},
+		{"Movie-behindthescenes.mkv", "Movie", 0, "behindthescenes"},
+		{"The.Matrix.1999-Featurette.mp4", "The Matrix", 1999, "featurette"},
+		{"Movie (2020) -deletedscene.mkv", "Movie", 2020, "deletedscene"},
+		{"Movie (2020).mkv", "Movie", 2020, ""},
+		// A trailing release group is not an extra
+		{"Movie.2020.1080p.BluRay.x264-SPARKS.mkv", "Movie", 2020, ""},
+		// The suffix must be the whole last token
+		{"Movie-trailers.mkv", "Movie-trailers", 0, ""},
+	}
+
+	parser := NewMovieParser()
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, err := parser.Parse(tt.filename)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.Title != tt.wantTitle || got.Year != tt.wantYear {
+				t.Errorf("Parse() = %q (%d), want %q (%d)", got.Title, got.Year, tt.wantTitle, tt.wantYear)
+			}
+			if got.MovieMetadata.ExtraType != tt.wantExtraType {
+				t.Errorf("ExtraType = %q, want %q", got.MovieMetadata.ExtraType, tt.wantExtraType)
+			}
+		})
+	}
+}
+
 func TestTVParser_Parse(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -145,6 +181,8 @@ func TestTVParser_Parse(t *testing.T) {
 		wantSeason       int
 		wantEpisode      int
 		wantEpisodeTitle string
+		wantAirDate      string
+		wantEpisodeEnd   int
 	}{
 		{
 			name:          "standard S01E01 format",
@@ -210,6 +248,80 @@ func TestTVParser_Parse(t *testing.T) {
 			wantSeason:    2,
 			wantEpisode:   10,
 		},
+		{
+			name:           "two-episode file",
+			filename:       "Show.S01E01E02.720p.mkv",
+			wantShowTitle:  "Show",
+			wantSeason:     1,
+			wantEpisode:    1,
+			wantEpisodeEnd: 2,
+		},
+		{
+			name:             "three-episode file with title",
+			filename:         "The.Show.S02E01E02E03.Pilot.Parts.1080p.mkv",
+			wantShowTitle:    "The Show",
+			wantSeason:       2,
+			wantEpisode:      1,
+			wantEpisodeEnd:   3,
+			wantEpisodeTitle: "Pilot Parts",
+		},
+		{
+			name:           "dashed episode range",
+			filename:       "Show.S01E04-E06.mkv",
+			wantShowTitle:  "Show",
+			wantSeason:     1,
+			wantEpisode:    4,
+			wantEpisodeEnd: 6,
+		},
+		{
+			name:          "daily show with dotted date",
+			filename:      "The.Daily.Show.2023.05.15.720p.WEB.h264.mkv",
+			wantShowTitle: "The Daily Show",
+			wantSeason:    2023,
+			wantAirDate:   "2023-05-15",
+		},
+		{
+			name:             "daily show with dashed date and guest",
+			filename:         "Late Night - 2021-11-03 - Guest Name - 1080p HDTV.mp4",
+			wantShowTitle:    "Late Night",
+			wantSeason:       2021,
+			wantAirDate:      "2021-11-03",
+			wantEpisodeTitle: "Guest Name",
+		},
+		{
+			name:          "daily pattern ignored when S01E01 present",
+			filename:      "Show.2023.05.15.S01E02.mkv",
+			wantShowTitle: "Show 2023 05 15",
+			wantSeason:    1,
+			wantEpisode:   2,
+		},
+		{
+			name:          "invalid date is not an air date",
+			filename:      "Show.2023.13.45.mkv",
+			wantShowTitle: "",
+		},
+		{
+			name:          "mini-series episode without season",
+			filename:      "Chernobyl.E01.1080p.BluRay.x264.mkv",
+			wantShowTitle: "Chernobyl",
+			wantSeason:    1,
+			wantEpisode:   1,
+		},
+		{
+			name:             "mini-series episode with title",
+			filename:         "Band.of.Brothers.E03.Carentan.720p.HDTV.mkv",
+			wantShowTitle:    "Band of Brothers",
+			wantSeason:       1,
+			wantEpisode:      3,
+			wantEpisodeTitle: "Carentan",
+		},
+		{
+			name:          "mini-series part without season",
+			filename:      "The Night Manager - Part 2.mkv",
+			wantShowTitle: "The Night Manager",
+			wantSeason:    1,
+			wantEpisode:   2,
+		},
 	}
 
 	parser := NewTVParser()
@@ -237,6 +349,76 @@ func TestTVParser_Parse(t *testing.T) {
 			if tt.wantEpisodeTitle != "" && got.TVMetadata.EpisodeTitle != tt.wantEpisodeTitle {
 				t.Errorf("EpisodeTitle = %q, want %q", got.TVMetadata.EpisodeTitle, tt.wantEpisodeTitle)
 			}
+			if got.TVMetadata.EpisodeEnd != tt.wantEpisodeEnd {
+				t.Errorf("EpisodeEnd = %d, want %d", got.TVMetadata.EpisodeEnd, tt.wantEpisodeEnd)
+			}
+			if got.TVMetadata.AirDate != tt.wantAirDate {
+				t.Errorf("AirDate = %q, want %q", got.TVMetadata.AirDate, tt.wantAirDate)
+			}
+		})
+	}
+}
+
+func TestTVParser_Parse_InvalidEpisodeSpan(t *testing.T) {
+	parser := NewTVParser()
+
+	for _, filename := range []string{"Show.S01E05E02.mkv", "Show.S01E03-E03.mkv"} {
+		if _, err := parser.Parse(filename); err == nil {
+			t.Errorf("Parse(%q) should reject a non-ascending episode span", filename)
+		}
+	}
+}
+
+func TestParse_ProviderIDTokens(t *testing.T) {
+	tests := []struct {
+		name       string
+		filename   string
+		mediaType  types.MediaType
+		wantTitle  string
+		wantYear   int
+		wantTMDBID int
+		wantIMDBID string
+	}{
+		{"movie tmdb braces", "Movie (2020) {tmdb-12345}.mkv", types.MediaTypeMovie, "Movie", 2020, 12345, ""},
+		{"movie imdb braces", "The Matrix (1999) {imdb-tt0133093}.mkv", types.MediaTypeMovie, "The Matrix", 1999, 0, "tt0133093"},
+		{"movie jellyfin brackets", "The Matrix (1999) [tmdbid-603] [imdbid-tt0133093].mkv", types.MediaTypeMovie, "The Matrix", 1999, 603, "tt0133093"},
+		{"movie token before year", "Inception {tmdb-27205} 2010 1080p.mkv", types.MediaTypeMovie, "Inception", 2010, 27205, ""},
+		{"movie uppercase imdb", "Movie.2020.{IMDB-TT1234567}.mkv", types.MediaTypeMovie, "Movie", 2020, 0, "tt1234567"},
+		{"movie without token", "The.Matrix.1999.1080p.mkv", types.MediaTypeMovie, "The Matrix", 1999, 0, ""},
+		{"tv tmdb", "Breaking Bad {tmdb-1396} S01E01.mkv", types.MediaTypeTV, "Breaking Bad", 0, 1396, ""},
+		{"tv imdb", "Breaking.Bad.S01E01.[imdbid-tt0903747].mkv", types.MediaTypeTV, "Breaking Bad", 0, 0, "tt0903747"},
+	}
+
+	parser := NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := parser.Parse(tt.filename, tt.mediaType)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var title string
+			var tmdbID int
+			var imdbID string
+			if tt.mediaType == types.MediaTypeTV {
+				title, tmdbID, imdbID = m.TVMetadata.ShowTitle, m.TVMetadata.TMDBID, m.TVMetadata.IMDBID
+			} else {
+				title, tmdbID, imdbID = m.Title, m.MovieMetadata.TMDBID, m.MovieMetadata.IMDBID
+			}
+
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if m.Year != tt.wantYear {
+				t.Errorf("Year = %d, want %d", m.Year, tt.wantYear)
+			}
+			if tmdbID != tt.wantTMDBID {
+				t.Errorf("TMDBID = %d, want %d", tmdbID, tt.wantTMDBID)
+			}
+			if imdbID != tt.wantIMDBID {
+				t.Errorf("IMDBID = %q, want %q", imdbID, tt.wantIMDBID)
+			}
 		})
 	}
 }
@@ -280,6 +462,42 @@ func TestParser_Parse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:      "music artist and track number",
+			filename:  "Pink Floyd - 03 - Time.mp3",
+			mediaType: types.MediaTypeMusic,
+			wantTitle: "Time",
+			checkFunc: func(t *testing.T, m *types.Metadata) {
+				if m.MusicMetadata == nil {
+					t.Fatal("MusicMetadata should not be nil for music")
+				}
+				if m.MusicMetadata.Artist != "Pink Floyd" || m.MusicMetadata.TrackNumber != 3 {
+					t.Errorf("MusicMetadata = %+v, want Pink Floyd track 3", m.MusicMetadata)
+				}
+			},
+		},
+		{
+			name:      "music track number only",
+			filename:  "07 - Us and Them.flac",
+			mediaType: types.MediaTypeMusic,
+			wantTitle: "Us and Them",
+			checkFunc: func(t *testing.T, m *types.Metadata) {
+				if m.MusicMetadata.Artist != "" || m.MusicMetadata.TrackNumber != 7 {
+					t.Errorf("MusicMetadata = %+v, want no artist, track 7", m.MusicMetadata)
+				}
+			},
+		},
+		{
+			name:      "book author and title",
+			filename:  "Frank Herbert - Dune.epub",
+			mediaType: types.MediaTypeBook,
+			wantTitle: "Dune",
+			checkFunc: func(t *testing.T, m *types.Metadata) {
+				if m.BookMetadata == nil || m.BookMetadata.Author != "Frank Herbert" {
+					t.Errorf("BookMetadata = %+v, want author Frank Herbert", m.BookMetadata)
+				}
+			},
+		},
 		{
 			name:      "unknown type returns empty metadata",
 			filename:  "file.txt",
@@ -315,3 +533,82 @@ func TestParser_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyParentTitle(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		wantTitle string
+		wantYear  int
+		wantTMDB  int
+	}{
+		{"folder gives cleaner title", "/media/The Matrix (1999)/the.matrix.1999.1080p.BluRay.x264.mkv", "The Matrix", 1999, 0},
+		{"folder punctuation kept", "/media/Spider-Man (2002)/spider.man.2002.720p.mkv", "Spider-Man", 2002, 0},
+		{"yearless filename", "/media/Heat (1995)/heat.mkv", "Heat", 1995, 0},
+		{"folder year within one", "/media/Parasite (2019)/Parasite.2020.1080p.mkv", "Parasite", 2019, 0},
+		{"folder provider id", "/media/Alien (1979) [tmdbid-348]/alien.1979.mkv", "Alien", 1979, 348},
+		{"collection folder ignored", "/media/Star Wars (1977)/Star.Wars.The.Empire.Strikes.Back.1980.mkv", "Star Wars The Empire Strikes Back", 1980, 0},
+		{"unrelated folder ignored", "/media/Downloads (2020)/Inception.2020.mkv", "Inception", 2020, 0},
+		{"scene folder ignored", "/dl/The.Matrix.1999.1080p.BluRay/matrix.mkv", "matrix", 0, 0},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse(filepath.Base(tt.path), types.MediaTypeMovie)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			ApplyParentTitle(got, tt.path)
+			if got.Title != tt.wantTitle || got.Year != tt.wantYear {
+				t.Errorf("title/year = %q/%d, want %q/%d", got.Title, got.Year, tt.wantTitle, tt.wantYear)
+			}
+			if got.MovieMetadata.TMDBID != tt.wantTMDB {
+				t.Errorf("TMDBID = %d, want %d", got.MovieMetadata.TMDBID, tt.wantTMDB)
+			}
+		})
+	}
+}
+
+func TestApplyMusicFolders(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantArtist string
+		wantAlbum  string
+		wantYear   int
+		wantDisc   int
+		wantTrack  int
+	}{
+		{"artist and album folders", "/music/Pink Floyd/The Wall/01 - In the Flesh.mp3", "Pink Floyd", "The Wall", 0, 0, 1},
+		{"folder beats filename artist", "/music/Pink Floyd/Animals (1977)/pink floyd - Dogs.flac", "Pink Floyd", "Animals", 1977, 0, 0},
+		{"artist - album folder", "/downloads/Pink Floyd - Dark Side of the Moon/01 - Speak to Me.mp3", "Pink Floyd", "Dark Side of the Moon", 0, 0, 1},
+		{"disc folder skipped", "/music/The Beatles/The Beatles [1968]/CD2/01 - Birthday.mp3", "The Beatles", "The Beatles", 1968, 2, 1},
+		{"too shallow keeps filename", "/Artist - Song.mp3", "Artist", "", 0, 0, 0},
+		{"one folder keeps filename", "/downloads/Artist - Song.mp3", "Artist", "", 0, 0, 0},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse(filepath.Base(tt.path), types.MediaTypeMusic)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			ApplyMusicFolders(got, tt.path)
+			music := got.MusicMetadata
+			if music.Artist != tt.wantArtist || music.Album != tt.wantAlbum {
+				t.Errorf("artist/album = %q/%q, want %q/%q", music.Artist, music.Album, tt.wantArtist, tt.wantAlbum)
+			}
+			if got.Year != tt.wantYear {
+				t.Errorf("Year = %d, want %d", got.Year, tt.wantYear)
+			}
+			if music.DiscNumber != tt.wantDisc {
+				t.Errorf("DiscNumber = %d, want %d", music.DiscNumber, tt.wantDisc)
+			}
+			if music.TrackNumber != tt.wantTrack {
+				t.Errorf("TrackNumber = %d, want %d", music.TrackNumber, tt.wantTrack)
+			}
+		})
+	}
+}
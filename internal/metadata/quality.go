@@ -0,0 +1,229 @@
+package metadata
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/internal/util"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+var (
+	// qualityTagPattern matches quality tags (1080p, 720p, 4K, etc.)
+	qualityTagPattern = regexp.MustCompile(`(?i)(4K|8K|2160p|1080p|720p|480p|UHD|HD)`)
+	// sourceTagPattern matches source tags (BluRay, WEB-DL, etc.)
+	sourceTagPattern = regexp.MustCompile(`(?i)(BluRay|Blu-Ray|BRRip|BDRip|WEB-DL|WEBRip|WEBDL|DVDRip|DVD-Rip|HDTV|PDTV|HDRip)`)
+	// codecTagPattern matches codec tags (x264, h265, etc.)
+	codecTagPattern = regexp.MustCompile(`(?i)(x264|x265|h264|h265|HEVC|AVC|XviD)`)
+)
+
+// releaseTokenSeparators splits a release name into its dot/space separated tokens
+var releaseTokenSeparators = regexp.MustCompile(`[\[\]\(\)._\s-]+`)
+
+// parseReleaseFlags sets IsProper/IsRepack from the release tokens in name
+func parseReleaseFlags(name string, metadata *types.Metadata) {
+	for _, token := range releaseTokenSeparators.Split(name, -1) {
+		switch strings.ToUpper(token) {
+		case "PROPER":
+			metadata.IsProper = true
+		case "REPACK":
+			metadata.IsRepack = true
+		}
+	}
+}
+
+// releaseGroupPattern matches a trailing "-GROUP" token, optionally followed
+// by a bracketed indexer tag ("-SPARKS[rarbg]")
+var releaseGroupPattern = regexp.MustCompile(`-([A-Za-z0-9]{2,})(?:\[[^\]]*\])?$`)
+
+// notReleaseGroups are trailing tokens that complete a hyphenated release
+// tag rather than naming a group ("WEB-DL", "DVD-Rip", "Blu-Ray")
+var notReleaseGroups = map[string]bool{
+	"DL": true, "RIP": true, "RAY": true, "HD": true, "SD": true, "AUDIO": true,
+}
+
+// releaseMarkerPattern matches the quality, source and codec tags that mark
+// a scene-style release name
+var releaseMarkerPattern = regexp.MustCompile(`(?i)\b(?:2160p|1080p|720p|480p|4K|UHD|BluRay|Blu-Ray|BRRip|BDRip|WEB-DL|WEBRip|WEBDL|WEB|DVDRip|HDTV|PDTV|HDRip|x264|x265|h264|h265|HEVC|AVC|XviD)\b`)
+
+// parseReleaseGroup sets ReleaseGroup from a trailing "-GROUP" token. Only
+// names that carry a release marker (quality, source or codec) are considered,
+// so hyphenated titles like "Spider-Man.mkv" are not mistaken for a group.
+func parseReleaseGroup(name string, metadata *types.Metadata) {
+	if !releaseMarkerPattern.MatchString(name) {
+		return
+	}
+	m := releaseGroupPattern.FindStringSubmatch(name)
+	if m == nil {
+		return
+	}
+	group := m[1]
+	if notReleaseGroups[strings.ToUpper(group)] || resolutionRank(group) > 0 || episodeTokenPattern.MatchString(group) {
+		return
+	}
+	metadata.ReleaseGroup = group
+}
+
+// ApplyParentRelease fills Quality, Source, Codec and ReleaseGroup from the
+// directory holding path when the filename itself carries no release tags,
+// as in "Movie.2020.1080p.BluRay.x264-GRP/Movie.mkv". Fields the filename
+// already set are kept, and a directory name without a release marker
+// ("Movies", "Season 1") is ignored. Only movie and TV metadata is touched.
+func ApplyParentRelease(metadata *types.Metadata, path string) {
+	if metadata.MovieMetadata == nil && metadata.TVMetadata == nil {
+		return
+	}
+	if releaseMarkerPattern.MatchString(util.RemoveExtension(filepath.Base(path))) {
+		return
+	}
+	dir := filepath.Base(filepath.Dir(path))
+	if !releaseMarkerPattern.MatchString(dir) {
+		return
+	}
+
+	if metadata.Quality == "" {
+		metadata.Quality = strings.ToUpper(qualityTagPattern.FindString(dir))
+	}
+	if metadata.Source == "" {
+		metadata.Source = sourceTagPattern.FindString(dir)
+	}
+	if metadata.Codec == "" {
+		metadata.Codec = strings.ToLower(codecTagPattern.FindString(dir))
+	}
+	if metadata.ReleaseGroup == "" {
+		parseReleaseGroup(dir, metadata)
+	}
+}
+
+// episodeTokenPattern matches the tail of an episode span ("S01E01-E03")
+var episodeTokenPattern = regexp.MustCompile(`(?i)^E\d+$`)
+
+// stripReleaseFlags removes upper-case PROPER/REPACK words that leaked into a
+// cleaned title (e.g. "Show.S01E01.REPACK.720p" has no real episode title)
+func stripReleaseFlags(title string) string {
+	words := strings.Fields(title)
+	kept := words[:0]
+	for _, word := range words {
+		if word == "PROPER" || word == "REPACK" {
+			continue
+		}
+		kept = append(kept, word)
+	}
+	return strings.Join(kept, " ")
+}
+
+// resolutionRank orders quality tags from lowest to highest resolution;
+// unknown or empty quality ranks lowest
+func resolutionRank(quality string) int {
+	switch strings.ToUpper(quality) {
+	case "8K":
+		return 6
+	case "4K", "2160P", "UHD":
+		return 5
+	case "1080P":
+		return 4
+	case "720P", "HD":
+		return 3
+	case "480P":
+		return 2
+	default:
+		return 0
+	}
+}
+
+// CompareQuality compares two releases of the same media. It returns a
+// positive number when a is preferred, negative when b is preferred and 0
+// when neither wins. Higher resolution always wins; at equal resolution a
+// PROPER or REPACK re-release wins over an original release.
+func CompareQuality(a, b *types.Metadata) int {
+	return CompareQualityWithGroups(a, b, nil)
+}
+
+// CompareQualityWithGroups is CompareQuality with a final tie-break on release
+// group: trustedGroups lists groups from most to least trusted (matched
+// case-insensitively), and any listed group beats an unlisted one
+func CompareQualityWithGroups(a, b *types.Metadata, trustedGroups []string) int {
+	if a == nil || b == nil {
+		switch {
+		case a != nil:
+			return 1
+		case b != nil:
+			return -1
+		default:
+			return 0
+		}
+	}
+
+	if diff := resolutionRank(a.Quality) - resolutionRank(b.Quality); diff != 0 {
+		return diff
+	}
+
+	if diff := boolRank(a.IsProper || a.IsRepack) - boolRank(b.IsProper || b.IsRepack); diff != 0 {
+		return diff
+	}
+
+	return groupRank(a.ReleaseGroup, trustedGroups) - groupRank(b.ReleaseGroup, trustedGroups)
+}
+
+// groupRank scores a release group by its position in trustedGroups: the
+// first entry scores highest and unlisted or missing groups score 0
+func groupRank(group string, trustedGroups []string) int {
+	if group == "" {
+		return 0
+	}
+	for i, trusted := range trustedGroups {
+		if strings.EqualFold(group, trusted) {
+			return len(trustedGroups) - i
+		}
+	}
+	return 0
+}
+
+// boolRank converts a bool to 1 or 0 for comparisons
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// sampleScale scales the configured sample threshold (which applies to 1080p)
+// by resolution rank: higher resolutions produce larger full-length files
+var sampleScale = map[int]float64{
+	6: 4,    // 8K
+	5: 2.5,  // 4K/2160p
+	4: 1,    // 1080p
+	3: 0.5,  // 720p
+	2: 0.25, // 480p
+}
+
+// DetectSample reports whether a video file is likely a sample clip rather
+// than the full release, with a human-readable reason. A file is flagged when
+// its name contains a "sample" token, or when it is smaller than maxSize
+// scaled to the resolution tag in its name (maxSize applies to 1080p; 720p
+// uses half, 2160p two and a half times). Files without a resolution tag are
+// only flagged by name. maxSize <= 0 disables the size check.
+func DetectSample(filename string, size int64, maxSize int64) (bool, string) {
+	rank := 0
+	var tag string
+	for _, token := range releaseTokenSeparators.Split(filename, -1) {
+		if strings.EqualFold(token, "sample") {
+			return true, "filename contains \"sample\""
+		}
+		if r := resolutionRank(token); r > rank {
+			rank, tag = r, token
+		}
+	}
+
+	if maxSize <= 0 || rank == 0 {
+		return false, ""
+	}
+
+	threshold := int64(float64(maxSize) * sampleScale[rank])
+	if size < threshold {
+		return true, fmt.Sprintf("%s file is only %d MB (full releases are usually over %d MB)", tag, size/(1024*1024), threshold/(1024*1024))
+	}
+	return false, ""
+}
@@ -0,0 +1,208 @@
+package metadata
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestParse_ReleaseFlags(t *testing.T) {
+	tests := []struct {
+		name             string
+		filename         string
+		mediaType        types.MediaType
+		wantProper       bool
+		wantRepack       bool
+		wantEpisodeTitle string
+	}{
+		{"movie proper", "The.Matrix.1999.PROPER.1080p.BluRay.x264.mkv", types.MediaTypeMovie, true, false, ""},
+		{"movie repack lowercase", "Inception (2010) repack 1080p.mp4", types.MediaTypeMovie, false, true, ""},
+		{"movie plain", "The.Matrix.1999.1080p.BluRay.x264.mkv", types.MediaTypeMovie, false, false, ""},
+		{"title containing the word is not a flag", "Improper.Conduct.1994.720p.mkv", types.MediaTypeMovie, false, false, ""},
+		{"tv repack", "Breaking.Bad.S01E01.REPACK.720p.HDTV.mkv", types.MediaTypeTV, false, true, ""},
+		{"tv proper with episode title", "The.Office.S02E15.The.Big.Job.PROPER.720p.WEB-DL.mkv", types.MediaTypeTV, true, false, "The Big Job"},
+		{"tv proper and repack", "Show.S01E02.PROPER.REPACK.1080p.mkv", types.MediaTypeTV, true, true, ""},
+	}
+
+	parser := NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse(tt.filename, tt.mediaType)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.IsProper != tt.wantProper {
+				t.Errorf("IsProper = %v, want %v", got.IsProper, tt.wantProper)
+			}
+			if got.IsRepack != tt.wantRepack {
+				t.Errorf("IsRepack = %v, want %v", got.IsRepack, tt.wantRepack)
+			}
+			if got.TVMetadata != nil && got.TVMetadata.EpisodeTitle != tt.wantEpisodeTitle {
+				t.Errorf("EpisodeTitle = %q, want %q", got.TVMetadata.EpisodeTitle, tt.wantEpisodeTitle)
+			}
+		})
+	}
+}
+
+func TestCompareQuality(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *types.Metadata
+		b    *types.Metadata
+		want int // sign of the result
+	}{
+		{"repack wins at equal resolution", &types.Metadata{Quality: "1080P", IsRepack: true}, &types.Metadata{Quality: "1080P"}, 1},
+		{"proper wins at equal resolution", &types.Metadata{Quality: "720P"}, &types.Metadata{Quality: "720P", IsProper: true}, -1},
+		{"higher resolution beats repack", &types.Metadata{Quality: "2160P"}, &types.Metadata{Quality: "1080P", IsRepack: true}, 1},
+		{"4K equals 2160p", &types.Metadata{Quality: "4K"}, &types.Metadata{Quality: "2160P"}, 0},
+		{"both repacks are equal", &types.Metadata{Quality: "1080P", IsRepack: true}, &types.Metadata{Quality: "1080P", IsProper: true}, 0},
+		{"known quality beats unknown", &types.Metadata{Quality: "480P"}, &types.Metadata{}, 1},
+		{"nil loses", nil, &types.Metadata{}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareQuality(tt.a, tt.b)
+			if sign(got) != tt.want {
+				t.Errorf("CompareQuality() = %d, want sign %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_ReleaseGroup(t *testing.T) {
+	tests := []struct {
+		name      string
+		filename  string
+		mediaType types.MediaType
+		want      string
+	}{
+		{"scene movie", "The.Matrix.1999.1080p.BluRay.x264-SPARKS.mkv", types.MediaTypeMovie, "SPARKS"},
+		{"scene tv", "Show.S01E01.720p.HDTV.x264-LOL.mkv", types.MediaTypeTV, "LOL"},
+		{"tracker tag after group", "Inception.2010.720p.BluRay.x264-GROUP[rarbg].mkv", types.MediaTypeMovie, "GROUP"},
+		{"hyphenated title without release tags", "Spider-Man.2002.mkv", types.MediaTypeMovie, ""},
+		{"source tag is not a group", "Movie.2020.WEB-DL.mkv", types.MediaTypeMovie, ""},
+		{"plain name", "Inception (2010).mkv", types.MediaTypeMovie, ""},
+	}
+
+	parser := NewParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse(tt.filename, tt.mediaType)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.ReleaseGroup != tt.want {
+				t.Errorf("ReleaseGroup = %q, want %q", got.ReleaseGroup, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareQualityWithGroups(t *testing.T) {
+	trusted := []string{"SPARKS", "NTb"}
+
+	tests := []struct {
+		name string
+		a    *types.Metadata
+		b    *types.Metadata
+		want int // sign of the result
+	}{
+		{"trusted group wins at equal quality", &types.Metadata{Quality: "1080P", ReleaseGroup: "SPARKS"}, &types.Metadata{Quality: "1080P", ReleaseGroup: "YIFY"}, 1},
+		{"higher-ranked group wins", &types.Metadata{Quality: "1080P", ReleaseGroup: "ntb"}, &types.Metadata{Quality: "1080P", ReleaseGroup: "sparks"}, -1},
+		{"resolution beats trusted group", &types.Metadata{Quality: "720P", ReleaseGroup: "SPARKS"}, &types.Metadata{Quality: "1080P"}, -1},
+		{"repack beats trusted group", &types.Metadata{Quality: "1080P", ReleaseGroup: "SPARKS"}, &types.Metadata{Quality: "1080P", IsRepack: true}, -1},
+		{"unlisted groups are equal", &types.Metadata{Quality: "1080P", ReleaseGroup: "LOL"}, &types.Metadata{Quality: "1080P", ReleaseGroup: "YIFY"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareQualityWithGroups(tt.a, tt.b, trusted)
+			if sign(got) != tt.want {
+				t.Errorf("CompareQualityWithGroups() = %d, want sign %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func TestDetectSample(t *testing.T) {
+	const mb = 1024 * 1024
+	const maxSize = 200 * mb
+
+	tests := []struct {
+		name     string
+		filename string
+		size     int64
+		maxSize  int64
+		want     bool
+	}{
+		{"small 1080p file", "The.Matrix.1999.1080p.BluRay.x264.mkv", 50 * mb, maxSize, true},
+		{"full 1080p file", "The.Matrix.1999.1080p.BluRay.x264.mkv", 4000 * mb, maxSize, false},
+		{"720p uses half the threshold", "Show.S01E01.720p.HDTV.mkv", 150 * mb, maxSize, false},
+		{"small 720p file", "Show.S01E01.720p.HDTV.mkv", 60 * mb, maxSize, true},
+		{"2160p scales up", "Movie.2020.2160p.UHD.mkv", 300 * mb, maxSize, true},
+		{"no resolution tag", "Movie.2020.mkv", 5 * mb, maxSize, false},
+		{"sample token", "Movie.2020.1080p-sample.mkv", 4000 * mb, maxSize, true},
+		{"title word is not a token", "Samples.of.Life.2020.1080p.mkv", 4000 * mb, maxSize, false},
+		{"size check disabled", "The.Matrix.1999.1080p.BluRay.x264.mkv", 50 * mb, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := DetectSample(tt.filename, tt.size, tt.maxSize)
+			if got != tt.want {
+				t.Errorf("DetectSample(%q, %d) = %v (%s), want %v", tt.filename, tt.size, got, reason, tt.want)
+			}
+			if got && reason == "" {
+				t.Error("DetectSample() flagged a file without a reason")
+			}
+		})
+	}
+}
+
+func TestApplyParentRelease(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		mediaType types.MediaType
+		quality   string
+		source    string
+		codec     string
+		group     string
+	}{
+		{"movie quality in folder", "/dl/Movie.2020.1080p.BluRay.x264-SPARKS/Movie.mkv", types.MediaTypeMovie, "1080P", "BluRay", "x264", "SPARKS"},
+		{"tv quality in folder", "/dl/Show.S01.720p.WEB-DL.h264-GRP/Show S01E01.mkv", types.MediaTypeTV, "720P", "WEB-DL", "h264", "GRP"},
+		{"filename tags win", "/dl/Movie.2020.1080p.BluRay-SPARKS/Movie.2020.720p.WEBRip.mkv", types.MediaTypeMovie, "720P", "WEBRip", "", ""},
+		{"plain folder ignored", "/media/Movies/Movie (2020).mkv", types.MediaTypeMovie, "", "", "", ""},
+		{"season folder ignored", "/media/Show/Season 1/Show S01E01.mkv", types.MediaTypeTV, "", "", "", ""},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse(filepath.Base(tt.path), tt.mediaType)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			ApplyParentRelease(got, tt.path)
+			if got.Quality != tt.quality || got.Source != tt.source || got.Codec != tt.codec || got.ReleaseGroup != tt.group {
+				t.Errorf("quality/source/codec/group = %q/%q/%q/%q, want %q/%q/%q/%q",
+					got.Quality, got.Source, got.Codec, got.ReleaseGroup, tt.quality, tt.source, tt.codec, tt.group)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package metadata
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/pkg/types"
@@ -16,21 +18,34 @@ type TVParser interface {
 type tvParser struct {
 	// Pattern for S01E01 format
 	seasonEpisodePattern *regexp.Regexp
+	// Pattern for the extra episodes of a multi-episode file (E02E03, -E03)
+	extraEpisodesPattern *regexp.Regexp
 	// Pattern for 1x01 format
 	altPattern *regexp.Regexp
 	// Pattern to extract show name before season/episode
 	showNamePattern *regexp.Regexp
+	// Pattern for daily shows dated YYYY.MM.DD or YYYY-MM-DD
+	dailyPattern *regexp.Regexp
+	// Pattern for mini-series episodes without a season (E01, Part 2)
+	miniSeriesPattern *regexp.Regexp
 }
 
 // NewTVParser creates a new TVParser
 func NewTVParser() TVParser {
 	return &tvParser{
-		// Capture season and episode numbers from S##E## pattern
-		seasonEpisodePattern: regexp.MustCompile(`(?i)S(\d{1,4})E(\d{1,4})`),
+		// Capture season and episode numbers from S##E## pattern, plus any
+		// further episodes directly following it (S01E01E02E03, S01E01-E03)
+		seasonEpisodePattern: regexp.MustCompile(`(?i)S(\d{1,4})E(\d{1,4})((?:-?E\d{1,4})*)`),
+		extraEpisodesPattern: regexp.MustCompile(`(?i)E(\d{1,4})`),
 		// Capture season and episode from ##x## pattern
 		altPattern: regexp.MustCompile(`(?i)(\d{1,4})x(\d{1,4})`),
 		// Capture everything before the season/episode pattern as show name
 		showNamePattern: regexp.MustCompile(`^(.+?)[\._\s-]+(?i)(?:S?\d{1,4}[xE]\d{1,4})`),
+		// Capture show name, air date and the rest from Show.2023.05.15.Rest
+		dailyPattern: regexp.MustCompile(`^(.+?)[\._\s-]+((?:19|20)\d{2})[.-](\d{2})[.-](\d{2})(?:[\._\s-]+(.*))?$`),
+		// Capture show name, episode number and the rest from Show.E01.Rest
+		// or Show.Part.2.Rest
+		miniSeriesPattern: regexp.MustCompile(`(?i)^(.+?)[\._\s-]+(?:ep?|part[\._\s-]?)(\d{1,3})(?:[\._\s-]+(.*))?$`),
 	}
 }
 
@@ -40,7 +55,9 @@ func (t *tvParser) Parse(filename string) (*types.Metadata, error) {
 		TVMetadata: &types.TVMetadata{},
 	}
 
-	name := util.RemoveExtension(filename)
+	name, tmdbID, imdbID := extractProviderIDs(util.RemoveExtension(filename))
+	metadata.TVMetadata.TMDBID = tmdbID
+	metadata.TVMetadata.IMDBID = imdbID
 
 	// Extract season and episode numbers
 	var season, episode int
@@ -57,6 +74,9 @@ func (t *tvParser) Parse(filename string) (*types.Metadata, error) {
 		if err == nil {
 			metadata.TVMetadata.Episode = episode
 		}
+		if err := t.parseEpisodeSpan(matches[3], metadata.TVMetadata); err != nil {
+			return nil, err
+		}
 	} else {
 		// Try alternative 1x01 pattern
 		altMatches := t.altPattern.FindStringSubmatch(name)
@@ -69,6 +89,10 @@ func (t *tvParser) Parse(filename string) (*types.Metadata, error) {
 			if err == nil {
 				metadata.TVMetadata.Episode = episode
 			}
+		} else if t.parseDaily(name, metadata) || t.parseMiniSeries(name, metadata) {
+			parseReleaseFlags(name, metadata)
+			parseReleaseGroup(name, metadata)
+			return metadata, nil
 		}
 	}
 
@@ -85,9 +109,101 @@ func (t *tvParser) Parse(filename string) (*types.Metadata, error) {
 	episodeTitlePattern := regexp.MustCompile(`(?i)S?\d{1,4}[xE]\d{1,4}[\.\s-]+(.+?)[\.\s-]+(?:\d{3,4}p|BluRay|WEB|HDTV|x26[45])`)
 	episodeMatches := episodeTitlePattern.FindStringSubmatch(name)
 	if len(episodeMatches) >= 2 {
-		episodeTitle := util.CleanTitle(episodeMatches[1])
+		episodeTitle := stripReleaseFlags(util.CleanTitle(episodeMatches[1]))
 		metadata.TVMetadata.EpisodeTitle = episodeTitle
 	}
 
+	parseReleaseFlags(name, metadata)
+	parseReleaseGroup(name, metadata)
+
 	return metadata, nil
 }
+
+// parseEpisodeSpan records the last episode of a multi-episode file from the
+// extra episode markers following S##E## ("E02E03" or "-E03"). Episodes must
+// ascend; a descending span like E05E02 is rejected.
+func (t *tvParser) parseEpisodeSpan(extra string, tv *types.TVMetadata) error {
+	last := tv.Episode
+	for _, m := range t.extraEpisodesPattern.FindAllStringSubmatch(extra, -1) {
+		episode, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		if episode <= last {
+			return fmt.Errorf("invalid episode span: E%02d follows E%02d", episode, last)
+		}
+		last = episode
+	}
+	if last > tv.Episode {
+		tv.EpisodeEnd = last
+	}
+	return nil
+}
+
+// parseDaily recognizes date-based episodes of daily shows, e.g.
+// "The.Daily.Show.2023.05.15.720p". The air date's year becomes the season
+// so episodes group into "Season 2023" folders. Returns false if the name
+// carries no valid date.
+func (t *tvParser) parseDaily(name string, metadata *types.Metadata) bool {
+	matches := t.dailyPattern.FindStringSubmatch(name)
+	if len(matches) < 5 {
+		return false
+	}
+
+	airDate := matches[2] + "-" + matches[3] + "-" + matches[4]
+	if _, err := time.Parse("2006-01-02", airDate); err != nil {
+		return false
+	}
+	year, _ := strconv.Atoi(matches[2])
+
+	showName := util.CleanTitle(matches[1])
+	metadata.Title = showName
+	metadata.TVMetadata.ShowTitle = showName
+	metadata.TVMetadata.Season = year
+	metadata.TVMetadata.AirDate = airDate
+
+	// Text between the date and the first quality tag is the episode title
+	titleMatches := dailyTitlePattern.FindStringSubmatch(matches[5])
+	if len(titleMatches) >= 2 {
+		metadata.TVMetadata.EpisodeTitle = stripReleaseFlags(util.CleanTitle(titleMatches[1]))
+	}
+
+	return true
+}
+
+// parseMiniSeries recognizes episodes numbered without a season, as
+// mini-series are released: "Chernobyl.E01.1080p" or "Show.Part.2". They
+// default to season 1. Returns false if the name carries no such marker.
+func (t *tvParser) parseMiniSeries(name string, metadata *types.Metadata) bool {
+	matches := t.miniSeriesPattern.FindStringSubmatch(name)
+	if len(matches) < 3 {
+		return false
+	}
+	episode, err := strconv.Atoi(matches[2])
+	if err != nil || episode == 0 {
+		return false
+	}
+
+	showName := util.CleanTitle(matches[1])
+	metadata.Title = showName
+	metadata.TVMetadata.ShowTitle = showName
+	metadata.TVMetadata.Season = 1
+	metadata.TVMetadata.Episode = episode
+	metadata.TVMetadata.MiniSeries = true
+
+	// Text between the episode and the first quality tag is the episode title
+	titleMatches := dailyTitlePattern.FindStringSubmatch(matches[3])
+	if len(titleMatches) >= 2 && !leadingTagPattern.MatchString(titleMatches[1]) {
+		metadata.TVMetadata.EpisodeTitle = stripReleaseFlags(util.CleanTitle(titleMatches[1]))
+	}
+
+	return true
+}
+
+// dailyTitlePattern captures the leading text of a daily or mini-series
+// episode's remainder up to the first quality or source tag
+var dailyTitlePattern = regexp.MustCompile(`(?i)^(.+?)[\.\s-]+(?:\d{3,4}p|BluRay|WEB|HDTV|x26[45])`)
+
+// leadingTagPattern matches a remainder that starts with a quality or source
+// tag, i.e. one that carries no episode title
+var leadingTagPattern = regexp.MustCompile(`(?i)^(?:\d{3,4}p|BluRay|WEB|HDTV|x26[45])`)
@@ -0,0 +1,217 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errUnsupportedVideo is returned for a container whose duration cannot be read
+var errUnsupportedVideo = errors.New("unsupported video container")
+
+// Matroska element IDs, marker bits included
+const (
+	ebmlHeaderID     = 0x1A45DFA3
+	mkvSegmentID     = 0x18538067
+	mkvInfoID        = 0x1549A966
+	mkvClusterID     = 0x1F43B675
+	mkvTimescaleID   = 0x2AD7B1
+	mkvDurationID    = 0x4489
+	ebmlUnknownSize  = math.MaxUint64
+	mkvDefaultScale  = 1000000 // nanoseconds per timestamp unit
+	maxEBMLBodyBytes = 8
+)
+
+// ReadVideoDuration returns the running time of an MKV/WebM, MP4/M4V/MOV or
+// AVI file, read from its container headers without touching the streams
+func ReadVideoDuration(path string) (time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+
+	var seconds float64
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mkv", ".webm":
+		seconds, err = mkvDuration(f, size)
+	case ".mp4", ".m4v", ".mov":
+		seconds, err = mp4Duration(f, 0, size)
+	case ".avi":
+		seconds, err = aviDuration(f)
+	default:
+		err = errUnsupportedVideo
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// mkvDuration reads Duration and TimestampScale from the Segment's Info
+// element, which muxers write ahead of the first Cluster
+func mkvDuration(r io.ReaderAt, size int64) (float64, error) {
+	id, length, err := ebmlElement(r, 0)
+	if err != nil || id != ebmlHeaderID {
+		return 0, errors.New("not a Matroska file")
+	}
+	offset := length.end
+
+	id, segment, err := ebmlElement(r, offset)
+	if err != nil || id != mkvSegmentID {
+		return 0, errors.New("no Matroska segment")
+	}
+	end := segment.end
+	if segment.size == ebmlUnknownSize || end > size {
+		end = size
+	}
+
+	for offset = segment.body; offset < end; {
+		id, element, err := ebmlElement(r, offset)
+		if err != nil {
+			return 0, err
+		}
+		switch id {
+		case mkvInfoID:
+			return mkvInfoDuration(r, element.body, element.end)
+		case mkvClusterID:
+			return 0, errors.New("no Matroska segment info before the first cluster")
+		}
+		if element.size == ebmlUnknownSize {
+			return 0, errors.New("corrupt Matroska element")
+		}
+		offset = element.end
+	}
+	return 0, errors.New("no Matroska segment info")
+}
+
+// mkvInfoDuration reads the children of an Info element in [offset, end)
+func mkvInfoDuration(r io.ReaderAt, offset, end int64) (float64, error) {
+	scale := uint64(mkvDefaultScale)
+	duration := -1.0
+	for offset < end {
+		id, element, err := ebmlElement(r, offset)
+		if err != nil {
+			return 0, err
+		}
+		if element.size > maxEBMLBodyBytes && (id == mkvTimescaleID || id == mkvDurationID) {
+			return 0, errors.New("corrupt Matroska segment info")
+		}
+
+		switch id {
+		case mkvTimescaleID:
+			body := make([]byte, element.size)
+			if _, err := r.ReadAt(body, element.body); err != nil {
+				return 0, err
+			}
+			scale = 0
+			for _, b := range body {
+				scale = scale<<8 | uint64(b)
+			}
+		case mkvDurationID:
+			body := make([]byte, element.size)
+			if _, err := r.ReadAt(body, element.body); err != nil {
+				return 0, err
+			}
+			switch element.size {
+			case 4:
+				duration = float64(math.Float32frombits(binary.BigEndian.Uint32(body)))
+			case 8:
+				duration = math.Float64frombits(binary.BigEndian.Uint64(body))
+			default:
+				return 0, errors.New("corrupt Matroska duration")
+			}
+		}
+		offset = element.end
+	}
+	if duration < 0 {
+		return 0, errors.New("Matroska segment info has no duration")
+	}
+	return duration * float64(scale) / float64(time.Second), nil
+}
+
+// ebmlSpan locates an EBML element's body
+type ebmlSpan struct {
+	body int64
+	size uint64
+	end  int64
+}
+
+// ebmlElement reads the ID and size of the EBML element at offset
+func ebmlElement(r io.ReaderAt, offset int64) (uint64, ebmlSpan, error) {
+	id, idLen, err := ebmlVint(r, offset, true)
+	if err != nil {
+		return 0, ebmlSpan{}, err
+	}
+	size, sizeLen, err := ebmlVint(r, offset+int64(idLen), false)
+	if err != nil {
+		return 0, ebmlSpan{}, err
+	}
+	span := ebmlSpan{body: offset + int64(idLen+sizeLen), size: size}
+	span.end = span.body + int64(size)
+	if size == ebmlUnknownSize || span.end < span.body {
+		span.end = math.MaxInt64
+	}
+	return id, span, nil
+}
+
+// ebmlVint reads a variable-length integer: the count of leading zero bits
+// in its first byte gives its length. IDs keep the length marker; sizes
+// drop it, and a size with every value bit set is unknown.
+func ebmlVint(r io.ReaderAt, offset int64, keepMarker bool) (uint64, int, error) {
+	buf := make([]byte, 8)
+	if _, err := r.ReadAt(buf[:1], offset); err != nil {
+		return 0, 0, err
+	}
+	length := bits.LeadingZeros8(buf[0]) + 1
+	if length > 8 || (keepMarker && length > 4) {
+		return 0, 0, errors.New("corrupt EBML integer")
+	}
+	if length > 1 {
+		if _, err := r.ReadAt(buf[1:length], offset+1); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	value := uint64(buf[0])
+	if !keepMarker {
+		value &= 0xFF >> length
+	}
+	for _, b := range buf[1:length] {
+		value = value<<8 | uint64(b)
+	}
+	if !keepMarker && value == 1<<(7*length)-1 {
+		return ebmlUnknownSize, length, nil
+	}
+	return value, length, nil
+}
+
+// aviDuration multiplies the frame count in the main AVI header by the
+// frame duration. OpenDML files count only the frames of their first RIFF
+// chunk there, which still tells a short episode from a feature.
+func aviDuration(r io.ReaderAt) (float64, error) {
+	header := make([]byte, 52)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return 0, err
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "AVI " ||
+		string(header[12:16]) != "LIST" || string(header[20:24]) != "hdrl" || string(header[24:28]) != "avih" {
+		return 0, errors.New("no AVI main header")
+	}
+	microsPerFrame := binary.LittleEndian.Uint32(header[32:])
+	frames := binary.LittleEndian.Uint32(header[48:])
+	return float64(microsPerFrame) * float64(frames) / 1e6, nil
+}
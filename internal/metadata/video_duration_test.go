@@ -0,0 +1,106 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ebml encodes one EBML element with a one-byte size
+func ebml(id []byte, body []byte) []byte {
+	return append(append(append([]byte{}, id...), 0x80|byte(len(body))), body...)
+}
+
+// mkvFile builds an EBML header and an unknown-size Segment holding a
+// SeekHead and an Info element with the given duration in milliseconds
+func mkvFile(durationMs float64, float32Duration bool) []byte {
+	duration := make([]byte, 8)
+	binary.BigEndian.PutUint64(duration, math.Float64bits(durationMs))
+	if float32Duration {
+		duration = duration[:4]
+		binary.BigEndian.PutUint32(duration, math.Float32bits(float32(durationMs)))
+	}
+	info := append(ebml([]byte{0x2A, 0xD7, 0xB1}, []byte{0x0F, 0x42, 0x40}), ebml([]byte{0x44, 0x89}, duration)...)
+
+	var b bytes.Buffer
+	b.Write(ebml([]byte{0x1A, 0x45, 0xDF, 0xA3}, ebml([]byte{0x42, 0x82}, []byte("matroska"))))
+	b.Write([]byte{0x18, 0x53, 0x80, 0x67, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	b.Write(ebml([]byte{0x11, 0x4D, 0x9B, 0x74}, make([]byte, 12)))
+	b.Write(ebml([]byte{0x15, 0x49, 0xA9, 0x66}, info))
+	b.Write(ebml([]byte{0x1F, 0x43, 0xB6, 0x75}, make([]byte, 16)))
+	return b.Bytes()
+}
+
+// aviFile builds a RIFF AVI header with a main AVI header
+func aviFile(microsPerFrame, frames uint32) []byte {
+	avih := make([]byte, 56)
+	binary.LittleEndian.PutUint32(avih[0:], microsPerFrame)
+	binary.LittleEndian.PutUint32(avih[16:], frames)
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(4+12+8+len(avih)))
+	b.WriteString("AVI LIST")
+	binary.Write(&b, binary.LittleEndian, uint32(4+8+len(avih)))
+	b.WriteString("hdrlavih")
+	binary.Write(&b, binary.LittleEndian, uint32(len(avih)))
+	b.Write(avih)
+	return b.Bytes()
+}
+
+func TestReadVideoDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data []byte
+		want time.Duration
+	}{
+		{"mkv", "episode.mkv", mkvFile(22*60*1000, false), 22 * time.Minute},
+		{"webm with 32-bit duration", "episode.webm", mkvFile(45*60*1000, true), 45 * time.Minute},
+		{"mp4", "movie.mp4", mp4File(1000, 7200*1000), 2 * time.Hour},
+		{"avi", "movie.avi", aviFile(40000, 25*60*95), 95 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadVideoDuration(path)
+			if err != nil {
+				t.Fatalf("ReadVideoDuration() error = %v", err)
+			}
+			if got.Round(time.Second) != tt.want {
+				t.Errorf("ReadVideoDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unsupported or corrupt", func(t *testing.T) {
+		dir := t.TempDir()
+		clusterFirst := mkvFile(1000, false)
+		// Move the Cluster ahead of the SeekHead and Info
+		info := bytes.Index(clusterFirst, []byte{0x11, 0x4D, 0x9B, 0x74})
+		cluster := bytes.Index(clusterFirst, []byte{0x1F, 0x43, 0xB6, 0x75})
+		clusterFirst = append(append(append([]byte{}, clusterFirst[:info]...), clusterFirst[cluster:]...), clusterFirst[info:cluster]...)
+
+		for name, data := range map[string][]byte{
+			"movie.ts":      []byte("transport stream"),
+			"movie.mkv":     []byte("not matroska at all"),
+			"movie.avi":     []byte("RIFF....WAVEfmt not an avi header"),
+			"clusters.mkv":  clusterFirst,
+			"truncated.mp4": mp4File(1000, 1000)[:20],
+		} {
+			path := filepath.Join(dir, name)
+			os.WriteFile(path, data, 0644)
+			if _, err := ReadVideoDuration(path); err == nil {
+				t.Errorf("ReadVideoDuration(%s) expected an error", name)
+			}
+		}
+	})
+}
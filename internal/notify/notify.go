@@ -0,0 +1,232 @@
+// Package notify sends run summaries to webhooks, Discord and ntfy so
+// unattended runs can be followed from a phone or chat.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultTimeout bounds each notification request
+const DefaultTimeout = 10 * time.Second
+
+// maxListedFailures caps the failed files listed in a message
+const maxListedFailures = 10
+
+// Failure is one file that could not be organized
+type Failure struct {
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+// Summary describes a finished run
+type Summary struct {
+	Command       string    `json:"command"`
+	Success       bool      `json:"success"`
+	Sources       []string  `json:"sources"`
+	DestRoots     []string  `json:"dest_roots"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Organized     int       `json:"organized"`
+	Failed        int       `json:"failed"`
+	Skipped       int       `json:"skipped"`
+	DurationMS    int64     `json:"duration_ms"`
+	Error         string    `json:"error,omitempty"`
+	Failures      []Failure `json:"failures,omitempty"`
+}
+
+// Title is a one-line headline for the run
+func (s Summary) Title() string {
+	switch {
+	case s.Error != "":
+		return fmt.Sprintf("go-jf-org %s failed", s.Command)
+	case s.Failed > 0:
+		return fmt.Sprintf("go-jf-org %s: %d failed, %d organized", s.Command, s.Failed, s.Organized)
+	default:
+		return fmt.Sprintf("go-jf-org %s: %d organized", s.Command, s.Organized)
+	}
+}
+
+// Message is the notification body: the counts and destination on success,
+// plus the error, failed files and how to roll back on failure
+func (s Summary) Message() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Organized: %d, Failed: %d, Skipped: %d\n", s.Organized, s.Failed, s.Skipped)
+	if len(s.DestRoots) > 0 {
+		fmt.Fprintf(&b, "Destination: %s\n", strings.Join(s.DestRoots, ", "))
+	}
+	fmt.Fprintf(&b, "Duration: %s\n", time.Duration(s.DurationMS)*time.Millisecond)
+	if s.Success {
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+
+	if s.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", s.Error)
+	}
+	if len(s.Failures) > 0 {
+		b.WriteString("Failures:\n")
+		for i, f := range s.Failures {
+			if i == maxListedFailures {
+				fmt.Fprintf(&b, "  ... and %d more\n", len(s.Failures)-i)
+				break
+			}
+			fmt.Fprintf(&b, "  %s: %s\n", f.Source, f.Error)
+		}
+	}
+	if s.TransactionID != "" {
+		fmt.Fprintf(&b, "Transaction: %s\nTo roll back: go-jf-org rollback %s\n", s.TransactionID, s.TransactionID)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Notifier delivers a run summary
+type Notifier interface {
+	// Name identifies the notifier in logs
+	Name() string
+	Notify(ctx context.Context, summary Summary) error
+}
+
+// Config selects the notifiers to create; an empty URL leaves one out
+type Config struct {
+	// WebhookURL receives the Summary as a JSON POST
+	WebhookURL string
+	// DiscordWebhookURL is a Discord channel webhook
+	DiscordWebhookURL string
+	// NtfyURL is an ntfy topic URL, e.g. https://ntfy.sh/my-topic
+	NtfyURL string
+	// NtfyToken is an optional ntfy access token
+	NtfyToken string
+	Timeout   time.Duration
+}
+
+// New creates the notifiers configured in config
+func New(config Config) []Notifier {
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	client := &http.Client{Timeout: config.Timeout}
+
+	var notifiers []Notifier
+	if config.WebhookURL != "" {
+		notifiers = append(notifiers, &Webhook{url: config.WebhookURL, client: client})
+	}
+	if config.DiscordWebhookURL != "" {
+		notifiers = append(notifiers, &Discord{url: config.DiscordWebhookURL, client: client})
+	}
+	if config.NtfyURL != "" {
+		notifiers = append(notifiers, &Ntfy{url: config.NtfyURL, token: config.NtfyToken, client: client})
+	}
+	return notifiers
+}
+
+// Send delivers summary to every notifier. Failures are only logged: a
+// notification must never fail the run it reports on.
+func Send(ctx context.Context, notifiers []Notifier, summary Summary) {
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, summary); err != nil {
+			log.Warn().Err(err).Str("notifier", n.Name()).Msg("Failed to send notification")
+			continue
+		}
+		log.Debug().Str("notifier", n.Name()).Msg("Sent notification")
+	}
+}
+
+// Webhook POSTs the Summary as JSON to a URL
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// Name implements Notifier
+func (w *Webhook) Name() string { return "webhook" }
+
+// Notify implements Notifier
+func (w *Webhook) Notify(ctx context.Context, summary Summary) error {
+	return postJSON(ctx, w.client, w.url, summary)
+}
+
+// Discord posts the title and message to a Discord webhook
+type Discord struct {
+	url    string
+	client *http.Client
+}
+
+// discordMaxContent is Discord's limit on a message's content
+const discordMaxContent = 2000
+
+// Name implements Notifier
+func (d *Discord) Name() string { return "discord" }
+
+// Notify implements Notifier
+func (d *Discord) Notify(ctx context.Context, summary Summary) error {
+	content := "**" + summary.Title() + "**\n" + summary.Message()
+	if len(content) > discordMaxContent {
+		content = content[:discordMaxContent-3] + "..."
+	}
+	return postJSON(ctx, d.client, d.url, map[string]string{
+		"username": "go-jf-org",
+		"content":  content,
+	})
+}
+
+// Ntfy publishes the message to an ntfy topic, at high priority on failure
+type Ntfy struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// Name implements Notifier
+func (n *Ntfy) Name() string { return "ntfy" }
+
+// Notify implements Notifier
+func (n *Ntfy) Notify(ctx context.Context, summary Summary) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, strings.NewReader(summary.Message()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", summary.Title())
+	if summary.Success {
+		req.Header.Set("Tags", "white_check_mark")
+	} else {
+		req.Header.Set("Tags", "warning")
+		req.Header.Set("Priority", "high")
+	}
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+	return do(n.client, req)
+}
+
+// postJSON POSTs body encoded as JSON
+func postJSON(ctx context.Context, client *http.Client, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return do(client, req)
+}
+
+// do sends req and treats any non-2xx status as an error
+func do(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+	return nil
+}
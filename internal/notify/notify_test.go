@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// request is what a mock server received
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// newRecorder returns a server that records requests and answers with status
+func newRecorder(t *testing.T, status int) (*httptest.Server, func() []request) {
+	t.Helper()
+	var mu sync.Mutex
+	var got []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got = append(got, request{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []request {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]request(nil), got...)
+	}
+}
+
+func TestWebhook_PayloadHasSummaryFields(t *testing.T) {
+	server, requests := newRecorder(t, http.StatusNoContent)
+
+	summary := Summary{
+		Command:       "organize",
+		Success:       true,
+		Sources:       []string{"/downloads"},
+		DestRoots:     []string{"/media/Movies"},
+		TransactionID: "txn-1",
+		Organized:     12,
+		Skipped:       2,
+		DurationMS:    1500,
+	}
+	Send(context.Background(), New(Config{WebhookURL: server.URL}), summary)
+
+	got := requests()
+	if len(got) != 1 {
+		t.Fatalf("got %d requests, want 1", len(got))
+	}
+	if ct := got[0].header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var payload Summary
+	if err := json.Unmarshal(got[0].body, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if !reflect.DeepEqual(payload, summary) {
+		t.Errorf("payload = %+v, want %+v", payload, summary)
+	}
+}
+
+func TestDiscordAndNtfy_FailureMessage(t *testing.T) {
+	discord, discordRequests := newRecorder(t, http.StatusNoContent)
+	ntfy, ntfyRequests := newRecorder(t, http.StatusOK)
+
+	summary := Summary{
+		Command:       "organize",
+		DestRoots:     []string{"/media/TV"},
+		TransactionID: "txn-2",
+		Organized:     3,
+		Failed:        1,
+		Failures:      []Failure{{Source: "/downloads/Show.S01E01.mkv", Error: "permission denied"}},
+	}
+	Send(context.Background(), New(Config{DiscordWebhookURL: discord.URL, NtfyURL: ntfy.URL, NtfyToken: "secret"}), summary)
+
+	wants := []string{"1 failed, 3 organized", "/downloads/Show.S01E01.mkv: permission denied", "go-jf-org rollback txn-2"}
+
+	var message struct{ Username, Content string }
+	if got := discordRequests(); len(got) != 1 {
+		t.Fatalf("discord got %d requests, want 1", len(got))
+	} else if err := json.Unmarshal(got[0].body, &message); err != nil {
+		t.Fatalf("discord payload is not JSON: %v", err)
+	}
+	for _, want := range wants {
+		if !strings.Contains(message.Content, want) {
+			t.Errorf("discord content %q does not contain %q", message.Content, want)
+		}
+	}
+
+	got := ntfyRequests()
+	if len(got) != 1 {
+		t.Fatalf("ntfy got %d requests, want 1", len(got))
+	}
+	published := got[0].header.Get("Title") + "\n" + string(got[0].body)
+	for _, want := range wants {
+		if !strings.Contains(published, want) {
+			t.Errorf("ntfy message %q does not contain %q", published, want)
+		}
+	}
+	if p := got[0].header.Get("Priority"); p != "high" {
+		t.Errorf("ntfy Priority = %q, want high", p)
+	}
+	if a := got[0].header.Get("Authorization"); a != "Bearer secret" {
+		t.Errorf("ntfy Authorization = %q, want the token", a)
+	}
+}
+
+func TestNotify_ServerErrorIsReturned(t *testing.T) {
+	server, _ := newRecorder(t, http.StatusInternalServerError)
+
+	notifiers := New(Config{WebhookURL: server.URL})
+	if err := notifiers[0].Notify(context.Background(), Summary{Command: "organize"}); err == nil {
+		t.Error("expected an error for a 500 response")
+	}
+
+	// Send only logs it
+	Send(context.Background(), notifiers, Summary{Command: "organize"})
+}
+
+func TestNew_OnlyConfiguredTargets(t *testing.T) {
+	if n := New(Config{}); len(n) != 0 {
+		t.Errorf("New(empty) = %d notifiers, want 0", len(n))
+	}
+	n := New(Config{WebhookURL: "http://a", NtfyURL: "http://b"})
+	if len(n) != 2 || n[0].Name() != "webhook" || n[1].Name() != "ntfy" {
+		t.Errorf("New() = %v, want webhook and ntfy", n)
+	}
+}
@@ -0,0 +1,144 @@
+package organizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/internal/artwork"
+	"github.com/opd-ai/go-jf-org/internal/safety"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// Artwork download kinds recorded for retrying failed downloads
+const (
+	// ArtworkMoviePoster is a movie poster from a TMDB image path
+	ArtworkMoviePoster = "movie_poster"
+	// ArtworkBackdrop is a movie backdrop from a TMDB image path
+	ArtworkBackdrop = "backdrop"
+	// ArtworkPosterThumbnail is a poster thumbnail from a TMDB image path
+	ArtworkPosterThumbnail = "poster_thumbnail"
+	// ArtworkTVPoster is a TV show poster from a TMDB image path
+	ArtworkTVPoster = "tv_poster"
+	// ArtworkActorImage is an actor image from its full URL
+	ArtworkActorImage = "actor_image"
+	// ArtworkAlbumCover is an album cover from a MusicBrainz release ID
+	ArtworkAlbumCover = "album_cover"
+	// ArtworkBookCover is a book cover from Open Library by normalized ISBN
+	ArtworkBookCover = "book_cover"
+)
+
+// artworkConfig returns the downloader configuration for this organizer.
+// Existing artwork is never downloaded again; artworkAttempts, when set,
+// replaces the default number of attempts per image.
+func (o *Organizer) artworkConfig() artwork.Config {
+	config := artwork.DefaultConfig()
+	config.Force = false
+	if o.artworkFormat != "" {
+		config.Format = o.artworkFormat
+	}
+	if o.artworkAttempts > 0 {
+		config.MaxRetries = o.artworkAttempts
+	}
+	return config
+}
+
+// artworkFailed marks an artwork operation failed and, when the run is
+// logged in a transaction, keeps the download for 'transactions
+// retry-artwork'. Artwork a source does not have is not worth retrying and
+// is not kept, nor is a download with no source to repeat it from.
+func (o *Organizer) artworkFailed(op *types.Operation, kind, source string, err error) {
+	op.Status = types.OperationStatusFailed
+	op.Error = err
+
+	if !o.enableTransactions || o.transactionMgr == nil || errors.Is(err, artwork.ErrNotFound) {
+		return
+	}
+	if source == "" {
+		log.Warn().Err(err).Str("kind", kind).Str("dest", op.Destination).Msg("Artwork download failed with no source recorded; it cannot be retried")
+		return
+	}
+	o.artworkRetries = append(o.artworkRetries, safety.ArtworkRetry{
+		Kind:        kind,
+		Source:      source,
+		Destination: op.Destination,
+		Size:        string(o.artworkSize),
+		Error:       err.Error(),
+		Attempts:    1,
+	})
+}
+
+// takeArtworkRetries returns the failed downloads recorded since the last
+// call and forgets them
+func (o *Organizer) takeArtworkRetries() []safety.ArtworkRetry {
+	retries := o.artworkRetries
+	o.artworkRetries = nil
+	return retries
+}
+
+// RetryArtwork repeats failed artwork downloads recorded in a transaction.
+// It returns an operation for each download that now succeeds and the
+// retries still failing, with their latest error. Downloads use this
+// organizer's artwork format and TMDB image base at the size each retry
+// recorded.
+func (o *Organizer) RetryArtwork(ctx context.Context, retries []safety.ArtworkRetry) ([]types.Operation, []safety.ArtworkRetry) {
+	var downloaded []types.Operation
+	var failing []safety.ArtworkRetry
+
+	for _, retry := range retries {
+		if err := o.retryArtwork(ctx, retry); err != nil {
+			log.Warn().Err(err).Str("kind", retry.Kind).Str("dest", retry.Destination).Msg("Artwork download failed again")
+			retry.Error = err.Error()
+			retry.Attempts++
+			failing = append(failing, retry)
+			continue
+		}
+
+		downloaded = append(downloaded, types.Operation{
+			Type:        types.OperationCreateFile,
+			Source:      retry.Source,
+			Destination: savedArtworkPath(retry.Destination),
+			Status:      types.OperationStatusCompleted,
+		})
+	}
+	return downloaded, failing
+}
+
+// retryArtwork repeats one failed download the way downloadArtworkForPlan
+// made it
+func (o *Organizer) retryArtwork(ctx context.Context, retry safety.ArtworkRetry) error {
+	size := artwork.ImageSize(retry.Size)
+	if size == "" {
+		size = o.artworkSize
+	}
+	config := o.artworkConfig()
+
+	switch retry.Kind {
+	case ArtworkAlbumCover:
+		return artwork.NewCoverArtDownloader(config, size).DownloadAlbumCover(ctx, retry.Source, filepath.Dir(retry.Destination))
+	case ArtworkBookCover:
+		covers := artwork.NewOpenLibraryDownloader(config, size)
+		covers.SetBaseURL(o.openLibraryBase)
+		return covers.DownloadBookCoverByISBN(ctx, retry.Source, filepath.Dir(retry.Destination))
+	}
+
+	tmdb := artwork.NewTMDBDownloader(config, size)
+	tmdb.SetImageBaseURL(o.tmdbImageBase)
+	switch retry.Kind {
+	case ArtworkMoviePoster:
+		return tmdb.DownloadMoviePosterTo(ctx, retry.Source, retry.Destination)
+	case ArtworkBackdrop:
+		return tmdb.DownloadMovieBackdropTo(ctx, retry.Source, retry.Destination)
+	case ArtworkPosterThumbnail:
+		return tmdb.DownloadPosterThumbnailTo(ctx, retry.Source, retry.Destination)
+	case ArtworkTVPoster:
+		return tmdb.DownloadTVPoster(ctx, retry.Source, filepath.Dir(retry.Destination))
+	case ArtworkActorImage:
+		return tmdb.DownloadImage(ctx, retry.Source, retry.Destination)
+	default:
+		return fmt.Errorf("unknown artwork kind: %s", retry.Kind)
+	}
+}
@@ -0,0 +1,181 @@
+package organizer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/artwork"
+	"github.com/opd-ai/go-jf-org/internal/isbn"
+	"github.com/opd-ai/go-jf-org/internal/safety"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestExecuteWithTransaction_ArtworkRetries(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/original/missing.jpg":
+			http.NotFound(w, r)
+		case failing.Load():
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("image data"))
+		}
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	sourceFile := filepath.Join(tmpDir, "The.Matrix.1999.1080p.mkv")
+	createTestFile(t, sourceFile)
+	movieDir := filepath.Join(tmpDir, "organized", "The Matrix (1999)")
+	plan := Plan{
+		SourcePath:      sourceFile,
+		DestinationPath: filepath.Join(movieDir, "The Matrix (1999).mkv"),
+		MediaType:       types.MediaTypeMovie,
+		Operation:       types.OperationMove,
+		Metadata: &types.Metadata{
+			Title: "The Matrix",
+			Year:  1999,
+			MovieMetadata: &types.MovieMetadata{
+				PosterURL:   "/poster.jpg",
+				BackdropURL: "/missing.jpg",
+			},
+		},
+	}
+
+	tm, err := safety.NewTransactionManager(filepath.Join(tmpDir, "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := NewOrganizerWithTransactions(false, tm)
+	o.SetDownloadArtwork(true, artwork.SizeOriginal)
+	o.SetTMDBImageBase(server.URL)
+	o.artworkAttempts = 1
+
+	txnID, _, err := o.ExecuteWithTransaction([]Plan{plan}, "skip")
+	if err != nil {
+		t.Fatalf("ExecuteWithTransaction() error = %v", err)
+	}
+
+	// The unavailable poster is kept for retrying; the missing backdrop is not
+	txn, err := tm.Load(txnID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txn.ArtworkRetries) != 1 {
+		t.Fatalf("ArtworkRetries = %+v, want only the poster", txn.ArtworkRetries)
+	}
+	retry := txn.ArtworkRetries[0]
+	posterPath := filepath.Join(movieDir, "poster.jpg")
+	if retry.Kind != ArtworkMoviePoster || retry.Source != "/poster.jpg" || retry.Destination != posterPath || retry.Size != string(artwork.SizeOriginal) || retry.Attempts != 1 || retry.Error == "" {
+		t.Errorf("ArtworkRetries[0] = %+v", retry)
+	}
+
+	// A retry that fails again stays listed with another attempt counted
+	downloaded, still := o.RetryArtwork(context.Background(), txn.ArtworkRetries)
+	if len(downloaded) != 0 || len(still) != 1 || still[0].Attempts != 2 {
+		t.Fatalf("RetryArtwork() while failing = %+v, %+v", downloaded, still)
+	}
+
+	failing.Store(false)
+	downloaded, still = o.RetryArtwork(context.Background(), still)
+	if len(still) != 0 || len(downloaded) != 1 {
+		t.Fatalf("RetryArtwork() = %+v, %+v; want the poster downloaded", downloaded, still)
+	}
+	if downloaded[0].Destination != posterPath || downloaded[0].Status != types.OperationStatusCompleted {
+		t.Errorf("downloaded operation = %+v", downloaded[0])
+	}
+	if _, err := os.Stat(posterPath); err != nil {
+		t.Errorf("poster was not downloaded: %v", err)
+	}
+
+	if err := tm.RecordArtworkRetries(txn, downloaded, still); err != nil {
+		t.Fatalf("RecordArtworkRetries() error = %v", err)
+	}
+	txn, err = tm.Load(txnID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := txn.Operations[len(txn.Operations)-1]
+	if len(txn.ArtworkRetries) != 0 || last.Destination != posterPath || last.Status != types.OperationStatusCompleted {
+		t.Errorf("reloaded transaction retries = %+v, last operation = %+v", txn.ArtworkRetries, last)
+	}
+}
+
+func TestRetryArtwork_UnknownKind(t *testing.T) {
+	o := NewOrganizer(false)
+	downloaded, failing := o.RetryArtwork(context.Background(), []safety.ArtworkRetry{
+		{Kind: "hologram", Source: "/x.jpg", Destination: filepath.Join(t.TempDir(), "x.jpg"), Attempts: 1},
+	})
+	if len(downloaded) != 0 || len(failing) != 1 || failing[0].Error == "" || failing[0].Attempts != 2 {
+		t.Errorf("RetryArtwork() = %+v, %+v; want the retry kept as failing", downloaded, failing)
+	}
+}
+
+func TestExecuteWithTransaction_BookCoverRetry(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("image data"))
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	sourceFile := filepath.Join(tmpDir, "Sapiens.epub")
+	createTestFile(t, sourceFile)
+	bookDir := filepath.Join(tmpDir, "organized", "Yuval Noah Harari", "Sapiens")
+	plan := Plan{
+		SourcePath:      sourceFile,
+		DestinationPath: filepath.Join(bookDir, "Sapiens.epub"),
+		MediaType:       types.MediaTypeBook,
+		Operation:       types.OperationMove,
+		Metadata: &types.Metadata{
+			Title:        "Sapiens",
+			BookMetadata: &types.BookMetadata{Author: "Yuval Noah Harari", ISBN: "0-385-47257-9"},
+		},
+	}
+
+	tm, err := safety.NewTransactionManager(filepath.Join(tmpDir, "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := NewOrganizerWithTransactions(false, tm)
+	o.SetDownloadArtwork(true, artwork.SizeOriginal)
+	o.openLibraryBase = server.URL
+	o.artworkAttempts = 1
+
+	txnID, _, err := o.ExecuteWithTransaction([]Plan{plan}, "skip")
+	if err != nil {
+		t.Fatalf("ExecuteWithTransaction() error = %v", err)
+	}
+	txn, err := tm.Load(txnID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coverPath := filepath.Join(bookDir, "cover.jpg")
+	if len(txn.ArtworkRetries) != 1 || txn.ArtworkRetries[0].Kind != ArtworkBookCover || txn.ArtworkRetries[0].Destination != coverPath {
+		t.Fatalf("ArtworkRetries = %+v, want the book cover", txn.ArtworkRetries)
+	}
+	if want, _ := isbn.Normalize("0-385-47257-9"); txn.ArtworkRetries[0].Source != want {
+		t.Errorf("retry source = %q, want the normalized ISBN %q", txn.ArtworkRetries[0].Source, want)
+	}
+
+	failing.Store(false)
+	downloaded, still := o.RetryArtwork(context.Background(), txn.ArtworkRetries)
+	if len(still) != 0 || len(downloaded) != 1 || downloaded[0].Destination != coverPath {
+		t.Fatalf("RetryArtwork() = %+v, %+v; want the cover downloaded", downloaded, still)
+	}
+	if _, err := os.Stat(coverPath); err != nil {
+		t.Errorf("book cover was not downloaded: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package organizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+// DefaultCollisionLimit is how many numeric suffixes (-1, -2, ...) are tried
+// before giving up or falling back to a hash suffix
+const DefaultCollisionLimit = 1000
+
+// Collision kinds
+const (
+	// CollisionDestinationExists means the planned destination file already existed
+	CollisionDestinationExists = "destination_exists"
+	// CollisionPlanCollision means an earlier file in the same plan has the
+	// same destination
+	CollisionPlanCollision = "plan_collision"
+)
+
+// Collision resolutions
+const (
+	CollisionSkipped = "skipped"
+	CollisionRenamed = "renamed"
+	CollisionFailed  = "failed"
+)
+
+// Collision records a conflict encountered during execution and how it was resolved
+type Collision struct {
+	Timestamp           time.Time `json:"timestamp"`
+	Source              string    `json:"source"`
+	IntendedDestination string    `json:"intended_destination"`
+	Kind                string    `json:"kind"`
+	Strategy            string    `json:"strategy"`
+	Resolution          string    `json:"resolution"`
+	FinalDestination    string    `json:"final_destination,omitempty"`
+	Error               string    `json:"error,omitempty"`
+}
+
+// RecordCollision appends a collision to the organizer's collision log
+func (o *Organizer) RecordCollision(c Collision) {
+	if c.Timestamp.IsZero() {
+		c.Timestamp = time.Now()
+	}
+	o.collisions = append(o.collisions, c)
+}
+
+// Collisions returns all collisions recorded by this organizer
+func (o *Organizer) Collisions() []Collision {
+	return o.collisions
+}
+
+// WriteCollisionLog writes collisions to a JSON file, creating parent directories as needed
+func WriteCollisionLog(path string, collisions []Collision) error {
+	if collisions == nil {
+		collisions = []Collision{}
+	}
+
+	data, err := json.MarshalIndent(collisions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal collision log: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create collision log directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write collision log: %w", err)
+	}
+
+	return nil
+}
+
+// markPlanCollisions flags every plan whose destination was already claimed
+// by an earlier plan in the list, so two inputs never silently race for one
+// slot. The first plan keeps the destination; it returns how many were flagged.
+func markPlanCollisions(plans []Plan) int {
+	claimed := make(map[string]string, len(plans))
+	marked := 0
+	for i := range plans {
+		dest := filepath.Clean(plans[i].DestinationPath)
+		first, taken := claimed[dest]
+		if !taken {
+			claimed[dest] = plans[i].SourcePath
+			continue
+		}
+		plans[i].Conflict = true
+		plans[i].ConflictKind = CollisionPlanCollision
+		plans[i].ConflictReason = fmt.Sprintf("same destination as %s", first)
+		marked++
+	}
+	return marked
+}
+
+// AvailableName finds a free filename for path by appending -1, -2, ... up to
+// limit attempts (DefaultCollisionLimit when limit <= 0). When the numeric
+// suffixes are exhausted and hashFallback is set, a short hash of the source
+// and destination is appended instead, which is unique for each source file.
+func AvailableName(path, source string, limit int, hashFallback bool) (string, error) {
+	return availableName(fsys.OSFileSystem{}, path, source, limit, hashFallback)
+}
+
+// availableName is AvailableName checking for free names on f
+func availableName(f fsys.ReadFS, path, source string, limit int, hashFallback bool) (string, error) {
+	if limit <= 0 {
+		limit = DefaultCollisionLimit
+	}
+
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	name := base[:len(base)-len(ext)]
+
+	for i := 1; i < limit; i++ {
+		newPath := filepath.Join(dir, fmt.Sprintf("%s-%d%s", name, i, ext))
+		if _, err := f.Stat(newPath); errors.Is(err, fs.ErrNotExist) {
+			return newPath, nil
+		}
+	}
+
+	if !hashFallback {
+		return "", fmt.Errorf("could not find available filename after %d attempts for %s", limit, path)
+	}
+
+	// Salt the hash on the (unlikely) chance the hashed name is taken as well
+	for salt := 0; salt < DefaultCollisionLimit; salt++ {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d", source, path, salt)))
+		newPath := filepath.Join(dir, fmt.Sprintf("%s-%s%s", name, hex.EncodeToString(sum[:4]), ext))
+		if _, err := f.Stat(newPath); errors.Is(err, fs.ErrNotExist) {
+			return newPath, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find available filename for %s, even with a hash suffix", path)
+}
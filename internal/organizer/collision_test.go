@@ -0,0 +1,182 @@
+package organizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestExecute_CollisionLog(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	renameSource := filepath.Join(tmpDir, "The.Matrix.1999.1080p.mkv")
+	skipSource := filepath.Join(tmpDir, "Inception.2010.1080p.mkv")
+	createTestFile(t, renameSource)
+	createTestFile(t, skipSource)
+
+	renameDest := filepath.Join(tmpDir, "organized", "The Matrix (1999)", "The Matrix (1999).mkv")
+	createTestFile(t, renameDest)
+
+	plans := []Plan{{
+		SourcePath:      renameSource,
+		DestinationPath: renameDest,
+		MediaType:       types.MediaTypeMovie,
+		Operation:       types.OperationMove,
+		Conflict:        true,
+	}}
+
+	o := NewOrganizer(false)
+	if _, err := o.Execute(plans, "rename"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	collisions := o.Collisions()
+	if len(collisions) != 1 {
+		t.Fatalf("Expected 1 collision, got %d", len(collisions))
+	}
+
+	c := collisions[0]
+	wantFinal := filepath.Join(tmpDir, "organized", "The Matrix (1999)", "The Matrix (1999)-1.mkv")
+	if c.Source != renameSource {
+		t.Errorf("Source = %s, want %s", c.Source, renameSource)
+	}
+	if c.IntendedDestination != renameDest {
+		t.Errorf("IntendedDestination = %s, want %s", c.IntendedDestination, renameDest)
+	}
+	if c.Kind != CollisionDestinationExists {
+		t.Errorf("Kind = %s, want %s", c.Kind, CollisionDestinationExists)
+	}
+	if c.Strategy != "rename" || c.Resolution != CollisionRenamed {
+		t.Errorf("Strategy/Resolution = %s/%s, want rename/%s", c.Strategy, c.Resolution, CollisionRenamed)
+	}
+	if c.FinalDestination != wantFinal {
+		t.Errorf("FinalDestination = %s, want %s", c.FinalDestination, wantFinal)
+	}
+	if _, err := os.Stat(wantFinal); err != nil {
+		t.Errorf("Renamed file not created: %v", err)
+	}
+
+	// A skipped conflict is recorded without a final destination
+	skipPlans := []Plan{{
+		SourcePath:      skipSource,
+		DestinationPath: renameDest,
+		MediaType:       types.MediaTypeMovie,
+		Operation:       types.OperationMove,
+		Conflict:        true,
+	}}
+	if _, err := o.Execute(skipPlans, "skip"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	collisions = o.Collisions()
+	if len(collisions) != 2 {
+		t.Fatalf("Expected 2 collisions, got %d", len(collisions))
+	}
+	if collisions[1].Resolution != CollisionSkipped || collisions[1].FinalDestination != "" {
+		t.Errorf("unexpected skip entry: %+v", collisions[1])
+	}
+
+	// Log is writable to a file
+	logPath := filepath.Join(tmpDir, "logs", "collisions.json")
+	if err := WriteCollisionLog(logPath, collisions); err != nil {
+		t.Fatalf("WriteCollisionLog() error = %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded []Collision
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("invalid collision log JSON: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].FinalDestination != wantFinal {
+		t.Errorf("unexpected collision log contents: %+v", loaded)
+	}
+}
+
+func TestAvailableName_HashFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	dest := filepath.Join(tmpDir, "Movie (2020).mkv")
+	createTestFile(t, dest)
+
+	// Exhaust the numeric suffixes allowed by a small limit
+	const limit = 5
+	for i := 1; i < limit; i++ {
+		createTestFile(t, filepath.Join(tmpDir, fmt.Sprintf("Movie (2020)-%d.mkv", i)))
+	}
+
+	if _, err := AvailableName(dest, "/src/a.mkv", limit, false); err == nil {
+		t.Fatal("AvailableName() without fallback should fail once suffixes are exhausted")
+	}
+
+	first, err := AvailableName(dest, "/src/a.mkv", limit, true)
+	if err != nil {
+		t.Fatalf("AvailableName() with fallback error = %v", err)
+	}
+	if !regexp.MustCompile(`^Movie \(2020\)-[0-9a-f]{8}\.mkv$`).MatchString(filepath.Base(first)) {
+		t.Errorf("hash fallback name = %q, want Movie (2020)-<8 hex>.mkv", filepath.Base(first))
+	}
+	if filepath.Dir(first) != tmpDir {
+		t.Errorf("hash fallback should stay in %s, got %s", tmpDir, first)
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Errorf("hash fallback returned an existing path: %s", first)
+	}
+
+	// Another source gets a different name, and a taken name is not reused
+	second, err := AvailableName(dest, "/src/b.mkv", limit, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == first {
+		t.Errorf("different sources produced the same fallback name %s", first)
+	}
+
+	createTestFile(t, first)
+	again, err := AvailableName(dest, "/src/a.mkv", limit, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == first {
+		t.Errorf("fallback reused existing name %s", first)
+	}
+}
+
+func TestExecute_ConflictRenameHashFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := filepath.Join(tmpDir, "src", "Movie.2020.mkv")
+	createTestFile(t, source)
+	dest := filepath.Join(tmpDir, "dest", "Movie (2020).mkv")
+	createTestFile(t, dest)
+	createTestFile(t, filepath.Join(tmpDir, "dest", "Movie (2020)-1.mkv"))
+
+	o := NewOrganizer(false)
+	o.SetCollisionLimit(2, true)
+
+	ops, err := o.Execute([]Plan{{
+		SourcePath:      source,
+		DestinationPath: dest,
+		MediaType:       types.MediaTypeMovie,
+		Operation:       types.OperationMove,
+		Conflict:        true,
+	}}, "rename")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(ops) != 1 || ops[0].Status != types.OperationStatusCompleted {
+		t.Fatalf("ops = %+v, want one completed move", ops)
+	}
+	if ops[0].Destination == dest || strings.HasSuffix(ops[0].Destination, "-2.mkv") {
+		t.Errorf("expected a hash-suffixed destination, got %s", ops[0].Destination)
+	}
+	if _, err := os.Stat(ops[0].Destination); err != nil {
+		t.Errorf("moved file missing at %s: %v", ops[0].Destination, err)
+	}
+}
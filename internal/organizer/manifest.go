@@ -0,0 +1,217 @@
+package organizer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// ManifestEntry maps one source file to the metadata it should be organized
+// with, bypassing detection and filename parsing
+type ManifestEntry struct {
+	Source       string          `json:"source"`
+	Type         types.MediaType `json:"type"`
+	Title        string          `json:"title"`
+	Year         int             `json:"year,omitempty"`
+	Season       int             `json:"season,omitempty"`
+	Episode      int             `json:"episode,omitempty"`
+	EpisodeTitle string          `json:"episode_title,omitempty"`
+}
+
+// manifestColumns are the CSV header names a manifest may use
+var manifestColumns = map[string]bool{
+	"source": true, "type": true, "title": true, "year": true,
+	"season": true, "episode": true, "episode_title": true,
+}
+
+// LoadManifest reads a manifest from a JSON array of entries or, for a .csv
+// file, a CSV with a header row naming the columns. Relative sources are
+// resolved against the manifest's directory. Every entry is validated and
+// every source must exist.
+func LoadManifest(path string) ([]ManifestEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer f.Close()
+
+	var entries []ManifestEntry
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		entries, err = readManifestCSV(f)
+	} else {
+		err = json.NewDecoder(f).Decode(&entries)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("manifest %s has no entries", path)
+	}
+
+	base, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for i := range entries {
+		entry := &entries[i]
+		if err := entry.validate(); err != nil {
+			return nil, fmt.Errorf("manifest entry %d: %w", i+1, err)
+		}
+		if !filepath.IsAbs(entry.Source) {
+			entry.Source = filepath.Join(base, entry.Source)
+		}
+		entry.Source = filepath.Clean(entry.Source)
+		if info, err := os.Stat(entry.Source); err != nil || info.IsDir() {
+			missing = append(missing, entry.Source)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("manifest sources do not exist or are not files:\n  %s", strings.Join(missing, "\n  "))
+	}
+	return entries, nil
+}
+
+// readManifestCSV parses CSV rows into entries using the header row
+func readManifestCSV(r io.Reader) ([]ManifestEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header row: %w", err)
+	}
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		if !manifestColumns[header[i]] {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+
+	var entries []ManifestEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var entry ManifestEntry
+		for i, value := range record {
+			if err := entry.set(header[i], strings.TrimSpace(value)); err != nil {
+				line, _ := reader.FieldPos(i)
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// set assigns one CSV column's value
+func (e *ManifestEntry) set(column, value string) error {
+	number := func() (int, error) {
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("%s %q is not a number", column, value)
+		}
+		return n, nil
+	}
+
+	var err error
+	switch column {
+	case "source":
+		e.Source = value
+	case "type":
+		e.Type = types.MediaType(strings.ToLower(value))
+	case "title":
+		e.Title = value
+	case "year":
+		e.Year, err = number()
+	case "season":
+		e.Season, err = number()
+	case "episode":
+		e.Episode, err = number()
+	case "episode_title":
+		e.EpisodeTitle = value
+	}
+	return err
+}
+
+// validate checks that an entry has what its type's destination needs
+func (e *ManifestEntry) validate() error {
+	if e.Source == "" {
+		return fmt.Errorf("missing source")
+	}
+	if e.Title == "" {
+		return fmt.Errorf("%s: missing title", e.Source)
+	}
+	switch e.Type {
+	case types.MediaTypeMovie:
+	case types.MediaTypeTV:
+		if e.Episode <= 0 {
+			return fmt.Errorf("%s: tv entries need an episode", e.Source)
+		}
+		if e.Season < 0 {
+			return fmt.Errorf("%s: season cannot be negative", e.Source)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported type %q (use movie or tv)", e.Source, e.Type)
+	}
+	return nil
+}
+
+// metadata builds the metadata an entry describes
+func (e *ManifestEntry) metadata() *types.Metadata {
+	meta := &types.Metadata{Title: e.Title, Year: e.Year}
+	if e.Type == types.MediaTypeTV {
+		meta.TVMetadata = &types.TVMetadata{
+			ShowTitle:    e.Title,
+			Season:       e.Season,
+			Episode:      e.Episode,
+			EpisodeTitle: e.EpisodeTitle,
+		}
+	} else {
+		meta.MovieMetadata = &types.MovieMetadata{}
+	}
+	return meta
+}
+
+// ManifestSources returns the source paths of entries
+func ManifestSources(entries []ManifestEntry) []string {
+	sources := make([]string, len(entries))
+	for i, entry := range entries {
+		sources[i] = entry.Source
+	}
+	return sources
+}
+
+// PlanManifest creates a plan from manifest entries, using each entry's
+// metadata as given instead of detecting and parsing the file
+func (o *Organizer) PlanManifest(entries []ManifestEntry, destRoot string, mediaTypeFilter types.MediaType) ([]Plan, error) {
+	plans := make([]Plan, 0, len(entries))
+	o.alreadyOrganized = 0
+	o.unrouted = 0
+	o.yearless = 0
+
+	for i := range entries {
+		entry := &entries[i]
+		if mediaTypeFilter != "" && mediaTypeFilter != types.MediaTypeUnknown && entry.Type != mediaTypeFilter {
+			continue
+		}
+		if plan, ok := o.planFile(entry.Source, destRoot, entry.Type, entry.metadata()); ok {
+			plans = append(plans, plan)
+		}
+	}
+
+	return plans, nil
+}
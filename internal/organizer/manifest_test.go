@@ -0,0 +1,112 @@
+package organizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestPlanManifest_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	// Names detection could not make sense of
+	createTestFile(t, filepath.Join(tmpDir, "in", "video1.mkv"))
+	createTestFile(t, filepath.Join(tmpDir, "in", "clip_0042.mp4"))
+
+	manifest := filepath.Join(tmpDir, "in", "manifest.json")
+	data := `[
+  {"source": "video1.mkv", "type": "movie", "title": "The Matrix", "year": 1999},
+  {"source": "clip_0042.mp4", "type": "tv", "title": "Breaking Bad", "season": 1, "episode": 2, "episode_title": "Cat's in the Bag"}
+]`
+	if err := os.WriteFile(manifest, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := LoadManifest(manifest)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+
+	destRoot := filepath.Join(tmpDir, "media")
+	o := NewOrganizer(false)
+	plans, err := o.PlanManifest(entries, destRoot, types.MediaTypeUnknown)
+	if err != nil {
+		t.Fatalf("PlanManifest() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(destRoot, "The Matrix (1999)", "The Matrix (1999).mkv"),
+		filepath.Join(destRoot, "Breaking Bad", "Season 01", "Breaking Bad - S01E02 - Cat's in the Bag.mp4"),
+	}
+	if len(plans) != len(want) {
+		t.Fatalf("got %d plans, want %d", len(plans), len(want))
+	}
+	for i, plan := range plans {
+		if plan.DestinationPath != want[i] {
+			t.Errorf("plan %d destination = %s, want %s", i, plan.DestinationPath, want[i])
+		}
+	}
+
+	if _, err := o.Execute(plans, "skip"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for _, path := range want {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to exist: %v", path, err)
+		}
+	}
+}
+
+func TestLoadManifest_CSV(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := filepath.Join(tmpDir, "ep.mkv")
+	createTestFile(t, source)
+
+	manifest := filepath.Join(tmpDir, "manifest.csv")
+	data := "type,source,title,season,episode\ntv," + source + ",The Office,2,5\n"
+	if err := os.WriteFile(manifest, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := LoadManifest(manifest)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	want := ManifestEntry{Source: source, Type: types.MediaTypeTV, Title: "The Office", Season: 2, Episode: 5}
+	if len(entries) != 1 || entries[0] != want {
+		t.Errorf("entries = %+v, want [%+v]", entries, want)
+	}
+}
+
+func TestLoadManifest_Invalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, filepath.Join(tmpDir, "exists.mkv"))
+
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		wantErr string
+	}{
+		{"missing source", "m.json", `[{"source": "exists.mkv", "type": "movie", "title": "A"}, {"source": "gone.mkv", "type": "movie", "title": "B"}]`, "gone.mkv"},
+		{"unsupported type", "m.json", `[{"source": "exists.mkv", "type": "music", "title": "A"}]`, "unsupported type"},
+		{"tv without episode", "m.json", `[{"source": "exists.mkv", "type": "tv", "title": "A", "season": 1}]`, "need an episode"},
+		{"unknown column", "m.csv", "source,type,title,rating\nexists.mkv,movie,A,5\n", "unknown column"},
+		{"bad number", "m.csv", "source,type,title,year\nexists.mkv,movie,A,soon\n", "not a number"},
+		{"empty", "m.json", `[]`, "no entries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := filepath.Join(tmpDir, tt.file)
+			if err := os.WriteFile(manifest, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadManifest(manifest)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadManifest() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
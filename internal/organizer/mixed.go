@@ -0,0 +1,37 @@
+package organizer
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// FolderMix describes a source folder whose files were planned to more
+// than one media type, e.g. a download holding a movie and its soundtrack
+type FolderMix struct {
+	Dir    string
+	Counts map[types.MediaType]int
+}
+
+// MixedFolders returns the source folders in plans that contain files of
+// more than one media type, sorted by path
+func MixedFolders(plans []Plan) []FolderMix {
+	counts := make(map[string]map[types.MediaType]int)
+	for _, plan := range plans {
+		dir := filepath.Dir(plan.SourcePath)
+		if counts[dir] == nil {
+			counts[dir] = make(map[types.MediaType]int)
+		}
+		counts[dir][plan.MediaType]++
+	}
+
+	var mixes []FolderMix
+	for dir, byType := range counts {
+		if len(byType) > 1 {
+			mixes = append(mixes, FolderMix{Dir: dir, Counts: byType})
+		}
+	}
+	sort.Slice(mixes, func(i, j int) bool { return mixes[i].Dir < mixes[j].Dir })
+	return mixes
+}
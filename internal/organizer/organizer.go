@@ -2,32 +2,110 @@ package organizer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/opd-ai/go-jf-org/internal/artwork"
 	"github.com/opd-ai/go-jf-org/internal/detector"
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+	"github.com/opd-ai/go-jf-org/internal/isbn"
 	"github.com/opd-ai/go-jf-org/internal/jellyfin"
 	"github.com/opd-ai/go-jf-org/internal/metadata"
 	"github.com/opd-ai/go-jf-org/internal/safety"
+	"github.com/opd-ai/go-jf-org/internal/util"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
+// ErrorPolicy controls what Execute does after a file operation fails
+type ErrorPolicy string
+
+const (
+	// ErrorPolicyContinue processes the remaining files after a failure
+	ErrorPolicyContinue ErrorPolicy = "continue"
+	// ErrorPolicyStop halts after the first failure, keeping completed operations
+	ErrorPolicyStop ErrorPolicy = "stop"
+	// ErrorPolicyRollback halts after the first failure and rolls back every
+	// operation of the transaction. Without transactions it behaves like stop.
+	ErrorPolicyRollback ErrorPolicy = "rollback"
+)
+
+// SidecarNFOPolicy controls how a source's own NFO, moved along with it,
+// combines with the NFO the organizer would generate
+type SidecarNFOPolicy string
+
+const (
+	// SidecarNFOMerge keeps every field of the sidecar NFO and adds the
+	// generated fields it lacks
+	SidecarNFOMerge SidecarNFOPolicy = "merge"
+	// SidecarNFOReplace uses the sidecar NFO as is, in place of a generated one
+	SidecarNFOReplace SidecarNFOPolicy = "replace"
+)
+
+// EnrichFunc fills in metadata for a file of the given media type from an
+// external source such as TMDB, giving up once ctx is done
+type EnrichFunc func(ctx context.Context, mediaType types.MediaType, meta *types.Metadata) error
+
 // Organizer handles file organization operations
 type Organizer struct {
-	detector           detector.Detector
-	parser             metadata.Parser
-	naming             *jellyfin.Naming
-	nfoGenerator       *jellyfin.NFOGenerator
-	dryRun             bool
-	createNFO          bool
-	downloadArtwork    bool
-	artworkSize        artwork.ImageSize
-	transactionMgr     *safety.TransactionManager
-	enableTransactions bool
+	detector              detector.Detector
+	parser                metadata.Parser
+	naming                *jellyfin.Naming
+	nfoGenerator          *jellyfin.NFOGenerator
+	dryRun                bool
+	createNFO             bool
+	downloadArtwork       bool
+	artworkSize           artwork.ImageSize
+	tmdbImageBase         string
+	generateThumbnails    bool
+	actorImages           bool
+	artworkFormat         artwork.Format
+	posterSources         []artwork.Source
+	sourceKeys            artwork.SourceKeys
+	transactionMgr        *safety.TransactionManager
+	enableTransactions    bool
+	hashFiles             bool
+	hashAlgorithm         safety.HashAlgorithm
+	renameOnly            bool
+	collisionLimit        int
+	collisionHashFallback bool
+	collisions            []Collision
+	sampleMaxSize         int64
+	skipSamples           bool
+	errorPolicy           ErrorPolicy
+	extrasDirs            []string
+	readMediaInfo         bool
+	preferFolderNames     bool
+	alreadyOrganized      int
+	fs                    fsys.FileSystem
+	copier                *safety.Copier
+	throttle              *safety.Throttle
+	clobberNFO            bool
+	nfoWritten            map[string]bool
+	backupTxn             string
+	backupBeforeMove      bool
+	copySidecarNFO        bool
+	sidecarNFOPolicy      SidecarNFOPolicy
+	destinations          map[types.MediaType]string
+	nfoTypes              map[types.MediaType]bool
+	unrouted              int
+	quarantineYearless    bool
+	yearless              int
+	enrich                EnrichFunc
+	enrichTimeout         time.Duration
+	enrichTimeouts        int
+	planCollisions        int
+	artworkRetries        []safety.ArtworkRetry
+	artworkAttempts       int
+	openLibraryBase       string
+	titleCase             bool
+	smallWords            []string
+	acronyms              []string
 }
 
 // NewOrganizer creates a new organizer instance
@@ -42,6 +120,9 @@ func NewOrganizer(dryRun bool) *Organizer {
 		downloadArtwork:    false,
 		artworkSize:        artwork.SizeMedium,
 		enableTransactions: false,
+		extrasDirs:         jellyfin.DefaultExtrasDirs,
+		fs:                 fsys.OSFileSystem{},
+		copier:             safety.NewCopier(safety.DefaultCopyBufferSize),
 	}
 }
 
@@ -58,7 +139,37 @@ func NewOrganizerWithTransactions(dryRun bool, tm *safety.TransactionManager) *O
 		artworkSize:        artwork.SizeMedium,
 		transactionMgr:     tm,
 		enableTransactions: tm != nil,
+		extrasDirs:         jellyfin.DefaultExtrasDirs,
+		fs:                 fsys.OSFileSystem{},
+		copier:             safety.NewCopier(safety.DefaultCopyBufferSize),
+	}
+}
+
+// SetCopyBuffer sets the buffer size used when a move crosses filesystems
+// and has to be copied, and an optional callback for copy progress
+func (o *Organizer) SetCopyBuffer(size int, progress safety.CopyProgressFunc) {
+	o.copier = safety.NewCopier(size)
+	o.copier.SetFileSystem(o.fs)
+	o.copier.SetProgress(progress)
+	o.copier.SetThrottle(o.throttle)
+}
+
+// SetFileSystem makes the organizer plan and move files on f instead of the
+// local disk, e.g. an fsys.MemFS in tests. Artwork downloads and media info
+// probing still read the local disk.
+func (o *Organizer) SetFileSystem(f fsys.FileSystem) {
+	o.fs = f
+	o.copier.SetFileSystem(f)
+}
+
+// SetThrottle limits file moves to opsPerSec per second and data copied
+// across filesystems to bytesPerSec; zero leaves a limit off
+func (o *Organizer) SetThrottle(opsPerSec int, bytesPerSec int64) {
+	o.throttle = nil
+	if opsPerSec > 0 || bytesPerSec > 0 {
+		o.throttle = safety.NewThrottle(opsPerSec, bytesPerSec)
 	}
+	o.copier.SetThrottle(o.throttle)
 }
 
 // SetCreateNFO enables or disables NFO file creation
@@ -66,6 +177,218 @@ func (o *Organizer) SetCreateNFO(create bool) {
 	o.createNFO = create
 }
 
+// SetNFOTypes limits NFO creation to the given media types; nil creates
+// NFOs for every type
+func (o *Organizer) SetNFOTypes(mediaTypes []types.MediaType) {
+	o.nfoTypes = nil
+	if mediaTypes == nil {
+		return
+	}
+	o.nfoTypes = make(map[types.MediaType]bool, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		o.nfoTypes[mediaType] = true
+	}
+}
+
+// nfoEnabled reports whether NFOs are created for mediaType
+func (o *Organizer) nfoEnabled(mediaType types.MediaType) bool {
+	return o.createNFO && (o.nfoTypes == nil || o.nfoTypes[mediaType])
+}
+
+// SetClobberNFO makes NFO generation overwrite existing NFO files instead of
+// keeping them
+func (o *Organizer) SetClobberNFO(clobber bool) {
+	o.clobberNFO = clobber
+}
+
+// SetRecordOriginalFilename adds each file's name before organizing to its
+// movie or book NFO as <original_filename>
+func (o *Organizer) SetRecordOriginalFilename(enabled bool) {
+	o.nfoGenerator.SetRecordOriginalFilename(enabled)
+}
+
+// SetSidecarNFO enables moving a video's existing "<name>.nfo" along with it,
+// renamed for Jellyfin, and sets how it combines with a generated NFO
+func (o *Organizer) SetSidecarNFO(enabled bool, policy SidecarNFOPolicy) {
+	o.copySidecarNFO = enabled
+	o.sidecarNFOPolicy = policy
+}
+
+// SetBackupBeforeMove enables copying each file into the transaction's
+// backups before it is moved, so rollback can restore it even if the
+// organized file is lost. It has no effect without transactions.
+func (o *Organizer) SetBackupBeforeMove(enabled bool) {
+	o.backupBeforeMove = enabled
+}
+
+// SetHashFiles enables recording a hash of each moved file in its operation
+func (o *Organizer) SetHashFiles(enabled bool) {
+	o.hashFiles = enabled
+}
+
+// SetHashAlgorithm sets the algorithm used when hashing is enabled; empty
+// selects SHA-256
+func (o *Organizer) SetHashAlgorithm(algorithm safety.HashAlgorithm) {
+	o.hashAlgorithm = algorithm
+}
+
+// recordHash stores the destination file's hash and the algorithm used in
+// the operation when hashing is enabled
+func (o *Organizer) recordHash(op *types.Operation) {
+	if !o.hashFiles {
+		return
+	}
+	algorithm := o.hashAlgorithm
+	if algorithm == "" {
+		algorithm = safety.HashSHA256
+	}
+	hash, err := safety.HashFileIn(o.fs, op.Destination, algorithm)
+	if err != nil {
+		log.Warn().Err(err).Str("file", op.Destination).Msg("Failed to hash file")
+		return
+	}
+	op.Hash = hash
+	op.HashAlgorithm = string(algorithm)
+}
+
+// SetRenameOnly makes plans rename files in place: the directory is kept and
+// only the filename is changed to follow the naming rules
+func (o *Organizer) SetRenameOnly(enabled bool) {
+	o.renameOnly = enabled
+}
+
+// SetCollisionLimit sets how many numeric suffixes the rename conflict
+// strategy tries (0 uses DefaultCollisionLimit), and whether to fall back to
+// a hash suffix when they run out
+func (o *Organizer) SetCollisionLimit(limit int, hashFallback bool) {
+	o.collisionLimit = limit
+	o.collisionHashFallback = hashFallback
+}
+
+// SetMiniSeriesLayout sets where episodes numbered without a season go
+// (season or show)
+func (o *Organizer) SetMiniSeriesLayout(layout jellyfin.MiniSeriesLayout) {
+	o.naming.SetMiniSeriesLayout(layout)
+}
+
+// SetMovieLayout sets the destination layout for movies (folder or flat)
+func (o *Organizer) SetMovieLayout(layout jellyfin.MovieLayout) {
+	o.naming.SetMovieLayout(layout)
+}
+
+// SetDestStructure sets the library layout (nested or flat-by-type). The
+// flat-by-type structure has no show, album or book folders, so only movies
+// get NFOs and artwork (named after the movie file).
+func (o *Organizer) SetDestStructure(structure jellyfin.DestStructure) {
+	o.naming.SetDestStructure(structure)
+}
+
+// flatByType reports whether files go directly into their library roots
+func (o *Organizer) flatByType() bool {
+	return o.naming.DestStructure() == jellyfin.StructureFlatByType
+}
+
+// SetMusicLayout sets the destination layout for music (artist or decade-artist)
+func (o *Organizer) SetMusicLayout(layout jellyfin.MusicLayout) {
+	o.naming.SetMusicLayout(layout)
+}
+
+// SetExtrasLayout sets where suffixed movie extras ("Movie-trailer.mkv")
+// go: beside the movie (suffix) or in the matching extras folder (folder)
+func (o *Organizer) SetExtrasLayout(layout jellyfin.ExtrasLayout) {
+	o.naming.SetExtrasLayout(layout)
+}
+
+// SetFirstLetterShards groups the folders of the given media types into
+// first-letter buckets ("Movies/M/The Matrix (1999)/"), skipping articles
+// when picking the letter
+func (o *Organizer) SetFirstLetterShards(mediaTypes []types.MediaType, articles []string) {
+	o.naming.SetFirstLetterShards(mediaTypes, articles)
+}
+
+// SetSortArticles sets the leading articles moved to the end of folder names
+// and used for NFO sort titles; nil or empty disables article sorting
+func (o *Organizer) SetSortArticles(articles []string) {
+	o.naming.SetSortArticles(articles)
+	o.nfoGenerator.SetSortArticles(articles)
+}
+
+// SetTitleCase enables title-casing all-lowercase movie and show titles
+// parsed from filenames ("the lord of the rings" -> "The Lord of the Rings"),
+// keeping smallWords lowercase and writing acronyms in capitals
+func (o *Organizer) SetTitleCase(enabled bool, smallWords, acronyms []string) {
+	o.titleCase = enabled
+	o.smallWords = smallWords
+	o.acronyms = acronyms
+}
+
+// normalizeTitles title-cases a movie or episode's titles that are entirely
+// lowercase; titles with any capitals were cased on purpose and are kept
+func (o *Organizer) normalizeTitles(meta *types.Metadata) {
+	if !o.titleCase || (meta.MovieMetadata == nil && meta.TVMetadata == nil) {
+		return
+	}
+	titles := []*string{&meta.Title}
+	if meta.TVMetadata != nil {
+		titles = append(titles, &meta.TVMetadata.ShowTitle, &meta.TVMetadata.EpisodeTitle)
+	}
+	for _, title := range titles {
+		if *title != "" && *title == strings.ToLower(*title) {
+			*title = util.TitleCase(*title, o.smallWords, o.acronyms)
+		}
+	}
+}
+
+// SetASCIIFold enables transliterating folder and file names to ASCII;
+// NFO titles keep the original Unicode
+func (o *Organizer) SetASCIIFold(enabled bool, mode jellyfin.UnmappedMode) {
+	o.naming.SetASCIIFold(enabled, mode)
+}
+
+// SetErrorPolicy sets what execution does after a failed operation
+// (continue, stop or rollback); the default is continue
+func (o *Organizer) SetErrorPolicy(policy ErrorPolicy) {
+	o.errorPolicy = policy
+}
+
+// haltOnError reports whether execution should stop after a failure
+func (o *Organizer) haltOnError() bool {
+	return o.errorPolicy == ErrorPolicyStop || o.errorPolicy == ErrorPolicyRollback
+}
+
+// SetLooseTrackLayout sets where music tracks without an album are placed
+func (o *Organizer) SetLooseTrackLayout(layout jellyfin.LooseTrackLayout) {
+	o.naming.SetLooseTrackLayout(layout)
+}
+
+// SetIDTokens enables appending provider ID tokens ("[tmdbid-603]") to movie
+// and show names when the ID is known
+func (o *Organizer) SetIDTokens(enabled bool) {
+	o.naming.SetIDTokens(enabled)
+}
+
+// SetFilenameStyle sets the separator and case of generated filenames
+func (o *Organizer) SetFilenameStyle(style jellyfin.FilenameStyle) {
+	o.naming.SetFilenameStyle(style)
+}
+
+// SetUnknownYearPolicy sets how movies, albums and books without a year are
+// handled: omitted from the name, replaced by placeholder, or quarantined,
+// which leaves the file where it is
+func (o *Organizer) SetUnknownYearPolicy(policy jellyfin.UnknownYearPolicy, placeholder string) {
+	o.quarantineYearless = policy == jellyfin.UnknownYearQuarantine
+	if policy != jellyfin.UnknownYearPlaceholder {
+		placeholder = ""
+	}
+	o.naming.SetUnknownYearPlaceholder(placeholder)
+}
+
+// SetEpisodeTitleFallback sets the placeholder template used for TV episode
+// filenames when no episode title is known (e.g. "Episode {episode}")
+func (o *Organizer) SetEpisodeTitleFallback(template string) {
+	o.naming.SetEpisodeTitleFallback(template)
+}
+
 // SetDownloadArtwork enables or disables artwork downloads
 func (o *Organizer) SetDownloadArtwork(download bool, size artwork.ImageSize) {
 	o.downloadArtwork = download
@@ -75,6 +398,107 @@ func (o *Organizer) SetDownloadArtwork(download bool, size artwork.ImageSize) {
 	}
 }
 
+// SetTMDBImageBase sets the base URL used for TMDB artwork downloads
+// (e.g. a mirror or proxy); empty keeps the default
+func (o *Organizer) SetTMDBImageBase(base string) {
+	o.tmdbImageBase = base
+}
+
+// SetGenerateThumbnails enables downloading a small "poster-thumb.jpg"
+// alongside each movie and TV poster
+func (o *Organizer) SetGenerateThumbnails(enabled bool) {
+	o.generateThumbnails = enabled
+}
+
+// SetArtworkFormat sets whether PNG and GIF artwork is converted to JPEG
+// (the default) or saved as served with its own extension
+func (o *Organizer) SetArtworkFormat(format artwork.Format) {
+	o.artworkFormat = format
+}
+
+// SetPosterSources sets the order movie poster sources are tried in (e.g.
+// tmdb, fanart, omdb), with the API keys fanart.tv and OMDb need; empty
+// uses TMDB alone
+func (o *Organizer) SetPosterSources(sources []artwork.Source, keys artwork.SourceKeys) {
+	o.posterSources = sources
+	o.sourceKeys = keys
+}
+
+// posterSourceNames returns the configured poster sources, defaulting to TMDB
+func (o *Organizer) posterSourceNames() []artwork.Source {
+	if len(o.posterSources) == 0 {
+		return []artwork.Source{artwork.SourceTMDB}
+	}
+	return o.posterSources
+}
+
+// SetActorImages enables downloading actor profile images into a Kodi-style
+// ".actors" folder next to each movie and show; it requires enriched credits
+// (see tmdb.Enricher.SetActorLimit)
+func (o *Organizer) SetActorImages(enabled bool) {
+	o.actorImages = enabled
+}
+
+// SetEnricher sets a function run on each file's parsed metadata while
+// planning, so names, NFOs and artwork use the looked-up details. A file
+// that fails to enrich is planned from its parsed metadata.
+func (o *Organizer) SetEnricher(enrich EnrichFunc) {
+	o.enrich = enrich
+}
+
+// SetEnrichTimeout bounds how long enriching one file may take; a lookup
+// still running at the deadline is abandoned and the file is planned from
+// its parsed metadata. Zero waits for every lookup.
+func (o *Organizer) SetEnrichTimeout(timeout time.Duration) {
+	o.enrichTimeout = timeout
+}
+
+// SetSampleFilter configures detection of sample clips: video files smaller
+// than maxSize for their resolution (see metadata.DetectSample). Suspected
+// samples are skipped when skip is true, otherwise planned with a warning.
+func (o *Organizer) SetSampleFilter(maxSize int64, skip bool) {
+	o.sampleMaxSize = maxSize
+	o.skipSamples = skip
+}
+
+// SetExtrasDirs sets the movie subfolder names (e.g. "extras", "trailers")
+// moved along with a movie from its own folder; nil or empty disables this
+func (o *Organizer) SetExtrasDirs(dirs []string) {
+	o.extrasDirs = dirs
+}
+
+// SetReadMediaInfo makes planning take a video's resolution and codec from a
+// companion MediaInfo report ("Movie.mediainfo" or "Movie.txt") when one sits
+// beside it, in place of the tags guessed from its name
+func (o *Organizer) SetReadMediaInfo(read bool) {
+	o.readMediaInfo = read
+}
+
+// SetPreferFolderNames makes planning take a music track's artist and album
+// from the folders above it (see metadata.ApplyMusicFolders) instead of its
+// filename
+func (o *Organizer) SetPreferFolderNames(prefer bool) {
+	o.preferFolderNames = prefer
+}
+
+// SetProbeDuration makes planning settle videos whose filename is ambiguous
+// between movie and TV by reading their running time from the container
+// (see detector.NewWithDurationProbe)
+func (o *Organizer) SetProbeDuration(enabled bool) {
+	if enabled {
+		o.detector = detector.NewWithDurationProbe(metadata.ReadVideoDuration)
+	} else {
+		o.detector = detector.New()
+	}
+}
+
+// SetDestinations sets a library root per media type. PlanOrganization uses
+// them when called with an empty destRoot, so a folder mixing movies, music
+// and books sends each file to its own library.
+func (o *Organizer) SetDestinations(destinations map[types.MediaType]string) {
+	o.destinations = destinations
+}
+
 // Plan represents a planned organization operation
 type Plan struct {
 	SourcePath      string
@@ -84,15 +508,55 @@ type Plan struct {
 	Operation       types.OperationType
 	Conflict        bool
 	ConflictReason  string
+	// ConflictKind is CollisionDestinationExists for a file already on disk
+	// or CollisionPlanCollision when an earlier file in the plan has the
+	// same destination
+	ConflictKind string
+	// Subtitles are companion subtitle files moved alongside the video
+	Subtitles []string
+	// Extras are extras folders (extras/, trailers/, ...) moved into the movie's folder
+	Extras []string
+	// SidecarNFO is the video's own "<name>.nfo", moved when SetSidecarNFO is enabled
+	SidecarNFO string
+	// Warnings are non-fatal issues noticed while planning, e.g. a suspected sample
+	Warnings []string
+}
+
+// ExtraType returns the Jellyfin extra suffix ("trailer", ...) of a plan for
+// a movie extra, or "" for any other plan
+func (p Plan) ExtraType() string {
+	if p.MediaType != types.MediaTypeMovie || p.Metadata == nil || p.Metadata.MovieMetadata == nil {
+		return ""
+	}
+	return p.Metadata.MovieMetadata.ExtraType
+}
+
+// CollisionKind returns the collision log kind of a conflicting plan
+func (p Plan) CollisionKind() string {
+	if p.ConflictKind == "" {
+		return CollisionDestinationExists
+	}
+	return p.ConflictKind
 }
 
 // PlanOrganization analyzes files and creates a plan without executing
 func (o *Organizer) PlanOrganization(files []string, destRoot string, mediaTypeFilter types.MediaType) ([]Plan, error) {
 	plans := make([]Plan, 0, len(files))
+	o.alreadyOrganized = 0
+	o.unrouted = 0
+	o.yearless = 0
+	o.enrichTimeouts = 0
+	o.planCollisions = 0
 
 	for _, file := range files {
+		// Files inside a recognized extras folder travel with their movie
+		if jellyfin.IsExtrasDir(filepath.Base(filepath.Dir(file)), o.extrasDirs) {
+			log.Debug().Str("file", file).Msg("Skipping file in extras folder")
+			continue
+		}
+
 		// Detect media type
-		mediaType := o.detector.Detect(filepath.Base(file))
+		mediaType := o.detector.Detect(file)
 
 		// Skip if filtering by type and doesn't match
 		if mediaTypeFilter != "" && mediaTypeFilter != types.MediaTypeUnknown && mediaType != mediaTypeFilter {
@@ -118,33 +582,193 @@ func (o *Organizer) PlanOrganization(files []string, destRoot string, mediaTypeF
 			log.Warn().Str("file", file).Msg("Parser returned nil metadata, skipping")
 			continue
 		}
+		metadata.ApplyParentTitle(meta, file)
+		metadata.ApplyParentRelease(meta, file)
+		if o.readMediaInfo {
+			metadata.ApplyMediaInfo(meta, file)
+		}
+		if o.preferFolderNames {
+			metadata.ApplyMusicFolders(meta, file)
+		}
+		o.normalizeTitles(meta)
+
+		if o.enrich != nil {
+			if mediaType == types.MediaTypeMusic {
+				metadata.ApplyAudioDuration(meta, file)
+			}
+			err := util.EnrichWithTimeout(context.Background(), o.enrichTimeout, meta, func(ctx context.Context, meta *types.Metadata) error {
+				return o.enrich(ctx, mediaType, meta)
+			})
+			if errors.Is(err, util.ErrEnrichTimeout) {
+				log.Warn().Err(err).Str("file", file).Msg("Enrichment timed out, using parsed metadata")
+				o.enrichTimeouts++
+			} else if err != nil {
+				log.Debug().Err(err).Str("file", file).Msg("Failed to enrich metadata, using parsed metadata")
+			}
+		}
 
-		// Build destination path
-		ext := filepath.Ext(file)
-		destPath := o.naming.BuildFullPath(destRoot, mediaType, meta, ext)
-		if destPath == "" {
-			log.Warn().Str("file", file).Str("type", string(mediaType)).Msg("Could not build destination path, skipping")
+		if o.quarantineYearless && meta.Year == 0 && namedWithYear(mediaType, meta) {
+			log.Warn().Str("file", file).Str("type", string(mediaType)).Msg("No year detected, leaving file in place")
+			o.yearless++
 			continue
 		}
 
-		plan := Plan{
-			SourcePath:      file,
-			DestinationPath: destPath,
-			MediaType:       mediaType,
-			Metadata:        meta,
-			Operation:       types.OperationMove,
+		if plan, ok := o.planFile(file, destRoot, mediaType, meta); ok {
+			plans = append(plans, plan)
 		}
+	}
+
+	o.anchorExtras(plans)
+	o.planCollisions = markPlanCollisions(plans)
+	if o.planCollisions > 0 {
+		log.Warn().Int("files", o.planCollisions).Msg("Several files in the plan want the same destination")
+	}
+
+	return plans, nil
+}
+
+// planFile builds the plan for one file from its media type and metadata;
+// ok is false when the file is skipped (no destination, already in place,
+// or a skipped sample)
+func (o *Organizer) planFile(file, destRoot string, mediaType types.MediaType, meta *types.Metadata) (plan Plan, ok bool) {
+	// Without a single root, each type goes to its own library
+	root := destRoot
+	if root == "" {
+		root = o.destinations[mediaType]
+	}
+	if root == "" {
+		log.Warn().Str("file", file).Str("type", string(mediaType)).Msg("No destination configured for media type, skipping")
+		o.unrouted++
+		return Plan{}, false
+	}
+
+	meta.OriginalFilename = filepath.Base(file)
+
+	// Build destination path
+	ext := filepath.Ext(file)
+	destPath := o.naming.BuildFullPath(root, mediaType, meta, ext)
+	if destPath == "" {
+		log.Warn().Str("file", file).Str("type", string(mediaType)).Msg("Could not build destination path, skipping")
+		return Plan{}, false
+	}
+
+	operation := types.OperationMove
+	if o.renameOnly {
+		// Keep the file where it is and only correct its name
+		destPath = filepath.Join(filepath.Dir(file), filepath.Base(destPath))
+		operation = types.OperationRename
+	}
+
+	// A file already at its computed destination needs nothing, which
+	// keeps re-running organize over an organized library a no-op
+	if samePath(destPath, file) {
+		log.Debug().Str("file", file).Msg("File already organized, skipping")
+		o.alreadyOrganized++
+		return Plan{}, false
+	}
 
-		// Check for conflicts
-		if _, err := os.Stat(destPath); err == nil {
+	plan = Plan{
+		SourcePath:      file,
+		DestinationPath: destPath,
+		MediaType:       mediaType,
+		Metadata:        meta,
+		Operation:       operation,
+	}
+
+	// Carry companion subtitles along with videos
+	if mediaType == types.MediaTypeMovie || mediaType == types.MediaTypeTV {
+		plan.Subtitles = o.findSubtitles(file)
+		if o.copySidecarNFO && plan.ExtraType() == "" {
+			plan.SidecarNFO = o.findSidecarNFO(file)
+		}
+
+		if sample, reason := o.detectSample(file); sample {
+			if o.skipSamples {
+				log.Warn().Str("file", file).Str("reason", reason).Msg("Skipping suspected sample file")
+				return Plan{}, false
+			}
+			plan.Warnings = append(plan.Warnings, "suspected sample: "+reason)
+		}
+	}
+	if mediaType == types.MediaTypeMovie && operation == types.OperationMove && o.naming.MovieLayout() != jellyfin.MovieLayoutFlat && plan.ExtraType() == "" {
+		plan.Extras = o.findExtras(file)
+	}
+
+	// Check for conflicts (a case-only rename on a case-insensitive
+	// filesystem resolves to the source itself and is not a conflict)
+	if destInfo, err := o.fs.Stat(destPath); err == nil {
+		if srcInfo, err := o.fs.Stat(file); err != nil || !os.SameFile(srcInfo, destInfo) {
 			plan.Conflict = true
+			plan.ConflictKind = CollisionDestinationExists
 			plan.ConflictReason = "destination file already exists"
 		}
+	}
+
+	return plan, true
+}
+
+// AlreadyOrganized returns how many files the last PlanOrganization skipped
+// because they were already at their destination
+func (o *Organizer) AlreadyOrganized() int {
+	return o.alreadyOrganized
+}
+
+// Yearless returns how many files the last PlanOrganization left in place
+// because they had no year and the unknown-year policy is quarantine
+func (o *Organizer) Yearless() int {
+	return o.yearless
+}
 
-		plans = append(plans, plan)
+// PlanCollisions returns how many files the last PlanOrganization flagged
+// because an earlier file in the plan has the same destination
+func (o *Organizer) PlanCollisions() int {
+	return o.planCollisions
+}
+
+// EnrichTimeouts returns how many files the last PlanOrganization planned
+// from parsed metadata because their enrichment timed out
+func (o *Organizer) EnrichTimeouts() int {
+	return o.enrichTimeouts
+}
+
+// namedWithYear reports whether a file's organized name carries a
+// "(Year)": movies, books and tracks filed under an album
+func namedWithYear(mediaType types.MediaType, meta *types.Metadata) bool {
+	switch mediaType {
+	case types.MediaTypeMovie, types.MediaTypeBook:
+		return true
+	case types.MediaTypeMusic:
+		return !jellyfin.IsLooseTrack(meta)
+	default:
+		return false
 	}
+}
 
-	return plans, nil
+// Unrouted returns how many files the last PlanOrganization skipped because
+// no destination was configured for their media type
+func (o *Organizer) Unrouted() int {
+	return o.unrouted
+}
+
+// samePath reports whether two paths name the same location. Case differences
+// are significant so case-only renames are still planned
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// detectSample checks whether a video file looks like a sample clip
+func (o *Organizer) detectSample(file string) (bool, string) {
+	info, err := o.fs.Stat(file)
+	if err != nil {
+		// Without a size only the filename can be judged
+		return metadata.DetectSample(filepath.Base(file), 0, 0)
+	}
+	return metadata.DetectSample(filepath.Base(file), info.Size(), o.sampleMaxSize)
 }
 
 // Execute performs the organization based on the plan
@@ -153,24 +777,8 @@ func (o *Organizer) Execute(plans []Plan, conflictStrategy string) ([]types.Oper
 
 	for _, plan := range plans {
 		// Handle conflicts
-		if plan.Conflict {
-			switch conflictStrategy {
-			case "skip":
-				log.Info().Str("file", plan.SourcePath).Msg("Skipping due to conflict")
-				continue
-			case "rename":
-				// Add suffix to destination
-				newPath, err := findAvailableName(plan.DestinationPath)
-				if err != nil {
-					log.Error().Err(err).Str("file", plan.SourcePath).Msg("Failed to find available name")
-					continue
-				}
-				plan.DestinationPath = newPath
-				log.Info().Str("file", plan.SourcePath).Str("new_dest", plan.DestinationPath).Msg("Renamed due to conflict")
-			default:
-				log.Warn().Str("file", plan.SourcePath).Msg("Unknown conflict strategy, skipping")
-				continue
-			}
+		if plan.Conflict && !o.resolveConflict(&plan, conflictStrategy) {
+			continue
 		}
 
 		op := types.Operation{
@@ -184,6 +792,7 @@ func (o *Organizer) Execute(plans []Plan, conflictStrategy string) ([]types.Oper
 			log.Info().Str("source", op.Source).Str("dest", op.Destination).Msg("[DRY-RUN] Would move file")
 			op.Status = types.OperationStatusCompleted
 			operations = append(operations, op)
+			operations = append(operations, o.moveCompanions(plan)...)
 
 			// Show NFO files that would be created
 			nfoOps, err := o.createNFOFiles(plan)
@@ -206,11 +815,15 @@ func (o *Organizer) Execute(plans []Plan, conflictStrategy string) ([]types.Oper
 
 		// Create destination directory
 		destDir := filepath.Dir(plan.DestinationPath)
-		if err := os.MkdirAll(destDir, 0755); err != nil {
+		if err := o.fs.MkdirAll(destDir, 0755); err != nil {
 			op.Status = types.OperationStatusFailed
 			op.Error = fmt.Errorf("failed to create directory: %w", err)
 			log.Error().Err(err).Str("dir", destDir).Msg("Failed to create destination directory")
 			operations = append(operations, op)
+			if o.haltOnError() {
+				log.Warn().Str("policy", string(o.errorPolicy)).Msg("Stopping after first failure")
+				break
+			}
 			continue
 		}
 
@@ -218,13 +831,17 @@ func (o *Organizer) Execute(plans []Plan, conflictStrategy string) ([]types.Oper
 		log.Info().Str("source", op.Source).Str("dest", op.Destination).Msg("Moving file")
 		op.Status = types.OperationStatusInProgress
 
-		if err := os.Rename(op.Source, op.Destination); err != nil {
+		if err := o.copier.Move(op.Source, op.Destination); err != nil {
 			op.Status = types.OperationStatusFailed
 			op.Error = fmt.Errorf("failed to move file: %w", err)
 			log.Error().Err(err).Str("source", op.Source).Str("dest", op.Destination).Msg("Failed to move file")
 		} else {
 			op.Status = types.OperationStatusCompleted
 			log.Info().Str("source", op.Source).Str("dest", op.Destination).Msg("File moved successfully")
+			o.recordHash(&op)
+
+			// Move companion subtitles and extras after successful move
+			operations = append(operations, o.moveCompanions(plan)...)
 
 			// Create NFO files after successful move
 			nfoOps, err := o.createNFOFiles(plan)
@@ -244,6 +861,11 @@ func (o *Organizer) Execute(plans []Plan, conflictStrategy string) ([]types.Oper
 		}
 
 		operations = append(operations, op)
+
+		if op.Status == types.OperationStatusFailed && o.haltOnError() {
+			log.Warn().Str("policy", string(o.errorPolicy)).Msg("Stopping after first failure")
+			break
+		}
 	}
 
 	return operations, nil
@@ -264,30 +886,18 @@ func (o *Organizer) ExecuteWithTransaction(plans []Plan, conflictStrategy string
 
 	log.Info().Str("transaction", txn.ID).Int("plans", len(plans)).Msg("Starting transaction")
 
+	// Files replaced during the transaction are backed up under its ID
+	o.backupTxn = txn.ID
+	defer func() { o.backupTxn = "" }()
+
 	operations := make([]types.Operation, 0, len(plans))
 	operationIndices := make(map[int]int) // maps operations index to transaction index
-	hasErrors := false
+	var firstErr error
 
 	for _, plan := range plans {
 		// Handle conflicts
-		if plan.Conflict {
-			switch conflictStrategy {
-			case "skip":
-				log.Info().Str("file", plan.SourcePath).Msg("Skipping due to conflict")
-				continue
-			case "rename":
-				// Add suffix to destination
-				newPath, err := findAvailableName(plan.DestinationPath)
-				if err != nil {
-					log.Error().Err(err).Str("file", plan.SourcePath).Msg("Failed to find available name")
-					continue
-				}
-				plan.DestinationPath = newPath
-				log.Info().Str("file", plan.SourcePath).Str("new_dest", plan.DestinationPath).Msg("Renamed due to conflict")
-			default:
-				log.Warn().Str("file", plan.SourcePath).Msg("Unknown conflict strategy, skipping")
-				continue
-			}
+		if plan.Conflict && !o.resolveConflict(&plan, conflictStrategy) {
+			continue
 		}
 
 		op := types.Operation{
@@ -305,6 +915,11 @@ func (o *Organizer) ExecuteWithTransaction(plans []Plan, conflictStrategy string
 			o.transactionMgr.AddOperation(txn, op)
 			operationIndices[len(operations)-1] = txnIndex
 
+			for _, subOp := range o.moveCompanions(plan) {
+				o.transactionMgr.AddOperation(txn, subOp)
+				operations = append(operations, subOp)
+			}
+
 			// Show NFO files that would be created
 			nfoOps, err := o.createNFOFiles(plan)
 			if err != nil {
@@ -338,27 +953,62 @@ func (o *Organizer) ExecuteWithTransaction(plans []Plan, conflictStrategy string
 
 		// Create destination directory
 		destDir := filepath.Dir(plan.DestinationPath)
-		if err := os.MkdirAll(destDir, 0755); err != nil {
+		if err := o.fs.MkdirAll(destDir, 0755); err != nil {
 			op.Status = types.OperationStatusFailed
 			op.Error = fmt.Errorf("failed to create directory: %w", err)
 			log.Error().Err(err).Str("dir", destDir).Msg("Failed to create destination directory")
+			o.transactionMgr.UpdateOperation(txn, txnIndex, op)
 			operations = append(operations, op)
-			hasErrors = true
+			if firstErr == nil {
+				firstErr = op.Error
+			}
+			if o.haltOnError() {
+				break
+			}
 			continue
 		}
 
+		// Keep a copy to restore from if the organized file is lost
+		if o.backupBeforeMove {
+			backup := o.transactionMgr.BackupPath(txn.ID, op.Source)
+			if err := o.backupFile(op.Source, backup); err != nil {
+				op.Status = types.OperationStatusFailed
+				op.Error = fmt.Errorf("failed to back up file: %w", err)
+				log.Error().Err(err).Str("source", op.Source).Msg("Failed to back up file before moving it")
+				o.transactionMgr.UpdateOperation(txn, txnIndex, op)
+				operations = append(operations, op)
+				if firstErr == nil {
+					firstErr = op.Error
+				}
+				if o.haltOnError() {
+					break
+				}
+				continue
+			}
+			op.Backup = backup
+		}
+
 		// Move file
 		log.Info().Str("source", op.Source).Str("dest", op.Destination).Msg("Moving file")
 		op.Status = types.OperationStatusInProgress
 
-		if err := os.Rename(op.Source, op.Destination); err != nil {
+		if err := o.copier.Move(op.Source, op.Destination); err != nil {
 			op.Status = types.OperationStatusFailed
 			op.Error = fmt.Errorf("failed to move file: %w", err)
 			log.Error().Err(err).Str("source", op.Source).Str("dest", op.Destination).Msg("Failed to move file")
-			hasErrors = true
+			if firstErr == nil {
+				firstErr = op.Error
+			}
 		} else {
 			op.Status = types.OperationStatusCompleted
 			log.Info().Str("source", op.Source).Str("dest", op.Destination).Msg("File moved successfully")
+			o.recordHash(&op)
+
+			// Move companion subtitles and extras after successful move
+			for _, subOp := range o.moveCompanions(plan) {
+				o.transactionMgr.AddOperation(txn, subOp)
+				operations = append(operations, subOp)
+			}
 
 			// Create NFO files after successful move
 			nfoOps, err := o.createNFOFiles(plan)
@@ -381,18 +1031,36 @@ func (o *Organizer) ExecuteWithTransaction(plans []Plan, conflictStrategy string
 					operations = append(operations, artworkOp)
 				}
 			}
+			for _, retry := range o.takeArtworkRetries() {
+				o.transactionMgr.AddArtworkRetry(txn, retry)
+			}
 		}
 
 		// Update operation status in transaction using saved index
 		o.transactionMgr.UpdateOperation(txn, txnIndex, op)
 
 		operations = append(operations, op)
+
+		if op.Status == types.OperationStatusFailed && o.haltOnError() {
+			break
+		}
 	}
 
 	// Complete or fail transaction
-	if hasErrors {
+	if firstErr != nil {
 		o.transactionMgr.Fail(txn, fmt.Errorf("some operations failed"))
 		log.Warn().Str("transaction", txn.ID).Msg("Transaction completed with errors")
+
+		switch o.errorPolicy {
+		case ErrorPolicyStop:
+			log.Warn().Str("transaction", txn.ID).Msg("Stopped after first failure; completed operations were kept")
+		case ErrorPolicyRollback:
+			log.Warn().Str("transaction", txn.ID).Msg("Rolling back after first failure")
+			if err := o.transactionMgr.Rollback(txn.ID); err != nil {
+				return txn.ID, operations, fmt.Errorf("operation failed (%v) and rollback of transaction %s failed: %w", firstErr, txn.ID, err)
+			}
+			return txn.ID, operations, fmt.Errorf("operation failed, transaction %s rolled back: %w", txn.ID, firstErr)
+		}
 	} else {
 		o.transactionMgr.Complete(txn)
 		log.Info().Str("transaction", txn.ID).Msg("Transaction completed successfully")
@@ -401,200 +1069,630 @@ func (o *Organizer) ExecuteWithTransaction(plans []Plan, conflictStrategy string
 	return txn.ID, operations, nil
 }
 
-// findAvailableName finds an available filename by adding a suffix
-// Returns an error if no available name can be found after 1000 attempts
-func findAvailableName(path string) (string, error) {
-	dir := filepath.Dir(path)
-	base := filepath.Base(path)
-	ext := filepath.Ext(base)
-	name := base[:len(base)-len(ext)]
+// resolveConflict applies the conflict strategy to a conflicting plan and records
+// the outcome in the collision log. Returns false if the plan should be skipped.
+func (o *Organizer) resolveConflict(plan *Plan, conflictStrategy string) bool {
+	collision := Collision{
+		Source:              plan.SourcePath,
+		IntendedDestination: plan.DestinationPath,
+		Kind:                plan.CollisionKind(),
+		Strategy:            conflictStrategy,
+	}
 
-	for i := 1; i < 1000; i++ {
-		newName := fmt.Sprintf("%s-%d%s", name, i, ext)
-		newPath := filepath.Join(dir, newName)
-		if _, err := os.Stat(newPath); os.IsNotExist(err) {
-			return newPath, nil
+	switch conflictStrategy {
+	case "skip":
+		log.Info().Str("file", plan.SourcePath).Msg("Skipping due to conflict")
+		collision.Resolution = CollisionSkipped
+		o.RecordCollision(collision)
+		return false
+	case "rename":
+		// Add suffix to destination
+		newPath, err := availableName(o.fs, plan.DestinationPath, plan.SourcePath, o.collisionLimit, o.collisionHashFallback)
+		if err != nil {
+			log.Error().Err(err).Str("file", plan.SourcePath).Msg("Failed to find available name")
+			collision.Resolution = CollisionFailed
+			collision.Error = err.Error()
+			o.RecordCollision(collision)
+			return false
 		}
+		plan.DestinationPath = newPath
+		log.Info().Str("file", plan.SourcePath).Str("new_dest", plan.DestinationPath).Msg("Renamed due to conflict")
+		collision.Resolution = CollisionRenamed
+		collision.FinalDestination = newPath
+		o.RecordCollision(collision)
+		return true
+	default:
+		log.Warn().Str("file", plan.SourcePath).Msg("Unknown conflict strategy, skipping")
+		collision.Resolution = CollisionSkipped
+		o.RecordCollision(collision)
+		return false
 	}
+}
 
-	// If we somehow exhaust 1000 tries, return error
-	return "", fmt.Errorf("could not find available filename after 1000 attempts for %s", path)
+// findAvailableName finds an available filename by adding a suffix, using the
+// default limit and no hash fallback
+func findAvailableName(path string) (string, error) {
+	return AvailableName(path, "", DefaultCollisionLimit, false)
 }
 
-// createSimpleNFOFile creates a single NFO file with the given parameters
-// This helper function reduces code duplication for movie, music, and book NFO creation
-func (o *Organizer) createSimpleNFOFile(destDir, filename, mediaType string, content string) types.Operation {
-	nfoPath := filepath.Join(destDir, filename)
+// findSubtitles returns external subtitle files next to a video that share its filename stem
+func (o *Organizer) findSubtitles(videoPath string) []string {
+	dir := filepath.Dir(videoPath)
+	base := filepath.Base(videoPath)
+	stem := base[:len(base)-len(filepath.Ext(base))]
 
-	op := types.Operation{
-		Type:        types.OperationCreateFile,
-		Source:      "",
-		Destination: nfoPath,
-		Status:      types.OperationStatusPending,
+	entries, err := o.fs.ReadDir(dir)
+	if err != nil {
+		log.Debug().Err(err).Str("dir", dir).Msg("Failed to read directory for subtitles")
+		return nil
 	}
 
-	if !o.dryRun {
-		if err := os.WriteFile(nfoPath, []byte(content), 0644); err != nil {
-			op.Status = types.OperationStatusFailed
-			op.Error = fmt.Errorf("failed to write %s NFO file: %w", mediaType, err)
-		} else {
-			op.Status = types.OperationStatusCompleted
-			log.Info().Str("path", nfoPath).Msgf("Created %s NFO file", mediaType)
+	var subtitles []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !util.ContainsExtension(jellyfin.SubtitleExtensions, filepath.Ext(name)) {
+			continue
+		}
+		subStem := name[:len(name)-len(filepath.Ext(name))]
+		if subStem == stem || strings.HasPrefix(subStem, stem+".") {
+			subtitles = append(subtitles, filepath.Join(dir, name))
 		}
-	} else {
-		op.Status = types.OperationStatusCompleted
-		log.Info().Str("path", nfoPath).Msgf("[DRY-RUN] Would create %s NFO file", mediaType)
 	}
 
-	return op
+	return subtitles
 }
 
-// createNFOFiles creates NFO files for the media based on type and metadata
-func (o *Organizer) createNFOFiles(plan Plan) ([]types.Operation, error) {
-	if !o.createNFO {
-		return nil, nil
-	}
-
-	operations := make([]types.Operation, 0)
-	destDir := filepath.Dir(plan.DestinationPath)
+// moveCompanions moves a plan's subtitles, sidecar NFO and extras folders
+// next to its destination
+func (o *Organizer) moveCompanions(plan Plan) []types.Operation {
+	operations := append(o.moveSubtitles(plan), o.moveSidecarNFO(plan)...)
+	return append(operations, o.moveExtras(plan)...)
+}
 
-	switch plan.MediaType {
-	case types.MediaTypeMovie:
-		// Create movie.nfo in the movie directory
-		content, err := o.nfoGenerator.GenerateMovieNFO(plan.Metadata)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate movie NFO: %w", err)
+// findSidecarNFO returns the NFO next to a video that shares its filename
+// stem, or "" when there is none
+func (o *Organizer) findSidecarNFO(videoPath string) string {
+	stem := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	for _, ext := range []string{".nfo", ".NFO"} {
+		if info, err := o.fs.Stat(stem + ext); err == nil && info.Mode().IsRegular() {
+			return stem + ext
 		}
+	}
+	return ""
+}
 
-		op := o.createSimpleNFOFile(destDir, "movie.nfo", "movie", content)
-		operations = append(operations, op)
+// sidecarNFOPath returns where a plan's sidecar NFO belongs: the movie NFO
+// for movies and the episode's "<name>.nfo" for TV
+func (o *Organizer) sidecarNFOPath(plan Plan) string {
+	if plan.MediaType == types.MediaTypeMovie {
+		return o.naming.GetMovieNFOPath(plan.DestinationPath)
+	}
+	return strings.TrimSuffix(plan.DestinationPath, filepath.Ext(plan.DestinationPath)) + ".nfo"
+}
 
-	case types.MediaTypeTV:
-		if plan.Metadata.TVMetadata == nil {
-			return nil, nil
+// moveSidecarNFO moves a plan's sidecar NFO to its Jellyfin name. Under the
+// merge policy a movie's sidecar is completed with the generated NFO's
+// missing fields; either way the result is never overwritten by NFO
+// generation later in the run.
+func (o *Organizer) moveSidecarNFO(plan Plan) []types.Operation {
+	if plan.SidecarNFO == "" {
+		return nil
+	}
+
+	destPath := o.sidecarNFOPath(plan)
+	op := types.Operation{
+		Type:        types.OperationMove,
+		Source:      plan.SidecarNFO,
+		Destination: destPath,
+		Status:      types.OperationStatusPending,
+	}
+
+	if _, err := o.fs.Stat(destPath); err == nil && !o.clobberNFO {
+		log.Warn().Str("source", plan.SidecarNFO).Str("dest", destPath).Msg("NFO destination already exists, leaving sidecar NFO in place")
+		return nil
+	}
+
+	if o.dryRun {
+		log.Info().Str("source", plan.SidecarNFO).Str("dest", destPath).Msgf("[DRY-RUN] Would move sidecar NFO (%s)", o.sidecarNFOPolicy)
+		op.Status = types.OperationStatusCompleted
+		o.markNFOWritten(destPath)
+		return []types.Operation{op}
+	}
+
+	merged := o.mergeSidecarNFO(plan)
+	if err := o.copier.Move(plan.SidecarNFO, destPath); err != nil {
+		op.Status = types.OperationStatusFailed
+		op.Error = fmt.Errorf("failed to move sidecar NFO: %w", err)
+		log.Warn().Err(err).Str("source", plan.SidecarNFO).Str("dest", destPath).Msg("Failed to move sidecar NFO")
+		return []types.Operation{op}
+	}
+	op.Status = types.OperationStatusCompleted
+	o.markNFOWritten(destPath)
+	log.Info().Str("source", plan.SidecarNFO).Str("dest", destPath).Msg("Sidecar NFO moved successfully")
+
+	if merged != "" {
+		if err := o.fs.WriteFile(destPath, []byte(merged), 0644); err != nil {
+			log.Warn().Err(err).Str("path", destPath).Msg("Failed to write merged NFO, keeping the sidecar as is")
+		} else {
+			log.Info().Str("path", destPath).Msg("Merged generated metadata into sidecar NFO")
 		}
+	}
 
-		tv := plan.Metadata.TVMetadata
+	return []types.Operation{op}
+}
 
-		// Create tvshow.nfo in the show directory (parent of season directory)
-		showDir := filepath.Dir(destDir)
-		tvshowNFOPath := filepath.Join(showDir, "tvshow.nfo")
-
-		// Check if tvshow.nfo already exists (multiple episodes share same show)
-		if _, err := os.Stat(tvshowNFOPath); err == nil {
-			// File exists, skip creation
-			log.Debug().Str("path", tvshowNFOPath).Msg("Skipping existing tvshow.nfo")
-		} else if !os.IsNotExist(err) {
-			// Stat failed for some other reason (e.g., permission denied)
-			return nil, fmt.Errorf("failed to check if tvshow.nfo exists: %w", err)
+// mergeSidecarNFO returns the sidecar NFO merged with the generated movie NFO,
+// or "" when it should be used as is: under the replace policy, when NFO
+// generation is off, for TV episodes (no episode NFO is generated) or when
+// the sidecar cannot be merged
+func (o *Organizer) mergeSidecarNFO(plan Plan) string {
+	if o.sidecarNFOPolicy != SidecarNFOMerge || !o.nfoEnabled(plan.MediaType) || plan.MediaType != types.MediaTypeMovie {
+		return ""
+	}
+
+	existing, err := fsys.ReadFile(o.fs, plan.SidecarNFO)
+	if err != nil {
+		log.Warn().Err(err).Str("path", plan.SidecarNFO).Msg("Failed to read sidecar NFO, keeping it as is")
+		return ""
+	}
+	generated, err := o.nfoGenerator.GenerateMovieNFO(plan.Metadata)
+	if err != nil {
+		log.Warn().Err(err).Str("path", plan.SidecarNFO).Msg("Failed to generate NFO to merge, keeping sidecar as is")
+		return ""
+	}
+	merged, err := jellyfin.MergeNFO(string(existing), generated)
+	if err != nil {
+		log.Warn().Err(err).Str("path", plan.SidecarNFO).Msg("Cannot merge sidecar NFO, keeping it as is")
+		return ""
+	}
+	return merged
+}
+
+// markNFOWritten records that path holds this run's NFO, so neither
+// keepExistingNFO's clobbering nor a later plan rewrites it
+func (o *Organizer) markNFOWritten(path string) {
+	if o.nfoWritten == nil {
+		o.nfoWritten = make(map[string]bool)
+	}
+	o.nfoWritten[path] = true
+}
+
+// moveSubtitles moves a plan's companion subtitles next to its destination,
+// renamed to Jellyfin's "Name.lang[.forced][.sdh].ext" convention
+func (o *Organizer) moveSubtitles(plan Plan) []types.Operation {
+	if len(plan.Subtitles) == 0 {
+		return nil
+	}
+
+	sourceBase := filepath.Base(plan.SourcePath)
+	videoStem := sourceBase[:len(sourceBase)-len(filepath.Ext(sourceBase))]
+	operations := make([]types.Operation, 0, len(plan.Subtitles))
+
+	for _, subPath := range plan.Subtitles {
+		info := jellyfin.ParseSubtitleInfo(filepath.Base(subPath), videoStem)
+		destPath := o.naming.GetSubtitlePath(plan.DestinationPath, info, filepath.Ext(subPath))
+
+		op := types.Operation{
+			Type:        types.OperationMove,
+			Source:      subPath,
+			Destination: destPath,
+			Status:      types.OperationStatusPending,
+		}
+
+		if _, err := o.fs.Stat(destPath); err == nil {
+			log.Warn().Str("source", subPath).Str("dest", destPath).Msg("Subtitle destination already exists, skipping")
+			continue
+		}
+
+		if o.dryRun {
+			log.Info().Str("source", subPath).Str("dest", destPath).Msg("[DRY-RUN] Would move subtitle")
+			op.Status = types.OperationStatusCompleted
+		} else if err := o.copier.Move(subPath, destPath); err != nil {
+			op.Status = types.OperationStatusFailed
+			op.Error = fmt.Errorf("failed to move subtitle: %w", err)
+			log.Warn().Err(err).Str("source", subPath).Str("dest", destPath).Msg("Failed to move subtitle")
 		} else {
-			// File doesn't exist, create it
-			content, err := o.nfoGenerator.GenerateTVShowNFO(plan.Metadata)
-			if err != nil {
-				return nil, fmt.Errorf("failed to generate TV show NFO: %w", err)
-			}
+			op.Status = types.OperationStatusCompleted
+			log.Info().Str("source", subPath).Str("dest", destPath).Msg("Subtitle moved successfully")
+		}
 
-			op := types.Operation{
-				Type:        types.OperationCreateFile,
-				Source:      "",
-				Destination: tvshowNFOPath,
-				Status:      types.OperationStatusPending,
+		operations = append(operations, op)
+	}
+
+	return operations
+}
+
+// findExtras returns the extras folders next to a movie file. Folders are
+// only claimed when the movie is the sole video in its directory, so extras
+// are never carried off with an unrelated file from a shared download folder.
+func (o *Organizer) findExtras(videoPath string) []string {
+	if len(o.extrasDirs) == 0 {
+		return nil
+	}
+
+	dir := filepath.Dir(videoPath)
+	entries, err := o.fs.ReadDir(dir)
+	if err != nil {
+		log.Debug().Err(err).Str("dir", dir).Msg("Failed to read directory for extras")
+		return nil
+	}
+
+	var extras []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			if jellyfin.IsExtrasDir(name, o.extrasDirs) {
+				extras = append(extras, filepath.Join(dir, name))
 			}
+			continue
+		}
+		if filepath.Join(dir, name) == videoPath {
+			continue
+		}
+		// Suffixed extras ("Movie-trailer.mkv") travel on their own
+		if _, extraType := metadata.ParseExtraSuffix(util.RemoveExtension(name)); extraType != "" {
+			continue
+		}
+		if mediaType := o.detector.Detect(name); mediaType != types.MediaTypeMovie && mediaType != types.MediaTypeTV {
+			continue
+		}
+		if sample, _ := metadata.DetectSample(name, 0, 0); !sample {
+			log.Debug().Str("dir", dir).Msg("Directory holds several videos, leaving extras folders in place")
+			return nil
+		}
+	}
 
-			if !o.dryRun {
-				if err := os.WriteFile(tvshowNFOPath, []byte(content), 0644); err != nil {
-					op.Status = types.OperationStatusFailed
-					op.Error = fmt.Errorf("failed to write tvshow NFO: %w", err)
-				} else {
-					op.Status = types.OperationStatusCompleted
-					log.Info().Str("path", tvshowNFOPath).Msg("Created tvshow NFO file")
-				}
-			} else {
-				op.Status = types.OperationStatusCompleted
-				log.Info().Str("path", tvshowNFOPath).Msg("[DRY-RUN] Would create tvshow NFO file")
+	return extras
+}
+
+// anchorExtras places each suffixed extra ("Movie-trailer.mkv") next to the
+// movie planned from the same source folder, so the extra follows the
+// movie's name even when its own filename lacks the year or differs. Extras
+// without a single movie beside them keep the path their own name gives.
+func (o *Organizer) anchorExtras(plans []Plan) {
+	movies := make(map[string]int)
+	for i, plan := range plans {
+		if plan.MediaType != types.MediaTypeMovie || plan.ExtraType() != "" || plan.Operation != types.OperationMove {
+			continue
+		}
+		dir := filepath.Dir(plan.SourcePath)
+		if _, seen := movies[dir]; seen {
+			// Several movies in one folder: the extra's owner is ambiguous
+			movies[dir] = -1
+			continue
+		}
+		movies[dir] = i
+	}
+
+	for i := range plans {
+		extraType := plans[i].ExtraType()
+		if extraType == "" || plans[i].Operation != types.OperationMove {
+			continue
+		}
+		movie, ok := movies[filepath.Dir(plans[i].SourcePath)]
+		if !ok || movie < 0 {
+			continue
+		}
+		ext := filepath.Ext(plans[i].SourcePath)
+		plans[i].DestinationPath = o.naming.GetMovieExtraPath(plans[movie].DestinationPath, extraType, ext)
+
+		_, err := o.fs.Stat(plans[i].DestinationPath)
+		plans[i].Conflict = err == nil
+		plans[i].ConflictKind, plans[i].ConflictReason = "", ""
+		if plans[i].Conflict {
+			plans[i].ConflictKind = CollisionDestinationExists
+			plans[i].ConflictReason = "destination file already exists"
+		}
+	}
+}
+
+// moveExtras moves a plan's extras folders into its destination directory,
+// keeping their names. Each file inside is moved through the copier, so
+// folders on another disk are copied, and the emptied source folders are
+// removed afterwards.
+func (o *Organizer) moveExtras(plan Plan) []types.Operation {
+	if len(plan.Extras) == 0 {
+		return nil
+	}
+
+	destDir := filepath.Dir(plan.DestinationPath)
+	var operations []types.Operation
+
+	for _, extrasPath := range plan.Extras {
+		destPath := filepath.Join(destDir, filepath.Base(extrasPath))
+		if _, err := o.fs.Stat(destPath); err == nil {
+			log.Warn().Str("source", extrasPath).Str("dest", destPath).Msg("Extras destination already exists, skipping")
+			continue
+		}
+
+		var files, dirs []string
+		err := fsys.WalkDir(o.fs, extrasPath, func(path string, d os.DirEntry, err error) error {
+			switch {
+			case err != nil:
+				return err
+			case d.IsDir():
+				dirs = append(dirs, path)
+			case d.Type().IsRegular():
+				files = append(files, path)
 			}
+			return nil
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("source", extrasPath).Msg("Failed to read extras folder")
+			operations = append(operations, types.Operation{
+				Type:        types.OperationMove,
+				Source:      extrasPath,
+				Destination: destPath,
+				Status:      types.OperationStatusFailed,
+				Error:       fmt.Errorf("failed to read extras folder: %w", err),
+			})
+			continue
+		}
 
+		moved := 0
+		for _, file := range files {
+			rel, _ := filepath.Rel(extrasPath, file)
+			op := o.moveExtrasFile(file, filepath.Join(destPath, rel))
+			if op.Status == types.OperationStatusCompleted {
+				moved++
+			}
 			operations = append(operations, op)
 		}
 
-		// Create season.nfo in the season directory
-		seasonNFOPath := filepath.Join(destDir, "season.nfo")
-
-		// Check if season.nfo already exists (multiple episodes share same season)
-		if _, err := os.Stat(seasonNFOPath); err == nil {
-			// File exists, skip creation
-			log.Debug().Str("path", seasonNFOPath).Msg("Skipping existing season.nfo")
-		} else if !os.IsNotExist(err) {
-			// Stat failed for some other reason (e.g., permission denied)
-			return nil, fmt.Errorf("failed to check if season.nfo exists: %w", err)
+		if o.dryRun {
+			log.Info().Str("source", extrasPath).Str("dest", destPath).Msg("[DRY-RUN] Would move extras folder")
+			continue
+		}
+		log.Info().Str("source", extrasPath).Str("dest", destPath).Int("files", moved).Msg("Extras folder moved")
+
+		// Deepest first; folders still holding something stay
+		for i := len(dirs) - 1; i >= 0; i-- {
+			o.fs.Remove(dirs[i])
+		}
+	}
+
+	return operations
+}
+
+// moveExtrasFile moves one file of an extras folder, creating its directory
+func (o *Organizer) moveExtrasFile(source, dest string) types.Operation {
+	op := types.Operation{
+		Type:        types.OperationMove,
+		Source:      source,
+		Destination: dest,
+		Status:      types.OperationStatusCompleted,
+	}
+	if o.dryRun {
+		return op
+	}
+
+	if err := o.fs.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		op.Status = types.OperationStatusFailed
+		op.Error = fmt.Errorf("failed to create extras directory: %w", err)
+	} else if err := o.copier.Move(source, dest); err != nil {
+		op.Status = types.OperationStatusFailed
+		op.Error = fmt.Errorf("failed to move extras file: %w", err)
+	}
+	if op.Error != nil {
+		log.Warn().Err(op.Error).Str("source", source).Str("dest", dest).Msg("Failed to move extras file")
+	}
+	return op
+}
+
+// createSimpleNFOFile creates a single NFO file with the given parameters
+// This helper function reduces code duplication for movie, music, and book NFO creation
+func (o *Organizer) createSimpleNFOFile(destDir, filename, mediaType string, content string) types.Operation {
+	nfoPath := filepath.Join(destDir, filename)
+
+	op := types.Operation{
+		Type:        types.OperationCreateFile,
+		Source:      "",
+		Destination: nfoPath,
+		Status:      types.OperationStatusPending,
+	}
+
+	if !o.dryRun {
+		if err := o.fs.WriteFile(nfoPath, []byte(content), 0644); err != nil {
+			op.Status = types.OperationStatusFailed
+			op.Error = fmt.Errorf("failed to write %s NFO file: %w", mediaType, err)
 		} else {
-			// File doesn't exist, create it
-			content, err := o.nfoGenerator.GenerateSeasonNFO(tv.Season)
-			if err != nil {
-				return nil, fmt.Errorf("failed to generate season NFO: %w", err)
-			}
+			op.Status = types.OperationStatusCompleted
+			log.Info().Str("path", nfoPath).Msgf("Created %s NFO file", mediaType)
+		}
+	} else {
+		op.Status = types.OperationStatusCompleted
+		log.Info().Str("path", nfoPath).Msgf("[DRY-RUN] Would create %s NFO file", mediaType)
+	}
 
-			op := types.Operation{
-				Type:        types.OperationCreateFile,
-				Source:      "",
-				Destination: seasonNFOPath,
-				Status:      types.OperationStatusPending,
-			}
+	return op
+}
 
-			if !o.dryRun {
-				if err := os.WriteFile(seasonNFOPath, []byte(content), 0644); err != nil {
-					op.Status = types.OperationStatusFailed
-					op.Error = fmt.Errorf("failed to write season NFO: %w", err)
-				} else {
-					op.Status = types.OperationStatusCompleted
-					log.Info().Str("path", seasonNFOPath).Msg("Created season NFO file")
-				}
-			} else {
-				op.Status = types.OperationStatusCompleted
-				log.Info().Str("path", seasonNFOPath).Msg("[DRY-RUN] Would create season NFO file")
-			}
+// keepExistingNFO reports whether an NFO at path should be left alone: an
+// existing file is kept unless clobbering, and an NFO already written this
+// run is never rewritten (episodes share tvshow.nfo and season.nfo, and a
+// moved sidecar NFO takes the generated one's place)
+func (o *Organizer) keepExistingNFO(path string) (bool, error) {
+	if o.nfoWritten[path] {
+		return true, nil
+	}
+	if _, err := o.fs.Stat(path); err == nil {
+		if o.clobberNFO {
+			return false, nil
+		}
+		log.Debug().Str("path", path).Msg("Skipping existing NFO file")
+		return true, nil
+	} else if !os.IsNotExist(err) {
+		// Stat failed for some other reason (e.g., permission denied)
+		return false, fmt.Errorf("failed to check if %s exists: %w", filepath.Base(path), err)
+	}
+	return false, nil
+}
 
-			operations = append(operations, op)
+// writeNFO generates and writes an NFO unless keepExistingNFO says to keep
+// the file already there
+func (o *Organizer) writeNFO(path, mediaType string, generate func() (string, error)) ([]types.Operation, error) {
+	keep, err := o.keepExistingNFO(path)
+	if err != nil || keep {
+		return nil, err
+	}
+
+	content, err := generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s NFO: %w", mediaType, err)
+	}
+
+	if o.clobberNFO {
+		o.markNFOWritten(path)
+		if _, err := o.fs.Stat(path); err == nil {
+			return []types.Operation{o.replaceNFOFile(path, mediaType, content)}, nil
+		}
+	}
+	return []types.Operation{o.createSimpleNFOFile(filepath.Dir(path), filepath.Base(path), mediaType, content)}, nil
+}
+
+// replaceNFOFile overwrites the existing NFO at path with content. Inside a
+// transaction the old file is first copied to the transaction's backups, so
+// rollback can put it back.
+func (o *Organizer) replaceNFOFile(path, mediaType, content string) types.Operation {
+	op := types.Operation{
+		Type:        types.OperationReplaceFile,
+		Destination: path,
+		Status:      types.OperationStatusPending,
+	}
+	if o.backupTxn != "" {
+		op.Source = o.transactionMgr.BackupPath(o.backupTxn, path)
+	}
+
+	if o.dryRun {
+		op.Status = types.OperationStatusCompleted
+		log.Info().Str("path", path).Msgf("[DRY-RUN] Would replace %s NFO file", mediaType)
+		return op
+	}
+
+	if op.Source != "" {
+		if err := o.backupFile(path, op.Source); err != nil {
+			op.Status = types.OperationStatusFailed
+			op.Error = fmt.Errorf("failed to back up %s NFO file: %w", mediaType, err)
+			return op
 		}
+	}
+	if err := o.fs.WriteFile(path, []byte(content), 0644); err != nil {
+		op.Status = types.OperationStatusFailed
+		op.Error = fmt.Errorf("failed to write %s NFO file: %w", mediaType, err)
+		return op
+	}
+	op.Status = types.OperationStatusCompleted
+	log.Info().Str("path", path).Str("backup", op.Source).Msgf("Replaced %s NFO file", mediaType)
+	return op
+}
+
+// backupFile copies path to backup, creating backup's directory
+func (o *Organizer) backupFile(path, backup string) error {
+	if err := o.fs.MkdirAll(filepath.Dir(backup), 0755); err != nil {
+		return err
+	}
+	return o.copier.Copy(path, backup)
+}
+
+// createNFOFiles creates NFO files for the media based on type and metadata.
+// Existing NFOs, which may have been edited by hand, are kept unless
+// SetClobberNFO is enabled
+func (o *Organizer) createNFOFiles(plan Plan) ([]types.Operation, error) {
+	// Extras share their movie's NFO and artwork
+	if !o.nfoEnabled(plan.MediaType) || plan.ExtraType() != "" {
+		return nil, nil
+	}
+	// Show, season, album and book NFOs describe a folder that a flat
+	// library does not have
+	if o.flatByType() && plan.MediaType != types.MediaTypeMovie {
+		return nil, nil
+	}
+
+	operations := make([]types.Operation, 0)
+	destDir := filepath.Dir(plan.DestinationPath)
+
+	switch plan.MediaType {
+	case types.MediaTypeMovie:
+		// Create movie.nfo in the movie directory
+		nfoPath := filepath.Join(destDir, filepath.Base(o.naming.GetMovieNFOPath(plan.DestinationPath)))
+		ops, err := o.writeNFO(nfoPath, "movie", func() (string, error) {
+			return o.nfoGenerator.GenerateMovieNFO(plan.Metadata)
+		})
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, ops...)
+
+	case types.MediaTypeTV:
+		if plan.Metadata.TVMetadata == nil {
+			return nil, nil
+		}
+
+		tv := plan.Metadata.TVMetadata
+
+		// Create tvshow.nfo in the show directory (parent of season directory)
+		showDir := filepath.Dir(destDir)
+		ops, err := o.writeNFO(filepath.Join(showDir, "tvshow.nfo"), "tvshow", func() (string, error) {
+			return o.nfoGenerator.GenerateTVShowNFO(plan.Metadata)
+		})
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, ops...)
+
+		// Create season.nfo in the season directory
+		ops, err = o.writeNFO(filepath.Join(destDir, "season.nfo"), "season", func() (string, error) {
+			return o.nfoGenerator.GenerateSeasonNFO(tv.Season)
+		})
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, ops...)
 
 	case types.MediaTypeMusic:
+		// Singles and Compilations buckets hold unrelated tracks, not an album
+		if jellyfin.IsLooseTrack(plan.Metadata) && o.naming.LooseTrackLayout() != jellyfin.LooseTrackUnknownAlbum {
+			break
+		}
+
 		// Create album.nfo in the album directory
-		content, err := o.nfoGenerator.GenerateMusicAlbumNFO(plan.Metadata)
+		ops, err := o.writeNFO(filepath.Join(destDir, "album.nfo"), "album", func() (string, error) {
+			return o.nfoGenerator.GenerateMusicAlbumNFO(plan.Metadata)
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate music album NFO: %w", err)
+			return nil, err
 		}
-
-		op := o.createSimpleNFOFile(destDir, "album.nfo", "album", content)
-		operations = append(operations, op)
+		operations = append(operations, ops...)
 
 	case types.MediaTypeBook:
 		// Create book.nfo in the book directory
-		content, err := o.nfoGenerator.GenerateBookNFO(plan.Metadata)
+		ops, err := o.writeNFO(filepath.Join(destDir, "book.nfo"), "book", func() (string, error) {
+			return o.nfoGenerator.GenerateBookNFO(plan.Metadata)
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate book NFO: %w", err)
+			return nil, err
 		}
-
-		op := o.createSimpleNFOFile(destDir, "book.nfo", "book", content)
-		operations = append(operations, op)
+		operations = append(operations, ops...)
 	}
 
 	return operations, nil
 }
 
-// ValidatePlan checks if a plan can be executed safely
+// ValidatePlan checks if a plan can be executed safely. Each error is a
+// *ValidationError naming the plan that failed.
 func (o *Organizer) ValidatePlan(plans []Plan) []error {
 	errors := make([]error, 0)
 
 	for _, plan := range plans {
 		// Check source exists and is readable
-		info, err := os.Stat(plan.SourcePath)
+		info, err := o.fs.Stat(plan.SourcePath)
 		if err != nil {
-			errors = append(errors, fmt.Errorf("source file %s: %w", plan.SourcePath, err))
+			errors = append(errors, invalidPlan(plan, fmt.Errorf("source file %s: %w", plan.SourcePath, err)))
 			continue
 		}
 
 		if info.IsDir() {
-			errors = append(errors, fmt.Errorf("source %s is a directory, not a file", plan.SourcePath))
+			errors = append(errors, invalidPlan(plan, fmt.Errorf("source %s is a directory, not a file", plan.SourcePath)))
 			continue
 		}
 
@@ -602,25 +1700,151 @@ func (o *Organizer) ValidatePlan(plans []Plan) []error {
 		destDir := filepath.Dir(plan.DestinationPath)
 
 		// Check if parent exists
-		parentInfo, err := os.Stat(filepath.Dir(destDir))
+		parentInfo, err := o.fs.Stat(filepath.Dir(destDir))
 		if err != nil && !os.IsNotExist(err) {
-			errors = append(errors, fmt.Errorf("cannot access parent directory of %s: %w", destDir, err))
+			errors = append(errors, invalidPlan(plan, fmt.Errorf("cannot access parent directory of %s: %w", destDir, err)))
 			continue
 		}
 
 		// If parent exists, check if it's writable
 		if parentInfo != nil && !parentInfo.IsDir() {
-			errors = append(errors, fmt.Errorf("parent of destination %s is not a directory", destDir))
+			errors = append(errors, invalidPlan(plan, fmt.Errorf("parent of destination %s is not a directory", destDir)))
 		}
 	}
 
 	return errors
 }
 
+// downloadPosterThumbnail downloads a small "-thumb" copy next to a poster
+// when thumbnail generation is enabled; an existing thumbnail is left alone
+func (o *Organizer) downloadPosterThumbnail(ctx context.Context, downloader *artwork.TMDBDownloader, posterURL, posterPath string) []types.Operation {
+	if !o.generateThumbnails {
+		return nil
+	}
+
+	thumbPath := artwork.ThumbnailPath(posterPath)
+	op := types.Operation{
+		Type:        types.OperationCreateFile,
+		Source:      posterURL,
+		Destination: thumbPath,
+	}
+
+	if o.dryRun {
+		log.Info().Str("dest", thumbPath).Msg("[DRY-RUN] Would download poster thumbnail")
+		op.Status = types.OperationStatusCompleted
+		return []types.Operation{op}
+	}
+
+	if artwork.SavedImage(thumbPath) != "" {
+		return nil
+	}
+
+	if err := downloader.DownloadPosterThumbnailTo(ctx, posterURL, thumbPath); err != nil {
+		o.artworkFailed(&op, ArtworkPosterThumbnail, posterURL, err)
+		log.Warn().Err(err).Msg("Failed to download poster thumbnail")
+	} else {
+		op.Status = types.OperationStatusCompleted
+		op.Destination = savedArtworkPath(op.Destination)
+	}
+	return []types.Operation{op}
+}
+
+// savedArtworkPath returns the file artwork requested at path was saved as,
+// which has another extension when the image was kept in its served format
+func savedArtworkPath(path string) string {
+	if saved := artwork.SavedImage(path); saved != "" {
+		return saved
+	}
+	return path
+}
+
+// downloadActorImages downloads each actor's profile image to
+// "<dir>/.actors/First_Last.jpg" when actor images are enabled; existing
+// images are kept, so episodes of one show share a single download
+func (o *Organizer) downloadActorImages(ctx context.Context, downloader *artwork.TMDBDownloader, actors []types.Person, dir string) []types.Operation {
+	if !o.actorImages {
+		return nil
+	}
+
+	var operations []types.Operation
+	for _, actor := range actors {
+		if actor.Thumb == "" || actor.Name == "" {
+			continue
+		}
+
+		imagePath := filepath.Join(dir, jellyfin.ActorsDir, jellyfin.ActorImageName(actor.Name, filepath.Ext(actor.Thumb)))
+		op := types.Operation{
+			Type:        types.OperationCreateFile,
+			Source:      actor.Thumb,
+			Destination: imagePath,
+		}
+
+		if o.dryRun {
+			log.Info().Str("dest", imagePath).Msg("[DRY-RUN] Would download actor image")
+			op.Status = types.OperationStatusCompleted
+			operations = append(operations, op)
+			continue
+		}
+
+		if artwork.SavedImage(imagePath) != "" {
+			continue
+		}
+
+		if err := downloader.DownloadImage(ctx, actor.Thumb, imagePath); err != nil {
+			o.artworkFailed(&op, ArtworkActorImage, actor.Thumb, err)
+			log.Warn().Err(err).Str("actor", actor.Name).Msg("Failed to download actor image")
+		} else {
+			op.Status = types.OperationStatusCompleted
+			op.Destination = savedArtworkPath(op.Destination)
+		}
+		operations = append(operations, op)
+	}
+	return operations
+}
+
+// PreflightDestinations checks up front that every destination directory in
+// the plans is writable (e.g. not on a read-only mount). Each directory is
+// resolved to its nearest existing ancestor, and each distinct ancestor is
+// probed once, so a run into thousands of new folders under one library
+// root makes a single probe; the first failure is returned.
+func (o *Organizer) PreflightDestinations(plans []Plan) error {
+	validator := safety.NewValidator()
+	validator.SetFileSystem(o.fs)
+	resolved := make(map[string]bool)
+	probed := make(map[string]bool)
+
+	for _, plan := range plans {
+		destDir := filepath.Dir(plan.DestinationPath)
+		if resolved[destDir] {
+			continue
+		}
+		resolved[destDir] = true
+
+		existing, err := validator.NearestExistingDir(destDir)
+		if err != nil {
+			return err
+		}
+		if probed[existing] {
+			continue
+		}
+		probed[existing] = true
+
+		if err := validator.CheckExistingDirWritable(existing); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // downloadArtworkForPlan downloads artwork for a media file based on its plan
 // Returns operations for downloaded artwork files for transaction logging
 func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]types.Operation, error) {
-	if !o.downloadArtwork || plan.Metadata == nil {
+	if !o.downloadArtwork || plan.Metadata == nil || plan.ExtraType() != "" {
+		return nil, nil
+	}
+	// Shared names like cover.jpg would collide in a flat library
+	if o.flatByType() && plan.MediaType != types.MediaTypeMovie {
 		return nil, nil
 	}
 
@@ -628,9 +1852,7 @@ func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]ty
 	destDir := filepath.Dir(plan.DestinationPath)
 	operations := make([]types.Operation, 0)
 
-	// Create artwork config
-	artworkConfig := artwork.DefaultConfig()
-	artworkConfig.Force = false // Don't re-download existing artwork
+	artworkConfig := o.artworkConfig()
 
 	switch plan.MediaType {
 	case types.MediaTypeMovie:
@@ -639,11 +1861,13 @@ func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]ty
 		}
 
 		downloader := artwork.NewTMDBDownloader(artworkConfig, o.artworkSize)
+		downloader.SetImageBaseURL(o.tmdbImageBase)
 
-		// Download poster
-		if plan.Metadata.MovieMetadata.PosterURL != "" {
-			posterPath := filepath.Join(destDir, "poster.jpg")
-			if o.dryRun {
+		// Download poster, trying each configured source in turn
+		chain := artwork.NewMoviePosterChain(o.posterSourceNames(), downloader, artworkConfig, o.sourceKeys)
+		posterPath := o.naming.GetMovieArtworkPath(plan.DestinationPath, "poster")
+		if o.dryRun {
+			if plan.Metadata.MovieMetadata.PosterURL != "" || chain.Len() > 1 {
 				log.Info().Str("dest", posterPath).Msg("[DRY-RUN] Would download movie poster")
 				operations = append(operations, types.Operation{
 					Type:        types.OperationCreateFile,
@@ -651,27 +1875,35 @@ func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]ty
 					Destination: posterPath,
 					Status:      types.OperationStatusCompleted,
 				})
-			} else {
-				err := downloader.DownloadMoviePoster(ctx, plan.Metadata.MovieMetadata.PosterURL, destDir)
-				op := types.Operation{
-					Type:        types.OperationCreateFile,
-					Source:      plan.Metadata.MovieMetadata.PosterURL,
-					Destination: posterPath,
-				}
-				if err != nil {
-					op.Status = types.OperationStatusFailed
-					op.Error = err
-					log.Warn().Err(err).Msg("Failed to download movie poster")
-				} else {
-					op.Status = types.OperationStatusCompleted
-				}
+			}
+		} else if chain.Len() > 0 {
+			source, imageURL, err := chain.FetchMoviePoster(ctx, plan.Metadata.MovieMetadata, posterPath)
+			op := types.Operation{
+				Type:        types.OperationCreateFile,
+				Source:      imageURL,
+				Destination: posterPath,
+			}
+			switch {
+			case errors.Is(err, artwork.ErrNotFound):
+				log.Debug().Str("dest", posterPath).Msg("No poster available from any source")
+			case err != nil:
+				o.artworkFailed(&op, ArtworkMoviePoster, plan.Metadata.MovieMetadata.PosterURL, err)
+				log.Warn().Err(err).Msg("Failed to download movie poster")
+				operations = append(operations, op)
+			default:
+				op.Status = types.OperationStatusCompleted
+				op.Destination = savedArtworkPath(op.Destination)
+				log.Debug().Str("source", string(source)).Str("dest", op.Destination).Msg("Downloaded movie poster")
 				operations = append(operations, op)
 			}
 		}
+		if plan.Metadata.MovieMetadata.PosterURL != "" {
+			operations = append(operations, o.downloadPosterThumbnail(ctx, downloader, plan.Metadata.MovieMetadata.PosterURL, posterPath)...)
+		}
 
 		// Download backdrop
 		if plan.Metadata.MovieMetadata.BackdropURL != "" {
-			backdropPath := filepath.Join(destDir, "backdrop.jpg")
+			backdropPath := o.naming.GetMovieArtworkPath(plan.DestinationPath, "backdrop")
 			if o.dryRun {
 				log.Info().Str("dest", backdropPath).Msg("[DRY-RUN] Would download movie backdrop")
 				operations = append(operations, types.Operation{
@@ -681,29 +1913,32 @@ func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]ty
 					Status:      types.OperationStatusCompleted,
 				})
 			} else {
-				err := downloader.DownloadMovieBackdrop(ctx, plan.Metadata.MovieMetadata.BackdropURL, destDir)
+				err := downloader.DownloadMovieBackdropTo(ctx, plan.Metadata.MovieMetadata.BackdropURL, backdropPath)
 				op := types.Operation{
 					Type:        types.OperationCreateFile,
 					Source:      plan.Metadata.MovieMetadata.BackdropURL,
 					Destination: backdropPath,
 				}
 				if err != nil {
-					op.Status = types.OperationStatusFailed
-					op.Error = err
+					o.artworkFailed(&op, ArtworkBackdrop, plan.Metadata.MovieMetadata.BackdropURL, err)
 					log.Warn().Err(err).Msg("Failed to download movie backdrop")
 				} else {
 					op.Status = types.OperationStatusCompleted
+					op.Destination = savedArtworkPath(op.Destination)
 				}
 				operations = append(operations, op)
 			}
 		}
 
+		operations = append(operations, o.downloadActorImages(ctx, downloader, plan.Metadata.MovieMetadata.Actors, destDir)...)
+
 	case types.MediaTypeTV:
 		if plan.Metadata.TVMetadata == nil {
 			return nil, nil
 		}
 
 		downloader := artwork.NewTMDBDownloader(artworkConfig, o.artworkSize)
+		downloader.SetImageBaseURL(o.tmdbImageBase)
 
 		// Download TV show poster (to show directory)
 		if plan.Metadata.TVMetadata.PosterURL != "" {
@@ -722,7 +1957,7 @@ func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]ty
 				})
 			} else {
 				// Only download if it doesn't already exist
-				if !artwork.FileExists(posterPath) {
+				if artwork.SavedImage(posterPath) == "" {
 					err := downloader.DownloadTVPoster(ctx, plan.Metadata.TVMetadata.PosterURL, showDir)
 					op := types.Operation{
 						Type:        types.OperationCreateFile,
@@ -730,17 +1965,21 @@ func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]ty
 						Destination: posterPath,
 					}
 					if err != nil {
-						op.Status = types.OperationStatusFailed
-						op.Error = err
+						o.artworkFailed(&op, ArtworkTVPoster, plan.Metadata.TVMetadata.PosterURL, err)
 						log.Warn().Err(err).Msg("Failed to download TV show poster")
 					} else {
 						op.Status = types.OperationStatusCompleted
+						op.Destination = savedArtworkPath(op.Destination)
 					}
 					operations = append(operations, op)
 				}
 			}
+			operations = append(operations, o.downloadPosterThumbnail(ctx, downloader, plan.Metadata.TVMetadata.PosterURL, posterPath)...)
 		}
 
+		showDir := filepath.Dir(filepath.Dir(plan.DestinationPath))
+		operations = append(operations, o.downloadActorImages(ctx, downloader, plan.Metadata.TVMetadata.Actors, showDir)...)
+
 	case types.MediaTypeMusic:
 		if plan.Metadata.MusicMetadata == nil {
 			return nil, nil
@@ -767,11 +2006,11 @@ func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]ty
 					Destination: coverPath,
 				}
 				if err != nil {
-					op.Status = types.OperationStatusFailed
-					op.Error = err
+					o.artworkFailed(&op, ArtworkAlbumCover, plan.Metadata.MusicMetadata.MusicBrainzRID, err)
 					log.Warn().Err(err).Msg("Failed to download album cover")
 				} else {
 					op.Status = types.OperationStatusCompleted
+					op.Destination = savedArtworkPath(op.Destination)
 				}
 				operations = append(operations, op)
 			}
@@ -783,10 +2022,18 @@ func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]ty
 		}
 
 		downloader := artwork.NewOpenLibraryDownloader(artworkConfig, o.artworkSize)
+		downloader.SetBaseURL(o.openLibraryBase)
 
 		// Download book cover (prefer ISBN)
 		coverPath := filepath.Join(destDir, "cover.jpg")
 		if plan.Metadata.BookMetadata.ISBN != "" {
+			normalized, err := isbn.Normalize(plan.Metadata.BookMetadata.ISBN)
+			if err != nil {
+				log.Warn().Err(err).Str("isbn", plan.Metadata.BookMetadata.ISBN).Msg("Invalid ISBN, skipping book cover download")
+				return operations, nil
+			}
+			plan.Metadata.BookMetadata.ISBN = normalized
+
 			if o.dryRun {
 				log.Info().Str("dest", coverPath).Msg("[DRY-RUN] Would download book cover")
 				operations = append(operations, types.Operation{
@@ -803,11 +2050,11 @@ func (o *Organizer) downloadArtworkForPlan(ctx context.Context, plan Plan) ([]ty
 					Destination: coverPath,
 				}
 				if err != nil {
-					op.Status = types.OperationStatusFailed
-					op.Error = err
+					o.artworkFailed(&op, ArtworkBookCover, plan.Metadata.BookMetadata.ISBN, err)
 					log.Warn().Err(err).Msg("Failed to download book cover")
 				} else {
 					op.Status = types.OperationStatusCompleted
+					op.Destination = savedArtworkPath(op.Destination)
 				}
 				operations = append(operations, op)
 			}
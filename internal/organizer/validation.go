@@ -0,0 +1,96 @@
+package organizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/opd-ai/go-jf-org/internal/util"
+)
+
+// ValidationError is a plan that failed ValidatePlan
+type ValidationError struct {
+	SourcePath      string
+	DestinationPath string
+	Err             error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// invalidPlan wraps err as a ValidationError for plan
+func invalidPlan(plan Plan, err error) *ValidationError {
+	return &ValidationError{SourcePath: plan.SourcePath, DestinationPath: plan.DestinationPath, Err: err}
+}
+
+// ExcludeInvalid returns the plans whose source has no ValidationError in
+// errs, leaving plans untouched
+func ExcludeInvalid(plans []Plan, errs []error) []Plan {
+	invalid := make(map[string]bool, len(errs))
+	for _, err := range errs {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			invalid[verr.SourcePath] = true
+		}
+	}
+	if len(invalid) == 0 {
+		return plans
+	}
+
+	valid := make([]Plan, 0, len(plans))
+	for _, plan := range plans {
+		if !invalid[plan.SourcePath] {
+			valid = append(valid, plan)
+		}
+	}
+	return valid
+}
+
+// ValidationReport is the JSON form of ValidatePlan's errors
+type ValidationReport struct {
+	SchemaVersion int                     `json:"schema_version"`
+	Errors        []ValidationReportEntry `json:"errors"`
+}
+
+// ValidationReportEntry is one plan that failed validation
+type ValidationReportEntry struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination,omitempty"`
+	Error       string `json:"error"`
+}
+
+// WriteValidationReport writes errs as a ValidationReport to a JSON file,
+// creating parent directories as needed
+func WriteValidationReport(path string, errs []error) error {
+	report := ValidationReport{SchemaVersion: util.JSONSchemaVersion, Errors: []ValidationReportEntry{}}
+	for _, err := range errs {
+		entry := ValidationReportEntry{Error: err.Error()}
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			entry.Source, entry.Destination = verr.SourcePath, verr.DestinationPath
+		}
+		report.Errors = append(report.Errors, entry)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal validation report: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create validation report directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write validation report: %w", err)
+	}
+
+	return nil
+}
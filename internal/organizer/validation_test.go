@@ -0,0 +1,79 @@
+package organizer
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidatePlan_ExcludeInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	valid := filepath.Join(tmpDir, "Valid.2020.mkv")
+	createTestFile(t, valid)
+	missing := filepath.Join(tmpDir, "Missing.2020.mkv")
+
+	plans := []Plan{
+		{SourcePath: valid, DestinationPath: filepath.Join(tmpDir, "dest", "Valid (2020)", "Valid (2020).mkv")},
+		{SourcePath: missing, DestinationPath: filepath.Join(tmpDir, "dest", "Missing (2020)", "Missing (2020).mkv")},
+	}
+
+	errs := NewOrganizer(false).ValidatePlan(plans)
+	if len(errs) != 1 {
+		t.Fatalf("ValidatePlan() errors = %v, want 1", errs)
+	}
+	var verr *ValidationError
+	if !errors.As(errs[0], &verr) || verr.SourcePath != missing || verr.DestinationPath != plans[1].DestinationPath {
+		t.Fatalf("ValidatePlan() error = %#v, want a ValidationError for %s", errs[0], missing)
+	}
+	if !errors.Is(errs[0], os.ErrNotExist) {
+		t.Errorf("ValidationError does not wrap the stat error: %v", errs[0])
+	}
+
+	kept := ExcludeInvalid(plans, errs)
+	if len(kept) != 1 || kept[0].SourcePath != valid {
+		t.Errorf("ExcludeInvalid() = %v, want only %s", kept, valid)
+	}
+	if len(ExcludeInvalid(plans, nil)) != 2 {
+		t.Error("ExcludeInvalid() without errors dropped plans")
+	}
+}
+
+func TestWriteValidationReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	errs := []error{
+		invalidPlan(Plan{SourcePath: "/src/a.mkv", DestinationPath: "/dest/a.mkv"}, errors.New("source file /src/a.mkv: missing")),
+		errors.New("not tied to a plan"),
+	}
+
+	path := filepath.Join(tmpDir, "reports", "validation.json")
+	if err := WriteValidationReport(path, errs); err != nil {
+		t.Fatalf("WriteValidationReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report ValidationReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid validation report JSON: %v", err)
+	}
+	want := []ValidationReportEntry{
+		{Source: "/src/a.mkv", Destination: "/dest/a.mkv", Error: "source file /src/a.mkv: missing"},
+		{Error: "not tied to a plan"},
+	}
+	if report.SchemaVersion == 0 || len(report.Errors) != len(want) || report.Errors[0] != want[0] || report.Errors[1] != want[1] {
+		t.Errorf("report = %+v, want errors %+v", report, want)
+	}
+
+	// A clean run still writes a report, with an empty list
+	if err := WriteValidationReport(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if err := json.Unmarshal(data, &report); err != nil || report.Errors == nil || len(report.Errors) != 0 {
+		t.Errorf("empty report = %s, want \"errors\": []", data)
+	}
+}
@@ -0,0 +1,125 @@
+// Package output renders command results as text, JSON or aligned tables.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format selects how a command prints its result
+type Format string
+
+const (
+	// FormatText prints each row as "column: value" lines
+	FormatText Format = "text"
+	// FormatJSON prints the result's JSON encoding
+	FormatJSON Format = "json"
+	// FormatTable prints rows as aligned columns under a header
+	FormatTable Format = "table"
+)
+
+// ParseFormat validates a --output value; "" means text
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON, FormatTable:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid output format: %s (must be text, json or table)", s)
+	}
+}
+
+// Result is a command result that can be rendered in every format. JSON
+// output marshals the result itself; text and table output use Rows.
+type Result interface {
+	// Rows returns the column names and one row of cells per record
+	Rows() (columns []string, rows [][]string)
+}
+
+// Formatter writes a result to w in one format
+type Formatter interface {
+	Format(w io.Writer, r Result) error
+}
+
+// New returns the formatter for f, defaulting to text
+func New(f Format) Formatter {
+	switch f {
+	case FormatJSON:
+		return JSONFormatter{}
+	case FormatTable:
+		return TableFormatter{}
+	default:
+		return TextFormatter{}
+	}
+}
+
+// TextFormatter prints each row as a block of "column: value" lines,
+// skipping empty cells, with a blank line between rows
+type TextFormatter struct{}
+
+// Format implements Formatter
+func (TextFormatter) Format(w io.Writer, r Result) error {
+	columns, rows := r.Rows()
+	width := 0
+	for _, c := range columns {
+		if len(c) > width {
+			width = len(c)
+		}
+	}
+
+	for i, row := range rows {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		for j, cell := range row {
+			if cell == "" || j >= len(columns) {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "%-*s  %s\n", width+1, columns[j]+":", cell); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// JSONFormatter prints the result as indented JSON
+type JSONFormatter struct{}
+
+// Format implements Formatter
+func (JSONFormatter) Format(w io.Writer, r Result) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// TableFormatter prints rows as aligned columns under an upper-case header
+type TableFormatter struct{}
+
+// Format implements Formatter
+func (TableFormatter) Format(w io.Writer, r Result) error {
+	columns, rows := r.Rows()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, len(columns))
+	rule := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = strings.ToUpper(c)
+		rule[i] = strings.Repeat("-", len(c))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	fmt.Fprintln(tw, strings.Join(rule, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
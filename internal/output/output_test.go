@@ -0,0 +1,97 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// fileList is a small result rendered through every formatter
+type fileList struct {
+	Files []fileEntry `json:"files"`
+}
+
+type fileEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Note string `json:"note,omitempty"`
+}
+
+func (l fileList) Rows() ([]string, [][]string) {
+	rows := make([][]string, 0, len(l.Files))
+	for _, f := range l.Files {
+		rows = append(rows, []string{f.Path, f.Type, f.Note})
+	}
+	return []string{"path", "type", "note"}, rows
+}
+
+var testResult = fileList{Files: []fileEntry{
+	{Path: "/media/Movie (2020).mkv", Type: "movie"},
+	{Path: "/media/Show S01E01.mkv", Type: "tv", Note: "no year"},
+}}
+
+func render(t *testing.T, f Format) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := New(f).Format(&buf, testResult); err != nil {
+		t.Fatalf("Format(%s) error = %v", f, err)
+	}
+	return buf.String()
+}
+
+func TestTextFormatter(t *testing.T) {
+	want := "path:  /media/Movie (2020).mkv\n" +
+		"type:  movie\n" +
+		"\n" +
+		"path:  /media/Show S01E01.mkv\n" +
+		"type:  tv\n" +
+		"note:  no year\n"
+	if got := render(t, FormatText); got != want {
+		t.Errorf("text output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	got := render(t, FormatJSON)
+	var decoded fileList
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("json output does not decode: %v\n%s", err, got)
+	}
+	if len(decoded.Files) != 2 || decoded.Files[1].Note != "no year" {
+		t.Errorf("json output decoded to %+v", decoded)
+	}
+	if !strings.HasSuffix(got, "}\n") {
+		t.Errorf("json output should end with a newline: %q", got)
+	}
+}
+
+func TestTableFormatter(t *testing.T) {
+	want := "PATH                     TYPE   NOTE\n" +
+		"----                     ----   ----\n" +
+		"/media/Movie (2020).mkv  movie  \n" +
+		"/media/Show S01E01.mkv   tv     no year\n"
+	if got := render(t, FormatTable); got != want {
+		t.Errorf("table output =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatText, false},
+		{"text", FormatText, false},
+		{"JSON", FormatJSON, false},
+		{"table", FormatTable, false},
+		{"yaml", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
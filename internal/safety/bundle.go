@@ -0,0 +1,308 @@
+package safety
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+const (
+	// backupsDirName holds per-transaction backup copies inside the log directory
+	backupsDirName = "backups"
+	// bundleManifestName is the last entry of an export bundle
+	bundleManifestName = "manifest.json"
+)
+
+// bundleManifest lists every file in an export bundle with its checksum
+type bundleManifest struct {
+	TransactionID string       `json:"transaction_id"`
+	Files         []bundleFile `json:"files"`
+}
+
+// bundleFile is one manifest entry; Name is the slash-separated tar path
+type bundleFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupDir returns the directory holding backup copies made for a
+// transaction. Export and import carry it along with the transaction log.
+func (tm *TransactionManager) BackupDir(id string) string {
+	return filepath.Join(tm.logDir, backupsDirName, id)
+}
+
+// BackupPath returns where transaction id keeps its backup copy of path:
+// the same path, below BackupDir
+func (tm *TransactionManager) BackupPath(id, path string) string {
+	return filepath.Join(tm.BackupDir(id), strings.TrimPrefix(path, filepath.VolumeName(path)))
+}
+
+// Export writes a gzipped tarball holding the transaction log, its backup
+// files and a manifest of their SHA-256 checksums to w
+func (tm *TransactionManager) Export(id string, w io.Writer) error {
+	if _, err := tm.Load(id); err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifest := bundleManifest{TransactionID: id}
+
+	files := tm.files()
+	add := func(name, src string) error {
+		entry, err := addBundleFile(tw, files, name, src)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, entry)
+		return nil
+	}
+
+	if err := add(id+".json", tm.getLogPath(id)); err != nil {
+		return err
+	}
+
+	backupDir := tm.BackupDir(id)
+	err := fsys.WalkDir(files, backupDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == backupDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(backupDir, p)
+		if err != nil {
+			return err
+		}
+		return add(path.Join(backupsDirName, id, filepath.ToSlash(rel)), p)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to bundle backups: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle manifest: %w", err)
+	}
+	hdr := &tar.Header{Name: bundleManifestName, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write bundle manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write bundle manifest: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+// addBundleFile copies src from files into the tarball as name, hashing it
+// on the way
+func addBundleFile(tw *tar.Writer, files fsys.ReadFS, name, src string) (bundleFile, error) {
+	info, err := files.Stat(src)
+	if err != nil {
+		return bundleFile{}, fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+	f, err := files.Open(src)
+	if err != nil {
+		return bundleFile{}, fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer f.Close()
+
+	hdr := &tar.Header{Name: name, Mode: 0644, Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return bundleFile{}, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tw, h), f); err != nil {
+		return bundleFile{}, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return bundleFile{Name: name, Size: info.Size(), SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// Import restores a bundle written by Export into the log directory and
+// returns the transaction ID. Every file is checked against the manifest
+// before anything is installed, and an existing transaction with the same
+// ID is never overwritten.
+func (tm *TransactionManager) Import(r io.Reader) (string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return "", fmt.Errorf("not a transaction bundle: %w", err)
+	}
+	defer gz.Close()
+
+	files := tm.files()
+	tmpDir, err := fsys.MkdirTemp(files, tm.logDir, ".import-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create import directory: %w", err)
+	}
+	defer fsys.RemoveAll(files, tmpDir)
+
+	extracted := make(map[string]bundleFile)
+	var manifest *bundleManifest
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("corrupt transaction bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if !validBundleName(hdr.Name) {
+			return "", fmt.Errorf("transaction bundle has unsafe entry %q", hdr.Name)
+		}
+
+		if hdr.Name == bundleManifestName {
+			manifest = &bundleManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return "", fmt.Errorf("corrupt bundle manifest: %w", err)
+			}
+			continue
+		}
+
+		entry, err := extractBundleFile(files, tr, hdr.Name, filepath.Join(tmpDir, filepath.FromSlash(hdr.Name)))
+		if err != nil {
+			return "", err
+		}
+		extracted[hdr.Name] = entry
+	}
+
+	if manifest == nil {
+		return "", fmt.Errorf("transaction bundle has no manifest")
+	}
+	id := manifest.TransactionID
+	if id == "" || filepath.Base(id) != id || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("transaction bundle has invalid transaction ID %q", id)
+	}
+	if err := checkBundle(manifest, extracted); err != nil {
+		return "", err
+	}
+
+	staged := &TransactionManager{logDir: tmpDir, fs: files}
+	txn, err := staged.Load(id)
+	if err != nil {
+		return "", fmt.Errorf("transaction bundle log is unreadable: %w", err)
+	}
+	if txn.ID != id {
+		return "", fmt.Errorf("transaction bundle log is for %s, manifest says %s", txn.ID, id)
+	}
+
+	if _, err := files.Stat(tm.getLogPath(id)); err == nil {
+		return "", fmt.Errorf("transaction %s already exists in %s", id, tm.logDir)
+	}
+	if _, err := files.Stat(tm.BackupDir(id)); err == nil {
+		return "", fmt.Errorf("backups for transaction %s already exist in %s", id, tm.BackupDir(id))
+	}
+
+	// Backups first, so a log is only visible once everything it needs is there
+	if _, err := files.Stat(staged.BackupDir(id)); err == nil {
+		if err := files.MkdirAll(filepath.Dir(tm.BackupDir(id)), 0755); err != nil {
+			return "", fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		if err := files.Rename(staged.BackupDir(id), tm.BackupDir(id)); err != nil {
+			return "", fmt.Errorf("failed to install backups: %w", err)
+		}
+	}
+	if err := files.Rename(staged.getLogPath(id), tm.getLogPath(id)); err != nil {
+		return "", fmt.Errorf("failed to install transaction log: %w", err)
+	}
+	return id, nil
+}
+
+// validBundleName rejects absolute paths and ".." so entries stay in the
+// import directory
+func validBundleName(name string) bool {
+	if name == "" || path.IsAbs(name) || strings.Contains(name, `\`) {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return path.Clean(name) == name
+}
+
+// extractBundleFile writes one tar entry to dest in files and returns its
+// checksum. An entry repeated in the bundle is an error.
+func extractBundleFile(files fsys.FileSystem, r io.Reader, name, dest string) (bundleFile, error) {
+	if err := files.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return bundleFile{}, fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	if _, err := files.Stat(dest); err == nil {
+		return bundleFile{}, fmt.Errorf("failed to extract %s: %w", name, fs.ErrExist)
+	}
+	f, err := files.Create(dest, 0644)
+	if err != nil {
+		return bundleFile{}, fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return bundleFile{}, fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	return bundleFile{Name: name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// checkBundle compares extracted files with the manifest: the log must be
+// listed, every listed file must belong to the transaction and be present
+// with a matching size and checksum, and nothing may be extra
+func checkBundle(manifest *bundleManifest, extracted map[string]bundleFile) error {
+	logName := manifest.TransactionID + ".json"
+	backupPrefix := path.Join(backupsDirName, manifest.TransactionID) + "/"
+	listed := make(map[string]bool, len(manifest.Files))
+	var problems []string
+	for _, want := range manifest.Files {
+		listed[want.Name] = true
+		got, ok := extracted[want.Name]
+		switch {
+		case want.Name != logName && !strings.HasPrefix(want.Name, backupPrefix):
+			problems = append(problems, want.Name+": not part of the transaction")
+		case !ok:
+			problems = append(problems, want.Name+": missing")
+		case got.Size != want.Size || got.SHA256 != want.SHA256:
+			problems = append(problems, want.Name+": checksum mismatch")
+		}
+	}
+	for name := range extracted {
+		if !listed[name] {
+			problems = append(problems, name+": not in manifest")
+		}
+	}
+	if !listed[logName] {
+		problems = append(problems, logName+": not in manifest")
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("transaction bundle failed integrity check: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package safety
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// exportTestTransaction creates a completed transaction with two backup
+// files and returns its manager, ID and exported bundle
+func exportTestTransaction(t *testing.T) (*TransactionManager, string, []byte) {
+	t.Helper()
+	tm, err := NewTransactionManager(filepath.Join(t.TempDir(), "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err := tm.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := types.Operation{Type: types.OperationMove, Source: "/src/a.mkv", Destination: "/dst/a.mkv", Status: types.OperationStatusCompleted}
+	if err := tm.AddOperation(txn, op); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.Complete(txn); err != nil {
+		t.Fatal(err)
+	}
+
+	backups := map[string]string{
+		"a.mkv":        "backup of a",
+		"nested/b.nfo": "<movie/>",
+	}
+	for name, content := range backups {
+		p := filepath.Join(tm.BackupDir(txn.ID), name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tm.Export(txn.ID, &buf); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	return tm, txn.ID, buf.Bytes()
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	src, id, bundle := exportTestTransaction(t)
+
+	dst, err := NewTransactionManager(filepath.Join(t.TempDir(), "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := dst.Import(bytes.NewReader(bundle))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if got != id {
+		t.Errorf("Import() ID = %s, want %s", got, id)
+	}
+
+	txn, err := dst.Load(id)
+	if err != nil {
+		t.Fatalf("imported transaction not loadable: %v", err)
+	}
+	if txn.Status != TransactionStatusCompleted || len(txn.Operations) != 1 || txn.Operations[0].Source != "/src/a.mkv" {
+		t.Errorf("imported transaction = %+v", txn)
+	}
+
+	for _, name := range []string{"a.mkv", filepath.Join("nested", "b.nfo")} {
+		want, _ := os.ReadFile(filepath.Join(src.BackupDir(id), name))
+		data, err := os.ReadFile(filepath.Join(dst.BackupDir(id), name))
+		if err != nil {
+			t.Errorf("backup %s not imported: %v", name, err)
+		} else if !bytes.Equal(data, want) {
+			t.Errorf("backup %s = %q, want %q", name, data, want)
+		}
+	}
+
+	ids, err := dst.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != id {
+		t.Errorf("List() = %v, want [%s] with no import leftovers", ids, id)
+	}
+
+	// A second import must not overwrite the transaction
+	if _, err := dst.Import(bytes.NewReader(bundle)); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("re-Import() error = %v, want already exists", err)
+	}
+}
+
+func TestExportImport_FileSystem(t *testing.T) {
+	src, id, bundle := exportTestTransaction(t)
+
+	m := fsys.NewMemFS()
+	logDir := filepath.Join(string(filepath.Separator), "txn")
+	dst, err := NewTransactionManagerWithFS(logDir, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.Import(bytes.NewReader(bundle)); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if _, err := dst.Load(id); err != nil {
+		t.Fatalf("imported transaction not loadable: %v", err)
+	}
+	entries, err := m.ReadDir(logDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".import-") {
+			t.Errorf("import directory %s left behind", entry.Name())
+		}
+	}
+
+	// Exporting again from the in-memory manager gives the same backups
+	var buf bytes.Buffer
+	if err := dst.Export(id, &buf); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	again, err := NewTransactionManager(filepath.Join(t.TempDir(), "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := again.Import(&buf); err != nil {
+		t.Fatalf("Import() of the re-exported bundle error = %v", err)
+	}
+	want, _ := os.ReadFile(filepath.Join(src.BackupDir(id), "a.mkv"))
+	if data, err := os.ReadFile(filepath.Join(again.BackupDir(id), "a.mkv")); err != nil || !bytes.Equal(data, want) {
+		t.Errorf("backup after a round trip through memory = %q, %v; want %q", data, err, want)
+	}
+}
+
+func TestImport_RejectsTamperedBundle(t *testing.T) {
+	_, id, bundle := exportTestTransaction(t)
+
+	// Rewrite the bundle with one backup's content altered
+	zr, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(zr)
+	var out bytes.Buffer
+	zw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(zw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		if strings.HasSuffix(hdr.Name, "a.mkv") {
+			data = []byte("tampered!!!")
+		}
+		hdr.Size = int64(len(data))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(data)
+	}
+	tw.Close()
+	zw.Close()
+
+	dst, err := NewTransactionManager(filepath.Join(t.TempDir(), "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.Import(&out); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Import() error = %v, want checksum mismatch", err)
+	}
+	if _, err := dst.Load(id); err == nil {
+		t.Error("tampered bundle's transaction was installed")
+	}
+	if _, err := os.Stat(dst.BackupDir(id)); !os.IsNotExist(err) {
+		t.Errorf("tampered bundle's backups were installed (err = %v)", err)
+	}
+}
+
+func TestImport_RejectsNonBundle(t *testing.T) {
+	tm, err := NewTransactionManager(filepath.Join(t.TempDir(), "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.Import(strings.NewReader("not a tarball")); err == nil {
+		t.Error("Import() of garbage succeeded")
+	}
+}
+
+func TestValidBundleName(t *testing.T) {
+	tests := map[string]bool{
+		"abc.json":            true,
+		"backups/abc/a.mkv":   true,
+		"../abc.json":         false,
+		"/etc/passwd":         false,
+		"backups/../../x":     false,
+		"backups/./abc/a.mkv": false,
+		`backups\abc\a.mkv`:   false,
+		"":                    false,
+	}
+	for name, want := range tests {
+		if got := validBundleName(name); got != want {
+			t.Errorf("validBundleName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
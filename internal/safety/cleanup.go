@@ -0,0 +1,160 @@
+package safety
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+// CleanupMode is what happens to junk files left in an organized source folder
+type CleanupMode string
+
+const (
+	// CleanupTrash moves junk under a timestamped trash directory, keeping
+	// its full path so it can be put back by hand
+	CleanupTrash CleanupMode = "trash"
+	// CleanupRemove deletes junk outright
+	CleanupRemove CleanupMode = "remove"
+)
+
+// CleanupResult reports what CleanSources did
+type CleanupResult struct {
+	// Junk maps each junk file to where it was trashed ("" when removed)
+	Junk map[string]string
+	// RemovedDirs are the source directories removed once empty
+	RemovedDirs []string
+	// Errors are the junk files or directories that could not be cleaned
+	Errors []error
+}
+
+// GetDefaultTrashDir returns the default directory junk files are moved to
+func GetDefaultTrashDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".go-jf-org", "trash"), nil
+}
+
+// IsJunk reports whether a file name matches one of the junk patterns.
+// Patterns are shell globs ("*.nfo", "RARBG.txt") matched case-insensitively
+// against the base name.
+func IsJunk(name string, patterns []string) bool {
+	name = strings.ToLower(filepath.Base(name))
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CleanSources tidies the source directories files were organized out of.
+// Files directly in each of dirs matching patterns are moved under
+// trashDir/<timestamp>/<original path> (CleanupTrash) or deleted
+// (CleanupRemove); files matching no pattern are never touched. Each
+// directory left empty is then removed, along with its empty parents up to
+// but not including the containing root in roots. Only dirs are cleaned, so
+// folders the organizer did not process stay as they are.
+func CleanSources(dirs, roots, patterns []string, mode CleanupMode, trashDir string, now time.Time) CleanupResult {
+	return cleanSources(fsys.OSFileSystem{}, dirs, roots, patterns, mode, trashDir, now)
+}
+
+// cleanSources is CleanSources through f, which holds the trash directory too
+func cleanSources(f fsys.FileSystem, dirs, roots, patterns []string, mode CleanupMode, trashDir string, now time.Time) CleanupResult {
+	result := CleanupResult{Junk: make(map[string]string)}
+	trashRun := filepath.Join(trashDir, now.Format("20060102-150405"))
+	copier := NewCopier(DefaultCopyBufferSize)
+	copier.SetFileSystem(f)
+
+	sorted := append([]string(nil), dirs...)
+	// Longer paths are deeper; clean children before parents
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	for _, dir := range sorted {
+		entries, err := f.ReadDir(dir)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				result.Errors = append(result.Errors, fmt.Errorf("failed to read %s: %w", dir, err))
+			}
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || !IsJunk(entry.Name(), patterns) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if mode == CleanupRemove {
+				if err := f.Remove(path); err != nil {
+					result.Errors = append(result.Errors, fmt.Errorf("failed to remove %s: %w", path, err))
+					continue
+				}
+				result.Junk[path] = ""
+				continue
+			}
+
+			trashed := filepath.Join(trashRun, path)
+			if err := trashFile(f, copier, path, trashed); err != nil {
+				result.Errors = append(result.Errors, err)
+				continue
+			}
+			result.Junk[path] = trashed
+		}
+
+		result.RemovedDirs = append(result.RemovedDirs, removeEmptyDirs(f, dir, roots)...)
+	}
+
+	for path, trashed := range result.Junk {
+		log.Debug().Str("file", path).Str("trash", trashed).Msg("Cleaned junk file")
+	}
+	return result
+}
+
+// trashFile moves a junk file to its trash path without overwriting
+func trashFile(f fsys.FileSystem, copier *Copier, path, trashed string) error {
+	if _, err := fsys.Lstat(f, trashed); err == nil {
+		return fmt.Errorf("failed to trash %s: %s already exists", path, trashed)
+	}
+	if err := f.MkdirAll(filepath.Dir(trashed), 0755); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	if err := copier.Move(path, trashed); err != nil {
+		return fmt.Errorf("failed to trash %s: %w", path, err)
+	}
+	return nil
+}
+
+// removeEmptyDirs removes dir and its empty parents, stopping at the first
+// directory that is not empty or is (or lies outside) one of roots
+func removeEmptyDirs(f fsys.FileSystem, dir string, roots []string) []string {
+	var removed []string
+	for insideRoots(dir, roots) {
+		if err := f.Remove(dir); err != nil {
+			break
+		}
+		removed = append(removed, dir)
+		dir = filepath.Dir(dir)
+	}
+	return removed
+}
+
+// insideRoots reports whether dir lies strictly below one of roots
+func insideRoots(dir string, roots []string) bool {
+	for _, root := range roots {
+		rel, err := filepath.Rel(root, dir)
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package safety
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+func writeCleanupFiles(t *testing.T, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("junk"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCleanSources_Trash(t *testing.T) {
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "unsorted")
+	processed := filepath.Join(root, "Movie.2020.1080p")
+	kept := filepath.Join(root, "Other.2021.720p")
+	trashDir := filepath.Join(tmpDir, "trash")
+
+	junk := []string{
+		filepath.Join(processed, "Movie.2020.1080p.nfo"),
+		filepath.Join(processed, "RARBG.txt"),
+		filepath.Join(processed, "Screens.JPG"),
+	}
+	unlisted := filepath.Join(processed, "Movie.2020.1080p.srt")
+	untouched := filepath.Join(kept, "RARBG.txt")
+	writeCleanupFiles(t, append(junk, unlisted, untouched)...)
+
+	patterns := []string{"*.nfo", "*.jpg", "RARBG.txt"}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	result := CleanSources([]string{processed}, []string{root}, patterns, CleanupTrash, trashDir, now)
+
+	if len(result.Errors) > 0 {
+		t.Fatalf("CleanSources() errors = %v", result.Errors)
+	}
+	if len(result.Junk) != len(junk) {
+		t.Errorf("cleaned %d junk files, want %d: %v", len(result.Junk), len(junk), result.Junk)
+	}
+	for _, path := range junk {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("junk file %s still in source (err = %v)", path, err)
+		}
+		trashed := filepath.Join(trashDir, "20240301-120000", path)
+		if result.Junk[path] != trashed {
+			t.Errorf("Junk[%s] = %q, want %q", path, result.Junk[path], trashed)
+		}
+		if _, err := os.Stat(trashed); err != nil {
+			t.Errorf("junk file not in trash: %v", err)
+		}
+	}
+
+	// Unlisted files and folders the organizer did not process stay
+	for _, path := range []string{unlisted, untouched} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("file %s was touched: %v", path, err)
+		}
+	}
+	if len(result.RemovedDirs) != 0 {
+		t.Errorf("RemovedDirs = %v, want none while a subtitle is left", result.RemovedDirs)
+	}
+}
+
+func TestCleanSources_RemovesEmptyDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "unsorted")
+	season := filepath.Join(root, "Show", "Season 1")
+	writeCleanupFiles(t, filepath.Join(season, "Show.S01.nfo"))
+
+	result := CleanSources([]string{season}, []string{root}, []string{"*.nfo"}, CleanupRemove, filepath.Join(tmpDir, "trash"), time.Now())
+	if len(result.Errors) > 0 {
+		t.Fatalf("CleanSources() errors = %v", result.Errors)
+	}
+	if trashed, ok := result.Junk[filepath.Join(season, "Show.S01.nfo")]; !ok || trashed != "" {
+		t.Errorf("Junk = %v, want the NFO removed", result.Junk)
+	}
+
+	want := []string{season, filepath.Join(root, "Show")}
+	if len(result.RemovedDirs) != len(want) || result.RemovedDirs[0] != want[0] || result.RemovedDirs[1] != want[1] {
+		t.Errorf("RemovedDirs = %v, want %v", result.RemovedDirs, want)
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Errorf("source root removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "trash")); !os.IsNotExist(err) {
+		t.Errorf("trash directory created in remove mode (err = %v)", err)
+	}
+}
+
+func TestIsJunk(t *testing.T) {
+	patterns := []string{"*.nfo", "RARBG.txt", "sample*.jpg"}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"movie.nfo", true},
+		{"Movie.NFO", true},
+		{"rarbg.TXT", true},
+		{"Sample-01.jpg", true},
+		{"notes.txt", false},
+		{"poster.jpg", false},
+		{"Movie.mkv", false},
+	}
+	for _, tt := range tests {
+		if got := IsJunk(tt.name, patterns); got != tt.want {
+			t.Errorf("IsJunk(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCleanSources_FileSystem(t *testing.T) {
+	m := fsys.NewMemFS()
+	root := filepath.Join(string(filepath.Separator), "unsorted")
+	processed := filepath.Join(root, "Movie.2020.1080p")
+	junk := filepath.Join(processed, "RARBG.txt")
+	if err := m.MkdirAll(processed, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile(junk, []byte("junk"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	trashDir := filepath.Join(string(filepath.Separator), "trash")
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	result := cleanSources(m, []string{processed}, []string{root}, []string{"RARBG.txt"}, CleanupTrash, trashDir, now)
+	if len(result.Errors) > 0 {
+		t.Fatalf("cleanSources() errors = %v", result.Errors)
+	}
+
+	trashed := filepath.Join(trashDir, "20240301-120000", junk)
+	if result.Junk[junk] != trashed {
+		t.Errorf("Junk[%s] = %q, want %q", junk, result.Junk[junk], trashed)
+	}
+	if _, err := m.Stat(trashed); err != nil {
+		t.Errorf("junk file not in trash: %v", err)
+	}
+	if len(result.RemovedDirs) != 1 || result.RemovedDirs[0] != processed {
+		t.Errorf("RemovedDirs = %v, want [%s]", result.RemovedDirs, processed)
+	}
+}
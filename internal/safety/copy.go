@@ -0,0 +1,254 @@
+package safety
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+// DefaultCopyBufferSize is the buffer used to stream a file across filesystems
+const DefaultCopyBufferSize = 4 * 1024 * 1024
+
+// CopyProgressFunc is called after each buffer is written during a copy with
+// the source path, bytes written so far and the file's total size
+type CopyProgressFunc func(path string, written, total int64)
+
+// renameFile is os.Rename, replaceable in tests to simulate a cross-device move
+var renameFile = os.Rename
+
+// Copier moves files by rename, falling back to a streaming copy when the
+// destination is on another filesystem. One fixed-size buffer is reused for
+// every copy, so memory stays bounded regardless of file size.
+type Copier struct {
+	mu         sync.Mutex
+	bufferSize int
+	buf        []byte
+	progress   CopyProgressFunc
+	throttle   *Throttle
+	// fs moves files when set to something other than the local disk
+	fs fsys.FileSystem
+}
+
+// NewCopier creates a Copier with the given buffer size in bytes; a
+// non-positive size uses DefaultCopyBufferSize
+func NewCopier(bufferSize int) *Copier {
+	if bufferSize <= 0 {
+		bufferSize = DefaultCopyBufferSize
+	}
+	return &Copier{bufferSize: bufferSize}
+}
+
+// SetProgress sets the callback invoked as a copy advances
+func (c *Copier) SetProgress(fn CopyProgressFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress = fn
+}
+
+// SetThrottle limits the rate of moves and of bytes copied; nil removes the limit
+func (c *Copier) SetThrottle(t *Throttle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.throttle = t
+}
+
+// SetFileSystem makes Move and Copy work through f, streaming a file
+// through f when a rename cannot cross devices; nil or fsys.OSFileSystem
+// keeps the local disk
+func (c *Copier) SetFileSystem(f fsys.FileSystem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fs = f
+}
+
+// Move renames src to dst, copying and then removing src when the rename
+// fails because the two are on different filesystems
+func (c *Copier) Move(src, dst string) error {
+	c.mu.Lock()
+	throttle, f := c.throttle, c.fs
+	c.mu.Unlock()
+	throttle.WaitOp()
+
+	rename, remove := renameFile, os.Remove
+	if f != nil && !fsys.IsOS(f) {
+		rename, remove = f.Rename, f.Remove
+	}
+
+	err := rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	log.Debug().Str("source", src).Str("dest", dst).Msg("Cross-device move, copying instead")
+	if err := c.Copy(src, dst); err != nil {
+		return err
+	}
+	if err := remove(src); err != nil {
+		return fmt.Errorf("copied but failed to remove source: %w", err)
+	}
+	return nil
+}
+
+// Copy streams src into a temporary file next to dst, syncs it to disk and
+// renames it into place, so dst is either absent or complete. The source's
+// permissions and modification time are preserved.
+func (c *Copier) Copy(src, dst string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fs != nil && !fsys.IsOS(c.fs) {
+		return c.copyThrough(c.fs, src, dst)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open source: %w", err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat source: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".copy-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if err := c.stream(tmp, in, src, info.Size()); err != nil {
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := os.Chtimes(tmpPath, info.ModTime(), info.ModTime()); err != nil {
+		log.Debug().Err(err).Str("path", tmpPath).Msg("Failed to preserve modification time")
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		return fmt.Errorf("failed to rename copied file: %w", err)
+	}
+	committed = true
+
+	syncDir(filepath.Dir(dst))
+	return nil
+}
+
+// copyThrough is Copy within f: src is streamed into a temporary file next
+// to dst, which is then renamed into place. The caller holds c.mu.
+func (c *Copier) copyThrough(f fsys.FileSystem, src, dst string) error {
+	info, err := f.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to stat source: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("cannot copy directory %s", src)
+	}
+
+	in, err := f.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open source: %w", err)
+	}
+	defer in.Close()
+
+	tmpPath := filepath.Join(filepath.Dir(dst), ".copy-"+filepath.Base(dst))
+	out, err := f.Create(tmpPath, info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	closed, committed := false, false
+	defer func() {
+		if !closed {
+			out.Close()
+		}
+		if !committed {
+			f.Remove(tmpPath)
+		}
+	}()
+
+	if err := c.stream(out, in, src, info.Size()); err != nil {
+		return err
+	}
+	closed = true
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := f.Rename(tmpPath, dst); err != nil {
+		return fmt.Errorf("failed to rename copied file: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// stream copies in to out through the Copier's fixed buffer, reporting
+// progress for path; the caller holds c.mu
+func (c *Copier) stream(out io.Writer, in io.Reader, path string, total int64) error {
+	if c.buf == nil {
+		c.buf = make([]byte, c.bufferSize)
+	}
+
+	// Wrapping both ends hides ReadFrom/WriteTo so io.CopyBuffer always
+	// streams through the fixed buffer
+	w := &progressWriter{w: out, path: path, total: total, progress: c.progress, throttle: c.throttle}
+	if _, err := io.CopyBuffer(w, struct{ io.Reader }{in}, c.buf); err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	return nil
+}
+
+// syncDir flushes a directory entry so a completed rename survives a crash.
+// Not every platform supports it, so failures are only logged.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		log.Debug().Err(err).Str("dir", dir).Msg("Failed to sync directory")
+	}
+}
+
+// progressWriter counts bytes written and reports them to a progress
+// callback, waiting on the throttle before each write
+type progressWriter struct {
+	w        io.Writer
+	path     string
+	written  int64
+	total    int64
+	progress CopyProgressFunc
+	throttle *Throttle
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.throttle.WaitBytes(len(b))
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.progress != nil {
+		p.progress(p.path, p.written, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,206 @@
+package safety
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+func TestCopier_CopyLargeSparseFileBounded(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "remux.mkv")
+	const size = 64 * 1024 * 1024
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(size); err != nil {
+		t.Fatal(err)
+	}
+	// A marker at the end proves the whole file made it across
+	if _, err := f.WriteAt([]byte("END"), size-3); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	const bufferSize = 1024 * 1024
+	var calls int
+	var last int64
+	c := NewCopier(bufferSize)
+	c.SetProgress(func(path string, written, total int64) {
+		calls++
+		if path != src || total != size {
+			t.Errorf("progress(%q, _, %d), want (%q, _, %d)", path, total, src, size)
+		}
+		if written < last {
+			last = 0 // next copy started
+		}
+		if written <= last || written-last > bufferSize {
+			t.Errorf("progress advanced from %d to %d, want steps of at most %d", last, written, bufferSize)
+		}
+		last = written
+	})
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	dst1 := filepath.Join(tmpDir, "copy1.mkv")
+	if err := c.Copy(src, dst1); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	buf := &c.buf[0]
+
+	dst2 := filepath.Join(tmpDir, "copy2.mkv")
+	if err := c.Copy(src, dst2); err != nil {
+		t.Fatalf("second Copy() error = %v", err)
+	}
+
+	runtime.ReadMemStats(&after)
+
+	if &c.buf[0] != buf || len(c.buf) != bufferSize {
+		t.Error("Copier should reuse a single fixed-size buffer across copies")
+	}
+	// Two 64MB copies through a 1MB buffer must not allocate anywhere near
+	// the file size
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 8*1024*1024 {
+		t.Errorf("copying allocated %d bytes, want memory bounded by the buffer", allocated)
+	}
+	if last != size {
+		t.Errorf("final progress = %d, want %d", last, size)
+	}
+	if calls < 2*size/bufferSize {
+		t.Errorf("progress called %d times, want at least %d", calls, 2*size/bufferSize)
+	}
+
+	for _, dst := range []string{dst1, dst2} {
+		info, err := os.Stat(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() != size {
+			t.Errorf("%s size = %d, want %d", dst, info.Size(), size)
+		}
+		tail := make([]byte, 3)
+		out, err := os.Open(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.ReadAt(tail, size-3)
+		out.Close()
+		if !bytes.Equal(tail, []byte("END")) {
+			t.Errorf("%s tail = %q, want END", dst, tail)
+		}
+	}
+
+	// No temporary files are left behind
+	matches, _ := filepath.Glob(filepath.Join(tmpDir, ".copy-*"))
+	if len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestCopier_MoveCrossDevice(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "movie.mkv")
+	dst := filepath.Join(tmpDir, "dest", "movie.mkv")
+	if err := os.WriteFile(src, []byte("video"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	old := renameFile
+	renameFile = func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+	defer func() { renameFile = old }()
+
+	if err := NewCopier(0).Move(src, dst); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Errorf("source should be removed after a cross-device move, stat err = %v", err)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "video" {
+		t.Errorf("destination content = %q, want %q", data, "video")
+	}
+	if info, _ := os.Stat(dst); info.Mode().Perm() != 0640 {
+		t.Errorf("destination mode = %v, want 0640", info.Mode().Perm())
+	}
+}
+
+// exdevFS is a MemFS that cannot rename between directories, as if each
+// were on its own disk
+type exdevFS struct {
+	*fsys.MemFS
+}
+
+func (e exdevFS) Rename(oldpath, newpath string) error {
+	if filepath.Dir(oldpath) != filepath.Dir(newpath) {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+	return e.MemFS.Rename(oldpath, newpath)
+}
+
+func TestCopier_MoveCrossDeviceThroughFileSystem(t *testing.T) {
+	m := exdevFS{fsys.NewMemFS()}
+	root := string(filepath.Separator)
+	src := filepath.Join(root, "incoming", "movie.mkv")
+	dst := filepath.Join(root, "library", "movie.mkv")
+	content := bytes.Repeat([]byte("video"), 1000)
+	for _, dir := range []string{filepath.Dir(src), filepath.Dir(dst)} {
+		if err := m.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.WriteFile(src, content, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	// A small buffer shows the file is streamed rather than read whole
+	c := NewCopier(1024)
+	c.SetFileSystem(m)
+	writes := 0
+	c.SetProgress(func(path string, written, total int64) { writes++ })
+	if err := c.Move(src, dst); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	if writes < len(content)/1024 {
+		t.Errorf("copy made %d writes, want one per 1024-byte buffer", writes)
+	}
+	if _, err := m.Stat(src); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("source should be removed after a cross-device move, stat err = %v", err)
+	}
+	data, err := fsys.ReadFile(m, dst)
+	if err != nil || !bytes.Equal(data, content) {
+		t.Errorf("destination content differs, err = %v", err)
+	}
+	entries, _ := m.ReadDir(filepath.Dir(dst))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %d entries in the destination", len(entries))
+	}
+}
+
+func TestCopier_MoveOtherErrorsNotCopied(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "missing.mkv")
+
+	if err := NewCopier(0).Move(src, filepath.Join(tmpDir, "dst.mkv")); err == nil {
+		t.Error("Move() of a missing source should fail")
+	}
+}
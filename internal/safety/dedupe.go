@@ -0,0 +1,182 @@
+package safety
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// IdenticalSet is a group of byte-identical files that do not yet share an
+// inode. Paths are sorted; the first is kept when the set is hardlinked.
+type IdenticalSet struct {
+	Size  int64
+	Hash  string
+	Paths []string
+}
+
+// Reclaimable returns the bytes freed by linking every copy to the first
+func (s IdenticalSet) Reclaimable() int64 {
+	return s.Size * int64(len(s.Paths)-1)
+}
+
+// FindIdenticalFiles walks root and returns sets of regular files with the
+// same content. Files are grouped by size first and only same-size files are
+// hashed. Paths that are already hardlinks of one another count once, and
+// empty files are ignored.
+func FindIdenticalFiles(root string) ([]IdenticalSet, error) {
+	type candidate struct {
+		path string
+		info os.FileInfo
+	}
+	bySize := make(map[int64][]candidate)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() == 0 {
+			return nil
+		}
+
+		// Keep one path per inode; WalkDir's lexical order makes it the first
+		for _, c := range bySize[info.Size()] {
+			if os.SameFile(c.info, info) {
+				return nil
+			}
+		}
+		bySize[info.Size()] = append(bySize[info.Size()], candidate{path: path, info: info})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	var sets []IdenticalSet
+	for size, candidates := range bySize {
+		if len(candidates) < 2 {
+			continue
+		}
+		byHash := make(map[string][]string)
+		for _, c := range candidates {
+			hash, err := HashFile(c.path)
+			if err != nil {
+				return nil, err
+			}
+			byHash[hash] = append(byHash[hash], c.path)
+		}
+		for hash, paths := range byHash {
+			if len(paths) < 2 {
+				continue
+			}
+			sort.Strings(paths)
+			sets = append(sets, IdenticalSet{Size: size, Hash: hash, Paths: paths})
+		}
+	}
+
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Paths[0] < sets[j].Paths[0] })
+	return sets, nil
+}
+
+// Hardlink replaces every copy in each set with a hardlink to the set's
+// first file, recording one hardlink operation per replaced path in a new
+// transaction whose ID is returned. A copy on another filesystem is linked
+// to an earlier copy on its own filesystem instead, or kept as it is when it
+// is the first there. Rolling the transaction back re-copies each linked
+// path so it owns its data again.
+func (tm *TransactionManager) Hardlink(sets []IdenticalSet) (string, []types.Operation, error) {
+	txn, err := tm.Begin()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var ops []types.Operation
+	for _, set := range sets {
+		keepers := []string{set.Paths[0]}
+		for _, dup := range set.Paths[1:] {
+			op := types.Operation{
+				Type:        types.OperationHardlink,
+				Destination: dup,
+				Status:      types.OperationStatusCompleted,
+				Hash:        set.Hash,
+			}
+
+			var linkErr error
+			linked := false
+			for _, keeper := range keepers {
+				linkErr = linkOver(keeper, dup)
+				if errors.Is(linkErr, syscall.EXDEV) {
+					continue
+				}
+				op.Source = keeper
+				linked = true
+				break
+			}
+			if !linked {
+				log.Debug().Str("path", dup).Msg("No identical file on the same filesystem, keeping copy")
+				keepers = append(keepers, dup)
+				continue
+			}
+			if linkErr != nil {
+				log.Error().Err(linkErr).Str("path", dup).Msg("Failed to replace file with hardlink")
+				op.Status = types.OperationStatusFailed
+			}
+
+			// AddOperation leaves the error out of the log; the returned
+			// operation carries it
+			if err := tm.AddOperation(txn, op); err != nil {
+				return txn.ID, ops, err
+			}
+			op.Error = linkErr
+			ops = append(ops, op)
+		}
+	}
+
+	if err := tm.Complete(txn); err != nil {
+		return txn.ID, ops, err
+	}
+	return txn.ID, ops, nil
+}
+
+// linkOver atomically replaces dup with a hardlink to target by linking to a
+// temporary name beside dup and renaming it over dup
+func linkOver(target, dup string) error {
+	tmp := filepath.Join(filepath.Dir(dup), ".link-"+filepath.Base(dup))
+	if err := os.Link(target, tmp); err != nil {
+		return fmt.Errorf("failed to link %s: %w", target, err)
+	}
+	if err := os.Rename(tmp, dup); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", dup, err)
+	}
+	return nil
+}
+
+// linkReversible returns why a hardlink cannot be undone, or nil when it can
+func linkReversible(op types.Operation) error {
+	dest, err := os.Stat(op.Destination)
+	if err != nil {
+		return fmt.Errorf("linked file no longer exists: %s", op.Destination)
+	}
+	if src, err := os.Stat(op.Source); err != nil || !os.SameFile(src, dest) {
+		return errNotLinked
+	}
+	return nil
+}
+
+// errNotLinked means a hardlinked path was replaced since; there is nothing to undo
+var errNotLinked = errors.New("no longer a hardlink, nothing to do")
@@ -0,0 +1,98 @@
+package safety
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func sameInode(t *testing.T, a, b string) bool {
+	t.Helper()
+	ia, err := os.Stat(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return os.SameFile(ia, ib)
+}
+
+func TestHardlink_IdenticalFilesShareInode(t *testing.T) {
+	lib := t.TempDir()
+	files := map[string]string{
+		"Collection A/Movie (2020)/Movie (2020).mkv": "identical movie data",
+		"Collection B/Movie (2020)/Movie (2020).mkv": "identical movie data",
+		"Other (2021)/Other (2021).mkv":              "same size, other data",
+		"empty-a.nfo":                                "",
+		"empty-b.nfo":                                "",
+	}
+	for name, content := range files {
+		p := filepath.Join(lib, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := filepath.Join(lib, "Collection A/Movie (2020)/Movie (2020).mkv")
+	b := filepath.Join(lib, "Collection B/Movie (2020)/Movie (2020).mkv")
+
+	sets, err := FindIdenticalFiles(lib)
+	if err != nil {
+		t.Fatalf("FindIdenticalFiles() error = %v", err)
+	}
+	if len(sets) != 1 || len(sets[0].Paths) != 2 || sets[0].Paths[0] != a || sets[0].Paths[1] != b {
+		t.Fatalf("FindIdenticalFiles() = %+v, want one set [%s %s]", sets, a, b)
+	}
+	if got := sets[0].Reclaimable(); got != int64(len("identical movie data")) {
+		t.Errorf("Reclaimable() = %d", got)
+	}
+
+	tm, err := NewTransactionManager(filepath.Join(t.TempDir(), "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txnID, ops, err := tm.Hardlink(sets)
+	if err != nil {
+		t.Fatalf("Hardlink() error = %v", err)
+	}
+	if len(ops) != 1 || ops[0].Type != types.OperationHardlink || ops[0].Source != a || ops[0].Destination != b {
+		t.Fatalf("Hardlink() ops = %+v", ops)
+	}
+	if !sameInode(t, a, b) {
+		t.Fatal("identical files do not share an inode after Hardlink()")
+	}
+	if data, _ := os.ReadFile(b); string(data) != "identical movie data" {
+		t.Errorf("linked file content = %q", data)
+	}
+
+	// Already linked files are not reported again
+	if sets, err := FindIdenticalFiles(lib); err != nil || len(sets) != 0 {
+		t.Errorf("FindIdenticalFiles() after linking = %+v, %v; want none", sets, err)
+	}
+
+	_, steps, err := tm.PlanRollback(txnID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 1 || steps[0].Action != RollbackCopyBack || steps[0].Warning != "" {
+		t.Errorf("PlanRollback() = %+v", steps)
+	}
+
+	if err := tm.Rollback(txnID); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if sameInode(t, a, b) {
+		t.Error("files still share an inode after rollback")
+	}
+	for _, p := range []string{a, b} {
+		if data, _ := os.ReadFile(p); string(data) != "identical movie data" {
+			t.Errorf("%s content after rollback = %q", p, data)
+		}
+	}
+}
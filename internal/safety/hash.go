@@ -0,0 +1,144 @@
+package safety
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+	"github.com/zeebo/blake3"
+)
+
+// HashAlgorithm names the algorithm used for integrity hashes
+type HashAlgorithm string
+
+const (
+	// HashSHA256 is the default algorithm, and the one assumed for
+	// operations logged without an algorithm
+	HashSHA256 HashAlgorithm = "sha256"
+	// HashBLAKE3 is a cryptographic hash much faster than SHA-256 on large files
+	HashBLAKE3 HashAlgorithm = "blake3"
+	// HashCRC32 is a cheap checksum that only catches accidental changes
+	HashCRC32 HashAlgorithm = "crc32"
+)
+
+// ParseHashAlgorithm validates an algorithm name; empty selects SHA-256
+func ParseHashAlgorithm(name string) (HashAlgorithm, error) {
+	switch algorithm := HashAlgorithm(strings.ToLower(strings.TrimSpace(name))); algorithm {
+	case "":
+		return HashSHA256, nil
+	case HashSHA256, HashBLAKE3, HashCRC32:
+		return algorithm, nil
+	default:
+		return "", fmt.Errorf("invalid hash algorithm: %s (must be sha256, blake3 or crc32)", name)
+	}
+}
+
+// NewHasher returns a new hash.Hash for algorithm; empty selects SHA-256
+func NewHasher(algorithm HashAlgorithm) (hash.Hash, error) {
+	switch algorithm {
+	case "", HashSHA256:
+		return sha256.New(), nil
+	case HashBLAKE3:
+		return blake3.New(), nil
+	case HashCRC32:
+		return crc32.NewIEEE(), nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm: %s", algorithm)
+	}
+}
+
+// HashFile returns the hex-encoded SHA-256 of a file, streaming its contents
+func HashFile(path string) (string, error) {
+	return HashFileWith(path, HashSHA256)
+}
+
+// HashFileWith returns the hex-encoded hash of a file using algorithm
+func HashFileWith(path string, algorithm HashAlgorithm) (string, error) {
+	return HashFileIn(fsys.OSFileSystem{}, path, algorithm)
+}
+
+// HashFileIn returns the hex-encoded hash of a file read through f
+func HashFileIn(f fsys.ReadFS, path string, algorithm HashAlgorithm) (string, error) {
+	h, err := NewHasher(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := f.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file for hashing: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to hash file: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyStatus describes the outcome of verifying one hashed operation
+type VerifyStatus string
+
+const (
+	// VerifyStatusOK means the file matches its recorded hash
+	VerifyStatusOK VerifyStatus = "ok"
+	// VerifyStatusModified means the file content differs from its recorded hash
+	VerifyStatusModified VerifyStatus = "modified"
+	// VerifyStatusMissing means the file no longer exists
+	VerifyStatusMissing VerifyStatus = "missing"
+	// VerifyStatusError means the file could not be read
+	VerifyStatusError VerifyStatus = "error"
+)
+
+// VerifyResult is the verification outcome for one operation
+type VerifyResult struct {
+	Operation types.Operation
+	Status    VerifyStatus
+	Actual    string
+	Err       error
+}
+
+// Verify re-hashes the destination of every operation in a transaction that
+// has a recorded hash, with the algorithm it was recorded with, and reports
+// whether the file is unchanged
+func (tm *TransactionManager) Verify(id string) ([]VerifyResult, error) {
+	txn, err := tm.Load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]VerifyResult, 0)
+	for _, op := range txn.Operations {
+		if op.Hash == "" || op.Status != types.OperationStatusCompleted {
+			continue
+		}
+
+		result := VerifyResult{Operation: op}
+		actual, err := HashFileIn(tm.files(), op.Destination, HashAlgorithm(op.HashAlgorithm))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			result.Status = VerifyStatusMissing
+		case err != nil:
+			result.Status = VerifyStatusError
+			result.Err = err
+		case actual != op.Hash:
+			result.Status = VerifyStatusModified
+			result.Actual = actual
+		default:
+			result.Status = VerifyStatusOK
+			result.Actual = actual
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
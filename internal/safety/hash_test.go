@@ -0,0 +1,206 @@
+package safety
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.mkv")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := HashFile(path)
+	if err != nil {
+		t.Fatalf("HashFile() error = %v", err)
+	}
+
+	want := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if got != want {
+		t.Errorf("HashFile() = %s, want %s", got, want)
+	}
+
+	if _, err := HashFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("HashFile() expected error for missing file")
+	}
+}
+
+func TestHashFileWith(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.mkv")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		algorithm HashAlgorithm
+		want      string
+	}{
+		{HashSHA256, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{HashBLAKE3, "ea8f163db38682925e4491c5e58d4bb3506ef8c14eb78a86e908c5624a67200f"},
+		{HashCRC32, "3610a686"},
+	}
+	for _, tt := range tests {
+		got, err := HashFileWith(path, tt.algorithm)
+		if err != nil {
+			t.Fatalf("HashFileWith(%s) error = %v", tt.algorithm, err)
+		}
+		if got != tt.want {
+			t.Errorf("HashFileWith(%s) = %s, want %s", tt.algorithm, got, tt.want)
+		}
+	}
+
+	if _, err := HashFileWith(path, "md5"); err == nil {
+		t.Error("HashFileWith() expected error for unsupported algorithm")
+	}
+}
+
+func TestParseHashAlgorithm(t *testing.T) {
+	for name, want := range map[string]HashAlgorithm{"": HashSHA256, "BLAKE3": HashBLAKE3, " crc32 ": HashCRC32} {
+		got, err := ParseHashAlgorithm(name)
+		if err != nil || got != want {
+			t.Errorf("ParseHashAlgorithm(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseHashAlgorithm("md5"); err == nil {
+		t.Error("ParseHashAlgorithm() expected error for md5")
+	}
+}
+
+func TestTransactionManager_Verify_RecordedAlgorithm(t *testing.T) {
+	tmpDir := t.TempDir()
+	tm, err := NewTransactionManager(filepath.Join(tmpDir, "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, err := tm.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[HashAlgorithm]string{}
+	for _, algorithm := range []HashAlgorithm{HashBLAKE3, HashCRC32} {
+		f := filepath.Join(tmpDir, string(algorithm)+".mkv")
+		if err := os.WriteFile(f, []byte("original"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := HashFileWith(f, algorithm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[algorithm] = f
+		tm.AddOperation(txn, types.Operation{
+			Type:          types.OperationMove,
+			Source:        f + ".src",
+			Destination:   f,
+			Status:        types.OperationStatusCompleted,
+			Hash:          hash,
+			HashAlgorithm: string(algorithm),
+		})
+	}
+	tm.Complete(txn)
+
+	// A BLAKE3 or CRC32 digest never matches a SHA-256, so these only pass
+	// when verify uses the algorithm recorded with each operation
+	results, err := tm.Verify(txn.ID)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Verify() returned %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Status != VerifyStatusOK {
+			t.Errorf("%s: status = %s, want ok", r.Operation.Destination, r.Status)
+		}
+	}
+
+	if err := os.WriteFile(files[HashCRC32], []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results, err = tm.Verify(txn.ID)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	for _, r := range results {
+		want := VerifyStatusOK
+		if r.Operation.Destination == files[HashCRC32] {
+			want = VerifyStatusModified
+		}
+		if r.Status != want {
+			t.Errorf("%s: status = %s, want %s", r.Operation.Destination, r.Status, want)
+		}
+	}
+}
+
+func TestTransactionManager_Verify(t *testing.T) {
+	tmpDir := t.TempDir()
+	tm, err := NewTransactionManager(filepath.Join(tmpDir, "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	intact := filepath.Join(tmpDir, "intact.mkv")
+	modified := filepath.Join(tmpDir, "modified.mkv")
+	removed := filepath.Join(tmpDir, "removed.mkv")
+	for _, f := range []string{intact, modified, removed} {
+		if err := os.WriteFile(f, []byte("original"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txn, err := tm.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{intact, modified, removed} {
+		hash, err := HashFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tm.AddOperation(txn, types.Operation{
+			Type:        types.OperationMove,
+			Source:      f + ".src",
+			Destination: f,
+			Status:      types.OperationStatusCompleted,
+			Hash:        hash,
+		})
+	}
+	// Operations without a hash are ignored
+	tm.AddOperation(txn, types.Operation{Type: types.OperationCreateFile, Destination: intact + ".nfo", Status: types.OperationStatusCompleted})
+	tm.Complete(txn)
+
+	// Tamper with files after the transaction
+	if err := os.WriteFile(modified, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(removed); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := tm.Verify(txn.ID)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	want := map[string]VerifyStatus{
+		intact:   VerifyStatusOK,
+		modified: VerifyStatusModified,
+		removed:  VerifyStatusMissing,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Verify() returned %d results, want %d", len(results), len(want))
+	}
+	for _, r := range results {
+		if r.Status != want[r.Operation.Destination] {
+			t.Errorf("%s: status = %s, want %s", r.Operation.Destination, r.Status, want[r.Operation.Destination])
+		}
+	}
+
+	if _, err := tm.Verify("nonexistent"); err == nil {
+		t.Error("Verify() expected error for unknown transaction")
+	}
+}
@@ -0,0 +1,74 @@
+package safety
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+// IgnoreMarker is the file that makes Jellyfin skip a folder and everything
+// below it when scanning a library
+const IgnoreMarker = ".ignore"
+
+// ignoreMarkerContent identifies markers go-jf-org wrote, the only ones it
+// ever removes
+var ignoreMarkerContent = []byte("# Written by go-jf-org so Jellyfin skips files that are not organized yet\n")
+
+// WriteIgnoreMarker writes an .ignore marker into dir, creating dir if
+// needed. An existing .ignore, ours or the user's, is left as it is;
+// written reports whether a marker was added.
+func WriteIgnoreMarker(dir string) (written bool, err error) {
+	return writeIgnoreMarker(fsys.OSFileSystem{}, dir)
+}
+
+// writeIgnoreMarker is WriteIgnoreMarker through f
+func writeIgnoreMarker(f fsys.FileSystem, dir string) (bool, error) {
+	path := filepath.Join(dir, IgnoreMarker)
+	if _, err := fsys.Lstat(f, path); err == nil {
+		return false, nil
+	}
+	if err := f.MkdirAll(dir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := f.WriteFile(path, ignoreMarkerContent, 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// RemoveIgnoreMarker removes the .ignore marker go-jf-org wrote into dir.
+// A marker the user wrote, or none at all, is not an error; removed reports
+// whether one was deleted.
+func RemoveIgnoreMarker(dir string) (removed bool, err error) {
+	return removeIgnoreMarker(fsys.OSFileSystem{}, dir)
+}
+
+// removeIgnoreMarker is RemoveIgnoreMarker through f
+func removeIgnoreMarker(f fsys.FileSystem, dir string) (bool, error) {
+	path := filepath.Join(dir, IgnoreMarker)
+	data, err := fsys.ReadFile(f, path)
+	if err != nil || !bytes.Equal(data, ignoreMarkerContent) {
+		return false, nil
+	}
+	if err := f.Remove(path); err != nil {
+		return false, fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// RemoveIgnoreMarkerIfAlone removes the marker go-jf-org wrote into dir
+// when nothing else is left there, so emptied folders can go
+func RemoveIgnoreMarkerIfAlone(dir string) (bool, error) {
+	return removeIgnoreMarkerIfAlone(fsys.OSFileSystem{}, dir)
+}
+
+// removeIgnoreMarkerIfAlone is RemoveIgnoreMarkerIfAlone through f
+func removeIgnoreMarkerIfAlone(f fsys.FileSystem, dir string) (bool, error) {
+	entries, err := f.ReadDir(dir)
+	if err != nil || len(entries) != 1 || entries[0].Name() != IgnoreMarker {
+		return false, nil
+	}
+	return removeIgnoreMarker(f, dir)
+}
@@ -0,0 +1,123 @@
+package safety
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestIgnoreMarker(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "incoming")
+	marker := filepath.Join(dir, IgnoreMarker)
+
+	if written, err := WriteIgnoreMarker(dir); err != nil || !written {
+		t.Fatalf("WriteIgnoreMarker() = %v, %v; want the marker and its folder created", written, err)
+	}
+	if written, _ := WriteIgnoreMarker(dir); written {
+		t.Error("WriteIgnoreMarker() wrote over an existing marker")
+	}
+
+	// Not alone: the folder still holds a file
+	os.WriteFile(filepath.Join(dir, "movie.mkv"), []byte("video"), 0644)
+	if removed, _ := RemoveIgnoreMarkerIfAlone(dir); removed {
+		t.Error("RemoveIgnoreMarkerIfAlone() removed the marker beside a file")
+	}
+	os.Remove(filepath.Join(dir, "movie.mkv"))
+	if removed, err := RemoveIgnoreMarkerIfAlone(dir); err != nil || !removed {
+		t.Errorf("RemoveIgnoreMarkerIfAlone() = %v, %v; want the lone marker removed", removed, err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("marker still present, stat err = %v", err)
+	}
+
+	// A marker the user wrote is never removed or replaced
+	os.WriteFile(marker, []byte("mine\n"), 0644)
+	if written, _ := WriteIgnoreMarker(dir); written {
+		t.Error("WriteIgnoreMarker() replaced the user's marker")
+	}
+	if removed, _ := RemoveIgnoreMarker(dir); removed {
+		t.Error("RemoveIgnoreMarker() removed the user's marker")
+	}
+	if data, _ := os.ReadFile(marker); string(data) != "mine\n" {
+		t.Errorf("user's marker = %q, want it untouched", data)
+	}
+}
+
+func TestIgnoreMarker_FileSystem(t *testing.T) {
+	m := fsys.NewMemFS()
+	dir := filepath.Join(string(filepath.Separator), "incoming")
+
+	if written, err := writeIgnoreMarker(m, dir); err != nil || !written {
+		t.Fatalf("writeIgnoreMarker() = %v, %v; want the marker and its folder created", written, err)
+	}
+	if removed, err := removeIgnoreMarkerIfAlone(m, dir); err != nil || !removed {
+		t.Errorf("removeIgnoreMarkerIfAlone() = %v, %v; want the lone marker removed", removed, err)
+	}
+	if entries, _ := m.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("folder still holds %d entries", len(entries))
+	}
+}
+
+// stageMovie writes a marked staging directory holding one staged movie and
+// a completed transaction that moved it there from source
+func stageMovie(t *testing.T, tm *TransactionManager, library, source string) (string, string) {
+	t.Helper()
+	staging := StagingDir(library, time.Now())
+	staged := filepath.Join(staging, "Movie (2020)", "Movie (2020).mkv")
+	if err := os.MkdirAll(filepath.Dir(staged), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(staged, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteIgnoreMarker(staging); err != nil {
+		t.Fatal(err)
+	}
+
+	txn, _ := tm.Begin()
+	tm.AddOperation(txn, types.Operation{Type: types.OperationMove, Source: source, Destination: staged, Status: types.OperationStatusCompleted})
+	tm.Complete(txn)
+	return txn.ID, staging
+}
+
+func TestIgnoreMarker_StagingCleanup(t *testing.T) {
+	t.Run("merge", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		tm, _ := NewTransactionManager(filepath.Join(tmpDir, "txn"))
+		library := filepath.Join(tmpDir, "movies")
+		txnID, staging := stageMovie(t, tm, library, filepath.Join(tmpDir, "movie.mkv"))
+
+		if _, _, err := tm.MergeStaging(txnID); err != nil {
+			t.Fatalf("MergeStaging() error = %v", err)
+		}
+		if _, err := os.Stat(staging); !os.IsNotExist(err) {
+			t.Errorf("merged staging directory should be removed with its marker, stat err = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(library, IgnoreMarker)); !os.IsNotExist(err) {
+			t.Errorf("marker must not be merged into the library, stat err = %v", err)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		tm, _ := NewTransactionManager(filepath.Join(tmpDir, "txn"))
+		library := filepath.Join(tmpDir, "movies")
+		os.MkdirAll(filepath.Join(library, "Kept (2001)"), 0755)
+		source := filepath.Join(tmpDir, "movie.mkv")
+		txnID, staging := stageMovie(t, tm, library, source)
+
+		if err := tm.Rollback(txnID); err != nil {
+			t.Fatalf("Rollback() error = %v", err)
+		}
+		if _, err := os.Stat(source); err != nil {
+			t.Errorf("rollback did not restore the file: %v", err)
+		}
+		if _, err := os.Stat(staging); !os.IsNotExist(err) {
+			t.Errorf("rolled back staging directory should be removed with its marker, stat err = %v", err)
+		}
+	})
+}
@@ -1,39 +1,86 @@
 package safety
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/opd-ai/go-jf-org/internal/fsys"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
-// Rollback reverses a completed or failed transaction
-func (tm *TransactionManager) Rollback(txnID string) error {
+// RollbackAction is what a rollback step does to reverse an operation
+type RollbackAction string
+
+const (
+	// RollbackMoveBack moves a file from its destination back to its source
+	RollbackMoveBack RollbackAction = "move"
+	// RollbackDeleteFile deletes a file the transaction created
+	RollbackDeleteFile RollbackAction = "delete"
+	// RollbackRemoveDir removes a directory the transaction created, if empty
+	RollbackRemoveDir RollbackAction = "remove-dir"
+	// RollbackCopyBack gives a hardlinked file its own copy of the data again
+	RollbackCopyBack RollbackAction = "copy"
+	// RollbackRestoreFile puts a file the transaction overwrote back from its
+	// backup copy
+	RollbackRestoreFile RollbackAction = "restore"
+)
+
+// RollbackStep is one reverse operation of a planned rollback
+type RollbackStep struct {
+	Action RollbackAction
+	// Operation is the transaction operation being reversed
+	Operation types.Operation
+	// Warning explains why the step will fail or be skipped, if it will
+	Warning string
+}
+
+// From returns the path the step acts on, or the backup copy a restore
+// reads from
+func (s RollbackStep) From() string {
+	if s.Action == RollbackRestoreFile {
+		return s.Operation.Source
+	}
+	return s.Operation.Destination
+}
+
+// To returns where a moved or restored file goes back to, or "" for removals
+func (s RollbackStep) To() string {
+	switch s.Action {
+	case RollbackMoveBack:
+		return s.Operation.Source
+	case RollbackRestoreFile:
+		return s.Operation.Destination
+	}
+	return ""
+}
+
+// PlanRollback loads a transaction and returns the steps that would reverse
+// it, in the order they run, without changing anything on disk. Steps that
+// cannot be performed as things stand carry a warning.
+func (tm *TransactionManager) PlanRollback(txnID string) (*Transaction, []RollbackStep, error) {
 	// Load the transaction
 	txn, err := tm.Load(txnID)
 	if err != nil {
-		return fmt.Errorf("failed to load transaction: %w", err)
+		return nil, nil, fmt.Errorf("failed to load transaction: %w", err)
 	}
 
 	// Check if already rolled back
 	if txn.Status == TransactionStatusRolledBack {
-		return fmt.Errorf("transaction %s has already been rolled back", txnID)
+		return nil, nil, fmt.Errorf("transaction %s has already been rolled back", txnID)
 	}
 
 	// Can only rollback completed or failed transactions
 	if txn.Status != TransactionStatusCompleted && txn.Status != TransactionStatusFailed {
-		return fmt.Errorf("cannot rollback transaction in status %s", txn.Status)
+		return nil, nil, fmt.Errorf("cannot rollback transaction in status %s", txn.Status)
 	}
 
-	log.Info().Str("transaction", txnID).Int("operations", len(txn.Operations)).Msg("Starting rollback")
-
 	// Reverse operations in reverse order
-	var rollbackErrors []error
-	successCount := 0
-
+	steps := make([]RollbackStep, 0, len(txn.Operations))
 	for i := len(txn.Operations) - 1; i >= 0; i-- {
 		op := txn.Operations[i]
 
@@ -47,6 +94,58 @@ func (tm *TransactionManager) Rollback(txnID string) error {
 			continue
 		}
 
+		step := RollbackStep{Operation: op}
+		switch op.Type {
+		case types.OperationMove, types.OperationRename:
+			step.Action = RollbackMoveBack
+			if err := tm.moveReversible(op); err != nil {
+				step.Warning = err.Error()
+			}
+		case types.OperationCreateFile:
+			step.Action = RollbackDeleteFile
+			if _, err := tm.files().Stat(op.Destination); os.IsNotExist(err) {
+				step.Warning = "file already removed, nothing to do"
+			}
+		case types.OperationCreateDir:
+			step.Action = RollbackRemoveDir
+			if entries, err := tm.files().ReadDir(op.Destination); err == nil && len(entries) > 0 {
+				step.Warning = "directory not empty, it will be kept"
+			}
+		case types.OperationHardlink:
+			step.Action = RollbackCopyBack
+			if err := linkReversible(op); err != nil {
+				step.Warning = err.Error()
+			}
+		case types.OperationReplaceFile:
+			step.Action = RollbackRestoreFile
+			if op.Source == "" {
+				step.Warning = "no backup copy was kept, the file will be left as it is"
+			} else if _, err := tm.files().Stat(op.Source); os.IsNotExist(err) {
+				step.Warning = fmt.Sprintf("backup copy no longer exists: %s", op.Source)
+			}
+		default:
+			step.Warning = fmt.Sprintf("unknown operation type: %s", op.Type)
+		}
+		steps = append(steps, step)
+	}
+
+	return txn, steps, nil
+}
+
+// Rollback reverses a completed or failed transaction
+func (tm *TransactionManager) Rollback(txnID string) error {
+	txn, steps, err := tm.PlanRollback(txnID)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("transaction", txnID).Int("operations", len(txn.Operations)).Msg("Starting rollback")
+
+	var rollbackErrors []error
+	successCount := 0
+
+	for _, step := range steps {
+		op := step.Operation
 		if err := tm.rollbackOperation(op); err != nil {
 			log.Error().
 				Err(err).
@@ -90,6 +189,10 @@ func (tm *TransactionManager) rollbackOperation(op types.Operation) error {
 		return tm.rollbackCreateDir(op)
 	case types.OperationCreateFile:
 		return tm.rollbackCreateFile(op)
+	case types.OperationHardlink:
+		return tm.rollbackHardlink(op)
+	case types.OperationReplaceFile:
+		return tm.rollbackReplaceFile(op)
 	default:
 		return fmt.Errorf("unknown operation type: %s", op.Type)
 	}
@@ -103,29 +206,32 @@ func (tm *TransactionManager) rollbackMove(op types.Operation) error {
 		Str("to", op.Source).
 		Msg("Rolling back move operation")
 
-	// Check if destination still exists
-	if _, err := os.Stat(op.Destination); os.IsNotExist(err) {
-		return fmt.Errorf("destination file no longer exists: %s", op.Destination)
+	if err := tm.moveReversible(op); err != nil {
+		return err
 	}
 
-	// Check if source location is available (not recreated)
-	if _, err := os.Stat(op.Source); err == nil {
-		return fmt.Errorf("source location already occupied: %s", op.Source)
+	// A file lost from its destination comes back from its backup copy
+	from := op.Destination
+	if _, err := tm.files().Stat(from); os.IsNotExist(err) {
+		from = op.Backup
+		log.Info().Str("file", op.Destination).Str("backup", op.Backup).Msg("Destination file is gone, restoring from backup")
 	}
 
 	// Ensure source directory exists
 	sourceDir := filepath.Dir(op.Source)
-	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+	if err := tm.files().MkdirAll(sourceDir, 0755); err != nil {
 		return fmt.Errorf("failed to create source directory: %w", err)
 	}
 
 	// Move file back
-	if err := os.Rename(op.Destination, op.Source); err != nil {
+	copier := NewCopier(DefaultCopyBufferSize)
+	copier.SetFileSystem(tm.fs)
+	if err := copier.Move(from, op.Source); err != nil {
 		return fmt.Errorf("failed to move file back: %w", err)
 	}
 
 	log.Info().
-		Str("from", op.Destination).
+		Str("from", from).
 		Str("to", op.Source).
 		Msg("File moved back successfully")
 
@@ -136,6 +242,25 @@ func (tm *TransactionManager) rollbackMove(op types.Operation) error {
 	return nil
 }
 
+// moveReversible returns why a move cannot be reversed, or nil when it can
+func (tm *TransactionManager) moveReversible(op types.Operation) error {
+	// Check if destination still exists, or else a backup to restore from
+	if _, err := tm.files().Stat(op.Destination); os.IsNotExist(err) {
+		if op.Backup == "" {
+			return fmt.Errorf("destination file no longer exists: %s", op.Destination)
+		}
+		if _, err := tm.files().Stat(op.Backup); err != nil {
+			return fmt.Errorf("destination file and its backup no longer exist: %s", op.Destination)
+		}
+	}
+
+	// Check if source location is available (not recreated)
+	if _, err := tm.files().Stat(op.Source); err == nil {
+		return fmt.Errorf("source location already occupied: %s", op.Source)
+	}
+	return nil
+}
+
 // rollbackRename reverses a file rename operation
 func (tm *TransactionManager) rollbackRename(op types.Operation) error {
 	// Rename is essentially the same as move for rollback purposes
@@ -147,7 +272,7 @@ func (tm *TransactionManager) rollbackCreateDir(op types.Operation) error {
 	log.Debug().Str("dir", op.Destination).Msg("Rolling back directory creation")
 
 	// Check if directory exists
-	info, err := os.Stat(op.Destination)
+	info, err := tm.files().Stat(op.Destination)
 	if os.IsNotExist(err) {
 		// Directory already gone, nothing to do
 		log.Debug().Str("dir", op.Destination).Msg("Directory already removed")
@@ -162,7 +287,7 @@ func (tm *TransactionManager) rollbackCreateDir(op types.Operation) error {
 	}
 
 	// Only remove if empty
-	entries, err := os.ReadDir(op.Destination)
+	entries, err := tm.files().ReadDir(op.Destination)
 	if err != nil {
 		return fmt.Errorf("failed to read directory: %w", err)
 	}
@@ -176,7 +301,7 @@ func (tm *TransactionManager) rollbackCreateDir(op types.Operation) error {
 	}
 
 	// Remove empty directory
-	if err := os.Remove(op.Destination); err != nil {
+	if err := tm.files().Remove(op.Destination); err != nil {
 		return fmt.Errorf("failed to remove directory: %w", err)
 	}
 
@@ -189,14 +314,14 @@ func (tm *TransactionManager) rollbackCreateFile(op types.Operation) error {
 	log.Debug().Str("file", op.Destination).Msg("Rolling back file creation")
 
 	// Check if file exists
-	if _, err := os.Stat(op.Destination); os.IsNotExist(err) {
+	if _, err := tm.files().Stat(op.Destination); os.IsNotExist(err) {
 		// File already gone, nothing to do
 		log.Debug().Str("file", op.Destination).Msg("File already removed")
 		return nil
 	}
 
 	// Remove the file
-	if err := os.Remove(op.Destination); err != nil {
+	if err := tm.files().Remove(op.Destination); err != nil {
 		return fmt.Errorf("failed to remove file: %w", err)
 	}
 
@@ -204,6 +329,52 @@ func (tm *TransactionManager) rollbackCreateFile(op types.Operation) error {
 	return nil
 }
 
+// rollbackReplaceFile restores a file the transaction overwrote from the
+// backup copy made before it was replaced
+func (tm *TransactionManager) rollbackReplaceFile(op types.Operation) error {
+	log.Debug().Str("file", op.Destination).Str("backup", op.Source).Msg("Rolling back file replacement")
+
+	if op.Source == "" {
+		return fmt.Errorf("no backup of %s was kept", op.Destination)
+	}
+	data, err := fsys.ReadFile(tm.files(), op.Source)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if err := tm.files().WriteFile(op.Destination, data, 0644); err != nil {
+		return fmt.Errorf("failed to restore file: %w", err)
+	}
+	if err := tm.files().Remove(op.Source); err != nil {
+		log.Warn().Err(err).Str("backup", op.Source).Msg("Failed to remove restored backup")
+	}
+
+	log.Info().Str("file", op.Destination).Msg("File restored from backup")
+	return nil
+}
+
+// rollbackHardlink breaks a hardlink made by dedupe by copying the shared
+// data over the linked path
+func (tm *TransactionManager) rollbackHardlink(op types.Operation) error {
+	log.Debug().Str("file", op.Destination).Msg("Rolling back hardlink")
+
+	if err := linkReversible(op); err != nil {
+		if errors.Is(err, errNotLinked) {
+			log.Debug().Str("file", op.Destination).Msg("File no longer linked")
+			return nil
+		}
+		return err
+	}
+
+	// Copy streams into a temporary file and renames it over the path, so
+	// copying a file onto itself leaves it with a fresh inode
+	if err := NewCopier(DefaultCopyBufferSize).Copy(op.Destination, op.Destination); err != nil {
+		return fmt.Errorf("failed to re-copy linked file: %w", err)
+	}
+
+	log.Info().Str("file", op.Destination).Msg("Hardlink replaced with a copy")
+	return nil
+}
+
 // tryRemoveEmptyDir attempts to remove a directory if it's empty, doesn't error if not empty
 func (tm *TransactionManager) tryRemoveEmptyDir(dir string) {
 	// Convert to absolute path for safety checks
@@ -219,12 +390,21 @@ func (tm *TransactionManager) tryRemoveEmptyDir(dir string) {
 		return
 	}
 
-	entries, err := os.ReadDir(absDir)
-	if err != nil || len(entries) > 0 {
+	entries, err := tm.files().ReadDir(absDir)
+	if err != nil {
+		return
+	}
+	// A staging directory emptied by the rollback still holds its marker
+	if len(entries) == 1 && entries[0].Name() == IgnoreMarker && strings.HasPrefix(filepath.Base(absDir), StagingDirPrefix) {
+		if removed, _ := removeIgnoreMarker(tm.files(), absDir); removed {
+			entries = nil
+		}
+	}
+	if len(entries) > 0 {
 		return
 	}
 
-	if err := os.Remove(absDir); err != nil {
+	if err := tm.files().Remove(absDir); err != nil {
 		log.Debug().Err(err).Str("dir", absDir).Msg("Could not remove directory")
 		return
 	}
@@ -435,3 +435,76 @@ func TestTryRemoveEmptyDirWithFiles(t *testing.T) {
 		t.Error("Directory with files was incorrectly removed")
 	}
 }
+
+func TestPlanRollback(t *testing.T) {
+	tmpDir := t.TempDir()
+	tm, _ := NewTransactionManager(filepath.Join(tmpDir, "txn"))
+
+	movedSource := filepath.Join(tmpDir, "source", "movie.mkv")
+	movedDest := filepath.Join(tmpDir, "dest", "Movie (2023)", "Movie (2023).mkv")
+	missingDest := filepath.Join(tmpDir, "dest", "gone.mkv")
+	occupiedSource := filepath.Join(tmpDir, "source", "occupied.mkv")
+	occupiedDest := filepath.Join(tmpDir, "dest", "occupied.mkv")
+	nfo := filepath.Join(tmpDir, "dest", "Movie (2023)", "movie.nfo")
+	for _, path := range []string{movedDest, occupiedSource, occupiedDest, nfo} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txn, _ := tm.Begin()
+	ops := []types.Operation{
+		{Type: types.OperationMove, Source: movedSource, Destination: movedDest, Status: types.OperationStatusCompleted},
+		{Type: types.OperationMove, Source: filepath.Join(tmpDir, "source", "gone.mkv"), Destination: missingDest, Status: types.OperationStatusCompleted},
+		{Type: types.OperationRename, Source: occupiedSource, Destination: occupiedDest, Status: types.OperationStatusCompleted},
+		{Type: types.OperationCreateFile, Destination: nfo, Status: types.OperationStatusCompleted},
+		{Type: types.OperationMove, Source: "pending", Destination: "pending", Status: types.OperationStatusPending},
+	}
+	for _, op := range ops {
+		tm.AddOperation(txn, op)
+	}
+	tm.Complete(txn)
+
+	_, steps, err := tm.PlanRollback(txn.ID)
+	if err != nil {
+		t.Fatalf("PlanRollback() error = %v", err)
+	}
+
+	want := []struct {
+		action  RollbackAction
+		from    string
+		to      string
+		warning bool
+	}{
+		{RollbackDeleteFile, nfo, "", false},
+		{RollbackMoveBack, occupiedDest, occupiedSource, true},
+		{RollbackMoveBack, missingDest, filepath.Join(tmpDir, "source", "gone.mkv"), true},
+		{RollbackMoveBack, movedDest, movedSource, false},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("PlanRollback() returned %d steps, want %d: %+v", len(steps), len(want), steps)
+	}
+	for i, w := range want {
+		s := steps[i]
+		if s.Action != w.action || s.From() != w.from || s.To() != w.to || (s.Warning != "") != w.warning {
+			t.Errorf("step %d = {%s %s -> %s warning %q}, want {%s %s -> %s warning %v}",
+				i, s.Action, s.From(), s.To(), s.Warning, w.action, w.from, w.to, w.warning)
+		}
+	}
+
+	// Planning leaves every file and the transaction untouched
+	for _, path := range []string{movedDest, occupiedSource, occupiedDest, nfo} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should still exist after planning: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(movedSource); !os.IsNotExist(err) {
+		t.Errorf("%s should not be restored by planning", movedSource)
+	}
+	if loaded, _ := tm.Load(txn.ID); loaded.Status != TransactionStatusCompleted {
+		t.Errorf("transaction status = %s after planning, want %s", loaded.Status, TransactionStatusCompleted)
+	}
+}
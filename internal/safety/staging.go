@@ -0,0 +1,148 @@
+package safety
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// StagingDirPrefix names the hidden directory organize --stage writes into
+const StagingDirPrefix = ".staging-"
+
+// StagingDir returns the timestamped staging directory under destRoot, e.g.
+// "/media/movies/.staging-20231015-142530"
+func StagingDir(destRoot string, t time.Time) string {
+	return filepath.Join(destRoot, StagingDirPrefix+t.Format("20060102-150405"))
+}
+
+// UnstagedPath removes the staging directory component from a staged path,
+// returning the path the file should occupy in the library and the staging
+// root it was found under. ok is false if the path is not staged.
+func UnstagedPath(path string) (final, stagingRoot string, ok bool) {
+	parts := strings.Split(filepath.Clean(path), string(filepath.Separator))
+	for i, part := range parts {
+		if !strings.HasPrefix(part, StagingDirPrefix) {
+			continue
+		}
+		rest := append(append([]string{}, parts[:i]...), parts[i+1:]...)
+		stagingRoot = strings.Join(parts[:i+1], string(filepath.Separator))
+		final = strings.Join(rest, string(filepath.Separator))
+		return final, stagingRoot, true
+	}
+	return "", "", false
+}
+
+// MergeStaging moves every staged file recorded in a transaction out of its
+// staging directory into the matching library path. The moves are recorded
+// in a new transaction, whose ID is returned, so the merge can itself be
+// rolled back. Files whose library path is already occupied are left staged
+// and reported as failed operations.
+func (tm *TransactionManager) MergeStaging(txnID string) (string, []types.Operation, error) {
+	txn, err := tm.Load(txnID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load transaction: %w", err)
+	}
+	if txn.Status != TransactionStatusCompleted {
+		return "", nil, fmt.Errorf("cannot merge staging for transaction in status %s", txn.Status)
+	}
+
+	merge, err := tm.Begin()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var ops []types.Operation
+	stagingRoots := make(map[string]bool)
+
+	for _, staged := range txn.Operations {
+		if staged.Status != types.OperationStatusCompleted || staged.Type == types.OperationCreateDir {
+			continue
+		}
+		final, root, ok := UnstagedPath(staged.Destination)
+		if !ok {
+			continue
+		}
+		stagingRoots[root] = true
+
+		op := types.Operation{
+			Type:          types.OperationMove,
+			Source:        staged.Destination,
+			Destination:   final,
+			Status:        types.OperationStatusCompleted,
+			Hash:          staged.Hash,
+			HashAlgorithm: staged.HashAlgorithm,
+		}
+
+		mergeErr := mergeFile(tm.files(), staged.Destination, final)
+		if mergeErr != nil {
+			log.Error().Err(mergeErr).Str("source", staged.Destination).Msg("Failed to merge staged file")
+			op.Status = types.OperationStatusFailed
+		}
+
+		// AddOperation leaves the error out of the log; the returned
+		// operation carries it
+		if err := tm.AddOperation(merge, op); err != nil {
+			return merge.ID, ops, err
+		}
+		op.Error = mergeErr
+		ops = append(ops, op)
+	}
+
+	for root := range stagingRoots {
+		if _, err := removeIgnoreMarker(tm.files(), root); err != nil {
+			log.Warn().Err(err).Msg("Failed to remove staging marker")
+		}
+		removeEmptyTree(tm.files(), root)
+	}
+
+	if err := tm.Complete(merge); err != nil {
+		return merge.ID, ops, err
+	}
+	return merge.ID, ops, nil
+}
+
+// mergeFile moves a staged file to its library path within f without
+// overwriting
+func mergeFile(f fsys.FileSystem, staged, final string) error {
+	if _, err := f.Stat(staged); err != nil {
+		return fmt.Errorf("staged file missing: %w", err)
+	}
+	if _, err := fsys.Lstat(f, final); err == nil {
+		return fmt.Errorf("destination already exists: %s", final)
+	}
+	if err := f.MkdirAll(filepath.Dir(final), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	if err := f.Rename(staged, final); err != nil {
+		return fmt.Errorf("failed to move staged file: %w", err)
+	}
+	return nil
+}
+
+// removeEmptyTree removes root and any directories beneath it in f that are
+// empty, deepest first, leaving directories that still hold files
+func removeEmptyTree(f fsys.FileSystem, root string) {
+	var dirs []string
+	fsys.WalkDir(f, root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+
+	// Longer paths are deeper; remove children before parents
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+	for _, dir := range dirs {
+		if err := f.Remove(dir); err == nil {
+			log.Debug().Str("dir", dir).Msg("Removed empty staging directory")
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package safety
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestStagingDir(t *testing.T) {
+	ts := time.Date(2023, 10, 15, 14, 25, 30, 0, time.UTC)
+	got := StagingDir("/media/movies", ts)
+	want := filepath.Join("/media/movies", ".staging-20231015-142530")
+	if got != want {
+		t.Errorf("StagingDir() = %q, want %q", got, want)
+	}
+}
+
+func TestUnstagedPath(t *testing.T) {
+	staging := filepath.Join("/media/movies", ".staging-20231015-142530")
+
+	tests := []struct {
+		name      string
+		path      string
+		wantFinal string
+		wantOK    bool
+	}{
+		{
+			name:      "staged file",
+			path:      filepath.Join(staging, "Movie (2020)", "Movie (2020).mkv"),
+			wantFinal: filepath.Join("/media/movies", "Movie (2020)", "Movie (2020).mkv"),
+			wantOK:    true,
+		},
+		{
+			name:   "not staged",
+			path:   filepath.Join("/media/movies", "Movie (2020)", "Movie (2020).mkv"),
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			final, root, ok := UnstagedPath(tt.path)
+			if ok != tt.wantOK {
+				t.Fatalf("UnstagedPath() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if final != tt.wantFinal {
+				t.Errorf("final = %q, want %q", final, tt.wantFinal)
+			}
+			if root != staging {
+				t.Errorf("stagingRoot = %q, want %q", root, staging)
+			}
+		})
+	}
+}
+
+func TestMergeStaging(t *testing.T) {
+	tmpDir := t.TempDir()
+	tm, err := NewTransactionManager(filepath.Join(tmpDir, "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	library := filepath.Join(tmpDir, "movies")
+	staging := StagingDir(library, time.Now())
+
+	stagedMovie := filepath.Join(staging, "Movie (2020)", "Movie (2020).mkv")
+	stagedNFO := filepath.Join(staging, "Movie (2020)", "movie.nfo")
+	stagedTaken := filepath.Join(staging, "Taken (2008)", "Taken (2008).mkv")
+	for _, path := range []string{stagedMovie, stagedNFO, stagedTaken} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The library already holds this title; its staged copy must stay put
+	existing := filepath.Join(library, "Taken (2008)", "Taken (2008).mkv")
+	if err := os.MkdirAll(filepath.Dir(existing), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(existing, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	txn, _ := tm.Begin()
+	for _, op := range []types.Operation{
+		{Type: types.OperationMove, Source: "/src/movie.mkv", Destination: stagedMovie, Status: types.OperationStatusCompleted},
+		{Type: types.OperationCreateFile, Destination: stagedNFO, Status: types.OperationStatusCompleted},
+		{Type: types.OperationMove, Source: "/src/taken.mkv", Destination: stagedTaken, Status: types.OperationStatusCompleted},
+	} {
+		tm.AddOperation(txn, op)
+	}
+	tm.Complete(txn)
+
+	mergeID, ops, err := tm.MergeStaging(txn.ID)
+	if err != nil {
+		t.Fatalf("MergeStaging() error = %v", err)
+	}
+	if len(ops) != 3 {
+		t.Fatalf("MergeStaging() returned %d operations, want 3", len(ops))
+	}
+
+	for _, path := range []string{
+		filepath.Join(library, "Movie (2020)", "Movie (2020).mkv"),
+		filepath.Join(library, "Movie (2020)", "movie.nfo"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("merged file missing: %v", err)
+		}
+	}
+	if _, err := os.Stat(stagedTaken); err != nil {
+		t.Errorf("conflicting staged file should be left in place: %v", err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "original" {
+		t.Errorf("existing library file was overwritten")
+	}
+	if _, err := os.Stat(filepath.Join(staging, "Movie (2020)")); !os.IsNotExist(err) {
+		t.Errorf("emptied staging directory should be removed, stat err = %v", err)
+	}
+
+	failed := 0
+	for _, op := range ops {
+		if op.Status == types.OperationStatusFailed {
+			failed++
+			if !strings.HasPrefix(op.Source, staging) {
+				t.Errorf("failed op source = %q, want staged path", op.Source)
+			}
+		}
+	}
+	if failed != 1 {
+		t.Errorf("failed operations = %d, want 1", failed)
+	}
+
+	// The merge is itself a transaction and can be undone
+	if err := tm.Rollback(mergeID); err != nil {
+		t.Fatalf("Rollback(merge) error = %v", err)
+	}
+	if _, err := os.Stat(stagedMovie); err != nil {
+		t.Errorf("rollback did not restore staged file: %v", err)
+	}
+}
+
+func TestMergeStaging_RequiresCompleted(t *testing.T) {
+	tm, err := NewTransactionManager(filepath.Join(t.TempDir(), "txn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, _ := tm.Begin()
+
+	if _, _, err := tm.MergeStaging(txn.ID); err == nil {
+		t.Error("MergeStaging() on a pending transaction should fail")
+	}
+}
+
+func TestMergeStaging_FileSystem(t *testing.T) {
+	m := fsys.NewMemFS()
+	root := string(filepath.Separator)
+	tm, err := NewTransactionManagerWithFS(filepath.Join(root, "txn"), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	library := filepath.Join(root, "movies")
+	staging := StagingDir(library, time.Now())
+	staged := filepath.Join(staging, "Movie (2020)", "Movie (2020).mkv")
+	if err := m.MkdirAll(filepath.Dir(staged), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile(staged, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writeIgnoreMarker(m, staging); err != nil {
+		t.Fatal(err)
+	}
+
+	txn, _ := tm.Begin()
+	tm.AddOperation(txn, types.Operation{Type: types.OperationMove, Source: "/src/movie.mkv", Destination: staged, Status: types.OperationStatusCompleted})
+	tm.Complete(txn)
+
+	if _, _, err := tm.MergeStaging(txn.ID); err != nil {
+		t.Fatalf("MergeStaging() error = %v", err)
+	}
+	if _, err := m.Stat(filepath.Join(library, "Movie (2020)", "Movie (2020).mkv")); err != nil {
+		t.Errorf("merged file missing: %v", err)
+	}
+	if _, err := m.Stat(staging); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("staging directory should be removed with its marker, stat err = %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package safety
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Throttle caps file moves per second and copied bytes per second so
+// organizing a large batch does not saturate a slow disk. Each limit is a
+// token bucket holding one second's worth; a zero limit is unlimited. A nil
+// Throttle never blocks.
+type Throttle struct {
+	ops   *bucket
+	bytes *bucket
+}
+
+// NewThrottle creates a Throttle allowing opsPerSec operations and
+// bytesPerSec bytes per second; zero or less leaves that limit off
+func NewThrottle(opsPerSec int, bytesPerSec int64) *Throttle {
+	return &Throttle{
+		ops:   newBucket(float64(opsPerSec)),
+		bytes: newBucket(float64(bytesPerSec)),
+	}
+}
+
+// WaitOp blocks until another operation may start
+func (t *Throttle) WaitOp() {
+	if t != nil {
+		t.ops.take(1)
+	}
+}
+
+// WaitBytes blocks until n more bytes may be written
+func (t *Throttle) WaitBytes(n int) {
+	if t != nil {
+		t.bytes.take(float64(n))
+	}
+}
+
+// bucket is a token bucket refilled continuously at rate tokens per second
+// up to one second's worth
+type bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// newBucket returns a full bucket, or nil (unlimited) for a non-positive rate
+func newBucket(rate float64) *bucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &bucket{rate: rate, tokens: rate, last: time.Now()}
+}
+
+// take consumes n tokens, sleeping until they have accumulated. A request
+// larger than what is available goes into debt that later callers wait
+// out too, so the average rate holds under concurrency and for requests
+// bigger than the bucket.
+func (b *bucket) take(n float64) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= n
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	time.Sleep(wait)
+}
@@ -0,0 +1,66 @@
+package safety
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestThrottle_CopierBytesPerSec(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "dest"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	old := renameFile
+	renameFile = func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+	defer func() { renameFile = old }()
+
+	// 96KB at 64KB/s: the first second's worth is the bucket's burst, the
+	// remaining 32KB must take at least half a second
+	const rate = 64 * 1024
+	copier := NewCopier(8 * 1024)
+	copier.SetThrottle(NewThrottle(0, rate))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		src := filepath.Join(tmpDir, "movie"+string(rune('a'+i))+".mkv")
+		if err := os.WriteFile(src, make([]byte, 32*1024), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := copier.Move(src, filepath.Join(tmpDir, "dest", filepath.Base(src))); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond {
+		t.Errorf("copied 96KB in %v, faster than the 64KB/s cap allows", elapsed)
+	}
+}
+
+func TestThrottle_OpsPerSec(t *testing.T) {
+	throttle := NewThrottle(20, 0)
+
+	// 20 operations are the burst; 5 more at 20/s take at least 250ms
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		throttle.WaitOp()
+	}
+	if elapsed := time.Since(start); elapsed < 225*time.Millisecond {
+		t.Errorf("25 operations took %v, faster than 20/s allows", elapsed)
+	}
+
+	// Unlimited and nil throttles never block
+	start = time.Now()
+	var none *Throttle
+	for i := 0; i < 1000; i++ {
+		NewThrottle(0, 0).WaitBytes(1 << 30)
+		none.WaitOp()
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("unlimited throttle blocked for %v", elapsed)
+	}
+}
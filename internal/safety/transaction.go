@@ -7,8 +7,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
@@ -20,6 +24,26 @@ type Transaction struct {
 	Status     TransactionStatus `json:"status"`
 	Completed  time.Time         `json:"completed,omitempty"`
 	Error      string            `json:"error,omitempty"`
+	// ArtworkRetries are artwork downloads that failed and can be retried
+	// with 'transactions retry-artwork'
+	ArtworkRetries []ArtworkRetry `json:"artwork_retries,omitempty"`
+}
+
+// ArtworkRetry is a failed artwork download, recorded so it can be repeated
+// later without organizing the files again
+type ArtworkRetry struct {
+	// Kind names the download, which decides how Source is fetched
+	Kind string `json:"kind"`
+	// Source is the image URL, TMDB image path or MusicBrainz release ID
+	Source string `json:"source"`
+	// Destination is where the artwork was to be saved
+	Destination string `json:"destination"`
+	// Size is the artwork size preference the download used
+	Size string `json:"size,omitempty"`
+	// Error is why the last attempt failed
+	Error string `json:"error,omitempty"`
+	// Attempts counts the failed attempts, the one during organize included
+	Attempts int `json:"attempts"`
 }
 
 // TransactionStatus represents the status of a transaction
@@ -39,20 +63,68 @@ const (
 // TransactionManager handles transaction logging and retrieval
 type TransactionManager struct {
 	logDir string
+	// fs holds the logs and the files rollback restores; nil is the local disk
+	fs fsys.FileSystem
+	// writeFile persists a log; replaceable in tests to simulate disk failures
+	writeFile func(path string, data []byte, perm os.FileMode) error
+	// degraded is set once a mid-run write fails; logs are then kept in memory
+	// until the transaction finishes
+	degraded bool
+	// fallbackPath is where the last transaction was flushed when the log
+	// directory could not be written
+	fallbackPath string
+	// batchSize and batchInterval bound how long progress goes unwritten;
+	// see SetCommitBatch
+	batchSize     int
+	batchInterval time.Duration
+	// unsaved counts the progress updates since the log was last written
+	// at lastSaved; batchMu guards both, as updates may come from several
+	// goroutines
+	batchMu   sync.Mutex
+	unsaved   int
+	lastSaved time.Time
 }
 
 // NewTransactionManager creates a new transaction manager
 func NewTransactionManager(logDir string) (*TransactionManager, error) {
+	return NewTransactionManagerWithFS(logDir, fsys.OSFileSystem{})
+}
+
+// NewTransactionManagerWithFS creates a transaction manager that keeps its
+// logs on f and rolls back files there, e.g. an fsys.MemFS in tests
+func NewTransactionManagerWithFS(logDir string, f fsys.FileSystem) (*TransactionManager, error) {
 	// Create log directory if it doesn't exist
-	if err := os.MkdirAll(logDir, 0755); err != nil {
+	if err := f.MkdirAll(logDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create transaction log directory: %w", err)
 	}
 
 	return &TransactionManager{
-		logDir: logDir,
+		logDir:    logDir,
+		fs:        f,
+		writeFile: f.WriteFile,
 	}, nil
 }
 
+// SetCommitBatch makes progress updates (AddOperation, UpdateOperation and
+// AddArtworkRetry) write the log once size of them have been made, or on
+// the first one made interval or more after the last write, instead of on
+// every update, which rewrites the whole log each time. Either may be zero
+// to disable it; both zero, or a size of 1, writes every update, the
+// default. Begin, Complete and Fail always write, so only a crash loses
+// the updates of the current batch, and rollback then misses those moves.
+func (tm *TransactionManager) SetCommitBatch(size int, interval time.Duration) {
+	tm.batchSize = size
+	tm.batchInterval = interval
+}
+
+// files returns the file system the manager works on
+func (tm *TransactionManager) files() fsys.FileSystem {
+	if tm.fs == nil {
+		return fsys.OSFileSystem{}
+	}
+	return tm.fs
+}
+
 // generateID generates a random transaction ID
 func generateID() string {
 	bytes := make([]byte, 8)
@@ -80,18 +152,41 @@ func (tm *TransactionManager) Begin() (*Transaction, error) {
 	return txn, nil
 }
 
-// AddOperation adds an operation to the transaction
+// AddOperation adds an operation to the transaction. The operation's Error is
+// not recorded: error values cannot be read back from the JSON log, and a
+// logged one would make the transaction impossible to load or roll back.
 func (tm *TransactionManager) AddOperation(txn *Transaction, op types.Operation) error {
+	op.Error = nil
 	txn.Operations = append(txn.Operations, op)
-	return tm.save(txn)
+	return tm.saveProgress(txn)
 }
 
-// UpdateOperation updates an existing operation in the transaction by index
+// UpdateOperation updates an existing operation in the transaction by index;
+// as with AddOperation, the operation's Error is not recorded
 func (tm *TransactionManager) UpdateOperation(txn *Transaction, index int, op types.Operation) error {
 	if index < 0 || index >= len(txn.Operations) {
 		return fmt.Errorf("invalid operation index: %d", index)
 	}
+	op.Error = nil
 	txn.Operations[index] = op
+	return tm.saveProgress(txn)
+}
+
+// AddArtworkRetry records a failed artwork download in the transaction
+func (tm *TransactionManager) AddArtworkRetry(txn *Transaction, retry ArtworkRetry) error {
+	txn.ArtworkRetries = append(txn.ArtworkRetries, retry)
+	return tm.saveProgress(txn)
+}
+
+// RecordArtworkRetries saves the outcome of retrying a transaction's
+// artwork: the downloads that now succeeded are added as operations, so
+// rollback removes them with the rest, and failing replaces the retry list
+func (tm *TransactionManager) RecordArtworkRetries(txn *Transaction, downloaded []types.Operation, failing []ArtworkRetry) error {
+	for _, op := range downloaded {
+		op.Error = nil
+		txn.Operations = append(txn.Operations, op)
+	}
+	txn.ArtworkRetries = failing
 	return tm.save(txn)
 }
 
@@ -99,7 +194,7 @@ func (tm *TransactionManager) UpdateOperation(txn *Transaction, index int, op ty
 func (tm *TransactionManager) Complete(txn *Transaction) error {
 	txn.Status = TransactionStatusCompleted
 	txn.Completed = time.Now()
-	return tm.save(txn)
+	return tm.flush(txn)
 }
 
 // Fail marks a transaction as failed
@@ -109,7 +204,92 @@ func (tm *TransactionManager) Fail(txn *Transaction, err error) error {
 	if err != nil {
 		txn.Error = err.Error()
 	}
-	return tm.save(txn)
+	return tm.flush(txn)
+}
+
+// LogDir returns the directory transaction logs are written to
+func (tm *TransactionManager) LogDir() string {
+	return tm.logDir
+}
+
+// Degraded reports whether a write to the log directory failed during the
+// current run, so the transaction was kept in memory
+func (tm *TransactionManager) Degraded() bool {
+	return tm.degraded
+}
+
+// FallbackPath returns where the transaction log was written when the log
+// directory was unavailable at the end of the run, or "" if it was not needed
+func (tm *TransactionManager) FallbackPath() string {
+	return tm.fallbackPath
+}
+
+// saveProgress persists an in-flight transaction. If the log directory
+// becomes unwritable, it warns once and keeps the transaction in memory so
+// the run can continue; flush writes the full record at the end.
+func (tm *TransactionManager) saveProgress(txn *Transaction) error {
+	if tm.degraded {
+		return nil
+	}
+	tm.batchMu.Lock()
+	tm.unsaved++
+	due := tm.batchDue()
+	tm.batchMu.Unlock()
+	if !due {
+		return nil
+	}
+	if err := tm.save(txn); err != nil {
+		tm.degraded = true
+		log.Error().
+			Err(err).
+			Str("transaction", txn.ID).
+			Str("log_dir", tm.logDir).
+			Msg("Transaction log directory unavailable; continuing with the log in memory and will retry when the run finishes")
+		return nil
+	}
+	return nil
+}
+
+// batchDue reports whether the progress updates held back so far should
+// be written now; the caller holds batchMu
+func (tm *TransactionManager) batchDue() bool {
+	if tm.batchSize <= 0 && tm.batchInterval <= 0 {
+		return true
+	}
+	if tm.batchSize > 0 && tm.unsaved >= tm.batchSize {
+		return true
+	}
+	return tm.batchInterval > 0 && time.Since(tm.lastSaved) >= tm.batchInterval
+}
+
+// flush writes the finished transaction to the log directory, falling back
+// to the system temp directory so a rollback record still exists
+func (tm *TransactionManager) flush(txn *Transaction) error {
+	err := tm.save(txn)
+	if err == nil {
+		if tm.degraded {
+			log.Warn().Str("transaction", txn.ID).Msg("Transaction log directory recovered; full transaction log written")
+			tm.degraded = false
+		}
+		return nil
+	}
+
+	fallbackDir := filepath.Join(os.TempDir(), "go-jf-org-txn")
+	fallback := &TransactionManager{logDir: fallbackDir, writeFile: os.WriteFile}
+	if mkErr := os.MkdirAll(fallbackDir, 0755); mkErr != nil {
+		return fmt.Errorf("%w (fallback directory also unavailable: %v)", err, mkErr)
+	}
+	if fbErr := fallback.save(txn); fbErr != nil {
+		return fmt.Errorf("%w (fallback write also failed: %v)", err, fbErr)
+	}
+
+	tm.fallbackPath = fallback.getLogPath(txn.ID)
+	log.Error().
+		Err(err).
+		Str("transaction", txn.ID).
+		Str("fallback", tm.fallbackPath).
+		Msg("Could not write transaction log; saved it to a temporary location instead. Copy it into the log directory to enable rollback")
+	return nil
 }
 
 // MarkRolledBack marks a transaction as rolled back
@@ -122,7 +302,7 @@ func (tm *TransactionManager) MarkRolledBack(txn *Transaction) error {
 func (tm *TransactionManager) Load(id string) (*Transaction, error) {
 	path := tm.getLogPath(id)
 
-	data, err := os.ReadFile(path)
+	data, err := fsys.ReadFile(tm.files(), path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("transaction %s not found", id)
@@ -140,7 +320,7 @@ func (tm *TransactionManager) Load(id string) (*Transaction, error) {
 
 // List returns all transaction IDs
 func (tm *TransactionManager) List() ([]string, error) {
-	entries, err := os.ReadDir(tm.logDir)
+	entries, err := tm.files().ReadDir(tm.logDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read transaction directory: %w", err)
 	}
@@ -168,10 +348,18 @@ func (tm *TransactionManager) save(txn *Transaction) error {
 		return fmt.Errorf("failed to marshal transaction: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	writeFile := tm.writeFile
+	if writeFile == nil {
+		writeFile = tm.files().WriteFile
+	}
+	if err := writeFile(path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write transaction log: %w", err)
 	}
 
+	tm.batchMu.Lock()
+	tm.unsaved = 0
+	tm.lastSaved = time.Now()
+	tm.batchMu.Unlock()
 	return nil
 }
 
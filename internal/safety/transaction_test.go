@@ -1,13 +1,16 @@
 package safety
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/opd-ai/go-jf-org/internal/fsys"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
@@ -97,6 +100,106 @@ func TestAddOperation(t *testing.T) {
 	}
 }
 
+func TestAddOperation_WriteFailureDegrades(t *testing.T) {
+	tmpDir := t.TempDir()
+	logDir := filepath.Join(tmpDir, "txn")
+	tm, _ := NewTransactionManager(logDir)
+
+	txn, err := tm.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+
+	// Simulate the log directory becoming unwritable mid-run
+	tm.writeFile = func(path string, data []byte, perm os.FileMode) error {
+		return fmt.Errorf("simulated write failure: %w", syscall.ENOSPC)
+	}
+
+	for i := 0; i < 3; i++ {
+		op := types.Operation{
+			Type:        types.OperationMove,
+			Source:      fmt.Sprintf("/source/file%d.mkv", i),
+			Destination: fmt.Sprintf("/dest/file%d.mkv", i),
+			Status:      types.OperationStatusPending,
+		}
+		if err := tm.AddOperation(txn, op); err != nil {
+			t.Fatalf("AddOperation should keep going in memory, got error: %v", err)
+		}
+	}
+	if err := tm.UpdateOperation(txn, 0, txn.Operations[0]); err != nil {
+		t.Fatalf("UpdateOperation should keep going in memory, got error: %v", err)
+	}
+
+	if !tm.Degraded() {
+		t.Error("expected manager to report degraded after write failure")
+	}
+	if len(txn.Operations) != 3 {
+		t.Errorf("expected 3 in-memory operations, got %d", len(txn.Operations))
+	}
+
+	// The log on disk still reflects only the state before the failure
+	loaded, err := tm.Load(txn.ID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Operations) != 0 {
+		t.Errorf("expected 0 persisted operations before flush, got %d", len(loaded.Operations))
+	}
+
+	// Completing flushes the full transaction to the fallback location
+	if err := tm.Complete(txn); err != nil {
+		t.Fatalf("Complete should fall back to a temp location, got error: %v", err)
+	}
+
+	fallback := tm.FallbackPath()
+	if fallback == "" {
+		t.Fatal("expected a fallback path after flushing with an unwritable log dir")
+	}
+	defer os.Remove(fallback)
+
+	data, err := os.ReadFile(fallback)
+	if err != nil {
+		t.Fatalf("failed to read fallback log: %v", err)
+	}
+	var flushed Transaction
+	if err := json.Unmarshal(data, &flushed); err != nil {
+		t.Fatalf("failed to parse fallback log: %v", err)
+	}
+	if flushed.ID != txn.ID || len(flushed.Operations) != 3 || flushed.Status != TransactionStatusCompleted {
+		t.Errorf("fallback log = id %s, %d ops, status %s; want id %s, 3 ops, completed",
+			flushed.ID, len(flushed.Operations), flushed.Status, txn.ID)
+	}
+}
+
+func TestComplete_RecoversAfterDegraded(t *testing.T) {
+	tmpDir := t.TempDir()
+	logDir := filepath.Join(tmpDir, "txn")
+	tm, _ := NewTransactionManager(logDir)
+	txn, _ := tm.Begin()
+
+	tm.writeFile = func(path string, data []byte, perm os.FileMode) error {
+		return fmt.Errorf("simulated write failure")
+	}
+	tm.AddOperation(txn, types.Operation{Type: types.OperationMove, Source: "/a", Destination: "/b"})
+
+	// The directory becomes writable again before the run ends
+	tm.writeFile = os.WriteFile
+	if err := tm.Complete(txn); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	if tm.Degraded() || tm.FallbackPath() != "" {
+		t.Errorf("expected primary log to be used, degraded=%v fallback=%q", tm.Degraded(), tm.FallbackPath())
+	}
+	loaded, err := tm.Load(txn.ID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Operations) != 1 {
+		t.Errorf("expected 1 persisted operation, got %d", len(loaded.Operations))
+	}
+}
+
 func TestComplete(t *testing.T) {
 	tmpDir := t.TempDir()
 	logDir := filepath.Join(tmpDir, "txn")
@@ -159,6 +262,30 @@ func TestFail(t *testing.T) {
 	}
 }
 
+func TestFail_WithFailedOperationLoads(t *testing.T) {
+	tm, _ := NewTransactionManager(filepath.Join(t.TempDir(), "txn"))
+
+	txn, _ := tm.Begin()
+	op := types.Operation{Type: types.OperationMove, Source: "/a", Destination: "/b", Status: types.OperationStatusPending}
+	tm.AddOperation(txn, op)
+
+	op.Status = types.OperationStatusFailed
+	op.Error = fmt.Errorf("failed to move file")
+	if err := tm.UpdateOperation(txn, 0, op); err != nil {
+		t.Fatalf("UpdateOperation failed: %v", err)
+	}
+	tm.Fail(txn, op.Error)
+
+	// A failed transaction must stay loadable so it can be rolled back
+	loaded, err := tm.Load(txn.ID)
+	if err != nil {
+		t.Fatalf("Load of transaction with a failed operation failed: %v", err)
+	}
+	if loaded.Operations[0].Status != types.OperationStatusFailed {
+		t.Errorf("operation status = %s, want %s", loaded.Operations[0].Status, types.OperationStatusFailed)
+	}
+}
+
 func TestMarkRolledBack(t *testing.T) {
 	tmpDir := t.TempDir()
 	logDir := filepath.Join(tmpDir, "txn")
@@ -409,3 +536,157 @@ func TestTransactionTimestamps(t *testing.T) {
 		t.Error("Completed timestamp is after expected time")
 	}
 }
+
+func TestTransactionManager_MemFS(t *testing.T) {
+	m := fsys.NewMemFS()
+	logDir := filepath.Join(string(filepath.Separator), "txn")
+	tm, err := NewTransactionManagerWithFS(logDir, m)
+	if err != nil {
+		t.Fatalf("NewTransactionManagerWithFS failed: %v", err)
+	}
+
+	source := filepath.Join(string(filepath.Separator), "downloads", "movie.mkv")
+	dest := filepath.Join(string(filepath.Separator), "movies", "Movie (2023)", "Movie (2023).mkv")
+	if err := m.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile(dest, []byte("test content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	txn, _ := tm.Begin()
+	tm.AddOperation(txn, types.Operation{
+		Type:        types.OperationMove,
+		Source:      source,
+		Destination: dest,
+		Status:      types.OperationStatusCompleted,
+	})
+	tm.Complete(txn)
+
+	ids, err := tm.List()
+	if err != nil || len(ids) != 1 || ids[0] != txn.ID {
+		t.Fatalf("List() = %v, %v; want [%s]", ids, err, txn.ID)
+	}
+	if err := tm.Rollback(txn.ID); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+
+	if _, err := m.Stat(source); err != nil {
+		t.Errorf("Source file was not restored: %v", err)
+	}
+	if _, err := m.Stat(filepath.Dir(dest)); !os.IsNotExist(err) {
+		t.Error("Empty destination directory was not removed")
+	}
+	if _, err := os.Stat(logDir); !os.IsNotExist(err) {
+		t.Error("Transaction log was written to the local disk")
+	}
+}
+
+func TestSetCommitBatch(t *testing.T) {
+	persisted := func(tm *TransactionManager, txn *Transaction) int {
+		t.Helper()
+		loaded, err := tm.Load(txn.ID)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		return len(loaded.Operations)
+	}
+	op := types.Operation{Type: types.OperationMove, Source: "/a.mkv", Destination: "/b.mkv", Status: types.OperationStatusCompleted}
+
+	t.Run("size", func(t *testing.T) {
+		tm, _ := NewTransactionManagerWithFS("/txn", fsys.NewMemFS())
+		tm.SetCommitBatch(4, 0)
+		txn, _ := tm.Begin()
+
+		for i := 0; i < 3; i++ {
+			tm.AddOperation(txn, op)
+		}
+		if got := persisted(tm, txn); got != 0 {
+			t.Errorf("after 3 of a batch of 4, persisted %d operations, want 0", got)
+		}
+		tm.UpdateOperation(txn, 0, op)
+		if got := persisted(tm, txn); got != 3 {
+			t.Errorf("after a full batch, persisted %d operations, want 3", got)
+		}
+		tm.AddOperation(txn, op)
+		if got := persisted(tm, txn); got != 3 {
+			t.Errorf("after 1 of the next batch, persisted %d operations, want 3", got)
+		}
+
+		// Completing writes the partial batch
+		tm.Complete(txn)
+		if got := persisted(tm, txn); got != 4 {
+			t.Errorf("after Complete, persisted %d operations, want 4", got)
+		}
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		tm, _ := NewTransactionManagerWithFS("/txn", fsys.NewMemFS())
+		tm.SetCommitBatch(0, 50*time.Millisecond)
+		txn, _ := tm.Begin()
+
+		tm.AddOperation(txn, op)
+		tm.AddOperation(txn, op)
+		if got := persisted(tm, txn); got != 0 {
+			t.Errorf("within the interval, persisted %d operations, want 0", got)
+		}
+		time.Sleep(60 * time.Millisecond)
+		tm.AddOperation(txn, op)
+		if got := persisted(tm, txn); got != 3 {
+			t.Errorf("after the interval, persisted %d operations, want 3", got)
+		}
+
+		tm.AddOperation(txn, op)
+		tm.Fail(txn, fmt.Errorf("stopped"))
+		if got := persisted(tm, txn); got != 4 {
+			t.Errorf("after Fail, persisted %d operations, want 4", got)
+		}
+	})
+
+	t.Run("default writes every update", func(t *testing.T) {
+		tm, _ := NewTransactionManagerWithFS("/txn", fsys.NewMemFS())
+		txn, _ := tm.Begin()
+		tm.AddOperation(txn, op)
+		if got := persisted(tm, txn); got != 1 {
+			t.Errorf("persisted %d operations, want 1", got)
+		}
+	})
+}
+
+// benchmarkPersistence logs a 1,000-operation transaction, adding each
+// operation and then marking it completed, as organize does. Writing the
+// log on every update rewrites it 2,000 times, and the cost grows with the
+// square of the operation count.
+func benchmarkPersistence(b *testing.B, batchSize int) {
+	const operations = 1000
+	for i := 0; i < b.N; i++ {
+		tm, err := NewTransactionManager(b.TempDir())
+		if err != nil {
+			b.Fatal(err)
+		}
+		tm.SetCommitBatch(batchSize, 0)
+		txn, _ := tm.Begin()
+		for j := 0; j < operations; j++ {
+			op := types.Operation{
+				Type:        types.OperationMove,
+				Source:      fmt.Sprintf("/downloads/Show.S01E%04d.mkv", j),
+				Destination: fmt.Sprintf("/media/tv/Show/Season 01/Show - S01E%04d.mkv", j),
+				Status:      types.OperationStatusPending,
+			}
+			tm.AddOperation(txn, op)
+			op.Status = types.OperationStatusCompleted
+			tm.UpdateOperation(txn, j, op)
+		}
+		if err := tm.Complete(txn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTransactionPersistence_PerOperation(b *testing.B) {
+	benchmarkPersistence(b, 1)
+}
+
+func BenchmarkTransactionPersistence_Batched(b *testing.B) {
+	benchmarkPersistence(b, 500)
+}
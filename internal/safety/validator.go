@@ -1,20 +1,23 @@
 package safety
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"strings"
 	"syscall"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/opd-ai/go-jf-org/internal/fsys"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
 // Validator performs pre-operation validation checks
 type Validator struct {
 	minFreeSpace uint64 // Minimum free space in bytes (10% buffer)
+	fs           fsys.FileSystem
 }
 
 // NewValidator creates a new validator with default settings
@@ -24,6 +27,20 @@ func NewValidator() *Validator {
 	}
 }
 
+// SetFileSystem makes the validator check paths through f instead of the
+// local disk. Free space is only checked on the local disk.
+func (v *Validator) SetFileSystem(f fsys.FileSystem) {
+	v.fs = f
+}
+
+// files returns the filesystem the validator checks
+func (v *Validator) files() fsys.FileSystem {
+	if v.fs == nil {
+		return fsys.OSFileSystem{}
+	}
+	return v.fs
+}
+
 // ValidationError represents a validation failure
 type ValidationError struct {
 	Operation types.Operation
@@ -54,9 +71,9 @@ func (v *Validator) ValidateOperation(op types.Operation) error {
 // validateMoveOperation validates a move/rename operation
 func (v *Validator) validateMoveOperation(op types.Operation) error {
 	// Check source exists
-	sourceInfo, err := os.Stat(op.Source)
+	sourceInfo, err := v.files().Stat(op.Source)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return &ValidationError{
 				Operation: op,
 				Reason:    fmt.Sprintf("source file does not exist: %s", op.Source),
@@ -77,7 +94,7 @@ func (v *Validator) validateMoveOperation(op types.Operation) error {
 	}
 
 	// Check source is readable
-	file, err := os.Open(op.Source)
+	file, err := v.files().Open(op.Source)
 	if err != nil {
 		return &ValidationError{
 			Operation: op,
@@ -126,7 +143,7 @@ func (v *Validator) validateCreateDirOperation(op types.Operation) error {
 	}
 
 	// Check if directory already exists
-	if _, err := os.Stat(op.Destination); err == nil {
+	if _, err := v.files().Stat(op.Destination); err == nil {
 		return &ValidationError{
 			Operation: op,
 			Reason:    fmt.Sprintf("directory already exists: %s", op.Destination),
@@ -156,7 +173,7 @@ func (v *Validator) validateCreateFileOperation(op types.Operation) error {
 	}
 
 	// Check if file already exists
-	if _, err := os.Stat(op.Destination); err == nil {
+	if _, err := v.files().Stat(op.Destination); err == nil {
 		return &ValidationError{
 			Operation: op,
 			Reason:    fmt.Sprintf("file already exists: %s", op.Destination),
@@ -221,11 +238,11 @@ func (v *Validator) validatePath(path string) error {
 // checkWritable verifies a directory exists and is writable
 func (v *Validator) checkWritable(dir string) error {
 	// Check if directory exists
-	info, err := os.Stat(dir)
+	info, err := v.files().Stat(dir)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			// Try to create it
-			if err := os.MkdirAll(dir, 0755); err != nil {
+			if err := v.files().MkdirAll(dir, 0755); err != nil {
 				return fmt.Errorf("cannot create directory: %w", err)
 			}
 			return nil
@@ -239,20 +256,66 @@ func (v *Validator) checkWritable(dir string) error {
 
 	// Try to create a temporary file to verify write permissions
 	tmpFile := filepath.Join(dir, ".go-jf-org-write-test")
-	f, err := os.Create(tmpFile)
-	if err != nil {
+	if err := v.files().WriteFile(tmpFile, nil, 0644); err != nil {
 		return fmt.Errorf("directory is not writable: %w", err)
 	}
-	f.Close()
 	// Clean up test file, log if removal fails but don't error
 	// (the existence of the file proved writability)
-	if err := os.Remove(tmpFile); err != nil {
+	if err := v.files().Remove(tmpFile); err != nil {
 		log.Debug().Err(err).Str("file", tmpFile).Msg("Could not remove write test file")
 	}
 
 	return nil
 }
 
+// CheckDestinationWritable probes the nearest existing ancestor of dir with a
+// write test, without creating any directories. It returns a single clear error
+// when the destination filesystem is read-only or not writable, so callers can
+// abort before starting any operations.
+func (v *Validator) CheckDestinationWritable(dir string) error {
+	existing, err := v.NearestExistingDir(dir)
+	if err != nil {
+		return err
+	}
+	return v.CheckExistingDirWritable(existing)
+}
+
+// CheckExistingDirWritable is CheckDestinationWritable for a directory
+// already known to exist, such as one returned by NearestExistingDir
+func (v *Validator) CheckExistingDirWritable(existing string) error {
+	if err := v.checkWritable(existing); err != nil {
+		if errors.Is(err, syscall.EROFS) {
+			return fmt.Errorf("destination filesystem is read-only: %s", existing)
+		}
+		return fmt.Errorf("destination %s is not writable: %w", existing, err)
+	}
+
+	return nil
+}
+
+// NearestExistingDir returns dir or its closest existing ancestor
+func (v *Validator) NearestExistingDir(dir string) (string, error) {
+	current := filepath.Clean(dir)
+	for {
+		info, err := v.files().Stat(current)
+		if err == nil {
+			if !info.IsDir() {
+				return "", fmt.Errorf("destination path component is not a directory: %s", current)
+			}
+			return current, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("cannot access destination %s: %w", current, err)
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", fmt.Errorf("no existing ancestor for destination: %s", dir)
+		}
+		current = parent
+	}
+}
+
 // checkDiskSpace verifies sufficient disk space is available
 func (v *Validator) checkDiskSpace(path string, requiredBytes uint64) error {
 	// Add 10% buffer
@@ -265,6 +328,10 @@ func (v *Validator) checkDiskSpace(path string, requiredBytes uint64) error {
 		requiredBytes = requiredWithBuffer
 	}
 
+	if !fsys.IsOS(v.files()) {
+		return nil
+	}
+
 	// Note: syscall.Statfs_t is Unix-specific
 	// On Windows, this check will be skipped
 	var stat syscall.Statfs_t
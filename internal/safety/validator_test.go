@@ -1,11 +1,15 @@
 package safety
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/opd-ai/go-jf-org/internal/fsys"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
@@ -391,3 +395,85 @@ func TestCheckDiskSpace(t *testing.T) {
 	// We just verify the function doesn't panic
 	_ = v.checkDiskSpace(tmpDir, 1024*1024*1024*1024*100) // 100 TB
 }
+
+func TestCheckDestinationWritable(t *testing.T) {
+	tmpDir := t.TempDir()
+	v := NewValidator()
+
+	// Missing subdirectories are probed at the nearest existing ancestor and not created
+	missing := filepath.Join(tmpDir, "movies", "The Matrix (1999)")
+	if err := v.CheckDestinationWritable(missing); err != nil {
+		t.Errorf("CheckDestinationWritable() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "movies")); !os.IsNotExist(err) {
+		t.Error("CheckDestinationWritable() should not create directories")
+	}
+
+	// A file in the path is reported
+	file := filepath.Join(tmpDir, "file.txt")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.CheckDestinationWritable(filepath.Join(file, "sub")); err == nil {
+		t.Error("CheckDestinationWritable() expected error when path component is a file")
+	}
+}
+
+func TestCheckDestinationWritable_ReadOnly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Permission testing not reliable on Windows")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("Permission checks are bypassed when running as root")
+	}
+
+	tmpDir := t.TempDir()
+	readOnly := filepath.Join(tmpDir, "readonly")
+	if err := os.Mkdir(readOnly, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(readOnly, 0755)
+
+	v := NewValidator()
+	err := v.CheckDestinationWritable(filepath.Join(readOnly, "Movie (2020)"))
+	if err == nil {
+		t.Fatal("CheckDestinationWritable() expected error for read-only destination")
+	}
+	if !strings.Contains(err.Error(), "not writable") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestValidator_FileSystem(t *testing.T) {
+	m := fsys.NewMemFS()
+	root := string(filepath.Separator)
+	src := filepath.Join(root, "incoming", "movie.mkv")
+	if err := m.MkdirAll(filepath.Dir(src), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile(src, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewValidator()
+	v.SetFileSystem(m)
+	op := types.Operation{
+		Type:        types.OperationMove,
+		Source:      src,
+		Destination: filepath.Join(root, "library", "Movie (2020)", "Movie (2020).mkv"),
+	}
+	if err := v.ValidateOperation(op); err != nil {
+		t.Errorf("ValidateOperation() error = %v", err)
+	}
+	if err := v.CheckDestinationWritable(filepath.Join(root, "other", "Movie")); err != nil {
+		t.Errorf("CheckDestinationWritable() error = %v", err)
+	}
+	if _, err := m.Stat(filepath.Join(root, ".go-jf-org-write-test")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("write test file left behind, stat err = %v", err)
+	}
+
+	op.Source = filepath.Join(root, "incoming", "missing.mkv")
+	if err := v.ValidateOperation(op); err == nil {
+		t.Error("ValidateOperation() should fail for a source missing from the filesystem")
+	}
+}
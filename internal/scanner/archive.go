@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ArchiveSet is a RAR or 7z archive found by a scan, with all its volumes.
+// Releases often arrive as "Movie.part01.rar".."Movie.part50.rar" (or
+// "Movie.rar", "Movie.r00", ...), which hold one media file between them
+// and must be extracted rather than organized part by part.
+type ArchiveSet struct {
+	// Dir is the directory the volumes are in
+	Dir string
+	// Name is the archive name without volume suffix, e.g. "Movie"
+	Name string
+	// Format is "rar" or "7z"
+	Format string
+	// Parts are the volumes in order, the first one to open first
+	Parts []string
+	// Incomplete marks a set missing volumes: its first, or one between the
+	// first and last found
+	Incomplete bool
+}
+
+// First returns the volume an extractor should be given
+func (a ArchiveSet) First() string {
+	return a.Parts[0]
+}
+
+var (
+	// Movie.part01.rar
+	rarPartPattern = regexp.MustCompile(`(?i)^(.+)\.part(\d+)\.rar$`)
+	// Movie.rar, with Movie.r00, Movie.r01, ... following it
+	rarOldPattern = regexp.MustCompile(`(?i)^(.+)\.(rar|r(\d{2,3}))$`)
+	// Movie.7z or Movie.7z.001
+	sevenZipPattern = regexp.MustCompile(`(?i)^(.+)\.7z(?:\.(\d{3}))?$`)
+)
+
+// archivePart parses an archive volume's file name into its set name,
+// format and position in the set; ok is false for any other file
+func archivePart(path string) (name, format string, index int, ok bool) {
+	base := filepath.Base(path)
+	if m := rarPartPattern.FindStringSubmatch(base); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		return m[1], "rar", n, true
+	}
+	if m := rarOldPattern.FindStringSubmatch(base); m != nil {
+		if m[3] == "" {
+			return m[1], "rar", 0, true
+		}
+		n, _ := strconv.Atoi(m[3])
+		return m[1], "rar", n + 1, true
+	}
+	if m := sevenZipPattern.FindStringSubmatch(base); m != nil {
+		if m[2] == "" {
+			return m[1], "7z", 0, true
+		}
+		n, _ := strconv.Atoi(m[2])
+		return m[1], "7z", n, true
+	}
+	return "", "", 0, false
+}
+
+// firstIndex is the index a set's first volume has: 1 for numbered volumes
+// ("part01.rar", "7z.001"), 0 for "Movie.rar" or "Movie.7z"
+func firstIndex(path string) int {
+	base := filepath.Base(path)
+	if rarPartPattern.MatchString(base) {
+		return 1
+	}
+	if m := sevenZipPattern.FindStringSubmatch(base); m != nil && m[2] != "" {
+		return 1
+	}
+	return 0
+}
+
+// IsArchivePart reports whether path is a RAR or 7z archive volume
+func IsArchivePart(path string) bool {
+	_, _, _, ok := archivePart(path)
+	return ok
+}
+
+// GroupArchives groups archive volumes into sets by directory and name,
+// ignoring paths that are not volumes. Sets are returned sorted by their
+// first volume.
+func GroupArchives(paths []string) []ArchiveSet {
+	type volume struct {
+		path  string
+		index int
+	}
+	type key struct{ dir, name, format string }
+
+	sets := make(map[key]*ArchiveSet)
+	volumes := make(map[key][]volume)
+	for _, path := range paths {
+		name, format, index, ok := archivePart(path)
+		if !ok {
+			continue
+		}
+		k := key{filepath.Dir(path), strings.ToLower(name), format}
+		if sets[k] == nil {
+			sets[k] = &ArchiveSet{Dir: k.dir, Name: name, Format: format}
+		}
+		volumes[k] = append(volumes[k], volume{path, index})
+	}
+
+	result := make([]ArchiveSet, 0, len(sets))
+	for k, set := range sets {
+		vols := volumes[k]
+		sort.Slice(vols, func(i, j int) bool { return vols[i].index < vols[j].index })
+		for i, v := range vols {
+			set.Parts = append(set.Parts, v.path)
+			if i > 0 && v.index != vols[i-1].index+1 {
+				set.Incomplete = true
+			}
+		}
+		if vols[0].index != firstIndex(vols[0].path) {
+			set.Incomplete = true
+		}
+		result = append(result, *set)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].First() < result[j].First() })
+	return result
+}
+
+// Extract runs command to unpack the set next to its volumes. command is
+// the program and its arguments, in which "{archive}" is replaced with the
+// first volume and "{dir}" with the set's directory; no shell is involved,
+// so paths with spaces need no quoting.
+func (a ArchiveSet) Extract(command []string) error {
+	if len(command) == 0 {
+		return fmt.Errorf("no extract command configured")
+	}
+	if a.Incomplete {
+		return fmt.Errorf("archive %s is missing volumes", a.First())
+	}
+
+	args := make([]string, len(command))
+	for i, arg := range command {
+		arg = strings.ReplaceAll(arg, "{archive}", a.First())
+		args[i] = strings.ReplaceAll(arg, "{dir}", a.Dir)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = a.Dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to extract %s: %w: %s", a.First(), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Warning describes a set left unextracted, for scan and organize output
+func (a ArchiveSet) Warning() string {
+	msg := fmt.Sprintf("%s: %s archive", a.First(), a.Format)
+	if len(a.Parts) > 1 {
+		msg = fmt.Sprintf("%s: %d-part %s archive", a.First(), len(a.Parts), a.Format)
+	}
+	if a.Incomplete {
+		return msg + " with missing volumes, cannot be extracted"
+	}
+	return msg + ", needs extraction"
+}
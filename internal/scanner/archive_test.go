@@ -0,0 +1,145 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGroupArchives(t *testing.T) {
+	dir := filepath.Join("media", "Movie.2020.1080p")
+	paths := []string{
+		// Listed out of order; part10 sorts after part9
+		filepath.Join(dir, "movie.part10.rar"),
+		filepath.Join(dir, "movie.part2.rar"),
+		filepath.Join(dir, "movie.part1.rar"),
+	}
+	for i := 3; i <= 9; i++ {
+		paths = append(paths, filepath.Join(dir, "movie.part"+strconv.Itoa(i)+".rar"))
+	}
+	paths = append(paths,
+		filepath.Join("media", "Show", "show.s01e01.r01"),
+		filepath.Join("media", "Show", "show.s01e01.rar"),
+		filepath.Join("media", "Show", "show.s01e01.r00"),
+		filepath.Join("media", "Album", "album.7z.002"),
+		filepath.Join("media", "Album", "album.7z.001"),
+		filepath.Join("media", "Broken", "broken.part1.rar"),
+		filepath.Join("media", "Broken", "broken.part3.rar"),
+		filepath.Join("media", "Movie.2020.1080p", "movie.mkv"),
+		filepath.Join("media", "Movie.2020.1080p", "movie.nfo"),
+	)
+
+	sets := GroupArchives(paths)
+	if len(sets) != 4 {
+		t.Fatalf("GroupArchives() = %d sets, want 4: %+v", len(sets), sets)
+	}
+
+	byName := make(map[string]ArchiveSet)
+	for _, set := range sets {
+		byName[set.Name] = set
+	}
+
+	movie := byName["movie"]
+	if movie.Format != "rar" || len(movie.Parts) != 10 || movie.Incomplete {
+		t.Errorf("movie set = %+v, want 10 complete rar parts", movie)
+	}
+	if movie.First() != filepath.Join(dir, "movie.part1.rar") || movie.Parts[9] != filepath.Join(dir, "movie.part10.rar") {
+		t.Errorf("movie parts = %v, want part1 first and part10 last", movie.Parts)
+	}
+
+	show := byName["show.s01e01"]
+	wantShow := []string{"show.s01e01.rar", "show.s01e01.r00", "show.s01e01.r01"}
+	for i, part := range show.Parts {
+		if filepath.Base(part) != wantShow[i] {
+			t.Errorf("show parts = %v, want %v", show.Parts, wantShow)
+			break
+		}
+	}
+	if show.Incomplete {
+		t.Error("old-style rar set marked incomplete")
+	}
+
+	album := byName["album"]
+	if album.Format != "7z" || filepath.Base(album.First()) != "album.7z.001" || album.Incomplete {
+		t.Errorf("album set = %+v, want a complete 7z set starting at .001", album)
+	}
+
+	broken := byName["broken"]
+	if !broken.Incomplete {
+		t.Errorf("broken set = %+v, want it incomplete (part2 missing)", broken)
+	}
+	if !strings.Contains(broken.Warning(), "missing volumes") {
+		t.Errorf("Warning() = %q, want it to mention missing volumes", broken.Warning())
+	}
+	if got := movie.Warning(); !strings.Contains(got, "10-part rar archive, needs extraction") {
+		t.Errorf("Warning() = %q, want it to say the set needs extraction", got)
+	}
+}
+
+func TestGroupArchives_MissingFirstVolume(t *testing.T) {
+	sets := GroupArchives([]string{"movie.part2.rar", "movie.part3.rar"})
+	if len(sets) != 1 || !sets[0].Incomplete {
+		t.Errorf("GroupArchives() = %+v, want one incomplete set", sets)
+	}
+}
+
+func TestScan_ArchiveSets(t *testing.T) {
+	tmpDir := t.TempDir()
+	release := filepath.Join(tmpDir, "Movie.2020.1080p")
+	if err := os.MkdirAll(release, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"movie.part1.rar", "movie.part2.rar", "movie.part3.rar", "Other.2021.mkv"} {
+		if err := os.WriteFile(filepath.Join(release, name), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewScanner([]string{".mkv"}, nil, nil, 0)
+	result, err := s.Scan(tmpDir)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	// The volumes are grouped as one set, never listed as media files
+	if len(result.Files) != 1 || filepath.Base(result.Files[0]) != "Other.2021.mkv" {
+		t.Errorf("Files = %v, want only Other.2021.mkv", result.Files)
+	}
+	if len(result.Archives) != 1 || len(result.Archives[0].Parts) != 3 {
+		t.Fatalf("Archives = %+v, want one 3-part set", result.Archives)
+	}
+	if result.Archives[0].First() != filepath.Join(release, "movie.part1.rar") {
+		t.Errorf("First() = %s, want part1", result.Archives[0].First())
+	}
+}
+
+func TestArchiveSet_Extract(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses cp")
+	}
+	tmpDir := t.TempDir()
+	first := filepath.Join(tmpDir, "movie.part1.rar")
+	if err := os.WriteFile(first, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	set := ArchiveSet{Dir: tmpDir, Name: "movie", Format: "rar", Parts: []string{first}}
+
+	// The placeholders are filled in per argument
+	if err := set.Extract([]string{"cp", "{archive}", "{dir}/movie.mkv"}); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "movie.mkv")); err != nil {
+		t.Errorf("extract command did not run with the set's paths: %v", err)
+	}
+
+	if err := set.Extract([]string{"false"}); err == nil {
+		t.Error("Extract() with a failing command returned no error")
+	}
+	set.Incomplete = true
+	if err := set.Extract([]string{"cp", "{archive}", "{dir}/again.mkv"}); err == nil {
+		t.Error("Extract() of an incomplete set returned no error")
+	}
+}
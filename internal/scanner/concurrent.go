@@ -16,6 +16,8 @@ import (
 type WorkerPool struct {
 	numWorkers int
 	detector   detector.Detector
+	maxDepth   int
+	ignore     *IgnoreMatcher
 }
 
 // NewWorkerPool creates a new worker pool for concurrent scanning
@@ -26,9 +28,20 @@ func NewWorkerPool(numWorkers int, det detector.Detector) *WorkerPool {
 	return &WorkerPool{
 		numWorkers: numWorkers,
 		detector:   det,
+		maxDepth:   -1,
 	}
 }
 
+// SetMaxDepth limits how far below the root the walk descends (-1 = unlimited)
+func (wp *WorkerPool) SetMaxDepth(depth int) {
+	wp.maxDepth = depth
+}
+
+// SetIgnore sets the matcher for paths the walk skips
+func (wp *WorkerPool) SetIgnore(m *IgnoreMatcher) {
+	wp.ignore = m
+}
+
 // FileScanResult represents a single file scan result
 type FileScanResult struct {
 	Path      string
@@ -176,6 +189,17 @@ func (wp *WorkerPool) walkDirectory(ctx context.Context, rootPath string, pathCh
 			return nil
 		}
 
+		if ignored(wp.ignore, rootPath, path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() && beyondMaxDepth(rootPath, path, wp.maxDepth) {
+			return filepath.SkipDir
+		}
+
 		// Send file paths to channel
 		if !info.IsDir() {
 			select {
@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/internal/jellyfin"
+	"github.com/opd-ai/go-jf-org/internal/metadata"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// DuplicateFile describes one copy within a duplicate group
+type DuplicateFile struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	Quality string `json:"quality,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Codec   string `json:"codec,omitempty"`
+	Proper  bool   `json:"proper,omitempty"`
+	Repack  bool   `json:"repack,omitempty"`
+	// ReleaseGroup is the scene group, e.g. "SPARKS"
+	ReleaseGroup string `json:"release_group,omitempty"`
+	// Best marks the preferred copy by metadata.CompareQualityWithGroups
+	Best bool `json:"best,omitempty"`
+
+	meta *types.Metadata
+}
+
+// DuplicateGroup is a set of files resolving to the same media identity
+type DuplicateGroup struct {
+	// Key identifies the media, e.g. "The Matrix (1999)" or "Breaking Bad S01E01"
+	Key       string          `json:"key"`
+	MediaType types.MediaType `json:"media_type"`
+	Files     []DuplicateFile `json:"files"`
+}
+
+// FindDuplicates groups movie and TV files by parsed identity (title+year for
+// movies, show+S##E## for TV) and returns groups containing more than one file.
+// It only reads metadata and file sizes; nothing is moved.
+func (s *Scanner) FindDuplicates(files []string) []DuplicateGroup {
+	groups := make(map[string]*DuplicateGroup)
+
+	for _, file := range files {
+		mediaType := s.GetMediaType(file)
+		if mediaType != types.MediaTypeMovie && mediaType != types.MediaTypeTV {
+			continue
+		}
+
+		meta, err := s.GetMetadata(file)
+		if err != nil || meta == nil {
+			continue
+		}
+
+		key := duplicateKey(mediaType, meta)
+		if key == "" {
+			continue
+		}
+
+		df := DuplicateFile{
+			Path:         file,
+			Quality:      meta.Quality,
+			Source:       meta.Source,
+			Codec:        meta.Codec,
+			Proper:       meta.IsProper,
+			Repack:       meta.IsRepack,
+			ReleaseGroup: meta.ReleaseGroup,
+			meta:         meta,
+		}
+		if info, err := os.Stat(file); err == nil {
+			df.Size = info.Size()
+		}
+
+		lookup := string(mediaType) + "|" + strings.ToLower(key)
+		group, ok := groups[lookup]
+		if !ok {
+			group = &DuplicateGroup{Key: key, MediaType: mediaType}
+			groups[lookup] = group
+		}
+		group.Files = append(group.Files, df)
+	}
+
+	duplicates := make([]DuplicateGroup, 0)
+	for _, group := range groups {
+		if len(group.Files) > 1 {
+			sort.Slice(group.Files, func(i, j int) bool {
+				return group.Files[i].Path < group.Files[j].Path
+			})
+			markBest(group.Files, s.trustedGroups)
+			duplicates = append(duplicates, *group)
+		}
+	}
+
+	// Sort for deterministic output
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].MediaType != duplicates[j].MediaType {
+			return duplicates[i].MediaType < duplicates[j].MediaType
+		}
+		return duplicates[i].Key < duplicates[j].Key
+	})
+
+	return duplicates
+}
+
+// markBest flags the highest-quality copy, breaking quality ties by trusted
+// release group; remaining ties keep the first path
+func markBest(files []DuplicateFile, trustedGroups []string) {
+	best := 0
+	for i := 1; i < len(files); i++ {
+		if metadata.CompareQualityWithGroups(files[i].meta, files[best].meta, trustedGroups) > 0 {
+			best = i
+		}
+	}
+	files[best].Best = true
+}
+
+// duplicateKey returns the media identity used to group duplicates
+func duplicateKey(mediaType types.MediaType, meta *types.Metadata) string {
+	switch mediaType {
+	case types.MediaTypeMovie:
+		if meta.Title == "" {
+			return ""
+		}
+		if meta.Year > 0 {
+			return fmt.Sprintf("%s (%d)", meta.Title, meta.Year)
+		}
+		return meta.Title
+	case types.MediaTypeTV:
+		if meta.TVMetadata == nil || meta.TVMetadata.ShowTitle == "" {
+			return ""
+		}
+		if meta.TVMetadata.Episode == 0 && meta.TVMetadata.AirDate != "" {
+			return fmt.Sprintf("%s %s", meta.TVMetadata.ShowTitle, meta.TVMetadata.AirDate)
+		}
+		return fmt.Sprintf("%s %s", meta.TVMetadata.ShowTitle, jellyfin.EpisodeCode(meta.TVMetadata))
+	}
+	return ""
+}
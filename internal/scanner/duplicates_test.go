@@ -0,0 +1,141 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := []string{
+		filepath.Join(tmpDir, "The.Matrix.1999.1080p.BluRay.x264.mkv"),
+		filepath.Join(tmpDir, "sub", "The.Matrix.1999.720p.WEB-DL.mkv"),
+		filepath.Join(tmpDir, "Inception.2010.1080p.mkv"),
+		filepath.Join(tmpDir, "Breaking.Bad.S01E01.720p.mkv"),
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewScanner([]string{".mkv"}, nil, nil, 0)
+	groups := s.FindDuplicates(files)
+
+	if len(groups) != 1 {
+		t.Fatalf("FindDuplicates() got %d groups, want 1: %+v", len(groups), groups)
+	}
+
+	group := groups[0]
+	if group.MediaType != types.MediaTypeMovie {
+		t.Errorf("group media type = %s, want movie", group.MediaType)
+	}
+	if group.Key != "The Matrix (1999)" {
+		t.Errorf("group key = %q, want %q", group.Key, "The Matrix (1999)")
+	}
+	if len(group.Files) != 2 {
+		t.Fatalf("group has %d files, want 2", len(group.Files))
+	}
+
+	qualities := map[string]bool{}
+	for _, f := range group.Files {
+		qualities[f.Quality] = true
+		if f.Size != 4 {
+			t.Errorf("file %s size = %d, want 4", f.Path, f.Size)
+		}
+	}
+	if !qualities["1080P"] || !qualities["720P"] {
+		t.Errorf("expected 1080P and 720P qualities, got %v", qualities)
+	}
+
+	// Nothing should have been moved
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			t.Errorf("file %s missing after FindDuplicates: %v", f, err)
+		}
+	}
+}
+
+func TestFindDuplicates_PrefersRepack(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := []string{
+		filepath.Join(tmpDir, "a", "Breaking.Bad.S01E01.720p.HDTV.mkv"),
+		filepath.Join(tmpDir, "b", "Breaking.Bad.S01E01.REPACK.720p.HDTV.mkv"),
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewScanner([]string{".mkv"}, nil, nil, 0)
+	groups := s.FindDuplicates(files)
+
+	if len(groups) != 1 || len(groups[0].Files) != 2 {
+		t.Fatalf("FindDuplicates() = %+v, want one group of 2", groups)
+	}
+
+	for _, f := range groups[0].Files {
+		wantBest := f.Path == files[1]
+		if f.Best != wantBest {
+			t.Errorf("file %s Best = %v, want %v", f.Path, f.Best, wantBest)
+		}
+		if f.Repack != wantBest {
+			t.Errorf("file %s Repack = %v, want %v", f.Path, f.Repack, wantBest)
+		}
+	}
+}
+
+func TestFindDuplicates_MediaInfoOverridesFilename(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// The 1080p-named copy is really a 720p encode according to its report
+	files := []string{
+		filepath.Join(tmpDir, "a", "Inception.2010.1080p.mkv"),
+		filepath.Join(tmpDir, "b", "Inception.2010.720p.mkv"),
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report := "Video\nFormat : AVC\nWidth : 1 280 pixels\nHeight : 720 pixels\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "a", "Inception.2010.1080p.mediainfo"), []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report = "Video\nFormat : HEVC\nWidth : 1 920 pixels\nHeight : 1 080 pixels\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "b", "Inception.2010.720p.txt"), []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScanner([]string{".mkv"}, nil, nil, 0)
+	s.SetReadMediaInfo(true)
+	groups := s.FindDuplicates(files)
+
+	if len(groups) != 1 || len(groups[0].Files) != 2 {
+		t.Fatalf("FindDuplicates() = %+v, want one group of 2", groups)
+	}
+	for _, f := range groups[0].Files {
+		wantBest := f.Path == files[1]
+		if f.Best != wantBest {
+			t.Errorf("file %s Best = %v, want %v", f.Path, f.Best, wantBest)
+		}
+	}
+	if got := groups[0].Files[1]; got.Quality != "1080P" || got.Codec != "h265" {
+		t.Errorf("report copy = %s %s, want 1080P h265", got.Quality, got.Codec)
+	}
+}
@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+// IgnoreFileName is the gitignore-style file read from a scan root
+const IgnoreFileName = ".jf-ignore"
+
+// ignoreRule is a single parsed ignore pattern
+type ignoreRule struct {
+	// segments of the pattern split on "/"
+	segments []string
+	// negate re-includes paths matched by earlier rules ("!pattern")
+	negate bool
+	// dirOnly matches directories only (trailing "/")
+	dirOnly bool
+	// anchored matches against the full relative path rather than any name
+	anchored bool
+}
+
+// IgnoreMatcher decides whether paths below a scan root are excluded using
+// gitignore-style patterns. Later patterns override earlier ones
+type IgnoreMatcher struct {
+	rules []ignoreRule
+}
+
+// NewIgnoreMatcher parses gitignore-style patterns. Blank lines and lines
+// starting with "#" are skipped; "!" negates, a trailing "/" matches only
+// directories, a pattern containing "/" is anchored to the root and "**"
+// matches any number of directories
+func NewIgnoreMatcher(patterns []string) *IgnoreMatcher {
+	m := &IgnoreMatcher{}
+	for _, p := range patterns {
+		p = strings.TrimRight(p, " \t\r")
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if strings.HasPrefix(p, "!") {
+			rule.negate = true
+			p = p[1:]
+		} else if strings.HasPrefix(p, `\`) {
+			p = p[1:]
+		}
+		if strings.HasSuffix(p, "/") {
+			rule.dirOnly = true
+			p = strings.TrimRight(p, "/")
+		}
+		if strings.Contains(p, "/") {
+			rule.anchored = true
+			p = strings.TrimPrefix(p, "/")
+		}
+		if p == "" {
+			continue
+		}
+		rule.segments = strings.Split(p, "/")
+		m.rules = append(m.rules, rule)
+	}
+	return m
+}
+
+// LoadIgnoreFile reads the patterns in root's .jf-ignore. A missing file
+// yields no patterns and no error
+func LoadIgnoreFile(root string) ([]string, error) {
+	return loadIgnoreFile(fsys.OSFileSystem{}, root)
+}
+
+// loadIgnoreFile is LoadIgnoreFile reading through fs
+func loadIgnoreFile(fs fsys.ReadFS, root string) ([]string, error) {
+	f, err := fs.Open(filepath.Join(root, IgnoreFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", IgnoreFileName, err)
+	}
+	defer f.Close()
+
+	var patterns []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		patterns = append(patterns, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	return patterns, nil
+}
+
+// Empty reports whether the matcher has no rules
+func (m *IgnoreMatcher) Empty() bool {
+	return m == nil || len(m.rules) == 0
+}
+
+// Match reports whether rel, a slash- or OS-separated path relative to the
+// scan root, is ignored
+func (m *IgnoreMatcher) Match(rel string, isDir bool) bool {
+	if m.Empty() {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." || rel == "" {
+		return false
+	}
+
+	parts := strings.Split(rel, "/")
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		var ok bool
+		if r.anchored {
+			ok = matchSegments(r.segments, parts)
+		} else {
+			ok = matchSegments(r.segments, parts[len(parts)-1:])
+		}
+		if ok {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matchSegments matches pattern segments against path segments, letting
+// "**" stand for zero or more path segments
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], parts[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
+
+// ignoreMatcher combines the configured exclude patterns with the root's
+// .jf-ignore, whose rules take precedence
+func (s *Scanner) ignoreMatcher(fs fsys.ReadFS, root string) (*IgnoreMatcher, error) {
+	filePatterns, err := loadIgnoreFile(fs, root)
+	if err != nil {
+		return nil, err
+	}
+	patterns := make([]string, 0, len(s.excludes)+len(filePatterns))
+	patterns = append(patterns, s.excludes...)
+	patterns = append(patterns, filePatterns...)
+	return NewIgnoreMatcher(patterns), nil
+}
+
+// ignored reports whether path, found while walking root, matches m
+func ignored(m *IgnoreMatcher, root, path string, isDir bool) bool {
+	if m.Empty() {
+		return false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return m.Match(rel, isDir)
+}
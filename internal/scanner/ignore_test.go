@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestIgnoreMatcher_Match(t *testing.T) {
+	m := NewIgnoreMatcher([]string{
+		"# comment",
+		"",
+		"*.part",
+		"Samples/",
+		"/Incoming/tmp",
+		"**/Extras/**/*.mkv",
+		"*.avi",
+		"!Keep.avi",
+	})
+
+	tests := []struct {
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{"movie.mkv", false, false},
+		{"movie.mkv.part", false, true},
+		{"Show/Season 01/ep.part", false, true},
+		{"Samples", true, true},
+		{"Movie/Samples", true, true},
+		{"Samples", false, false}, // dir-only rule
+		{"Incoming/tmp", true, true},
+		{"Other/Incoming/tmp", true, false}, // anchored to the root
+		{"Movie/Extras/bts/clip.mkv", false, true},
+		{"Extras/clip.mkv", false, true},
+		{"Old.avi", false, true},
+		{"Keep.avi", false, false},
+		{"Dir/Keep.avi", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.rel, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestIgnoreMatcher_Empty(t *testing.T) {
+	var m *IgnoreMatcher
+	if m.Match("anything.mkv", false) {
+		t.Error("nil matcher should not ignore anything")
+	}
+	if !NewIgnoreMatcher([]string{"# only a comment"}).Empty() {
+		t.Error("matcher with only comments should be empty")
+	}
+}
+
+func TestScan_IgnoreFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := []string{
+		"Movie.2020.mkv",
+		"Trailer.mkv",
+		filepath.Join("Extras", "Extra.mkv"),
+		filepath.Join("Downloads", "partial.mkv"),
+		filepath.Join("Downloads", "Wanted.mkv"),
+	}
+	for _, name := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignore := "# skip trailers and download leftovers\nTrailer.mkv\nDownloads/*.mkv\n!Downloads/Wanted.mkv\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, IgnoreFileName), []byte(ignore), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScanner([]string{".mkv"}, nil, nil, 0)
+	s.SetExcludePatterns([]string{"Extras/"})
+	s.SetNumWorkers(2)
+
+	want := []string{"Movie.2020.mkv", "Wanted.mkv"}
+	scans := map[string]func() (*ScanResult, error){
+		"Scan":           func() (*ScanResult, error) { return s.Scan(tmpDir) },
+		"ScanConcurrent": func() (*ScanResult, error) { return s.ScanConcurrent(context.Background(), tmpDir) },
+	}
+	for method, scan := range scans {
+		result, err := scan()
+		if err != nil {
+			t.Fatalf("%s() error = %v", method, err)
+		}
+		got := make([]string, 0, len(result.Files))
+		for _, f := range result.Files {
+			got = append(got, filepath.Base(f))
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s() = %v, want %v", method, got, want)
+		}
+	}
+}
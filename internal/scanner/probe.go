@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+// probeSize is how much of each end of a file the readability probe reads
+const probeSize = 4 * 1024
+
+// SkippedFile is a media file left out of a scan because it looks broken
+type SkippedFile struct {
+	Path   string
+	Reason string
+}
+
+// SetCheckReadable enables reading the first and last few KB of every
+// media file, skipping files that cannot be read or end in zeros, as a
+// download that was preallocated but never finished does
+func (s *Scanner) SetCheckReadable(enabled bool) {
+	s.checkReadable = enabled
+}
+
+// skipReason returns why a media file of the given size should be skipped,
+// or "" if it looks usable. Zero-byte files are always skipped; files below
+// the minimum size are left to that filter rather than probed.
+func (s *Scanner) skipReason(path string, size int64) string {
+	if size == 0 {
+		return "zero-byte file"
+	}
+	if !s.checkReadable || size < s.minFileSize {
+		return ""
+	}
+	return probeReadable(s.fs, path, size)
+}
+
+// probeReadable reads the head and tail of a file and describes what is
+// wrong with it, or returns "" if both reads succeed
+func probeReadable(fs fsys.ReadFS, path string, size int64) string {
+	f, err := fs.Open(path)
+	if err != nil {
+		return fmt.Sprintf("unreadable: %v", err)
+	}
+	defer f.Close()
+
+	n := int64(probeSize)
+	if size < n {
+		n = size
+	}
+	buf := make([]byte, n)
+
+	for _, probe := range []struct {
+		name   string
+		offset int64
+	}{{"start", 0}, {"end", size - n}} {
+		if _, err := f.ReadAt(buf, probe.offset); err == io.EOF {
+			return "truncated: shorter than its reported size"
+		} else if err != nil {
+			return fmt.Sprintf("unreadable %s: %v", probe.name, err)
+		}
+	}
+	if size > n && bytes.Count(buf, []byte{0}) == len(buf) {
+		return "truncated: ends in zeros (incomplete download?)"
+	}
+	return ""
+}
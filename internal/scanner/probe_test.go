@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/fsys"
+)
+
+func TestScan_SkipsZeroByteFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "empty.mkv"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "movie.mkv"), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Zero-byte files are caught even with no minimum size
+	s := NewScanner([]string{".mkv"}, nil, nil, 0)
+	scans := map[string]func() (*ScanResult, error){
+		"Scan":           func() (*ScanResult, error) { return s.Scan(tmpDir) },
+		"ScanConcurrent": func() (*ScanResult, error) { return s.ScanConcurrent(context.Background(), tmpDir) },
+	}
+	for method, scan := range scans {
+		result, err := scan()
+		if err != nil {
+			t.Fatalf("%s() error = %v", method, err)
+		}
+		if len(result.Files) != 1 || filepath.Base(result.Files[0]) != "movie.mkv" {
+			t.Errorf("%s() files = %v, want only movie.mkv", method, result.Files)
+		}
+		if len(result.Skipped) != 1 || filepath.Base(result.Skipped[0].Path) != "empty.mkv" || result.Skipped[0].Reason != "zero-byte file" {
+			t.Errorf("%s() skipped = %+v, want empty.mkv as zero-byte", method, result.Skipped)
+		}
+	}
+}
+
+func TestScan_CheckReadable(t *testing.T) {
+	tmpDir := t.TempDir()
+	data := make([]byte, 3*probeSize)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "good.mkv"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A preallocated download whose second half never arrived
+	partial := append(append([]byte{}, data[:probeSize]...), make([]byte, 2*probeSize)...)
+	if err := os.WriteFile(filepath.Join(tmpDir, "partial.mkv"), partial, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScanner([]string{".mkv"}, nil, nil, 0)
+	result, err := s.Scan(tmpDir)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Errorf("without the probe, Scan() files = %v, want both", result.Files)
+	}
+
+	s.SetCheckReadable(true)
+	result, err = s.Scan(tmpDir)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Files) != 1 || filepath.Base(result.Files[0]) != "good.mkv" {
+		t.Errorf("Scan() files = %v, want only good.mkv", result.Files)
+	}
+	if len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0].Reason, "truncated") {
+		t.Errorf("Scan() skipped = %+v, want partial.mkv as truncated", result.Skipped)
+	}
+}
+
+func TestProbeReadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "short.mkv")
+	if err := os.WriteFile(path, []byte("tiny but fine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if reason := probeReadable(fsys.OSFileSystem{}, path, 13); reason != "" {
+		t.Errorf("probeReadable() on a small file = %q, want pass", reason)
+	}
+	if reason := probeReadable(fsys.OSFileSystem{}, path, 100); !strings.Contains(reason, "truncated") {
+		t.Errorf("probeReadable() with a larger reported size = %q, want truncated", reason)
+	}
+	if reason := probeReadable(fsys.OSFileSystem{}, filepath.Join(t.TempDir(), "missing.mkv"), 10); !strings.Contains(reason, "unreadable") {
+		t.Errorf("probeReadable() on a missing file = %q, want unreadable", reason)
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/opd-ai/go-jf-org/internal/detector"
+	"github.com/opd-ai/go-jf-org/internal/fsys"
 	"github.com/opd-ai/go-jf-org/internal/metadata"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 	"github.com/rs/zerolog/log"
@@ -28,6 +30,18 @@ type Scanner struct {
 	parser metadata.Parser
 	// Number of workers for concurrent scanning (0 = auto-detect)
 	numWorkers int
+	// Release groups preferred when picking the best duplicate, most trusted first
+	trustedGroups []string
+	// Maximum directory depth to descend below the root (-1 = unlimited)
+	maxDepth int
+	// Gitignore-style patterns excluded from every scan
+	excludes []string
+	// Whether to read each file's head and tail to catch broken downloads
+	checkReadable bool
+	// Whether a video's companion MediaInfo report overrides its quality tags
+	readMediaInfo bool
+	// File system Scan reads, the local disk unless set
+	fs fsys.ReadFS
 }
 
 // NewScanner creates a new Scanner with the given configuration
@@ -40,26 +54,66 @@ func NewScanner(videoExts, audioExts, bookExts []string, minSize int64) *Scanner
 		detector:        detector.New(),
 		parser:          metadata.NewParser(),
 		numWorkers:      0, // Auto-detect
+		maxDepth:        -1,
+		fs:              fsys.OSFileSystem{},
 	}
 }
 
-// SetNumWorkers sets the number of concurrent workers (0 = auto-detect based on CPU count)
+// SetNumWorkers sets the number of concurrent file system workers used for
+// size lookups and concurrent scans (0 = auto-detect based on CPU count)
 func (s *Scanner) SetNumWorkers(n int) {
 	s.numWorkers = n
 }
 
+// SetTrustedReleaseGroups sets the release groups preferred, most trusted
+// first, when duplicates are otherwise of equal quality
+func (s *Scanner) SetTrustedReleaseGroups(groups []string) {
+	s.trustedGroups = groups
+}
+
+// SetReadMediaInfo makes GetMetadata take a video's resolution and codec from
+// a companion MediaInfo report ("Movie.mediainfo" or "Movie.txt") when one
+// sits beside it, so duplicates are ranked by measured quality
+func (s *Scanner) SetReadMediaInfo(read bool) {
+	s.readMediaInfo = read
+}
+
+// SetMaxDepth limits how far below the root the scan descends. 0 scans only
+// the root's direct children; a negative value removes the limit
+func (s *Scanner) SetMaxDepth(depth int) {
+	s.maxDepth = depth
+}
+
+// SetExcludePatterns sets gitignore-style patterns excluded from every scan;
+// a .jf-ignore in the scan root is applied after them
+func (s *Scanner) SetExcludePatterns(patterns []string) {
+	s.excludes = patterns
+}
+
+// SetFileSystem makes Scan read through f, e.g. an SFTP server, instead of
+// the local disk. ScanConcurrent always reads the local disk.
+func (s *Scanner) SetFileSystem(f fsys.ReadFS) {
+	s.fs = f
+}
+
 // ScanResult contains the results of a scan operation
 type ScanResult struct {
 	// Files is a list of absolute paths to media files that match the scan criteria
 	Files []string
 	// Errors is a collection of non-fatal errors encountered during the scan
 	Errors []error
+	// Skipped lists zero-byte and, with SetCheckReadable, unreadable or
+	// truncated media files, with the reason each was left out
+	Skipped []SkippedFile
+	// Archives are the RAR and 7z archive sets found, which need extracting
+	// before their contents can be organized (Scan only)
+	Archives []ArchiveSet
 }
 
 // Scan walks the directory tree and returns all media files
 func (s *Scanner) Scan(rootPath string) (*ScanResult, error) {
 	// Verify the path exists
-	info, err := os.Stat(rootPath)
+	info, err := s.fs.Stat(rootPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to access path: %w", err)
 	}
@@ -68,6 +122,11 @@ func (s *Scanner) Scan(rootPath string) (*ScanResult, error) {
 		return nil, fmt.Errorf("path is not a directory: %s", rootPath)
 	}
 
+	ignore, err := s.ignoreMatcher(s.fs, rootPath)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &ScanResult{
 		Files:  make([]string, 0),
 		Errors: make([]error, 0),
@@ -75,36 +134,39 @@ func (s *Scanner) Scan(rootPath string) (*ScanResult, error) {
 
 	log.Info().Str("path", rootPath).Msg("Starting directory scan")
 
+	var candidates []candidate
+	var archiveParts []string
+
 	// Walk the directory tree
-	err = filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+	err = fsys.WalkDir(s.fs, rootPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			log.Warn().Err(err).Str("path", path).Msg("Error accessing path")
 			result.Errors = append(result.Errors, fmt.Errorf("error accessing %s: %w", path, err))
 			return nil // Continue walking
 		}
 
-		// Skip directories
-		if d.IsDir() {
+		if ignored(ignore, rootPath, path, d.IsDir()) {
+			log.Debug().Str("path", path).Msg("Path matches an ignore pattern, skipping")
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		// Check if file matches our criteria
-		if s.isMediaFile(path) {
-			// Check file size
-			fileInfo, err := d.Info()
-			if err != nil {
-				log.Warn().Err(err).Str("path", path).Msg("Failed to get file info")
-				result.Errors = append(result.Errors, fmt.Errorf("failed to get file info for %s: %w", path, err))
-				return nil
-			}
-
-			if fileInfo.Size() < s.minFileSize {
-				log.Debug().Str("path", path).Int64("size", fileInfo.Size()).Msg("File too small, skipping")
-				return nil
+		// Skip directories, pruning those beyond the depth limit
+		if d.IsDir() {
+			if beyondMaxDepth(rootPath, path, s.maxDepth) {
+				log.Debug().Str("path", path).Int("max_depth", s.maxDepth).Msg("Directory beyond max depth, skipping")
+				return filepath.SkipDir
 			}
+			return nil
+		}
 
-			result.Files = append(result.Files, path)
-			log.Debug().Str("path", path).Msg("Found media file")
+		// Check if file matches our criteria; sizes are looked up afterwards
+		if s.isMediaFile(path) {
+			candidates = append(candidates, candidate{path: path, entry: d})
+		} else if IsArchivePart(path) {
+			archiveParts = append(archiveParts, path)
 		}
 
 		return nil
@@ -114,11 +176,90 @@ func (s *Scanner) Scan(rootPath string) (*ScanResult, error) {
 		return nil, fmt.Errorf("failed to walk directory: %w", err)
 	}
 
+	// Check file sizes, which on network shares costs a round trip per file
+	for _, c := range s.statCandidates(candidates) {
+		if c.err != nil {
+			log.Warn().Err(c.err).Str("path", c.path).Msg("Failed to get file info")
+			result.Errors = append(result.Errors, fmt.Errorf("failed to get file info for %s: %w", c.path, c.err))
+			continue
+		}
+
+		if c.skip != "" {
+			log.Warn().Str("path", c.path).Str("reason", c.skip).Msg("Skipping broken media file")
+			result.Skipped = append(result.Skipped, SkippedFile{Path: c.path, Reason: c.skip})
+			continue
+		}
+
+		if c.size < s.minFileSize {
+			log.Debug().Str("path", c.path).Int64("size", c.size).Msg("File too small, skipping")
+			continue
+		}
+
+		result.Files = append(result.Files, c.path)
+		log.Debug().Str("path", c.path).Msg("Found media file")
+	}
+
+	result.Archives = GroupArchives(archiveParts)
+	for _, set := range result.Archives {
+		log.Debug().Str("archive", set.First()).Int("parts", len(set.Parts)).Msg("Found archive set")
+	}
+
 	log.Info().Int("count", len(result.Files)).Int("errors", len(result.Errors)).Msg("Scan complete")
 
 	return result, nil
 }
 
+// candidate is a walked media file whose size has yet to be checked
+type candidate struct {
+	path  string
+	entry fs.DirEntry
+	size  int64
+	skip  string
+	err   error
+}
+
+// fileInfo returns a walked entry's info; replaceable in tests
+var fileInfo = func(d fs.DirEntry) (fs.FileInfo, error) {
+	return d.Info()
+}
+
+// statCandidates fills in each candidate's size and skip reason using up to
+// the scanner's worker count of concurrent lookups, keeping the walk order
+func (s *Scanner) statCandidates(candidates []candidate) []candidate {
+	workers := s.numWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(candidates) {
+		workers = len(candidates)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				info, err := fileInfo(candidates[idx].entry)
+				if err != nil {
+					candidates[idx].err = err
+					continue
+				}
+				candidates[idx].size = info.Size()
+				candidates[idx].skip = s.skipReason(candidates[idx].path, info.Size())
+			}
+		}()
+	}
+	for i := range candidates {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return candidates
+}
+
 // ScanConcurrent walks the directory tree concurrently and returns all media files
 // This method uses worker pools for better performance on large directories
 func (s *Scanner) ScanConcurrent(ctx context.Context, rootPath string) (*ScanResult, error) {
@@ -132,6 +273,11 @@ func (s *Scanner) ScanConcurrent(ctx context.Context, rootPath string) (*ScanRes
 		return nil, fmt.Errorf("path is not a directory: %s", rootPath)
 	}
 
+	ignore, err := s.ignoreMatcher(fsys.OSFileSystem{}, rootPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// Determine number of workers
 	numWorkers := s.numWorkers
 	if numWorkers <= 0 {
@@ -148,6 +294,8 @@ func (s *Scanner) ScanConcurrent(ctx context.Context, rootPath string) (*ScanRes
 
 	// Create worker pool and scan
 	pool := NewWorkerPool(numWorkers, s.detector)
+	pool.SetMaxDepth(s.maxDepth)
+	pool.SetIgnore(ignore)
 	paths, sizes, err := pool.ScanConcurrent(ctx, rootPath, allExtensions)
 	if err != nil {
 		return nil, fmt.Errorf("concurrent scan failed: %w", err)
@@ -160,6 +308,11 @@ func (s *Scanner) ScanConcurrent(ctx context.Context, rootPath string) (*ScanRes
 	}
 
 	for i, path := range paths {
+		if reason := s.skipReason(path, sizes[i]); reason != "" {
+			log.Warn().Str("path", path).Str("reason", reason).Msg("Skipping broken media file")
+			result.Skipped = append(result.Skipped, SkippedFile{Path: path, Reason: reason})
+			continue
+		}
 		if sizes[i] >= s.minFileSize {
 			result.Files = append(result.Files, path)
 		} else {
@@ -172,6 +325,19 @@ func (s *Scanner) ScanConcurrent(ctx context.Context, rootPath string) (*ScanRes
 	return result, nil
 }
 
+// beyondMaxDepth reports whether descending into dir would exceed maxDepth
+// levels below root. The root itself is never beyond the limit
+func beyondMaxDepth(root, dir string, maxDepth int) bool {
+	if maxDepth < 0 {
+		return false
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return false
+	}
+	return strings.Count(rel, string(filepath.Separator))+1 > maxDepth
+}
+
 // isMediaFile checks if a file is a media file based on its extension
 func (s *Scanner) isMediaFile(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
@@ -189,7 +355,16 @@ func (s *Scanner) GetMediaType(path string) types.MediaType {
 // GetMetadata extracts metadata from a file
 func (s *Scanner) GetMetadata(path string) (*types.Metadata, error) {
 	mediaType := s.GetMediaType(path)
-	return s.parser.Parse(filepath.Base(path), mediaType)
+	meta, err := s.parser.Parse(filepath.Base(path), mediaType)
+	if err != nil || meta == nil {
+		return meta, err
+	}
+	metadata.ApplyParentTitle(meta, path)
+	metadata.ApplyParentRelease(meta, path)
+	if s.readMediaInfo {
+		metadata.ApplyMediaInfo(meta, path)
+	}
+	return meta, nil
 }
 
 // normalizeExtensions ensures all extensions start with a dot and are lowercase
@@ -1,9 +1,16 @@
 package scanner
 
 import (
+	"context"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
@@ -192,3 +199,108 @@ func TestScanNonExistentDirectory(t *testing.T) {
 		t.Error("Expected error for non-existent directory, got nil")
 	}
 }
+
+func TestScan_MaxDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := []string{
+		"top.mkv",
+		filepath.Join("One", "one.mkv"),
+		filepath.Join("One", "Two", "two.mkv"),
+		filepath.Join("One", "Two", "Three", "three.mkv"),
+	}
+	for _, name := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		want     []string
+	}{
+		{"unlimited", -1, []string{"one.mkv", "three.mkv", "top.mkv", "two.mkv"}},
+		{"direct children only", 0, []string{"top.mkv"}},
+		{"one level", 1, []string{"one.mkv", "top.mkv"}},
+		{"two levels", 2, []string{"one.mkv", "top.mkv", "two.mkv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScanner([]string{".mkv"}, nil, nil, 0)
+			s.SetMaxDepth(tt.maxDepth)
+			s.SetNumWorkers(2)
+
+			scans := map[string]func() (*ScanResult, error){
+				"Scan":           func() (*ScanResult, error) { return s.Scan(tmpDir) },
+				"ScanConcurrent": func() (*ScanResult, error) { return s.ScanConcurrent(context.Background(), tmpDir) },
+			}
+			for method, scan := range scans {
+				result, err := scan()
+				if err != nil {
+					t.Fatalf("%s() error = %v", method, err)
+				}
+				got := make([]string, 0, len(result.Files))
+				for _, f := range result.Files {
+					got = append(got, filepath.Base(f))
+				}
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s() with max depth %d = %v, want %v", method, tt.maxDepth, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestScan_RespectsWorkerCount(t *testing.T) {
+	tmpDir := t.TempDir()
+	var want []string
+	for i := 0; i < 24; i++ {
+		path := filepath.Join(tmpDir, fmt.Sprintf("Movie.%02d.mkv", i))
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, path)
+	}
+
+	// Slow stat calls down, as on a network share, and record how many run at once
+	var active, peak int32
+	old := fileInfo
+	fileInfo = func(d fs.DirEntry) (fs.FileInfo, error) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		return d.Info()
+	}
+	defer func() { fileInfo = old }()
+
+	for _, workers := range []int{1, 3, 8} {
+		atomic.StoreInt32(&peak, 0)
+		s := NewScanner([]string{".mkv"}, nil, nil, 0)
+		s.SetNumWorkers(workers)
+
+		result, err := s.Scan(tmpDir)
+		if err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		if !reflect.DeepEqual(result.Files, want) {
+			t.Errorf("Scan() with %d workers = %v, want %v in walk order", workers, result.Files, want)
+		}
+		if got := int(atomic.LoadInt32(&peak)); got > workers {
+			t.Errorf("Scan() with %d workers ran %d stat calls at once", workers, got)
+		} else if workers > 1 && got < 2 {
+			t.Errorf("Scan() with %d workers never overlapped stat calls", workers)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Package server exposes organize runs and the transaction log over a small
+// HTTP API, so home dashboards can follow and trigger go-jf-org.
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/internal/notify"
+	"github.com/opd-ai/go-jf-org/internal/safety"
+)
+
+// OrganizeRequest is the JSON body of POST /organize; unset fields fall
+// back to the organize command's defaults and the config file
+type OrganizeRequest struct {
+	Paths     []string `json:"paths"`
+	Dest      string   `json:"dest,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	Conflict  string   `json:"conflict,omitempty"`
+	DryRun    bool     `json:"dry_run,omitempty"`
+	CreateNFO bool     `json:"create_nfo,omitempty"`
+	Hash      bool     `json:"hash,omitempty"`
+}
+
+// OrganizeFunc performs one organize run, recording its outcome in run
+type OrganizeFunc func(req OrganizeRequest, run *notify.Summary) error
+
+// Run is one organize run started through the API
+type Run struct {
+	Request  OrganizeRequest `json:"request"`
+	Started  time.Time       `json:"started"`
+	Finished time.Time       `json:"finished,omitempty"`
+	Summary  *notify.Summary `json:"summary,omitempty"`
+}
+
+// Status is the body of GET /status
+type Status struct {
+	Running bool `json:"running"`
+	Current *Run `json:"current,omitempty"`
+	Last    *Run `json:"last,omitempty"`
+}
+
+// TransactionInfo is one entry of GET /transactions
+type TransactionInfo struct {
+	ID         string                   `json:"id"`
+	Status     safety.TransactionStatus `json:"status"`
+	Operations int                      `json:"operations"`
+	Timestamp  time.Time                `json:"timestamp"`
+	Completed  time.Time                `json:"completed,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+// Server serves the HTTP API; it runs at most one organize or rollback at
+// a time
+type Server struct {
+	tm       *safety.TransactionManager
+	organize OrganizeFunc
+	token    string
+
+	mu      sync.Mutex
+	current *Run
+	last    *Run
+	// rollingBack is set while a rollback runs
+	rollingBack bool
+	// done is closed when the current run finishes; used by tests
+	done chan struct{}
+}
+
+// New creates a server that organizes with organize and lists and rolls
+// back transactions from tm. A non-empty token must be sent by every
+// client as "Authorization: Bearer <token>".
+func New(tm *safety.TransactionManager, organize OrganizeFunc, token string) *Server {
+	return &Server{
+		tm:       tm,
+		organize: organize,
+		token:    token,
+	}
+}
+
+// Handler returns the API's routes, behind the token check
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("GET /transactions", s.handleTransactions)
+	mux.HandleFunc("POST /organize", s.handleOrganize)
+	mux.HandleFunc("POST /rollback/{id}", s.handleRollback)
+	return s.authorize(mux)
+}
+
+// authorize rejects requests without the configured token
+func (s *Server) authorize(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+// status snapshots the current and last runs
+func (s *Server) status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Status{
+		Running: s.current != nil,
+		Current: copyRun(s.current),
+		Last:    copyRun(s.last),
+	}
+}
+
+func (s *Server) handleTransactions(w http.ResponseWriter, r *http.Request) {
+	ids, err := s.tm.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to list transactions: %w", err))
+		return
+	}
+
+	infos := make([]TransactionInfo, 0, len(ids))
+	for _, id := range ids {
+		txn, err := s.tm.Load(id)
+		if err != nil {
+			log.Warn().Err(err).Str("id", id).Msg("Failed to load transaction")
+			continue
+		}
+		infos = append(infos, TransactionInfo{
+			ID:         txn.ID,
+			Status:     txn.Status,
+			Operations: len(txn.Operations),
+			Timestamp:  txn.Timestamp,
+			Completed:  txn.Completed,
+			Error:      txn.Error,
+		})
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (s *Server) handleOrganize(w http.ResponseWriter, r *http.Request) {
+	var req OrganizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if len(req.Paths) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("paths is required"))
+		return
+	}
+	if req.Conflict == "interactive" {
+		writeError(w, http.StatusBadRequest, errors.New("interactive conflict strategy is not available over the API"))
+		return
+	}
+
+	s.mu.Lock()
+	if s.current != nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, errors.New("an organize run is already in progress"))
+		return
+	}
+	if s.rollingBack {
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, errors.New("a rollback is in progress"))
+		return
+	}
+	run := &Run{Request: req, Started: time.Now()}
+	s.current = run
+	s.done = make(chan struct{})
+	done := s.done
+	s.mu.Unlock()
+
+	go s.execute(run, done)
+	writeJSON(w, http.StatusAccepted, copyRun(run))
+}
+
+// execute performs run and records it as the last run
+func (s *Server) execute(run *Run, done chan struct{}) {
+	defer close(done)
+
+	summary := &notify.Summary{Command: "organize"}
+	err := s.organize(run.Request, summary)
+	summary.Success = err == nil && summary.Failed == 0
+	if err != nil {
+		summary.Error = err.Error()
+		log.Error().Err(err).Msg("Organize run from API failed")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run.Finished = time.Now()
+	summary.DurationMS = run.Finished.Sub(run.Started).Milliseconds()
+	run.Summary = summary
+	s.current = nil
+	s.last = run
+}
+
+func (s *Server) handleRollback(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	txn, err := s.tm.Load(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("failed to load transaction: %w", err))
+		return
+	}
+	if txn.Status == safety.TransactionStatusRolledBack {
+		writeError(w, http.StatusConflict, errors.New("transaction has already been rolled back"))
+		return
+	}
+
+	// Rolling back while an organize run moves files would race with it
+	s.mu.Lock()
+	switch {
+	case s.current != nil:
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, errors.New("an organize run is in progress"))
+		return
+	case s.rollingBack:
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, errors.New("a rollback is already in progress"))
+		return
+	}
+	s.rollingBack = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.rollingBack = false
+		s.mu.Unlock()
+	}()
+
+	log.Info().Str("transaction", id).Msg("Starting rollback from API")
+	if err := s.tm.Rollback(id); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("rollback failed: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"id":     id,
+		"status": string(safety.TransactionStatusRolledBack),
+	})
+}
+
+// copyRun copies run so it can be encoded outside the lock
+func copyRun(run *Run) *Run {
+	if run == nil {
+		return nil
+	}
+	c := *run
+	return &c
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warn().Err(err).Msg("Failed to write API response")
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
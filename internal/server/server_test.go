@@ -0,0 +1,208 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opd-ai/go-jf-org/internal/notify"
+	"github.com/opd-ai/go-jf-org/internal/safety"
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func newTestServer(t *testing.T, organize OrganizeFunc, token string) (*Server, *safety.TransactionManager) {
+	t.Helper()
+	tm, err := safety.NewTransactionManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTransactionManager() error = %v", err)
+	}
+	return New(tm, organize, token), tm
+}
+
+func serve(t *testing.T, h http.Handler, method, path, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleStatus(t *testing.T) {
+	organize := func(req OrganizeRequest, run *notify.Summary) error {
+		run.Sources = req.Paths
+		run.Organized = 3
+		run.TransactionID = "abc123"
+		return nil
+	}
+	s, _ := newTestServer(t, organize, "")
+	h := s.Handler()
+
+	rec := serve(t, h, http.MethodGet, "/status", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /status code = %d, want 200", rec.Code)
+	}
+	var status Status
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if status.Running || status.Last != nil {
+		t.Errorf("status before any run = %+v, want idle with no last run", status)
+	}
+
+	rec = serve(t, h, http.MethodPost, "/organize", `{"paths":["/media/unsorted"],"dry_run":true}`, "")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /organize code = %d, want 202: %s", rec.Code, rec.Body)
+	}
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+	<-done
+
+	rec = serve(t, h, http.MethodGet, "/status", "", "")
+	status = Status{}
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if status.Running {
+		t.Error("status.Running = true after the run finished")
+	}
+	if status.Last == nil || status.Last.Summary == nil {
+		t.Fatalf("status.Last = %+v, want the finished run", status.Last)
+	}
+	if !status.Last.Request.DryRun || status.Last.Request.Paths[0] != "/media/unsorted" {
+		t.Errorf("Last.Request = %+v, want the posted options", status.Last.Request)
+	}
+	summary := status.Last.Summary
+	if !summary.Success || summary.Organized != 3 || summary.TransactionID != "abc123" {
+		t.Errorf("Last.Summary = %+v, want a successful run of 3 files in abc123", summary)
+	}
+}
+
+func TestHandleOrganize_Invalid(t *testing.T) {
+	s, _ := newTestServer(t, func(OrganizeRequest, *notify.Summary) error { return nil }, "")
+	h := s.Handler()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"malformed", `{"paths":`},
+		{"no paths", `{"dry_run":true}`},
+		{"interactive", `{"paths":["/media"],"conflict":"interactive"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h, http.MethodPost, "/organize", tt.body, "")
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("POST /organize code = %d, want 400", rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandleTransactions(t *testing.T) {
+	s, tm := newTestServer(t, nil, "")
+
+	txn, err := tm.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	op := types.Operation{Type: types.OperationMove, Source: "/a", Destination: "/b", Status: types.OperationStatusCompleted}
+	if err := tm.AddOperation(txn, op); err != nil {
+		t.Fatalf("AddOperation() error = %v", err)
+	}
+	if err := tm.Complete(txn); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	rec := serve(t, s.Handler(), http.MethodGet, "/transactions", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /transactions code = %d, want 200", rec.Code)
+	}
+	var infos []TransactionInfo
+	if err := json.NewDecoder(rec.Body).Decode(&infos); err != nil {
+		t.Fatalf("decode transactions: %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(infos))
+	}
+	if infos[0].ID != txn.ID || infos[0].Status != safety.TransactionStatusCompleted || infos[0].Operations != 1 {
+		t.Errorf("transaction = %+v, want %s completed with 1 operation", infos[0], txn.ID)
+	}
+}
+
+func TestHandleRollback_Unknown(t *testing.T) {
+	s, _ := newTestServer(t, nil, "")
+	rec := serve(t, s.Handler(), http.MethodPost, "/rollback/missing", "", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("POST /rollback/missing code = %d, want 404", rec.Code)
+	}
+}
+
+func TestHandleRollback_DuringOrganize(t *testing.T) {
+	release := make(chan struct{})
+	organize := func(OrganizeRequest, *notify.Summary) error {
+		<-release
+		return nil
+	}
+	s, tm := newTestServer(t, organize, "")
+	h := s.Handler()
+
+	txn, err := tm.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if err := tm.Complete(txn); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	rec := serve(t, h, http.MethodPost, "/organize", `{"paths":["/media/unsorted"]}`, "")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /organize code = %d, want 202", rec.Code)
+	}
+	rec = serve(t, h, http.MethodPost, "/rollback/"+txn.ID, "", "")
+	if rec.Code != http.StatusConflict {
+		t.Errorf("POST /rollback during an organize run code = %d, want 409", rec.Code)
+	}
+
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+	close(release)
+	<-done
+
+	rec = serve(t, h, http.MethodPost, "/rollback/"+txn.ID, "", "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("POST /rollback after the run code = %d, want 200: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestHandler_Token(t *testing.T) {
+	s, _ := newTestServer(t, nil, "s3cret")
+	h := s.Handler()
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong", "guess", http.StatusUnauthorized},
+		{"valid", "s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, path := range []string{"/status", "/transactions"} {
+				rec := serve(t, h, http.MethodGet, path, "", tt.token)
+				if rec.Code != tt.want {
+					t.Errorf("GET %s code = %d, want %d", path, rec.Code, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -8,8 +8,9 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// EnricherFunc is a function that enriches metadata for a single file
-type EnricherFunc func(*types.Metadata) error
+// EnricherFunc is a function that enriches metadata for a single file; it
+// should give up once ctx is done
+type EnricherFunc func(ctx context.Context, meta *types.Metadata) error
 
 // EnrichmentResult represents the result of enriching a single file
 type EnrichmentResult struct {
@@ -96,7 +97,7 @@ func (ce *ConcurrentEnricher) worker(ctx context.Context, wg *sync.WaitGroup, jo
 			}
 
 			metadata := metadataList[idx]
-			err := enricher(metadata)
+			err := enricher(ctx, metadata)
 
 			result := EnrichmentResult{
 				Index:    idx,
@@ -184,7 +185,7 @@ func (ce *ConcurrentEnricher) workerWithProgress(ctx context.Context, wg *sync.W
 			}
 
 			metadata := metadataList[idx]
-			err := enricher(metadata)
+			err := enricher(ctx, metadata)
 
 			if err != nil {
 				log.Debug().Err(err).Int("index", idx).Msg("Enrichment error")
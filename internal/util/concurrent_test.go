@@ -3,6 +3,7 @@ package util
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -57,7 +58,7 @@ func TestConcurrentEnricher_EnrichBatch(t *testing.T) {
 			enricher := NewConcurrentEnricher(tt.numWorkers)
 
 			// Define enrichment function
-			enrichFunc := func(m *types.Metadata) error {
+			enrichFunc := func(_ context.Context, m *types.Metadata) error {
 				// Simulate enrichment by adding to year
 				m.Year += 1000
 				return nil
@@ -102,7 +103,7 @@ func TestConcurrentEnricher_EnrichBatchWithErrors(t *testing.T) {
 	enricher := NewConcurrentEnricher(4)
 
 	// Enrichment function that fails on even indices
-	enrichFunc := func(m *types.Metadata) error {
+	enrichFunc := func(_ context.Context, m *types.Metadata) error {
 		if m.Year%2 == 0 {
 			return errors.New("even year error")
 		}
@@ -149,7 +150,7 @@ func TestConcurrentEnricher_ContextCancellation(t *testing.T) {
 	enricher := NewConcurrentEnricher(4)
 
 	// Enrichment function that takes time
-	enrichFunc := func(m *types.Metadata) error {
+	enrichFunc := func(_ context.Context, m *types.Metadata) error {
 		time.Sleep(10 * time.Millisecond)
 		m.Year += 1000
 		return nil
@@ -190,7 +191,7 @@ func TestConcurrentEnricher_WithProgress(t *testing.T) {
 	enricher := NewConcurrentEnricher(4)
 	progress := NewProgressTracker(0, "Enriching")
 
-	enrichFunc := func(m *types.Metadata) error {
+	enrichFunc := func(_ context.Context, m *types.Metadata) error {
 		m.Year += 1000
 		return nil
 	}
@@ -250,7 +251,7 @@ func benchmarkEnricher(b *testing.B, numWorkers int) {
 	}
 
 	enricher := NewConcurrentEnricher(numWorkers)
-	enrichFunc := func(m *types.Metadata) error {
+	enrichFunc := func(_ context.Context, m *types.Metadata) error {
 		// Simulate some work
 		time.Sleep(100 * time.Microsecond)
 		m.Year += 1000
@@ -264,3 +265,34 @@ func benchmarkEnricher(b *testing.B, numWorkers int) {
 		_, _ = enricher.EnrichBatch(ctx, metadataList, enrichFunc)
 	}
 }
+
+func TestConcurrentEnricher_RespectsWorkerCount(t *testing.T) {
+	for _, workers := range []int{1, 2, 6} {
+		metadataList := make([]*types.Metadata, 18)
+		for i := range metadataList {
+			metadataList[i] = &types.Metadata{Title: "Test"}
+		}
+
+		var active, peak int32
+		enrichFunc := func(_ context.Context, m *types.Metadata) error {
+			n := atomic.AddInt32(&active, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			return nil
+		}
+
+		NewConcurrentEnricher(workers).EnrichWithProgress(context.Background(), metadataList, enrichFunc, nil)
+
+		if got := int(atomic.LoadInt32(&peak)); got > workers {
+			t.Errorf("%d workers ran %d enrichments at once", workers, got)
+		} else if workers > 1 && got < 2 {
+			t.Errorf("%d workers never overlapped enrichments", workers)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+// ErrEnrichTimeout reports that enriching a file outlasted its per-item
+// timeout; the file keeps the metadata it had before the lookup
+var ErrEnrichTimeout = errors.New("enrichment timed out")
+
+// EnrichWithTimeout runs enricher on a copy of meta and copies the result
+// back when it finishes within timeout. A lookup still running at the
+// deadline is abandoned: meta is left untouched and an error wrapping
+// ErrEnrichTimeout is returned, so the file proceeds with the metadata
+// parsed from its name. enricher receives a context that is cancelled at
+// the deadline, so its in-flight API requests are cancelled with it. A
+// timeout of zero or less runs enricher directly.
+func EnrichWithTimeout(ctx context.Context, timeout time.Duration, meta *types.Metadata, enricher EnricherFunc) error {
+	if timeout <= 0 || meta == nil {
+		return enricher(ctx, meta)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	work := CloneMetadata(meta)
+	done := make(chan error, 1)
+	go func() {
+		done <- enricher(ctx, work)
+	}()
+
+	select {
+	case err := <-done:
+		*meta = *work
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s", ErrEnrichTimeout, timeout)
+		}
+		return ctx.Err()
+	}
+}
+
+// CloneMetadata returns a deep copy of meta, so an enrichment running on
+// the copy cannot change meta
+func CloneMetadata(meta *types.Metadata) *types.Metadata {
+	if meta == nil {
+		return nil
+	}
+	clone := *meta
+	if meta.MovieMetadata != nil {
+		movie := *meta.MovieMetadata
+		movie.Director = slices.Clone(movie.Director)
+		movie.Cast = slices.Clone(movie.Cast)
+		movie.Actors = slices.Clone(movie.Actors)
+		movie.Genres = slices.Clone(movie.Genres)
+		movie.Tags = slices.Clone(movie.Tags)
+		clone.MovieMetadata = &movie
+	}
+	if meta.TVMetadata != nil {
+		tv := *meta.TVMetadata
+		tv.Genres = slices.Clone(tv.Genres)
+		tv.Actors = slices.Clone(tv.Actors)
+		tv.Tags = slices.Clone(tv.Tags)
+		clone.TVMetadata = &tv
+	}
+	if meta.MusicMetadata != nil {
+		music := *meta.MusicMetadata
+		clone.MusicMetadata = &music
+	}
+	if meta.BookMetadata != nil {
+		book := *meta.BookMetadata
+		clone.BookMetadata = &book
+	}
+	return &clone
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opd-ai/go-jf-org/pkg/types"
+)
+
+func TestEnrichWithTimeout(t *testing.T) {
+	meta := &types.Metadata{Title: "parsed", MovieMetadata: &types.MovieMetadata{Tags: []string{"a"}}}
+
+	err := EnrichWithTimeout(context.Background(), time.Second, meta, func(_ context.Context, m *types.Metadata) error {
+		m.Title = "enriched"
+		m.MovieMetadata.Tags = append(m.MovieMetadata.Tags, "b")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EnrichWithTimeout() error = %v", err)
+	}
+	if meta.Title != "enriched" || len(meta.MovieMetadata.Tags) != 2 {
+		t.Errorf("finished enrichment not applied: %+v", meta)
+	}
+
+	cancelled := make(chan error, 1)
+	err = EnrichWithTimeout(context.Background(), 20*time.Millisecond, meta, func(ctx context.Context, m *types.Metadata) error {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		m.Title = "late"
+		return ctx.Err()
+	})
+	if !errors.Is(err, ErrEnrichTimeout) {
+		t.Errorf("EnrichWithTimeout() error = %v, want ErrEnrichTimeout", err)
+	}
+	if meta.Title != "enriched" {
+		t.Errorf("Title = %q, want the abandoned lookup to leave it alone", meta.Title)
+	}
+	select {
+	case err := <-cancelled:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("enricher context error = %v, want DeadlineExceeded", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("enricher context was not cancelled at the deadline")
+	}
+}
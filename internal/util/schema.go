@@ -0,0 +1,8 @@
+package util
+
+// JSONSchemaVersion is the version of the machine-readable output contract
+// shared by every JSON report (stats, preview, verify, duplicates). It is
+// emitted as the top-level "schema_version" field and must be incremented
+// whenever a field is renamed, removed or changes type; adding a field does
+// not require a bump. The shapes are documented in docs/json-output.md.
+const JSONSchemaVersion = 1
@@ -3,8 +3,13 @@ package util
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"os"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/rs/zerolog/log"
 )
 
 // Statistics tracks operation statistics and metrics
@@ -16,6 +21,11 @@ type Statistics struct {
 	Sizes     map[string]int64         `json:"sizes_bytes"`
 	Timings   map[string]time.Duration `json:"timings_ms"`
 	mu        sync.RWMutex
+	// updates counts counter and size changes for a flusher that writes
+	// every flushEvery of them, signalled through flushNow
+	updates    int
+	flushEvery int
+	flushNow   chan struct{}
 }
 
 // NewStatistics creates a new statistics tracker
@@ -38,6 +48,7 @@ func (s *Statistics) Add(name string, value int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.Counters[name] += value
+	s.noteUpdate()
 }
 
 // Get returns the value of a counter
@@ -52,6 +63,22 @@ func (s *Statistics) AddSize(name string, bytes int64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.Sizes[name] += bytes
+	s.noteUpdate()
+}
+
+// noteUpdate signals a running flusher every flushEvery updates; the caller
+// holds s.mu
+func (s *Statistics) noteUpdate() {
+	if s.flushEvery <= 0 {
+		return
+	}
+	s.updates++
+	if s.updates%s.flushEvery == 0 {
+		select {
+		case s.flushNow <- struct{}{}:
+		default: // a flush is already pending
+		}
+	}
 }
 
 // GetSize returns the value of a size counter
@@ -83,33 +110,171 @@ func (s *Statistics) Finish() {
 	s.Duration = s.EndTime.Sub(s.StartTime)
 }
 
-// ToJSON converts statistics to JSON format
-func (s *Statistics) ToJSON() (string, error) {
+// StatsReport is the versioned, machine-readable form of Statistics
+type StatsReport struct {
+	SchemaVersion int              `json:"schema_version"`
+	StartTime     string           `json:"start_time"`
+	EndTime       string           `json:"end_time"`
+	Duration      int64            `json:"duration_ms"`
+	Counters      map[string]int   `json:"counters"`
+	Sizes         map[string]int64 `json:"sizes_bytes"`
+	Timings       map[string]int64 `json:"timings_ms"`
+	// Partial marks a snapshot taken before the run finished
+	Partial bool `json:"partial,omitempty"`
+}
+
+// Rows lists the duration, then counters, sizes and timings by name, for
+// text and table output
+func (r StatsReport) Rows() ([]string, [][]string) {
+	rows := [][]string{{"duration", FormatDuration(time.Duration(r.Duration) * time.Millisecond)}}
+	for _, name := range sortedKeys(r.Counters) {
+		rows = append(rows, []string{name, fmt.Sprintf("%d", r.Counters[name])})
+	}
+	for _, name := range sortedKeys(r.Sizes) {
+		rows = append(rows, []string{name, FormatBytes(r.Sizes[name])})
+	}
+	for _, name := range sortedKeys(r.Timings) {
+		rows = append(rows, []string{name, FormatDuration(time.Duration(r.Timings[name]) * time.Millisecond)})
+	}
+	return []string{"metric", "value"}, rows
+}
+
+// sortedKeys returns a map's keys in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Report returns a snapshot of the statistics as a StatsReport
+func (s *Statistics) Report() StatsReport {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Convert to JSON-friendly format
-	data := struct {
-		StartTime string           `json:"start_time"`
-		EndTime   string           `json:"end_time"`
-		Duration  int64            `json:"duration_ms"`
-		Counters  map[string]int   `json:"counters"`
-		Sizes     map[string]int64 `json:"sizes_bytes"`
-		Timings   map[string]int64 `json:"timings_ms"`
-	}{
-		StartTime: s.StartTime.Format(time.RFC3339),
-		EndTime:   s.EndTime.Format(time.RFC3339),
-		Duration:  s.Duration.Milliseconds(),
-		Counters:  s.Counters,
-		Sizes:     s.Sizes,
-		Timings:   make(map[string]int64),
+	report := StatsReport{
+		SchemaVersion: JSONSchemaVersion,
+		StartTime:     s.StartTime.Format(time.RFC3339),
+		EndTime:       s.EndTime.Format(time.RFC3339),
+		Duration:      s.Duration.Milliseconds(),
+		Counters:      make(map[string]int, len(s.Counters)),
+		Sizes:         make(map[string]int64, len(s.Sizes)),
+		Timings:       make(map[string]int64, len(s.Timings)),
+	}
+	for k, v := range s.Counters {
+		report.Counters[k] = v
+	}
+	for k, v := range s.Sizes {
+		report.Sizes[k] = v
 	}
-
 	for k, v := range s.Timings {
-		data.Timings[k] = v.Milliseconds()
+		report.Timings[k] = v.Milliseconds()
+	}
+	return report
+}
+
+// Snapshot returns the statistics so far: the Report once Finish has been
+// called, otherwise a Partial report ending now
+func (s *Statistics) Snapshot() StatsReport {
+	report := s.Report()
+	if report.Partial = s.endTime().IsZero(); report.Partial {
+		now := time.Now()
+		report.EndTime = now.Format(time.RFC3339)
+		report.Duration = now.Sub(s.StartTime).Milliseconds()
+	}
+	return report
+}
+
+// endTime returns EndTime under the lock
+func (s *Statistics) endTime() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.EndTime
+}
+
+// StatsFlusher writes snapshots of a Statistics to a file while a run is in
+// progress, so a run that dies before Finish still leaves partial stats
+type StatsFlusher struct {
+	stats *Statistics
+	path  string
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+	err   error
+}
+
+// StartFlusher writes a Snapshot to path every interval and, when every is
+// positive, after every that many counter or size updates. Either may be
+// zero to disable it. Stop the flusher after Finish to write the final
+// report.
+func (s *Statistics) StartFlusher(path string, interval time.Duration, every int) *StatsFlusher {
+	s.mu.Lock()
+	s.flushEvery = every
+	s.flushNow = make(chan struct{}, 1)
+	s.mu.Unlock()
+
+	f := &StatsFlusher{stats: s, path: path, stop: make(chan struct{}), done: make(chan struct{})}
+	var tick <-chan time.Time
+	var ticker *time.Ticker
+	if interval > 0 {
+		ticker = time.NewTicker(interval)
+		tick = ticker.C
+	}
+
+	go func() {
+		defer close(f.done)
+		if ticker != nil {
+			defer ticker.Stop()
+		}
+		for {
+			select {
+			case <-tick:
+			case <-s.flushNow:
+			case <-f.stop:
+				return
+			}
+			if err := f.write(); err != nil {
+				log.Debug().Err(err).Str("file", path).Msg("Failed to write partial statistics")
+			}
+		}
+	}()
+	return f
+}
+
+// Stop ends the flusher and writes a last snapshot, which is the final
+// report if Finish has been called. It is safe to call more than once and
+// on a nil flusher.
+func (f *StatsFlusher) Stop() error {
+	if f == nil {
+		return nil
+	}
+	f.once.Do(func() {
+		close(f.stop)
+		<-f.done
+		f.err = f.write()
+	})
+	return f.err
+}
+
+// write replaces the file with the current snapshot atomically, so a reader
+// (or a crash) never sees it half-written
+func (f *StatsFlusher) write() error {
+	data, err := json.MarshalIndent(f.stats.Snapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
 	}
+	return os.Rename(tmp, f.path)
+}
 
-	bytes, err := json.MarshalIndent(data, "", "  ")
+// ToJSON converts statistics to JSON format
+func (s *Statistics) ToJSON() (string, error) {
+	bytes, err := json.MarshalIndent(s.Report(), "", "  ")
 	if err != nil {
 		return "", err
 	}
@@ -194,6 +359,27 @@ func (t *Timer) Stop() {
 	t.stats.AddTiming(t.name, duration)
 }
 
+// Percentile returns the p-th percentile (0-100) of samples using the
+// nearest-rank method, or 0 when there are no samples. samples is not modified.
+func Percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
 // OperationStats tracks statistics for a specific operation
 type OperationStats struct {
 	Name           string
@@ -1,7 +1,11 @@
 package util
 
 import (
+	"bytes"
 	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -70,6 +74,43 @@ func TestStatistics_Timer(t *testing.T) {
 	}
 }
 
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/")
+
+// TestStatistics_ToJSON_Golden locks the stats JSON shape; if a change is
+// deliberate, bump JSONSchemaVersion when needed and rerun with -update
+func TestStatistics_ToJSON_Golden(t *testing.T) {
+	stats := NewStatistics()
+	stats.StartTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	stats.EndTime = stats.StartTime.Add(1500 * time.Millisecond)
+	stats.Duration = 1500 * time.Millisecond
+	stats.Add("files_moved", 3)
+	stats.AddSize("total", 4096)
+	stats.AddTiming("scan", 250*time.Millisecond)
+
+	got, err := stats.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	path := filepath.Join("testdata", "stats.golden")
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal([]byte(got+"\n"), want) {
+		t.Errorf("stats JSON changed:\n got: %s\nwant: %s", got, want)
+	}
+}
+
 func TestStatistics_ToJSON(t *testing.T) {
 	stats := NewStatistics()
 	stats.Increment("files")
@@ -89,6 +130,9 @@ func TestStatistics_ToJSON(t *testing.T) {
 	}
 
 	// Check for expected fields
+	if data["schema_version"] != float64(JSONSchemaVersion) {
+		t.Errorf("expected schema_version %d, got %v", JSONSchemaVersion, data["schema_version"])
+	}
 	if _, ok := data["counters"]; !ok {
 		t.Error("expected 'counters' in JSON output")
 	}
@@ -156,6 +200,79 @@ func TestStatistics_Concurrent(t *testing.T) {
 	}
 }
 
+func TestStatistics_Flusher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	stats := NewStatistics()
+	flusher := stats.StartFlusher(path, time.Hour, 2)
+	defer flusher.Stop()
+
+	read := func() StatsReport {
+		t.Helper()
+		var report StatsReport
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read stats file: %v", err)
+		}
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatalf("parse stats file: %v", err)
+		}
+		return report
+	}
+
+	stats.Add("files_organized", 3)
+	stats.AddSize("total_bytes", 1024)
+
+	// The second update triggers a flush; wait for it mid-run
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no partial stats file written mid-run")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	partial := read()
+	if !partial.Partial {
+		t.Error("mid-run snapshot should be marked partial")
+	}
+	if partial.Counters["files_organized"] != 3 || partial.Sizes["total_bytes"] != 1024 {
+		t.Errorf("partial snapshot = %+v, want the updates so far", partial)
+	}
+
+	stats.Increment("files_failed")
+	stats.Finish()
+	if err := flusher.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	final := read()
+	if final.Partial {
+		t.Error("final report should not be marked partial")
+	}
+	if final.Counters["files_failed"] != 1 || final.Duration != stats.Duration.Milliseconds() {
+		t.Errorf("final report = %+v, want the finished statistics", final)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file left behind")
+	}
+}
+
+func TestStatistics_FlusherInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	stats := NewStatistics()
+	flusher := stats.StartFlusher(path, 20*time.Millisecond, 0)
+	defer flusher.Stop()
+
+	stats.Increment("files_scanned")
+	time.Sleep(200 * time.Millisecond)
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("interval flush did not write the stats file: %v", err)
+	}
+}
+
 func TestOperationStats_Basic(t *testing.T) {
 	ops := NewOperationStats("Test Operation", 100)
 
@@ -345,3 +462,31 @@ func BenchmarkStatistics_Timer(b *testing.B) {
 		timer.Stop()
 	}
 }
+
+func TestPercentile(t *testing.T) {
+	samples := []time.Duration{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, 1},
+		{50, 5},
+		{95, 10},
+		{90, 9},
+		{100, 10},
+	}
+
+	for _, tt := range tests {
+		if got := Percentile(samples, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+	if samples[0] != 5 {
+		t.Error("Percentile() must not reorder its input")
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil) = %v, want 0", got)
+	}
+}
@@ -1,6 +1,11 @@
 package util
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
 // RemoveExtension removes the file extension from a filename
 func RemoveExtension(filename string) string {
@@ -18,6 +23,67 @@ func CleanTitle(title string) string {
 	return strings.TrimSpace(title)
 }
 
+// romanNumeralPattern matches roman numerals up to 39 ("ii", "iv", "xii"),
+// the range used for sequels and parts; longer ones are mostly real words
+var romanNumeralPattern = regexp.MustCompile(`^(?i)x{0,3}(ix|iv|v?i{0,3})$`)
+
+// TitleCase capitalizes a title word by word ("the lord of the rings" ->
+// "The Lord of the Rings"). smallWords stay lowercase unless they start or
+// end the title or follow a colon or dash; acronyms and roman numerals are
+// written in capitals ("fbi", "iii" -> "FBI", "III"). Matching ignores case,
+// and hyphenated words are cased part by part ("spider-man" -> "Spider-Man").
+func TitleCase(title string, smallWords, acronyms []string) string {
+	small := make(map[string]bool, len(smallWords))
+	for _, w := range smallWords {
+		small[strings.ToLower(w)] = true
+	}
+	upper := make(map[string]bool, len(acronyms))
+	for _, w := range acronyms {
+		upper[strings.ToLower(w)] = true
+	}
+
+	words := strings.Fields(title)
+	startsPhrase := true
+	for i, word := range words {
+		parts := strings.Split(word, "-")
+		for j, part := range parts {
+			capitalize := startsPhrase || i == len(words)-1 || j > 0
+			parts[j] = titleCaseWord(part, capitalize, small, upper)
+		}
+		words[i] = strings.Join(parts, "-")
+		startsPhrase = word == "-" || strings.HasSuffix(word, ":")
+	}
+	return strings.Join(words, " ")
+}
+
+// titleCaseWord cases one word, keeping leading and trailing punctuation
+// ("(part", "ii)") out of the acronym and small word lookups
+func titleCaseWord(word string, capitalize bool, small, upper map[string]bool) string {
+	start := strings.IndexFunc(word, isWordRune)
+	if start < 0 {
+		return word
+	}
+	end := strings.LastIndexFunc(word, isWordRune)
+	_, size := utf8.DecodeRuneInString(word[end:])
+	end += size
+	core := strings.ToLower(word[start:end])
+
+	switch {
+	case upper[core] || romanNumeralPattern.MatchString(core):
+		core = strings.ToUpper(core)
+	case small[core] && !capitalize:
+	default:
+		r, size := utf8.DecodeRuneInString(core)
+		core = string(unicode.ToUpper(r)) + core[size:]
+	}
+	return word[:start] + core + word[end:]
+}
+
+// isWordRune reports whether r is part of a word rather than punctuation
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
 // ContainsExtension checks if ext is in the provided extensions slice (case-insensitive)
 func ContainsExtension(extensions []string, ext string) bool {
 	ext = strings.ToLower(ext)
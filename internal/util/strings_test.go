@@ -49,6 +49,40 @@ func TestCleanTitle(t *testing.T) {
 	}
 }
 
+func TestTitleCase(t *testing.T) {
+	small := []string{"a", "an", "and", "of", "the", "in", "to", "vs"}
+	acronyms := []string{"USA", "FBI", "NYC"}
+
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"small words stay lowercase", "the lord of the rings", "The Lord of the Rings"},
+		{"small word first and last", "a walk to remember the", "A Walk to Remember The"},
+		{"small word after colon", "star wars: a new hope", "Star Wars: A New Hope"},
+		{"small word after dash", "mission impossible - the final reckoning", "Mission Impossible - The Final Reckoning"},
+		{"acronyms", "the fbi files in nyc", "The FBI Files in NYC"},
+		{"acronym with punctuation", "born in the usa!", "Born in the USA!"},
+		{"roman numerals", "rocky ii", "Rocky II"},
+		{"roman numeral mid-title", "part iv of the saga", "Part IV of the Saga"},
+		{"roman-looking word kept", "the mix", "The Mix"},
+		{"hyphenated", "spider-man vs the world", "Spider-Man vs the World"},
+		{"apostrophe", "ocean's eleven", "Ocean's Eleven"},
+		{"parentheses", "alien (director's cut)", "Alien (Director's Cut)"},
+		{"unicode", "amélie of montmartre", "Amélie of Montmartre"},
+		{"numbers", "12 angry men", "12 Angry Men"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TitleCase(tt.title, small, acronyms); got != tt.want {
+				t.Errorf("TitleCase(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestContainsExtension(t *testing.T) {
 	extensions := []string{".mkv", ".mp4", ".avi"}
 
@@ -0,0 +1,132 @@
+package verifier
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/opd-ai/go-jf-org/internal/detector"
+)
+
+// Container is a video container format identified from a file's magic bytes
+type Container string
+
+const (
+	ContainerMatroska Container = "matroska"
+	ContainerWebM     Container = "webm"
+	ContainerMP4      Container = "mp4"
+	ContainerAVI      Container = "avi"
+	ContainerMPEGTS   Container = "mpeg-ts"
+	ContainerUnknown  Container = ""
+)
+
+// containerExtensions maps each container to the extensions it may carry
+var containerExtensions = map[Container][]string{
+	ContainerMatroska: {".mkv"},
+	ContainerWebM:     {".webm", ".mkv"},
+	ContainerMP4:      {".mp4", ".m4v", ".mov"},
+	ContainerAVI:      {".avi"},
+	ContainerMPEGTS:   {".ts"},
+}
+
+// extensionContainers maps each checked extension to the container it implies
+var extensionContainers = map[string]Container{
+	".mkv":  ContainerMatroska,
+	".webm": ContainerWebM,
+	".mp4":  ContainerMP4,
+	".m4v":  ContainerMP4,
+	".mov":  ContainerMP4,
+	".avi":  ContainerAVI,
+	".ts":   ContainerMPEGTS,
+}
+
+// mpegTSPacketSize is the length of an MPEG transport stream packet
+const mpegTSPacketSize = 188
+
+// DetectContainer identifies a video container from the start of a file.
+// Unrecognized data returns ContainerUnknown.
+func DetectContainer(header []byte) Container {
+	switch {
+	case bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		// EBML; the DocType element names the flavor
+		if bytes.Contains(header, []byte("webm")) {
+			return ContainerWebM
+		}
+		return ContainerMatroska
+	case len(header) >= 8 && string(header[4:8]) == "ftyp":
+		return ContainerMP4
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "AVI ":
+		return ContainerAVI
+	case len(header) > mpegTSPacketSize && header[0] == 0x47 && header[mpegTSPacketSize] == 0x47:
+		return ContainerMPEGTS
+	}
+	return ContainerUnknown
+}
+
+// checkContainer reads a video file's header and returns a warning when the
+// container does not match its extension, or nil when it matches or cannot
+// be determined
+func checkContainer(path string) *Violation {
+	ext := strings.ToLower(filepath.Ext(path))
+	if _, ok := extensionContainers[ext]; !ok {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Debug().Err(err).Str("path", path).Msg("Cannot open file to check container")
+		return nil
+	}
+	defer f.Close()
+
+	header := make([]byte, 2*mpegTSPacketSize)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil
+	}
+
+	container := DetectContainer(header[:n])
+	if container == ContainerUnknown {
+		return nil
+	}
+	for _, allowed := range containerExtensions[container] {
+		if ext == allowed {
+			return nil
+		}
+	}
+
+	name := filepath.Base(path)
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	return &Violation{
+		Severity: SeverityWarning,
+		Path:     path,
+		Message:  fmt.Sprintf("File extension %s does not match its %s container", ext, container),
+		Suggestion: fmt.Sprintf("Rename to %s, or remux to match the extension: ffmpeg -i %q -c copy %q",
+			stem+containerExtensions[container][0], name, stem+".remux"+ext),
+		MediaType: detector.New().Detect(name),
+	}
+}
+
+// verifyContainers checks the container of every video file under rootPath
+func verifyContainers(rootPath string) []Violation {
+	violations := []Violation{}
+	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if v := checkContainer(path); v != nil {
+			violations = append(violations, *v)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("path", rootPath).Msg("Failed to walk directory for container checks")
+	}
+	return violations
+}
@@ -5,8 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/opd-ai/go-jf-org/internal/jellyfin"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
@@ -20,22 +22,147 @@ const (
 
 // Violation represents a single verification rule violation
 type Violation struct {
-	Severity   Severity
-	Path       string
-	Message    string
-	Suggestion string
-	MediaType  types.MediaType
+	Severity   Severity        `json:"severity"`
+	Path       string          `json:"path"`
+	Message    string          `json:"message"`
+	Suggestion string          `json:"suggestion,omitempty"`
+	MediaType  types.MediaType `json:"media_type,omitempty"`
 }
 
+// providerIDSuffix matches the optional " [tmdbid-603]" / " [imdbid-tt0133093]"
+// token that may follow the year in movie and show names
+const providerIDSuffix = `(?:\s+\[(?:tmdbid|imdbid)-\w+\])?`
+
 // Common regex patterns compiled once for performance
 var (
-	yearPattern    = regexp.MustCompile(`^(.+?)\s+\((\d{4})\)$`)
-	seasonPattern  = regexp.MustCompile(`^Season\s+(\d{2})$`)
-	episodePattern = regexp.MustCompile(`^(.+?)\s+-\s+S(\d{2})E(\d{2})(?:\s+-\s+(.+?))?(?:\s+-\s+\d{3,4}p)?\.(.+)$`)
+	yearPattern   = regexp.MustCompile(`^(.+?)\s+\((\d{4})\)` + providerIDSuffix + `$`)
+	seasonPattern = regexp.MustCompile(`^Season\s+(\d{2}|\d{4})$`)
+	// Article-sorted directory name: "Matrix, The (1999)"
+	sortedArticlePattern = regexp.MustCompile(`^(.+), (\S+) (\(\d{4}\)` + providerIDSuffix + `)$`)
 )
 
+// filePatterns match generated file names, which follow the configured
+// naming.separator and naming.case; folder names do not
+type filePatterns struct {
+	style jellyfin.FilenameStyle
+	// "Show - S01E02 - Title.ext"
+	episode *regexp.Regexp
+	// Daily shows are named by air date: "Show - 2023-05-15 - Title.ext"
+	dailyEpisode *regexp.Regexp
+	// Flat layout movie file (without extension): "Movie Name (Year)" with
+	// optional " - suffix"
+	flatMovie *regexp.Regexp
+}
+
+// defaultFilePatterns match the default Jellyfin style
+var defaultFilePatterns = newFilePatterns(jellyfin.FilenameStyle{})
+
+// newFilePatterns compiles the file name patterns for a filename style
+func newFilePatterns(style jellyfin.FilenameStyle) *filePatterns {
+	// word separates words in a title, part separates "Name - S01E02 - Title"
+	word, part := `\s+`, `\s+-\s+`
+	switch style.Separator {
+	case jellyfin.SeparatorDot:
+		word, part = `\.`, `\.`
+	case jellyfin.SeparatorUnderscore:
+		word, part = `_`, `_`
+	}
+	var flags string
+	if style.Case == jellyfin.CaseLower {
+		flags = `(?i)`
+	}
+	providerID := `(?:` + word + `\[(?:tmdbid|imdbid)-\w+\])?`
+
+	return &filePatterns{
+		style:        style,
+		episode:      regexp.MustCompile(flags + `^(.+?)` + part + `S(\d{2})E(\d{2})(?:-E\d{2,})?(?:` + part + `(.+?))?(?:` + part + `\d{3,4}p)?\.(.+)$`),
+		dailyEpisode: regexp.MustCompile(flags + `^(.+?)` + part + `(\d{4}-\d{2}-\d{2})(?:` + part + `(.+?))?\.(.+)$`),
+		flatMovie:    regexp.MustCompile(flags + `^(.+?)` + word + `\((\d{4})\)` + providerID + `(?:` + part + `.+)?$`),
+	}
+}
+
+// orDefault returns p, or the default style's patterns when none were set
+func (p *filePatterns) orDefault() *filePatterns {
+	if p == nil {
+		return defaultFilePatterns
+	}
+	return p
+}
+
+// unsortArticle restores an article-sorted directory name ("Matrix, The (1999)")
+// to its display form ("The Matrix (1999)"); other names are returned unchanged
+func unsortArticle(dirName string) string {
+	m := sortedArticlePattern.FindStringSubmatch(dirName)
+	if m == nil {
+		return dirName
+	}
+	return fmt.Sprintf("%s %s %s", m[2], m[1], m[3])
+}
+
+// movieVideoExtensions lists video extensions checked by the movie rules
+var movieVideoExtensions = map[string]bool{
+	".mkv": true, ".mp4": true, ".avi": true,
+	".m4v": true, ".ts": true, ".webm": true,
+}
+
+// sidecarExtensions lists the NFO and artwork files Jellyfin reads next to
+// media; without that media they are orphans
+var sidecarExtensions = map[string]bool{
+	".nfo": true, ".jpg": true, ".jpeg": true, ".png": true,
+	".webp": true, ".gif": true, ".tbn": true,
+}
+
+// episodeSidecarPattern matches an episode's own NFO or thumbnail stem
+// ("Show - S01E02 - Title", "Show - S01E02 - Title-thumb")
+var episodeSidecarPattern = regexp.MustCompile(`(?i)S\d{2}E\d{2}`)
+
+// orphanedSidecars flags NFO and artwork files in dir that have no media to
+// describe: all of them when dir holds no video, otherwise only episode
+// sidecars whose video is gone. Jellyfin picks up stray metadata as phantom
+// items or applies it to the wrong one.
+func orphanedSidecars(dir string, entries []os.DirEntry, videoFiles []string, mediaType types.MediaType) []Violation {
+	videoStems := make(map[string]bool, len(videoFiles))
+	for _, name := range videoFiles {
+		videoStems[strings.TrimSuffix(name, filepath.Ext(name))] = true
+	}
+
+	var violations []Violation
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !sidecarExtensions[strings.ToLower(filepath.Ext(name))] {
+			continue
+		}
+		if len(videoFiles) > 0 {
+			stem := strings.TrimSuffix(strings.TrimSuffix(name, filepath.Ext(name)), "-thumb")
+			if !episodeSidecarPattern.MatchString(stem) || videoStems[stem] {
+				continue
+			}
+		}
+		violations = append(violations, Violation{
+			Severity:   SeverityWarning,
+			Path:       filepath.Join(dir, name),
+			MediaType:  mediaType,
+			Message:    fmt.Sprintf("Orphaned metadata file with no matching video: %s", name),
+			Suggestion: "Remove it, or restore the video it belongs to",
+		})
+	}
+	return violations
+}
+
+// looseTrackDirs are the buckets used for tracks without an album
+// (organize.loose_track_layout); they carry no year
+var looseTrackDirs = map[string]bool{
+	"Singles":      true,
+	"Compilations": true,
+}
+
 // MovieRules contains verification rules for movie directories
-type MovieRules struct{}
+type MovieRules struct {
+	// extrasDirs are subfolder names accepted as Jellyfin extras
+	extrasDirs []string
+	// files match video file names; nil means the default style
+	files *filePatterns
+}
 
 // VerifyMovie checks if a movie directory follows Jellyfin conventions
 func (r *MovieRules) VerifyMovie(dirPath string) []Violation {
@@ -44,6 +171,11 @@ func (r *MovieRules) VerifyMovie(dirPath string) []Violation {
 	// Extract directory name
 	dirName := filepath.Base(dirPath)
 
+	// A library root holding "Movie Name (Year).ext" files directly uses the flat layout
+	if !yearPattern.MatchString(dirName) && r.IsFlatLibrary(dirPath) {
+		return r.VerifyFlatMovies(dirPath)
+	}
+
 	// Check directory naming: "Movie Name (Year)"
 	if !yearPattern.MatchString(dirName) {
 		violations = append(violations, Violation{
@@ -72,23 +204,21 @@ func (r *MovieRules) VerifyMovie(dirPath string) []Violation {
 		return violations
 	}
 
-	videoExtensions := map[string]bool{
-		".mkv": true, ".mp4": true, ".avi": true,
-		".m4v": true, ".ts": true, ".webm": true,
-	}
-
 	var videoFiles []string
 	var hasNFO bool
 
 	for _, entry := range entries {
 		if entry.IsDir() {
-			// Subdirectories are not expected in movie folders
+			if jellyfin.IsExtrasDir(entry.Name(), r.extrasDirs) {
+				continue
+			}
+			// Other subdirectories are not expected in movie folders
 			violations = append(violations, Violation{
 				Severity:   SeverityWarning,
 				Path:       filepath.Join(dirPath, entry.Name()),
 				MediaType:  types.MediaTypeMovie,
 				Message:    "Unexpected subdirectory in movie folder",
-				Suggestion: "Movies should have a flat structure",
+				Suggestion: "Movies should have a flat structure apart from extras folders such as extras/ or trailers/",
 			})
 			continue
 		}
@@ -96,19 +226,21 @@ func (r *MovieRules) VerifyMovie(dirPath string) []Violation {
 		fileName := entry.Name()
 		ext := strings.ToLower(filepath.Ext(fileName))
 
-		if videoExtensions[ext] {
+		if movieVideoExtensions[ext] {
 			videoFiles = append(videoFiles, fileName)
 
 			// Check if video file follows naming convention
 			nameWithoutExt := strings.TrimSuffix(fileName, ext)
 			// Allow optional quality/version suffixes: "Movie Name (Year) - 1080p.mkv"
-			if !strings.HasPrefix(nameWithoutExt, expectedName) {
+			style := r.files.orDefault().style
+			styledName := style.Apply(expectedName)
+			if !strings.HasPrefix(nameWithoutExt, styledName) && !strings.HasPrefix(nameWithoutExt, style.Apply(unsortArticle(expectedName))) {
 				violations = append(violations, Violation{
 					Severity:   SeverityWarning,
 					Path:       filepath.Join(dirPath, fileName),
 					MediaType:  types.MediaTypeMovie,
 					Message:    fmt.Sprintf("Video file name doesn't match directory: %s", fileName),
-					Suggestion: fmt.Sprintf("Rename to: %s%s", expectedName, ext),
+					Suggestion: fmt.Sprintf("Rename to: %s%s", styledName, ext),
 				})
 			}
 		} else if strings.ToLower(fileName) == "movie.nfo" {
@@ -125,6 +257,7 @@ func (r *MovieRules) VerifyMovie(dirPath string) []Violation {
 			Message:    "No video files found in movie directory",
 			Suggestion: "Add a video file or remove empty directory",
 		})
+		violations = append(violations, orphanedSidecars(dirPath, entries, nil, types.MediaTypeMovie)...)
 	}
 
 	// NFO is optional but recommended
@@ -141,8 +274,98 @@ func (r *MovieRules) VerifyMovie(dirPath string) []Violation {
 	return violations
 }
 
+// IsFlatLibrary returns true if the directory holds movie files named
+// "Movie Name (Year).ext" directly, rather than in per-movie folders
+func (r *MovieRules) IsFlatLibrary(dirPath string) bool {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if movieVideoExtensions[ext] && r.files.orDefault().flatMovie.MatchString(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// VerifyFlatMovies checks movie files stored directly in a library root (flat layout)
+// Expected: "Movie Name (Year).ext" with optional "Movie Name (Year).nfo" alongside
+func (r *MovieRules) VerifyFlatMovies(rootPath string) []Violation {
+	violations := []Violation{}
+
+	entries, err := os.ReadDir(rootPath)
+	if err != nil {
+		violations = append(violations, Violation{
+			Severity:   SeverityError,
+			Path:       rootPath,
+			MediaType:  types.MediaTypeMovie,
+			Message:    fmt.Sprintf("Cannot read directory: %v", err),
+			Suggestion: "Check directory permissions",
+		})
+		return violations
+	}
+
+	files := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files[strings.ToLower(entry.Name())] = true
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		fileName := entry.Name()
+		ext := filepath.Ext(fileName)
+		if !movieVideoExtensions[strings.ToLower(ext)] {
+			continue
+		}
+
+		stem := strings.TrimSuffix(fileName, ext)
+		filePath := filepath.Join(rootPath, fileName)
+
+		if !r.files.orDefault().flatMovie.MatchString(stem) {
+			violations = append(violations, Violation{
+				Severity:   SeverityError,
+				Path:       filePath,
+				MediaType:  types.MediaTypeMovie,
+				Message:    fmt.Sprintf("Movie file name does not match Jellyfin convention: %s", fileName),
+				Suggestion: "Rename to format: 'Movie Name (YYYY)" + ext + "'",
+			})
+			continue
+		}
+
+		// NFO is optional but recommended
+		if !files[strings.ToLower(stem+".nfo")] {
+			violations = append(violations, Violation{
+				Severity:   SeverityWarning,
+				Path:       filePath,
+				MediaType:  types.MediaTypeMovie,
+				Message:    fmt.Sprintf("Missing %s.nfo file", stem),
+				Suggestion: "Generate NFO file with: go-jf-org organize --flatten --create-nfo",
+			})
+		}
+	}
+
+	return violations
+}
+
 // TVRules contains verification rules for TV show directories
-type TVRules struct{}
+type TVRules struct {
+	// checkCompleteness warns about missing episodes and seasons
+	checkCompleteness bool
+	// files match episode file names; nil means the default style
+	files *filePatterns
+}
 
 // VerifyTVShow checks if a TV show directory follows Jellyfin conventions
 func (r *TVRules) VerifyTVShow(showPath string) []Violation {
@@ -197,6 +420,11 @@ func (r *TVRules) VerifyTVShow(showPath string) []Violation {
 			Message:    "No season directories found",
 			Suggestion: "Create directories named 'Season 01', 'Season 02', etc.",
 		})
+		violations = append(violations, orphanedSidecars(showPath, entries, nil, types.MediaTypeTV)...)
+	}
+
+	if r.checkCompleteness {
+		violations = append(violations, r.verifyCompleteness(showPath, seasonDirs)...)
 	}
 
 	// NFO is optional but recommended
@@ -213,6 +441,137 @@ func (r *TVRules) VerifyTVShow(showPath string) []Violation {
 	return violations
 }
 
+// episodeNumberPattern captures the season, episode and optional range end
+// of a "S01E02" or "S01E02-E03" episode marker in any filename style ("_"
+// is a word character, so \b would miss "Show_S01E02_Title")
+var episodeNumberPattern = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])S(\d{2})E(\d{2,})(?:-E(\d{2,}))?(?:[^a-z0-9]|$)`)
+
+// verifyCompleteness warns about numbered season directories missing between
+// the lowest and highest present, and about episodes missing from each
+// season between E01 and its highest episode. Specials (Season 00) and
+// date-numbered seasons of daily shows are not checked.
+func (r *TVRules) verifyCompleteness(showPath string, seasonDirs []string) []Violation {
+	violations := []Violation{}
+
+	seasons := make(map[int]bool)
+	for _, dirName := range seasonDirs {
+		m := seasonPattern.FindStringSubmatch(dirName)
+		if m == nil || len(m[1]) != 2 {
+			continue
+		}
+		season, _ := strconv.Atoi(m[1])
+		if season == 0 {
+			continue
+		}
+		seasons[season] = true
+
+		seasonPath := filepath.Join(showPath, dirName)
+		if missing := missingNumbers(seasonEpisodes(seasonPath, season)); len(missing) > 0 {
+			violations = append(violations, Violation{
+				Severity:   SeverityWarning,
+				Path:       seasonPath,
+				MediaType:  types.MediaTypeTV,
+				Message:    fmt.Sprintf("%s is missing episodes: %s", dirName, formatNumberRanges("E", missing)),
+				Suggestion: "Add the missing episodes, or ignore this if they were never released",
+			})
+		}
+	}
+
+	present := make(map[int]bool, len(seasons))
+	lowest := 0
+	for season := range seasons {
+		present[season] = true
+		if lowest == 0 || season < lowest {
+			lowest = season
+		}
+	}
+	var missingSeasons []int
+	for _, number := range missingNumbers(present) {
+		if number > lowest {
+			missingSeasons = append(missingSeasons, number)
+		}
+	}
+	if len(missingSeasons) > 0 {
+		violations = append(violations, Violation{
+			Severity:   SeverityWarning,
+			Path:       showPath,
+			MediaType:  types.MediaTypeTV,
+			Message:    fmt.Sprintf("Missing season directories: %s", formatNumberRanges("Season ", missingSeasons)),
+			Suggestion: "Add the missing seasons to complete the show",
+		})
+	}
+
+	return violations
+}
+
+// seasonEpisodes returns the episode numbers of season found in the file
+// names of seasonPath, expanding multi-episode ranges
+func seasonEpisodes(seasonPath string, season int) map[int]bool {
+	episodes := make(map[int]bool)
+	entries, err := os.ReadDir(seasonPath)
+	if err != nil {
+		return episodes
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !movieVideoExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		m := episodeNumberPattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		if number, _ := strconv.Atoi(m[1]); number != season {
+			continue
+		}
+		first, _ := strconv.Atoi(m[2])
+		last := first
+		if m[3] != "" {
+			last, _ = strconv.Atoi(m[3])
+		}
+		for episode := first; episode <= last; episode++ {
+			episodes[episode] = true
+		}
+	}
+	return episodes
+}
+
+// missingNumbers returns the numbers from 1 to the highest present that are
+// not present, in order
+func missingNumbers(present map[int]bool) []int {
+	highest := 0
+	for number := range present {
+		if number > highest {
+			highest = number
+		}
+	}
+	var missing []int
+	for number := 1; number < highest; number++ {
+		if !present[number] {
+			missing = append(missing, number)
+		}
+	}
+	return missing
+}
+
+// formatNumberRanges formats sorted numbers as prefixed two-digit values,
+// collapsing runs: "E02, E05-E07"
+func formatNumberRanges(prefix string, numbers []int) string {
+	var parts []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		part := fmt.Sprintf("%s%02d", prefix, numbers[i])
+		if j > i {
+			part += fmt.Sprintf("-%s%02d", prefix, numbers[j])
+		}
+		parts = append(parts, part)
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
 // verifySeason checks a single season directory
 func (r *TVRules) verifySeason(seasonPath, showName string) []Violation {
 	violations := []Violation{}
@@ -238,6 +597,7 @@ func (r *TVRules) verifySeason(seasonPath, showName string) []Violation {
 
 	var videoFiles []string
 	var hasSeasonNFO bool
+	files := r.files.orDefault()
 
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -258,7 +618,7 @@ func (r *TVRules) verifySeason(seasonPath, showName string) []Violation {
 			videoFiles = append(videoFiles, fileName)
 
 			// Verify episode naming
-			if !episodePattern.MatchString(fileName) {
+			if !files.episode.MatchString(fileName) && !files.dailyEpisode.MatchString(fileName) {
 				violations = append(violations, Violation{
 					Severity:   SeverityWarning,
 					Path:       filepath.Join(seasonPath, fileName),
@@ -281,6 +641,7 @@ func (r *TVRules) verifySeason(seasonPath, showName string) []Violation {
 			Suggestion: "Add episode files or remove empty season directory",
 		})
 	}
+	violations = append(violations, orphanedSidecars(seasonPath, entries, videoFiles, types.MediaTypeTV)...)
 
 	// Season NFO is optional
 	if !hasSeasonNFO && len(videoFiles) > 0 {
@@ -322,7 +683,7 @@ func (r *MusicRules) VerifyMusic(artistPath string) []Violation {
 	for _, entry := range entries {
 		if entry.IsDir() {
 			dirName := entry.Name()
-			if yearPattern.MatchString(dirName) {
+			if yearPattern.MatchString(dirName) || looseTrackDirs[dirName] {
 				albumDirs = append(albumDirs, dirName)
 				// Could verify album structure, but keeping it simple for now
 			} else {
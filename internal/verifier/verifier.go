@@ -8,6 +8,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/opd-ai/go-jf-org/internal/jellyfin"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
@@ -19,7 +20,10 @@ type Result struct {
 	Violations   []Violation
 	ErrorCount   int
 	WarningCount int
-	MediaCounts  map[types.MediaType]int
+	// MediaCounts counts violations by media type
+	MediaCounts map[types.MediaType]int
+	// ItemCounts counts verified items (movies, shows, artists, authors) by media type
+	ItemCounts map[types.MediaType]int
 }
 
 // Verifier performs structure verification on Jellyfin media directories
@@ -28,21 +32,108 @@ type Verifier struct {
 	tvRules    *TVRules
 	musicRules *MusicRules
 	bookRules  *BookRules
+	// checkContainers enables reading video headers to compare the
+	// container with the file extension
+	checkContainers bool
 }
 
 // NewVerifier creates a new verifier instance
 func NewVerifier() *Verifier {
 	return &Verifier{
-		movieRules: &MovieRules{},
+		movieRules: &MovieRules{extrasDirs: jellyfin.DefaultExtrasDirs},
 		tvRules:    &TVRules{},
 		musicRules: &MusicRules{},
 		bookRules:  &BookRules{},
 	}
 }
 
+// SetExtrasDirs sets the movie subfolder names accepted as extras
+// (default jellyfin.DefaultExtrasDirs)
+func (v *Verifier) SetExtrasDirs(dirs []string) {
+	v.movieRules.extrasDirs = dirs
+}
+
+// SetCheckContainers enables warnings for video files whose container,
+// read from the file's magic bytes, does not match the extension
+func (v *Verifier) SetCheckContainers(enabled bool) {
+	v.checkContainers = enabled
+}
+
+// SetCheckCompleteness enables warnings for TV shows with episodes missing
+// from a season or season directories missing between the present ones
+func (v *Verifier) SetCheckCompleteness(enabled bool) {
+	v.tvRules.checkCompleteness = enabled
+}
+
+// SetFilenameStyle sets the naming.separator and naming.case that movie and
+// episode file names are expected to follow
+func (v *Verifier) SetFilenameStyle(style jellyfin.FilenameStyle) {
+	files := newFilePatterns(style)
+	v.movieRules.files = files
+	v.tvRules.files = files
+}
+
 // VerifyPath verifies a directory structure for Jellyfin compatibility
 // mediaType can be specified to verify only specific media types, or empty for all
 func (v *Verifier) VerifyPath(rootPath string, mediaType types.MediaType) (*Result, error) {
+	result, err := newResult(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	absPath := result.Path
+
+	log.Info().Str("path", absPath).Msg("Starting verification")
+
+	// If mediaType is specified, verify based on type
+	// Otherwise, scan all subdirectories
+	if mediaType != "" {
+		violations := v.verifyByType(absPath, mediaType)
+		result.Violations = append(result.Violations, violations...)
+		result.CheckedDirs = 1
+		result.ItemCounts[mediaType] = 1
+	} else {
+		// Read top-level directories and infer media type
+		violations, checked := v.verifyAllTypes(absPath, result.ItemCounts)
+		result.Violations = append(result.Violations, violations...)
+		result.CheckedDirs = checked
+	}
+
+	if v.checkContainers {
+		result.Violations = append(result.Violations, verifyContainers(absPath)...)
+	}
+
+	result.tally()
+	return result, nil
+}
+
+// VerifyLibrary verifies a whole library root, such as one holding Movies/,
+// TV/, Music/ and Books/. Each top-level directory whose media type can be
+// inferred is verified as an item, so a mixed root works too; any other
+// top-level directory is treated as a section whose children are inferred
+// and verified individually. Violations from every section are aggregated.
+func (v *Verifier) VerifyLibrary(rootPath string) (*Result, error) {
+	result, err := newResult(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("path", result.Path).Msg("Starting library verification")
+
+	violations, checked := v.verifyLibrary(result.Path, result.ItemCounts)
+	result.Violations = append(result.Violations, violations...)
+	result.CheckedDirs = checked
+
+	if v.checkContainers {
+		result.Violations = append(result.Violations, verifyContainers(result.Path)...)
+	}
+
+	result.tally()
+	return result, nil
+}
+
+// newResult resolves rootPath, checks that it is a directory and returns an
+// empty result for it
+func newResult(rootPath string) (*Result, error) {
 	absPath, err := filepath.Abs(rootPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve path: %w", err)
@@ -58,46 +149,32 @@ func (v *Verifier) VerifyPath(rootPath string, mediaType types.MediaType) (*Resu
 		return nil, fmt.Errorf("path is not a directory: %s", absPath)
 	}
 
-	result := &Result{
+	return &Result{
 		Path:        absPath,
 		Violations:  []Violation{},
 		MediaCounts: make(map[types.MediaType]int),
-	}
-
-	log.Info().Str("path", absPath).Msg("Starting verification")
-
-	// If mediaType is specified, verify based on type
-	// Otherwise, scan all subdirectories
-	if mediaType != "" {
-		violations := v.verifyByType(absPath, mediaType)
-		result.Violations = append(result.Violations, violations...)
-		result.CheckedDirs = 1
-	} else {
-		// Read top-level directories and infer media type
-		violations, checked := v.verifyAllTypes(absPath)
-		result.Violations = append(result.Violations, violations...)
-		result.CheckedDirs = checked
-	}
+		ItemCounts:  make(map[types.MediaType]int),
+	}, nil
+}
 
-	// Count violations by severity
-	for _, violation := range result.Violations {
+// tally counts the result's violations by severity and media type
+func (r *Result) tally() {
+	for _, violation := range r.Violations {
 		if violation.Severity == SeverityError {
-			result.ErrorCount++
+			r.ErrorCount++
 		} else {
-			result.WarningCount++
+			r.WarningCount++
 		}
-		result.MediaCounts[violation.MediaType]++
+		r.MediaCounts[violation.MediaType]++
 	}
 
-	result.TotalDirs = result.CheckedDirs
+	r.TotalDirs = r.CheckedDirs
 
 	log.Info().
-		Int("checked", result.CheckedDirs).
-		Int("errors", result.ErrorCount).
-		Int("warnings", result.WarningCount).
+		Int("checked", r.CheckedDirs).
+		Int("errors", r.ErrorCount).
+		Int("warnings", r.WarningCount).
 		Msg("Verification complete")
-
-	return result, nil
 }
 
 // verifyByType verifies a directory as a specific media type
@@ -121,8 +198,56 @@ func (v *Verifier) verifyByType(path string, mediaType types.MediaType) []Violat
 	}
 }
 
-// verifyAllTypes scans a root directory and verifies subdirectories
-func (v *Verifier) verifyAllTypes(rootPath string) ([]Violation, int) {
+// verifyLibrary verifies each top-level directory of a library root as an
+// item when its type can be inferred, or as a section of items otherwise
+func (v *Verifier) verifyLibrary(rootPath string, items map[types.MediaType]int) ([]Violation, int) {
+	violations := []Violation{}
+	checked := 0
+
+	entries, err := os.ReadDir(rootPath)
+	if err != nil {
+		violations = append(violations, Violation{
+			Severity:   SeverityError,
+			Path:       rootPath,
+			Message:    fmt.Sprintf("Cannot read directory: %v", err),
+			Suggestion: "Check directory permissions",
+		})
+		return violations, 0
+	}
+
+	if v.movieRules.IsFlatLibrary(rootPath) {
+		log.Debug().Str("path", rootPath).Msg("Verifying flat movie layout")
+		violations = append(violations, v.movieRules.VerifyFlatMovies(rootPath)...)
+		checked++
+		items[types.MediaTypeMovie]++
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		dirPath := filepath.Join(rootPath, entry.Name())
+		if mediaType := v.inferMediaType(dirPath, entry.Name()); mediaType != "" {
+			log.Debug().Str("path", dirPath).Str("type", string(mediaType)).Msg("Verifying directory")
+			violations = append(violations, v.verifyByType(dirPath, mediaType)...)
+			checked++
+			items[mediaType]++
+			continue
+		}
+
+		log.Debug().Str("path", dirPath).Msg("Verifying library section")
+		sectionViolations, sectionChecked := v.verifyAllTypes(dirPath, items)
+		violations = append(violations, sectionViolations...)
+		checked += sectionChecked
+	}
+
+	return violations, checked
+}
+
+// verifyAllTypes scans a root directory and verifies subdirectories, counting
+// verified items by media type in items
+func (v *Verifier) verifyAllTypes(rootPath string, items map[types.MediaType]int) ([]Violation, int) {
 	violations := []Violation{}
 	checked := 0
 
@@ -137,6 +262,14 @@ func (v *Verifier) verifyAllTypes(rootPath string) ([]Violation, int) {
 		return violations, 0
 	}
 
+	// Movie files stored directly in the root use the flat layout
+	if v.movieRules.IsFlatLibrary(rootPath) {
+		log.Debug().Str("path", rootPath).Msg("Verifying flat movie layout")
+		violations = append(violations, v.movieRules.VerifyFlatMovies(rootPath)...)
+		checked++
+		items[types.MediaTypeMovie]++
+	}
+
 	// Iterate through top-level directories
 	for _, entry := range entries {
 		if !entry.IsDir() {
@@ -148,12 +281,22 @@ func (v *Verifier) verifyAllTypes(rootPath string) ([]Violation, int) {
 
 		// Infer media type based on directory structure
 		mediaType := v.inferMediaType(dirPath, dirName)
+		if mediaType == "" {
+			mediaType = v.inferParentType(dirPath)
+		}
 
 		if mediaType != "" {
 			log.Debug().Str("path", dirPath).Str("type", string(mediaType)).Msg("Verifying directory")
 			dirViolations := v.verifyByType(dirPath, mediaType)
 			violations = append(violations, dirViolations...)
 			checked++
+			items[mediaType]++
+		} else if jellyfin.IsFirstLetterBucket(dirName) {
+			// First-letter shard ("Movies/M/"): verify the items inside it
+			log.Debug().Str("path", dirPath).Msg("Verifying first-letter bucket")
+			bucketViolations, bucketChecked := v.verifyAllTypes(dirPath, items)
+			violations = append(violations, bucketViolations...)
+			checked += bucketChecked
 		} else {
 			// Unknown structure - warning
 			violations = append(violations, Violation{
@@ -241,6 +384,30 @@ func (v *Verifier) inferMediaType(dirPath, dirName string) types.MediaType {
 	return ""
 }
 
+// inferParentType recognizes artist and author directories, which hold
+// album or book subdirectories rather than media files, from the type of
+// their subdirectories
+func (v *Verifier) inferParentType(dirPath string) types.MediaType {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		switch v.inferMediaType(filepath.Join(dirPath, entry.Name()), entry.Name()) {
+		case types.MediaTypeMusic:
+			return types.MediaTypeMusic
+		case types.MediaTypeBook:
+			return types.MediaTypeBook
+		}
+	}
+
+	return ""
+}
+
 // IsValid returns true if the result has no errors
 func (r *Result) IsValid() bool {
 	return r.ErrorCount == 0
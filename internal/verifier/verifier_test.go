@@ -3,8 +3,12 @@ package verifier
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/opd-ai/go-jf-org/internal/jellyfin"
 	"github.com/opd-ai/go-jf-org/pkg/types"
 )
 
@@ -47,6 +51,22 @@ func TestMovieRules_VerifyMovie(t *testing.T) {
 			expectedErrors: 0,
 			expectedWarns:  0,
 		},
+		{
+			name: "directory with provider ID token",
+			setupFunc: func(dir string) error {
+				movieDir := filepath.Join(dir, "The Matrix (1999) [tmdbid-603]")
+				if err := os.Mkdir(movieDir, 0755); err != nil {
+					return err
+				}
+				videoFile := filepath.Join(movieDir, "The Matrix (1999) [tmdbid-603].mkv")
+				if err := os.WriteFile(videoFile, []byte("fake video"), 0644); err != nil {
+					return err
+				}
+				return os.WriteFile(filepath.Join(movieDir, "movie.nfo"), []byte("<movie></movie>"), 0644)
+			},
+			expectedErrors: 0,
+			expectedWarns:  0,
+		},
 		{
 			name: "invalid directory name",
 			setupFunc: func(dir string) error {
@@ -56,6 +76,19 @@ func TestMovieRules_VerifyMovie(t *testing.T) {
 			expectedErrors: 1,
 			expectedWarns:  0,
 		},
+		{
+			name: "article-sorted directory",
+			setupFunc: func(dir string) error {
+				movieDir := filepath.Join(dir, "Matrix, The (1999)")
+				if err := os.Mkdir(movieDir, 0755); err != nil {
+					return err
+				}
+				videoFile := filepath.Join(movieDir, "The Matrix (1999).mkv")
+				return os.WriteFile(videoFile, []byte("fake video"), 0644)
+			},
+			expectedErrors: 0,
+			expectedWarns:  1, // Missing NFO
+		},
 		{
 			name: "no video files",
 			setupFunc: func(dir string) error {
@@ -65,6 +98,35 @@ func TestMovieRules_VerifyMovie(t *testing.T) {
 			expectedErrors: 1,
 			expectedWarns:  0,
 		},
+		{
+			name: "orphaned movie.nfo without video",
+			setupFunc: func(dir string) error {
+				movieDir := filepath.Join(dir, "Gone Movie (2019)")
+				if err := os.Mkdir(movieDir, 0755); err != nil {
+					return err
+				}
+				return os.WriteFile(filepath.Join(movieDir, "movie.nfo"), []byte("<movie></movie>"), 0644)
+			},
+			expectedErrors: 1, // No video files
+			expectedWarns:  1, // Orphaned movie.nfo
+		},
+		{
+			name: "orphaned NFO and artwork without video",
+			setupFunc: func(dir string) error {
+				movieDir := filepath.Join(dir, "Gone Movie (2019)")
+				if err := os.Mkdir(movieDir, 0755); err != nil {
+					return err
+				}
+				for _, name := range []string{"movie.nfo", "poster.jpg", "fanart.png", "notes.txt"} {
+					if err := os.WriteFile(filepath.Join(movieDir, name), []byte("x"), 0644); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expectedErrors: 1, // No video files
+			expectedWarns:  3, // movie.nfo, poster.jpg, fanart.png
+		},
 		{
 			name: "wrong video filename",
 			setupFunc: func(dir string) error {
@@ -89,12 +151,33 @@ func TestMovieRules_VerifyMovie(t *testing.T) {
 				if err := os.WriteFile(videoFile, []byte("fake video"), 0644); err != nil {
 					return err
 				}
-				extrasDir := filepath.Join(movieDir, "Extras")
-				return os.Mkdir(extrasDir, 0755)
+				otherDir := filepath.Join(movieDir, "Random Stuff")
+				return os.Mkdir(otherDir, 0755)
 			},
 			expectedErrors: 0,
 			expectedWarns:  2, // Subdirectory + missing NFO
 		},
+		{
+			name: "recognized extras folders",
+			setupFunc: func(dir string) error {
+				movieDir := filepath.Join(dir, "Movie With Extras (2021)")
+				if err := os.Mkdir(movieDir, 0755); err != nil {
+					return err
+				}
+				videoFile := filepath.Join(movieDir, "Movie With Extras (2021).mkv")
+				if err := os.WriteFile(videoFile, []byte("fake video"), 0644); err != nil {
+					return err
+				}
+				for _, name := range []string{"extras", "Trailers", "extrafanart", "behind the scenes"} {
+					if err := os.Mkdir(filepath.Join(movieDir, name), 0755); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expectedErrors: 0,
+			expectedWarns:  1, // Missing NFO only
+		},
 	}
 
 	for _, tt := range tests {
@@ -119,8 +202,8 @@ func TestMovieRules_VerifyMovie(t *testing.T) {
 
 			moviePath := filepath.Join(tmpDir, entries[0].Name())
 
-			// Run verification
-			rules := &MovieRules{}
+			// Run verification with the default extras folders
+			rules := NewVerifier().movieRules
 			violations := rules.VerifyMovie(moviePath)
 
 			// Count errors and warnings
@@ -180,6 +263,35 @@ func TestTVRules_VerifyTVShow(t *testing.T) {
 			expectedErrors: 0,
 			expectedWarns:  2, // Missing tvshow.nfo, season.nfo
 		},
+		{
+			name: "valid multi-episode file",
+			setupFunc: func(dir string) error {
+				seasonDir := filepath.Join(dir, "Show", "Season 01")
+				if err := os.MkdirAll(seasonDir, 0755); err != nil {
+					return err
+				}
+				return os.WriteFile(filepath.Join(seasonDir, "Show - S01E01-E03.mkv"), []byte("fake video"), 0644)
+			},
+			expectedErrors: 0,
+			expectedWarns:  2, // Missing tvshow.nfo, season.nfo
+		},
+		{
+			name: "valid daily show structure",
+			setupFunc: func(dir string) error {
+				showDir := filepath.Join(dir, "The Daily Show")
+				if err := os.Mkdir(showDir, 0755); err != nil {
+					return err
+				}
+				seasonDir := filepath.Join(showDir, "Season 2023")
+				if err := os.Mkdir(seasonDir, 0755); err != nil {
+					return err
+				}
+				episodeFile := filepath.Join(seasonDir, "The Daily Show - 2023-05-15.mkv")
+				return os.WriteFile(episodeFile, []byte("fake video"), 0644)
+			},
+			expectedErrors: 0,
+			expectedWarns:  2, // Missing tvshow.nfo, season.nfo
+		},
 		{
 			name: "no season directories",
 			setupFunc: func(dir string) error {
@@ -269,6 +381,104 @@ func TestTVRules_VerifyTVShow(t *testing.T) {
 	}
 }
 
+func TestTVRules_OrphanedSidecars(t *testing.T) {
+	showDir := filepath.Join(t.TempDir(), "Show")
+	season := filepath.Join(showDir, "Season 01")
+	empty := filepath.Join(showDir, "Season 02")
+	for _, dir := range []string{season, empty} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := []string{
+		filepath.Join(showDir, "tvshow.nfo"),
+		filepath.Join(season, "season.nfo"),
+		filepath.Join(season, "Show - S01E01 - Pilot.mkv"),
+		filepath.Join(season, "Show - S01E01 - Pilot.nfo"),
+		filepath.Join(season, "Show - S01E01 - Pilot-thumb.jpg"),
+		filepath.Join(season, "Show - S01E02 - Deleted.nfo"),
+		filepath.Join(season, "Show - S01E02 - Deleted-thumb.jpg"),
+		filepath.Join(empty, "season.nfo"),
+		filepath.Join(empty, "poster.jpg"),
+	}
+	for _, f := range files {
+		if err := os.WriteFile(f, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var orphans []string
+	for _, v := range (&TVRules{}).VerifyTVShow(showDir) {
+		if strings.HasPrefix(v.Message, "Orphaned metadata file") {
+			if v.Severity != SeverityWarning {
+				t.Errorf("orphan %s has severity %s, want warning", v.Path, v.Severity)
+			}
+			rel, _ := filepath.Rel(showDir, v.Path)
+			orphans = append(orphans, rel)
+		}
+	}
+	sort.Strings(orphans)
+
+	want := []string{
+		filepath.Join("Season 01", "Show - S01E02 - Deleted-thumb.jpg"),
+		filepath.Join("Season 01", "Show - S01E02 - Deleted.nfo"),
+		filepath.Join("Season 02", "poster.jpg"),
+		filepath.Join("Season 02", "season.nfo"),
+	}
+	if !reflect.DeepEqual(orphans, want) {
+		t.Errorf("orphans = %v, want %v", orphans, want)
+	}
+}
+
+func TestTVRules_CheckCompleteness(t *testing.T) {
+	showDir := filepath.Join(t.TempDir(), "Show (2020)")
+	files := []string{
+		filepath.Join("Season 00", "Show - S00E05 - Special.mkv"),
+		filepath.Join("Season 01", "Show - S01E01 - Pilot.mkv"),
+		filepath.Join("Season 01", "Show - S01E03 - Third.mkv"),
+		filepath.Join("Season 01", "Show - S01E06-E07 - Finale.mkv"),
+		filepath.Join("Season 03", "Show - S03E01.mkv"),
+		filepath.Join("Season 03", "Show - S03E02.mkv"),
+	}
+	for _, f := range files {
+		path := filepath.Join(showDir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	missing := func(rules *TVRules) map[string]Violation {
+		found := make(map[string]Violation)
+		for _, v := range rules.VerifyTVShow(showDir) {
+			if strings.HasPrefix(v.Message, "Missing season directories") || strings.Contains(v.Message, "missing episodes") {
+				rel, _ := filepath.Rel(showDir, v.Path)
+				found[rel] = v
+			}
+		}
+		return found
+	}
+
+	if got := missing(&TVRules{}); len(got) != 0 {
+		t.Errorf("completeness checks should be opt-in, got %v", got)
+	}
+
+	got := missing(&TVRules{checkCompleteness: true})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 completeness warnings, got %v", got)
+	}
+	season := got["Season 01"]
+	if season.Severity != SeverityWarning || season.Message != "Season 01 is missing episodes: E02, E04-E05" {
+		t.Errorf("unexpected season warning %+v", season)
+	}
+	show := got["."]
+	if show.Severity != SeverityWarning || show.Message != "Missing season directories: Season 02" {
+		t.Errorf("unexpected show warning %+v", show)
+	}
+}
+
 // TestMusicRules_VerifyMusic tests music directory verification
 func TestMusicRules_VerifyMusic(t *testing.T) {
 	tests := []struct {
@@ -529,3 +739,385 @@ func TestVerifier_InferMediaType(t *testing.T) {
 		})
 	}
 }
+
+// TestMovieRules_VerifyFlatMovies tests verification of the flat movie layout
+func TestMovieRules_VerifyFlatMovies(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"The Matrix (1999).mkv":        "fake video",
+		"The Matrix (1999).nfo":        "<movie></movie>",
+		"Inception (2010).mp4":         "fake video",
+		"random_download.mkv":          "fake video",
+		"The Matrix (1999)-poster.jpg": "fake image",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+	}
+
+	rules := &MovieRules{}
+	if !rules.IsFlatLibrary(tmpDir) {
+		t.Fatal("Expected directory to be detected as a flat movie library")
+	}
+
+	violations := rules.VerifyFlatMovies(tmpDir)
+
+	errorCount := 0
+	warnCount := 0
+	for _, v := range violations {
+		if v.Severity == SeverityError {
+			errorCount++
+		} else {
+			warnCount++
+		}
+	}
+
+	// random_download.mkv doesn't match the naming convention
+	if errorCount != 1 {
+		t.Errorf("Expected 1 error, got %d", errorCount)
+	}
+	// Inception (2010).mp4 has no NFO
+	if warnCount != 1 {
+		t.Errorf("Expected 1 warning, got %d", warnCount)
+	}
+}
+
+// TestVerifier_VerifyPath_FlatLayout tests that a flat movie library verifies cleanly
+func TestVerifier_VerifyPath_FlatLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, name := range []string{"The Matrix (1999).mkv", "The Matrix (1999).nfo"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("fake"), 0644); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+	}
+
+	for _, mediaType := range []types.MediaType{"", types.MediaTypeMovie} {
+		result, err := NewVerifier().VerifyPath(tmpDir, mediaType)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.HasIssues() {
+			t.Errorf("Expected no issues for type %q, got %d violations", mediaType, len(result.Violations))
+			for _, v := range result.Violations {
+				t.Logf("  %s: %s - %s", v.Severity, v.Path, v.Message)
+			}
+		}
+	}
+}
+
+// TestVerifier_VerifyLibrary verifies a synthetic library root with one item
+// of each media type under its own section, plus a movie at the root
+func TestVerifier_VerifyLibrary(t *testing.T) {
+	root := t.TempDir()
+
+	files := []string{
+		"Movies/The Matrix (1999)/The Matrix (1999).mkv",
+		"Movies/The Matrix (1999)/movie.nfo",
+		"TV/Breaking Bad/tvshow.nfo",
+		"TV/Breaking Bad/Season 01/Breaking Bad - S01E01.mkv",
+		"Music/Pink Floyd/The Wall (1979)/01 - In the Flesh.flac",
+		"Books/King, Stephen/The Shining (1977)/The Shining.epub",
+		"Inception (2010)/Inception (2010).mkv",
+	}
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("fake content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "Misc", "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewVerifier().VerifyLibrary(root)
+	if err != nil {
+		t.Fatalf("VerifyLibrary() error = %v", err)
+	}
+
+	wantItems := map[types.MediaType]int{
+		types.MediaTypeMovie: 2,
+		types.MediaTypeTV:    1,
+		types.MediaTypeMusic: 1,
+		types.MediaTypeBook:  1,
+	}
+	for mediaType, want := range wantItems {
+		if got := result.ItemCounts[mediaType]; got != want {
+			t.Errorf("ItemCounts[%s] = %d, want %d", mediaType, got, want)
+		}
+	}
+	if result.CheckedDirs != 5 {
+		t.Errorf("CheckedDirs = %d, want 5", result.CheckedDirs)
+	}
+
+	if result.ErrorCount != 0 {
+		for _, v := range result.Violations {
+			t.Logf("  %s: %s - %s", v.Severity, v.Path, v.Message)
+		}
+		t.Errorf("ErrorCount = %d, want 0 for a well-formed library", result.ErrorCount)
+	}
+
+	// The unrecognizable directory inside the Misc section is reported
+	var unknown bool
+	for _, v := range result.Violations {
+		if v.Path == filepath.Join(root, "Misc", "notes") && strings.Contains(v.Message, "Cannot determine media type") {
+			unknown = true
+		}
+		if v.Path == filepath.Join(root, "Movies") || v.Path == filepath.Join(root, "Music") {
+			t.Errorf("section directory reported as an item: %s", v.Message)
+		}
+	}
+	if !unknown {
+		t.Error("expected a warning for the unrecognized Misc/notes directory")
+	}
+}
+
+func TestVerifier_VerifyLibrary_FirstLetterShards(t *testing.T) {
+	root := t.TempDir()
+
+	files := []string{
+		"Movies/M/The Matrix (1999)/The Matrix (1999).mkv",
+		"Movies/M/The Matrix (1999)/movie.nfo",
+		"Movies/A/Avatar (2009)/Avatar (2009).mkv",
+		"Movies/#/2001 A Space Odyssey (1968)/2001 A Space Odyssey (1968).mkv",
+		"TV/B/Breaking Bad/tvshow.nfo",
+		"TV/B/Breaking Bad/Season 01/Breaking Bad - S01E01.mkv",
+		"Music/B/The Beatles/Abbey Road (1969)/01 - Come Together.flac",
+		"Books/K/King, Stephen/The Shining (1977)/The Shining.epub",
+	}
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("fake content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := NewVerifier().VerifyLibrary(root)
+	if err != nil {
+		t.Fatalf("VerifyLibrary() error = %v", err)
+	}
+
+	wantItems := map[types.MediaType]int{
+		types.MediaTypeMovie: 3,
+		types.MediaTypeTV:    1,
+		types.MediaTypeMusic: 1,
+		types.MediaTypeBook:  1,
+	}
+	for mediaType, want := range wantItems {
+		if got := result.ItemCounts[mediaType]; got != want {
+			t.Errorf("ItemCounts[%s] = %d, want %d", mediaType, got, want)
+		}
+	}
+	if result.ErrorCount != 0 {
+		t.Errorf("ErrorCount = %d, want 0 for a sharded library", result.ErrorCount)
+	}
+	for _, v := range result.Violations {
+		if strings.Contains(v.Message, "Cannot determine media type") {
+			t.Errorf("bucket directory not recognized: %s", v.Path)
+		}
+	}
+
+	// A section verified on its own accepts its buckets too
+	result, err = NewVerifier().VerifyPath(filepath.Join(root, "Movies"), "")
+	if err != nil {
+		t.Fatalf("VerifyPath() error = %v", err)
+	}
+	if result.ItemCounts[types.MediaTypeMovie] != 3 || result.ErrorCount != 0 {
+		t.Errorf("VerifyPath(Movies) = %d movies, %d errors; want 3 movies, 0 errors", result.ItemCounts[types.MediaTypeMovie], result.ErrorCount)
+	}
+}
+
+// TestVerifier_VerifyPath_ArtistDirectories checks that artist directories,
+// which hold albums rather than tracks, are recognized as music
+func TestVerifier_VerifyPath_ArtistDirectories(t *testing.T) {
+	root := t.TempDir()
+	track := filepath.Join(root, "Pink Floyd", "The Wall (1979)", "01 - In the Flesh.flac")
+	if err := os.MkdirAll(filepath.Dir(track), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(track, []byte("fake audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewVerifier().VerifyPath(root, "")
+	if err != nil {
+		t.Fatalf("VerifyPath() error = %v", err)
+	}
+	if result.ItemCounts[types.MediaTypeMusic] != 1 {
+		t.Errorf("ItemCounts = %v, want one music item", result.ItemCounts)
+	}
+	if result.HasIssues() {
+		t.Errorf("expected no issues, got %+v", result.Violations)
+	}
+}
+
+func TestVerifier_SetExtrasDirs(t *testing.T) {
+	movieDir := filepath.Join(t.TempDir(), "Movie (2021)")
+	for _, dir := range []string{"extras", "Bonus"} {
+		if err := os.MkdirAll(filepath.Join(movieDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"Movie (2021).mkv", "movie.nfo"} {
+		if err := os.WriteFile(filepath.Join(movieDir, name), []byte("fake"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v := NewVerifier()
+	v.SetExtrasDirs([]string{"bonus"})
+	violations := v.movieRules.VerifyMovie(movieDir)
+
+	if len(violations) != 1 || violations[0].Path != filepath.Join(movieDir, "extras") {
+		t.Errorf("expected only extras/ to be flagged once it is no longer configured, got %+v", violations)
+	}
+}
+
+// Synthetic container headers
+var (
+	matroskaHeader = append([]byte{0x1A, 0x45, 0xDF, 0xA3, 0x9F, 0x42, 0x82, 0x88}, []byte("matroska")...)
+	webmHeader     = append([]byte{0x1A, 0x45, 0xDF, 0xA3, 0x9F, 0x42, 0x82, 0x84}, []byte("webm")...)
+	mp4Header      = []byte{0x00, 0x00, 0x00, 0x20, 'f', 't', 'y', 'p', 'i', 's', 'o', 'm'}
+	aviHeader      = []byte{'R', 'I', 'F', 'F', 0x00, 0x10, 0x00, 0x00, 'A', 'V', 'I', ' '}
+)
+
+func mpegTSHeader() []byte {
+	b := make([]byte, 2*mpegTSPacketSize)
+	b[0], b[mpegTSPacketSize] = 0x47, 0x47
+	return b
+}
+
+func TestDetectContainer(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   Container
+	}{
+		{"matroska", matroskaHeader, ContainerMatroska},
+		{"webm", webmHeader, ContainerWebM},
+		{"mp4", mp4Header, ContainerMP4},
+		{"avi", aviHeader, ContainerAVI},
+		{"mpeg-ts", mpegTSHeader(), ContainerMPEGTS},
+		{"unknown", []byte("not a video"), ContainerUnknown},
+		{"empty", nil, ContainerUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectContainer(tt.header); got != tt.want {
+				t.Errorf("DetectContainer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_CheckContainers(t *testing.T) {
+	tmpDir := t.TempDir()
+	movieDir := filepath.Join(tmpDir, "Movie (2020)")
+	if err := os.MkdirAll(movieDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		// An MKV pretending to be an MP4
+		"Movie (2020).mp4": matroskaHeader,
+		// Correct and undetectable files are left alone
+		"Movie (2020) - trailer.mkv": matroskaHeader,
+		"Movie (2020) - clip.m4v":    mp4Header,
+		"movie.nfo":                  []byte("<movie/>"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(movieDir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	containerWarnings := func(result *Result) []Violation {
+		var found []Violation
+		for _, v := range result.Violations {
+			if strings.Contains(v.Message, "container") {
+				found = append(found, v)
+			}
+		}
+		return found
+	}
+
+	v := NewVerifier()
+	result, err := v.VerifyPath(movieDir, types.MediaTypeMovie)
+	if err != nil {
+		t.Fatalf("VerifyPath() error = %v", err)
+	}
+	if got := containerWarnings(result); len(got) != 0 {
+		t.Errorf("container checks should be opt-in, got %v", got)
+	}
+
+	v.SetCheckContainers(true)
+	result, err = v.VerifyPath(movieDir, types.MediaTypeMovie)
+	if err != nil {
+		t.Fatalf("VerifyPath() error = %v", err)
+	}
+	got := containerWarnings(result)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 container warning, got %v", got)
+	}
+	w := got[0]
+	if w.Severity != SeverityWarning || filepath.Base(w.Path) != "Movie (2020).mp4" {
+		t.Errorf("unexpected warning %+v", w)
+	}
+	if !strings.Contains(w.Message, "matroska") || !strings.Contains(w.Suggestion, "Movie (2020).mkv") || !strings.Contains(w.Suggestion, "remux") {
+		t.Errorf("warning should name the real container and suggest a rename or remux, got %+v", w)
+	}
+}
+
+func TestVerifier_SetFilenameStyle(t *testing.T) {
+	tests := []struct {
+		style   jellyfin.FilenameStyle
+		movie   string
+		episode string
+	}{
+		{jellyfin.FilenameStyle{Separator: jellyfin.SeparatorDot}, "The.Matrix.(1999).mkv", "Mr.Robot.S01E01.Pilot.mkv"},
+		{jellyfin.FilenameStyle{Separator: jellyfin.SeparatorUnderscore, Case: jellyfin.CaseLower}, "the_matrix_(1999)_1080p.mkv", "mr._robot_s01e01_pilot.mkv"},
+		{jellyfin.FilenameStyle{Case: jellyfin.CaseTitle}, "The Matrix (1999).mkv", "Mr. Robot - S01E01 - Pilot.mkv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style.Separator)+"/"+string(tt.style.Case), func(t *testing.T) {
+			root := t.TempDir()
+			movieDir := filepath.Join(root, "The Matrix (1999)")
+			seasonDir := filepath.Join(root, "Mr. Robot", "Season 01")
+			for _, dir := range []string{movieDir, seasonDir} {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, path := range []string{
+				filepath.Join(movieDir, tt.movie),
+				filepath.Join(movieDir, "movie.nfo"),
+				filepath.Join(seasonDir, tt.episode),
+				filepath.Join(seasonDir, "season.nfo"),
+			} {
+				if err := os.WriteFile(path, []byte("fake"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			v := NewVerifier()
+			v.SetFilenameStyle(tt.style)
+			violations := append(v.movieRules.VerifyMovie(movieDir), v.tvRules.verifySeason(seasonDir, "Mr. Robot")...)
+			if len(violations) != 0 {
+				t.Errorf("styled names flagged: %+v", violations)
+			}
+
+			// The default style does not accept other separators
+			if tt.style.Separator != "" && len(NewVerifier().tvRules.verifySeason(seasonDir, "Mr. Robot")) == 0 {
+				t.Errorf("default style accepted %s", tt.episode)
+			}
+		})
+	}
+}
@@ -38,6 +38,19 @@ type Metadata struct {
 	Source string
 	// Codec contains codec information (x264, h265, etc.)
 	Codec string
+	// IsProper is set for PROPER re-releases that fix an earlier release
+	IsProper bool
+	// IsRepack is set for REPACK re-releases of the same group's earlier release
+	IsRepack bool
+	// ReleaseGroup is the scene group from a trailing "-GROUP" token (e.g. "SPARKS")
+	ReleaseGroup string
+	// OriginalFilename is the file's name before it was organized
+	OriginalFilename string
+	// Origin is where the fields not yet merged from elsewhere came from;
+	// the zero value is SourceFilename
+	Origin Source
+	// fieldSources records the source of each field set by Merge
+	fieldSources map[string]Source
 	// Additional metadata specific to media type
 	MovieMetadata *MovieMetadata
 	TVMetadata    *TVMetadata
@@ -51,31 +64,66 @@ type MovieMetadata struct {
 	Plot          string
 	Director      []string
 	Cast          []string
-	Genres        []string
-	Rating        float64
-	TMDBID        int
-	IMDBID        string
-	Runtime       int // Runtime in minutes
-	Tagline       string
-	PosterURL     string // URL to poster image
-	BackdropURL   string // URL to backdrop image
+	// Actors are the billed cast with roles and image URLs, when credits were fetched
+	Actors      []Person
+	Genres      []string
+	Rating      float64
+	TMDBID      int
+	IMDBID      string
+	Runtime     int // Runtime in minutes
+	Tagline     string
+	PosterURL   string // URL to poster image
+	BackdropURL string // URL to backdrop image
+	// Certification is the age rating (e.g. "PG-13") for the configured region
+	Certification string
+	// Tags are free-form labels, such as the alternate title a file was
+	// matched by
+	Tags []string
+	// ExtraType is the Jellyfin extra suffix ("trailer", "behindthescenes",
+	// ...) when the file is an extra of the movie rather than the movie
+	ExtraType string
 }
 
 // TVMetadata contains TV show-specific metadata
 type TVMetadata struct {
-	ShowTitle    string
-	Season       int
-	Episode      int
+	ShowTitle string
+	// OriginalTitle is the show's title in its original language, when it
+	// differs from ShowTitle
+	OriginalTitle string
+	Season        int
+	Episode       int
+	// EpisodeEnd is the last episode of a multi-episode file (S01E01E02E03),
+	// with Episode as the first; 0 for single-episode files
+	EpisodeEnd int
+	// MiniSeries is set for episodes numbered without a season ("E01",
+	// "Part 2"), which are placed in season 1
+	MiniSeries   bool
 	EpisodeTitle string
 	Plot         string
 	AirDate      string
 	TMDBID       int
 	TVDBID       int
+	IMDBID       string
 	Rating       float64
 	Genres       []string
 	Tagline      string
 	PosterURL    string // URL to poster image
 	BackdropURL  string // URL to backdrop image
+	// Actors are the show's billed cast, when credits were fetched
+	Actors []Person
+	// Certification is the show's age rating (e.g. "TV-MA") for the configured region
+	Certification string
+	// Tags are free-form labels, such as the alternate title a file was
+	// matched by
+	Tags []string
+}
+
+// Person is a cast member of a movie or TV show
+type Person struct {
+	Name string
+	Role string
+	// Thumb is the URL of the person's profile image, if known
+	Thumb string
 }
 
 // MusicMetadata contains music-specific metadata
@@ -85,6 +133,7 @@ type MusicMetadata struct {
 	AlbumArtist    string
 	TrackNumber    int
 	DiscNumber     int
+	Duration       int // Duration in seconds
 	Genre          string
 	MusicBrainzID  string
 	MusicBrainzRID string
@@ -112,6 +161,13 @@ type Operation struct {
 	Status OperationStatus
 	// Error contains any error that occurred
 	Error error
+	// Hash is the hex digest of the destination file, recorded when hashing is enabled
+	Hash string `json:",omitempty"`
+	// HashAlgorithm names the algorithm Hash was computed with; empty means sha256
+	HashAlgorithm string `json:",omitempty"`
+	// Backup is a copy of Source kept before a move (safety.backup_before_move);
+	// rollback restores from it when Destination is gone
+	Backup string `json:",omitempty"`
 }
 
 // OperationType represents the type of operation
@@ -126,6 +182,11 @@ const (
 	OperationCreateDir OperationType = "create_dir"
 	// OperationCreateFile represents a file creation operation (e.g., NFO)
 	OperationCreateFile OperationType = "create_file"
+	// OperationHardlink replaces Destination with a hardlink to the identical Source
+	OperationHardlink OperationType = "hardlink"
+	// OperationReplaceFile overwrites the existing file at Destination (e.g.,
+	// a clobbered NFO); Source is a backup copy of what was there, if one was kept
+	OperationReplaceFile OperationType = "replace_file"
 )
 
 // OperationStatus represents the status of an operation
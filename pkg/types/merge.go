@@ -0,0 +1,183 @@
+package types
+
+// Source ranks where metadata came from. When two sources both set a field,
+// Merge keeps the value from the higher-ranked one.
+type Source int
+
+const (
+	// SourceFilename is metadata parsed from file and folder names
+	SourceFilename Source = iota
+	// SourceEnrichment is metadata looked up from TMDB, MusicBrainz or OpenLibrary
+	SourceEnrichment
+	// SourceNFO is metadata read from an existing, possibly hand-edited, NFO
+	SourceNFO
+)
+
+// String returns the source's name
+func (s Source) String() string {
+	switch s {
+	case SourceFilename:
+		return "filename"
+	case SourceEnrichment:
+		return "enrichment"
+	case SourceNFO:
+		return "nfo"
+	default:
+		return "unknown"
+	}
+}
+
+// FieldSource returns the source the named field's value came from, e.g.
+// "Title" or "Movie.Plot". Fields never merged report Origin.
+func (m *Metadata) FieldSource(field string) Source {
+	if s, ok := m.fieldSources[field]; ok {
+		return s
+	}
+	return m.Origin
+}
+
+// Merge copies fields from other, which came from precedence, into m, field
+// by field: a value set only in other fills the gap, a value set in both
+// replaces m's when precedence ranks at least as high as the source m's
+// value came from, and fields other leaves empty never clear m's. Type
+// specific metadata (MovieMetadata, ...) is merged the same way and created
+// on m when only other has it.
+func (m *Metadata) Merge(other *Metadata, precedence Source) {
+	if other == nil {
+		return
+	}
+
+	mergeValue(m, "Title", &m.Title, other.Title, precedence)
+	mergeValue(m, "Year", &m.Year, other.Year, precedence)
+	mergeValue(m, "Quality", &m.Quality, other.Quality, precedence)
+	mergeValue(m, "Source", &m.Source, other.Source, precedence)
+	mergeValue(m, "Codec", &m.Codec, other.Codec, precedence)
+	mergeValue(m, "IsProper", &m.IsProper, other.IsProper, precedence)
+	mergeValue(m, "IsRepack", &m.IsRepack, other.IsRepack, precedence)
+	mergeValue(m, "ReleaseGroup", &m.ReleaseGroup, other.ReleaseGroup, precedence)
+	mergeValue(m, "OriginalFilename", &m.OriginalFilename, other.OriginalFilename, precedence)
+
+	if other.MovieMetadata != nil {
+		if m.MovieMetadata == nil {
+			m.MovieMetadata = &MovieMetadata{}
+		}
+		m.mergeMovie(other.MovieMetadata, precedence)
+	}
+	if other.TVMetadata != nil {
+		if m.TVMetadata == nil {
+			m.TVMetadata = &TVMetadata{}
+		}
+		m.mergeTV(other.TVMetadata, precedence)
+	}
+	if other.MusicMetadata != nil {
+		if m.MusicMetadata == nil {
+			m.MusicMetadata = &MusicMetadata{}
+		}
+		m.mergeMusic(other.MusicMetadata, precedence)
+	}
+	if other.BookMetadata != nil {
+		if m.BookMetadata == nil {
+			m.BookMetadata = &BookMetadata{}
+		}
+		m.mergeBook(other.BookMetadata, precedence)
+	}
+}
+
+func (m *Metadata) mergeMovie(o *MovieMetadata, p Source) {
+	d := m.MovieMetadata
+	mergeValue(m, "Movie.OriginalTitle", &d.OriginalTitle, o.OriginalTitle, p)
+	mergeValue(m, "Movie.Plot", &d.Plot, o.Plot, p)
+	mergeSlice(m, "Movie.Director", &d.Director, o.Director, p)
+	mergeSlice(m, "Movie.Cast", &d.Cast, o.Cast, p)
+	mergeSlice(m, "Movie.Actors", &d.Actors, o.Actors, p)
+	mergeSlice(m, "Movie.Genres", &d.Genres, o.Genres, p)
+	mergeValue(m, "Movie.Rating", &d.Rating, o.Rating, p)
+	mergeValue(m, "Movie.TMDBID", &d.TMDBID, o.TMDBID, p)
+	mergeValue(m, "Movie.IMDBID", &d.IMDBID, o.IMDBID, p)
+	mergeValue(m, "Movie.Runtime", &d.Runtime, o.Runtime, p)
+	mergeValue(m, "Movie.Tagline", &d.Tagline, o.Tagline, p)
+	mergeValue(m, "Movie.PosterURL", &d.PosterURL, o.PosterURL, p)
+	mergeValue(m, "Movie.BackdropURL", &d.BackdropURL, o.BackdropURL, p)
+	mergeValue(m, "Movie.Certification", &d.Certification, o.Certification, p)
+	mergeSlice(m, "Movie.Tags", &d.Tags, o.Tags, p)
+	mergeValue(m, "Movie.ExtraType", &d.ExtraType, o.ExtraType, p)
+}
+
+func (m *Metadata) mergeTV(o *TVMetadata, p Source) {
+	d := m.TVMetadata
+	mergeValue(m, "TV.ShowTitle", &d.ShowTitle, o.ShowTitle, p)
+	mergeValue(m, "TV.OriginalTitle", &d.OriginalTitle, o.OriginalTitle, p)
+	mergeValue(m, "TV.Season", &d.Season, o.Season, p)
+	mergeValue(m, "TV.Episode", &d.Episode, o.Episode, p)
+	mergeValue(m, "TV.EpisodeEnd", &d.EpisodeEnd, o.EpisodeEnd, p)
+	mergeValue(m, "TV.MiniSeries", &d.MiniSeries, o.MiniSeries, p)
+	mergeValue(m, "TV.EpisodeTitle", &d.EpisodeTitle, o.EpisodeTitle, p)
+	mergeValue(m, "TV.Plot", &d.Plot, o.Plot, p)
+	mergeValue(m, "TV.AirDate", &d.AirDate, o.AirDate, p)
+	mergeValue(m, "TV.TMDBID", &d.TMDBID, o.TMDBID, p)
+	mergeValue(m, "TV.TVDBID", &d.TVDBID, o.TVDBID, p)
+	mergeValue(m, "TV.IMDBID", &d.IMDBID, o.IMDBID, p)
+	mergeValue(m, "TV.Rating", &d.Rating, o.Rating, p)
+	mergeSlice(m, "TV.Genres", &d.Genres, o.Genres, p)
+	mergeValue(m, "TV.Tagline", &d.Tagline, o.Tagline, p)
+	mergeValue(m, "TV.PosterURL", &d.PosterURL, o.PosterURL, p)
+	mergeValue(m, "TV.BackdropURL", &d.BackdropURL, o.BackdropURL, p)
+	mergeSlice(m, "TV.Actors", &d.Actors, o.Actors, p)
+	mergeValue(m, "TV.Certification", &d.Certification, o.Certification, p)
+	mergeSlice(m, "TV.Tags", &d.Tags, o.Tags, p)
+}
+
+func (m *Metadata) mergeMusic(o *MusicMetadata, p Source) {
+	d := m.MusicMetadata
+	mergeValue(m, "Music.Artist", &d.Artist, o.Artist, p)
+	mergeValue(m, "Music.Album", &d.Album, o.Album, p)
+	mergeValue(m, "Music.AlbumArtist", &d.AlbumArtist, o.AlbumArtist, p)
+	mergeValue(m, "Music.TrackNumber", &d.TrackNumber, o.TrackNumber, p)
+	mergeValue(m, "Music.DiscNumber", &d.DiscNumber, o.DiscNumber, p)
+	mergeValue(m, "Music.Duration", &d.Duration, o.Duration, p)
+	mergeValue(m, "Music.Genre", &d.Genre, o.Genre, p)
+	mergeValue(m, "Music.MusicBrainzID", &d.MusicBrainzID, o.MusicBrainzID, p)
+	mergeValue(m, "Music.MusicBrainzRID", &d.MusicBrainzRID, o.MusicBrainzRID, p)
+}
+
+func (m *Metadata) mergeBook(o *BookMetadata, p Source) {
+	d := m.BookMetadata
+	mergeValue(m, "Book.Author", &d.Author, o.Author, p)
+	mergeValue(m, "Book.Publisher", &d.Publisher, o.Publisher, p)
+	mergeValue(m, "Book.ISBN", &d.ISBN, o.ISBN, p)
+	mergeValue(m, "Book.Series", &d.Series, o.Series, p)
+	mergeValue(m, "Book.SeriesIndex", &d.SeriesIndex, o.SeriesIndex, p)
+	mergeValue(m, "Book.Description", &d.Description, o.Description, p)
+}
+
+// mergeValue applies Merge's rule to one comparable field
+func mergeValue[T comparable](m *Metadata, field string, dst *T, src T, p Source) {
+	var zero T
+	if src == zero {
+		return
+	}
+	if *dst != zero && p < m.FieldSource(field) {
+		return
+	}
+	*dst = src
+	m.setFieldSource(field, p)
+}
+
+// mergeSlice applies Merge's rule to one list field; an empty list is unset
+func mergeSlice[T any](m *Metadata, field string, dst *[]T, src []T, p Source) {
+	if len(src) == 0 {
+		return
+	}
+	if len(*dst) > 0 && p < m.FieldSource(field) {
+		return
+	}
+	*dst = append([]T(nil), src...)
+	m.setFieldSource(field, p)
+}
+
+func (m *Metadata) setFieldSource(field string, s Source) {
+	if m.fieldSources == nil {
+		m.fieldSources = make(map[string]Source)
+	}
+	m.fieldSources[field] = s
+}
@@ -0,0 +1,127 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetadata_Merge_Precedence(t *testing.T) {
+	sources := []Source{SourceFilename, SourceEnrichment, SourceNFO}
+
+	// Every pair of sources: the receiver's plot came from have, the
+	// incoming one from incoming
+	for _, have := range sources {
+		for _, incoming := range sources {
+			t.Run(have.String()+"<-"+incoming.String(), func(t *testing.T) {
+				m := &Metadata{Origin: have, MovieMetadata: &MovieMetadata{Plot: "existing"}}
+				m.Merge(&Metadata{MovieMetadata: &MovieMetadata{Plot: "incoming"}}, incoming)
+
+				want, wantSource := "existing", have
+				if incoming >= have {
+					want, wantSource = "incoming", incoming
+				}
+				if m.MovieMetadata.Plot != want {
+					t.Errorf("Plot = %q, want %q", m.MovieMetadata.Plot, want)
+				}
+				if got := m.FieldSource("Movie.Plot"); got != wantSource {
+					t.Errorf("FieldSource(Movie.Plot) = %s, want %s", got, wantSource)
+				}
+			})
+		}
+	}
+}
+
+func TestMetadata_Merge_FieldByField(t *testing.T) {
+	// Filename parsing, then enrichment, then a hand-edited NFO, then a
+	// second enrichment pass that must not undo the NFO
+	m := &Metadata{
+		Title:         "The Matrix",
+		Year:          1999,
+		Quality:       "1080P",
+		MovieMetadata: &MovieMetadata{},
+	}
+	m.Merge(&Metadata{
+		Title: "The Matrix (TMDB)",
+		MovieMetadata: &MovieMetadata{
+			Plot:   "A hacker learns the truth.",
+			Genres: []string{"Action", "Science Fiction"},
+			TMDBID: 603,
+		},
+	}, SourceEnrichment)
+	m.Merge(&Metadata{
+		MovieMetadata: &MovieMetadata{
+			Plot:   "My own summary.",
+			Genres: []string{"Favourites"},
+		},
+	}, SourceNFO)
+	m.Merge(&Metadata{
+		Title: "The Matrix Again",
+		MovieMetadata: &MovieMetadata{
+			Plot:    "Another plot.",
+			Genres:  []string{"Action"},
+			Runtime: 136,
+		},
+	}, SourceEnrichment)
+
+	if m.Title != "The Matrix Again" {
+		t.Errorf("Title = %q, want the latest enrichment over the filename", m.Title)
+	}
+	if m.Year != 1999 || m.Quality != "1080P" {
+		t.Errorf("Year/Quality = %d/%s, want unset enrichment fields to keep the filename's", m.Year, m.Quality)
+	}
+	if m.MovieMetadata.Plot != "My own summary." {
+		t.Errorf("Plot = %q, want the NFO's", m.MovieMetadata.Plot)
+	}
+	if !reflect.DeepEqual(m.MovieMetadata.Genres, []string{"Favourites"}) {
+		t.Errorf("Genres = %v, want the NFO's", m.MovieMetadata.Genres)
+	}
+	if m.MovieMetadata.TMDBID != 603 || m.MovieMetadata.Runtime != 136 {
+		t.Errorf("TMDBID/Runtime = %d/%d, want gaps filled by enrichment", m.MovieMetadata.TMDBID, m.MovieMetadata.Runtime)
+	}
+	if got := m.FieldSource("Year"); got != SourceFilename {
+		t.Errorf("FieldSource(Year) = %s, want filename", got)
+	}
+}
+
+func TestMetadata_Merge_EmptyNeverClears(t *testing.T) {
+	m := &Metadata{
+		Title:         "Abbey Road",
+		Origin:        SourceFilename,
+		MusicMetadata: &MusicMetadata{Artist: "The Beatles", TrackNumber: 3},
+	}
+	m.Merge(&Metadata{MusicMetadata: &MusicMetadata{Album: "Abbey Road"}}, SourceNFO)
+
+	if m.Title != "Abbey Road" || m.MusicMetadata.Artist != "The Beatles" || m.MusicMetadata.TrackNumber != 3 {
+		t.Errorf("got %+v %+v, want fields the NFO left empty kept", m, m.MusicMetadata)
+	}
+	if m.MusicMetadata.Album != "Abbey Road" {
+		t.Errorf("Album = %q, want it filled from the NFO", m.MusicMetadata.Album)
+	}
+}
+
+func TestMetadata_Merge_CreatesTypeMetadata(t *testing.T) {
+	m := &Metadata{Title: "Breaking Bad"}
+	m.Merge(&Metadata{TVMetadata: &TVMetadata{ShowTitle: "Breaking Bad", Season: 1, Episode: 2}}, SourceEnrichment)
+	m.Merge(&Metadata{BookMetadata: &BookMetadata{Author: "Someone"}}, SourceEnrichment)
+	m.Merge(nil, SourceNFO)
+
+	if m.TVMetadata == nil || m.TVMetadata.Season != 1 || m.TVMetadata.Episode != 2 {
+		t.Errorf("TVMetadata = %+v, want it created from the merge", m.TVMetadata)
+	}
+	if m.BookMetadata == nil || m.BookMetadata.Author != "Someone" {
+		t.Errorf("BookMetadata = %+v, want it created from the merge", m.BookMetadata)
+	}
+	if m.MovieMetadata != nil || m.MusicMetadata != nil {
+		t.Error("Merge created type metadata the other side did not have")
+	}
+}
+
+func TestMetadata_Merge_CopiesSlices(t *testing.T) {
+	genres := []string{"Drama"}
+	m := &Metadata{}
+	m.Merge(&Metadata{MovieMetadata: &MovieMetadata{Genres: genres}}, SourceEnrichment)
+	genres[0] = "Changed"
+	if m.MovieMetadata.Genres[0] != "Drama" {
+		t.Errorf("Genres = %v, want a copy unaffected by the source slice", m.MovieMetadata.Genres)
+	}
+}